@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,16 +17,19 @@ import (
 // KonsulClient is a simple HTTP client for Konsul
 type KonsulClient struct {
 	addr       string
+	token      string
 	httpClient *http.Client
 	log        logger.Logger
 	kvCache    *KVCache
 	svcCache   *ServiceCache
 }
 
-// NewKonsulClient creates a new Konsul client
-func NewKonsulClient(addr string, log logger.Logger) *KonsulClient {
+// NewKonsulClient creates a new Konsul client. A non-empty token is sent as
+// bearer token with every request.
+func NewKonsulClient(addr, token string, log logger.Logger) *KonsulClient {
 	return &KonsulClient{
-		addr: addr,
+		addr:  addr,
+		token: token,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
@@ -38,7 +43,71 @@ func NewKonsulClient(addr string, log logger.Logger) *KonsulClient {
 func (c *KonsulClient) KVStore() template.KVStoreReader {
 	// Fetch initial data
 	_ = c.refreshKV()
-	return c.kvCache
+	return &kvStore{KVCache: c.kvCache, client: c}
+}
+
+// kvStore serves template KV reads from the cache and reads secrets from
+// Konsul on every use, so that their plaintext is never cached.
+type kvStore struct {
+	*KVCache
+	client *KonsulClient
+}
+
+// Secret implements template.SecretReader.
+func (s *kvStore) Secret(key string) (string, error) {
+	return s.client.getSecret(key)
+}
+
+// get sends a GET request for path, authenticated with the client token.
+func (c *KonsulClient) get(path string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, c.addr+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return c.httpClient.Do(req)
+}
+
+// getSecret reads the plaintext of a secret key. It needs a token with the
+// decrypt capability on the key when ACLs are enabled.
+func (c *KonsulClient) getSecret(key string) (string, error) {
+	escaped := strings.Split(key, "/")
+	for i, segment := range escaped {
+		escaped[i] = url.PathEscape(segment)
+	}
+	resp, err := c.get("/kv/" + strings.Join(escaped, "/") + "?decrypt=true")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.log.Warn("Failed to close KV response body", logger.Error(err))
+		}
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", fmt.Errorf("key not found: %s", key)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "", fmt.Errorf("not allowed to decrypt secret %s (status %d)", key, resp.StatusCode)
+	default:
+		return "", fmt.Errorf("unexpected status reading secret %s: %d", key, resp.StatusCode)
+	}
+
+	var body struct {
+		Value  string `json:"value"`
+		Secret bool   `json:"secret"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if !body.Secret {
+		return "", fmt.Errorf("key is not a secret: %s", key)
+	}
+	return body.Value, nil
 }
 
 // ServiceStore returns the service store interface
@@ -50,8 +119,7 @@ func (c *KonsulClient) ServiceStore() template.ServiceStoreReader {
 
 // refreshKV fetches KV data from Konsul
 func (c *KonsulClient) refreshKV() error {
-	url := fmt.Sprintf("%s/kv", c.addr)
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.get("/kv")
 	if err != nil {
 		c.log.Warn("Failed to fetch KV data", logger.Error(err))
 		return err
@@ -84,8 +152,7 @@ func (c *KonsulClient) refreshKV() error {
 
 // refreshServices fetches service data from Konsul
 func (c *KonsulClient) refreshServices() error {
-	url := fmt.Sprintf("%s/services", c.addr)
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.get("/services")
 	if err != nil {
 		c.log.Warn("Failed to fetch service data", logger.Error(err))
 		return err
@@ -187,6 +254,11 @@ func (c *ServiceCache) Update(services []template.Service) {
 	defer c.mu.Unlock()
 	c.services = make(map[string]template.Service)
 	for _, svc := range services {
-		c.services[svc.Name] = svc
+		// Key by instance so replicas of the same service are all kept
+		key := svc.ID
+		if key == "" {
+			key = svc.Name
+		}
+		c.services[key] = svc
 	}
 }
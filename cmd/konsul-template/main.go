@@ -22,6 +22,7 @@ func main() {
 		once        = flag.Bool("once", false, "Run once and exit (don't watch for changes)")
 		dryRun      = flag.Bool("dry", false, "Dry run mode (render but don't write files)")
 		konsulAddr  = flag.String("konsul", "http://localhost:8500", "Konsul server address")
+		token       = flag.String("token", os.Getenv("KONSUL_TOKEN"), "Konsul token, needed for the secret function when ACLs are enabled (default $KONSUL_TOKEN)")
 		templateSrc = flag.String("template", "", "Single template source file")
 		dest        = flag.String("dest", "", "Single template destination file")
 		showVersion = flag.Bool("version", false, "Show version and exit")
@@ -46,7 +47,7 @@ func main() {
 	}
 
 	// Create a simple client that connects to Konsul
-	client := NewKonsulClient(*konsulAddr, log)
+	client := NewKonsulClient(*konsulAddr, *token, log)
 
 	// Build configuration
 	config := template.ConfigEngine{
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"io/fs"
 	"log"
@@ -35,10 +36,12 @@ import (
 	"github.com/neogan74/konsul/internal/persistence"
 	konsulraft "github.com/neogan74/konsul/internal/raft"
 	"github.com/neogan74/konsul/internal/ratelimit"
+	"github.com/neogan74/konsul/internal/secrets"
 	"github.com/neogan74/konsul/internal/store"
 	"github.com/neogan74/konsul/internal/telemetry"
 	konsultls "github.com/neogan74/konsul/internal/tls"
 	"github.com/neogan74/konsul/internal/watch"
+	"github.com/neogan74/konsul/internal/webhook"
 )
 
 //go:embed all:ui
@@ -194,6 +197,12 @@ func main() {
 			BackupDir:  cfg.Persistence.BackupDir,
 			SyncWrites: cfg.Persistence.SyncWrites,
 			WALEnabled: cfg.Persistence.WALEnabled,
+
+			EncryptionKey:             cfg.Persistence.EncryptionKey,
+			EncryptionKeyFile:         cfg.Persistence.EncryptionKeyFile,
+			PreviousEncryptionKey:     cfg.Persistence.PreviousEncryptionKey,
+			PreviousEncryptionKeyFile: cfg.Persistence.PreviousEncryptionKeyFile,
+			DataKeyRotation:           cfg.Persistence.DataKeyRotation,
 		}, appLogger)
 		if err != nil {
 			log.Fatalf("Failed to initialize persistence engine: %v", err)
@@ -225,6 +234,32 @@ func main() {
 		kv = store.NewKVStore()
 		svcStore = store.NewServiceStoreWithTTL(cfg.Service.TTL)
 	}
+	svcStore.EnableScriptChecks(cfg.Service.EnableScriptChecks)
+
+	if cfg.KV.SecretsEnabled() {
+		transit, err := loadTransit(cfg.KV)
+		if err != nil {
+			log.Fatalf("Failed to load secrets transit key: %v", err)
+		}
+		kv.SetTransit(transit)
+		appLogger.Info("Secret KV values enabled")
+	}
+
+	// Sessions hold their locks in the KV store
+	sessionStore := store.NewSessionStore(kv)
+
+	// Namespaces are replicated through Raft; standalone nodes rebuild the
+	// registry from the namespaces found in persisted data
+	namespaceStore := store.NewNamespaceStore(kv, svcStore)
+	if !cfg.Raft.Enabled {
+		if found := namespaceStore.Discover(); len(found) > 0 {
+			appLogger.Info("Namespaces restored from persisted data", logger.Int("count", len(found)))
+		}
+	}
+
+	// Prepared queries run against the service catalog and, like namespaces,
+	// are replicated through Raft
+	preparedQueryStore := store.NewPreparedQueryStore(svcStore)
 
 	// Ensure stores are closed on shutdown
 	defer func() {
@@ -236,28 +271,97 @@ func main() {
 		}
 	}()
 
+	// API keys and ACL policies are replicated through Raft like the stores
+	// above, so they are created before the Raft node
+	var apiKeyService *auth.APIKeyService
+	var userService *auth.UserService
+	if cfg.Auth.Enabled {
+		if cfg.Persistence.Enabled {
+			apiKeyService, err = auth.NewAPIKeyServiceWithPersistence(cfg.Auth.APIKeyPrefix, engine)
+			if err != nil {
+				log.Fatalf("Failed to initialize API key service: %v", err)
+			}
+			userService, err = auth.NewUserServiceWithPersistence(cfg.Auth.MaxLoginAttempts, cfg.Auth.LockoutDuration, engine)
+			if err != nil {
+				log.Fatalf("Failed to initialize user service: %v", err)
+			}
+		} else {
+			apiKeyService = auth.NewAPIKeyService(cfg.Auth.APIKeyPrefix)
+			userService = auth.NewUserService(cfg.Auth.MaxLoginAttempts, cfg.Auth.LockoutDuration)
+		}
+	}
+
+	var aclEvaluator *acl.Evaluator
+	if cfg.ACL.Enabled {
+		if cfg.Persistence.Enabled {
+			aclEvaluator = acl.NewEvaluatorWithPersistence(engine, appLogger)
+		} else {
+			aclEvaluator = acl.NewEvaluator(appLogger)
+		}
+	}
+
 	// Initialize Raft clustering if enabled
 	var raftNode *konsulraft.Node
 	if cfg.Raft.Enabled {
 		raftCfg := &konsulraft.Config{
-			NodeID:             cfg.Raft.NodeID,
-			BindAddr:           cfg.Raft.BindAddr,
-			AdvertiseAddr:      cfg.Raft.AdvertiseAddr,
-			DataDir:            cfg.Raft.DataDir,
-			Bootstrap:          cfg.Raft.Bootstrap,
-			HeartbeatTimeout:   cfg.Raft.HeartbeatTimeout,
-			ElectionTimeout:    cfg.Raft.ElectionTimeout,
-			LeaderLeaseTimeout: cfg.Raft.LeaderLeaseTimeout,
-			CommitTimeout:      cfg.Raft.CommitTimeout,
-			SnapshotInterval:   cfg.Raft.SnapshotInterval,
-			SnapshotThreshold:  cfg.Raft.SnapshotThreshold,
-			SnapshotRetention:  cfg.Raft.SnapshotRetention,
-			MaxAppendEntries:   cfg.Raft.MaxAppendEntries,
-			TrailingLogs:       cfg.Raft.TrailingLogs,
-			LogLevel:           cfg.Raft.LogLevel,
-		}
-
-		raftNode, err = konsulraft.NewNode(raftCfg, kv, svcStore)
+			NodeID:              cfg.Raft.NodeID,
+			BindAddr:            cfg.Raft.BindAddr,
+			AdvertiseAddr:       cfg.Raft.AdvertiseAddr,
+			DataDir:             cfg.Raft.DataDir,
+			Bootstrap:           cfg.Raft.Bootstrap,
+			NonVoter:            cfg.Raft.NonVoter,
+			HeartbeatTimeout:    cfg.Raft.HeartbeatTimeout,
+			ElectionTimeout:     cfg.Raft.ElectionTimeout,
+			LeaderLeaseTimeout:  cfg.Raft.LeaderLeaseTimeout,
+			CommitTimeout:       cfg.Raft.CommitTimeout,
+			SnapshotInterval:    cfg.Raft.SnapshotInterval,
+			SnapshotThreshold:   cfg.Raft.SnapshotThreshold,
+			SnapshotRetention:   cfg.Raft.SnapshotRetention,
+			SnapshotCompression: cfg.Raft.SnapshotCompression,
+			MaxAppendEntries:    cfg.Raft.MaxAppendEntries,
+			TrailingLogs:        cfg.Raft.TrailingLogs,
+			LogLevel:            cfg.Raft.LogLevel,
+			Autopilot: konsulraft.AutopilotConfig{
+				Enabled:                 cfg.Raft.Autopilot.Enabled,
+				CleanupDeadServers:      cfg.Raft.Autopilot.CleanupDeadServers,
+				LastContactThreshold:    cfg.Raft.Autopilot.LastContactThreshold,
+				MaxTrailingLogs:         cfg.Raft.Autopilot.MaxTrailingLogs,
+				ServerStabilizationTime: cfg.Raft.Autopilot.ServerStabilizationTime,
+				DeadServerThreshold:     cfg.Raft.Autopilot.DeadServerThreshold,
+			},
+			Forwarding: konsulraft.ForwardingConfig{
+				Enabled:     cfg.Raft.Forwarding.Enabled,
+				MaxAttempts: cfg.Raft.Forwarding.MaxAttempts,
+				RetryWait:   cfg.Raft.Forwarding.RetryWait,
+			},
+			ServiceTTL: konsulraft.ServiceTTLConfig{
+				ExpiryInterval: cfg.Service.CleanupInterval,
+				Grace:          cfg.Raft.ServiceTTLGrace,
+			},
+		}
+
+		fsmCfg := konsulraft.FSMConfig{
+			KVStore:        kv,
+			ServiceStore:   svcStore,
+			SessionStore:   sessionStore,
+			NamespaceStore: namespaceStore,
+			QueryStore:     preparedQueryStore,
+		}
+		if aclEvaluator != nil {
+			fsmCfg.ACLStore = aclEvaluator
+		}
+		if apiKeyService != nil {
+			fsmCfg.APIKeyStore = apiKeyService
+		}
+		if userService != nil {
+			fsmCfg.UserStore = userService
+		}
+
+		// Only the leader sees the heartbeats, so instances stay listed
+		// until it expires them through the log
+		svcStore.EnableReplicatedExpiry(true)
+
+		raftNode, err = konsulraft.NewNodeWithFSM(raftCfg, fsmCfg)
 		if err != nil {
 			log.Fatalf("Failed to initialize Raft node: %v", err)
 		}
@@ -273,7 +377,8 @@ func main() {
 			logger.String("node_id", cfg.Raft.NodeID),
 			logger.String("bind_addr", cfg.Raft.BindAddr),
 			logger.String("advertise_addr", cfg.Raft.AdvertiseAddr),
-			logger.String("bootstrap", fmt.Sprintf("%t", cfg.Raft.Bootstrap)))
+			logger.String("bootstrap", fmt.Sprintf("%t", cfg.Raft.Bootstrap)),
+			logger.String("non_voter", fmt.Sprintf("%t", cfg.Raft.NonVoter)))
 
 		// Wait for leader election (with timeout)
 		go func() {
@@ -287,6 +392,45 @@ func main() {
 		}()
 	}
 
+	// Create the first user from the configured admin credentials. In a
+	// cluster the leader creates it once the Raft log has been replayed.
+	if userService != nil && cfg.Auth.AdminPassword != "" {
+		bootstrapAdmin := func() {
+			if userService.Count() > 0 {
+				return
+			}
+			user, err := userService.NewUser(cfg.Auth.AdminUsername, cfg.Auth.AdminPassword, []string{"admin"}, cfg.Auth.AdminPolicies, nil)
+			if err == nil {
+				if raftNode != nil {
+					err = raftNode.UserCreate(user)
+				} else {
+					err = userService.AddUser(user)
+				}
+			}
+			if err != nil {
+				if !errors.Is(err, auth.ErrUserExists) {
+					appLogger.Error("Failed to create admin user", logger.String("username", cfg.Auth.AdminUsername), logger.Error(err))
+				}
+				return
+			}
+			appLogger.Info("Admin user created", logger.String("username", cfg.Auth.AdminUsername))
+		}
+
+		if raftNode == nil {
+			bootstrapAdmin()
+		} else {
+			go func() {
+				if err := raftNode.WaitForLeader(30 * time.Second); err == nil && raftNode.IsLeader() {
+					if err := raftNode.Barrier(10 * time.Second); err != nil {
+						appLogger.Warn("Raft barrier failed before creating admin user", logger.Error(err))
+						return
+					}
+					bootstrapAdmin()
+				}
+			}()
+		}
+	}
+
 	// Initialize load balancer with default round-robin strategy
 	balancer := loadbalancer.New(svcStore, loadbalancer.StrategyRoundRobin)
 	appLogger.Info("Load balancer initialized", logger.String("strategy", string(loadbalancer.StrategyRoundRobin)))
@@ -297,13 +441,16 @@ func main() {
 	metrics.LoadBalancerCurrentStrategy.WithLabelValues("least-connections").Set(0)
 
 	// Initialize handlers (raftNode can be nil if Raft is disabled)
-	kvHandler := handlers.NewKVHandler(kv, raftNode)
-	serviceHandler := handlers.NewServiceHandler(svcStore, raftNode)
+	kvHandler := handlers.NewKVHandler(kv, raftNode).WithSessions(sessionStore).WithNamespaces(namespaceStore).WithACL(aclEvaluator)
+	sessionHandler := handlers.NewSessionHandler(sessionStore, svcStore, raftNode)
+	serviceHandler := handlers.NewServiceHandler(svcStore, raftNode).WithNamespaces(namespaceStore)
+	namespaceHandler := handlers.NewNamespaceHandler(namespaceStore, raftNode)
+	preparedQueryHandler := handlers.NewPreparedQueryHandler(preparedQueryStore, raftNode)
 	loadBalancerHandler := handlers.NewLoadBalancerHandler(balancer)
 	healthHandler := handlers.NewHealthHandler(kv, svcStore, version)
-	healthCheckHandler := handlers.NewHealthCheckHandler(svcStore, raftNode)
+	healthCheckHandler := handlers.NewHealthCheckHandler(svcStore, raftNode).WithNamespaces(namespaceStore)
 	backupHandler := handlers.NewBackupHandler(engine, appLogger)
-	batchHandler := handlers.NewBatchHandler(kv, svcStore, raftNode)
+	batchHandler := handlers.NewBatchHandler(kv, svcStore, raftNode).WithNamespaces(namespaceStore)
 	agentHandler := handlers.NewAgentHandlers(svcStore, kv, appLogger)
 
 	// Initialize store metrics
@@ -313,6 +460,7 @@ func main() {
 	// Initialize auth services if enabled
 	var jwtService *auth.JWTService
 	var authHandler *handlers.AuthHandler
+	var userHandler *handlers.UserHandler
 	if cfg.Auth.Enabled {
 		jwtService = auth.NewJWTService(
 			cfg.Auth.JWTSecret,
@@ -320,18 +468,17 @@ func main() {
 			cfg.Auth.RefreshExpiry,
 			cfg.Auth.Issuer,
 		)
-		apiKeyService := auth.NewAPIKeyService(cfg.Auth.APIKeyPrefix)
-		authHandler = handlers.NewAuthHandler(jwtService, apiKeyService)
+		authHandler = handlers.NewAuthHandler(jwtService, apiKeyService).WithUsers(userService).WithRaft(raftNode)
+		userHandler = handlers.NewUserHandler(userService, raftNode)
 	}
 
 	// Initialize ACL system if enabled
-	var aclEvaluator *acl.Evaluator
 	var aclHandler *handlers.ACLHandler
 	if cfg.ACL.Enabled {
-		aclEvaluator = acl.NewEvaluator(appLogger)
-		aclHandler = handlers.NewACLHandler(aclEvaluator, cfg.ACL.PolicyDir, appLogger)
+		aclHandler = handlers.NewACLHandler(aclEvaluator, cfg.ACL.PolicyDir, appLogger).WithRaft(raftNode)
 
-		// Load policies from disk
+		// Load policies from disk; they seed the local node and are not
+		// replicated
 		if err := aclHandler.LoadPolicies(); err != nil {
 			appLogger.Error("Failed to load ACL policies", logger.Error(err))
 		} else {
@@ -346,25 +493,51 @@ func main() {
 	// Initialize watch manager if enabled
 	var watchManager *watch.Manager
 	var kvWatchHandler *handlers.KVWatchHandler
+	var serviceWatchHandler *handlers.ServiceWatchHandler
 	if cfg.Watch.Enabled {
 		watchManager = watch.NewManager(
 			aclEvaluator,
 			appLogger,
 			cfg.Watch.BufferSize,
 			cfg.Watch.MaxPerClient,
-		)
+		).WithJournalSize(cfg.Watch.JournalSize)
 
-		// Connect watch manager to KV store
+		// Connect watch manager to KV store and health checks
 		kv.SetWatchManager(watchManager)
+		svcStore.SetWatchManager(watchManager)
 
-		// Create watch handler
+		// Create watch handlers
 		kvWatchHandler = handlers.NewKVWatchHandler(kv, watchManager, aclEvaluator, appLogger)
+		serviceWatchHandler = handlers.NewServiceWatchHandler(svcStore, watchManager, aclEvaluator, appLogger).WithNamespaces(namespaceStore)
 
 		appLogger.Info("Watch system initialized",
 			logger.Int("buffer_size", cfg.Watch.BufferSize),
 			logger.Int("max_per_client", cfg.Watch.MaxPerClient))
 	}
 
+	// Initialize webhooks if enabled; they subscribe to the watch manager
+	var webhookManager *webhook.Manager
+	if cfg.Webhook.Enabled && watchManager != nil {
+		webhookManager, err = webhook.NewManager(watchManager, engine, webhook.Config{
+			MaxAttempts: cfg.Webhook.MaxAttempts,
+			RetryBase:   cfg.Webhook.RetryBase,
+			RetryMax:    cfg.Webhook.RetryMax,
+			Timeout:     cfg.Webhook.Timeout,
+			Workers:     cfg.Webhook.Workers,
+			HistorySize: cfg.Webhook.HistorySize,
+		}, appLogger)
+		if err != nil {
+			appLogger.Error("Failed to initialize webhooks", logger.Error(err))
+			log.Fatalf("Failed to initialize webhooks: %v", err)
+		}
+		defer webhookManager.Close()
+
+		appLogger.Info("Webhooks initialized",
+			logger.Int("webhooks", len(webhookManager.List())),
+			logger.Int("max_attempts", cfg.Webhook.MaxAttempts),
+			logger.Int("workers", cfg.Webhook.Workers))
+	}
+
 	// Auth endpoints (public)
 	if cfg.Auth.Enabled {
 		app.Post("/auth/login", authHandler.Login)
@@ -386,6 +559,23 @@ func main() {
 		apiKeyRoutes.Put("/:id", authHandler.UpdateAPIKey)
 		apiKeyRoutes.Delete("/:id", authHandler.DeleteAPIKey)
 		apiKeyRoutes.Post("/:id/revoke", authHandler.RevokeAPIKey)
+
+		// User management endpoints (admin role only)
+		userRoutes := app.Group("/auth/users")
+		userRoutes.Use(middleware.JWTAuth(jwtService, cfg.Auth.PublicPaths))
+		userRoutes.Use(middleware.RequireRole("admin"))
+		if auditManager.Enabled() {
+			userRoutes.Use(middleware.AuditMiddleware(middleware.AuditConfig{
+				Manager:      auditManager,
+				ResourceType: "auth",
+			}))
+		}
+		userRoutes.Post("/", userHandler.Create)
+		userRoutes.Get("/", userHandler.List)
+		userRoutes.Get("/:username", userHandler.Get)
+		userRoutes.Put("/:username", userHandler.Update)
+		userRoutes.Put("/:username/password", userHandler.SetPassword)
+		userRoutes.Delete("/:username", userHandler.Delete)
 	}
 
 	// Apply auth middleware to protected routes if required
@@ -415,6 +605,46 @@ func main() {
 		aclRoutes.Post("/test", aclHandler.TestPolicy)
 	}
 
+	// Namespace management endpoints (requires admin ACL permission)
+	namespaceRoutes := app.Group("/namespaces")
+	if cfg.ACL.Enabled && cfg.Auth.Enabled {
+		namespaceRoutes.Use(middleware.JWTAuth(jwtService, cfg.Auth.PublicPaths))
+		namespaceRoutes.Use(middleware.DynamicACLMiddleware(aclEvaluator))
+	}
+	if auditManager.Enabled() {
+		namespaceRoutes.Use(middleware.AuditMiddleware(middleware.AuditConfig{
+			Manager:      auditManager,
+			ResourceType: "namespace",
+			ActionMapper: middleware.NamespaceActionMapper,
+		}))
+	}
+	namespaceRoutes.Get("/", namespaceHandler.List)
+	namespaceRoutes.Get("/:name", namespaceHandler.Get)
+	namespaceRoutes.Put("/:name", namespaceHandler.Create)
+	namespaceRoutes.Delete("/:name", namespaceHandler.Delete)
+
+	// Webhook management endpoints (requires admin ACL permission)
+	if webhookManager != nil {
+		webhookHandler := handlers.NewWebhookHandler(webhookManager, aclEvaluator).WithNamespaces(namespaceStore)
+		// Unlike the other admin routes these are never open: config
+		// validation refuses webhooks without ACLs
+		webhookRoutes := app.Group("/webhooks")
+		webhookRoutes.Use(middleware.JWTAuth(jwtService, cfg.Auth.PublicPaths))
+		webhookRoutes.Use(middleware.DynamicACLMiddleware(aclEvaluator))
+		if auditManager.Enabled() {
+			webhookRoutes.Use(middleware.AuditMiddleware(middleware.AuditConfig{
+				Manager:      auditManager,
+				ResourceType: "webhook",
+				ActionMapper: middleware.WebhookActionMapper,
+			}))
+		}
+		webhookRoutes.Get("/", webhookHandler.List)
+		webhookRoutes.Get("/:name", webhookHandler.Get)
+		webhookRoutes.Put("/:name", webhookHandler.Set)
+		webhookRoutes.Delete("/:name", webhookHandler.Delete)
+		webhookRoutes.Get("/:name/deliveries", webhookHandler.Deliveries)
+	}
+
 	// Rate limit management endpoints (requires admin permission)
 	if cfg.RateLimit.Enabled {
 		rateLimitHandler := handlers.NewRateLimitHandler(rateLimitService, appLogger)
@@ -475,39 +705,46 @@ func main() {
 
 	// KV Watch endpoints (WebSocket and SSE) - if watch is enabled
 	if cfg.Watch.Enabled && kvWatchHandler != nil {
-		// WebSocket watch endpoint with authentication
-		app.Get("/kv/watch/:key", func(c *fiber.Ctx) error {
-			// Apply authentication if required
-			if cfg.Auth.RequireAuth && cfg.Auth.Enabled {
-				// Run JWT auth middleware
-				if err := middleware.JWTAuth(jwtService, cfg.Auth.PublicPaths)(c); err != nil {
-					return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-						"error":   "unauthorized",
-						"message": "authentication required for watch",
-					})
+		// watchRoute serves a watch over WebSocket or, otherwise, SSE
+		watchRoute := func(ws func(*websocket.Conn), sse fiber.Handler) fiber.Handler {
+			return func(c *fiber.Ctx) error {
+				// Apply authentication if required
+				if cfg.Auth.RequireAuth && cfg.Auth.Enabled {
+					// Run JWT auth middleware
+					if err := middleware.JWTAuth(jwtService, cfg.Auth.PublicPaths)(c); err != nil {
+						return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+							"error":   "unauthorized",
+							"message": "authentication required for watch",
+						})
+					}
 				}
-			}
 
-			// Check if this is a WebSocket upgrade request
-			if websocket.IsWebSocketUpgrade(c) {
-				// Create WebSocket handler with authentication context
-				return websocket.New(func(conn *websocket.Conn) {
-					// Pass claims through locals
-					if cfg.Auth.Enabled {
-						claims := middleware.GetClaims(c)
-						conn.Locals("claims", claims)
-					}
-					kvWatchHandler.WatchWebSocket(conn)
-				})(c)
+				// Check if this is a WebSocket upgrade request
+				if websocket.IsWebSocketUpgrade(c) {
+					// Create WebSocket handler with authentication context
+					return websocket.New(func(conn *websocket.Conn) {
+						// Pass claims through locals
+						if cfg.Auth.Enabled {
+							claims := middleware.GetClaims(c)
+							conn.Locals("claims", claims)
+						}
+						ws(conn)
+					})(c)
+				}
+
+				// Otherwise, handle as SSE
+				return sse(c)
 			}
+		}
 
-			// Otherwise, handle as SSE
-			return kvWatchHandler.WatchSSE(c)
-		})
+		app.Get("/kv/watch/:key", watchRoute(kvWatchHandler.WatchWebSocket, kvWatchHandler.WatchSSE))
+		app.Get("/services/watch", watchRoute(serviceWatchHandler.WatchWebSocket, serviceWatchHandler.WatchSSE))
+		app.Get("/services/watch/:name", watchRoute(serviceWatchHandler.WatchWebSocket, serviceWatchHandler.WatchSSE))
 
-		appLogger.Info("KV watch endpoints registered",
+		appLogger.Info("Watch endpoints registered",
 			logger.String("websocket_path", "/kv/watch/:key (WebSocket)"),
-			logger.String("sse_path", "/kv/watch/:key (SSE)"))
+			logger.String("sse_path", "/kv/watch/:key (SSE)"),
+			logger.String("service_path", "/services/watch/:name (WebSocket, SSE)"))
 	}
 
 	// Service discovery endpoints with audit logging
@@ -540,6 +777,21 @@ func main() {
 	app.Get("/services/query/tags", serviceHandler.QueryByTags)
 	app.Get("/services/query/metadata", serviceHandler.QueryByMetadata)
 	app.Get("/services/query", serviceHandler.QueryByTagsAndMetadata)
+
+	// Prepared queries; executing one is a read like the service queries above
+	app.Get("/query/", preparedQueryHandler.List)
+	app.Get("/query/:name", preparedQueryHandler.Get)
+	app.Get("/query/:name/execute", preparedQueryHandler.Execute)
+	app.Put("/query/:name", preparedQueryHandler.Set)
+	app.Delete("/query/:name", preparedQueryHandler.Delete)
+
+	// Session endpoints (locks are taken with PUT /kv/<key>?acquire=<session>)
+	sessionRoutes := app.Group("/session")
+	sessionRoutes.Put("/create", sessionHandler.Create)
+	sessionRoutes.Put("/renew/:id", sessionHandler.Renew)
+	sessionRoutes.Put("/destroy/:id", sessionHandler.Destroy)
+	sessionRoutes.Get("/info/:id", sessionHandler.Info)
+	sessionRoutes.Get("/list", sessionHandler.List)
 	// Agent protocol endpoints - for agent communication
 	agentRoutes := app.Group("/v1/agent")
 	agentRoutes.Post("/register", agentHandler.HandleAgentRegister)
@@ -596,6 +848,7 @@ func main() {
 	app.Get("/health/checks", healthCheckHandler.ListChecks)
 	app.Get("/health/service/:name", healthCheckHandler.GetServiceChecks)
 	app.Put("/health/check/:id", healthCheckHandler.UpdateTTLCheck)
+	app.Get("/health/check/:id/history", healthCheckHandler.GetCheckHistory)
 
 	// Backup/restore endpoints with audit logging
 	backupRoutes := app.Group("")
@@ -652,13 +905,16 @@ func main() {
 	// GraphQL setup (if enabled)
 	if cfg.GraphQL.Enabled {
 		gqlDeps := resolver.ResolverDependencies{
-			KVStore:      kv,
-			ServiceStore: svcStore,
-			ACLEvaluator: aclEvaluator,
-			JWTService:   jwtService,
-			Logger:       appLogger,
-			Version:      version,
-			RaftNode:     raftNode,
+			KVStore:        kv,
+			ServiceStore:   svcStore,
+			SessionStore:   sessionStore,
+			NamespaceStore: namespaceStore,
+			WebhookManager: webhookManager,
+			ACLEvaluator:   aclEvaluator,
+			JWTService:     jwtService,
+			Logger:         appLogger,
+			Version:        version,
+			RaftNode:       raftNode,
 		}
 
 		gqlServer := graphql.NewServer(gqlDeps)
@@ -675,11 +931,16 @@ func main() {
 		appLogger.Info("GraphQL API enabled at /graphql")
 	}
 
-	// Start background cleanup process
+	// Start background cleanup process. In a cluster the leader removes the
+	// instances that stop heartbeating it through the log instead.
 	go func() {
 		ticker := time.NewTicker(cfg.Service.CleanupInterval)
 		defer ticker.Stop()
 		for range ticker.C {
+			if raftNode != nil {
+				metrics.RegisteredServicesTotal.Set(float64(len(svcStore.List())))
+				continue
+			}
 			count := svcStore.CleanupExpired()
 			if count > 0 {
 				appLogger.Info("Cleaned up expired services", logger.Int("count", count))
@@ -689,15 +950,110 @@ func main() {
 		}
 	}()
 
+	// Delete keys whose TTL ran out. In a cluster only the leader finds them
+	// and the deletion is replicated, so every node emits the same events.
+	if cfg.KV.ExpiryInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(cfg.KV.ExpiryInterval)
+			defer ticker.Stop()
+			for now := range ticker.C {
+				var expired []string
+				if raftNode != nil {
+					if !raftNode.IsLeader() {
+						continue
+					}
+					expired = kv.ExpiredKeys(now)
+					if len(expired) == 0 {
+						continue
+					}
+					if err := raftNode.KVExpire(expired, now); err != nil {
+						appLogger.Warn("Failed to expire keys", logger.Error(err))
+						continue
+					}
+				} else {
+					expired = kv.DeleteExpired(now)
+				}
+				if len(expired) > 0 {
+					appLogger.Debug("Expired keys deleted", logger.Int("count", len(expired)))
+					metrics.ExpiredKeysTotal.Add(float64(len(expired)))
+					metrics.KVStoreSize.Set(float64(len(kv.List())))
+				}
+			}
+		}()
+	}
+
+	// Deregister instances whose checks stayed critical for longer than their
+	// deregister_critical_service_after. In a cluster only the leader does
+	// this, through Raft.
+	go func() {
+		ticker := time.NewTicker(cfg.Service.CleanupInterval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			if raftNode != nil && !raftNode.IsLeader() {
+				continue
+			}
+			for _, id := range svcStore.CriticalInstances(now) {
+				if raftNode != nil {
+					if err := raftNode.ServiceDeregister(id); err != nil {
+						appLogger.Warn("Failed to deregister critical service",
+							logger.String("service_id", id),
+							logger.Error(err))
+						continue
+					}
+				} else {
+					svcStore.Deregister(id)
+				}
+				svcStore.RemoveHealthChecks(id)
+				appLogger.Info("Critical service deregistered", logger.String("service_id", id))
+				metrics.CriticalServicesDeregisteredTotal.Inc()
+				metrics.RegisteredServicesTotal.Set(float64(len(svcStore.List())))
+			}
+		}
+	}()
+
+	// Invalidate sessions whose TTL ran out or whose linked checks failed.
+	// In a cluster only the leader does this, through Raft, so that every
+	// node releases the same locks.
+	go func() {
+		ticker := time.NewTicker(cfg.Service.CleanupInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if raftNode != nil && !raftNode.IsLeader() {
+				continue
+			}
+			for _, id := range sessionStore.Invalidated(time.Now(), svcStore.CheckStatus) {
+				var err error
+				if raftNode != nil {
+					err = raftNode.SessionDestroy(id)
+				} else {
+					err = sessionStore.Destroy(id)
+				}
+				if err != nil && !store.IsNotFound(err) {
+					appLogger.Warn("Failed to invalidate session",
+						logger.String("session", id),
+						logger.Error(err))
+					continue
+				}
+				appLogger.Info("Session invalidated", logger.String("session", id))
+				metrics.InvalidatedSessionsTotal.Inc()
+			}
+		}
+	}()
+
 	// Start DNS server if enabled
 	var dnsServer *dns.Server
 	if cfg.DNS.Enabled {
 		dnsConfig := dns.Config{
-			Host:   cfg.DNS.Host,
-			Port:   cfg.DNS.Port,
-			Domain: cfg.DNS.Domain,
+			Host:        cfg.DNS.Host,
+			Port:        cfg.DNS.Port,
+			Domain:      cfg.DNS.Domain,
+			OnlyPassing: cfg.DNS.OnlyPassing,
+
+			Recursors:       cfg.DNS.Recursors,
+			RecursorTimeout: cfg.DNS.RecursorTimeout,
+			CacheSize:       cfg.DNS.CacheSize,
 		}
-		dnsServer = dns.NewServer(dnsConfig, svcStore, appLogger)
+		dnsServer = dns.NewServer(dnsConfig, svcStore, appLogger).WithPreparedQueries(preparedQueryStore)
 		if err := dnsServer.Start(); err != nil {
 			appLogger.Error("Failed to start DNS server", logger.Error(err))
 		} else {
@@ -777,3 +1133,20 @@ func main() {
 	}
 	appLogger.Info("Server exited gracefully")
 }
+
+// loadTransit creates the transit that seals secret KV values from the
+// configured transit keys.
+func loadTransit(cfg config.KVConfig) (*secrets.Transit, error) {
+	key, err := persistence.LoadEncryptionKey(cfg.TransitKey, cfg.TransitKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := persistence.LoadEncryptionKey(cfg.PreviousTransitKey, cfg.PreviousTransitKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("previous key: %w", err)
+	}
+	if previous == nil {
+		return secrets.NewTransit(key)
+	}
+	return secrets.NewTransit(key, previous)
+}
@@ -31,6 +31,8 @@ type GlobalConfig struct {
 	TLSCACert     string
 	TLSClientCert string
 	TLSClientKey  string
+	Namespace     string
+	Token         string
 }
 
 // ParseGlobalFlags parses common flags and returns GlobalConfig and remaining args
@@ -44,6 +46,8 @@ func (cli *CLI) ParseGlobalFlags(args []string, commandName string) (*GlobalConf
 	flagSet.StringVar(&config.TLSCACert, "ca-cert", "", "Path to CA certificate file")
 	flagSet.StringVar(&config.TLSClientCert, "client-cert", "", "Path to client certificate file")
 	flagSet.StringVar(&config.TLSClientKey, "client-key", "", "Path to client key file")
+	flagSet.StringVar(&config.Namespace, "namespace", os.Getenv("KONSUL_NAMESPACE"), "Namespace to operate in (default: default)")
+	flagSet.StringVar(&config.Token, "token", os.Getenv("KONSUL_TOKEN"), "JWT token to authenticate with")
 
 	if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
 		return nil, nil, flag.ErrHelp
@@ -67,7 +71,14 @@ func (cli *CLI) CreateClient(config *GlobalConfig) *KonsulClient {
 		ClientKeyFile:  config.TLSClientKey,
 	}
 
-	return NewKonsulClientWithTLS(config.ServerURL, tlsConfig)
+	client := NewKonsulClientWithTLS(config.ServerURL, tlsConfig)
+	if config.Namespace != "" {
+		client.SetNamespace(config.Namespace)
+	}
+	if config.Token != "" {
+		client.SetToken(config.Token)
+	}
+	return client
 }
 
 // Printf writes formatted output to the output writer
@@ -45,9 +45,12 @@ type ErrorResponse struct {
 }
 
 type Service struct {
-	Name    string `json:"name"`
-	Address string `json:"address"`
-	Port    int    `json:"port"`
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Node      string `json:"node,omitempty"`
+	Address   string `json:"address"`
+	Port      int    `json:"port"`
 }
 
 type CheckDefinition struct {
@@ -57,17 +60,28 @@ type CheckDefinition struct {
 	HTTP          string            `json:"http,omitempty"`
 	TCP           string            `json:"tcp,omitempty"`
 	GRPC          string            `json:"grpc,omitempty"`
+	UDP           string            `json:"udp,omitempty"`
+	Args          []string          `json:"args,omitempty"`
 	TTL           string            `json:"ttl,omitempty"`
 	Interval      string            `json:"interval,omitempty"`
 	Timeout       string            `json:"timeout,omitempty"`
 	Method        string            `json:"method,omitempty"`
 	Headers       map[string]string `json:"headers,omitempty"`
 	TLSSkipVerify bool              `json:"tls_skip_verify,omitempty"`
+	BodyMatch     string            `json:"body_match,omitempty"`
+	BodyJSONPath  string            `json:"body_json_path,omitempty"`
+	BodyJSONValue string            `json:"body_json_value,omitempty"`
 	GRPCUseTLS    bool              `json:"grpc_use_tls,omitempty"`
+
+	SuccessBeforePassing           int    `json:"success_before_passing,omitempty"`
+	FailuresBeforeCritical         int    `json:"failures_before_critical,omitempty"`
+	DeregisterCriticalServiceAfter string `json:"deregister_critical_service_after,omitempty"`
 }
 
 type ServiceRegisterRequest struct {
+	ID      string             `json:"id,omitempty"`
 	Name    string             `json:"name"`
+	Node    string             `json:"node,omitempty"`
 	Address string             `json:"address"`
 	Port    int                `json:"port"`
 	Checks  []*CheckDefinition `json:"checks,omitempty"`
@@ -234,6 +248,52 @@ func NewKonsulClientWithTLS(baseURL string, tlsConfig *TLSConfig) *KonsulClient
 	}
 }
 
+// namespaceHeader selects the namespace of a request.
+const namespaceHeader = "X-Konsul-Namespace"
+
+// namespaceTransport sets the namespace header on every request.
+type namespaceTransport struct {
+	namespace string
+	base      http.RoundTripper
+}
+
+func (t *namespaceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(namespaceHeader, t.namespace)
+	return t.base.RoundTrip(req)
+}
+
+// SetNamespace makes all further requests of the client target namespace ns.
+func (c *KonsulClient) SetNamespace(ns string) {
+	base := c.HTTPClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c.HTTPClient.Transport = &namespaceTransport{namespace: ns, base: base}
+}
+
+// tokenTransport sets the bearer token on every request.
+type tokenTransport struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
+}
+
+// SetToken makes all further requests of the client authenticate with the
+// JWT token.
+func (c *KonsulClient) SetToken(token string) {
+	base := c.HTTPClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c.HTTPClient.Transport = &tokenTransport{token: token, base: base}
+}
+
 func (c *KonsulClient) GetKV(key string) (string, error) {
 	reqURL := fmt.Sprintf("%s/kv/%s", c.BaseURL, url.PathEscape(key))
 
@@ -405,7 +465,7 @@ func (c *KonsulClient) RegisterService(name, address, port string) error {
 	return nil
 }
 
-func (c *KonsulClient) RegisterServiceWithChecks(name, address, port string, checks []*CheckDefinition) error {
+func (c *KonsulClient) RegisterServiceWithChecks(id, name, node, address, port string, checks []*CheckDefinition) error {
 	url := fmt.Sprintf("%s/register", c.BaseURL)
 
 	// Convert port to int
@@ -415,7 +475,9 @@ func (c *KonsulClient) RegisterServiceWithChecks(name, address, port string, che
 	}
 
 	reqBody := ServiceRegisterRequest{
+		ID:      id,
 		Name:    name,
+		Node:    node,
 		Address: address,
 		Port:    portInt,
 		Checks:  checks,
@@ -1551,6 +1613,91 @@ func (c *KonsulClient) ClusterSnapshot() (*ClusterActionResponse, error) {
 	return &result, nil
 }
 
+// ClusterServerHealth is the health of a server as seen by the leader.
+type ClusterServerHealth struct {
+	ID          string    `json:"id"`
+	Address     string    `json:"address"`
+	Suffrage    string    `json:"suffrage"`
+	Leader      bool      `json:"leader"`
+	Healthy     bool      `json:"healthy"`
+	ReadReplica bool      `json:"read_replica"`
+	LastContact string    `json:"last_contact"`
+	LastIndex   uint64    `json:"last_index"`
+	Lag         uint64    `json:"lag"`
+	StableSince time.Time `json:"stable_since"`
+}
+
+// ClusterAutopilotHealthResponse is the response from GET /cluster/autopilot/health.
+type ClusterAutopilotHealthResponse struct {
+	Healthy          bool                  `json:"healthy"`
+	FailureTolerance int                   `json:"failure_tolerance"`
+	Servers          []ClusterServerHealth `json:"servers"`
+}
+
+// ClusterAutopilotConfigResponse is the response from GET /cluster/autopilot/config.
+type ClusterAutopilotConfigResponse struct {
+	Enabled                 bool   `json:"enabled"`
+	CleanupDeadServers      bool   `json:"cleanup_dead_servers"`
+	LastContactThreshold    string `json:"last_contact_threshold"`
+	MaxTrailingLogs         uint64 `json:"max_trailing_logs"`
+	ServerStabilizationTime string `json:"server_stabilization_time"`
+	DeadServerThreshold     string `json:"dead_server_threshold"`
+}
+
+// ClusterAutopilotHealth fetches the health of the cluster servers from the leader.
+func (c *KonsulClient) ClusterAutopilotHealth() (*ClusterAutopilotHealthResponse, error) {
+	reqURL := fmt.Sprintf("%s/cluster/autopilot/health", c.BaseURL)
+	req, err := http.NewRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer closeResponseBody(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %d: %s", resp.StatusCode, string(body))
+	}
+	var result ClusterAutopilotHealthResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &result, nil
+}
+
+// ClusterAutopilotConfig fetches the autopilot configuration of the node.
+func (c *KonsulClient) ClusterAutopilotConfig() (*ClusterAutopilotConfigResponse, error) {
+	reqURL := fmt.Sprintf("%s/cluster/autopilot/config", c.BaseURL)
+	req, err := http.NewRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer closeResponseBody(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %d: %s", resp.StatusCode, string(body))
+	}
+	var result ClusterAutopilotConfigResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &result, nil
+}
+
 // ACL Response types
 
 // ACLPoliciesResponse represents the response from listing policies
@@ -1558,3 +1705,458 @@ type ACLPoliciesResponse struct {
 	Policies []string `json:"policies"`
 	Count    int      `json:"count"`
 }
+
+// SessionRequest is the request body for PUT /session/create.
+type SessionRequest struct {
+	Name     string   `json:"name,omitempty"`
+	Node     string   `json:"node,omitempty"`
+	TTL      string   `json:"ttl,omitempty"`
+	Behavior string   `json:"behavior,omitempty"`
+	Checks   []string `json:"checks,omitempty"`
+}
+
+// Session is a session as returned by the /session endpoints.
+type Session struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name,omitempty"`
+	Node        string    `json:"node,omitempty"`
+	TTL         string    `json:"ttl,omitempty"`
+	Behavior    string    `json:"behavior,omitempty"`
+	Checks      []string  `json:"checks,omitempty"`
+	CreateIndex uint64    `json:"create_index"`
+	ModifyIndex uint64    `json:"modify_index"`
+	ExpiresAt   time.Time `json:"expires_at,omitempty"`
+}
+
+// LockResponse is the response from PUT /kv/<key>?acquire= and ?release=.
+type LockResponse struct {
+	Key         string `json:"key"`
+	Result      bool   `json:"result"`
+	Session     string `json:"session"`
+	LockIndex   uint64 `json:"lock_index"`
+	ModifyIndex uint64 `json:"modify_index"`
+}
+
+// CreateSession creates a new session.
+func (c *KonsulClient) CreateSession(session SessionRequest) (*Session, error) {
+	jsonData, err := json.Marshal(session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	reqURL := fmt.Sprintf("%s/session/create", c.BaseURL)
+	var result Session
+	if err := c.doSessionRequest(reqURL, jsonData, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RenewSession extends the TTL of a session.
+func (c *KonsulClient) RenewSession(id string) (*Session, error) {
+	reqURL := fmt.Sprintf("%s/session/renew/%s", c.BaseURL, url.PathEscape(id))
+	var result Session
+	if err := c.doSessionRequest(reqURL, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DestroySession destroys a session, releasing the locks it holds.
+func (c *KonsulClient) DestroySession(id string) error {
+	reqURL := fmt.Sprintf("%s/session/destroy/%s", c.BaseURL, url.PathEscape(id))
+	return c.doSessionRequest(reqURL, nil, nil)
+}
+
+// AcquireLock tries to take the lock on key for a session and store value.
+func (c *KonsulClient) AcquireLock(key, session, value string) (*LockResponse, error) {
+	jsonData, err := json.Marshal(KVRequest{Value: value})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	reqURL := fmt.Sprintf("%s/kv/%s?acquire=%s", c.BaseURL, url.PathEscape(key), url.QueryEscape(session))
+	var result LockResponse
+	if err := c.doSessionRequest(reqURL, jsonData, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ReleaseLock drops a session's lock on key.
+func (c *KonsulClient) ReleaseLock(key, session string) (*LockResponse, error) {
+	reqURL := fmt.Sprintf("%s/kv/%s?release=%s", c.BaseURL, url.PathEscape(key), url.QueryEscape(session))
+	var result LockResponse
+	if err := c.doSessionRequest(reqURL, []byte("{}"), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// doSessionRequest sends a PUT request and decodes the JSON response into out, if set.
+func (c *KonsulClient) doSessionRequest(reqURL string, jsonData []byte, out interface{}) error {
+	req, err := http.NewRequest("PUT", reqURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if jsonData != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer closeResponseBody(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp ErrorResponse
+		if err := json.Unmarshal(body, &errResp); err == nil && errResp.Message != "" {
+			return fmt.Errorf("server error: %s - %s", errResp.Error, errResp.Message)
+		}
+		return fmt.Errorf("server returned %d: %s", resp.StatusCode, string(body))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// Namespace is a namespace as returned by the /namespaces endpoints.
+type Namespace struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Meta        map[string]string `json:"meta,omitempty"`
+	CreateIndex uint64            `json:"create_index"`
+	ModifyIndex uint64            `json:"modify_index"`
+	CreatedAt   time.Time         `json:"created_at,omitzero"`
+}
+
+// ListNamespaces lists all namespaces.
+func (c *KonsulClient) ListNamespaces() ([]Namespace, error) {
+	var result []Namespace
+	if err := c.doNamespaceRequest("GET", "", nil, http.StatusOK, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateNamespace creates a namespace.
+func (c *KonsulClient) CreateNamespace(name, description string) (*Namespace, error) {
+	jsonData, err := json.Marshal(Namespace{Description: description})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	var result Namespace
+	if err := c.doNamespaceRequest("PUT", name, jsonData, http.StatusCreated, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteNamespace deletes an empty namespace.
+func (c *KonsulClient) DeleteNamespace(name string) error {
+	return c.doNamespaceRequest("DELETE", name, nil, http.StatusOK, nil)
+}
+
+// doNamespaceRequest sends a request to /namespaces/<name> and decodes the JSON
+// response into out, if set.
+func (c *KonsulClient) doNamespaceRequest(method, name string, jsonData []byte, wantStatus int, out interface{}) error {
+	reqURL := fmt.Sprintf("%s/namespaces/%s", c.BaseURL, url.PathEscape(name))
+	req, err := http.NewRequest(method, reqURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if jsonData != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer closeResponseBody(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != wantStatus {
+		var errResp ErrorResponse
+		if err := json.Unmarshal(body, &errResp); err == nil && errResp.Message != "" {
+			return fmt.Errorf("server error: %s - %s", errResp.Error, errResp.Message)
+		}
+		return fmt.Errorf("server returned %d: %s", resp.StatusCode, string(body))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// User is a user as returned by the /auth/users endpoints.
+type User struct {
+	ID        string            `json:"id"`
+	Username  string            `json:"username"`
+	Roles     []string          `json:"roles"`
+	Policies  []string          `json:"policies"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Disabled  bool              `json:"disabled"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// LoginResponse holds the tokens returned by /auth/login.
+type LoginResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// Login exchanges a username and password for JWT tokens.
+func (c *KonsulClient) Login(username, password string) (*LoginResponse, error) {
+	jsonData, err := json.Marshal(map[string]string{"username": username, "password": password})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	var result LoginResponse
+	if err := c.doAuthRequest("POST", "/auth/login", jsonData, http.StatusOK, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListUsers lists all users.
+func (c *KonsulClient) ListUsers() ([]User, error) {
+	var result struct {
+		Users []User `json:"users"`
+	}
+	if err := c.doAuthRequest("GET", "/auth/users", nil, http.StatusOK, &result); err != nil {
+		return nil, err
+	}
+	return result.Users, nil
+}
+
+// GetUser gets a user.
+func (c *KonsulClient) GetUser(username string) (*User, error) {
+	var result User
+	if err := c.doAuthRequest("GET", "/auth/users/"+url.PathEscape(username), nil, http.StatusOK, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CreateUser creates a user.
+func (c *KonsulClient) CreateUser(username, password string, roles, policies []string) (*User, error) {
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"username": username,
+		"password": password,
+		"roles":    roles,
+		"policies": policies,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	var result User
+	if err := c.doAuthRequest("POST", "/auth/users", jsonData, http.StatusCreated, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateUser changes the roles, policies or disabled flag of a user; nil
+// arguments are left unchanged.
+func (c *KonsulClient) UpdateUser(username string, roles, policies []string, disabled *bool) (*User, error) {
+	update := map[string]interface{}{}
+	if roles != nil {
+		update["roles"] = roles
+	}
+	if policies != nil {
+		update["policies"] = policies
+	}
+	if disabled != nil {
+		update["disabled"] = *disabled
+	}
+	jsonData, err := json.Marshal(update)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	var result User
+	if err := c.doAuthRequest("PUT", "/auth/users/"+url.PathEscape(username), jsonData, http.StatusOK, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SetUserPassword sets the password of a user.
+func (c *KonsulClient) SetUserPassword(username, password string) error {
+	jsonData, err := json.Marshal(map[string]string{"password": password})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return c.doAuthRequest("PUT", "/auth/users/"+url.PathEscape(username)+"/password", jsonData, http.StatusOK, nil)
+}
+
+// DeleteUser deletes a user.
+func (c *KonsulClient) DeleteUser(username string) error {
+	return c.doAuthRequest("DELETE", "/auth/users/"+url.PathEscape(username), nil, http.StatusOK, nil)
+}
+
+// doAuthRequest sends a request to an /auth endpoint and decodes the JSON
+// response into out, if set.
+func (c *KonsulClient) doAuthRequest(method, path string, jsonData []byte, wantStatus int, out interface{}) error {
+	req, err := http.NewRequest(method, c.BaseURL+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if jsonData != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer closeResponseBody(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != wantStatus {
+		var errResp ErrorResponse
+		if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error != "" {
+			if errResp.Message != "" {
+				return fmt.Errorf("server error: %s - %s", errResp.Error, errResp.Message)
+			}
+			return fmt.Errorf("server error: %s", errResp.Error)
+		}
+		return fmt.Errorf("server returned %d: %s", resp.StatusCode, string(body))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// Webhook is a webhook as returned by the /webhooks endpoints.
+type Webhook struct {
+	Name        string    `json:"name"`
+	URL         string    `json:"url"`
+	Secret      string    `json:"secret,omitempty"`
+	Signed      bool      `json:"signed,omitempty"`
+	ClearSecret bool      `json:"clear_secret,omitempty"`
+	Kind        string    `json:"kind,omitempty"`
+	Namespace   string    `json:"namespace,omitempty"`
+	Pattern     string    `json:"pattern,omitempty"`
+	Events      []string  `json:"events,omitempty"`
+	Policies    []string  `json:"policies,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitzero"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"`
+}
+
+// WebhookDelivery is a delivery as returned by /webhooks/<name>/deliveries.
+type WebhookDelivery struct {
+	ID          string     `json:"id"`
+	Webhook     string     `json:"webhook"`
+	Event       WatchEvent `json:"event"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	StatusCode  int        `json:"status_code,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	NextAttempt time.Time  `json:"next_attempt,omitzero"`
+	CompletedAt time.Time  `json:"completed_at,omitzero"`
+}
+
+// ListWebhooks lists all webhooks.
+func (c *KonsulClient) ListWebhooks() ([]Webhook, error) {
+	var result []Webhook
+	if err := c.doWebhookRequest("GET", "", nil, http.StatusOK, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetWebhook gets a webhook.
+func (c *KonsulClient) GetWebhook(name string) (*Webhook, error) {
+	var result Webhook
+	if err := c.doWebhookRequest("GET", url.PathEscape(name), nil, http.StatusOK, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SetWebhook creates or replaces the webhook hook.Name.
+func (c *KonsulClient) SetWebhook(hook Webhook) (*Webhook, error) {
+	jsonData, err := json.Marshal(hook)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	var result Webhook
+	if err := c.doWebhookRequest("PUT", url.PathEscape(hook.Name), jsonData, http.StatusOK, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteWebhook deletes a webhook.
+func (c *KonsulClient) DeleteWebhook(name string) error {
+	return c.doWebhookRequest("DELETE", url.PathEscape(name), nil, http.StatusOK, nil)
+}
+
+// WebhookDeliveries lists the pending and recent deliveries of a webhook,
+// newest first.
+func (c *KonsulClient) WebhookDeliveries(name string) ([]WebhookDelivery, error) {
+	var result struct {
+		Deliveries []WebhookDelivery `json:"deliveries"`
+	}
+	if err := c.doWebhookRequest("GET", url.PathEscape(name)+"/deliveries", nil, http.StatusOK, &result); err != nil {
+		return nil, err
+	}
+	return result.Deliveries, nil
+}
+
+// doWebhookRequest sends a request to /webhooks/<path> and decodes the JSON
+// response into out, if set.
+func (c *KonsulClient) doWebhookRequest(method, path string, jsonData []byte, wantStatus int, out interface{}) error {
+	req, err := http.NewRequest(method, c.BaseURL+"/webhooks/"+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if jsonData != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer closeResponseBody(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != wantStatus {
+		var errResp ErrorResponse
+		if err := json.Unmarshal(body, &errResp); err == nil && errResp.Message != "" {
+			return fmt.Errorf("server error: %s - %s", errResp.Error, errResp.Message)
+		}
+		return fmt.Errorf("server returned %d: %s", resp.StatusCode, string(body))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -19,7 +19,7 @@ func NewClusterCommands(cli *CLI) *ClusterCommands {
 func (cc *ClusterCommands) Handle(args []string) {
 	if len(args) == 0 {
 		cc.cli.Errorln("Cluster subcommand required")
-		cc.cli.Errorln("Usage: konsulctl cluster <status|leader|peers|join|leave|snapshot> [options]")
+		cc.cli.Errorln("Usage: konsulctl cluster <status|leader|peers|join|leave|snapshot|autopilot> [options]")
 		cc.cli.Exit(1)
 		return
 	}
@@ -40,9 +40,11 @@ func (cc *ClusterCommands) Handle(args []string) {
 		cc.Leave(subArgs)
 	case "snapshot":
 		cc.Snapshot(subArgs)
+	case "autopilot":
+		cc.Autopilot(subArgs)
 	default:
 		cc.cli.Errorf("Unknown cluster subcommand: %s\n", subcommand)
-		cc.cli.Errorln("Available: status, leader, peers, join, leave, snapshot")
+		cc.cli.Errorln("Available: status, leader, peers, join, leave, snapshot, autopilot")
 		cc.cli.Exit(1)
 	}
 }
@@ -119,10 +121,18 @@ func (cc *ClusterCommands) Peers(args []string) {
 	info, err := client.ClusterPeers()
 	cc.cli.HandleError(err, "fetching cluster peers")
 
+	voters, replicas := 0, 0
 	cc.cli.Printf("Peers (%d):\n", info.Count)
 	for _, p := range info.Peers {
 		cc.cli.Printf("  %-20s  %-25s  %s\n", p.ID, p.Address, p.State)
+		switch p.State {
+		case "Voter":
+			voters++
+		case "ReadReplica":
+			replicas++
+		}
 	}
+	cc.cli.Printf("Voters: %d, read replicas: %d\n", voters, replicas)
 }
 
 // Join adds a node to the cluster.
@@ -198,3 +208,84 @@ func (cc *ClusterCommands) Snapshot(args []string) {
 	}
 	cc.cli.Printf("Snapshot triggered: %s\n", msg)
 }
+
+// Autopilot routes autopilot subcommands.
+func (cc *ClusterCommands) Autopilot(args []string) {
+	if len(args) == 0 {
+		cc.cli.Errorln("Autopilot subcommand required")
+		cc.cli.Errorln("Usage: konsulctl cluster autopilot <health|config> [options]")
+		cc.cli.Exit(1)
+		return
+	}
+
+	switch args[0] {
+	case "health":
+		cc.AutopilotHealth(args[1:])
+	case "config":
+		cc.AutopilotConfig(args[1:])
+	default:
+		cc.cli.Errorf("Unknown autopilot subcommand: %s\n", args[0])
+		cc.cli.Errorln("Available: health, config")
+		cc.cli.Exit(1)
+	}
+}
+
+// AutopilotHealth prints the health of the cluster servers as seen by the
+// leader. It must be sent to the leader.
+func (cc *ClusterCommands) AutopilotHealth(args []string) {
+	config, remaining, err := cc.cli.ParseGlobalFlags(args, "health")
+	if err == flag.ErrHelp {
+		cc.cli.Println("Usage: konsulctl cluster autopilot health [options]")
+		return
+	}
+	cc.cli.HandleError(err, "parsing flags")
+	cc.cli.ValidateExactArgs(remaining, 0, "Usage: konsulctl cluster autopilot health")
+
+	client := cc.cli.CreateClient(config)
+
+	health, err := client.ClusterAutopilotHealth()
+	cc.cli.HandleError(err, "fetching autopilot health")
+
+	cc.cli.Printf("Healthy:           %t\n", health.Healthy)
+	cc.cli.Printf("Failure Tolerance: %d\n", health.FailureTolerance)
+	cc.cli.Printf("Servers (%d):\n", len(health.Servers))
+	for _, s := range health.Servers {
+		status := "healthy"
+		if !s.Healthy {
+			status = "unhealthy"
+		}
+		contact := s.LastContact
+		if s.Leader {
+			contact = "leader"
+		}
+		role := s.Suffrage
+		if s.ReadReplica {
+			role = "ReadReplica"
+		}
+		cc.cli.Printf("  %-20s  %-25s  %-11s  %-9s  contact: %s, index: %d, lag: %d\n",
+			s.ID, s.Address, role, status, contact, s.LastIndex, s.Lag)
+	}
+}
+
+// AutopilotConfig prints the autopilot configuration of the node.
+func (cc *ClusterCommands) AutopilotConfig(args []string) {
+	config, remaining, err := cc.cli.ParseGlobalFlags(args, "config")
+	if err == flag.ErrHelp {
+		cc.cli.Println("Usage: konsulctl cluster autopilot config [options]")
+		return
+	}
+	cc.cli.HandleError(err, "parsing flags")
+	cc.cli.ValidateExactArgs(remaining, 0, "Usage: konsulctl cluster autopilot config")
+
+	client := cc.cli.CreateClient(config)
+
+	cfg, err := client.ClusterAutopilotConfig()
+	cc.cli.HandleError(err, "fetching autopilot config")
+
+	cc.cli.Printf("Enabled:                   %t\n", cfg.Enabled)
+	cc.cli.Printf("Cleanup Dead Servers:      %t\n", cfg.CleanupDeadServers)
+	cc.cli.Printf("Last Contact Threshold:    %s\n", cfg.LastContactThreshold)
+	cc.cli.Printf("Max Trailing Logs:         %d\n", cfg.MaxTrailingLogs)
+	cc.cli.Printf("Server Stabilization Time: %s\n", cfg.ServerStabilizationTime)
+	cc.cli.Printf("Dead Server Threshold:     %s\n", cfg.DeadServerThreshold)
+}
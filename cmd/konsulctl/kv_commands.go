@@ -1,24 +1,10 @@
 package main
 
 import (
-	"bufio"
-	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
-	"errors"
 	"flag"
-	"fmt"
-	"io"
-	"net/http"
 	"net/url"
-	"os"
-	"os/signal"
-	"strings"
-	"syscall"
 	"time"
-
-	"github.com/gorilla/websocket"
 )
 
 // KVCommands handles all KV store related commands
@@ -172,6 +158,7 @@ func (k *KVCommands) Watch(args []string) {
 		k.cli.Println()
 		k.cli.Println("Options:")
 		k.cli.Println("  --transport <type>  Transport type: websocket (default) or sse")
+		k.cli.Println("  --since <index>      Resume after the event with this index")
 		k.cli.Println("  --server <url>       Konsul server URL (default: http://localhost:8888)")
 		k.cli.Println("  --tls-skip-verify    Skip TLS certificate verification")
 		k.cli.Println("  --ca-cert <file>     Path to CA certificate file")
@@ -181,19 +168,7 @@ func (k *KVCommands) Watch(args []string) {
 		return
 	}
 
-	// Custom flag set with transport option
-	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
-	fs.SetOutput(k.cli.Error)
-	transport := fs.String("transport", "websocket", "Transport type (websocket or sse)")
-
-	// Global flags
-	serverURL := fs.String("server", "http://localhost:8888", "Konsul server URL")
-	tlsSkipVerify := fs.Bool("tls-skip-verify", false, "Skip TLS certificate verification")
-	tlsCACert := fs.String("ca-cert", "", "Path to CA certificate file")
-	tlsClientCert := fs.String("client-cert", "", "Path to client certificate file")
-	tlsClientKey := fs.String("client-key", "", "Path to client key file")
-	tokenFlag := fs.String("token", "", "JWT token for authentication (or set KONSUL_TOKEN)")
-
+	fs, watchConfig := newWatchFlagSet(k.cli, "watch")
 	err := fs.Parse(args)
 	k.cli.HandleError(err, "parsing flags")
 
@@ -201,319 +176,40 @@ func (k *KVCommands) Watch(args []string) {
 	k.cli.ValidateExactArgs(remaining, 1, "Usage: konsulctl kv watch <key|pattern>")
 
 	pattern := remaining[0]
+	config, transport, since := watchConfig()
 
-	// Build client config
-	config := &ClientConfig{
-		Address: *serverURL,
-		TLS: &TLSConfig{
-			Enabled:        strings.HasPrefix(*serverURL, "https://"),
-			SkipVerify:     *tlsSkipVerify,
-			CACertFile:     *tlsCACert,
-			ClientCertFile: *tlsClientCert,
-			ClientKeyFile:  *tlsClientKey,
-		},
+	query := url.Values{}
+	if since != "" {
+		query.Set("since", since)
 	}
-	config.Token = strings.TrimSpace(*tokenFlag)
-	if config.Token == "" {
-		config.Token = strings.TrimSpace(os.Getenv("KONSUL_TOKEN"))
-	}
-
-	switch *transport {
-	case "websocket":
-		k.watchWebSocket(config, pattern)
-	case "sse":
-		k.watchSSE(config, pattern)
-	default:
-		k.cli.Errorf("Unknown transport: %s (must be websocket or sse)\n", *transport)
-		k.cli.Exit(1)
+	stream := &watchStream{
+		cli:      k.cli,
+		config:   config,
+		path:     "/kv/watch/" + url.PathEscape(pattern),
+		query:    query,
+		sseEvent: "kv-change",
+		print:    k.printWatchPayload,
 	}
+	stream.run(transport, pattern)
 }
 
 // WatchEvent represents a watch event from the server
 type WatchEvent struct {
 	Type      string `json:"type"`
 	Key       string `json:"key"`
+	Index     uint64 `json:"index,omitempty"`
 	Value     string `json:"value,omitempty"`
 	OldValue  string `json:"old_value,omitempty"`
 	Timestamp int64  `json:"timestamp"`
 }
 
-// watchWebSocket watches for key changes using WebSocket
-func (k *KVCommands) watchWebSocket(config *ClientConfig, pattern string) {
-	// Build WebSocket URL
-	wsURL, err := k.buildWebSocketURL(config, pattern)
-	k.cli.HandleError(err, "building WebSocket URL")
-
-	k.cli.Printf("Watching %s (WebSocket)...\n", pattern)
-	k.cli.Println("Press Ctrl+C to stop")
-	k.cli.Println()
-
-	// Setup signal handler for graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-sigCh
-		k.cli.Println("\nStopping watch...")
-		cancel()
-	}()
-
-	// Connect to WebSocket
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-	}
-
-	// Add TLS configuration if needed
-	if config.TLS != nil && config.TLS.Enabled {
-		tlsClientConfig, err := k.buildTLSClientConfig(config.TLS)
-		k.cli.HandleError(err, "configuring TLS")
-		dialer.TLSClientConfig = tlsClientConfig
-	}
-
-	// Add authorization header
-	headers := http.Header{}
-	if config.Token != "" {
-		headers.Add("Authorization", "Bearer "+config.Token)
-	}
-
-	conn, _, err := dialer.Dial(wsURL, headers)
-	k.cli.HandleError(err, "connecting to WebSocket")
-	defer func() {
-		if err := conn.Close(); err != nil {
-			k.cli.Errorf("Error closing WebSocket: %v\n", err)
-		}
-	}()
-
-	// Read events
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			var event WatchEvent
-			err := conn.ReadJSON(&event)
-			if err != nil {
-				if ctx.Err() != nil {
-					return // Context cancelled, exit gracefully
-				}
-				k.cli.Errorf("Error reading event: %v\n", err)
-				return
-			}
-
-			k.printWatchEvent(&event)
-		}
-	}
-}
-
-// watchSSE watches for key changes using Server-Sent Events
-func (k *KVCommands) watchSSE(config *ClientConfig, pattern string) {
-	httpURL, err := k.buildHTTPWatchURL(config, pattern)
-	k.cli.HandleError(err, "building SSE URL")
-
-	k.cli.Printf("Watching %s (SSE)...\n", pattern)
-	k.cli.Println("Press Ctrl+C to stop")
-	k.cli.Println()
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-sigCh
-		k.cli.Println("\nStopping watch...")
-		cancel()
-	}()
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, httpURL, nil)
-	k.cli.HandleError(err, "creating SSE request")
-	req.Header.Set("Accept", "text/event-stream")
-	req.Header.Set("Cache-Control", "no-cache")
-	if config.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+config.Token)
-	}
-
-	client := k.newWatchHTTPClient(config)
-	resp, err := client.Do(req)
-	k.cli.HandleError(err, "connecting to SSE endpoint")
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			k.cli.Errorf("Error closing SSE response body: %v\n", err)
-		}
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		msg := strings.TrimSpace(string(body))
-		if msg != "" {
-			k.cli.ExitError("SSE request failed (%s): %s\n", resp.Status, msg)
-		}
-		k.cli.ExitError("SSE request failed (%s)\n", resp.Status)
-		return
-	}
-
-	reader := bufio.NewReader(resp.Body)
-	var (
-		eventName string
-		dataBuf   strings.Builder
-	)
-
-	for {
-		line, err := reader.ReadString('\n')
-		if err != nil {
-			if ctx.Err() != nil || errors.Is(err, context.Canceled) {
-				return
-			}
-			if errors.Is(err, io.EOF) {
-				k.cli.Println("\nConnection closed by server")
-				return
-			}
-			k.cli.Errorf("Error reading SSE stream: %v\n", err)
-			return
-		}
-
-		line = strings.TrimRight(line, "\r\n")
-
-		if line == "" {
-			payload := strings.TrimSuffix(dataBuf.String(), "\n")
-			k.handleSSEEvent(eventName, payload)
-			eventName = ""
-			dataBuf.Reset()
-			continue
-		}
-
-		if strings.HasPrefix(line, ":") {
-			continue
-		}
-
-		switch {
-		case strings.HasPrefix(line, "event:"):
-			eventName = strings.TrimSpace(line[len("event:"):])
-		case strings.HasPrefix(line, "data:"):
-			data := strings.TrimSpace(line[len("data:"):])
-			dataBuf.WriteString(data)
-			dataBuf.WriteByte('\n')
-		default:
-			// Ignore other SSE fields (id, retry, etc)
-		}
-	}
-}
-
-// buildWebSocketURL builds the WebSocket URL for watching
-func (k *KVCommands) buildWebSocketURL(config *ClientConfig, pattern string) (string, error) {
-	u, err := url.Parse(config.Address)
-	if err != nil {
-		return "", fmt.Errorf("invalid server URL: %w", err)
-	}
-
-	scheme := "ws"
-	if u.Scheme == "https" {
-		scheme = "wss"
-	}
-
-	u.Scheme = scheme
-	u.Path = buildWatchPath(u.Path, pattern)
-	u.RawQuery = ""
-	u.Fragment = ""
-
-	return u.String(), nil
-}
-
-func (k *KVCommands) buildHTTPWatchURL(config *ClientConfig, pattern string) (string, error) {
-	u, err := url.Parse(config.Address)
-	if err != nil {
-		return "", fmt.Errorf("invalid server URL: %w", err)
-	}
-
-	u.Path = buildWatchPath(u.Path, pattern)
-	u.RawQuery = ""
-	u.Fragment = ""
-
-	return u.String(), nil
-}
-
-func buildWatchPath(basePath, pattern string) string {
-	base := strings.TrimSuffix(basePath, "/")
-	if base == "" {
-		return fmt.Sprintf("/kv/watch/%s", url.PathEscape(pattern))
-	}
-
-	return fmt.Sprintf("%s/kv/watch/%s", base, url.PathEscape(pattern))
-}
-
-func (k *KVCommands) newWatchHTTPClient(config *ClientConfig) *http.Client {
-	transport := http.DefaultTransport.(*http.Transport).Clone()
-
-	if config.TLS != nil && config.TLS.Enabled {
-		tlsClientConfig, err := k.buildTLSClientConfig(config.TLS)
-		k.cli.HandleError(err, "configuring TLS")
-		if tlsClientConfig != nil {
-			transport.TLSClientConfig = tlsClientConfig
-		}
-	}
-
-	return &http.Client{
-		Timeout:   0,
-		Transport: transport,
-	}
-}
-
-func (k *KVCommands) buildTLSClientConfig(tlsConfig *TLSConfig) (*tls.Config, error) {
-	if tlsConfig == nil || !tlsConfig.Enabled {
-		return nil, nil
-	}
-
-	clientConfig := &tls.Config{
-		InsecureSkipVerify: tlsConfig.SkipVerify,
-	}
-
-	if tlsConfig.CACertFile != "" {
-		caCert, err := os.ReadFile(tlsConfig.CACertFile)
-		if err != nil {
-			return nil, fmt.Errorf("reading CA certificate: %w", err)
-		}
-
-		caCertPool := x509.NewCertPool()
-		if !caCertPool.AppendCertsFromPEM(caCert) {
-			return nil, fmt.Errorf("parsing CA certificate: invalid PEM data")
-		}
-		clientConfig.RootCAs = caCertPool
-	}
-
-	if tlsConfig.ClientCertFile != "" || tlsConfig.ClientKeyFile != "" {
-		if tlsConfig.ClientCertFile == "" || tlsConfig.ClientKeyFile == "" {
-			return nil, fmt.Errorf("both client-cert and client-key must be provided")
-		}
-
-		cert, err := tls.LoadX509KeyPair(tlsConfig.ClientCertFile, tlsConfig.ClientKeyFile)
-		if err != nil {
-			return nil, fmt.Errorf("loading client certificate: %w", err)
-		}
-		clientConfig.Certificates = []tls.Certificate{cert}
-	}
-
-	return clientConfig, nil
-}
-
-func (k *KVCommands) handleSSEEvent(eventName, payload string) {
-	payload = strings.TrimSpace(payload)
-	if payload == "" {
-		return
-	}
-
-	if eventName != "" && eventName != "kv-change" {
-		k.cli.Printf("Received %s event: %s\n", eventName, payload)
-		return
-	}
-
+// printWatchPayload prints a watch event received from the server
+func (k *KVCommands) printWatchPayload(payload []byte) {
 	var event WatchEvent
-	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+	if err := json.Unmarshal(payload, &event); err != nil {
 		k.cli.Errorf("Failed to parse watch event: %v\n", err)
 		return
 	}
-
 	k.printWatchEvent(&event)
 }
 
@@ -530,6 +226,8 @@ func (k *KVCommands) printWatchEvent(event *WatchEvent) {
 		}
 	case "delete":
 		k.cli.Printf("[%s] DELETE %s (was: %s)\n", timestamp, event.Key, event.OldValue)
+	case "resync":
+		k.cli.Printf("[%s] RESYNC required: events were missed, reload the keys and watch again with --since %d\n", timestamp, event.Index)
 	default:
 		k.cli.Printf("[%s] UNKNOWN event type: %s for key %s\n", timestamp, event.Type, event.Key)
 	}
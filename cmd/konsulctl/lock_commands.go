@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// LockCommands handles the lock command, which runs a child process while
+// holding a distributed lock on a KV key.
+type LockCommands struct {
+	cli *CLI
+}
+
+// NewLockCommands creates a new lock commands handler.
+func NewLockCommands(cli *CLI) *LockCommands {
+	return &LockCommands{cli: cli}
+}
+
+// Handle acquires the lock, runs the command and releases the lock when the
+// command exits.
+// Usage: konsulctl lock [options] <key> <command> [args...]
+func (lc *LockCommands) Handle(args []string) {
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+		lc.printUsage()
+		return
+	}
+
+	fs := flag.NewFlagSet("lock", flag.ContinueOnError)
+	fs.SetOutput(lc.cli.Error)
+	ttl := fs.Duration("ttl", 15*time.Second, "Session TTL, renewed while the command runs")
+	behavior := fs.String("behavior", "release", "What happens to the key if the session is invalidated (release or delete)")
+	value := fs.String("value", "", "Value stored in the key while the lock is held")
+	name := fs.String("name", "", "Session name (default: konsulctl lock <key>)")
+	retry := fs.Duration("retry", time.Second, "Interval between acquire attempts while the lock is held elsewhere")
+	timeout := fs.Duration("timeout", 0, "Give up if the lock is not acquired within this duration (0 waits forever)")
+
+	// Global flags
+	config := &GlobalConfig{}
+	fs.StringVar(&config.ServerURL, "server", "http://localhost:8888", "Konsul server URL")
+	fs.BoolVar(&config.TLSSkipVerify, "tls-skip-verify", false, "Skip TLS certificate verification")
+	fs.StringVar(&config.TLSCACert, "ca-cert", "", "Path to CA certificate file")
+	fs.StringVar(&config.TLSClientCert, "client-cert", "", "Path to client certificate file")
+	fs.StringVar(&config.TLSClientKey, "client-key", "", "Path to client key file")
+	fs.StringVar(&config.Namespace, "namespace", os.Getenv("KONSUL_NAMESPACE"), "Namespace of the key (default: default)")
+
+	err := fs.Parse(args)
+	lc.cli.HandleError(err, "parsing flags")
+
+	remaining := fs.Args()
+	lc.cli.ValidateMinArgs(remaining, 2, "Usage: konsulctl lock [options] <key> <command> [args...]")
+
+	key := remaining[0]
+	if *name == "" {
+		*name = "konsulctl lock " + key
+	}
+
+	client := lc.cli.CreateClient(config)
+
+	session, err := client.CreateSession(SessionRequest{
+		Name:     *name,
+		TTL:      ttl.String(),
+		Behavior: *behavior,
+	})
+	lc.cli.HandleError(err, "creating session")
+
+	if err := lc.acquire(client, key, session.ID, *value, *retry, *timeout); err != nil {
+		_ = client.DestroySession(session.ID)
+		lc.cli.HandleError(err, "acquiring lock")
+	}
+	lc.cli.Errorf("Lock acquired on %s (session %s)\n", key, session.ID)
+
+	stopRenew := make(chan struct{})
+	go lc.renew(client, session.ID, *ttl/2, stopRenew)
+
+	exitCode := lc.run(remaining[1], remaining[2:])
+
+	close(stopRenew)
+	if _, err := client.ReleaseLock(key, session.ID); err != nil {
+		lc.cli.Errorf("Error releasing lock: %v\n", err)
+	}
+	if err := client.DestroySession(session.ID); err != nil {
+		lc.cli.Errorf("Error destroying session: %v\n", err)
+	}
+
+	if exitCode != 0 {
+		lc.cli.Exit(exitCode)
+	}
+}
+
+// acquire retries until the lock is taken or the timeout expires.
+func (lc *LockCommands) acquire(client *KonsulClient, key, session, value string, retry, timeout time.Duration) error {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	for {
+		result, err := client.AcquireLock(key, session, value)
+		if err != nil {
+			return err
+		}
+		if result.Result {
+			return nil
+		}
+		if !deadline.IsZero() && time.Now().Add(retry).After(deadline) {
+			return errors.New("timed out waiting for lock held by session " + result.Session)
+		}
+		time.Sleep(retry)
+	}
+}
+
+// renew keeps the session alive until stop is closed.
+func (lc *LockCommands) renew(client *KonsulClient, session string, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := client.RenewSession(session); err != nil {
+				lc.cli.Errorf("Error renewing session: %v\n", err)
+			}
+		}
+	}
+}
+
+// run executes the command with the terminal attached, forwarding interrupt
+// signals, and returns its exit code.
+func (lc *LockCommands) run(name string, args []string) int {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = lc.cli.Output
+	cmd.Stderr = lc.cli.Error
+
+	if err := cmd.Start(); err != nil {
+		lc.cli.Errorf("Error starting command: %v\n", err)
+		return 1
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		for sig := range sigCh {
+			_ = cmd.Process.Signal(sig)
+		}
+	}()
+
+	if err := cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		lc.cli.Errorf("Error running command: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func (lc *LockCommands) printUsage() {
+	lc.cli.Println("Usage: konsulctl lock [options] <key> <command> [args...]")
+	lc.cli.Println()
+	lc.cli.Println("Creates a session, waits until it holds the lock on <key>, runs the command")
+	lc.cli.Println("and releases the lock when the command exits. The session is renewed while")
+	lc.cli.Println("the command runs.")
+	lc.cli.Println()
+	lc.cli.Println("Options:")
+	lc.cli.Println("  --ttl <duration>      Session TTL (default: 15s)")
+	lc.cli.Println("  --behavior <b>        release or delete the key if the session is invalidated (default: release)")
+	lc.cli.Println("  --value <value>       Value stored in the key while the lock is held")
+	lc.cli.Println("  --name <name>         Session name")
+	lc.cli.Println("  --retry <duration>    Interval between acquire attempts (default: 1s)")
+	lc.cli.Println("  --timeout <duration>  Give up after this long (default: wait forever)")
+	lc.cli.Println("  --server <url>        Konsul server URL (default: http://localhost:8888)")
+	lc.cli.Println("  --tls-skip-verify     Skip TLS certificate verification")
+	lc.cli.Println("  --ca-cert <file>      Path to CA certificate file")
+	lc.cli.Println("  --client-cert <file>  Path to client certificate file")
+	lc.cli.Println("  --client-key <file>   Path to client key file")
+}
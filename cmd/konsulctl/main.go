@@ -40,6 +40,18 @@ func main() {
 	case "cluster":
 		clusterCmd := NewClusterCommands(cli)
 		clusterCmd.Handle(args)
+	case "lock":
+		lockCmd := NewLockCommands(cli)
+		lockCmd.Handle(args)
+	case "namespace":
+		namespaceCmd := NewNamespaceCommands(cli)
+		namespaceCmd.Handle(args)
+	case "webhook":
+		webhookCmd := NewWebhookCommands(cli)
+		webhookCmd.Handle(args)
+	case "user":
+		userCmd := NewUserCommands(cli)
+		userCmd.Handle(args)
 	case "version":
 		cli.Printf("konsulctl version %s\n", version)
 	case "help", "-h", "--help":
@@ -69,6 +81,7 @@ func printUsage() {
 	fmt.Println("    list             List all services")
 	fmt.Println("    deregister <name>  Deregister service")
 	fmt.Println("    heartbeat <name>   Send heartbeat for service")
+	fmt.Println("    watch [name|pattern]  Watch for service changes")
 	fmt.Println()
 	fmt.Println("  backup <subcommand>   Backup operations")
 	fmt.Println("    create           Create a backup")
@@ -105,10 +118,39 @@ func printUsage() {
 	fmt.Println("    join <id> <addr> Add a node to the cluster")
 	fmt.Println("    leave <id>       Remove a node from the cluster")
 	fmt.Println("    snapshot         Trigger a Raft snapshot")
+	fmt.Println("    autopilot health Show server health as seen by the leader")
+	fmt.Println("    autopilot config Show the autopilot configuration")
+	fmt.Println()
+	fmt.Println("  lock <key> <cmd>   Run a command while holding a lock on a key")
+	fmt.Println("    --ttl <duration>   Session TTL (default: 15s)")
+	fmt.Println("    --timeout <dur>    Give up if the lock is not acquired in time")
+	fmt.Println()
+	fmt.Println("  namespace <subcommand>  Namespace operations")
+	fmt.Println("    list             List all namespaces")
+	fmt.Println("    create <name> [--description <text>]  Create a namespace")
+	fmt.Println("    delete <name>    Delete an empty namespace")
+	fmt.Println()
+	fmt.Println("  webhook <subcommand>  Webhook operations")
+	fmt.Println("    list             List all webhooks")
+	fmt.Println("    get <name>       Get webhook details")
+	fmt.Println("    set <name> <url> [--secret <s>] [--kind <kv|service>] [--pattern <p>] [--events <e1,e2>]  Create or replace a webhook")
+	fmt.Println("    delete <name>    Delete a webhook")
+	fmt.Println("    deliveries <name>  Show pending and recent deliveries")
+	fmt.Println()
+	fmt.Println("  user <subcommand>  User management")
+	fmt.Println("    login <username> [--password <pw>]  Log in and print a JWT token")
+	fmt.Println("    list             List all users")
+	fmt.Println("    get <username>   Get user details")
+	fmt.Println("    create <username> [--password <pw>] [--roles <r1,r2>] [--policies <p1,p2>]  Create a user")
+	fmt.Println("    update <username> [--roles <r1,r2>] [--policies <p1,p2>] [--disable|--enable]  Update a user")
+	fmt.Println("    passwd <username> [--password <pw>]  Set a user's password")
+	fmt.Println("    delete <username>  Delete a user")
 	fmt.Println()
 	fmt.Println("  version            Show version")
 	fmt.Println("  help               Show this help")
 	fmt.Println()
 	fmt.Println("Global Options:")
 	fmt.Println("  --server <url>     Konsul server URL (default: http://localhost:8888)")
+	fmt.Println("  --namespace <ns>   Namespace to operate in (default: $KONSUL_NAMESPACE or default)")
+	fmt.Println("  --token <jwt>      JWT token to authenticate with (default: $KONSUL_TOKEN)")
 }
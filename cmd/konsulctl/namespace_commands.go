@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+)
+
+// NamespaceCommands handles all namespace management commands.
+type NamespaceCommands struct {
+	cli *CLI
+}
+
+// NewNamespaceCommands creates a new namespace commands handler.
+func NewNamespaceCommands(cli *CLI) *NamespaceCommands {
+	return &NamespaceCommands{cli: cli}
+}
+
+// Handle routes namespace subcommands.
+func (nc *NamespaceCommands) Handle(args []string) {
+	if len(args) == 0 {
+		nc.cli.Errorln("Namespace subcommand required")
+		nc.cli.Errorln("Usage: konsulctl namespace <list|create|delete> [options]")
+		nc.cli.Exit(1)
+		return
+	}
+
+	subcommand := args[0]
+	subArgs := args[1:]
+
+	switch subcommand {
+	case "list":
+		nc.List(subArgs)
+	case "create":
+		nc.Create(subArgs)
+	case "delete":
+		nc.Delete(subArgs)
+	default:
+		nc.cli.Errorf("Unknown namespace subcommand: %s\n", subcommand)
+		nc.cli.Errorln("Available: list, create, delete")
+		nc.cli.Exit(1)
+	}
+}
+
+// List prints all namespaces.
+func (nc *NamespaceCommands) List(args []string) {
+	config, remaining, err := nc.cli.ParseGlobalFlags(args, "list")
+	if err == flag.ErrHelp {
+		nc.cli.Println("Usage: konsulctl namespace list [options]")
+		return
+	}
+	nc.cli.HandleError(err, "parsing flags")
+	nc.cli.ValidateExactArgs(remaining, 0, "Usage: konsulctl namespace list")
+
+	client := nc.cli.CreateClient(config)
+
+	namespaces, err := client.ListNamespaces()
+	nc.cli.HandleError(err, "listing namespaces")
+
+	nc.cli.Println("Namespaces:")
+	for _, ns := range namespaces {
+		if ns.Description != "" {
+			nc.cli.Printf("  %s - %s\n", ns.Name, ns.Description)
+		} else {
+			nc.cli.Printf("  %s\n", ns.Name)
+		}
+	}
+}
+
+// Create creates a namespace.
+func (nc *NamespaceCommands) Create(args []string) {
+	var description string
+	flagSet := flag.NewFlagSet("create", flag.ContinueOnError)
+	flagSet.SetOutput(nc.cli.Error)
+	flagSet.StringVar(&description, "description", "", "Namespace description")
+
+	config, remaining, err := nc.cli.ParseGlobalFlags(args, "create")
+	if err == flag.ErrHelp {
+		nc.cli.Println("Usage: konsulctl namespace create <name> [--description <text>] [options]")
+		return
+	}
+	nc.cli.HandleError(err, "parsing flags")
+	nc.cli.ValidateMinArgs(remaining, 1, "Usage: konsulctl namespace create <name> [--description <text>]")
+
+	err = flagSet.Parse(remaining[1:])
+	nc.cli.HandleError(err, "parsing create flags")
+
+	client := nc.cli.CreateClient(config)
+
+	ns, err := client.CreateNamespace(remaining[0], description)
+	nc.cli.HandleError(err, "creating namespace")
+
+	nc.cli.Printf("Successfully created namespace: %s\n", ns.Name)
+}
+
+// Delete deletes an empty namespace.
+func (nc *NamespaceCommands) Delete(args []string) {
+	config, remaining, err := nc.cli.ParseGlobalFlags(args, "delete")
+	if err == flag.ErrHelp {
+		nc.cli.Println("Usage: konsulctl namespace delete <name> [options]")
+		return
+	}
+	nc.cli.HandleError(err, "parsing flags")
+	nc.cli.ValidateExactArgs(remaining, 1, "Usage: konsulctl namespace delete <name>")
+
+	client := nc.cli.CreateClient(config)
+
+	err = client.DeleteNamespace(remaining[0])
+	nc.cli.HandleError(err, "deleting namespace")
+
+	nc.cli.Printf("Successfully deleted namespace: %s\n", remaining[0])
+}
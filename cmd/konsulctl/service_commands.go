@@ -1,8 +1,14 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // ServiceCommands handles all service discovery related commands
@@ -19,7 +25,7 @@ func NewServiceCommands(cli *CLI) *ServiceCommands {
 func (s *ServiceCommands) Handle(args []string) {
 	if len(args) == 0 {
 		s.cli.Errorln("Service subcommand required")
-		s.cli.Errorln("Usage: konsulctl service <register|list|deregister|heartbeat> [options]")
+		s.cli.Errorln("Usage: konsulctl service <register|list|deregister|heartbeat|watch> [options]")
 		s.cli.Exit(1)
 		return
 	}
@@ -36,9 +42,11 @@ func (s *ServiceCommands) Handle(args []string) {
 		s.Deregister(subArgs)
 	case "heartbeat":
 		s.Heartbeat(subArgs)
+	case "watch":
+		s.Watch(subArgs)
 	default:
 		s.cli.Errorf("Unknown service subcommand: %s\n", subcommand)
-		s.cli.Errorln("Available: register, list, deregister, heartbeat")
+		s.cli.Errorln("Available: register, list, deregister, heartbeat, watch")
 		s.cli.Exit(1)
 	}
 }
@@ -47,22 +55,26 @@ func (s *ServiceCommands) Handle(args []string) {
 func (s *ServiceCommands) Register(args []string) {
 	config, remaining, err := s.cli.ParseGlobalFlags(args, "register")
 	if err == flag.ErrHelp {
-		s.cli.Println("Usage: konsulctl service register <name> <address> <port> [--check-http <url>] [--check-tcp <addr>] [--check-ttl <duration>] [options]")
+		s.cli.Println("Usage: konsulctl service register <name> <address> <port> [--id <instance-id>] [--node <node>] [--check-http <url> [--check-body-match <regex>] [--check-json-path <path> [--check-json-value <value>]]] [--check-tcp <addr>] [--check-udp <addr>] [--check-script <command>] [--check-ttl <duration>] [--check-success-before-passing <n>] [--check-failures-before-critical <n>] [--check-deregister-after <duration>] [options]")
 		return
 	}
 	s.cli.HandleError(err, "parsing flags")
-	s.cli.ValidateMinArgs(remaining, 3, "Usage: konsulctl service register <name> <address> <port> [--check-http <url>] [--check-tcp <addr>] [--check-ttl <duration>]")
+	s.cli.ValidateMinArgs(remaining, 3, "Usage: konsulctl service register <name> <address> <port> [--id <instance-id>] [--node <node>] [--check-http <url> [--check-body-match <regex>] [--check-json-path <path> [--check-json-value <value>]]] [--check-tcp <addr>] [--check-udp <addr>] [--check-script <command>] [--check-ttl <duration>] [--check-success-before-passing <n>] [--check-failures-before-critical <n>] [--check-deregister-after <duration>]")
 
 	name := remaining[0]
 	address := remaining[1]
 	port := remaining[2]
 
+	// Parse instance flags
+	id, node, err := s.parseInstanceFlags(remaining[3:])
+	s.cli.HandleError(err, "parsing instance flags")
+
 	// Parse health check flags
 	checks, err := s.parseHealthChecks(name, remaining[3:])
 	s.cli.HandleError(err, "parsing health checks")
 
 	client := s.cli.CreateClient(config)
-	err = client.RegisterServiceWithChecks(name, address, port, checks)
+	err = client.RegisterServiceWithChecks(id, name, node, address, port, checks)
 	s.cli.HandleError(err, "registering service '"+name+"'")
 
 	checkInfo := ""
@@ -70,12 +82,45 @@ func (s *ServiceCommands) Register(args []string) {
 		checkInfo = fmt.Sprintf(" with %d health check(s)", len(checks))
 	}
 
-	s.cli.Printf("Successfully registered service: %s at %s:%s%s\n", name, address, port, checkInfo)
+	instanceInfo := ""
+	if id != "" {
+		instanceInfo = fmt.Sprintf(" (instance %s)", id)
+	}
+
+	s.cli.Printf("Successfully registered service: %s%s at %s:%s%s\n", name, instanceInfo, address, port, checkInfo)
+}
+
+// parseInstanceFlags parses the instance ID and node flags from arguments
+func (s *ServiceCommands) parseInstanceFlags(args []string) (id, node string, err error) {
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--id":
+			if i+1 >= len(args) {
+				return "", "", fmt.Errorf("--id requires an instance ID")
+			}
+			id = args[i+1]
+			i++
+
+		case "--node":
+			if i+1 >= len(args) {
+				return "", "", fmt.Errorf("--node requires a node name")
+			}
+			node = args[i+1]
+			i++
+		}
+	}
+
+	return id, node, nil
 }
 
-// parseHealthChecks parses health check flags from arguments
+// parseHealthChecks parses health check flags from arguments. Body
+// assertion flags apply to the HTTP checks; threshold and deregistration
+// flags apply to every check.
 func (s *ServiceCommands) parseHealthChecks(serviceName string, args []string) ([]*CheckDefinition, error) {
 	var checks []*CheckDefinition
+	var bodyMatch, jsonPath, jsonValue string
+	var successBefore, failuresBefore int
+	var deregisterAfter string
 
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
@@ -103,6 +148,66 @@ func (s *ServiceCommands) parseHealthChecks(serviceName string, args []string) (
 			})
 			i++ // Skip the address argument
 
+		case "--check-udp":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("--check-udp requires an address")
+			}
+			checks = append(checks, &CheckDefinition{
+				Name:     fmt.Sprintf("%s-udp-check", serviceName),
+				UDP:      args[i+1],
+				Interval: "30s",
+				Timeout:  "10s",
+			})
+			i++ // Skip the address argument
+
+		case "--check-script":
+			if i+1 >= len(args) || strings.TrimSpace(args[i+1]) == "" {
+				return nil, fmt.Errorf("--check-script requires a command")
+			}
+			checks = append(checks, &CheckDefinition{
+				Name:     fmt.Sprintf("%s-script-check", serviceName),
+				Args:     strings.Fields(args[i+1]),
+				Interval: "30s",
+				Timeout:  "10s",
+			})
+			i++ // Skip the command argument
+
+		case "--check-body-match", "--check-json-path", "--check-json-value":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("%s requires a value", args[i])
+			}
+			switch args[i] {
+			case "--check-body-match":
+				bodyMatch = args[i+1]
+			case "--check-json-path":
+				jsonPath = args[i+1]
+			default:
+				jsonValue = args[i+1]
+			}
+			i++ // Skip the value argument
+
+		case "--check-success-before-passing", "--check-failures-before-critical":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("%s requires a count", args[i])
+			}
+			count, err := strconv.Atoi(args[i+1])
+			if err != nil || count < 0 {
+				return nil, fmt.Errorf("%s requires a count, got %q", args[i], args[i+1])
+			}
+			if args[i] == "--check-success-before-passing" {
+				successBefore = count
+			} else {
+				failuresBefore = count
+			}
+			i++ // Skip the count argument
+
+		case "--check-deregister-after":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("--check-deregister-after requires a duration")
+			}
+			deregisterAfter = args[i+1]
+			i++ // Skip the duration argument
+
 		case "--check-ttl":
 			if i+1 >= len(args) {
 				return nil, fmt.Errorf("--check-ttl requires a duration")
@@ -124,6 +229,35 @@ func (s *ServiceCommands) parseHealthChecks(serviceName string, args []string) (
 		}
 	}
 
+	if bodyMatch != "" || jsonPath != "" || jsonValue != "" {
+		if jsonValue != "" && jsonPath == "" {
+			return nil, fmt.Errorf("--check-json-value requires --check-json-path")
+		}
+		found := false
+		for _, check := range checks {
+			if check.HTTP != "" {
+				check.BodyMatch = bodyMatch
+				check.BodyJSONPath = jsonPath
+				check.BodyJSONValue = jsonValue
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("body assertions require --check-http")
+		}
+	}
+
+	if successBefore > 0 || failuresBefore > 0 || deregisterAfter != "" {
+		if len(checks) == 0 {
+			return nil, fmt.Errorf("check thresholds and --check-deregister-after require a check")
+		}
+		for _, check := range checks {
+			check.SuccessBeforePassing = successBefore
+			check.FailuresBeforeCritical = failuresBefore
+			check.DeregisterCriticalServiceAfter = deregisterAfter
+		}
+	}
+
 	return checks, nil
 }
 
@@ -149,7 +283,15 @@ func (s *ServiceCommands) List(args []string) {
 
 	s.cli.Println("Services:")
 	for _, service := range services {
-		s.cli.Printf("  %s - %s:%d\n", service.Name, service.Address, service.Port)
+		instance := ""
+		if service.ID != "" && service.ID != service.Name {
+			instance = fmt.Sprintf(" [%s]", service.ID)
+		}
+		node := ""
+		if service.Node != "" {
+			node = fmt.Sprintf(" on %s", service.Node)
+		}
+		s.cli.Printf("  %s%s - %s:%d%s\n", service.Name, instance, service.Address, service.Port, node)
 	}
 }
 
@@ -157,11 +299,12 @@ func (s *ServiceCommands) List(args []string) {
 func (s *ServiceCommands) Deregister(args []string) {
 	config, remaining, err := s.cli.ParseGlobalFlags(args, "deregister")
 	if err == flag.ErrHelp {
-		s.cli.Println("Usage: konsulctl service deregister <name> [options]")
+		s.cli.Println("Usage: konsulctl service deregister <instance-id> [options]")
+		s.cli.Println("The instance ID defaults to the service name when none was given at registration.")
 		return
 	}
 	s.cli.HandleError(err, "parsing flags")
-	s.cli.ValidateExactArgs(remaining, 1, "Usage: konsulctl service deregister <name>")
+	s.cli.ValidateExactArgs(remaining, 1, "Usage: konsulctl service deregister <instance-id>")
 
 	name := remaining[0]
 	client := s.cli.CreateClient(config)
@@ -176,11 +319,12 @@ func (s *ServiceCommands) Deregister(args []string) {
 func (s *ServiceCommands) Heartbeat(args []string) {
 	config, remaining, err := s.cli.ParseGlobalFlags(args, "heartbeat")
 	if err == flag.ErrHelp {
-		s.cli.Println("Usage: konsulctl service heartbeat <name> [options]")
+		s.cli.Println("Usage: konsulctl service heartbeat <instance-id> [options]")
+		s.cli.Println("The instance ID defaults to the service name when none was given at registration.")
 		return
 	}
 	s.cli.HandleError(err, "parsing flags")
-	s.cli.ValidateExactArgs(remaining, 1, "Usage: konsulctl service heartbeat <name>")
+	s.cli.ValidateExactArgs(remaining, 1, "Usage: konsulctl service heartbeat <instance-id>")
 
 	name := remaining[0]
 	client := s.cli.CreateClient(config)
@@ -190,3 +334,123 @@ func (s *ServiceCommands) Heartbeat(args []string) {
 
 	s.cli.Printf("Successfully sent heartbeat for service: %s\n", name)
 }
+
+// Watch watches for instances of services being registered and deregistered
+// and for their health checks changing status
+func (s *ServiceCommands) Watch(args []string) {
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+		s.cli.Println("Usage: konsulctl service watch [name|pattern] [options]")
+		s.cli.Println()
+		s.cli.Println("Watch for service instances being registered and deregistered, and for")
+		s.cli.Println("their health checks changing status. Watching a single service starts")
+		s.cli.Println("with its current instances. Without a name, all services are watched.")
+		s.cli.Println()
+		s.cli.Println("Examples:")
+		s.cli.Println("  konsulctl service watch web")
+		s.cli.Println("  konsulctl service watch 'web-*'")
+		s.cli.Println("  konsulctl service watch --namespace staging")
+		s.cli.Println()
+		s.cli.Println("Options:")
+		s.cli.Println("  --namespace <ns>     Namespace of the services (or set KONSUL_NAMESPACE)")
+		s.cli.Println("  --transport <type>  Transport type: websocket (default) or sse")
+		s.cli.Println("  --since <index>      Resume after the event with this index")
+		s.cli.Println("  --server <url>       Konsul server URL (default: http://localhost:8888)")
+		s.cli.Println("  --tls-skip-verify    Skip TLS certificate verification")
+		s.cli.Println("  --ca-cert <file>     Path to CA certificate file")
+		s.cli.Println("  --client-cert <file> Path to client certificate file")
+		s.cli.Println("  --client-key <file>  Path to client key file")
+		s.cli.Println("  --token <token>      JWT token for authentication (or set KONSUL_TOKEN)")
+		return
+	}
+
+	fs, watchConfig := newWatchFlagSet(s.cli, "watch")
+	namespace := fs.String("namespace", os.Getenv("KONSUL_NAMESPACE"), "Namespace of the services")
+	err := fs.Parse(args)
+	s.cli.HandleError(err, "parsing flags")
+
+	remaining := fs.Args()
+	if len(remaining) > 1 {
+		s.cli.ExitError("Usage: konsulctl service watch [name|pattern]\n")
+		return
+	}
+	pattern := "*"
+	if len(remaining) == 1 {
+		pattern = remaining[0]
+	}
+	config, transport, since := watchConfig()
+
+	query := url.Values{}
+	if *namespace != "" {
+		query.Set("ns", *namespace)
+	}
+	if since != "" {
+		query.Set("since", since)
+	}
+	stream := &watchStream{
+		cli:      s.cli,
+		config:   config,
+		path:     "/services/watch/" + pattern, // Service names have no slashes
+		query:    query,
+		sseEvent: "service-change",
+		print:    s.printWatchPayload,
+	}
+	stream.run(transport, "services "+pattern)
+}
+
+// ServiceWatchEvent represents a service watch event from the server
+type ServiceWatchEvent struct {
+	Type      string `json:"type"`
+	Key       string `json:"key"`
+	Namespace string `json:"namespace,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Index     uint64 `json:"index,omitempty"`
+	Check     string `json:"check,omitempty"`
+	Value     string `json:"value,omitempty"`
+	OldValue  string `json:"old_value,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+	Service   *struct {
+		Address string `json:"address"`
+		Port    int    `json:"port"`
+	} `json:"service,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// printWatchPayload prints a service watch event received from the server
+func (s *ServiceCommands) printWatchPayload(payload []byte) {
+	var event ServiceWatchEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		s.cli.Errorf("Failed to parse watch event: %v\n", err)
+		return
+	}
+	if event.Error != "" {
+		s.cli.Errorf("Watch failed: %s: %s\n", event.Error, event.Message)
+		return
+	}
+
+	timestamp := time.Unix(event.Timestamp, 0).Format("2006-01-02 15:04:05")
+	instance := event.Key
+	if event.Instance != "" && event.Instance != event.Key {
+		instance = event.Key + "/" + event.Instance
+	}
+	if event.Namespace != "" {
+		instance = event.Namespace + ":" + instance
+	}
+
+	switch event.Type {
+	case "register":
+		if event.Service != nil {
+			s.cli.Printf("[%s] REGISTER %s at %s:%d\n", timestamp, instance, event.Service.Address, event.Service.Port)
+		} else {
+			s.cli.Printf("[%s] REGISTER %s\n", timestamp, instance)
+		}
+	case "deregister":
+		s.cli.Printf("[%s] DEREGISTER %s\n", timestamp, instance)
+	case "health":
+		s.cli.Printf("[%s] HEALTH %s: check %s %s -> %s\n", timestamp, instance, event.Check, event.OldValue, event.Value)
+	case "resync":
+		s.cli.Printf("[%s] RESYNC required: events were missed, reload the services and watch again with --since %d\n", timestamp, event.Index)
+	default:
+		s.cli.Printf("[%s] UNKNOWN event type: %s for service %s\n", timestamp, event.Type, instance)
+	}
+}
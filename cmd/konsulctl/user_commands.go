@@ -0,0 +1,291 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+)
+
+// UserCommands handles all user management commands.
+type UserCommands struct {
+	cli *CLI
+}
+
+// NewUserCommands creates a new user commands handler.
+func NewUserCommands(cli *CLI) *UserCommands {
+	return &UserCommands{cli: cli}
+}
+
+// Handle routes user subcommands.
+func (uc *UserCommands) Handle(args []string) {
+	if len(args) == 0 {
+		uc.cli.Errorln("User subcommand required")
+		uc.cli.Errorln("Usage: konsulctl user <login|list|get|create|update|passwd|delete> [options]")
+		uc.cli.Exit(1)
+		return
+	}
+
+	subcommand := args[0]
+	subArgs := args[1:]
+
+	switch subcommand {
+	case "login":
+		uc.Login(subArgs)
+	case "list":
+		uc.List(subArgs)
+	case "get":
+		uc.Get(subArgs)
+	case "create":
+		uc.Create(subArgs)
+	case "update":
+		uc.Update(subArgs)
+	case "passwd":
+		uc.Passwd(subArgs)
+	case "delete":
+		uc.Delete(subArgs)
+	default:
+		uc.cli.Errorf("Unknown user subcommand: %s\n", subcommand)
+		uc.cli.Errorln("Available: login, list, get, create, update, passwd, delete")
+		uc.cli.Exit(1)
+	}
+}
+
+// Login logs in with a username and password and prints the JWT token.
+func (uc *UserCommands) Login(args []string) {
+	var password string
+	flagSet := flag.NewFlagSet("login", flag.ContinueOnError)
+	flagSet.SetOutput(uc.cli.Error)
+	flagSet.StringVar(&password, "password", os.Getenv("KONSUL_PASSWORD"), "Password (default: $KONSUL_PASSWORD)")
+
+	config, remaining, err := uc.cli.ParseGlobalFlags(args, "login")
+	if err == flag.ErrHelp {
+		uc.cli.Println("Usage: konsulctl user login <username> [--password <password>] [options]")
+		return
+	}
+	uc.cli.HandleError(err, "parsing flags")
+	uc.cli.ValidateMinArgs(remaining, 1, "Usage: konsulctl user login <username> [--password <password>]")
+
+	err = flagSet.Parse(remaining[1:])
+	uc.cli.HandleError(err, "parsing login flags")
+	uc.requirePassword(password)
+
+	client := uc.cli.CreateClient(config)
+
+	tokens, err := client.Login(remaining[0], password)
+	uc.cli.HandleError(err, "logging in")
+
+	// Only the token goes to stdout so that it can be captured:
+	//   export KONSUL_TOKEN=$(konsulctl user login admin)
+	uc.cli.Println(tokens.Token)
+}
+
+// List prints all users.
+func (uc *UserCommands) List(args []string) {
+	config, remaining, err := uc.cli.ParseGlobalFlags(args, "list")
+	if err == flag.ErrHelp {
+		uc.cli.Println("Usage: konsulctl user list [options]")
+		return
+	}
+	uc.cli.HandleError(err, "parsing flags")
+	uc.cli.ValidateExactArgs(remaining, 0, "Usage: konsulctl user list")
+
+	client := uc.cli.CreateClient(config)
+
+	users, err := client.ListUsers()
+	uc.cli.HandleError(err, "listing users")
+
+	if len(users) == 0 {
+		uc.cli.Println("No users found")
+		return
+	}
+
+	uc.cli.Println("Users:")
+	for _, user := range users {
+		status := ""
+		if user.Disabled {
+			status = " (disabled)"
+		}
+		uc.cli.Printf("  %s%s - roles: %s, policies: %s\n",
+			user.Username, status, joinOrNone(user.Roles), joinOrNone(user.Policies))
+	}
+}
+
+// Get prints a user.
+func (uc *UserCommands) Get(args []string) {
+	config, remaining, err := uc.cli.ParseGlobalFlags(args, "get")
+	if err == flag.ErrHelp {
+		uc.cli.Println("Usage: konsulctl user get <username> [options]")
+		return
+	}
+	uc.cli.HandleError(err, "parsing flags")
+	uc.cli.ValidateExactArgs(remaining, 1, "Usage: konsulctl user get <username>")
+
+	client := uc.cli.CreateClient(config)
+
+	user, err := client.GetUser(remaining[0])
+	uc.cli.HandleError(err, "getting user")
+
+	uc.cli.Printf("Username: %s\n", user.Username)
+	uc.cli.Printf("ID:       %s\n", user.ID)
+	uc.cli.Printf("Roles:    %s\n", joinOrNone(user.Roles))
+	uc.cli.Printf("Policies: %s\n", joinOrNone(user.Policies))
+	uc.cli.Printf("Disabled: %t\n", user.Disabled)
+	uc.cli.Printf("Created:  %s\n", user.CreatedAt.Format("2006-01-02 15:04:05"))
+	uc.cli.Printf("Updated:  %s\n", user.UpdatedAt.Format("2006-01-02 15:04:05"))
+}
+
+// Create creates a user.
+func (uc *UserCommands) Create(args []string) {
+	var password, roles, policies string
+	flagSet := flag.NewFlagSet("create", flag.ContinueOnError)
+	flagSet.SetOutput(uc.cli.Error)
+	flagSet.StringVar(&password, "password", os.Getenv("KONSUL_PASSWORD"), "Password (default: $KONSUL_PASSWORD)")
+	flagSet.StringVar(&roles, "roles", "", "Comma-separated roles")
+	flagSet.StringVar(&policies, "policies", "", "Comma-separated ACL policies")
+
+	config, remaining, err := uc.cli.ParseGlobalFlags(args, "create")
+	if err == flag.ErrHelp {
+		uc.cli.Println("Usage: konsulctl user create <username> [--password <password>] [--roles <r1,r2>] [--policies <p1,p2>] [options]")
+		return
+	}
+	uc.cli.HandleError(err, "parsing flags")
+	uc.cli.ValidateMinArgs(remaining, 1, "Usage: konsulctl user create <username> [--password <password>] [--roles <r1,r2>] [--policies <p1,p2>]")
+
+	err = flagSet.Parse(remaining[1:])
+	uc.cli.HandleError(err, "parsing create flags")
+	uc.requirePassword(password)
+
+	client := uc.cli.CreateClient(config)
+
+	user, err := client.CreateUser(remaining[0], password, splitList(roles), splitList(policies))
+	uc.cli.HandleError(err, "creating user")
+
+	uc.cli.Printf("Successfully created user: %s\n", user.Username)
+}
+
+// Update changes the roles, policies or disabled state of a user.
+func (uc *UserCommands) Update(args []string) {
+	var roles, policies string
+	var disable, enable bool
+	flagSet := flag.NewFlagSet("update", flag.ContinueOnError)
+	flagSet.SetOutput(uc.cli.Error)
+	flagSet.StringVar(&roles, "roles", "", "Comma-separated roles (replaces the current roles)")
+	flagSet.StringVar(&policies, "policies", "", "Comma-separated ACL policies (replaces the current policies)")
+	flagSet.BoolVar(&disable, "disable", false, "Disable the user")
+	flagSet.BoolVar(&enable, "enable", false, "Enable the user")
+
+	config, remaining, err := uc.cli.ParseGlobalFlags(args, "update")
+	if err == flag.ErrHelp {
+		uc.cli.Println("Usage: konsulctl user update <username> [--roles <r1,r2>] [--policies <p1,p2>] [--disable|--enable] [options]")
+		return
+	}
+	uc.cli.HandleError(err, "parsing flags")
+	uc.cli.ValidateMinArgs(remaining, 1, "Usage: konsulctl user update <username> [--roles <r1,r2>] [--policies <p1,p2>] [--disable|--enable]")
+
+	err = flagSet.Parse(remaining[1:])
+	uc.cli.HandleError(err, "parsing update flags")
+
+	if disable && enable {
+		uc.cli.Errorln("Error: --disable and --enable are mutually exclusive")
+		uc.cli.Exit(1)
+		return
+	}
+
+	// Only flags that were given are sent, so the rest is left unchanged
+	var newRoles, newPolicies []string
+	var disabled *bool
+	flagSet.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "roles":
+			newRoles = splitList(roles)
+		case "policies":
+			newPolicies = splitList(policies)
+		case "disable", "enable":
+			disabled = &disable
+		}
+	})
+	if newRoles == nil && newPolicies == nil && disabled == nil {
+		uc.cli.Errorln("Error: nothing to update (use --roles, --policies, --disable or --enable)")
+		uc.cli.Exit(1)
+		return
+	}
+
+	client := uc.cli.CreateClient(config)
+
+	user, err := client.UpdateUser(remaining[0], newRoles, newPolicies, disabled)
+	uc.cli.HandleError(err, "updating user")
+
+	uc.cli.Printf("Successfully updated user: %s\n", user.Username)
+}
+
+// Passwd sets the password of a user, which also lifts a lockout.
+func (uc *UserCommands) Passwd(args []string) {
+	var password string
+	flagSet := flag.NewFlagSet("passwd", flag.ContinueOnError)
+	flagSet.SetOutput(uc.cli.Error)
+	flagSet.StringVar(&password, "password", os.Getenv("KONSUL_PASSWORD"), "New password (default: $KONSUL_PASSWORD)")
+
+	config, remaining, err := uc.cli.ParseGlobalFlags(args, "passwd")
+	if err == flag.ErrHelp {
+		uc.cli.Println("Usage: konsulctl user passwd <username> [--password <password>] [options]")
+		return
+	}
+	uc.cli.HandleError(err, "parsing flags")
+	uc.cli.ValidateMinArgs(remaining, 1, "Usage: konsulctl user passwd <username> [--password <password>]")
+
+	err = flagSet.Parse(remaining[1:])
+	uc.cli.HandleError(err, "parsing passwd flags")
+	uc.requirePassword(password)
+
+	client := uc.cli.CreateClient(config)
+
+	err = client.SetUserPassword(remaining[0], password)
+	uc.cli.HandleError(err, "setting password")
+
+	uc.cli.Printf("Successfully changed password of user: %s\n", remaining[0])
+}
+
+// Delete deletes a user.
+func (uc *UserCommands) Delete(args []string) {
+	config, remaining, err := uc.cli.ParseGlobalFlags(args, "delete")
+	if err == flag.ErrHelp {
+		uc.cli.Println("Usage: konsulctl user delete <username> [options]")
+		return
+	}
+	uc.cli.HandleError(err, "parsing flags")
+	uc.cli.ValidateExactArgs(remaining, 1, "Usage: konsulctl user delete <username>")
+
+	client := uc.cli.CreateClient(config)
+
+	err = client.DeleteUser(remaining[0])
+	uc.cli.HandleError(err, "deleting user")
+
+	uc.cli.Printf("Successfully deleted user: %s\n", remaining[0])
+}
+
+// requirePassword exits when no password was given by flag or environment.
+func (uc *UserCommands) requirePassword(password string) {
+	if password == "" {
+		uc.cli.Errorln("Error: password required (use --password or $KONSUL_PASSWORD)")
+		uc.cli.Exit(1)
+	}
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	items := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// joinOrNone joins items with commas, or returns "none" for an empty list.
+func joinOrNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
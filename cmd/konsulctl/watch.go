@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// watchStream streams the events of a watch endpoint of the server, over
+// WebSocket or Server-Sent Events
+type watchStream struct {
+	cli      *CLI
+	config   *ClientConfig
+	path     string               // Endpoint path
+	query    url.Values           // Query of the endpoint
+	sseEvent string               // SSE event name of the watch events
+	print    func(payload []byte) // Prints a watch event
+}
+
+// newWatchFlagSet returns a flag set with the flags shared by watch
+// commands, and a function returning the client configuration, transport
+// and index to resume after once the flags are parsed
+func newWatchFlagSet(cli *CLI, name string) (*flag.FlagSet, func() (*ClientConfig, string, string)) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(cli.Error)
+	transport := fs.String("transport", "websocket", "Transport type (websocket or sse)")
+	since := fs.String("since", "", "Resume after the event with this index")
+
+	// Global flags
+	serverURL := fs.String("server", "http://localhost:8888", "Konsul server URL")
+	tlsSkipVerify := fs.Bool("tls-skip-verify", false, "Skip TLS certificate verification")
+	tlsCACert := fs.String("ca-cert", "", "Path to CA certificate file")
+	tlsClientCert := fs.String("client-cert", "", "Path to client certificate file")
+	tlsClientKey := fs.String("client-key", "", "Path to client key file")
+	tokenFlag := fs.String("token", "", "JWT token for authentication (or set KONSUL_TOKEN)")
+
+	return fs, func() (*ClientConfig, string, string) {
+		config := &ClientConfig{
+			Address: *serverURL,
+			TLS: &TLSConfig{
+				Enabled:        strings.HasPrefix(*serverURL, "https://"),
+				SkipVerify:     *tlsSkipVerify,
+				CACertFile:     *tlsCACert,
+				ClientCertFile: *tlsClientCert,
+				ClientKeyFile:  *tlsClientKey,
+			},
+		}
+		config.Token = strings.TrimSpace(*tokenFlag)
+		if config.Token == "" {
+			config.Token = strings.TrimSpace(os.Getenv("KONSUL_TOKEN"))
+		}
+		return config, *transport, *since
+	}
+}
+
+// run streams the events over transport until interrupted; label names
+// what is watched
+func (w *watchStream) run(transport, label string) {
+	switch transport {
+	case "websocket":
+		w.watchWebSocket(label)
+	case "sse":
+		w.watchSSE(label)
+	default:
+		w.cli.Errorf("Unknown transport: %s (must be websocket or sse)\n", transport)
+		w.cli.Exit(1)
+	}
+}
+
+// watchWebSocket streams the events over WebSocket
+func (w *watchStream) watchWebSocket(label string) {
+	config := w.config
+	wsURL, err := w.url(true)
+	w.cli.HandleError(err, "building WebSocket URL")
+
+	w.cli.Printf("Watching %s (WebSocket)...\n", label)
+	w.cli.Println("Press Ctrl+C to stop")
+	w.cli.Println()
+
+	// Setup signal handler for graceful shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		w.cli.Println("\nStopping watch...")
+		cancel()
+	}()
+
+	// Connect to WebSocket
+	dialer := websocket.Dialer{
+		HandshakeTimeout: 10 * time.Second,
+	}
+
+	// Add TLS configuration if needed
+	if config.TLS != nil && config.TLS.Enabled {
+		tlsClientConfig, err := buildTLSClientConfig(config.TLS)
+		w.cli.HandleError(err, "configuring TLS")
+		dialer.TLSClientConfig = tlsClientConfig
+	}
+
+	// Add authorization header
+	headers := http.Header{}
+	if config.Token != "" {
+		headers.Add("Authorization", "Bearer "+config.Token)
+	}
+
+	conn, _, err := dialer.Dial(wsURL, headers)
+	w.cli.HandleError(err, "connecting to WebSocket")
+	defer func() {
+		if err := conn.Close(); err != nil {
+			w.cli.Errorf("Error closing WebSocket: %v\n", err)
+		}
+	}()
+
+	// Read events
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			_, payload, err := conn.ReadMessage()
+			if err != nil {
+				if ctx.Err() != nil {
+					return // Context cancelled, exit gracefully
+				}
+				w.cli.Errorf("Error reading event: %v\n", err)
+				return
+			}
+
+			w.print(payload)
+		}
+	}
+}
+
+// watchSSE streams the events over Server-Sent Events
+func (w *watchStream) watchSSE(label string) {
+	config := w.config
+	httpURL, err := w.url(false)
+	w.cli.HandleError(err, "building SSE URL")
+
+	w.cli.Printf("Watching %s (SSE)...\n", label)
+	w.cli.Println("Press Ctrl+C to stop")
+	w.cli.Println()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		w.cli.Println("\nStopping watch...")
+		cancel()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, httpURL, nil)
+	w.cli.HandleError(err, "creating SSE request")
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	if config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+config.Token)
+	}
+
+	client := w.httpClient()
+	resp, err := client.Do(req)
+	w.cli.HandleError(err, "connecting to SSE endpoint")
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			w.cli.Errorf("Error closing SSE response body: %v\n", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		msg := strings.TrimSpace(string(body))
+		if msg != "" {
+			w.cli.ExitError("SSE request failed (%s): %s\n", resp.Status, msg)
+		}
+		w.cli.ExitError("SSE request failed (%s)\n", resp.Status)
+		return
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	var (
+		eventName string
+		dataBuf   strings.Builder
+	)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+				return
+			}
+			if errors.Is(err, io.EOF) {
+				w.cli.Println("\nConnection closed by server")
+				return
+			}
+			w.cli.Errorf("Error reading SSE stream: %v\n", err)
+			return
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			payload := strings.TrimSuffix(dataBuf.String(), "\n")
+			w.handleSSEEvent(eventName, payload)
+			eventName = ""
+			dataBuf.Reset()
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			eventName = strings.TrimSpace(line[len("event:"):])
+		case strings.HasPrefix(line, "data:"):
+			data := strings.TrimSpace(line[len("data:"):])
+			dataBuf.WriteString(data)
+			dataBuf.WriteByte('\n')
+		default:
+			// Ignore other SSE fields (id, retry, etc)
+		}
+	}
+}
+
+// url returns the URL of the watch endpoint, for WebSocket or SSE
+func (w *watchStream) url(webSocket bool) (string, error) {
+	u, err := url.Parse(w.config.Address)
+	if err != nil {
+		return "", fmt.Errorf("invalid server URL: %w", err)
+	}
+
+	if webSocket {
+		scheme := "ws"
+		if u.Scheme == "https" {
+			scheme = "wss"
+		}
+		u.Scheme = scheme
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + w.path
+	u.RawQuery = w.query.Encode()
+	u.Fragment = ""
+
+	return u.String(), nil
+}
+
+// httpClient returns the HTTP client of SSE watches, which never times out
+func (w *watchStream) httpClient() *http.Client {
+	config := w.config
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.TLS != nil && config.TLS.Enabled {
+		tlsClientConfig, err := buildTLSClientConfig(config.TLS)
+		w.cli.HandleError(err, "configuring TLS")
+		if tlsClientConfig != nil {
+			transport.TLSClientConfig = tlsClientConfig
+		}
+	}
+
+	return &http.Client{
+		Timeout:   0,
+		Transport: transport,
+	}
+}
+
+// buildTLSClientConfig returns the TLS configuration of watch connections
+func buildTLSClientConfig(tlsConfig *TLSConfig) (*tls.Config, error) {
+	if tlsConfig == nil || !tlsConfig.Enabled {
+		return nil, nil
+	}
+
+	clientConfig := &tls.Config{
+		InsecureSkipVerify: tlsConfig.SkipVerify,
+	}
+
+	if tlsConfig.CACertFile != "" {
+		caCert, err := os.ReadFile(tlsConfig.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA certificate: %w", err)
+		}
+
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("parsing CA certificate: invalid PEM data")
+		}
+		clientConfig.RootCAs = caCertPool
+	}
+
+	if tlsConfig.ClientCertFile != "" || tlsConfig.ClientKeyFile != "" {
+		if tlsConfig.ClientCertFile == "" || tlsConfig.ClientKeyFile == "" {
+			return nil, fmt.Errorf("both client-cert and client-key must be provided")
+		}
+
+		cert, err := tls.LoadX509KeyPair(tlsConfig.ClientCertFile, tlsConfig.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		clientConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return clientConfig, nil
+}
+
+// handleSSEEvent prints an SSE event received from the server
+func (w *watchStream) handleSSEEvent(eventName, payload string) {
+	payload = strings.TrimSpace(payload)
+	if payload == "" {
+		return
+	}
+
+	if eventName != "" && eventName != w.sseEvent {
+		w.cli.Printf("Received %s event: %s\n", eventName, payload)
+		return
+	}
+
+	w.print([]byte(payload))
+}
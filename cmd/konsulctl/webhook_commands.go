@@ -0,0 +1,212 @@
+package main
+
+import (
+	"flag"
+)
+
+// WebhookCommands handles all webhook management commands.
+type WebhookCommands struct {
+	cli *CLI
+}
+
+// NewWebhookCommands creates a new webhook commands handler.
+func NewWebhookCommands(cli *CLI) *WebhookCommands {
+	return &WebhookCommands{cli: cli}
+}
+
+// Handle routes webhook subcommands.
+func (wc *WebhookCommands) Handle(args []string) {
+	if len(args) == 0 {
+		wc.cli.Errorln("Webhook subcommand required")
+		wc.cli.Errorln("Usage: konsulctl webhook <list|get|set|delete|deliveries> [options]")
+		wc.cli.Exit(1)
+		return
+	}
+
+	subcommand := args[0]
+	subArgs := args[1:]
+
+	switch subcommand {
+	case "list":
+		wc.List(subArgs)
+	case "get":
+		wc.Get(subArgs)
+	case "set":
+		wc.Set(subArgs)
+	case "delete":
+		wc.Delete(subArgs)
+	case "deliveries":
+		wc.Deliveries(subArgs)
+	default:
+		wc.cli.Errorf("Unknown webhook subcommand: %s\n", subcommand)
+		wc.cli.Errorln("Available: list, get, set, delete, deliveries")
+		wc.cli.Exit(1)
+	}
+}
+
+// List prints all webhooks.
+func (wc *WebhookCommands) List(args []string) {
+	config, remaining, err := wc.cli.ParseGlobalFlags(args, "list")
+	if err == flag.ErrHelp {
+		wc.cli.Println("Usage: konsulctl webhook list [options]")
+		return
+	}
+	wc.cli.HandleError(err, "parsing flags")
+	wc.cli.ValidateExactArgs(remaining, 0, "Usage: konsulctl webhook list")
+
+	client := wc.cli.CreateClient(config)
+
+	hooks, err := client.ListWebhooks()
+	wc.cli.HandleError(err, "listing webhooks")
+
+	if len(hooks) == 0 {
+		wc.cli.Println("No webhooks found")
+		return
+	}
+
+	wc.cli.Println("Webhooks:")
+	for _, hook := range hooks {
+		wc.cli.Printf("  %s - %s %s -> %s\n", hook.Name, hook.Kind, hook.Pattern, hook.URL)
+	}
+}
+
+// Get prints a webhook.
+func (wc *WebhookCommands) Get(args []string) {
+	config, remaining, err := wc.cli.ParseGlobalFlags(args, "get")
+	if err == flag.ErrHelp {
+		wc.cli.Println("Usage: konsulctl webhook get <name> [options]")
+		return
+	}
+	wc.cli.HandleError(err, "parsing flags")
+	wc.cli.ValidateExactArgs(remaining, 1, "Usage: konsulctl webhook get <name>")
+
+	client := wc.cli.CreateClient(config)
+
+	hook, err := client.GetWebhook(remaining[0])
+	wc.cli.HandleError(err, "getting webhook")
+
+	wc.cli.Printf("Name:      %s\n", hook.Name)
+	wc.cli.Printf("URL:       %s\n", hook.URL)
+	wc.cli.Printf("Kind:      %s\n", hook.Kind)
+	if hook.Namespace != "" {
+		wc.cli.Printf("Namespace: %s\n", hook.Namespace)
+	}
+	wc.cli.Printf("Pattern:   %s\n", hook.Pattern)
+	wc.cli.Printf("Events:    %s\n", joinOrAll(hook.Events))
+	wc.cli.Printf("Signed:    %t\n", hook.Signed)
+	wc.cli.Printf("Policies:  %s\n", joinOrNone(hook.Policies))
+	wc.cli.Printf("Created:   %s\n", hook.CreatedAt.Format("2006-01-02 15:04:05"))
+	wc.cli.Printf("Updated:   %s\n", hook.UpdatedAt.Format("2006-01-02 15:04:05"))
+}
+
+// Set creates or replaces a webhook. Service webhooks watch the services of
+// the --namespace global option.
+func (wc *WebhookCommands) Set(args []string) {
+	var secret, kind, pattern, events string
+	var clearSecret bool
+	flagSet := flag.NewFlagSet("set", flag.ContinueOnError)
+	flagSet.SetOutput(wc.cli.Error)
+	flagSet.StringVar(&secret, "secret", "", "Secret signing the requests with HMAC-SHA256 (default: keep the current one)")
+	flagSet.BoolVar(&clearSecret, "clear-secret", false, "Remove the secret, so requests are no longer signed")
+	flagSet.StringVar(&kind, "kind", "kv", "Kind of resource watched: kv or service")
+	flagSet.StringVar(&pattern, "pattern", "", "Key or service name pattern (default: ** for kv, * for services)")
+	flagSet.StringVar(&events, "events", "", "Comma-separated event types to deliver (default: all)")
+
+	usage := "Usage: konsulctl webhook set <name> <url> [--secret <secret> | --clear-secret] [--kind <kv|service>] [--pattern <pattern>] [--events <e1,e2>]"
+	config, remaining, err := wc.cli.ParseGlobalFlags(args, "set")
+	if err == flag.ErrHelp {
+		wc.cli.Println(usage + " [options]")
+		return
+	}
+	wc.cli.HandleError(err, "parsing flags")
+	wc.cli.ValidateMinArgs(remaining, 2, usage)
+
+	err = flagSet.Parse(remaining[2:])
+	wc.cli.HandleError(err, "parsing set flags")
+	if secret != "" && clearSecret {
+		wc.cli.Errorln("--secret and --clear-secret are mutually exclusive")
+		wc.cli.Exit(1)
+	}
+
+	client := wc.cli.CreateClient(config)
+
+	hook := Webhook{
+		Name:        remaining[0],
+		URL:         remaining[1],
+		Secret:      secret,
+		ClearSecret: clearSecret,
+		Kind:        kind,
+		Pattern:     pattern,
+	}
+	if events != "" {
+		hook.Events = splitList(events)
+	}
+	if kind == "service" {
+		hook.Namespace = config.Namespace
+	}
+
+	saved, err := client.SetWebhook(hook)
+	wc.cli.HandleError(err, "setting webhook")
+
+	wc.cli.Printf("Successfully set webhook: %s (%s %s -> %s)\n", saved.Name, saved.Kind, saved.Pattern, saved.URL)
+}
+
+// Delete deletes a webhook.
+func (wc *WebhookCommands) Delete(args []string) {
+	config, remaining, err := wc.cli.ParseGlobalFlags(args, "delete")
+	if err == flag.ErrHelp {
+		wc.cli.Println("Usage: konsulctl webhook delete <name> [options]")
+		return
+	}
+	wc.cli.HandleError(err, "parsing flags")
+	wc.cli.ValidateExactArgs(remaining, 1, "Usage: konsulctl webhook delete <name>")
+
+	client := wc.cli.CreateClient(config)
+
+	err = client.DeleteWebhook(remaining[0])
+	wc.cli.HandleError(err, "deleting webhook")
+
+	wc.cli.Printf("Successfully deleted webhook: %s\n", remaining[0])
+}
+
+// Deliveries prints the pending and recent deliveries of a webhook.
+func (wc *WebhookCommands) Deliveries(args []string) {
+	config, remaining, err := wc.cli.ParseGlobalFlags(args, "deliveries")
+	if err == flag.ErrHelp {
+		wc.cli.Println("Usage: konsulctl webhook deliveries <name> [options]")
+		return
+	}
+	wc.cli.HandleError(err, "parsing flags")
+	wc.cli.ValidateExactArgs(remaining, 1, "Usage: konsulctl webhook deliveries <name>")
+
+	client := wc.cli.CreateClient(config)
+
+	deliveries, err := client.WebhookDeliveries(remaining[0])
+	wc.cli.HandleError(err, "listing webhook deliveries")
+
+	if len(deliveries) == 0 {
+		wc.cli.Println("No deliveries found")
+		return
+	}
+
+	for _, delivery := range deliveries {
+		wc.cli.Printf("%s  %-9s %s %s (index %d), attempts: %d",
+			delivery.CreatedAt.Format("2006-01-02 15:04:05"), delivery.Status,
+			delivery.Event.Type, delivery.Event.Key, delivery.Event.Index, delivery.Attempts)
+		if delivery.StatusCode != 0 {
+			wc.cli.Printf(", status: %d", delivery.StatusCode)
+		}
+		if delivery.Error != "" {
+			wc.cli.Printf(", error: %s", delivery.Error)
+		}
+		wc.cli.Println()
+	}
+}
+
+// joinOrAll joins event types, or returns "all" for none.
+func joinOrAll(items []string) string {
+	if len(items) == 0 {
+		return "all"
+	}
+	return joinOrNone(items)
+}
@@ -1,18 +1,24 @@
 package acl
 
 import (
+	"encoding/json"
 	"sync"
 	"time"
 
 	"github.com/neogan74/konsul/internal/logger"
 	"github.com/neogan74/konsul/internal/metrics"
+	"github.com/neogan74/konsul/internal/persistence"
 )
 
+// policyRecordKind is the persistence record kind of ACL policies
+const policyRecordKind = "policy"
+
 // Evaluator evaluates ACL policies for authorization
 type Evaluator struct {
 	policies map[string]*Policy
 	mu       sync.RWMutex
 	log      logger.Logger
+	engine   persistence.Engine
 }
 
 // NewEvaluator creates a new ACL evaluator
@@ -23,6 +29,66 @@ func NewEvaluator(log logger.Logger) *Evaluator {
 	}
 }
 
+// NewEvaluatorWithPersistence creates an ACL evaluator that stores policies in
+// engine and loads the policies already stored there
+func NewEvaluatorWithPersistence(engine persistence.Engine, log logger.Logger) *Evaluator {
+	e := NewEvaluator(log)
+	e.engine = engine
+	if err := e.loadFromPersistence(); err != nil {
+		log.Warn("Failed to load ACL policies from persistence", logger.Error(err))
+	}
+	return e
+}
+
+// loadFromPersistence loads the persisted policies
+func (e *Evaluator) loadFromPersistence() error {
+	records, err := e.engine.ListACL(policyRecordKind)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for name, data := range records {
+		policy, err := FromJSON(data)
+		if err != nil {
+			e.log.Warn("Failed to load ACL policy from persistence",
+				logger.String("policy", name),
+				logger.Error(err))
+			continue
+		}
+		e.policies[policy.Name] = policy
+	}
+	return nil
+}
+
+// persistPolicy writes a policy to the persistence engine, if any.
+// Callers must hold e.mu.
+func (e *Evaluator) persistPolicy(policy *Policy) {
+	if e.engine == nil {
+		return
+	}
+	data, err := json.Marshal(policy)
+	if err == nil {
+		err = e.engine.SetACL(policyRecordKind, policy.Name, data)
+	}
+	if err != nil {
+		e.log.Error("Failed to persist ACL policy", logger.String("policy", policy.Name), logger.Error(err))
+	}
+}
+
+// unpersistPolicy removes a policy from the persistence engine, if any.
+// Callers must hold e.mu.
+func (e *Evaluator) unpersistPolicy(name string) {
+	if e.engine == nil {
+		return
+	}
+	if err := e.engine.DeleteACL(policyRecordKind, name); err != nil {
+		e.log.Error("Failed to delete persisted ACL policy", logger.String("policy", name), logger.Error(err))
+	}
+}
+
 // AddPolicy adds a policy to the evaluator
 func (e *Evaluator) AddPolicy(policy *Policy) error {
 	if err := policy.Validate(); err != nil {
@@ -37,6 +103,7 @@ func (e *Evaluator) AddPolicy(policy *Policy) error {
 	}
 
 	e.policies[policy.Name] = policy
+	e.persistPolicy(policy)
 	e.log.Info("ACL policy added", logger.String("policy", policy.Name))
 	return nil
 }
@@ -55,6 +122,7 @@ func (e *Evaluator) UpdatePolicy(policy *Policy) error {
 	}
 
 	e.policies[policy.Name] = policy
+	e.persistPolicy(policy)
 	e.log.Info("ACL policy updated", logger.String("policy", policy.Name))
 	return nil
 }
@@ -69,6 +137,7 @@ func (e *Evaluator) DeletePolicy(name string) error {
 	}
 
 	delete(e.policies, name)
+	e.unpersistPolicy(name)
 	e.log.Info("ACL policy deleted", logger.String("policy", name))
 	return nil
 }
@@ -98,6 +167,41 @@ func (e *Evaluator) ListPolicies() []string {
 	return names
 }
 
+// GetAllPolicies returns a copy of all policies for Raft snapshotting
+func (e *Evaluator) GetAllPolicies() map[string]*Policy {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	result := make(map[string]*Policy, len(e.policies))
+	for name, policy := range e.policies {
+		result[name] = policy
+	}
+	return result
+}
+
+// RestorePolicies replaces all policies with the snapshot data
+func (e *Evaluator) RestorePolicies(policies map[string]*Policy) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for name := range e.policies {
+		if _, ok := policies[name]; !ok {
+			e.unpersistPolicy(name)
+		}
+	}
+
+	e.policies = make(map[string]*Policy, len(policies))
+	for name, policy := range policies {
+		// Validate compiles the rule patterns, which are not serialized
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+		e.policies[name] = policy
+		e.persistPolicy(policy)
+	}
+	return nil
+}
+
 // Evaluate checks if the given policies allow the specified capability on a resource
 // Returns true if access is allowed, false if denied
 func (e *Evaluator) Evaluate(policyNames []string, resource Resource, capability Capability) bool {
@@ -140,9 +244,9 @@ func (e *Evaluator) Evaluate(policyNames []string, resource Resource, capability
 	// Evaluate policies based on resource type
 	switch resource.Type {
 	case ResourceTypeKV:
-		allowed = e.evaluateKV(policies, resource.Path, capability)
+		allowed = e.evaluateKV(policies, resource.Namespace, resource.Path, capability)
 	case ResourceTypeService:
-		allowed = e.evaluateService(policies, resource.Path, capability)
+		allowed = e.evaluateService(policies, resource.Namespace, resource.Path, capability)
 	case ResourceTypeHealth:
 		allowed = e.evaluateHealth(policies, capability)
 	case ResourceTypeBackup:
@@ -158,15 +262,16 @@ func (e *Evaluator) Evaluate(policyNames []string, resource Resource, capability
 }
 
 // evaluateKV evaluates KV rules
-func (e *Evaluator) evaluateKV(policies []*Policy, kvPath string, capability Capability) bool {
+func (e *Evaluator) evaluateKV(policies []*Policy, ns, kvPath string, capability Capability) bool {
 	e.log.Debug("Evaluating KV access",
+		logger.String("namespace", ns),
 		logger.String("path", kvPath),
 		logger.String("capability", string(capability)),
 		logger.Int("policies", len(policies)))
 
 	for _, policy := range policies {
 		for _, rule := range policy.KV {
-			if rule.Matches(kvPath) {
+			if rule.MatchesNamespace(ns) && rule.Matches(kvPath) {
 				// Check for explicit deny
 				if rule.HasCapability(CapabilityDeny) {
 					e.log.Debug("Explicit deny found",
@@ -195,15 +300,16 @@ func (e *Evaluator) evaluateKV(policies []*Policy, kvPath string, capability Cap
 }
 
 // evaluateService evaluates service rules
-func (e *Evaluator) evaluateService(policies []*Policy, serviceName string, capability Capability) bool {
+func (e *Evaluator) evaluateService(policies []*Policy, ns, serviceName string, capability Capability) bool {
 	e.log.Debug("Evaluating service access",
+		logger.String("namespace", ns),
 		logger.String("service", serviceName),
 		logger.String("capability", string(capability)),
 		logger.Int("policies", len(policies)))
 
 	for _, policy := range policies {
 		for _, rule := range policy.Service {
-			if rule.Matches(serviceName) {
+			if rule.MatchesNamespace(ns) && rule.Matches(serviceName) {
 				// Check for explicit deny
 				if rule.HasCapability(CapabilityDeny) {
 					e.log.Debug("Explicit deny found",
@@ -230,6 +230,53 @@ func TestEvaluator_MultiplePolicies(t *testing.T) {
 	}
 }
 
+func TestEvaluator_NamespaceScoping(t *testing.T) {
+	log := logger.GetDefault()
+	eval := NewEvaluator(log)
+
+	policy := &Policy{
+		Name: "team-policy",
+		KV: []KVRule{
+			{Path: "config/*", Capabilities: []Capability{CapabilityRead}},
+			{Namespace: "team-a", Path: "**", Capabilities: []Capability{CapabilityRead, CapabilityWrite}},
+		},
+		Service: []ServiceRule{
+			{Namespace: "*", Name: "web", Capabilities: []Capability{CapabilityRead}},
+		},
+	}
+	if err := eval.AddPolicy(policy); err != nil {
+		t.Fatalf("Failed to add policy: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		resource   Resource
+		capability Capability
+		expected   bool
+	}{
+		{"unscoped rule applies to default", NewKVResource("config/db"), CapabilityRead, true},
+		{"unscoped rule applies to explicit default", NewKVResource("config/db").InNamespace(DefaultNamespace), CapabilityRead, true},
+		{"unscoped rule does not apply to other namespace", NewKVResource("config/db").InNamespace("team-b"), CapabilityRead, false},
+		{"scoped rule applies to its namespace", NewKVResource("anything/here").InNamespace("team-a"), CapabilityWrite, true},
+		{"scoped rule does not apply to default", NewKVResource("anything/here"), CapabilityWrite, false},
+		{"wildcard namespace applies everywhere", NewServiceResource("web").InNamespace("team-b"), CapabilityRead, true},
+		{"wildcard namespace applies to default", NewServiceResource("web"), CapabilityRead, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allowed := eval.Evaluate([]string{"team-policy"}, tt.resource, tt.capability); allowed != tt.expected {
+				t.Errorf("Expected %v, got %v for %+v", tt.expected, allowed, tt.resource)
+			}
+		})
+	}
+
+	bad := &Policy{Name: "bad", KV: []KVRule{{Namespace: "[", Path: "*"}}}
+	if err := bad.Validate(); err == nil {
+		t.Error("Expected malformed namespace pattern to be rejected")
+	}
+}
+
 func TestEvaluator_NoPolicies(t *testing.T) {
 	log := logger.GetDefault()
 	eval := NewEvaluator(log)
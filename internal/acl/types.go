@@ -2,6 +2,7 @@ package acl
 
 import (
 	"encoding/json"
+	"fmt"
 	"path"
 	"regexp"
 	"strings"
@@ -17,6 +18,8 @@ const (
 	CapabilityList   Capability = "list"
 	CapabilityDelete Capability = "delete"
 	CapabilityDeny   Capability = "deny"
+	// CapabilityDecrypt allows reading the plaintext of secret values
+	CapabilityDecrypt Capability = "decrypt"
 
 	// Service capabilities
 	CapabilityRegister   Capability = "register"
@@ -54,17 +57,23 @@ type Policy struct {
 	Admin       []AdminRule   `json:"admin,omitempty"`
 }
 
+// DefaultNamespace is the namespace of resources accessed without one. It
+// matches store.DefaultNamespace.
+const DefaultNamespace = "default"
+
 // KVRule defines rules for KV store access
 type KVRule struct {
-	Path         string       `json:"path"`         // Path pattern (supports * wildcard)
-	Capabilities []Capability `json:"capabilities"` // List of allowed capabilities
+	Namespace    string       `json:"namespace,omitempty"` // Namespace pattern (supports * wildcard, empty for default)
+	Path         string       `json:"path"`                // Path pattern (supports * wildcard)
+	Capabilities []Capability `json:"capabilities"`        // List of allowed capabilities
 	compiled     *regexp.Regexp
 }
 
 // ServiceRule defines rules for service access
 type ServiceRule struct {
-	Name         string       `json:"name"`         // Service name pattern (supports * wildcard)
-	Capabilities []Capability `json:"capabilities"` // List of allowed capabilities
+	Namespace    string       `json:"namespace,omitempty"` // Namespace pattern (supports * wildcard, empty for default)
+	Name         string       `json:"name"`                // Service name pattern (supports * wildcard)
+	Capabilities []Capability `json:"capabilities"`        // List of allowed capabilities
 	compiled     *regexp.Regexp
 }
 
@@ -83,6 +92,11 @@ type AdminRule struct {
 	Capabilities []Capability `json:"capabilities"` // List of allowed capabilities
 }
 
+// MatchesNamespace checks if a KV rule applies to the given namespace
+func (r *KVRule) MatchesNamespace(ns string) bool {
+	return matchNamespace(r.Namespace, ns)
+}
+
 // Matches checks if a KV rule matches the given path
 func (r *KVRule) Matches(kvPath string) bool {
 	if r.compiled == nil {
@@ -134,6 +148,11 @@ func (r *KVRule) HasCapability(cap Capability) bool {
 	return false
 }
 
+// MatchesNamespace checks if a service rule applies to the given namespace
+func (r *ServiceRule) MatchesNamespace(ns string) bool {
+	return matchNamespace(r.Namespace, ns)
+}
+
 // Matches checks if a service rule matches the given service name
 func (r *ServiceRule) Matches(serviceName string) bool {
 	if r.compiled == nil {
@@ -181,6 +200,19 @@ func (r *ServiceRule) HasCapability(cap Capability) bool {
 	return false
 }
 
+// matchNamespace checks a rule namespace pattern against a namespace. Rules
+// without a namespace only apply to the default namespace.
+func matchNamespace(pattern, ns string) bool {
+	if ns == "" {
+		ns = DefaultNamespace
+	}
+	if pattern == "" {
+		return ns == DefaultNamespace
+	}
+	matched, err := path.Match(pattern, ns)
+	return err == nil && matched
+}
+
 // HasCapability checks if the rule has a specific capability
 func (r *HealthRule) HasCapability(cap Capability) bool {
 	for _, c := range r.Capabilities {
@@ -213,8 +245,15 @@ func (r *AdminRule) HasCapability(cap Capability) bool {
 
 // Resource represents a resource being accessed
 type Resource struct {
-	Type ResourceType
-	Path string // For KV: key path, For Service: service name
+	Type      ResourceType
+	Path      string // For KV: key path, For Service: service name
+	Namespace string // For KV and Service: namespace, empty for default
+}
+
+// InNamespace returns a copy of the resource scoped to the given namespace
+func (r Resource) InNamespace(ns string) Resource {
+	r.Namespace = ns
+	return r
 }
 
 // NewKVResource creates a new KV resource
@@ -262,15 +301,29 @@ func (p *Policy) Validate() error {
 
 	// Compile all patterns
 	for i := range p.KV {
+		if err := validateNamespacePattern(p.KV[i].Namespace); err != nil {
+			return err
+		}
 		p.KV[i].Compile()
 	}
 	for i := range p.Service {
+		if err := validateNamespacePattern(p.Service[i].Namespace); err != nil {
+			return err
+		}
 		p.Service[i].Compile()
 	}
 
 	return nil
 }
 
+// validateNamespacePattern checks that a rule namespace pattern is well formed
+func validateNamespacePattern(pattern string) error {
+	if _, err := path.Match(pattern, DefaultNamespace); err != nil {
+		return fmt.Errorf("%w: bad namespace pattern %q", ErrInvalidPolicy, pattern)
+	}
+	return nil
+}
+
 // ToJSON converts the policy to JSON
 func (p *Policy) ToJSON() ([]byte, error) {
 	return json.Marshal(p)
@@ -187,7 +187,13 @@ func (a *Agent) RegisterService(svc store.Service) error {
 	defer a.mu.Unlock()
 
 	// Generate service ID if not provided
-	serviceID := fmt.Sprintf("%s:%s:%d", a.info.NodeName, svc.Name, svc.Port)
+	if svc.ID == "" {
+		svc.ID = fmt.Sprintf("%s:%s:%d", a.info.NodeName, svc.Name, svc.Port)
+	}
+	if svc.Node == "" {
+		svc.Node = a.info.NodeName
+	}
+	serviceID := svc.ID
 
 	entry := &store.ServiceEntry{
 		Service:     svc,
@@ -202,12 +208,14 @@ func (a *Agent) RegisterService(svc store.Service) error {
 	a.syncEngine.QueueServiceUpdate(ServiceUpdate{
 		Type:        UpdateTypeAdd,
 		ServiceName: svc.Name,
+		ServiceID:   serviceID,
 		Service:     &svc,
 		Entry:       entry,
 	})
 
 	a.log.Info("Service registered locally",
 		logger.String("service", svc.Name),
+		logger.String("service_id", serviceID),
 		logger.String("address", svc.Address),
 		logger.Int("port", svc.Port))
 
@@ -230,6 +238,7 @@ func (a *Agent) DeregisterService(name string) error {
 			a.syncEngine.QueueServiceUpdate(ServiceUpdate{
 				Type:        UpdateTypeDelete,
 				ServiceName: name,
+				ServiceID:   id,
 			})
 			break
 		}
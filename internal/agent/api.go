@@ -1,6 +1,8 @@
 package agent
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/neogan74/konsul/internal/healthcheck"
 	"github.com/neogan74/konsul/internal/logger"
@@ -276,7 +278,14 @@ func (api *API) handleRegisterCheck(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Service ID is required")
 	}
 
+	if err := def.Validate(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
 	if err := api.agent.healthChecker.RegisterCheck(def.ServiceID, &def); err != nil {
+		if errors.Is(err, healthcheck.ErrScriptChecksDisabled) {
+			return fiber.NewError(fiber.StatusForbidden, err.Error())
+		}
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
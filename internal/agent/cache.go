@@ -98,9 +98,7 @@ func (c *Cache) ApplyServiceUpdate(update ServiceUpdate) {
 			// Append or update the entry
 			found := false
 			for i, entry := range existing {
-				if entry.Service.Name == update.ServiceName &&
-					entry.Service.Address == update.Entry.Service.Address &&
-					entry.Service.Port == update.Entry.Service.Port {
+				if sameServiceInstance(entry.Service, update.Entry.Service) {
 					existing[i] = update.Entry
 					found = true
 					break
@@ -109,15 +107,69 @@ func (c *Cache) ApplyServiceUpdate(update ServiceUpdate) {
 
 			if !found {
 				existing = append(existing, update.Entry)
+
+				// An instance re-registered under another name moves
+				id := update.Entry.Service.StorageKey()
+				for _, name := range c.services.Keys() {
+					if name != update.ServiceName {
+						c.removeInstance(name, id)
+					}
+				}
 			}
 
 			c.services.Add(update.ServiceName, existing)
 		}
 	case UpdateTypeDelete:
-		c.services.Remove(update.ServiceName)
+		if update.ServiceID == "" {
+			c.services.Remove(update.ServiceName)
+			return
+		}
+
+		// Delta syncs only know the ID of a deregistered instance, so
+		// look for it under every service name
+		names := []string{update.ServiceName}
+		if update.ServiceName == "" {
+			names = c.services.Keys()
+		}
+		for _, name := range names {
+			c.removeInstance(name, update.ServiceID)
+		}
+	}
+}
+
+// removeInstance drops the instance stored under id (its storage key on the
+// server) from a cached service, keeping its siblings cached. Callers must
+// hold c.mu.
+func (c *Cache) removeInstance(name, id string) {
+	existing, ok := c.services.Peek(name)
+	if !ok {
+		return
+	}
+	remaining := make([]*store.ServiceEntry, 0, len(existing))
+	for _, entry := range existing {
+		if entry.Service.StorageKey() != id {
+			remaining = append(remaining, entry)
+		}
+	}
+	if len(remaining) == len(existing) {
+		return
+	}
+	if len(remaining) == 0 {
+		c.services.Remove(name)
+	} else {
+		c.services.Add(name, remaining)
 	}
 }
 
+// sameServiceInstance reports whether two services describe the same instance.
+// Instances are matched by ID when both carry one, otherwise by name and endpoint.
+func sameServiceInstance(a, b store.Service) bool {
+	if a.ID != "" && b.ID != "" {
+		return a.ID == b.ID
+	}
+	return a.Name == b.Name && a.Address == b.Address && a.Port == b.Port
+}
+
 // KV cache operations
 
 // GetKV retrieves a KV entry from cache
@@ -196,6 +248,11 @@ func (c *Cache) ApplyHealthUpdate(update HealthUpdate) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if update.Type == UpdateTypeDelete {
+		c.health.Remove(update.CheckID)
+		return
+	}
+
 	result := &HealthCheckResult{
 		Status:    update.Status,
 		Output:    update.Output,
@@ -261,6 +318,17 @@ func (c *Cache) HealthCount() int {
 	return c.health.Len()
 }
 
+// Purge removes all entries from all caches but keeps the hit and miss
+// counters, for a full sync that replaces the cache contents.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.services.Purge()
+	c.kv.Purge()
+	c.health.Purge()
+}
+
 // Clear removes all entries from all caches
 func (c *Cache) Clear() {
 	c.mu.Lock()
@@ -241,6 +241,49 @@ func TestCache_ApplyServiceUpdate(t *testing.T) {
 	}
 }
 
+func TestCache_ApplyServiceUpdateInstances(t *testing.T) {
+	cfg := CacheConfig{
+		ServiceTTL:     time.Minute,
+		KVTTL:          time.Minute,
+		HealthTTL:      time.Minute,
+		MaxEntries:     100,
+		EvictionPolicy: "lru",
+	}
+
+	cache := NewCache(cfg)
+
+	for _, id := range []string{"web-1", "web-2"} {
+		cache.ApplyServiceUpdate(ServiceUpdate{
+			Type:        UpdateTypeAdd,
+			ServiceName: "web",
+			ServiceID:   id,
+			Entry: &store.ServiceEntry{
+				Service: store.Service{ID: id, Name: "web", Address: "10.0.0.1", Port: 80},
+			},
+		})
+	}
+
+	entries, ok := cache.GetService("web")
+	if !ok || len(entries) != 2 {
+		t.Fatalf("Expected 2 cached instances, got %d", len(entries))
+	}
+
+	// Deleting one instance keeps the other cached
+	cache.ApplyServiceUpdate(ServiceUpdate{
+		Type:        UpdateTypeDelete,
+		ServiceName: "web",
+		ServiceID:   "web-1",
+	})
+
+	entries, ok = cache.GetService("web")
+	if !ok || len(entries) != 1 {
+		t.Fatalf("Expected 1 cached instance after DELETE, got %d", len(entries))
+	}
+	if entries[0].Service.ID != "web-2" {
+		t.Errorf("Expected web-2 to remain, got %s", entries[0].Service.ID)
+	}
+}
+
 func TestCache_ApplyKVUpdate(t *testing.T) {
 	cfg := CacheConfig{
 		ServiceTTL:     100 * time.Millisecond,
@@ -378,3 +421,60 @@ func TestCache_Clear(t *testing.T) {
 		t.Errorf("Expected 0 misses after clear, got %d", cache.Misses())
 	}
 }
+
+func TestCache_ApplyDeltaUpdates(t *testing.T) {
+	cfg := CacheConfig{
+		ServiceTTL:     time.Minute,
+		KVTTL:          time.Minute,
+		HealthTTL:      time.Minute,
+		MaxEntries:     100,
+		EvictionPolicy: "lru",
+	}
+
+	cache := NewCache(cfg)
+
+	cache.ApplyServiceUpdate(ServiceUpdate{
+		Type:        UpdateTypeAdd,
+		ServiceName: "web",
+		ServiceID:   "web-1",
+		Entry: &store.ServiceEntry{
+			Service: store.Service{ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80},
+		},
+	})
+
+	// Re-registering the instance under another name moves it
+	cache.ApplyServiceUpdate(ServiceUpdate{
+		Type:        UpdateTypeUpdate,
+		ServiceName: "www",
+		ServiceID:   "web-1",
+		Entry: &store.ServiceEntry{
+			Service: store.Service{ID: "web-1", Name: "www", Address: "10.0.0.1", Port: 80},
+		},
+	})
+	if _, ok := cache.GetService("web"); ok {
+		t.Error("Expected the instance to be gone from its old name")
+	}
+
+	// Delta syncs delete instances by ID only
+	cache.ApplyServiceUpdate(ServiceUpdate{Type: UpdateTypeDelete, ServiceID: "web-1"})
+	if _, ok := cache.GetService("www"); ok {
+		t.Error("Expected the instance to be deleted by ID")
+	}
+
+	cache.ApplyHealthUpdate(HealthUpdate{CheckID: "check-1", Status: HealthStatusPassing})
+	cache.ApplyHealthUpdate(HealthUpdate{Type: UpdateTypeDelete, CheckID: "check-1"})
+	if _, ok := cache.GetHealth("check-1"); ok {
+		t.Error("Expected the removed check to be deleted")
+	}
+
+	// Purge drops the entries but keeps the statistics
+	cache.SetKV("key", &store.KVEntry{Value: "v"})
+	cache.GetKV("key")
+	cache.Purge()
+	if cache.Len() != 0 {
+		t.Errorf("Expected an empty cache after Purge, got %d entries", cache.Len())
+	}
+	if cache.Hits() == 0 {
+		t.Error("Expected Purge to keep the hit counter")
+	}
+}
@@ -151,7 +151,7 @@ func (c *ServerClient) DeregisterService(ctx context.Context, name string) error
 
 // GetService retrieves service entries from the server
 func (c *ServerClient) GetService(ctx context.Context, name string) ([]*store.ServiceEntry, error) {
-	url := fmt.Sprintf("%s/services/%s", c.serverURL, name)
+	url := fmt.Sprintf("%s/services/%s?metadata=true", c.serverURL, name)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -61,6 +61,7 @@ type HealthCheckConfig struct {
 	CheckInterval        time.Duration `json:"check_interval" yaml:"check_interval"`                 // Default: 10s
 	ReportOnlyChanges    bool          `json:"report_only_changes" yaml:"report_only_changes"`       // Default: true
 	Timeout              time.Duration `json:"timeout" yaml:"timeout"`                               // Default: 5s
+	EnableScriptChecks   bool          `json:"enable_script_checks" yaml:"enable_script_checks"`     // Default: false; script checks run commands on the host
 }
 
 // SyncConfig represents sync configuration
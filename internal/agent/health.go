@@ -27,8 +27,11 @@ type HealthChecker struct {
 
 // NewHealthChecker creates a new health checker
 func NewHealthChecker(cfg HealthCheckConfig, client *ServerClient, log logger.Logger) *HealthChecker {
+	manager := healthcheck.NewManager(log)
+	manager.EnableScriptChecks(cfg.EnableScriptChecks)
+
 	return &HealthChecker{
-		manager:      healthcheck.NewManager(log),
+		manager:      manager,
 		config:       cfg,
 		serverClient: client,
 		log:          log,
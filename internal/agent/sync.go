@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -13,6 +14,7 @@ import (
 type SyncEngine struct {
 	config       SyncConfig
 	lastIndex    int64
+	indexes      SyncIndexes // Store indexes of the last sync, guarded by mu
 	pendingQueue chan ServiceUpdate
 	batchBuffer  []ServiceUpdate
 	mu           sync.Mutex
@@ -96,9 +98,13 @@ func (s *SyncEngine) performSync(ctx context.Context, client *ServerClient, cach
 	startTime := time.Now()
 
 	// Build sync request
+	s.mu.Lock()
+	indexes := s.indexes
+	s.mu.Unlock()
 	req := SyncRequest{
 		AgentID:         client.agentID,
 		LastSyncIndex:   atomic.LoadInt64(&s.lastIndex),
+		Indexes:         indexes,
 		WatchedPrefixes: watchedPrefixes,
 		FullSync:        fullSync,
 	}
@@ -114,6 +120,9 @@ func (s *SyncEngine) performSync(ctx context.Context, client *ServerClient, cach
 
 	// Update last sync index
 	atomic.StoreInt64(&s.lastIndex, resp.CurrentIndex)
+	s.mu.Lock()
+	s.indexes = resp.Indexes
+	s.mu.Unlock()
 
 	// Update metrics
 	atomic.AddUint64(&s.syncCount, 1)
@@ -124,7 +133,8 @@ func (s *SyncEngine) performSync(ctx context.Context, client *ServerClient, cach
 	duration := time.Since(startTime)
 	s.log.Debug("Sync completed",
 		logger.String("duration", duration.String()),
-		logger.String("index", string(rune(resp.CurrentIndex))),
+		logger.String("index", strconv.FormatInt(resp.CurrentIndex, 10)),
+		logger.String("full_sync", strconv.FormatBool(resp.FullSync)),
 		logger.Int("service_updates", len(resp.ServiceUpdates)),
 		logger.Int("kv_updates", len(resp.KVUpdates)),
 		logger.Int("health_updates", len(resp.HealthUpdates)))
@@ -132,8 +142,13 @@ func (s *SyncEngine) performSync(ctx context.Context, client *ServerClient, cach
 	return nil
 }
 
-// applyUpdates applies sync response updates to the cache
+// applyUpdates applies sync response updates to the cache. A full sync
+// replaces the cache contents, dropping entries deleted on the server since.
 func (s *SyncEngine) applyUpdates(cache *Cache, resp *SyncResponse) {
+	if resp.FullSync {
+		cache.Purge()
+	}
+
 	// Apply service updates
 	for _, update := range resp.ServiceUpdates {
 		cache.ApplyServiceUpdate(update)
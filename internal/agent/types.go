@@ -30,6 +30,7 @@ const (
 type ServiceUpdate struct {
 	Type        UpdateType          `json:"type"`
 	ServiceName string              `json:"service_name"`
+	ServiceID   string              `json:"service_id,omitempty"`
 	Service     *store.Service      `json:"service,omitempty"`
 	Entry       *store.ServiceEntry `json:"entry,omitempty"`
 }
@@ -41,8 +42,10 @@ type KVUpdate struct {
 	Entry *store.KVEntry `json:"entry,omitempty"`
 }
 
-// HealthUpdate represents a health check status update
+// HealthUpdate represents a health check status update. Type is only set in
+// sync responses, where UpdateTypeDelete marks a removed check.
 type HealthUpdate struct {
+	Type      UpdateType                   `json:"type,omitempty"`
 	ServiceID string                       `json:"service_id"`
 	CheckID   string                       `json:"check_id"`
 	Status    HealthStatus                 `json:"status"`
@@ -50,17 +53,34 @@ type HealthUpdate struct {
 	Check     *healthcheck.CheckDefinition `json:"check,omitempty"`
 }
 
-// SyncRequest represents a sync request from agent to server
+// SyncIndexes holds the index of each server store an agent is synced up
+// to. The KV, service and health check stores advance their indexes
+// independently.
+type SyncIndexes struct {
+	KV      uint64 `json:"kv"`
+	Service uint64 `json:"service"`
+	Health  uint64 `json:"health"`
+}
+
+// SyncRequest represents a sync request from agent to server. The server
+// returns only the changes after Indexes, unless FullSync is set or the agent
+// has not synced yet (LastSyncIndex is zero).
 type SyncRequest struct {
-	AgentID         string   `json:"agent_id"`
-	LastSyncIndex   int64    `json:"last_sync_index"`
-	WatchedPrefixes []string `json:"watched_prefixes,omitempty"`
-	FullSync        bool     `json:"full_sync"`
+	AgentID         string      `json:"agent_id"`
+	LastSyncIndex   int64       `json:"last_sync_index"`
+	Indexes         SyncIndexes `json:"indexes"`
+	WatchedPrefixes []string    `json:"watched_prefixes,omitempty"`
+	FullSync        bool        `json:"full_sync"`
 }
 
-// SyncResponse represents a sync response from server to agent
+// SyncResponse represents a sync response from server to agent. FullSync is
+// set when the response holds the complete state instead of changes, either
+// because the agent asked for it or because the server no longer has the
+// changes since the agent's indexes; the agent then replaces what it has.
 type SyncResponse struct {
 	CurrentIndex   int64           `json:"current_index"`
+	Indexes        SyncIndexes     `json:"indexes"`
+	FullSync       bool            `json:"full_sync,omitempty"`
 	ServiceUpdates []ServiceUpdate `json:"service_updates,omitempty"`
 	KVUpdates      []KVUpdate      `json:"kv_updates,omitempty"`
 	HealthUpdates  []HealthUpdate  `json:"health_updates,omitempty"`
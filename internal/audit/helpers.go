@@ -75,7 +75,11 @@ func ExtractResourceFromPath(c *fiber.Ctx, resourceType string) Resource {
 	}
 
 	// Extract namespace if present
-	if ns := c.Query("namespace"); ns != "" {
+	if ns := c.Query("ns"); ns != "" {
+		resource.Namespace = ns
+	} else if ns := c.Get("X-Konsul-Namespace"); ns != "" {
+		resource.Namespace = ns
+	} else if ns := c.Query("namespace"); ns != "" {
 		resource.Namespace = ns
 	}
 
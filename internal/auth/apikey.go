@@ -4,14 +4,19 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/neogan74/konsul/internal/persistence"
 )
 
+// apiKeyRecordKind is the persistence record kind of API keys
+const apiKeyRecordKind = "apikey"
+
 var (
 	ErrAPIKeyNotFound = errors.New("API key not found")
 	ErrAPIKeyExpired  = errors.New("API key has expired")
@@ -34,6 +39,7 @@ type APIKeyService struct {
 	keys   map[string]*APIKey // key hash -> APIKey
 	mu     sync.RWMutex
 	prefix string
+	engine persistence.Engine
 }
 
 func NewAPIKeyService(prefix string) *APIKeyService {
@@ -46,7 +52,63 @@ func NewAPIKeyService(prefix string) *APIKeyService {
 	}
 }
 
+// NewAPIKeyServiceWithPersistence creates an API key service that stores keys
+// in engine and loads the keys already stored there.
+func NewAPIKeyServiceWithPersistence(prefix string, engine persistence.Engine) (*APIKeyService, error) {
+	a := NewAPIKeyService(prefix)
+	a.engine = engine
+
+	records, err := engine.ListACL(apiKeyRecordKind)
+	if err != nil {
+		return a, fmt.Errorf("failed to load API keys: %w", err)
+	}
+	for id, data := range records {
+		var apiKey APIKey
+		if err := json.Unmarshal(data, &apiKey); err != nil {
+			return a, fmt.Errorf("failed to load API key %s: %w", id, err)
+		}
+		a.keys[apiKey.KeyHash] = &apiKey
+	}
+	return a, nil
+}
+
+// persist writes an API key to the persistence engine, if any.
+// Callers must hold a.mu.
+func (a *APIKeyService) persist(apiKey *APIKey) error {
+	if a.engine == nil {
+		return nil
+	}
+	data, err := json.Marshal(apiKey)
+	if err != nil {
+		return fmt.Errorf("failed to marshal API key: %w", err)
+	}
+	return a.engine.SetACL(apiKeyRecordKind, apiKey.ID, data)
+}
+
+// unpersist removes an API key from the persistence engine, if any.
+// Callers must hold a.mu.
+func (a *APIKeyService) unpersist(keyID string) error {
+	if a.engine == nil {
+		return nil
+	}
+	return a.engine.DeleteACL(apiKeyRecordKind, keyID)
+}
+
 func (a *APIKeyService) GenerateAPIKey(name string, permissions []string, metadata map[string]string, expiresAt *time.Time) (string, *APIKey, error) {
+	keyString, apiKey, err := a.NewAPIKey(name, permissions, metadata, expiresAt)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := a.AddAPIKey(apiKey); err != nil {
+		return "", nil, err
+	}
+	return keyString, apiKey, nil
+}
+
+// NewAPIKey generates a key and its record without storing it. The record is
+// stored with AddAPIKey, which lets a Raft leader generate the key once and
+// replicate only its hash.
+func (a *APIKeyService) NewAPIKey(name string, permissions []string, metadata map[string]string, expiresAt *time.Time) (string, *APIKey, error) {
 	if name == "" {
 		return "", nil, errors.New("API key name cannot be empty")
 	}
@@ -76,11 +138,16 @@ func (a *APIKeyService) GenerateAPIKey(name string, permissions []string, metada
 		Enabled:     true,
 	}
 
+	return keyString, apiKey, nil
+}
+
+// AddAPIKey stores an API key record created by NewAPIKey.
+func (a *APIKeyService) AddAPIKey(apiKey *APIKey) error {
 	a.mu.Lock()
-	a.keys[keyHash] = apiKey
-	a.mu.Unlock()
+	defer a.mu.Unlock()
 
-	return keyString, apiKey, nil
+	a.keys[apiKey.KeyHash] = apiKey
+	return a.persist(apiKey)
 }
 
 func (a *APIKeyService) ValidateAPIKey(keyString string) (*APIKey, error) {
@@ -125,7 +192,7 @@ func (a *APIKeyService) RevokeAPIKey(keyID string) error {
 	for _, apiKey := range a.keys {
 		if apiKey.ID == keyID {
 			apiKey.Enabled = false
-			return nil
+			return a.persist(apiKey)
 		}
 	}
 
@@ -139,7 +206,7 @@ func (a *APIKeyService) DeleteAPIKey(keyID string) error {
 	for keyHash, apiKey := range a.keys {
 		if apiKey.ID == keyID {
 			delete(a.keys, keyHash)
-			return nil
+			return a.unpersist(keyID)
 		}
 	}
 
@@ -211,13 +278,50 @@ func (a *APIKeyService) UpdateAPIKey(keyID string, name string, permissions []st
 			if enabled != nil {
 				apiKey.Enabled = *enabled
 			}
-			return nil
+			return a.persist(apiKey)
 		}
 	}
 
 	return ErrAPIKeyNotFound
 }
 
+// GetAllAPIKeys returns a copy of all API keys, including their hashes, keyed
+// by ID for Raft snapshotting.
+func (a *APIKeyService) GetAllAPIKeys() map[string]APIKey {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	result := make(map[string]APIKey, len(a.keys))
+	for _, apiKey := range a.keys {
+		result[apiKey.ID] = *apiKey
+	}
+	return result
+}
+
+// RestoreAPIKeys replaces all API keys with the snapshot data.
+func (a *APIKeyService) RestoreAPIKeys(keys map[string]APIKey) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, apiKey := range a.keys {
+		if _, ok := keys[apiKey.ID]; !ok {
+			if err := a.unpersist(apiKey.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	a.keys = make(map[string]*APIKey, len(keys))
+	for _, apiKey := range keys {
+		apiKey := apiKey
+		a.keys[apiKey.KeyHash] = &apiKey
+		if err := a.persist(&apiKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (a *APIKeyService) HasPermission(apiKey *APIKey, permission string) bool {
 	if apiKey == nil {
 		return false
@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/neogan74/konsul/internal/persistence"
 )
 
 func TestNewAPIKeyService(t *testing.T) {
@@ -447,3 +449,48 @@ func timePtr(t time.Time) *time.Time {
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+func TestAPIKeyService_Persistence(t *testing.T) {
+	engine := persistence.NewMemoryEngine()
+
+	service, err := NewAPIKeyServiceWithPersistence("konsul", engine)
+	if err != nil {
+		t.Fatalf("NewAPIKeyServiceWithPersistence() error = %v", err)
+	}
+	keyString, apiKey, err := service.GenerateAPIKey("ci", []string{"kv:read"}, nil, nil)
+	if err != nil {
+		t.Fatalf("GenerateAPIKey() error = %v", err)
+	}
+	revoked, _, err := service.GenerateAPIKey("old", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("GenerateAPIKey() error = %v", err)
+	}
+	revokedKey, _ := service.ValidateAPIKey(revoked)
+	if err := service.RevokeAPIKey(revokedKey.ID); err != nil {
+		t.Fatalf("RevokeAPIKey() error = %v", err)
+	}
+
+	// A new service on the same engine sees the stored keys
+	reloaded, err := NewAPIKeyServiceWithPersistence("konsul", engine)
+	if err != nil {
+		t.Fatalf("NewAPIKeyServiceWithPersistence() error = %v", err)
+	}
+	validated, err := reloaded.ValidateAPIKey(keyString)
+	if err != nil {
+		t.Fatalf("ValidateAPIKey() after reload error = %v", err)
+	}
+	if validated.ID != apiKey.ID {
+		t.Errorf("ValidateAPIKey() ID = %v, want %v", validated.ID, apiKey.ID)
+	}
+	if _, err := reloaded.ValidateAPIKey(revoked); err != ErrAPIKeyDisabled {
+		t.Errorf("ValidateAPIKey() of revoked key error = %v, want %v", err, ErrAPIKeyDisabled)
+	}
+
+	if err := reloaded.DeleteAPIKey(apiKey.ID); err != nil {
+		t.Fatalf("DeleteAPIKey() error = %v", err)
+	}
+	reloaded, _ = NewAPIKeyServiceWithPersistence("konsul", engine)
+	if _, err := reloaded.ValidateAPIKey(keyString); err != ErrAPIKeyNotFound {
+		t.Errorf("ValidateAPIKey() of deleted key error = %v, want %v", err, ErrAPIKeyNotFound)
+	}
+}
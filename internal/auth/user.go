@@ -0,0 +1,406 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neogan74/konsul/internal/persistence"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// userRecordKind is the persistence record kind of users
+const userRecordKind = "user"
+
+// MinPasswordLength is the minimum length of a user password
+const MinPasswordLength = 8
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserExists         = errors.New("user already exists")
+	ErrUserDisabled       = errors.New("user is disabled")
+	ErrAccountLocked      = errors.New("account is locked")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrInvalidUsername    = errors.New("invalid username (allowed: letters, digits and . _ @ -, max 64 chars)")
+	ErrPasswordTooShort   = fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+)
+
+var usernameRegex = regexp.MustCompile(`^[a-zA-Z0-9._@-]{1,64}$`)
+
+// dummyHash is compared against when a login names an unknown user, so that
+// unknown and known users take the same time to reject.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("konsul-dummy-password"), bcrypt.DefaultCost)
+
+// User is an account that can log in with a password. Roles and policies are
+// copied into the JWTs issued to the user.
+type User struct {
+	ID           string            `json:"id"`
+	Username     string            `json:"username"`
+	PasswordHash string            `json:"password_hash,omitempty"`
+	Roles        []string          `json:"roles"`
+	Policies     []string          `json:"policies"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Disabled     bool              `json:"disabled"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// UserUpdate holds the fields of a user to change; nil fields are left alone.
+type UserUpdate struct {
+	Roles        []string          `json:"roles,omitempty"`
+	Policies     []string          `json:"policies,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Disabled     *bool             `json:"disabled,omitempty"`
+	PasswordHash string            `json:"password_hash,omitempty"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// loginFailures tracks the failed logins of a user. It is kept per node and
+// neither persisted nor replicated, like APIKey.LastUsedAt.
+type loginFailures struct {
+	count       int
+	lockedUntil time.Time
+}
+
+// UserService stores users and authenticates them by password. Accounts are
+// locked for lockoutDuration after maxFailedAttempts failed logins in a row.
+type UserService struct {
+	users             map[string]*User // username -> User
+	failures          map[string]*loginFailures
+	mu                sync.RWMutex
+	maxFailedAttempts int
+	lockoutDuration   time.Duration
+	engine            persistence.Engine
+}
+
+// NewUserService creates a user service. A maxFailedAttempts of zero
+// disables account lockout.
+func NewUserService(maxFailedAttempts int, lockoutDuration time.Duration) *UserService {
+	return &UserService{
+		users:             make(map[string]*User),
+		failures:          make(map[string]*loginFailures),
+		maxFailedAttempts: maxFailedAttempts,
+		lockoutDuration:   lockoutDuration,
+	}
+}
+
+// NewUserServiceWithPersistence creates a user service that stores users in
+// engine and loads the users already stored there.
+func NewUserServiceWithPersistence(maxFailedAttempts int, lockoutDuration time.Duration, engine persistence.Engine) (*UserService, error) {
+	s := NewUserService(maxFailedAttempts, lockoutDuration)
+	s.engine = engine
+
+	records, err := engine.ListACL(userRecordKind)
+	if err != nil {
+		return s, fmt.Errorf("failed to load users: %w", err)
+	}
+	for username, data := range records {
+		var user User
+		if err := json.Unmarshal(data, &user); err != nil {
+			return s, fmt.Errorf("failed to load user %s: %w", username, err)
+		}
+		s.users[user.Username] = &user
+	}
+	return s, nil
+}
+
+// HashPassword checks the length of password and returns its bcrypt hash.
+func HashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength {
+		return "", ErrPasswordTooShort
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// ValidateUsername checks that username is a valid username.
+func ValidateUsername(username string) error {
+	if !usernameRegex.MatchString(username) {
+		return ErrInvalidUsername
+	}
+	return nil
+}
+
+// persist writes a user to the persistence engine, if any.
+// Callers must hold s.mu.
+func (s *UserService) persist(user *User) error {
+	if s.engine == nil {
+		return nil
+	}
+	data, err := json.Marshal(user)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user: %w", err)
+	}
+	return s.engine.SetACL(userRecordKind, user.Username, data)
+}
+
+// unpersist removes a user from the persistence engine, if any.
+// Callers must hold s.mu.
+func (s *UserService) unpersist(username string) error {
+	if s.engine == nil {
+		return nil
+	}
+	return s.engine.DeleteACL(userRecordKind, username)
+}
+
+// CreateUser creates and stores a user.
+func (s *UserService) CreateUser(username, password string, roles, policies []string, metadata map[string]string) (*User, error) {
+	user, err := s.NewUser(username, password, roles, policies, metadata)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.AddUser(user); err != nil {
+		return nil, err
+	}
+	return sanitizeUser(user), nil
+}
+
+// NewUser builds a user with a hashed password without storing it. The user
+// is stored with AddUser, which lets a Raft leader hash the password once and
+// replicate only the hash.
+func (s *UserService) NewUser(username, password string, roles, policies []string, metadata map[string]string) (*User, error) {
+	if err := ValidateUsername(username); err != nil {
+		return nil, err
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if roles == nil {
+		roles = []string{}
+	}
+	if policies == nil {
+		policies = []string{}
+	}
+	return &User{
+		ID:           uuid.New().String(),
+		Username:     username,
+		PasswordHash: hash,
+		Roles:        roles,
+		Policies:     policies,
+		Metadata:     metadata,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// AddUser stores a user created by NewUser.
+func (s *UserService) AddUser(user *User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.users[user.Username]; exists {
+		return ErrUserExists
+	}
+	s.users[user.Username] = user
+	return s.persist(user)
+}
+
+// UpdateUser applies update to a user. A new password hash also clears the
+// lockout of the user.
+func (s *UserService) UpdateUser(username string, update UserUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, exists := s.users[username]
+	if !exists {
+		return ErrUserNotFound
+	}
+
+	if update.Roles != nil {
+		user.Roles = update.Roles
+	}
+	if update.Policies != nil {
+		user.Policies = update.Policies
+	}
+	if update.Metadata != nil {
+		user.Metadata = update.Metadata
+	}
+	if update.Disabled != nil {
+		user.Disabled = *update.Disabled
+	}
+	if update.PasswordHash != "" {
+		user.PasswordHash = update.PasswordHash
+		delete(s.failures, username)
+	}
+	user.UpdatedAt = update.UpdatedAt
+	if user.UpdatedAt.IsZero() {
+		user.UpdatedAt = time.Now()
+	}
+	return s.persist(user)
+}
+
+// DeleteUser removes a user.
+func (s *UserService) DeleteUser(username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.users[username]; !exists {
+		return ErrUserNotFound
+	}
+	delete(s.users, username)
+	delete(s.failures, username)
+	return s.unpersist(username)
+}
+
+// GetUser returns a user without its password hash.
+func (s *UserService) GetUser(username string) (*User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, exists := s.users[username]
+	if !exists {
+		return nil, ErrUserNotFound
+	}
+	return sanitizeUser(user), nil
+}
+
+// ListUsers returns all users ordered by username, without password hashes.
+func (s *UserService) ListUsers() []*User {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := make([]*User, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, sanitizeUser(user))
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	return users
+}
+
+// Count returns the number of users.
+func (s *UserService) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.users)
+}
+
+// Authenticate checks a username and password. Unknown users and wrong
+// passwords both return ErrInvalidCredentials; a locked account returns
+// ErrAccountLocked until its lockout expires.
+func (s *UserService) Authenticate(username, password string) (*User, error) {
+	s.mu.RLock()
+	user, exists := s.users[username]
+	var hash string
+	if exists {
+		hash = user.PasswordHash
+	}
+	s.mu.RUnlock()
+
+	if !exists {
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+	if until := s.LockedUntil(username); !until.IsZero() {
+		return nil, ErrAccountLocked
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		s.recordFailure(username)
+		return nil, ErrInvalidCredentials
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.failures, username)
+
+	// The user may have changed while the password was checked, so it is
+	// read again under the lock: UpdateUser modifies it in place
+	user, exists = s.users[username]
+	if !exists || user.PasswordHash != hash {
+		return nil, ErrInvalidCredentials
+	}
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+	return sanitizeUser(user), nil
+}
+
+// LockedUntil returns when the lockout of a user ends, or the zero time if
+// the user is not locked out.
+func (s *UserService) LockedUntil(username string) time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	f, ok := s.failures[username]
+	if !ok || time.Now().After(f.lockedUntil) {
+		return time.Time{}
+	}
+	return f.lockedUntil
+}
+
+// recordFailure counts a failed login and locks the account when it reaches
+// maxFailedAttempts.
+func (s *UserService) recordFailure(username string) {
+	if s.maxFailedAttempts <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.failures[username]
+	if !ok {
+		f = &loginFailures{}
+		s.failures[username] = f
+	}
+	f.count++
+	if f.count >= s.maxFailedAttempts {
+		f.lockedUntil = time.Now().Add(s.lockoutDuration)
+		f.count = 0
+	}
+}
+
+// GetAllUsers returns a copy of all users, including their password hashes,
+// for Raft snapshotting.
+func (s *UserService) GetAllUsers() map[string]User {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string]User, len(s.users))
+	for username, user := range s.users {
+		result[username] = *user
+	}
+	return result
+}
+
+// RestoreUsers replaces all users with the snapshot data.
+func (s *UserService) RestoreUsers(users map[string]User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for username := range s.users {
+		if _, ok := users[username]; !ok {
+			if err := s.unpersist(username); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.users = make(map[string]*User, len(users))
+	for username, user := range users {
+		user := user
+		s.users[username] = &user
+		if err := s.persist(&user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sanitizeUser returns a copy of user without its password hash.
+func sanitizeUser(user *User) *User {
+	userCopy := *user
+	userCopy.PasswordHash = ""
+	return &userCopy
+}
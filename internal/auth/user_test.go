@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neogan74/konsul/internal/persistence"
+)
+
+func TestUserService_Authenticate(t *testing.T) {
+	service := NewUserService(0, 0)
+	if _, err := service.CreateUser("alice", "alice-password", []string{"admin"}, []string{"developer"}, nil); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	user, err := service.Authenticate("alice", "alice-password")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if user.PasswordHash != "" {
+		t.Error("Authenticate() must not return the password hash")
+	}
+	if len(user.Policies) != 1 || user.Policies[0] != "developer" {
+		t.Errorf("Authenticate() policies = %v, want [developer]", user.Policies)
+	}
+
+	if _, err := service.Authenticate("alice", "wrong-password"); err != ErrInvalidCredentials {
+		t.Errorf("Authenticate() with wrong password error = %v, want %v", err, ErrInvalidCredentials)
+	}
+	if _, err := service.Authenticate("bob", "alice-password"); err != ErrInvalidCredentials {
+		t.Errorf("Authenticate() of unknown user error = %v, want %v", err, ErrInvalidCredentials)
+	}
+
+	disabled := true
+	if err := service.UpdateUser("alice", UserUpdate{Disabled: &disabled}); err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+	if _, err := service.Authenticate("alice", "alice-password"); err != ErrUserDisabled {
+		t.Errorf("Authenticate() of disabled user error = %v, want %v", err, ErrUserDisabled)
+	}
+}
+
+func TestUserService_AuthenticateDuringUpdate(t *testing.T) {
+	service := NewUserService(0, 0)
+	if _, err := service.CreateUser("alice", "alice-password", nil, nil, nil); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	// Run with -race: Authenticate must not read the user UpdateUser changes
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			disabled := i%2 == 0
+			if err := service.UpdateUser("alice", UserUpdate{Disabled: &disabled}); err != nil {
+				t.Errorf("UpdateUser() error = %v", err)
+			}
+		}
+	}()
+	for i := 0; i < 5; i++ {
+		if _, err := service.Authenticate("alice", "alice-password"); err != nil && err != ErrUserDisabled {
+			t.Errorf("Authenticate() error = %v", err)
+		}
+	}
+	<-done
+}
+
+func TestUserService_CreateUserValidation(t *testing.T) {
+	service := NewUserService(0, 0)
+
+	if _, err := service.CreateUser("bad name", "long-enough", nil, nil, nil); err != ErrInvalidUsername {
+		t.Errorf("CreateUser() with invalid username error = %v, want %v", err, ErrInvalidUsername)
+	}
+	if _, err := service.CreateUser("alice", "short", nil, nil, nil); err != ErrPasswordTooShort {
+		t.Errorf("CreateUser() with short password error = %v, want %v", err, ErrPasswordTooShort)
+	}
+	if _, err := service.CreateUser("alice", "long-enough", nil, nil, nil); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if _, err := service.CreateUser("alice", "long-enough", nil, nil, nil); err != ErrUserExists {
+		t.Errorf("CreateUser() of existing user error = %v, want %v", err, ErrUserExists)
+	}
+}
+
+func TestUserService_Lockout(t *testing.T) {
+	service := NewUserService(3, 50*time.Millisecond)
+	if _, err := service.CreateUser("alice", "alice-password", nil, nil, nil); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := service.Authenticate("alice", "wrong-password"); err != ErrInvalidCredentials {
+			t.Fatalf("attempt %d: Authenticate() error = %v, want %v", i+1, err, ErrInvalidCredentials)
+		}
+	}
+
+	// The correct password is rejected while the account is locked
+	if _, err := service.Authenticate("alice", "alice-password"); err != ErrAccountLocked {
+		t.Fatalf("Authenticate() of locked account error = %v, want %v", err, ErrAccountLocked)
+	}
+	if service.LockedUntil("alice").IsZero() {
+		t.Error("LockedUntil() is zero for a locked account")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := service.Authenticate("alice", "alice-password"); err != nil {
+		t.Errorf("Authenticate() after lockout expired error = %v", err)
+	}
+
+	// Setting a new password lifts a lockout
+	for i := 0; i < 3; i++ {
+		_, _ = service.Authenticate("alice", "wrong-password")
+	}
+	hash, err := HashPassword("new-password")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	if err := service.UpdateUser("alice", UserUpdate{PasswordHash: hash}); err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+	if _, err := service.Authenticate("alice", "new-password"); err != nil {
+		t.Errorf("Authenticate() after password change error = %v", err)
+	}
+}
+
+func TestUserService_Persistence(t *testing.T) {
+	engine := persistence.NewMemoryEngine()
+
+	service, err := NewUserServiceWithPersistence(0, 0, engine)
+	if err != nil {
+		t.Fatalf("NewUserServiceWithPersistence() error = %v", err)
+	}
+	if _, err := service.CreateUser("alice", "alice-password", []string{"admin"}, nil, nil); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if _, err := service.CreateUser("bob", "bob-password", nil, nil, nil); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if err := service.DeleteUser("bob"); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+
+	reloaded, err := NewUserServiceWithPersistence(0, 0, engine)
+	if err != nil {
+		t.Fatalf("NewUserServiceWithPersistence() error = %v", err)
+	}
+	if reloaded.Count() != 1 {
+		t.Errorf("Count() after reload = %d, want 1", reloaded.Count())
+	}
+	if _, err := reloaded.Authenticate("alice", "alice-password"); err != nil {
+		t.Errorf("Authenticate() after reload error = %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+// Package blocking implements index-based long polling for blocking queries
+package blocking
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWait is how long a blocking query waits when it does not specify a wait
+	DefaultWait = 5 * time.Minute
+	// MaxWait caps the wait a blocking query may request
+	MaxWait = 10 * time.Minute
+)
+
+// Notifier wakes blocking queries when the index they watch changes.
+// The zero value is ready to use.
+type Notifier struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+// Notify wakes every query currently waiting.
+func (n *Notifier) Notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.ch != nil {
+		close(n.ch)
+		n.ch = nil
+	}
+}
+
+// changed returns a channel that is closed on the next Notify.
+func (n *Notifier) changed() <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.ch == nil {
+		n.ch = make(chan struct{})
+	}
+	return n.ch
+}
+
+// Wait blocks until current() differs from index, wait elapses or ctx is
+// done, and returns the current index. An index of 0 never blocks. An index
+// ahead of the current one (after a snapshot restore, for example) returns
+// immediately so that the client picks up the reset.
+func (n *Notifier) Wait(ctx context.Context, index uint64, wait time.Duration, current func() uint64) uint64 {
+	if index == 0 {
+		return current()
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		// Grab the channel before comparing so a change in between is not lost
+		ch := n.changed()
+		if cur := current(); cur != index {
+			return cur
+		}
+		select {
+		case <-ch:
+		case <-timer.C:
+			return current()
+		case <-ctx.Done():
+			return current()
+		}
+	}
+}
+
+// Query holds the parameters of a blocking query.
+type Query struct {
+	Index uint64
+	Wait  time.Duration
+}
+
+// ParseQuery parses the index and wait query parameters. The wait defaults
+// to DefaultWait, is capped at MaxWait and gets up to 1/16 of random jitter
+// so that many clients watching the same index do not return in lockstep.
+func ParseQuery(index, wait string) (Query, error) {
+	var q Query
+	if index != "" {
+		parsed, err := strconv.ParseUint(index, 10, 64)
+		if err != nil {
+			return Query{}, fmt.Errorf("invalid index: %s", index)
+		}
+		q.Index = parsed
+	}
+
+	q.Wait = DefaultWait
+	if wait != "" {
+		parsed, err := time.ParseDuration(wait)
+		if err != nil || parsed <= 0 {
+			return Query{}, fmt.Errorf("invalid wait: %s", wait)
+		}
+		q.Wait = min(parsed, MaxWait)
+	}
+	q.Wait += rand.N(q.Wait/16 + 1)
+
+	return q, nil
+}
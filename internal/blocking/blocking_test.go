@@ -0,0 +1,79 @@
+package blocking
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNotifier_WaitReturnsOnChange(t *testing.T) {
+	var n Notifier
+	var index atomic.Uint64
+	index.Store(5)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		index.Store(6)
+		n.Notify()
+	}()
+
+	start := time.Now()
+	got := n.Wait(context.Background(), 5, time.Second, index.Load)
+	if got != 6 {
+		t.Errorf("expected index 6, got %d", got)
+	}
+	if time.Since(start) >= time.Second {
+		t.Error("expected wait to return before the timeout")
+	}
+}
+
+func TestNotifier_WaitNonBlocking(t *testing.T) {
+	var n Notifier
+	current := func() uint64 { return 5 }
+
+	// Index 0, an older index and an index from the future all return at once
+	for _, index := range []uint64{0, 3, 9} {
+		if got := n.Wait(context.Background(), index, time.Second, current); got != 5 {
+			t.Errorf("index %d: expected 5, got %d", index, got)
+		}
+	}
+}
+
+func TestNotifier_WaitTimeoutAndCancel(t *testing.T) {
+	var n Notifier
+	current := func() uint64 { return 5 }
+
+	if got := n.Wait(context.Background(), 5, 20*time.Millisecond, current); got != 5 {
+		t.Errorf("expected 5 after timeout, got %d", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got := n.Wait(ctx, 5, time.Second, current); got != 5 {
+		t.Errorf("expected 5 after cancel, got %d", got)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	q, err := ParseQuery("", "")
+	if err != nil || q.Index != 0 || q.Wait < DefaultWait {
+		t.Errorf("unexpected defaults: %+v, %v", q, err)
+	}
+
+	q, err = ParseQuery("42", "30s")
+	if err != nil || q.Index != 42 || q.Wait < 30*time.Second || q.Wait > 30*time.Second+30*time.Second/16 {
+		t.Errorf("unexpected query: %+v, %v", q, err)
+	}
+
+	q, _ = ParseQuery("1", "1h")
+	if q.Wait > MaxWait+MaxWait/16 {
+		t.Errorf("expected wait to be capped, got %s", q.Wait)
+	}
+
+	for _, tc := range [][2]string{{"abc", ""}, {"-1", ""}, {"1", "soon"}, {"1", "-5s"}} {
+		if _, err := ParseQuery(tc[0], tc[1]); err == nil {
+			t.Errorf("expected error for index=%q wait=%q", tc[0], tc[1])
+		}
+	}
+}
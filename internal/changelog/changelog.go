@@ -0,0 +1,103 @@
+// Package changelog keeps a bounded record of which keys of a store changed at
+// which index, so that clients holding an older index can catch up with only
+// the keys that changed since.
+package changelog
+
+import "sync"
+
+// DefaultCapacity is the number of changes a log keeps by default
+const DefaultCapacity = 4096
+
+// change records that the item under key was written or deleted at index
+type change struct {
+	index uint64
+	key   string
+}
+
+// Log is a bounded, index-ordered log of changed keys. It only records keys;
+// readers look up the current state of each key in the store itself, and a
+// key that is gone from the store was deleted. Changes must be recorded in
+// index order, which stores get by recording under their write lock.
+// The zero value is not usable; create logs with New.
+type Log struct {
+	mu      sync.RWMutex
+	entries []change // ring buffer
+	start   int      // position of the oldest entry
+	count   int
+	floor   uint64 // changes at or below floor are not in the log
+	last    uint64 // index of the newest change, or floor if there is none
+}
+
+// New creates a log that keeps the last capacity changes.
+func New(capacity int) *Log {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Log{entries: make([]change, capacity)}
+}
+
+// Record adds the change of key at index, dropping the oldest change when the
+// log is full.
+func (l *Log) Record(index uint64, key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.count == len(l.entries) {
+		l.floor = l.entries[l.start].index
+		l.start = (l.start + 1) % len(l.entries)
+		l.count--
+	}
+	l.entries[(l.start+l.count)%len(l.entries)] = change{index: index, key: key}
+	l.count++
+	l.last = index
+}
+
+// Reset empties the log after the store was replaced wholesale, for example
+// by a snapshot restore. Changes up to index are unknown from then on.
+func (l *Log) Reset(index uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.start = 0
+	l.count = 0
+	l.floor = index
+	l.last = index
+}
+
+// Since returns each key that changed after index once, in the order of its
+// latest change, and the index the keys bring a reader up to. ok is false
+// when the log cannot tell what changed: index is older than the oldest
+// change kept, or ahead of the log because the store was reset. The reader
+// then has to load the full state instead.
+func (l *Log) Since(index uint64) (keys []string, last uint64, ok bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if index < l.floor || index > l.last {
+		return nil, l.last, false
+	}
+
+	// Walk back from the newest change so each key is taken at its latest
+	seen := make(map[string]bool)
+	for i := l.count - 1; i >= 0; i-- {
+		c := l.entries[(l.start+i)%len(l.entries)]
+		if c.index <= index {
+			break
+		}
+		if !seen[c.key] {
+			seen[c.key] = true
+			keys = append(keys, c.key)
+		}
+	}
+	for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+		keys[i], keys[j] = keys[j], keys[i]
+	}
+	return keys, l.last, true
+}
+
+// Last returns the index of the newest change recorded.
+func (l *Log) Last() uint64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.last
+}
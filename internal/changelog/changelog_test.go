@@ -0,0 +1,81 @@
+package changelog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLog_SinceDeduplicatesKeys(t *testing.T) {
+	l := New(10)
+	l.Record(1, "a")
+	l.Record(2, "b")
+	l.Record(3, "a")
+	l.Record(4, "c")
+
+	keys, last, ok := l.Since(1)
+	if !ok {
+		t.Fatal("expected the log to cover index 1")
+	}
+	// Each key once, ordered by its latest change
+	if want := []string{"b", "a", "c"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("expected keys %v, got %v", want, keys)
+	}
+	if last != 4 {
+		t.Errorf("expected last index 4, got %d", last)
+	}
+
+	keys, last, ok = l.Since(4)
+	if !ok || len(keys) != 0 || last != 4 {
+		t.Errorf("expected no changes after the newest index, got %v %d %v", keys, last, ok)
+	}
+
+	keys, _, ok = l.Since(0)
+	if !ok || len(keys) != 3 {
+		t.Errorf("expected all keys since 0 while nothing was dropped, got %v %v", keys, ok)
+	}
+}
+
+func TestLog_Truncation(t *testing.T) {
+	l := New(3)
+	for i, key := range []string{"a", "b", "c", "d", "e"} {
+		l.Record(uint64(i+1), key)
+	}
+
+	// Changes 1 and 2 were dropped, so only readers at 2 or later are covered
+	if _, _, ok := l.Since(1); ok {
+		t.Error("expected index 1 to be no longer covered")
+	}
+	keys, last, ok := l.Since(2)
+	if !ok {
+		t.Fatal("expected index 2 to be covered")
+	}
+	if want := []string{"c", "d", "e"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("expected keys %v, got %v", want, keys)
+	}
+	if last != 5 {
+		t.Errorf("expected last index 5, got %d", last)
+	}
+}
+
+func TestLog_Reset(t *testing.T) {
+	l := New(10)
+	l.Record(1, "a")
+	l.Record(2, "b")
+	l.Reset(7)
+
+	if _, _, ok := l.Since(2); ok {
+		t.Error("expected indexes before the reset to be no longer covered")
+	}
+	if _, last, ok := l.Since(9); ok || last != 7 {
+		t.Errorf("expected an index ahead of the log to be not covered, got last %d ok %v", last, ok)
+	}
+
+	l.Record(8, "c")
+	keys, last, ok := l.Since(7)
+	if !ok || !reflect.DeepEqual(keys, []string{"c"}) || last != 8 {
+		t.Errorf("expected [c] up to 8, got %v %d %v", keys, last, ok)
+	}
+	if l.Last() != 8 {
+		t.Errorf("expected Last 8, got %d", l.Last())
+	}
+}
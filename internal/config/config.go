@@ -11,6 +11,7 @@ import (
 type Config struct {
 	Server      ServerConfig
 	Service     ServiceConfig
+	KV          KVConfig
 	Log         LogConfig
 	Persistence PersistenceConfig
 	Raft        RaftConfig
@@ -22,6 +23,7 @@ type Config struct {
 	GraphQL     GraphQLConfig
 	AdminUI     AdminUIConfig
 	Watch       WatchConfig
+	Webhook     WebhookConfig
 	Audit       AuditConfig
 }
 
@@ -44,6 +46,40 @@ type TLSConfig struct {
 type ServiceConfig struct {
 	TTL             time.Duration
 	CleanupInterval time.Duration
+	// EnableScriptChecks allows registering script checks, which run
+	// commands on the server
+	EnableScriptChecks bool
+}
+
+// KVConfig contains key-value store configuration
+type KVConfig struct {
+	ExpiryInterval time.Duration // How often keys with an expired TTL are deleted (0 = never)
+
+	// Transit key of secret values, a base64 encoded AES key of 16, 24 or
+	// 32 bytes given directly or in a file. All nodes of a cluster need the
+	// same key. Without one, secret values cannot be stored.
+	TransitKey             string
+	TransitKeyFile         string
+	PreviousTransitKey     string // Key before a rotation, still accepted for existing secrets
+	PreviousTransitKeyFile string
+}
+
+// SecretsEnabled reports whether a transit key is configured.
+func (k KVConfig) SecretsEnabled() bool {
+	return k.TransitKey != "" || k.TransitKeyFile != ""
+}
+
+func (k KVConfig) validateSecrets() error {
+	if k.TransitKey != "" && k.TransitKeyFile != "" {
+		return fmt.Errorf("transit key and transit key file are mutually exclusive")
+	}
+	if k.PreviousTransitKey != "" && k.PreviousTransitKeyFile != "" {
+		return fmt.Errorf("previous transit key and previous transit key file are mutually exclusive")
+	}
+	if !k.SecretsEnabled() && (k.PreviousTransitKey != "" || k.PreviousTransitKeyFile != "") {
+		return fmt.Errorf("previous transit key requires a transit key")
+	}
+	return nil
 }
 
 // LogConfig contains logging configuration
@@ -60,14 +96,55 @@ type PersistenceConfig struct {
 	BackupDir  string
 	SyncWrites bool
 	WALEnabled bool
+
+	// Encryption at rest (badger only). Keys are base64 encoded AES keys of
+	// 16, 24 or 32 bytes, given directly or in a file.
+	EncryptionKey             string
+	EncryptionKeyFile         string
+	PreviousEncryptionKey     string // Key before a rotation, still accepted for backups
+	PreviousEncryptionKeyFile string
+	DataKeyRotation           time.Duration
+}
+
+// EncryptionEnabled reports whether an encryption key is configured.
+func (p PersistenceConfig) EncryptionEnabled() bool {
+	return p.EncryptionKey != "" || p.EncryptionKeyFile != ""
+}
+
+func (p PersistenceConfig) validateEncryption() error {
+	if p.EncryptionKey != "" && p.EncryptionKeyFile != "" {
+		return fmt.Errorf("encryption key and encryption key file are mutually exclusive")
+	}
+	if p.PreviousEncryptionKey != "" && p.PreviousEncryptionKeyFile != "" {
+		return fmt.Errorf("previous encryption key and previous encryption key file are mutually exclusive")
+	}
+	hasPrevious := p.PreviousEncryptionKey != "" || p.PreviousEncryptionKeyFile != ""
+	if !p.EncryptionEnabled() {
+		if hasPrevious {
+			return fmt.Errorf("previous encryption key requires an encryption key")
+		}
+		return nil
+	}
+	if p.Type != "badger" {
+		return fmt.Errorf("encryption at rest requires the badger persistence type")
+	}
+	if p.DataKeyRotation <= 0 {
+		return fmt.Errorf("encryption data key rotation must be positive")
+	}
+	return nil
 }
 
 // DNSConfig contains DNS server configuration
 type DNSConfig struct {
-	Enabled bool
-	Host    string
-	Port    int
-	Domain  string
+	Enabled     bool
+	Host        string
+	Port        int
+	Domain      string
+	OnlyPassing bool // Leave instances with warning checks out of answers
+	// Recursors answer queries for names outside Domain (host or host:port)
+	Recursors       []string
+	RecursorTimeout time.Duration
+	CacheSize       int // Maximum number of recursor answers cached; 0 disables the cache
 }
 
 // RateLimitConfig contains rate limiting configuration
@@ -90,6 +167,13 @@ type AuthConfig struct {
 	APIKeyPrefix  string
 	RequireAuth   bool
 	PublicPaths   []string
+	// AdminUsername and AdminPassword create the first user when the user
+	// store is empty; without a password no user is created
+	AdminUsername    string
+	AdminPassword    string
+	AdminPolicies    []string // ACL policies of the first user
+	MaxLoginAttempts int      // failed logins before an account is locked; 0 disables lockout
+	LockoutDuration  time.Duration
 }
 
 // TracingConfig contains OpenTelemetry tracing configuration
@@ -127,6 +211,19 @@ type WatchConfig struct {
 	Enabled      bool
 	BufferSize   int // Event buffer size per watcher
 	MaxPerClient int // Max watchers per client (0 = unlimited)
+	JournalSize  int // Recent events of each kind kept for resuming watchers
+}
+
+// WebhookConfig contains outbound webhook configuration. Webhooks require
+// the watch system, and ACLs since a webhook makes the server POST to any URL.
+type WebhookConfig struct {
+	Enabled     bool
+	MaxAttempts int           // Attempts of a delivery before it fails
+	RetryBase   time.Duration // Wait before the first retry, doubled for each next one
+	RetryMax    time.Duration // Longest wait between attempts
+	Timeout     time.Duration // Timeout of a delivery request
+	Workers     int           // Deliveries POSTed at once
+	HistorySize int           // Completed deliveries kept per webhook
 }
 
 // AuditConfig contains audit logging configuration
@@ -141,23 +238,48 @@ type AuditConfig struct {
 
 // RaftConfig contains Raft clustering configuration
 type RaftConfig struct {
-	Enabled            bool
-	NodeID             string
-	BindAddr           string
-	AdvertiseAddr      string
-	DataDir            string
-	Bootstrap          bool
-	Peers              []RaftPeer
-	HeartbeatTimeout   time.Duration
-	ElectionTimeout    time.Duration
-	LeaderLeaseTimeout time.Duration
-	CommitTimeout      time.Duration
-	SnapshotInterval   time.Duration
-	SnapshotThreshold  uint64
-	SnapshotRetention  int
-	MaxAppendEntries   int
-	TrailingLogs       uint64
-	LogLevel           string
+	Enabled             bool
+	NodeID              string
+	BindAddr            string
+	AdvertiseAddr       string
+	DataDir             string
+	Bootstrap           bool
+	NonVoter            bool
+	Peers               []RaftPeer
+	HeartbeatTimeout    time.Duration
+	ElectionTimeout     time.Duration
+	LeaderLeaseTimeout  time.Duration
+	CommitTimeout       time.Duration
+	SnapshotInterval    time.Duration
+	SnapshotThreshold   uint64
+	SnapshotRetention   int
+	SnapshotCompression string
+	MaxAppendEntries    int
+	TrailingLogs        uint64
+	LogLevel            string
+	Autopilot           AutopilotConfig
+	Forwarding          ForwardingConfig
+	ServiceTTLGrace     time.Duration
+}
+
+// AutopilotConfig holds the configuration of the Raft autopilot, which adds
+// joining servers as non-voters, promotes them once stable and removes dead
+// servers
+type AutopilotConfig struct {
+	Enabled                 bool
+	CleanupDeadServers      bool
+	LastContactThreshold    time.Duration
+	MaxTrailingLogs         uint64
+	ServerStabilizationTime time.Duration
+	DeadServerThreshold     time.Duration
+}
+
+// ForwardingConfig holds the configuration of the forwarding of writes
+// received by followers to the Raft leader
+type ForwardingConfig struct {
+	Enabled     bool
+	MaxAttempts int
+	RetryWait   time.Duration
 }
 
 // RaftPeer represents a bootstrap peer (id@host:port)
@@ -182,6 +304,16 @@ func Load() (*Config, error) {
 		Service: ServiceConfig{
 			TTL:             getEnvDuration("KONSUL_SERVICE_TTL", 30*time.Second),
 			CleanupInterval: getEnvDuration("KONSUL_CLEANUP_INTERVAL", 60*time.Second),
+
+			EnableScriptChecks: getEnvBool("KONSUL_ENABLE_SCRIPT_CHECKS", false),
+		},
+		KV: KVConfig{
+			ExpiryInterval: getEnvDuration("KONSUL_KV_EXPIRY_INTERVAL", time.Second),
+
+			TransitKey:             getEnvString("KONSUL_SECRETS_TRANSIT_KEY", ""),
+			TransitKeyFile:         getEnvString("KONSUL_SECRETS_TRANSIT_KEY_FILE", ""),
+			PreviousTransitKey:     getEnvString("KONSUL_SECRETS_PREVIOUS_TRANSIT_KEY", ""),
+			PreviousTransitKeyFile: getEnvString("KONSUL_SECRETS_PREVIOUS_TRANSIT_KEY_FILE", ""),
 		},
 		Log: LogConfig{
 			Level:  getEnvString("KONSUL_LOG_LEVEL", "info"),
@@ -194,12 +326,24 @@ func Load() (*Config, error) {
 			BackupDir:  getEnvString("KONSUL_BACKUP_DIR", "./backups"),
 			SyncWrites: getEnvBool("KONSUL_SYNC_WRITES", true),
 			WALEnabled: getEnvBool("KONSUL_WAL_ENABLED", true),
+
+			EncryptionKey:             getEnvString("KONSUL_ENCRYPTION_KEY", ""),
+			EncryptionKeyFile:         getEnvString("KONSUL_ENCRYPTION_KEY_FILE", ""),
+			PreviousEncryptionKey:     getEnvString("KONSUL_ENCRYPTION_PREVIOUS_KEY", ""),
+			PreviousEncryptionKeyFile: getEnvString("KONSUL_ENCRYPTION_PREVIOUS_KEY_FILE", ""),
+			DataKeyRotation:           getEnvDuration("KONSUL_ENCRYPTION_DATA_KEY_ROTATION", 10*24*time.Hour),
 		},
 		DNS: DNSConfig{
 			Enabled: getEnvBool("KONSUL_DNS_ENABLED", true),
 			Host:    getEnvString("KONSUL_DNS_HOST", ""),
 			Port:    getEnvInt("KONSUL_DNS_PORT", 8600),
 			Domain:  getEnvString("KONSUL_DNS_DOMAIN", "consul"),
+
+			OnlyPassing: getEnvBool("KONSUL_DNS_ONLY_PASSING", false),
+
+			Recursors:       getEnvStringSlice("KONSUL_DNS_RECURSORS", nil),
+			RecursorTimeout: getEnvDuration("KONSUL_DNS_RECURSOR_TIMEOUT", 2*time.Second),
+			CacheSize:       getEnvInt("KONSUL_DNS_CACHE_SIZE", 1024),
 		},
 		RateLimit: RateLimitConfig{
 			Enabled:         getEnvBool("KONSUL_RATE_LIMIT_ENABLED", false),
@@ -210,14 +354,19 @@ func Load() (*Config, error) {
 			CleanupInterval: getEnvDuration("KONSUL_RATE_LIMIT_CLEANUP", 5*time.Minute),
 		},
 		Auth: AuthConfig{
-			Enabled:       getEnvBool("KONSUL_AUTH_ENABLED", false),
-			JWTSecret:     getEnvString("KONSUL_JWT_SECRET", ""),
-			JWTExpiry:     getEnvDuration("KONSUL_JWT_EXPIRY", 15*time.Minute),
-			RefreshExpiry: getEnvDuration("KONSUL_REFRESH_EXPIRY", 7*24*time.Hour),
-			Issuer:        getEnvString("KONSUL_JWT_ISSUER", "konsul"),
-			APIKeyPrefix:  getEnvString("KONSUL_APIKEY_PREFIX", "konsul"),
-			RequireAuth:   getEnvBool("KONSUL_REQUIRE_AUTH", false),
-			PublicPaths:   getEnvStringSlice("KONSUL_PUBLIC_PATHS", []string{"/health", "/health/live", "/health/ready", "/metrics", "/admin", "/admin/", "/admin/assets/*"}),
+			Enabled:          getEnvBool("KONSUL_AUTH_ENABLED", false),
+			JWTSecret:        getEnvString("KONSUL_JWT_SECRET", ""),
+			JWTExpiry:        getEnvDuration("KONSUL_JWT_EXPIRY", 15*time.Minute),
+			RefreshExpiry:    getEnvDuration("KONSUL_REFRESH_EXPIRY", 7*24*time.Hour),
+			Issuer:           getEnvString("KONSUL_JWT_ISSUER", "konsul"),
+			APIKeyPrefix:     getEnvString("KONSUL_APIKEY_PREFIX", "konsul"),
+			RequireAuth:      getEnvBool("KONSUL_REQUIRE_AUTH", false),
+			PublicPaths:      getEnvStringSlice("KONSUL_PUBLIC_PATHS", []string{"/health", "/health/live", "/health/ready", "/metrics", "/admin", "/admin/", "/admin/assets/*"}),
+			AdminUsername:    getEnvString("KONSUL_AUTH_ADMIN_USERNAME", "admin"),
+			AdminPassword:    getEnvString("KONSUL_AUTH_ADMIN_PASSWORD", ""),
+			AdminPolicies:    getEnvStringSlice("KONSUL_AUTH_ADMIN_POLICIES", nil),
+			MaxLoginAttempts: getEnvInt("KONSUL_AUTH_MAX_LOGIN_ATTEMPTS", 5),
+			LockoutDuration:  getEnvDuration("KONSUL_AUTH_LOCKOUT_DURATION", 15*time.Minute),
 		},
 		Tracing: TracingConfig{
 			Enabled:        getEnvBool("KONSUL_TRACING_ENABLED", false),
@@ -245,6 +394,16 @@ func Load() (*Config, error) {
 			Enabled:      getEnvBool("KONSUL_WATCH_ENABLED", true),
 			BufferSize:   getEnvInt("KONSUL_WATCH_BUFFER_SIZE", 100),
 			MaxPerClient: getEnvInt("KONSUL_WATCH_MAX_PER_CLIENT", 100),
+			JournalSize:  getEnvInt("KONSUL_WATCH_JOURNAL_SIZE", 4096),
+		},
+		Webhook: WebhookConfig{
+			Enabled:     getEnvBool("KONSUL_WEBHOOK_ENABLED", false),
+			MaxAttempts: getEnvInt("KONSUL_WEBHOOK_MAX_ATTEMPTS", 8),
+			RetryBase:   getEnvDuration("KONSUL_WEBHOOK_RETRY_BASE", time.Second),
+			RetryMax:    getEnvDuration("KONSUL_WEBHOOK_RETRY_MAX", 5*time.Minute),
+			Timeout:     getEnvDuration("KONSUL_WEBHOOK_TIMEOUT", 10*time.Second),
+			Workers:     getEnvInt("KONSUL_WEBHOOK_WORKERS", 4),
+			HistorySize: getEnvInt("KONSUL_WEBHOOK_HISTORY_SIZE", 50),
 		},
 		Audit: AuditConfig{
 			Enabled:       getEnvBool("KONSUL_AUDIT_ENABLED", false),
@@ -255,23 +414,39 @@ func Load() (*Config, error) {
 			DropPolicy:    getEnvString("KONSUL_AUDIT_DROP_POLICY", "drop"),
 		},
 		Raft: RaftConfig{
-			Enabled:            getEnvBool("KONSUL_RAFT_ENABLED", false),
-			NodeID:             getEnvString("KONSUL_RAFT_NODE_ID", ""),
-			BindAddr:           getEnvString("KONSUL_RAFT_BIND_ADDR", "0.0.0.0:7000"),
-			AdvertiseAddr:      getEnvString("KONSUL_RAFT_ADVERTISE_ADDR", ""),
-			DataDir:            getEnvString("KONSUL_RAFT_DATA_DIR", "./data/raft"),
-			Bootstrap:          getEnvBool("KONSUL_RAFT_BOOTSTRAP", false),
-			Peers:              parseRaftPeers(getEnvString("KONSUL_RAFT_PEERS", "")),
-			HeartbeatTimeout:   getEnvDuration("KONSUL_RAFT_HEARTBEAT_TIMEOUT", time.Second),
-			ElectionTimeout:    getEnvDuration("KONSUL_RAFT_ELECTION_TIMEOUT", time.Second),
-			LeaderLeaseTimeout: getEnvDuration("KONSUL_RAFT_LEADER_LEASE_TIMEOUT", 500*time.Millisecond),
-			CommitTimeout:      getEnvDuration("KONSUL_RAFT_COMMIT_TIMEOUT", 50*time.Millisecond),
-			SnapshotInterval:   getEnvDuration("KONSUL_RAFT_SNAPSHOT_INTERVAL", 120*time.Second),
-			SnapshotThreshold:  getEnvUint64("KONSUL_RAFT_SNAPSHOT_THRESHOLD", 8192),
-			SnapshotRetention:  getEnvInt("KONSUL_RAFT_SNAPSHOT_RETENTION", 2),
-			MaxAppendEntries:   getEnvInt("KONSUL_RAFT_MAX_APPEND_ENTRIES", 64),
-			TrailingLogs:       getEnvUint64("KONSUL_RAFT_TRAILING_LOGS", 10240),
-			LogLevel:           getEnvString("KONSUL_RAFT_LOG_LEVEL", "info"),
+			Enabled:             getEnvBool("KONSUL_RAFT_ENABLED", false),
+			NodeID:              getEnvString("KONSUL_RAFT_NODE_ID", ""),
+			BindAddr:            getEnvString("KONSUL_RAFT_BIND_ADDR", "0.0.0.0:7000"),
+			AdvertiseAddr:       getEnvString("KONSUL_RAFT_ADVERTISE_ADDR", ""),
+			DataDir:             getEnvString("KONSUL_RAFT_DATA_DIR", "./data/raft"),
+			Bootstrap:           getEnvBool("KONSUL_RAFT_BOOTSTRAP", false),
+			NonVoter:            getEnvBool("KONSUL_RAFT_NON_VOTER", false),
+			Peers:               parseRaftPeers(getEnvString("KONSUL_RAFT_PEERS", "")),
+			HeartbeatTimeout:    getEnvDuration("KONSUL_RAFT_HEARTBEAT_TIMEOUT", time.Second),
+			ElectionTimeout:     getEnvDuration("KONSUL_RAFT_ELECTION_TIMEOUT", time.Second),
+			LeaderLeaseTimeout:  getEnvDuration("KONSUL_RAFT_LEADER_LEASE_TIMEOUT", 500*time.Millisecond),
+			CommitTimeout:       getEnvDuration("KONSUL_RAFT_COMMIT_TIMEOUT", 50*time.Millisecond),
+			SnapshotInterval:    getEnvDuration("KONSUL_RAFT_SNAPSHOT_INTERVAL", 120*time.Second),
+			SnapshotThreshold:   getEnvUint64("KONSUL_RAFT_SNAPSHOT_THRESHOLD", 8192),
+			SnapshotRetention:   getEnvInt("KONSUL_RAFT_SNAPSHOT_RETENTION", 2),
+			SnapshotCompression: getEnvString("KONSUL_RAFT_SNAPSHOT_COMPRESSION", "zstd"),
+			MaxAppendEntries:    getEnvInt("KONSUL_RAFT_MAX_APPEND_ENTRIES", 64),
+			TrailingLogs:        getEnvUint64("KONSUL_RAFT_TRAILING_LOGS", 10240),
+			LogLevel:            getEnvString("KONSUL_RAFT_LOG_LEVEL", "info"),
+			Autopilot: AutopilotConfig{
+				Enabled:                 getEnvBool("KONSUL_RAFT_AUTOPILOT_ENABLED", true),
+				CleanupDeadServers:      getEnvBool("KONSUL_RAFT_AUTOPILOT_CLEANUP_DEAD_SERVERS", true),
+				LastContactThreshold:    getEnvDuration("KONSUL_RAFT_AUTOPILOT_LAST_CONTACT_THRESHOLD", time.Second),
+				MaxTrailingLogs:         getEnvUint64("KONSUL_RAFT_AUTOPILOT_MAX_TRAILING_LOGS", 250),
+				ServerStabilizationTime: getEnvDuration("KONSUL_RAFT_AUTOPILOT_SERVER_STABILIZATION_TIME", 10*time.Second),
+				DeadServerThreshold:     getEnvDuration("KONSUL_RAFT_AUTOPILOT_DEAD_SERVER_THRESHOLD", 5*time.Minute),
+			},
+			Forwarding: ForwardingConfig{
+				Enabled:     getEnvBool("KONSUL_RAFT_FORWARD_WRITES", true),
+				MaxAttempts: getEnvInt("KONSUL_RAFT_FORWARD_MAX_ATTEMPTS", 5),
+				RetryWait:   getEnvDuration("KONSUL_RAFT_FORWARD_RETRY_WAIT", 500*time.Millisecond),
+			},
+			ServiceTTLGrace: getEnvDuration("KONSUL_RAFT_SERVICE_TTL_GRACE", 10*time.Second),
 		},
 	}
 
@@ -308,6 +483,14 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid cleanup interval: %v (must be positive)", c.Service.CleanupInterval)
 	}
 
+	if c.KV.ExpiryInterval < 0 {
+		return fmt.Errorf("invalid KV expiry interval: %v (must not be negative)", c.KV.ExpiryInterval)
+	}
+
+	if err := c.KV.validateSecrets(); err != nil {
+		return err
+	}
+
 	validLogLevels := map[string]bool{
 		"debug": true,
 		"info":  true,
@@ -339,6 +522,10 @@ func (c *Config) Validate() error {
 		if c.Persistence.DataDir == "" {
 			return fmt.Errorf("data directory must be specified when persistence is enabled")
 		}
+
+		if err := c.Persistence.validateEncryption(); err != nil {
+			return err
+		}
 	}
 
 	// Validate raft configuration if enabled
@@ -361,6 +548,31 @@ func (c *Config) Validate() error {
 		if c.Raft.SnapshotThreshold == 0 {
 			return fmt.Errorf("raft snapshot threshold must be positive")
 		}
+		switch c.Raft.SnapshotCompression {
+		case "", "zstd", "gzip", "none":
+		default:
+			return fmt.Errorf("raft snapshot compression must be zstd, gzip or none")
+		}
+		if c.Raft.Forwarding.Enabled && (c.Raft.Forwarding.MaxAttempts <= 0 || c.Raft.Forwarding.RetryWait < 0) {
+			return fmt.Errorf("raft write forwarding needs a positive max attempts and a non-negative retry wait")
+		}
+		if c.Raft.ServiceTTLGrace < 0 {
+			return fmt.Errorf("raft service TTL grace must not be negative")
+		}
+		if c.Raft.NonVoter && c.Raft.Bootstrap {
+			return fmt.Errorf("a raft non-voter cannot bootstrap the cluster")
+		}
+		if c.Raft.Autopilot.Enabled {
+			if c.Raft.Autopilot.LastContactThreshold <= 0 || c.Raft.Autopilot.DeadServerThreshold <= 0 {
+				return fmt.Errorf("raft autopilot thresholds must be positive")
+			}
+			if c.Raft.Autopilot.ServerStabilizationTime < 0 {
+				return fmt.Errorf("raft autopilot server stabilization time must not be negative")
+			}
+			if c.Raft.Autopilot.MaxTrailingLogs == 0 {
+				return fmt.Errorf("raft autopilot max trailing logs must be positive")
+			}
+		}
 		for _, peer := range c.Raft.Peers {
 			if peer.ID == "" || peer.Address == "" {
 				return fmt.Errorf("raft peers must use id@host:port format")
@@ -389,6 +601,13 @@ func (c *Config) Validate() error {
 		if c.DNS.Domain == "" {
 			return fmt.Errorf("DNS domain must be specified when DNS is enabled")
 		}
+
+		if len(c.DNS.Recursors) > 0 && c.DNS.RecursorTimeout <= 0 {
+			return fmt.Errorf("DNS recursor timeout must be positive, got %v", c.DNS.RecursorTimeout)
+		}
+		if c.DNS.CacheSize < 0 {
+			return fmt.Errorf("DNS cache size must not be negative, got %d", c.DNS.CacheSize)
+		}
 	}
 
 	// Validate rate limit configuration if enabled
@@ -423,6 +642,14 @@ func (c *Config) Validate() error {
 		if c.Auth.Issuer == "" {
 			return fmt.Errorf("JWT issuer must be specified when auth is enabled")
 		}
+
+		if c.Auth.MaxLoginAttempts < 0 {
+			return fmt.Errorf("max login attempts must not be negative")
+		}
+
+		if c.Auth.MaxLoginAttempts > 0 && c.Auth.LockoutDuration <= 0 {
+			return fmt.Errorf("lockout duration must be positive when max login attempts is set")
+		}
 	}
 
 	// Validate ACL configuration if enabled
@@ -436,6 +663,33 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate watch configuration if enabled
+	if c.Watch.Enabled && c.Watch.JournalSize <= 0 {
+		return fmt.Errorf("watch journal size must be positive")
+	}
+
+	// Validate webhook configuration if enabled
+	if c.Webhook.Enabled {
+		if c.Webhook.MaxAttempts <= 0 {
+			return fmt.Errorf("webhook max attempts must be positive")
+		}
+		if c.Webhook.RetryBase <= 0 || c.Webhook.RetryMax < c.Webhook.RetryBase {
+			return fmt.Errorf("webhook retry base must be positive and at most the retry max")
+		}
+		if c.Webhook.Timeout <= 0 {
+			return fmt.Errorf("webhook timeout must be positive")
+		}
+		if c.Webhook.Workers <= 0 {
+			return fmt.Errorf("webhook workers must be positive")
+		}
+		if c.Webhook.HistorySize <= 0 {
+			return fmt.Errorf("webhook history size must be positive")
+		}
+		if !c.ACL.Enabled {
+			return fmt.Errorf("webhooks require ACL to be enabled")
+		}
+	}
+
 	// Validate audit logging configuration if enabled
 	if c.Audit.Enabled {
 		validSinks := map[string]bool{
@@ -27,6 +27,9 @@ func TestLoad_DefaultValues(t *testing.T) {
 	if cfg.Service.CleanupInterval != 60*time.Second {
 		t.Errorf("expected cleanup interval 60s, got %v", cfg.Service.CleanupInterval)
 	}
+	if cfg.Service.EnableScriptChecks {
+		t.Error("expected script checks disabled by default")
+	}
 	if cfg.Log.Level != "info" {
 		t.Errorf("expected log level 'info', got %q", cfg.Log.Level)
 	}
@@ -62,6 +65,7 @@ func TestLoad_EnvironmentVariables(t *testing.T) {
 	t.Setenv("KONSUL_PORT", "9999")
 	t.Setenv("KONSUL_SERVICE_TTL", "45s")
 	t.Setenv("KONSUL_CLEANUP_INTERVAL", "2m")
+	t.Setenv("KONSUL_ENABLE_SCRIPT_CHECKS", "true")
 	t.Setenv("KONSUL_LOG_LEVEL", "debug")
 	t.Setenv("KONSUL_LOG_FORMAT", "json")
 	t.Setenv("KONSUL_AUDIT_ENABLED", "true")
@@ -91,6 +95,9 @@ func TestLoad_EnvironmentVariables(t *testing.T) {
 	if cfg.Service.CleanupInterval != 2*time.Minute {
 		t.Errorf("expected cleanup interval 2m, got %v", cfg.Service.CleanupInterval)
 	}
+	if !cfg.Service.EnableScriptChecks {
+		t.Error("expected script checks enabled via env")
+	}
 	if cfg.Log.Level != "debug" {
 		t.Errorf("expected log level 'debug', got %q", cfg.Log.Level)
 	}
@@ -210,6 +217,214 @@ func TestValidate_InvalidAuditConfig(t *testing.T) {
 	}
 }
 
+func TestValidate_InvalidWebhookConfig(t *testing.T) {
+	cfg := &Config{
+		Server: ServerConfig{Port: 8080},
+		Service: ServiceConfig{
+			TTL:             30 * time.Second,
+			CleanupInterval: 60 * time.Second,
+		},
+		Log: LogConfig{Level: "info", Format: "text"},
+		Webhook: WebhookConfig{
+			Enabled:     true,
+			MaxAttempts: 8,
+			RetryBase:   time.Minute,
+			RetryMax:    time.Second,
+			Timeout:     time.Second,
+			Workers:     4,
+			HistorySize: 50,
+		},
+	}
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for a retry base above the retry max")
+	}
+}
+
+func TestValidate_WebhookRequiresACL(t *testing.T) {
+	cfg := &Config{
+		Server: ServerConfig{Port: 8080},
+		Service: ServiceConfig{
+			TTL:             30 * time.Second,
+			CleanupInterval: 60 * time.Second,
+		},
+		Log: LogConfig{Level: "info", Format: "text"},
+		Webhook: WebhookConfig{
+			Enabled:     true,
+			MaxAttempts: 8,
+			RetryBase:   time.Second,
+			RetryMax:    time.Minute,
+			Timeout:     time.Second,
+			Workers:     4,
+			HistorySize: 50,
+		},
+	}
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for webhooks without ACL")
+	}
+}
+
+func TestWebhook_DefaultValues(t *testing.T) {
+	clearEnvVars(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if cfg.Webhook.Enabled {
+		t.Error("expected webhooks disabled by default")
+	}
+	if cfg.Webhook.Workers != 4 {
+		t.Errorf("expected 4 webhook workers by default, got %d", cfg.Webhook.Workers)
+	}
+	if cfg.Webhook.HistorySize != 50 {
+		t.Errorf("expected webhook history size 50 by default, got %d", cfg.Webhook.HistorySize)
+	}
+}
+
+func TestValidate_InvalidRaftAutopilotConfig(t *testing.T) {
+	cfg := &Config{
+		Server: ServerConfig{Port: 8080},
+		Service: ServiceConfig{
+			TTL:             30 * time.Second,
+			CleanupInterval: 60 * time.Second,
+		},
+		Log: LogConfig{Level: "info", Format: "text"},
+		Raft: RaftConfig{
+			Enabled:            true,
+			NodeID:             "node-1",
+			BindAddr:           "127.0.0.1:7000",
+			DataDir:            "./data/raft",
+			HeartbeatTimeout:   time.Second,
+			ElectionTimeout:    time.Second,
+			LeaderLeaseTimeout: 500 * time.Millisecond,
+			SnapshotInterval:   time.Minute,
+			SnapshotThreshold:  8192,
+			Autopilot: AutopilotConfig{
+				Enabled:              true,
+				LastContactThreshold: time.Second,
+				MaxTrailingLogs:      250,
+			},
+		},
+	}
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for a zero dead server threshold")
+	}
+
+	cfg.Raft.Autopilot.DeadServerThreshold = time.Minute
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected a valid autopilot config, got %v", err)
+	}
+}
+
+func TestValidate_InvalidRaftForwardingConfig(t *testing.T) {
+	cfg := &Config{
+		Server: ServerConfig{Port: 8080},
+		Service: ServiceConfig{
+			TTL:             30 * time.Second,
+			CleanupInterval: 60 * time.Second,
+		},
+		Log: LogConfig{Level: "info", Format: "text"},
+		Raft: RaftConfig{
+			Enabled:            true,
+			NodeID:             "node-1",
+			BindAddr:           "127.0.0.1:7000",
+			DataDir:            "./data/raft",
+			HeartbeatTimeout:   time.Second,
+			ElectionTimeout:    time.Second,
+			LeaderLeaseTimeout: 500 * time.Millisecond,
+			SnapshotInterval:   time.Minute,
+			SnapshotThreshold:  8192,
+			Forwarding: ForwardingConfig{
+				Enabled:   true,
+				RetryWait: time.Second,
+			},
+		},
+	}
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for zero forwarding attempts")
+	}
+
+	cfg.Raft.Forwarding.MaxAttempts = 3
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected a valid forwarding config, got %v", err)
+	}
+}
+
+func TestValidate_RaftNonVoterBootstrap(t *testing.T) {
+	cfg := &Config{
+		Server: ServerConfig{Port: 8080},
+		Service: ServiceConfig{
+			TTL:             30 * time.Second,
+			CleanupInterval: 60 * time.Second,
+		},
+		Log: LogConfig{Level: "info", Format: "text"},
+		Raft: RaftConfig{
+			Enabled:            true,
+			NodeID:             "node-1",
+			BindAddr:           "127.0.0.1:7000",
+			DataDir:            "./data/raft",
+			Bootstrap:          true,
+			NonVoter:           true,
+			HeartbeatTimeout:   time.Second,
+			ElectionTimeout:    time.Second,
+			LeaderLeaseTimeout: 500 * time.Millisecond,
+			SnapshotInterval:   time.Minute,
+			SnapshotThreshold:  8192,
+		},
+	}
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for a bootstrapping non-voter")
+	}
+
+	cfg.Raft.Bootstrap = false
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected a valid non-voter config, got %v", err)
+	}
+}
+
+func TestValidate_InvalidRaftSnapshotCompression(t *testing.T) {
+	cfg := &Config{
+		Server: ServerConfig{Port: 8080},
+		Service: ServiceConfig{
+			TTL:             30 * time.Second,
+			CleanupInterval: 60 * time.Second,
+		},
+		Log: LogConfig{Level: "info", Format: "text"},
+		Raft: RaftConfig{
+			Enabled:             true,
+			NodeID:              "node-1",
+			BindAddr:            "127.0.0.1:7000",
+			DataDir:             "./data/raft",
+			HeartbeatTimeout:    time.Second,
+			ElectionTimeout:     time.Second,
+			LeaderLeaseTimeout:  500 * time.Millisecond,
+			SnapshotInterval:    time.Minute,
+			SnapshotThreshold:   8192,
+			SnapshotCompression: "lz4",
+		},
+	}
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for an unknown snapshot compression")
+	}
+
+	cfg.Raft.SnapshotCompression = "gzip"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected a valid snapshot compression, got %v", err)
+	}
+
+	cfg.Raft.ServiceTTLGrace = -time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for a negative service TTL grace")
+	}
+}
+
 func TestValidate_InvalidAuditDropPolicy(t *testing.T) {
 	cfg := &Config{
 		Server: ServerConfig{Port: 8080},
@@ -448,6 +663,54 @@ func TestValidate_DNSValidConfig(t *testing.T) {
 	}
 }
 
+func TestDNS_Recursors(t *testing.T) {
+	clearEnvVars(t)
+	defer clearEnvVars(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(cfg.DNS.Recursors) != 0 {
+		t.Errorf("expected no DNS recursors by default, got %v", cfg.DNS.Recursors)
+	}
+	if cfg.DNS.RecursorTimeout != 2*time.Second {
+		t.Errorf("expected DNS recursor timeout 2s by default, got %v", cfg.DNS.RecursorTimeout)
+	}
+	if cfg.DNS.CacheSize != 1024 {
+		t.Errorf("expected DNS cache size 1024 by default, got %d", cfg.DNS.CacheSize)
+	}
+
+	t.Setenv("KONSUL_DNS_RECURSORS", "1.1.1.1, 8.8.8.8:53")
+	t.Setenv("KONSUL_DNS_RECURSOR_TIMEOUT", "500ms")
+	t.Setenv("KONSUL_DNS_CACHE_SIZE", "0")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(cfg.DNS.Recursors) != 2 || cfg.DNS.Recursors[0] != "1.1.1.1" || cfg.DNS.Recursors[1] != "8.8.8.8:53" {
+		t.Errorf("expected DNS recursors [1.1.1.1 8.8.8.8:53], got %v", cfg.DNS.Recursors)
+	}
+	if cfg.DNS.RecursorTimeout != 500*time.Millisecond {
+		t.Errorf("expected DNS recursor timeout 500ms, got %v", cfg.DNS.RecursorTimeout)
+	}
+	if cfg.DNS.CacheSize != 0 {
+		t.Errorf("expected DNS cache size 0, got %d", cfg.DNS.CacheSize)
+	}
+
+	t.Setenv("KONSUL_DNS_RECURSOR_TIMEOUT", "0s")
+	if _, err := Load(); err == nil {
+		t.Error("expected Load() to fail validation with a zero recursor timeout")
+	}
+
+	t.Setenv("KONSUL_DNS_RECURSOR_TIMEOUT", "1s")
+	t.Setenv("KONSUL_DNS_CACHE_SIZE", "-1")
+	if _, err := Load(); err == nil {
+		t.Error("expected Load() to fail validation with a negative cache size")
+	}
+}
+
 // TLS Configuration Tests
 func TestTLS_DefaultValues(t *testing.T) {
 	clearEnvVars(t)
@@ -673,6 +936,102 @@ func TestValidate_PersistenceMissingDataDir(t *testing.T) {
 	}
 }
 
+func TestValidate_PersistenceEncryption(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(p *PersistenceConfig)
+		wantErr bool
+	}{
+		{"key", func(p *PersistenceConfig) { p.EncryptionKey = "a2V5" }, false},
+		{"key file with previous key", func(p *PersistenceConfig) {
+			p.EncryptionKeyFile = "/etc/konsul/key"
+			p.PreviousEncryptionKey = "b2xk"
+		}, false},
+		{"key and key file", func(p *PersistenceConfig) {
+			p.EncryptionKey = "a2V5"
+			p.EncryptionKeyFile = "/etc/konsul/key"
+		}, true},
+		{"previous key without key", func(p *PersistenceConfig) { p.PreviousEncryptionKey = "b2xk" }, true},
+		{"memory type", func(p *PersistenceConfig) {
+			p.Type = "memory"
+			p.EncryptionKey = "a2V5"
+		}, true},
+		{"zero data key rotation", func(p *PersistenceConfig) {
+			p.EncryptionKey = "a2V5"
+			p.DataKeyRotation = 0
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Server: ServerConfig{Port: 8080},
+				Service: ServiceConfig{
+					TTL:             30 * time.Second,
+					CleanupInterval: 60 * time.Second,
+				},
+				Log: LogConfig{Level: "info", Format: "text"},
+				Persistence: PersistenceConfig{
+					Enabled:         true,
+					Type:            "badger",
+					DataDir:         "/tmp/data",
+					DataKeyRotation: time.Hour,
+				},
+			}
+			tt.mutate(&cfg.Persistence)
+
+			err := cfg.Validate()
+			if tt.wantErr && err == nil {
+				t.Error("expected validation error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidate_KVSecrets(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(k *KVConfig)
+		wantErr bool
+	}{
+		{"none", func(k *KVConfig) {}, false},
+		{"key with previous key file", func(k *KVConfig) {
+			k.TransitKey = "a2V5"
+			k.PreviousTransitKeyFile = "/etc/konsul/transit.old"
+		}, false},
+		{"key and key file", func(k *KVConfig) {
+			k.TransitKey = "a2V5"
+			k.TransitKeyFile = "/etc/konsul/transit"
+		}, true},
+		{"previous key without key", func(k *KVConfig) { k.PreviousTransitKey = "b2xk" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Server: ServerConfig{Port: 8080},
+				Service: ServiceConfig{
+					TTL:             30 * time.Second,
+					CleanupInterval: 60 * time.Second,
+				},
+				Log: LogConfig{Level: "info", Format: "text"},
+			}
+			tt.mutate(&cfg.KV)
+
+			err := cfg.Validate()
+			if tt.wantErr && err == nil {
+				t.Error("expected validation error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
 // RateLimit Configuration Tests
 func TestRateLimit_DefaultValues(t *testing.T) {
 	clearEnvVars(t)
@@ -833,6 +1192,12 @@ func TestAuth_DefaultValues(t *testing.T) {
 	if cfg.Auth.RequireAuth {
 		t.Error("expected RequireAuth disabled by default")
 	}
+	if cfg.Auth.AdminUsername != "admin" || cfg.Auth.AdminPassword != "" {
+		t.Errorf("expected admin user 'admin' without password by default, got %q/%q", cfg.Auth.AdminUsername, cfg.Auth.AdminPassword)
+	}
+	if cfg.Auth.MaxLoginAttempts != 5 || cfg.Auth.LockoutDuration != 15*time.Minute {
+		t.Errorf("expected lockout after 5 attempts for 15m by default, got %d/%v", cfg.Auth.MaxLoginAttempts, cfg.Auth.LockoutDuration)
+	}
 
 	expectedPublicPaths := []string{
 		"/health",
@@ -986,6 +1351,40 @@ func TestValidate_AuthMissingIssuer(t *testing.T) {
 	}
 }
 
+func TestValidate_AuthLockout(t *testing.T) {
+	cfg := &Config{
+		Server: ServerConfig{Port: 8080},
+		Service: ServiceConfig{
+			TTL:             30 * time.Second,
+			CleanupInterval: 60 * time.Second,
+		},
+		Log: LogConfig{Level: "info", Format: "text"},
+		Auth: AuthConfig{
+			Enabled:          true,
+			JWTSecret:        "secret",
+			JWTExpiry:        15 * time.Minute,
+			RefreshExpiry:    7 * 24 * time.Hour,
+			Issuer:           "konsul",
+			MaxLoginAttempts: 5,
+			LockoutDuration:  0,
+		},
+	}
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for lockout without duration")
+	}
+
+	cfg.Auth.MaxLoginAttempts = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for negative max login attempts")
+	}
+
+	cfg.Auth.MaxLoginAttempts = 0
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected lockout to be optional, got %v", err)
+	}
+}
+
 // ACL Configuration Tests
 func TestACL_DefaultValues(t *testing.T) {
 	clearEnvVars(t)
@@ -1352,6 +1751,7 @@ func clearEnvVars(t *testing.T) {
 	t.Setenv("KONSUL_PORT", "")
 	t.Setenv("KONSUL_SERVICE_TTL", "")
 	t.Setenv("KONSUL_CLEANUP_INTERVAL", "")
+	t.Setenv("KONSUL_ENABLE_SCRIPT_CHECKS", "")
 	t.Setenv("KONSUL_LOG_LEVEL", "")
 	t.Setenv("KONSUL_LOG_FORMAT", "")
 	t.Setenv("KONSUL_PERSISTENCE_ENABLED", "")
@@ -1360,10 +1760,23 @@ func clearEnvVars(t *testing.T) {
 	t.Setenv("KONSUL_BACKUP_DIR", "")
 	t.Setenv("KONSUL_SYNC_WRITES", "")
 	t.Setenv("KONSUL_WAL_ENABLED", "")
+	t.Setenv("KONSUL_ENCRYPTION_KEY", "")
+	t.Setenv("KONSUL_ENCRYPTION_KEY_FILE", "")
+	t.Setenv("KONSUL_ENCRYPTION_PREVIOUS_KEY", "")
+	t.Setenv("KONSUL_ENCRYPTION_PREVIOUS_KEY_FILE", "")
+	t.Setenv("KONSUL_ENCRYPTION_DATA_KEY_ROTATION", "")
+	t.Setenv("KONSUL_SECRETS_TRANSIT_KEY", "")
+	t.Setenv("KONSUL_SECRETS_TRANSIT_KEY_FILE", "")
+	t.Setenv("KONSUL_SECRETS_PREVIOUS_TRANSIT_KEY", "")
+	t.Setenv("KONSUL_SECRETS_PREVIOUS_TRANSIT_KEY_FILE", "")
 	t.Setenv("KONSUL_DNS_ENABLED", "")
 	t.Setenv("KONSUL_DNS_HOST", "")
 	t.Setenv("KONSUL_DNS_PORT", "")
 	t.Setenv("KONSUL_DNS_DOMAIN", "")
+	t.Setenv("KONSUL_DNS_ONLY_PASSING", "")
+	t.Setenv("KONSUL_DNS_RECURSORS", "")
+	t.Setenv("KONSUL_DNS_RECURSOR_TIMEOUT", "")
+	t.Setenv("KONSUL_DNS_CACHE_SIZE", "")
 	t.Setenv("KONSUL_TLS_ENABLED", "")
 	t.Setenv("KONSUL_TLS_CERT_FILE", "")
 	t.Setenv("KONSUL_TLS_KEY_FILE", "")
@@ -1382,6 +1795,11 @@ func clearEnvVars(t *testing.T) {
 	t.Setenv("KONSUL_APIKEY_PREFIX", "")
 	t.Setenv("KONSUL_REQUIRE_AUTH", "")
 	t.Setenv("KONSUL_PUBLIC_PATHS", "")
+	t.Setenv("KONSUL_AUTH_ADMIN_USERNAME", "")
+	t.Setenv("KONSUL_AUTH_ADMIN_PASSWORD", "")
+	t.Setenv("KONSUL_AUTH_ADMIN_POLICIES", "")
+	t.Setenv("KONSUL_AUTH_MAX_LOGIN_ATTEMPTS", "")
+	t.Setenv("KONSUL_AUTH_LOCKOUT_DURATION", "")
 	t.Setenv("KONSUL_ACL_ENABLED", "")
 	t.Setenv("KONSUL_ACL_DEFAULT_POLICY", "")
 	t.Setenv("KONSUL_ACL_POLICY_DIR", "")
@@ -1399,6 +1817,9 @@ func clearEnvVars(t *testing.T) {
 	t.Setenv("KONSUL_WATCH_ENABLED", "")
 	t.Setenv("KONSUL_WATCH_BUFFER_SIZE", "")
 	t.Setenv("KONSUL_WATCH_MAX_PER_CLIENT", "")
+	t.Setenv("KONSUL_WEBHOOK_ENABLED", "")
+	t.Setenv("KONSUL_WEBHOOK_WORKERS", "")
+	t.Setenv("KONSUL_WEBHOOK_HISTORY_SIZE", "")
 	t.Setenv("KONSUL_AUDIT_ENABLED", "")
 	t.Setenv("KONSUL_AUDIT_SINK", "")
 	t.Setenv("KONSUL_AUDIT_FILE_PATH", "")
@@ -0,0 +1,207 @@
+package dns
+
+import (
+	"container/heap"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// answerCache caches the answers of recursors until their TTL runs out.
+// Negative answers are cached for the TTL of their SOA (RFC 2308). The
+// answers are also kept in a heap ordered by expiry, so that a full cache
+// evicts in O(log n).
+type answerCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[cacheKey]*cacheEntry
+	expiry  expiryHeap
+}
+
+type cacheKey struct {
+	name   string
+	qtype  uint16
+	qclass uint16
+	do     bool // DNSSEC OK: answers with and without signatures differ
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	msg     *dns.Msg
+	stored  time.Time
+	expires time.Time
+	index   int // position in the expiry heap
+}
+
+// expiryHeap implements heap.Interface, with the answer closest to expiry
+// first.
+type expiryHeap []*cacheEntry
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].expires.Before(h[j].expires) }
+
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap) Push(x any) {
+	entry := x.(*cacheEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *expiryHeap) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// newAnswerCache creates a cache of at most size answers. It returns nil,
+// which caches nothing, when size is not positive.
+func newAnswerCache(size int) *answerCache {
+	if size <= 0 {
+		return nil
+	}
+	return &answerCache{size: size, entries: make(map[cacheKey]*cacheEntry)}
+}
+
+// cacheKeyOf returns the cache key of a query; only single-question queries
+// are cached.
+func cacheKeyOf(r *dns.Msg) (cacheKey, bool) {
+	if len(r.Question) != 1 {
+		return cacheKey{}, false
+	}
+	q := r.Question[0]
+	key := cacheKey{name: strings.ToLower(q.Name), qtype: q.Qtype, qclass: q.Qclass}
+	if opt := r.IsEdns0(); opt != nil {
+		key.do = opt.Do()
+	}
+	return key, true
+}
+
+// cacheTTL returns how long an answer may be cached: the lowest TTL of its
+// records or, for a negative answer, the lower of the TTL and the minimum TTL
+// of its SOA. ok is false for answers that must not be cached.
+func cacheTTL(resp *dns.Msg) (ttl time.Duration, ok bool) {
+	if resp.Truncated || (resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError) {
+		return 0, false
+	}
+
+	if len(resp.Answer) == 0 {
+		for _, rr := range resp.Ns {
+			if soa, isSOA := rr.(*dns.SOA); isSOA {
+				seconds := min(soa.Hdr.Ttl, soa.Minttl)
+				return time.Duration(seconds) * time.Second, seconds > 0
+			}
+		}
+		return 0, false
+	}
+
+	lowest := ^uint32(0)
+	for _, section := range [][]dns.RR{resp.Answer, resp.Ns, resp.Extra} {
+		for _, rr := range section {
+			if rr.Header().Rrtype != dns.TypeOPT {
+				lowest = min(lowest, rr.Header().Ttl)
+			}
+		}
+	}
+	return time.Duration(lowest) * time.Second, lowest > 0
+}
+
+// get returns a copy of the cached answer to r, with its TTLs lowered by the
+// time spent in the cache, or nil.
+func (c *answerCache) get(r *dns.Msg) *dns.Msg {
+	if c == nil {
+		return nil
+	}
+	key, ok := cacheKeyOf(r)
+	if !ok {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	if !now.Before(entry.expires) {
+		c.remove(entry)
+		return nil
+	}
+
+	resp := entry.msg.Copy()
+	resp.Id = r.Id
+	elapsed := uint32(now.Sub(entry.stored) / time.Second)
+	for _, section := range [][]dns.RR{resp.Answer, resp.Ns, resp.Extra} {
+		for _, rr := range section {
+			if hdr := rr.Header(); hdr.Rrtype != dns.TypeOPT {
+				hdr.Ttl -= min(hdr.Ttl, elapsed)
+			}
+		}
+	}
+	return resp
+}
+
+// set caches resp as the answer to r when it may be cached.
+func (c *answerCache) set(r, resp *dns.Msg) {
+	if c == nil {
+		return
+	}
+	key, ok := cacheKeyOf(r)
+	if !ok {
+		return
+	}
+	ttl, ok := cacheTTL(resp)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if entry, exists := c.entries[key]; exists {
+		entry.msg, entry.stored, entry.expires = resp.Copy(), now, now.Add(ttl)
+		heap.Fix(&c.expiry, entry.index)
+		return
+	}
+	if len(c.entries) >= c.size {
+		c.evict(now)
+	}
+	entry := &cacheEntry{key: key, msg: resp.Copy(), stored: now, expires: now.Add(ttl)}
+	c.entries[key] = entry
+	heap.Push(&c.expiry, entry)
+}
+
+// evict removes the expired answers or, if none has expired, the answer
+// closest to expiry. Must be called with mu held.
+func (c *answerCache) evict(now time.Time) {
+	for len(c.expiry) > 0 && !now.Before(c.expiry[0].expires) {
+		c.remove(c.expiry[0])
+	}
+	if len(c.entries) >= c.size {
+		c.remove(c.expiry[0])
+	}
+}
+
+// remove removes an answer. Must be called with mu held.
+func (c *answerCache) remove(entry *cacheEntry) {
+	heap.Remove(&c.expiry, entry.index)
+	delete(c.entries, entry.key)
+}
+
+// len returns the number of cached answers.
+func (c *answerCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
@@ -0,0 +1,243 @@
+package dns
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/neogan74/konsul/internal/store"
+)
+
+const (
+	// recordTTL is the TTL of the records answered for services
+	recordTTL = 30
+	// maxUDPSize is the largest UDP response, advertised to EDNS0 clients
+	maxUDPSize = 4096
+)
+
+// addressRecord returns the record that resolves name to address: A for an
+// IPv4 address, AAAA for an IPv6 one and CNAME for a hostname.
+func addressRecord(name, address string) dns.RR {
+	hdr := dns.RR_Header{Name: name, Class: dns.ClassINET, Ttl: recordTTL}
+	ip := net.ParseIP(address)
+	switch {
+	case ip == nil:
+		hdr.Rrtype = dns.TypeCNAME
+		return &dns.CNAME{Hdr: hdr, Target: dns.Fqdn(address)}
+	case ip.To4() != nil:
+		hdr.Rrtype = dns.TypeA
+		return &dns.A{Hdr: hdr, A: ip.To4()}
+	default:
+		hdr.Rrtype = dns.TypeAAAA
+		return &dns.AAAA{Hdr: hdr, AAAA: ip}
+	}
+}
+
+// addAddresses answers an A, AAAA or ANY question with the addresses of
+// instances. Instances registered with a hostname are answered with a CNAME
+// whatever the type asked.
+func (s *Server) addAddresses(msg *dns.Msg, question dns.Question, instances []store.Service) {
+	cnames := make(map[string]bool)
+	for _, service := range instances {
+		rr := addressRecord(question.Name, service.Address)
+		switch rr := rr.(type) {
+		case *dns.CNAME:
+			if cnames[rr.Target] {
+				continue
+			}
+			cnames[rr.Target] = true
+		default:
+			if question.Qtype != dns.TypeANY && question.Qtype != rr.Header().Rrtype {
+				continue
+			}
+		}
+		msg.Answer = append(msg.Answer, rr)
+	}
+}
+
+// addSRV answers an SRV question. The target of an instance registered with
+// an IP address is <instance-id>.node.<domain>, resolved in the additional
+// section, so that each port is paired with the address of its instance;
+// the target of one registered with a hostname is the hostname.
+func (s *Server) addSRV(msg *dns.Msg, question dns.Question, instances []store.Service) {
+	for i, service := range instances {
+		target := fmt.Sprintf("%s.node.%s.", service.InstanceID(), s.domain)
+		extra := addressRecord(target, service.Address)
+		if cname, ok := extra.(*dns.CNAME); ok {
+			target = cname.Target
+			extra = nil
+		}
+
+		msg.Answer = append(msg.Answer, &dns.SRV{
+			Hdr: dns.RR_Header{
+				Name:   question.Name,
+				Rrtype: dns.TypeSRV,
+				Class:  dns.ClassINET,
+				Ttl:    recordTTL,
+			},
+			Priority: 1,
+			Weight:   uint16(100 / (i + 1)), // Simple weight distribution
+			Port:     uint16(service.Port),
+			Target:   target,
+		})
+		if extra != nil {
+			msg.Extra = append(msg.Extra, extra)
+		}
+	}
+}
+
+// addTXT answers a TXT question with the metadata of each instance that has
+// some, as key=value strings ordered by key.
+func (s *Server) addTXT(msg *dns.Msg, question dns.Question, instances []store.Service) {
+	for _, service := range instances {
+		if len(service.Meta) == 0 {
+			continue
+		}
+		keys := make([]string, 0, len(service.Meta))
+		for key := range service.Meta {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		txt := make([]string, 0, len(keys))
+		for _, key := range keys {
+			txt = append(txt, key+"="+service.Meta[key])
+		}
+		msg.Answer = append(msg.Answer, &dns.TXT{
+			Hdr: dns.RR_Header{
+				Name:   question.Name,
+				Rrtype: dns.TypeTXT,
+				Class:  dns.ClassINET,
+				Ttl:    recordTTL,
+			},
+			Txt: txt,
+		})
+	}
+}
+
+// isReverse reports whether name is a reverse lookup name.
+func isReverse(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasSuffix(name, ".in-addr.arpa.") || strings.HasSuffix(name, ".ip6.arpa.")
+}
+
+// reverseTargets returns the service names of the live instances of the
+// default namespace whose address has the reverse name name.
+func (s *Server) reverseTargets(name string) []string {
+	var targets []string
+	seen := make(map[string]bool)
+	for _, service := range s.store.ListNamespace(store.DefaultNamespace) {
+		arpa, err := dns.ReverseAddr(service.Address)
+		if err != nil || !strings.EqualFold(arpa, name) {
+			continue
+		}
+		target := fmt.Sprintf("%s.service.%s.", service.Name, s.domain)
+		if !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// handlePTRQuery answers a reverse lookup from the catalog. It reports
+// whether any instance has the address.
+func (s *Server) handlePTRQuery(msg *dns.Msg, question dns.Question) bool {
+	targets := s.reverseTargets(question.Name)
+	if question.Qtype != dns.TypePTR && question.Qtype != dns.TypeANY {
+		return len(targets) > 0
+	}
+	for _, target := range targets {
+		msg.Answer = append(msg.Answer, &dns.PTR{
+			Hdr: dns.RR_Header{
+				Name:   question.Name,
+				Rrtype: dns.TypePTR,
+				Class:  dns.ClassINET,
+				Ttl:    recordTTL,
+			},
+			Ptr: target,
+		})
+	}
+	return len(targets) > 0
+}
+
+// handleApexQuery answers SOA and NS questions for the domain itself.
+func (s *Server) handleApexQuery(msg *dns.Msg, question dns.Question) {
+	switch question.Qtype {
+	case dns.TypeSOA:
+		msg.Answer = append(msg.Answer, s.soa())
+		msg.Ns = append(msg.Ns, s.ns())
+	case dns.TypeNS:
+		msg.Answer = append(msg.Answer, s.ns())
+	case dns.TypeANY:
+		msg.Answer = append(msg.Answer, s.soa(), s.ns())
+	}
+}
+
+// soa returns the SOA record of the domain. Its TTL and minimum TTL of 0
+// keep resolvers from caching negative answers, as instances come and go.
+func (s *Server) soa() *dns.SOA {
+	zone := s.domain + "."
+	return &dns.SOA{
+		Hdr: dns.RR_Header{
+			Name:   zone,
+			Rrtype: dns.TypeSOA,
+			Class:  dns.ClassINET,
+			Ttl:    0,
+		},
+		Ns:      "ns." + zone,
+		Mbox:    "hostmaster." + zone,
+		Serial:  uint32(time.Now().Unix()),
+		Refresh: 3600,
+		Retry:   600,
+		Expire:  86400,
+		Minttl:  0,
+	}
+}
+
+// ns returns the NS record of the domain.
+func (s *Server) ns() *dns.NS {
+	zone := s.domain + "."
+	return &dns.NS{
+		Hdr: dns.RR_Header{
+			Name:   zone,
+			Rrtype: dns.TypeNS,
+			Class:  dns.ClassINET,
+			Ttl:    recordTTL,
+		},
+		Ns: "ns." + zone,
+	}
+}
+
+// responseSize returns the largest response the client of r accepts: 512
+// bytes over UDP, or the buffer size an EDNS0 client advertised.
+func responseSize(w dns.ResponseWriter, r *dns.Msg) int {
+	if _, tcp := w.RemoteAddr().(*net.TCPAddr); tcp {
+		return dns.MaxMsgSize
+	}
+	if opt := r.IsEdns0(); opt != nil {
+		return min(max(int(opt.UDPSize()), dns.MinMsgSize), maxUDPSize)
+	}
+	return dns.MinMsgSize
+}
+
+// fit sizes a response for its transport. Responses larger than
+// responseSize are truncated with the TC bit set so that the client retries
+// over TCP, and EDNS0 clients get an OPT record.
+func fit(w dns.ResponseWriter, r, msg *dns.Msg) {
+	if opt := r.IsEdns0(); opt != nil {
+		msg.SetEdns0(maxUDPSize, opt.Do())
+		if opt.Version() != 0 {
+			// Only EDNS version 0 is supported (RFC 6891)
+			msg.Answer, msg.Ns = nil, nil
+			msg.Extra = []dns.RR{msg.IsEdns0()}
+			msg.Rcode = dns.RcodeBadVers
+			return
+		}
+	}
+
+	msg.Truncate(responseSize(w, r))
+}
@@ -0,0 +1,172 @@
+package dns
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/neogan74/konsul/internal/store"
+)
+
+// exchange runs a query through the server and returns the response.
+func exchange(t *testing.T, server *Server, msg *dns.Msg, tcp bool) *dns.Msg {
+	t.Helper()
+	writer := &mockResponseWriter{tcp: tcp}
+	server.handleDNSRequest(writer, msg)
+	if writer.msg == nil {
+		t.Fatal("Expected DNS response, got nil")
+	}
+	return writer.msg
+}
+
+func question(name string, qtype uint16) *dns.Msg {
+	msg := new(dns.Msg)
+	msg.SetQuestion(name, qtype)
+	return msg
+}
+
+func TestDNSServer_AddressRecords(t *testing.T) {
+	dnsServer, serviceStore := setupTestServer()
+
+	services := []store.Service{
+		{ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80},
+		{ID: "web-2", Name: "web", Address: "2001:db8::1", Port: 80},
+		{ID: "db-1", Name: "db", Address: "db.example.com", Port: 5432},
+	}
+	for _, svc := range services {
+		if err := serviceStore.Register(svc); err != nil {
+			t.Fatalf("register service: %v", err)
+		}
+	}
+
+	resp := exchange(t, dnsServer, question("web.service.consul.", dns.TypeA), false)
+	if len(resp.Answer) != 1 || resp.Answer[0].(*dns.A).A.String() != "10.0.0.1" {
+		t.Errorf("Expected the IPv4 instance only, got %v", resp.Answer)
+	}
+
+	resp = exchange(t, dnsServer, question("web.service.consul.", dns.TypeAAAA), false)
+	if len(resp.Answer) != 1 || resp.Answer[0].(*dns.AAAA).AAAA.String() != "2001:db8::1" {
+		t.Errorf("Expected the IPv6 instance only, got %v", resp.Answer)
+	}
+
+	resp = exchange(t, dnsServer, question("db.service.consul.", dns.TypeA), false)
+	if len(resp.Answer) != 1 || resp.Answer[0].(*dns.CNAME).Target != "db.example.com." {
+		t.Errorf("Expected a CNAME for the hostname instance, got %v", resp.Answer)
+	}
+
+	// SRV targets: node names resolved in the additional section, or the hostname
+	resp = exchange(t, dnsServer, question("_web._tcp.service.consul.", dns.TypeSRV), false)
+	if len(resp.Answer) != 2 || len(resp.Extra) != 2 {
+		t.Fatalf("Expected 2 SRV records with 2 additional records, got %v / %v", resp.Answer, resp.Extra)
+	}
+	if _, ok := resp.Extra[1].(*dns.AAAA); !ok {
+		t.Errorf("Expected an AAAA additional record for the IPv6 instance, got %v", resp.Extra[1])
+	}
+	resp = exchange(t, dnsServer, question("_db._tcp.service.consul.", dns.TypeSRV), false)
+	if len(resp.Answer) != 1 || resp.Answer[0].(*dns.SRV).Target != "db.example.com." || len(resp.Extra) != 0 {
+		t.Errorf("Expected the hostname as SRV target, got %v / %v", resp.Answer, resp.Extra)
+	}
+}
+
+func TestDNSServer_TXTAndPTR(t *testing.T) {
+	dnsServer, serviceStore := setupTestServer()
+
+	services := []store.Service{
+		{ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80, Meta: map[string]string{"version": "1.2", "env": "prod"}},
+		{ID: "web-2", Name: "web", Address: "2001:db8::1", Port: 80},
+	}
+	for _, svc := range services {
+		if err := serviceStore.Register(svc); err != nil {
+			t.Fatalf("register service: %v", err)
+		}
+	}
+
+	resp := exchange(t, dnsServer, question("web.service.consul.", dns.TypeTXT), false)
+	if len(resp.Answer) != 1 {
+		t.Fatalf("Expected 1 TXT record, got %v", resp.Answer)
+	}
+	if txt := resp.Answer[0].(*dns.TXT).Txt; len(txt) != 2 || txt[0] != "env=prod" || txt[1] != "version=1.2" {
+		t.Errorf("Expected sorted metadata, got %v", txt)
+	}
+
+	for _, addr := range []string{"10.0.0.1", "2001:db8::1"} {
+		arpa, _ := dns.ReverseAddr(addr)
+		resp = exchange(t, dnsServer, question(arpa, dns.TypePTR), false)
+		if len(resp.Answer) != 1 || resp.Answer[0].(*dns.PTR).Ptr != "web.service.consul." {
+			t.Errorf("Expected a PTR to web.service.consul. for %s, got %v", addr, resp.Answer)
+		}
+	}
+
+	arpa, _ := dns.ReverseAddr("10.9.9.9")
+	if resp = exchange(t, dnsServer, question(arpa, dns.TypePTR), false); resp.Rcode != dns.RcodeNameError {
+		t.Errorf("Expected NXDOMAIN for an unknown address, got rcode %d", resp.Rcode)
+	}
+}
+
+func TestDNSServer_SOAAndNegativeAnswers(t *testing.T) {
+	dnsServer, _ := setupTestServer()
+
+	resp := exchange(t, dnsServer, question("consul.", dns.TypeSOA), false)
+	if len(resp.Answer) != 1 || resp.Answer[0].(*dns.SOA).Ns != "ns.consul." {
+		t.Errorf("Expected the SOA of the domain, got %v", resp.Answer)
+	}
+	if len(resp.Ns) != 1 || resp.Ns[0].Header().Rrtype != dns.TypeNS {
+		t.Errorf("Expected the NS in the authority section, got %v", resp.Ns)
+	}
+
+	resp = exchange(t, dnsServer, question("consul.", dns.TypeNS), false)
+	if len(resp.Answer) != 1 || resp.Answer[0].(*dns.NS).Ns != "ns.consul." {
+		t.Errorf("Expected the NS of the domain, got %v", resp.Answer)
+	}
+
+	resp = exchange(t, dnsServer, question("missing.service.consul.", dns.TypeA), false)
+	if resp.Rcode != dns.RcodeNameError {
+		t.Errorf("Expected NXDOMAIN for an unknown service, got rcode %d", resp.Rcode)
+	}
+	if len(resp.Ns) != 1 || resp.Ns[0].Header().Rrtype != dns.TypeSOA || !resp.Authoritative {
+		t.Errorf("Expected an authoritative answer with the SOA, got %v", resp.Ns)
+	}
+}
+
+func TestDNSServer_TruncationAndEDNS0(t *testing.T) {
+	dnsServer, serviceStore := setupTestServer()
+
+	for i := 0; i < 40; i++ {
+		svc := store.Service{ID: fmt.Sprintf("web-%d", i), Name: "web", Address: fmt.Sprintf("10.0.0.%d", i+1), Port: 80}
+		if err := serviceStore.Register(svc); err != nil {
+			t.Fatalf("register service: %v", err)
+		}
+	}
+
+	// Plain UDP answers are limited to 512 bytes
+	resp := exchange(t, dnsServer, question("_web._tcp.service.consul.", dns.TypeSRV), false)
+	if !resp.Truncated || resp.Len() > dns.MinMsgSize {
+		t.Errorf("Expected a truncated answer of at most %d bytes, got %d bytes (TC=%v)", dns.MinMsgSize, resp.Len(), resp.Truncated)
+	}
+
+	// Over TCP everything fits
+	resp = exchange(t, dnsServer, question("_web._tcp.service.consul.", dns.TypeSRV), true)
+	if resp.Truncated || len(resp.Answer) != 40 {
+		t.Errorf("Expected 40 answers over TCP, got %d (TC=%v)", len(resp.Answer), resp.Truncated)
+	}
+
+	// EDNS0 clients get answers up to their buffer size, and an OPT record
+	query := question("_web._tcp.service.consul.", dns.TypeSRV)
+	query.SetEdns0(4096, false)
+	resp = exchange(t, dnsServer, query, false)
+	if resp.Truncated || len(resp.Answer) != 40 {
+		t.Errorf("Expected 40 answers with EDNS0, got %d (TC=%v)", len(resp.Answer), resp.Truncated)
+	}
+	if opt := resp.IsEdns0(); opt == nil || opt.UDPSize() != maxUDPSize {
+		t.Errorf("Expected an OPT record advertising %d bytes, got %v", maxUDPSize, opt)
+	}
+
+	// Unsupported EDNS versions are refused
+	query = question("web.service.consul.", dns.TypeA)
+	query.SetEdns0(4096, false)
+	query.IsEdns0().SetVersion(1)
+	resp = exchange(t, dnsServer, query, false)
+	if resp.Rcode != dns.RcodeBadVers || len(resp.Answer) != 0 {
+		t.Errorf("Expected BADVERS, got rcode %d with %d answers", resp.Rcode, len(resp.Answer))
+	}
+}
@@ -0,0 +1,94 @@
+package dns
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/neogan74/konsul/internal/metrics"
+)
+
+// DefaultRecursorTimeout is the timeout of a query to a recursor when none
+// is configured.
+const DefaultRecursorTimeout = 2 * time.Second
+
+// recursorAddr returns the address of a recursor, with the DNS port added
+// when it has none.
+func recursorAddr(addr string) string {
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return addr
+	}
+	return net.JoinHostPort(strings.Trim(addr, "[]"), "53")
+}
+
+// forwarder sends queries for names outside the domain to upstream
+// recursors, in order, and caches their answers.
+type forwarder struct {
+	recursors []string
+	udp       *dns.Client
+	tcp       *dns.Client
+	cache     *answerCache
+}
+
+func newForwarder(recursors []string, timeout time.Duration, cacheSize int) *forwarder {
+	if timeout <= 0 {
+		timeout = DefaultRecursorTimeout
+	}
+	addrs := make([]string, 0, len(recursors))
+	for _, recursor := range recursors {
+		addrs = append(addrs, recursorAddr(recursor))
+	}
+	return &forwarder{
+		recursors: addrs,
+		udp:       &dns.Client{Net: "udp", Timeout: timeout, UDPSize: maxUDPSize},
+		tcp:       &dns.Client{Net: "tcp", Timeout: timeout},
+		cache:     newAnswerCache(cacheSize),
+	}
+}
+
+// forward returns the answer to r from the cache or from the first recursor
+// that answers it. Queries go over UDP, or TCP when the client used TCP or a
+// UDP answer came back truncated.
+func (f *forwarder) forward(r *dns.Msg, tcp bool) (*dns.Msg, error) {
+	if f.cache != nil {
+		if resp := f.cache.get(r); resp != nil {
+			metrics.DNSCacheLookupsTotal.WithLabelValues("hit").Inc()
+			return resp, nil
+		}
+		metrics.DNSCacheLookupsTotal.WithLabelValues("miss").Inc()
+	}
+
+	var lastErr error
+	for _, addr := range f.recursors {
+		resp, err := f.exchange(r, addr, tcp)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		// Let the next recursor try when this one cannot answer
+		if resp.Rcode == dns.RcodeServerFailure || resp.Rcode == dns.RcodeRefused {
+			lastErr = fmt.Errorf("recursor %s answered %s", addr, dns.RcodeToString[resp.Rcode])
+			continue
+		}
+
+		metrics.DNSForwardedQueriesTotal.WithLabelValues("success").Inc()
+		f.cache.set(r, resp)
+		return resp, nil
+	}
+
+	metrics.DNSForwardedQueriesTotal.WithLabelValues("error").Inc()
+	return nil, fmt.Errorf("no recursor answered: %w", lastErr)
+}
+
+func (f *forwarder) exchange(r *dns.Msg, addr string, tcp bool) (*dns.Msg, error) {
+	if !tcp {
+		resp, _, err := f.udp.Exchange(r, addr)
+		if err != nil || !resp.Truncated {
+			return resp, err
+		}
+	}
+	resp, _, err := f.tcp.Exchange(r, addr)
+	return resp, err
+}
@@ -0,0 +1,369 @@
+package dns
+
+import (
+	"container/heap"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/store"
+)
+
+// upstream is a recursor stand-in listening on UDP and TCP on the same port.
+type upstream struct {
+	addr    string
+	queries atomic.Int32
+}
+
+// startUpstream starts a recursor stand-in that answers with handle. The
+// tcp argument tells whether the query came over TCP.
+func startUpstream(t *testing.T, handle func(r *dns.Msg, tcp bool) *dns.Msg) *upstream {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen udp: %v", err)
+	}
+	ln, err := net.Listen("tcp", pc.LocalAddr().String())
+	if err != nil {
+		pc.Close()
+		t.Fatalf("listen tcp: %v", err)
+	}
+
+	u := &upstream{addr: pc.LocalAddr().String()}
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		u.queries.Add(1)
+		_, tcp := w.RemoteAddr().(*net.TCPAddr)
+		_ = w.WriteMsg(handle(r, tcp))
+	})
+
+	for _, server := range []*dns.Server{
+		{PacketConn: pc, Handler: handler},
+		{Listener: ln, Handler: handler},
+	} {
+		started := make(chan struct{})
+		server.NotifyStartedFunc = func() { close(started) }
+		go func() { _ = server.ActivateAndServe() }()
+		<-started
+		t.Cleanup(func() { _ = server.Shutdown() })
+	}
+	return u
+}
+
+// answerA answers every question with an A record of the given TTL.
+func answerA(ttl uint32) func(r *dns.Msg, tcp bool) *dns.Msg {
+	return func(r *dns.Msg, tcp bool) *dns.Msg {
+		msg := new(dns.Msg)
+		msg.SetReply(r)
+		msg.RecursionAvailable = true
+		msg.Answer = append(msg.Answer, &dns.A{
+			Hdr: dns.RR_Header{Name: r.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: ttl},
+			A:   net.ParseIP("93.184.216.34").To4(),
+		})
+		return msg
+	}
+}
+
+func setupRecursorServer(cacheSize int, recursors ...string) (*Server, *store.ServiceStore) {
+	serviceStore := store.NewServiceStoreWithTTL(30 * time.Second)
+	config := Config{
+		Domain:          "consul",
+		Recursors:       recursors,
+		RecursorTimeout: 500 * time.Millisecond,
+		CacheSize:       cacheSize,
+	}
+	return NewServer(config, serviceStore, logger.NewFromConfig("info", "text")), serviceStore
+}
+
+func TestRecursorAddr(t *testing.T) {
+	tests := map[string]string{
+		"1.1.1.1":          "1.1.1.1:53",
+		"1.1.1.1:5353":     "1.1.1.1:5353",
+		"dns.example.com":  "dns.example.com:53",
+		"2001:db8::1":      "[2001:db8::1]:53",
+		"[2001:db8::1]:54": "[2001:db8::1]:54",
+	}
+	for addr, want := range tests {
+		if got := recursorAddr(addr); got != want {
+			t.Errorf("recursorAddr(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
+
+func TestDNSServer_ForwardsOutOfDomainQueries(t *testing.T) {
+	up := startUpstream(t, answerA(60))
+	dnsServer, serviceStore := setupRecursorServer(16, up.addr)
+	if err := serviceStore.Register(store.Service{ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80}); err != nil {
+		t.Fatalf("register service: %v", err)
+	}
+
+	resp := exchange(t, dnsServer, question("example.com.", dns.TypeA), false)
+	if resp.Rcode != dns.RcodeSuccess || len(resp.Answer) != 1 {
+		t.Fatalf("Expected the upstream answer, got %v", resp)
+	}
+	if !resp.RecursionAvailable || resp.Authoritative {
+		t.Errorf("Expected a non-authoritative answer with recursion available")
+	}
+
+	// The second query is answered from the cache
+	resp = exchange(t, dnsServer, question("EXAMPLE.com.", dns.TypeA), false)
+	if len(resp.Answer) != 1 {
+		t.Fatalf("Expected the cached answer, got %v", resp)
+	}
+	if got := up.queries.Load(); got != 1 {
+		t.Errorf("Expected 1 upstream query, got %d", got)
+	}
+
+	// Names of the domain are answered locally, with recursion available
+	resp = exchange(t, dnsServer, question("web.service.consul.", dns.TypeA), false)
+	if len(resp.Answer) != 1 || !resp.Authoritative || !resp.RecursionAvailable {
+		t.Errorf("Expected a local authoritative answer, got %v", resp)
+	}
+	resp = exchange(t, dnsServer, question("missing.service.consul.", dns.TypeA), false)
+	if resp.Rcode != dns.RcodeNameError {
+		t.Errorf("Expected NXDOMAIN for an unknown local name, got %s", dns.RcodeToString[resp.Rcode])
+	}
+	if got := up.queries.Load(); got != 1 {
+		t.Errorf("Expected local names not to be forwarded, got %d upstream queries", got)
+	}
+}
+
+func TestDNSServer_ForwardsUnknownReverseLookups(t *testing.T) {
+	up := startUpstream(t, func(r *dns.Msg, tcp bool) *dns.Msg {
+		msg := new(dns.Msg)
+		msg.SetReply(r)
+		msg.Answer = append(msg.Answer, &dns.PTR{
+			Hdr: dns.RR_Header{Name: r.Question[0].Name, Rrtype: dns.TypePTR, Class: dns.ClassINET, Ttl: 60},
+			Ptr: "host.example.com.",
+		})
+		return msg
+	})
+	dnsServer, serviceStore := setupRecursorServer(0, up.addr)
+	if err := serviceStore.Register(store.Service{ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80}); err != nil {
+		t.Fatalf("register service: %v", err)
+	}
+
+	resp := exchange(t, dnsServer, question("1.0.0.10.in-addr.arpa.", dns.TypePTR), false)
+	if len(resp.Answer) != 1 || resp.Answer[0].(*dns.PTR).Ptr != "web.service.consul." {
+		t.Errorf("Expected the catalog to answer for a known address, got %v", resp.Answer)
+	}
+
+	resp = exchange(t, dnsServer, question("2.0.0.10.in-addr.arpa.", dns.TypePTR), false)
+	if len(resp.Answer) != 1 || resp.Answer[0].(*dns.PTR).Ptr != "host.example.com." {
+		t.Errorf("Expected the upstream to answer for an unknown address, got %v", resp.Answer)
+	}
+	if got := up.queries.Load(); got != 1 {
+		t.Errorf("Expected 1 upstream query, got %d", got)
+	}
+}
+
+func TestDNSServer_ForwardingNegativeCache(t *testing.T) {
+	up := startUpstream(t, func(r *dns.Msg, tcp bool) *dns.Msg {
+		msg := new(dns.Msg)
+		msg.SetRcode(r, dns.RcodeNameError)
+		msg.Ns = append(msg.Ns, &dns.SOA{
+			Hdr:    dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 300},
+			Ns:     "ns.example.com.",
+			Mbox:   "hostmaster.example.com.",
+			Minttl: 30,
+		})
+		return msg
+	})
+	dnsServer, _ := setupRecursorServer(16, up.addr)
+
+	for i := 0; i < 3; i++ {
+		resp := exchange(t, dnsServer, question("missing.example.com.", dns.TypeA), false)
+		if resp.Rcode != dns.RcodeNameError {
+			t.Fatalf("Expected NXDOMAIN, got %s", dns.RcodeToString[resp.Rcode])
+		}
+	}
+	if got := up.queries.Load(); got != 1 {
+		t.Errorf("Expected the negative answer to be cached, got %d upstream queries", got)
+	}
+
+	entry := dnsServer.forwarder.cache.entries[cacheKey{name: "missing.example.com.", qtype: dns.TypeA, qclass: dns.ClassINET}]
+	if ttl := entry.expires.Sub(entry.stored); ttl != 30*time.Second {
+		t.Errorf("Expected the SOA minimum TTL of 30s, got %v", ttl)
+	}
+}
+
+func TestDNSServer_ForwardingFailover(t *testing.T) {
+	failing := startUpstream(t, func(r *dns.Msg, tcp bool) *dns.Msg {
+		msg := new(dns.Msg)
+		msg.SetRcode(r, dns.RcodeServerFailure)
+		return msg
+	})
+	up := startUpstream(t, answerA(60))
+	dnsServer, _ := setupRecursorServer(0, failing.addr, up.addr)
+
+	resp := exchange(t, dnsServer, question("example.com.", dns.TypeA), false)
+	if resp.Rcode != dns.RcodeSuccess || len(resp.Answer) != 1 {
+		t.Fatalf("Expected the second recursor to answer, got %v", resp)
+	}
+	if failing.queries.Load() != 1 || up.queries.Load() != 1 {
+		t.Errorf("Expected one query to each recursor, got %d and %d", failing.queries.Load(), up.queries.Load())
+	}
+
+	// Without the cache every query reaches the recursors
+	exchange(t, dnsServer, question("example.com.", dns.TypeA), false)
+	if got := up.queries.Load(); got != 2 {
+		t.Errorf("Expected 2 upstream queries without a cache, got %d", got)
+	}
+}
+
+func TestDNSServer_ForwardingServerFailure(t *testing.T) {
+	// Reserve a port and release it so that nothing answers there
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen udp: %v", err)
+	}
+	addr := pc.LocalAddr().String()
+	pc.Close()
+
+	dnsServer, _ := setupRecursorServer(16, addr)
+	resp := exchange(t, dnsServer, question("example.com.", dns.TypeA), false)
+	if resp.Rcode != dns.RcodeServerFailure {
+		t.Errorf("Expected SERVFAIL, got %s", dns.RcodeToString[resp.Rcode])
+	}
+	if len(dnsServer.forwarder.cache.entries) != 0 {
+		t.Error("Expected failures not to be cached")
+	}
+}
+
+func TestDNSServer_ForwardingRetriesTruncatedOverTCP(t *testing.T) {
+	full := answerA(60)
+	up := startUpstream(t, func(r *dns.Msg, tcp bool) *dns.Msg {
+		if tcp {
+			return full(r, tcp)
+		}
+		msg := new(dns.Msg)
+		msg.SetReply(r)
+		msg.Truncated = true
+		return msg
+	})
+	dnsServer, _ := setupRecursorServer(16, up.addr)
+
+	resp := exchange(t, dnsServer, question("example.com.", dns.TypeA), false)
+	if resp.Truncated || len(resp.Answer) != 1 {
+		t.Errorf("Expected the full answer fetched over TCP, got %v", resp)
+	}
+	if got := up.queries.Load(); got != 2 {
+		t.Errorf("Expected a UDP and a TCP query, got %d", got)
+	}
+
+	// Clients using TCP get their queries forwarded over TCP
+	exchange(t, dnsServer, question("other.example.com.", dns.TypeA), true)
+	if got := up.queries.Load(); got != 3 {
+		t.Errorf("Expected a single TCP query, got %d", got)
+	}
+}
+
+func TestAnswerCache(t *testing.T) {
+	reply := func(name string, ttl uint32) (*dns.Msg, *dns.Msg) {
+		r := question(name, dns.TypeA)
+		return r, answerA(ttl)(r, false)
+	}
+
+	cache := newAnswerCache(2)
+
+	// TTLs decrease with the time spent in the cache
+	r, resp := reply("a.example.com.", 60)
+	cache.set(r, resp)
+	key, _ := cacheKeyOf(r)
+	entry := cache.entries[key]
+	entry.stored = entry.stored.Add(-10 * time.Second)
+
+	r.Id = 42
+	cached := cache.get(r)
+	if cached == nil || cached.Id != 42 {
+		t.Fatalf("Expected the cached answer with the query ID, got %v", cached)
+	}
+	if ttl := cached.Answer[0].Header().Ttl; ttl != 50 {
+		t.Errorf("Expected TTL 50 after 10s in the cache, got %d", ttl)
+	}
+
+	// Expired answers are dropped
+	entry.expires = time.Now().Add(-time.Second)
+	if cache.get(r) != nil {
+		t.Error("Expected the expired answer to be dropped")
+	}
+	if cache.len() != 0 {
+		t.Errorf("Expected an empty cache, got %d answers", cache.len())
+	}
+
+	// Answers with a zero TTL are not cached
+	r, resp = reply("zero.example.com.", 0)
+	cache.set(r, resp)
+	if cache.len() != 0 {
+		t.Error("Expected an answer with TTL 0 not to be cached")
+	}
+
+	// A full cache evicts the answer closest to expiry
+	for i, ttl := range []uint32{30, 60, 90} {
+		r, resp = reply(string(rune('b'+i))+".example.com.", ttl)
+		cache.set(r, resp)
+	}
+	if cache.len() != 2 {
+		t.Fatalf("Expected 2 cached answers, got %d", cache.len())
+	}
+	if r, _ = reply("b.example.com.", 0); cache.get(r) != nil {
+		t.Error("Expected the answer closest to expiry to be evicted")
+	}
+
+	// A nil cache caches nothing
+	var disabled *answerCache
+	disabled.set(r, resp)
+	if disabled.get(r) != nil {
+		t.Error("Expected a nil cache to return nothing")
+	}
+}
+
+func TestAnswerCache_Eviction(t *testing.T) {
+	set := func(cache *answerCache, name string, ttl uint32) *dns.Msg {
+		r := question(name, dns.TypeA)
+		cache.set(r, answerA(ttl)(r, false))
+		return r
+	}
+
+	cache := newAnswerCache(100)
+	queries := make(map[uint32]*dns.Msg)
+	for ttl := uint32(1000); ttl > 900; ttl-- {
+		queries[ttl] = set(cache, fmt.Sprintf("%d.example.com.", ttl), ttl)
+	}
+
+	// Refreshing an answer moves it in the expiry order
+	set(cache, "901.example.com.", 2000)
+
+	// Each new answer evicts the one closest to expiry
+	for i := 0; i < 3; i++ {
+		set(cache, fmt.Sprintf("new-%d.example.com.", i), 3000)
+	}
+	for ttl, r := range map[uint32]*dns.Msg{902: queries[902], 903: queries[903], 904: queries[904]} {
+		if cache.get(r) != nil {
+			t.Errorf("Expected the answer with TTL %d to be evicted", ttl)
+		}
+	}
+	for _, ttl := range []uint32{901, 905, 1000} {
+		if cache.get(queries[ttl]) == nil {
+			t.Errorf("Expected the answer with TTL %d to be kept", ttl)
+		}
+	}
+
+	// Expired answers are all evicted before any other
+	for _, ttl := range []uint32{950, 960, 970} {
+		entry := cache.entries[cacheKey{name: fmt.Sprintf("%d.example.com.", ttl), qtype: dns.TypeA, qclass: dns.ClassINET}]
+		entry.expires = time.Now().Add(-time.Second)
+		heap.Fix(&cache.expiry, entry.index)
+	}
+	set(cache, "last.example.com.", 3000)
+	if cache.len() != 98 {
+		t.Errorf("Expected the 3 expired answers to be evicted, got %d answers", cache.len())
+	}
+	if cache.get(queries[905]) == nil {
+		t.Error("Expected the answers not expired to be kept")
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/miekg/dns"
+	"github.com/neogan74/konsul/internal/healthcheck"
 	"github.com/neogan74/konsul/internal/logger"
 	"github.com/neogan74/konsul/internal/store"
 )
@@ -14,7 +16,10 @@ type Server struct {
 	udpServer *dns.Server
 	tcpServer *dns.Server
 	domain    string
+	statuses  []healthcheck.Status // Check statuses of the instances answered
 	store     *store.ServiceStore
+	queries   *store.PreparedQueryStore
+	forwarder *forwarder // nil without recursors
 	log       logger.Logger
 }
 
@@ -22,13 +27,28 @@ type Config struct {
 	Host   string
 	Port   int
 	Domain string
+	// OnlyPassing leaves instances with warning checks out of answers.
+	// Instances with critical checks are always left out.
+	OnlyPassing bool
+	// Recursors answer the queries for names outside Domain, tried in
+	// order (host or host:port). Without recursors such names are unknown.
+	Recursors       []string
+	RecursorTimeout time.Duration // Timeout of a query to a recursor
+	CacheSize       int           // Maximum number of recursor answers cached; 0 disables the cache
 }
 
 func NewServer(cfg Config, serviceStore *store.ServiceStore, log logger.Logger) *Server {
 	s := &Server{
-		domain: cfg.Domain,
-		store:  serviceStore,
-		log:    log,
+		domain:   strings.Trim(cfg.Domain, "."),
+		statuses: store.DiscoveryStatuses,
+		store:    serviceStore,
+		log:      log,
+	}
+	if cfg.OnlyPassing {
+		s.statuses = []healthcheck.Status{healthcheck.StatusPassing}
+	}
+	if len(cfg.Recursors) > 0 {
+		s.forwarder = newForwarder(cfg.Recursors, cfg.RecursorTimeout, cfg.CacheSize)
 	}
 
 	mux := dns.NewServeMux()
@@ -50,6 +70,13 @@ func NewServer(cfg Config, serviceStore *store.ServiceStore, log logger.Logger)
 	return s
 }
 
+// WithPreparedQueries answers <name>.query.<domain> lookups by executing the
+// prepared queries of queries.
+func (s *Server) WithPreparedQueries(queries *store.PreparedQueryStore) *Server {
+	s.queries = queries
+	return s
+}
+
 func (s *Server) Start() error {
 	s.log.Info("Starting DNS server",
 		logger.String("domain", s.domain),
@@ -91,137 +118,196 @@ func (s *Server) Stop() error {
 }
 
 func (s *Server) handleDNSRequest(w dns.ResponseWriter, r *dns.Msg) {
+	if s.forwarder != nil && s.forwardable(r) {
+		s.forward(w, r)
+		return
+	}
+
 	msg := new(dns.Msg)
 	msg.SetReply(r)
 	msg.Authoritative = true
+	msg.RecursionAvailable = s.forwarder != nil
+	msg.Compress = true
 
+	exists := false
 	for _, question := range r.Question {
 		s.log.Debug("DNS query received",
 			logger.String("name", question.Name),
 			logger.String("type", dns.TypeToString[question.Qtype]))
 
-		switch question.Qtype {
-		case dns.TypeSRV:
-			s.handleSRVQuery(msg, question)
-		case dns.TypeA:
-			s.handleAQuery(msg, question)
-		case dns.TypeANY:
-			s.handleSRVQuery(msg, question)
-			s.handleAQuery(msg, question)
-		default:
-			s.log.Debug("Unsupported DNS query type",
-				logger.String("type", dns.TypeToString[question.Qtype]))
+		if s.answer(msg, question) {
+			exists = true
 		}
 	}
 
+	// Negative answers carry the SOA for negative caching (RFC 2308):
+	// NXDOMAIN for unknown names, NOERROR for names without records of the
+	// type asked
 	if len(msg.Answer) == 0 {
-		msg.Rcode = dns.RcodeNameError
+		if !exists {
+			msg.Rcode = dns.RcodeNameError
+		}
+		msg.Ns = append(msg.Ns, s.soa())
 	}
 
+	fit(w, r, msg)
 	_ = w.WriteMsg(msg)
 }
 
-func (s *Server) handleSRVQuery(msg *dns.Msg, question dns.Question) {
-	name := strings.TrimSuffix(question.Name, ".")
+// forwardable reports whether r asks for a name the server does not know:
+// a name outside the domain, or the reverse name of an address that is not
+// in the catalog.
+func (s *Server) forwardable(r *dns.Msg) bool {
+	if len(r.Question) != 1 {
+		return false
+	}
+	name := strings.TrimSuffix(r.Question[0].Name, ".")
+	if isReverse(r.Question[0].Name) {
+		return len(s.reverseTargets(r.Question[0].Name)) == 0
+	}
+	return !strings.EqualFold(name, s.domain) && !strings.HasSuffix(strings.ToLower(name), "."+strings.ToLower(s.domain))
+}
 
-	// Parse SRV query: _service._protocol.service.consul
-	parts := strings.Split(name, ".")
-	if len(parts) < 4 {
+// forward answers r with the answer of the recursors, or SERVFAIL when none
+// answers.
+func (s *Server) forward(w dns.ResponseWriter, r *dns.Msg) {
+	_, tcp := w.RemoteAddr().(*net.TCPAddr)
+	resp, err := s.forwarder.forward(r, tcp)
+	if err != nil {
+		s.log.Warn("DNS forwarding failed",
+			logger.String("name", r.Question[0].Name),
+			logger.Error(err))
+		msg := new(dns.Msg)
+		msg.SetRcode(r, dns.RcodeServerFailure)
+		msg.RecursionAvailable = true
+		_ = w.WriteMsg(msg)
 		return
 	}
 
-	serviceName := strings.TrimPrefix(parts[0], "_")
-	protocol := strings.TrimPrefix(parts[1], "_")
-
-	// For now, we'll ignore protocol and just match service name
-	_ = protocol
+	resp.Truncate(responseSize(w, r))
+	_ = w.WriteMsg(resp)
+}
 
-	// Get all healthy services matching the name
-	services := s.store.List()
-	var matchingServices []store.Service
+// answer adds the records answering question to msg. It reports whether the
+// name asked exists, whether or not it has records of the type asked.
+func (s *Server) answer(msg *dns.Msg, question dns.Question) bool {
+	if isReverse(question.Name) {
+		return s.handlePTRQuery(msg, question)
+	}
+	if strings.EqualFold(strings.TrimSuffix(question.Name, "."), s.domain) {
+		s.handleApexQuery(msg, question)
+		return true
+	}
 
-	for _, service := range services {
-		if service.Name == serviceName {
-			matchingServices = append(matchingServices, service)
-		}
+	l, ok := s.parseName(question.Name)
+	if !ok {
+		return false
 	}
+	instances := s.instances(l)
 
-	// Create SRV records
-	for i, service := range matchingServices {
-		target := fmt.Sprintf("%s.node.%s.", service.Name, s.domain)
-
-		srv := &dns.SRV{
-			Hdr: dns.RR_Header{
-				Name:   question.Name,
-				Rrtype: dns.TypeSRV,
-				Class:  dns.ClassINET,
-				Ttl:    30,
-			},
-			Priority: 1,
-			Weight:   uint16(100 / (i + 1)), // Simple weight distribution
-			Port:     uint16(service.Port),
-			Target:   target,
+	switch question.Qtype {
+	case dns.TypeSRV:
+		if l.kind != lookupNode {
+			s.addSRV(msg, question, instances)
 		}
-		msg.Answer = append(msg.Answer, srv)
-
-		// Add corresponding A record in Additional section
-		a := &dns.A{
-			Hdr: dns.RR_Header{
-				Name:   target,
-				Rrtype: dns.TypeA,
-				Class:  dns.ClassINET,
-				Ttl:    30,
-			},
-			A: net.ParseIP(service.Address),
+	case dns.TypeA, dns.TypeAAAA:
+		s.addAddresses(msg, question, instances)
+	case dns.TypeTXT:
+		s.addTXT(msg, question, instances)
+	case dns.TypeANY:
+		if l.kind != lookupNode {
+			s.addSRV(msg, question, instances)
 		}
-		msg.Extra = append(msg.Extra, a)
+		s.addAddresses(msg, question, instances)
+		s.addTXT(msg, question, instances)
+	default:
+		s.log.Debug("Unsupported DNS query type",
+			logger.String("type", dns.TypeToString[question.Qtype]))
 	}
 
-	s.log.Debug("SRV query processed",
-		logger.String("service", serviceName),
-		logger.Int("matches", len(matchingServices)))
-}
+	s.log.Debug("DNS query processed",
+		logger.String("kind", l.kind),
+		logger.String("name", l.name),
+		logger.String("tag", l.tag),
+		logger.Int("matches", len(instances)))
 
-func (s *Server) handleAQuery(msg *dns.Msg, question dns.Question) {
-	name := strings.TrimSuffix(question.Name, ".")
+	return len(instances) > 0
+}
 
-	// Parse A query: service.node.consul or service-name.service.consul
-	parts := strings.Split(name, ".")
-	if len(parts) < 3 {
-		return
-	}
+// Lookup kinds, from the label that precedes the domain
+const (
+	lookupService = "service"
+	lookupNode    = "node"
+	lookupQuery   = "query"
+)
 
-	var serviceName string
+// lookup is a parsed question name.
+type lookup struct {
+	kind string // lookupService, lookupNode or lookupQuery
+	name string // Service or prepared query name, or instance ID of node lookups
+	tag  string // Tag the instances must have; service lookups only
+}
 
-	// Check if it's a node query (service.node.consul)
-	if len(parts) >= 3 && parts[1] == "node" {
-		serviceName = parts[0]
-	} else if len(parts) >= 3 && parts[1] == "service" {
-		// service-name.service.consul format
-		serviceName = parts[0]
-	} else {
-		return
+// parseName parses the question names the server answers:
+//
+//	<name>.service.<domain>
+//	<tag>.<name>.service.<domain>
+//	_<name>._<tag>.service.<domain>  (RFC 2782; _tcp and _udp match any tag)
+//	<instance-id>.node.<domain>      (falls back to the service name)
+//	<name>.query.<domain>            (prepared query)
+func (s *Server) parseName(qname string) (lookup, bool) {
+	name := strings.TrimSuffix(qname, ".")
+	domain := "." + s.domain
+	if len(name) <= len(domain) || !strings.EqualFold(name[len(name)-len(domain):], domain) {
+		return lookup{}, false
 	}
-
-	// Get all healthy services matching the name
-	services := s.store.List()
-
-	for _, service := range services {
-		if service.Name == serviceName {
-			a := &dns.A{
-				Hdr: dns.RR_Header{
-					Name:   question.Name,
-					Rrtype: dns.TypeA,
-					Class:  dns.ClassINET,
-					Ttl:    30,
-				},
-				A: net.ParseIP(service.Address),
+	labels := strings.Split(name[:len(name)-len(domain)], ".")
+
+	kind := strings.ToLower(labels[len(labels)-1])
+	switch {
+	case len(labels) >= 2 && kind == lookupNode:
+		// Instance IDs may contain dots
+		return lookup{kind: kind, name: strings.Join(labels[:len(labels)-1], ".")}, true
+	case len(labels) == 2 && (kind == lookupService || kind == lookupQuery):
+		return lookup{kind: kind, name: labels[0]}, true
+	case len(labels) == 3 && kind == lookupService:
+		if strings.HasPrefix(labels[0], "_") && strings.HasPrefix(labels[1], "_") {
+			tag := strings.TrimPrefix(labels[1], "_")
+			if strings.EqualFold(tag, "tcp") || strings.EqualFold(tag, "udp") {
+				tag = ""
 			}
-			msg.Answer = append(msg.Answer, a)
+			return lookup{kind: kind, name: strings.TrimPrefix(labels[0], "_"), tag: tag}, true
 		}
+		return lookup{kind: kind, name: labels[1], tag: labels[0]}, true
+	}
+	return lookup{}, false
+}
+
+// instances returns the live instances a lookup resolves to. Instances whose
+// health checks keep them out of answers are left out; prepared queries
+// apply their own health filter.
+func (s *Server) instances(l lookup) []store.Service {
+	if l.kind == lookupQuery {
+		if s.queries == nil {
+			return nil
+		}
+		result, err := s.queries.Execute(l.name)
+		if err != nil {
+			return nil
+		}
+		return result.Instances
+	}
+	if l.kind == lookupNode {
+		if service, ok := s.store.Get(l.name); ok {
+			return s.store.FilterByStatus([]store.Service{service}, s.statuses...)
+		}
+		// Other names resolve as a service name, as the SRV targets of older
+		// versions did
 	}
 
-	s.log.Debug("A query processed",
-		logger.String("service", serviceName),
-		logger.Int("records", len(msg.Answer)))
+	var tags []string
+	if l.tag != "" {
+		tags = []string{l.tag}
+	}
+	return s.store.FilterByStatus(s.store.QueryInstances(l.name, tags, nil), s.statuses...)
 }
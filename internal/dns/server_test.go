@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/miekg/dns"
+	"github.com/neogan74/konsul/internal/healthcheck"
 	"github.com/neogan74/konsul/internal/logger"
 	"github.com/neogan74/konsul/internal/store"
 )
@@ -27,7 +28,7 @@ func setupTestServer() (*Server, *store.ServiceStore) {
 func TestDNSServer_SRVQuery(t *testing.T) {
 	dnsServer, serviceStore := setupTestServer()
 
-	// Register test service (only one instance per name in current implementation)
+	// Register test service
 	service1 := store.Service{Name: "web", Address: "192.168.1.100", Port: 80}
 	if err := serviceStore.Register(service1); err != nil {
 		t.Fatalf("register service: %v", err)
@@ -85,6 +86,100 @@ func TestDNSServer_SRVQuery(t *testing.T) {
 	}
 }
 
+func TestDNSServer_SRVQuery_MultipleInstances(t *testing.T) {
+	dnsServer, serviceStore := setupTestServer()
+
+	instances := []store.Service{
+		{ID: "web-1", Name: "web", Address: "192.168.1.100", Port: 80},
+		{ID: "web-2", Name: "web", Address: "192.168.1.101", Port: 8080},
+	}
+	for _, svc := range instances {
+		if err := serviceStore.Register(svc); err != nil {
+			t.Fatalf("register service: %v", err)
+		}
+	}
+
+	query := new(dns.Msg)
+	query.SetQuestion("_web._tcp.service.consul.", dns.TypeSRV)
+
+	mockWriter := &mockResponseWriter{}
+	dnsServer.handleDNSRequest(mockWriter, query)
+
+	if mockWriter.msg == nil {
+		t.Fatal("Expected DNS response, got nil")
+	}
+	if len(mockWriter.msg.Answer) != 2 {
+		t.Fatalf("Expected 2 SRV records, got %d", len(mockWriter.msg.Answer))
+	}
+
+	ports := make(map[uint16]bool)
+	for _, rr := range mockWriter.msg.Answer {
+		srv, ok := rr.(*dns.SRV)
+		if !ok {
+			t.Fatal("Expected SRV record")
+		}
+		ports[srv.Port] = true
+	}
+	if !ports[80] || !ports[8080] {
+		t.Errorf("Expected SRV records for ports 80 and 8080, got %v", ports)
+	}
+}
+
+func TestDNSServer_SRVQuery_InstanceTargets(t *testing.T) {
+	dnsServer, serviceStore := setupTestServer()
+
+	instances := []store.Service{
+		{ID: "web-1", Name: "web", Address: "192.168.1.100", Port: 80},
+		{ID: "web-2", Name: "web", Address: "192.168.1.101", Port: 8080},
+	}
+	for _, svc := range instances {
+		if err := serviceStore.Register(svc); err != nil {
+			t.Fatalf("register service: %v", err)
+		}
+	}
+
+	query := new(dns.Msg)
+	query.SetQuestion("_web._tcp.service.consul.", dns.TypeSRV)
+	mockWriter := &mockResponseWriter{}
+	dnsServer.handleDNSRequest(mockWriter, query)
+
+	// Each port must be paired with the address of its instance
+	addresses := make(map[string]string)
+	for _, rr := range mockWriter.msg.Extra {
+		if a, ok := rr.(*dns.A); ok {
+			addresses[a.Hdr.Name] = a.A.String()
+		}
+	}
+	want := map[uint16]string{80: "192.168.1.100", 8080: "192.168.1.101"}
+	if len(mockWriter.msg.Answer) != 2 {
+		t.Fatalf("Expected 2 SRV records, got %d", len(mockWriter.msg.Answer))
+	}
+	for _, rr := range mockWriter.msg.Answer {
+		srv, ok := rr.(*dns.SRV)
+		if !ok {
+			t.Fatal("Expected SRV record")
+		}
+		if got := addresses[srv.Target]; got != want[srv.Port] {
+			t.Errorf("Expected target %s of port %d to resolve to %s, got %q", srv.Target, srv.Port, want[srv.Port], got)
+		}
+	}
+
+	// The targets resolve on their own too
+	for id, address := range map[string]string{"web-1": "192.168.1.100", "web-2": "192.168.1.101"} {
+		query := new(dns.Msg)
+		query.SetQuestion(id+".node.consul.", dns.TypeA)
+		mockWriter := &mockResponseWriter{}
+		dnsServer.handleDNSRequest(mockWriter, query)
+
+		if len(mockWriter.msg.Answer) != 1 {
+			t.Fatalf("Expected 1 A record for %s, got %d", id, len(mockWriter.msg.Answer))
+		}
+		if a, ok := mockWriter.msg.Answer[0].(*dns.A); !ok || a.A.String() != address {
+			t.Errorf("Expected %s to resolve to %s, got %v", id, address, mockWriter.msg.Answer[0])
+		}
+	}
+}
+
 func TestDNSServer_AQuery_NodeFormat(t *testing.T) {
 	dnsServer, serviceStore := setupTestServer()
 
@@ -232,13 +327,17 @@ func TestDNSServer_UnsupportedQueryType(t *testing.T) {
 	mockWriter := &mockResponseWriter{}
 	dnsServer.handleDNSRequest(mockWriter, query)
 
-	// Verify NXDOMAIN response for unsupported type
+	// The name exists: NOERROR without answers, and the SOA for negative caching
 	if mockWriter.msg == nil {
 		t.Fatal("Expected DNS response, got nil")
 	}
 
-	if mockWriter.msg.Rcode != dns.RcodeNameError {
-		t.Errorf("Expected NXDOMAIN for unsupported query type, got rcode %d", mockWriter.msg.Rcode)
+	if mockWriter.msg.Rcode != dns.RcodeSuccess || len(mockWriter.msg.Answer) != 0 {
+		t.Errorf("Expected an empty NOERROR answer for unsupported query type, got rcode %d with %d answers",
+			mockWriter.msg.Rcode, len(mockWriter.msg.Answer))
+	}
+	if len(mockWriter.msg.Ns) != 1 || mockWriter.msg.Ns[0].Header().Rrtype != dns.TypeSOA {
+		t.Errorf("Expected the SOA in the authority section, got %v", mockWriter.msg.Ns)
 	}
 }
 
@@ -370,6 +469,7 @@ func TestDNSServer_InvalidDomainParsing(t *testing.T) {
 // Mock response writer for testing
 type mockResponseWriter struct {
 	msg *dns.Msg
+	tcp bool // Whether the query came over TCP
 }
 
 func (m *mockResponseWriter) LocalAddr() net.Addr {
@@ -377,6 +477,9 @@ func (m *mockResponseWriter) LocalAddr() net.Addr {
 }
 
 func (m *mockResponseWriter) RemoteAddr() net.Addr {
+	if m.tcp {
+		return &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 12345}
+	}
 	return &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 12345}
 }
 
@@ -400,3 +503,118 @@ func (m *mockResponseWriter) TsigStatus() error {
 func (m *mockResponseWriter) TsigTimersOnly(bool) {}
 
 func (m *mockResponseWriter) Hijack() {}
+
+func TestDNSServer_HealthFiltering(t *testing.T) {
+	serviceStore := store.NewServiceStoreWithTTL(30 * time.Second)
+	log := logger.NewFromConfig("info", "text")
+
+	services := []store.Service{
+		{ID: "db-1", Name: "db", Address: "10.0.0.1", Port: 5432},
+		{ID: "db-2", Name: "db", Address: "10.0.0.2", Port: 5432,
+			Checks: []*healthcheck.CheckDefinition{{ID: "db-2-ttl", TTL: "60s"}}},
+	}
+	for _, svc := range services {
+		if err := serviceStore.Register(svc); err != nil {
+			t.Fatalf("register service: %v", err)
+		}
+	}
+
+	query := func(server *Server, qtype uint16, name string) []dns.RR {
+		msg := new(dns.Msg)
+		msg.SetQuestion(name, qtype)
+		writer := &mockResponseWriter{}
+		server.handleDNSRequest(writer, msg)
+		return writer.msg.Answer
+	}
+
+	// db-2 is critical until its TTL check is updated
+	server := NewServer(Config{Domain: "consul"}, serviceStore, log)
+	answers := query(server, dns.TypeA, "db.service.consul.")
+	if len(answers) != 1 || answers[0].(*dns.A).A.String() != "10.0.0.1" {
+		t.Fatalf("Expected only the healthy instance, got %v", answers)
+	}
+	if answers := query(server, dns.TypeSRV, "_db._tcp.service.consul."); len(answers) != 1 {
+		t.Errorf("Expected 1 SRV record, got %d", len(answers))
+	}
+
+	if err := serviceStore.UpdateTTLCheck("db-2-ttl"); err != nil {
+		t.Fatalf("update check: %v", err)
+	}
+	if answers := query(server, dns.TypeA, "db.service.consul."); len(answers) != 2 {
+		t.Errorf("Expected both instances once the check passes, got %d", len(answers))
+	}
+	passing := NewServer(Config{Domain: "consul", OnlyPassing: true}, serviceStore, log)
+	if answers := query(passing, dns.TypeA, "db.service.consul."); len(answers) != 2 {
+		t.Errorf("Expected both passing instances, got %d", len(answers))
+	}
+}
+
+func TestDNSServer_TagAndPreparedQueryLookups(t *testing.T) {
+	dnsServer, serviceStore := setupTestServer()
+	queries := store.NewPreparedQueryStore(serviceStore)
+	dnsServer.WithPreparedQueries(queries)
+
+	services := []store.Service{
+		{ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80, Tags: []string{"prod"}},
+		{ID: "web-2", Name: "web", Address: "10.0.0.2", Port: 80, Tags: []string{"canary"}},
+		{ID: "web-dr-1", Name: "web-dr", Address: "10.1.0.1", Port: 80, Tags: []string{"prod"}},
+	}
+	for _, svc := range services {
+		if err := serviceStore.Register(svc); err != nil {
+			t.Fatalf("register service: %v", err)
+		}
+	}
+	if _, err := queries.Set(store.PreparedQuery{Name: "web-prod", Service: "web", Tags: []string{"prod"}, Failover: []string{"web-dr"}}); err != nil {
+		t.Fatalf("set prepared query: %v", err)
+	}
+
+	query := func(qtype uint16, name string) []dns.RR {
+		msg := new(dns.Msg)
+		msg.SetQuestion(name, qtype)
+		writer := &mockResponseWriter{}
+		dnsServer.handleDNSRequest(writer, msg)
+		return writer.msg.Answer
+	}
+	addresses := func(answers []dns.RR) []string {
+		var found []string
+		for _, rr := range answers {
+			if a, ok := rr.(*dns.A); ok {
+				found = append(found, a.A.String())
+			}
+		}
+		return found
+	}
+
+	tests := []struct {
+		name  string
+		qtype uint16
+		want  int
+	}{
+		{"prod.web.service.consul.", dns.TypeA, 1},
+		{"canary.web.service.consul.", dns.TypeA, 1},
+		{"staging.web.service.consul.", dns.TypeA, 0},
+		{"_web._prod.service.consul.", dns.TypeSRV, 1},
+		{"_web._tcp.service.consul.", dns.TypeSRV, 2},
+		{"web.service.consul.", dns.TypeSRV, 2},
+		{"WEB.Service.CONSUL.", dns.TypeA, 0}, // service names are case-sensitive
+		{"web.service.other.", dns.TypeA, 0},
+		{"web-prod.query.consul.", dns.TypeA, 1},
+		{"web-prod.query.consul.", dns.TypeSRV, 1},
+		{"missing.query.consul.", dns.TypeA, 0},
+	}
+	for _, tt := range tests {
+		if answers := query(tt.qtype, tt.name); len(answers) != tt.want {
+			t.Errorf("%s %s: expected %d answers, got %d", dns.TypeToString[tt.qtype], tt.name, tt.want, len(answers))
+		}
+	}
+
+	if found := addresses(query(dns.TypeA, "web-prod.query.consul.")); len(found) != 1 || found[0] != "10.0.0.1" {
+		t.Errorf("expected the prod web instance, got %v", found)
+	}
+
+	// Without a prod web instance the query fails over to web-dr
+	serviceStore.Deregister("web-1")
+	if found := addresses(query(dns.TypeA, "web-prod.query.consul.")); len(found) != 1 || found[0] != "10.1.0.1" {
+		t.Errorf("expected the failover instance, got %v", found)
+	}
+}
@@ -33,10 +33,10 @@ func (l *ServiceLoader) batchFetch(ctx context.Context, keys []string) ([]*store
 	// Get all service entries at once
 	allEntries := l.serviceStore.ListAll()
 
-	// Create a map for quick lookup
+	// Create a map for quick lookup by instance ID
 	entryMap := make(map[string]*store.ServiceEntry)
 	for i := range allEntries {
-		entryMap[allEntries[i].Service.Name] = &allEntries[i]
+		entryMap[allEntries[i].Service.InstanceID()] = &allEntries[i]
 	}
 
 	// Populate results
@@ -49,20 +49,28 @@ type DirectiveRoot struct {
 
 type ComplexityRoot struct {
 	HealthCheck struct {
-		ID          func(childComplexity int) int
-		Interval    func(childComplexity int) int
-		LastChecked func(childComplexity int) int
-		Name        func(childComplexity int) int
-		Output      func(childComplexity int) int
-		ServiceID   func(childComplexity int) int
-		Status      func(childComplexity int) int
-		Timeout     func(childComplexity int) int
-		Type        func(childComplexity int) int
+		CriticalSince func(childComplexity int) int
+		ID            func(childComplexity int) int
+		Interval      func(childComplexity int) int
+		LastChecked   func(childComplexity int) int
+		Name          func(childComplexity int) int
+		Output        func(childComplexity int) int
+		ServiceID     func(childComplexity int) int
+		Status        func(childComplexity int) int
+		Timeout       func(childComplexity int) int
+		Type          func(childComplexity int) int
+	}
+
+	HealthCheckResult struct {
+		Output func(childComplexity int) int
+		Status func(childComplexity int) int
+		Time   func(childComplexity int) int
 	}
 
 	KVChangeEvent struct {
 		Key       func(childComplexity int) int
 		OldValue  func(childComplexity int) int
+		Secret    func(childComplexity int) int
 		Timestamp func(childComplexity int) int
 		Type      func(childComplexity int) int
 		Value     func(childComplexity int) int
@@ -76,7 +84,10 @@ type ComplexityRoot struct {
 
 	KVPair struct {
 		CreatedAt func(childComplexity int) int
+		ExpiresAt func(childComplexity int) int
 		Key       func(childComplexity int) int
+		Secret    func(childComplexity int) int
+		Session   func(childComplexity int) int
 		UpdatedAt func(childComplexity int) int
 		Value     func(childComplexity int) int
 	}
@@ -91,41 +102,70 @@ type ComplexityRoot struct {
 	}
 
 	Mutation struct {
-		DeregisterService func(childComplexity int, name string) int
-		KvCas             func(childComplexity int, key string, value string, index int) int
-		KvDelete          func(childComplexity int, key string) int
-		KvSet             func(childComplexity int, key string, value string) int
+		AcquireLock       func(childComplexity int, key string, session string, value string) int
+		CreateSession     func(childComplexity int, input *model.CreateSessionInput) int
+		DeleteWebhook     func(childComplexity int, name string) int
+		DeregisterService func(childComplexity int, name string, id *string, namespace *string) int
+		DestroySession    func(childComplexity int, id string) int
+		KvCas             func(childComplexity int, key string, value string, index int, ttl *string, namespace *string) int
+		KvDelete          func(childComplexity int, key string, namespace *string) int
+		KvSet             func(childComplexity int, key string, value string, ttl *string, namespace *string) int
 		RegisterService   func(childComplexity int, input model.RegisterServiceInput) int
-		UpdateHeartbeat   func(childComplexity int, name string) int
+		ReleaseLock       func(childComplexity int, key string, session string) int
+		RenewSession      func(childComplexity int, id string) int
+		SetWebhook        func(childComplexity int, input model.WebhookInput) int
+		UpdateHeartbeat   func(childComplexity int, name string, id *string, namespace *string) int
+	}
+
+	Namespace struct {
+		CreateIndex func(childComplexity int) int
+		CreatedAt   func(childComplexity int) int
+		Description func(childComplexity int) int
+		Metadata    func(childComplexity int) int
+		Name        func(childComplexity int) int
 	}
 
 	Query struct {
 		Health             func(childComplexity int) int
-		Kv                 func(childComplexity int, key string) int
-		KvList             func(childComplexity int, prefix *string, limit *int, offset *int) int
-		Service            func(childComplexity int, name string) int
-		Services           func(childComplexity int, limit *int, offset *int) int
-		ServicesByMetadata func(childComplexity int, filters []*model.MetadataFilter) int
-		ServicesByQuery    func(childComplexity int, tags []string, metadata []*model.MetadataFilter) int
-		ServicesByTags     func(childComplexity int, tags []string) int
+		HealthCheckHistory func(childComplexity int, id string) int
+		Kv                 func(childComplexity int, key string, namespace *string) int
+		KvList             func(childComplexity int, prefix *string, limit *int, offset *int, namespace *string) int
+		Namespaces         func(childComplexity int) int
+		Service            func(childComplexity int, name string, namespace *string) int
+		ServiceInstances   func(childComplexity int, name string, namespace *string, passing *bool) int
+		Services           func(childComplexity int, limit *int, offset *int, namespace *string, passing *bool) int
+		ServicesByMetadata func(childComplexity int, filters []*model.MetadataFilter, namespace *string) int
+		ServicesByQuery    func(childComplexity int, tags []string, metadata []*model.MetadataFilter, namespace *string) int
+		ServicesByTags     func(childComplexity int, tags []string, namespace *string) int
 		ServicesCount      func(childComplexity int) int
+		Session            func(childComplexity int, id string) int
+		Sessions           func(childComplexity int) int
+		Webhook            func(childComplexity int, name string) int
+		WebhookDeliveries  func(childComplexity int, name string) int
+		Webhooks           func(childComplexity int) int
 	}
 
 	Service struct {
 		Address   func(childComplexity int) int
 		Checks    func(childComplexity int) int
 		ExpiresAt func(childComplexity int) int
+		ID        func(childComplexity int) int
 		Metadata  func(childComplexity int) int
 		Name      func(childComplexity int) int
+		Namespace func(childComplexity int) int
+		Node      func(childComplexity int) int
 		Port      func(childComplexity int) int
 		Status    func(childComplexity int) int
 		Tags      func(childComplexity int) int
 	}
 
 	ServiceChangeEvent struct {
-		Service   func(childComplexity int) int
-		Timestamp func(childComplexity int) int
-		Type      func(childComplexity int) int
+		Check          func(childComplexity int) int
+		PreviousStatus func(childComplexity int) int
+		Service        func(childComplexity int) int
+		Status         func(childComplexity int) int
+		Timestamp      func(childComplexity int) int
+		Type           func(childComplexity int) int
 	}
 
 	ServiceStats struct {
@@ -134,9 +174,21 @@ type ComplexityRoot struct {
 		Total   func(childComplexity int) int
 	}
 
+	Session struct {
+		Behavior    func(childComplexity int) int
+		Checks      func(childComplexity int) int
+		CreateIndex func(childComplexity int) int
+		ExpiresAt   func(childComplexity int) int
+		ID          func(childComplexity int) int
+		ModifyIndex func(childComplexity int) int
+		Name        func(childComplexity int) int
+		Node        func(childComplexity int) int
+		TTL         func(childComplexity int) int
+	}
+
 	Subscription struct {
 		KvChanged      func(childComplexity int, key *string, prefix *string) int
-		ServiceChanged func(childComplexity int, name *string) int
+		ServiceChanged func(childComplexity int, name *string, namespace *string) int
 	}
 
 	SystemHealth struct {
@@ -147,30 +199,73 @@ type ComplexityRoot struct {
 		Uptime    func(childComplexity int) int
 		Version   func(childComplexity int) int
 	}
+
+	Webhook struct {
+		CreatedAt func(childComplexity int) int
+		Events    func(childComplexity int) int
+		Kind      func(childComplexity int) int
+		Name      func(childComplexity int) int
+		Namespace func(childComplexity int) int
+		Pattern   func(childComplexity int) int
+		Policies  func(childComplexity int) int
+		Signed    func(childComplexity int) int
+		URL       func(childComplexity int) int
+		UpdatedAt func(childComplexity int) int
+	}
+
+	WebhookDelivery struct {
+		Attempts    func(childComplexity int) int
+		CompletedAt func(childComplexity int) int
+		CreatedAt   func(childComplexity int) int
+		Error       func(childComplexity int) int
+		EventType   func(childComplexity int) int
+		ID          func(childComplexity int) int
+		Index       func(childComplexity int) int
+		Key         func(childComplexity int) int
+		NextAttempt func(childComplexity int) int
+		Status      func(childComplexity int) int
+		StatusCode  func(childComplexity int) int
+		Webhook     func(childComplexity int) int
+	}
 }
 
 type MutationResolver interface {
-	KvSet(ctx context.Context, key string, value string) (*model.KVPair, error)
-	KvDelete(ctx context.Context, key string) (bool, error)
-	KvCas(ctx context.Context, key string, value string, index int) (*model.KVPair, error)
+	KvSet(ctx context.Context, key string, value string, ttl *string, namespace *string) (*model.KVPair, error)
+	KvDelete(ctx context.Context, key string, namespace *string) (bool, error)
+	KvCas(ctx context.Context, key string, value string, index int, ttl *string, namespace *string) (*model.KVPair, error)
 	RegisterService(ctx context.Context, input model.RegisterServiceInput) (*model.Service, error)
-	DeregisterService(ctx context.Context, name string) (bool, error)
-	UpdateHeartbeat(ctx context.Context, name string) (*model.Service, error)
+	DeregisterService(ctx context.Context, name string, id *string, namespace *string) (bool, error)
+	UpdateHeartbeat(ctx context.Context, name string, id *string, namespace *string) (*model.Service, error)
+	CreateSession(ctx context.Context, input *model.CreateSessionInput) (*model.Session, error)
+	RenewSession(ctx context.Context, id string) (*model.Session, error)
+	DestroySession(ctx context.Context, id string) (bool, error)
+	AcquireLock(ctx context.Context, key string, session string, value string) (bool, error)
+	ReleaseLock(ctx context.Context, key string, session string) (bool, error)
+	SetWebhook(ctx context.Context, input model.WebhookInput) (*model.Webhook, error)
+	DeleteWebhook(ctx context.Context, name string) (bool, error)
 }
 type QueryResolver interface {
 	Health(ctx context.Context) (*model.SystemHealth, error)
-	Kv(ctx context.Context, key string) (*model.KVPair, error)
-	KvList(ctx context.Context, prefix *string, limit *int, offset *int) (*model.KVListResponse, error)
-	Service(ctx context.Context, name string) (*model.Service, error)
-	Services(ctx context.Context, limit *int, offset *int) ([]*model.Service, error)
+	Kv(ctx context.Context, key string, namespace *string) (*model.KVPair, error)
+	KvList(ctx context.Context, prefix *string, limit *int, offset *int, namespace *string) (*model.KVListResponse, error)
+	Service(ctx context.Context, name string, namespace *string) (*model.Service, error)
+	ServiceInstances(ctx context.Context, name string, namespace *string, passing *bool) ([]*model.Service, error)
+	Services(ctx context.Context, limit *int, offset *int, namespace *string, passing *bool) ([]*model.Service, error)
 	ServicesCount(ctx context.Context) (int, error)
-	ServicesByTags(ctx context.Context, tags []string) ([]*model.Service, error)
-	ServicesByMetadata(ctx context.Context, filters []*model.MetadataFilter) ([]*model.Service, error)
-	ServicesByQuery(ctx context.Context, tags []string, metadata []*model.MetadataFilter) ([]*model.Service, error)
+	ServicesByTags(ctx context.Context, tags []string, namespace *string) ([]*model.Service, error)
+	ServicesByMetadata(ctx context.Context, filters []*model.MetadataFilter, namespace *string) ([]*model.Service, error)
+	ServicesByQuery(ctx context.Context, tags []string, metadata []*model.MetadataFilter, namespace *string) ([]*model.Service, error)
+	HealthCheckHistory(ctx context.Context, id string) ([]*model.HealthCheckResult, error)
+	Namespaces(ctx context.Context) ([]*model.Namespace, error)
+	Webhooks(ctx context.Context) ([]*model.Webhook, error)
+	Webhook(ctx context.Context, name string) (*model.Webhook, error)
+	WebhookDeliveries(ctx context.Context, name string) ([]*model.WebhookDelivery, error)
+	Session(ctx context.Context, id string) (*model.Session, error)
+	Sessions(ctx context.Context) ([]*model.Session, error)
 }
 type SubscriptionResolver interface {
 	KvChanged(ctx context.Context, key *string, prefix *string) (<-chan *model.KVChangeEvent, error)
-	ServiceChanged(ctx context.Context, name *string) (<-chan *model.ServiceChangeEvent, error)
+	ServiceChanged(ctx context.Context, name *string, namespace *string) (<-chan *model.ServiceChangeEvent, error)
 }
 
 type executableSchema struct {
@@ -192,6 +287,12 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 	_ = ec
 	switch typeName + "." + field {
 
+	case "HealthCheck.criticalSince":
+		if e.complexity.HealthCheck.CriticalSince == nil {
+			break
+		}
+
+		return e.complexity.HealthCheck.CriticalSince(childComplexity), true
 	case "HealthCheck.id":
 		if e.complexity.HealthCheck.ID == nil {
 			break
@@ -247,6 +348,25 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.HealthCheck.Type(childComplexity), true
 
+	case "HealthCheckResult.output":
+		if e.complexity.HealthCheckResult.Output == nil {
+			break
+		}
+
+		return e.complexity.HealthCheckResult.Output(childComplexity), true
+	case "HealthCheckResult.status":
+		if e.complexity.HealthCheckResult.Status == nil {
+			break
+		}
+
+		return e.complexity.HealthCheckResult.Status(childComplexity), true
+	case "HealthCheckResult.time":
+		if e.complexity.HealthCheckResult.Time == nil {
+			break
+		}
+
+		return e.complexity.HealthCheckResult.Time(childComplexity), true
+
 	case "KVChangeEvent.key":
 		if e.complexity.KVChangeEvent.Key == nil {
 			break
@@ -259,6 +379,12 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.complexity.KVChangeEvent.OldValue(childComplexity), true
+	case "KVChangeEvent.secret":
+		if e.complexity.KVChangeEvent.Secret == nil {
+			break
+		}
+
+		return e.complexity.KVChangeEvent.Secret(childComplexity), true
 	case "KVChangeEvent.timestamp":
 		if e.complexity.KVChangeEvent.Timestamp == nil {
 			break
@@ -303,12 +429,30 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.complexity.KVPair.CreatedAt(childComplexity), true
+	case "KVPair.expiresAt":
+		if e.complexity.KVPair.ExpiresAt == nil {
+			break
+		}
+
+		return e.complexity.KVPair.ExpiresAt(childComplexity), true
 	case "KVPair.key":
 		if e.complexity.KVPair.Key == nil {
 			break
 		}
 
 		return e.complexity.KVPair.Key(childComplexity), true
+	case "KVPair.secret":
+		if e.complexity.KVPair.Secret == nil {
+			break
+		}
+
+		return e.complexity.KVPair.Secret(childComplexity), true
+	case "KVPair.session":
+		if e.complexity.KVPair.Session == nil {
+			break
+		}
+
+		return e.complexity.KVPair.Session(childComplexity), true
 	case "KVPair.updatedAt":
 		if e.complexity.KVPair.UpdatedAt == nil {
 			break
@@ -342,6 +486,39 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.MetadataEntry.Value(childComplexity), true
 
+	case "Mutation.acquireLock":
+		if e.complexity.Mutation.AcquireLock == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_acquireLock_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.AcquireLock(childComplexity, args["key"].(string), args["session"].(string), args["value"].(string)), true
+	case "Mutation.createSession":
+		if e.complexity.Mutation.CreateSession == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_createSession_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.CreateSession(childComplexity, args["input"].(*model.CreateSessionInput)), true
+	case "Mutation.deleteWebhook":
+		if e.complexity.Mutation.DeleteWebhook == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_deleteWebhook_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.DeleteWebhook(childComplexity, args["name"].(string)), true
 	case "Mutation.deregisterService":
 		if e.complexity.Mutation.DeregisterService == nil {
 			break
@@ -352,7 +529,18 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Mutation.DeregisterService(childComplexity, args["name"].(string)), true
+		return e.complexity.Mutation.DeregisterService(childComplexity, args["name"].(string), args["id"].(*string), args["namespace"].(*string)), true
+	case "Mutation.destroySession":
+		if e.complexity.Mutation.DestroySession == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_destroySession_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.DestroySession(childComplexity, args["id"].(string)), true
 	case "Mutation.kvCAS":
 		if e.complexity.Mutation.KvCas == nil {
 			break
@@ -363,7 +551,7 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Mutation.KvCas(childComplexity, args["key"].(string), args["value"].(string), args["index"].(int)), true
+		return e.complexity.Mutation.KvCas(childComplexity, args["key"].(string), args["value"].(string), args["index"].(int), args["ttl"].(*string), args["namespace"].(*string)), true
 	case "Mutation.kvDelete":
 		if e.complexity.Mutation.KvDelete == nil {
 			break
@@ -374,7 +562,7 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Mutation.KvDelete(childComplexity, args["key"].(string)), true
+		return e.complexity.Mutation.KvDelete(childComplexity, args["key"].(string), args["namespace"].(*string)), true
 	case "Mutation.kvSet":
 		if e.complexity.Mutation.KvSet == nil {
 			break
@@ -385,7 +573,7 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Mutation.KvSet(childComplexity, args["key"].(string), args["value"].(string)), true
+		return e.complexity.Mutation.KvSet(childComplexity, args["key"].(string), args["value"].(string), args["ttl"].(*string), args["namespace"].(*string)), true
 	case "Mutation.registerService":
 		if e.complexity.Mutation.RegisterService == nil {
 			break
@@ -397,6 +585,39 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.complexity.Mutation.RegisterService(childComplexity, args["input"].(model.RegisterServiceInput)), true
+	case "Mutation.releaseLock":
+		if e.complexity.Mutation.ReleaseLock == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_releaseLock_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.ReleaseLock(childComplexity, args["key"].(string), args["session"].(string)), true
+	case "Mutation.renewSession":
+		if e.complexity.Mutation.RenewSession == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_renewSession_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.RenewSession(childComplexity, args["id"].(string)), true
+	case "Mutation.setWebhook":
+		if e.complexity.Mutation.SetWebhook == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_setWebhook_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.SetWebhook(childComplexity, args["input"].(model.WebhookInput)), true
 	case "Mutation.updateHeartbeat":
 		if e.complexity.Mutation.UpdateHeartbeat == nil {
 			break
@@ -407,7 +628,38 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Mutation.UpdateHeartbeat(childComplexity, args["name"].(string)), true
+		return e.complexity.Mutation.UpdateHeartbeat(childComplexity, args["name"].(string), args["id"].(*string), args["namespace"].(*string)), true
+
+	case "Namespace.createIndex":
+		if e.complexity.Namespace.CreateIndex == nil {
+			break
+		}
+
+		return e.complexity.Namespace.CreateIndex(childComplexity), true
+	case "Namespace.createdAt":
+		if e.complexity.Namespace.CreatedAt == nil {
+			break
+		}
+
+		return e.complexity.Namespace.CreatedAt(childComplexity), true
+	case "Namespace.description":
+		if e.complexity.Namespace.Description == nil {
+			break
+		}
+
+		return e.complexity.Namespace.Description(childComplexity), true
+	case "Namespace.metadata":
+		if e.complexity.Namespace.Metadata == nil {
+			break
+		}
+
+		return e.complexity.Namespace.Metadata(childComplexity), true
+	case "Namespace.name":
+		if e.complexity.Namespace.Name == nil {
+			break
+		}
+
+		return e.complexity.Namespace.Name(childComplexity), true
 
 	case "Query.health":
 		if e.complexity.Query.Health == nil {
@@ -415,6 +667,17 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.complexity.Query.Health(childComplexity), true
+	case "Query.healthCheckHistory":
+		if e.complexity.Query.HealthCheckHistory == nil {
+			break
+		}
+
+		args, err := ec.field_Query_healthCheckHistory_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.HealthCheckHistory(childComplexity, args["id"].(string)), true
 	case "Query.kv":
 		if e.complexity.Query.Kv == nil {
 			break
@@ -425,7 +688,7 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Query.Kv(childComplexity, args["key"].(string)), true
+		return e.complexity.Query.Kv(childComplexity, args["key"].(string), args["namespace"].(*string)), true
 	case "Query.kvList":
 		if e.complexity.Query.KvList == nil {
 			break
@@ -436,7 +699,13 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Query.KvList(childComplexity, args["prefix"].(*string), args["limit"].(*int), args["offset"].(*int)), true
+		return e.complexity.Query.KvList(childComplexity, args["prefix"].(*string), args["limit"].(*int), args["offset"].(*int), args["namespace"].(*string)), true
+	case "Query.namespaces":
+		if e.complexity.Query.Namespaces == nil {
+			break
+		}
+
+		return e.complexity.Query.Namespaces(childComplexity), true
 	case "Query.service":
 		if e.complexity.Query.Service == nil {
 			break
@@ -447,7 +716,18 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Query.Service(childComplexity, args["name"].(string)), true
+		return e.complexity.Query.Service(childComplexity, args["name"].(string), args["namespace"].(*string)), true
+	case "Query.serviceInstances":
+		if e.complexity.Query.ServiceInstances == nil {
+			break
+		}
+
+		args, err := ec.field_Query_serviceInstances_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.ServiceInstances(childComplexity, args["name"].(string), args["namespace"].(*string), args["passing"].(*bool)), true
 	case "Query.services":
 		if e.complexity.Query.Services == nil {
 			break
@@ -458,7 +738,7 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Query.Services(childComplexity, args["limit"].(*int), args["offset"].(*int)), true
+		return e.complexity.Query.Services(childComplexity, args["limit"].(*int), args["offset"].(*int), args["namespace"].(*string), args["passing"].(*bool)), true
 	case "Query.servicesByMetadata":
 		if e.complexity.Query.ServicesByMetadata == nil {
 			break
@@ -469,7 +749,7 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Query.ServicesByMetadata(childComplexity, args["filters"].([]*model.MetadataFilter)), true
+		return e.complexity.Query.ServicesByMetadata(childComplexity, args["filters"].([]*model.MetadataFilter), args["namespace"].(*string)), true
 	case "Query.servicesByQuery":
 		if e.complexity.Query.ServicesByQuery == nil {
 			break
@@ -480,7 +760,7 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Query.ServicesByQuery(childComplexity, args["tags"].([]string), args["metadata"].([]*model.MetadataFilter)), true
+		return e.complexity.Query.ServicesByQuery(childComplexity, args["tags"].([]string), args["metadata"].([]*model.MetadataFilter), args["namespace"].(*string)), true
 	case "Query.servicesByTags":
 		if e.complexity.Query.ServicesByTags == nil {
 			break
@@ -491,13 +771,58 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Query.ServicesByTags(childComplexity, args["tags"].([]string)), true
+		return e.complexity.Query.ServicesByTags(childComplexity, args["tags"].([]string), args["namespace"].(*string)), true
 	case "Query.servicesCount":
 		if e.complexity.Query.ServicesCount == nil {
 			break
 		}
 
 		return e.complexity.Query.ServicesCount(childComplexity), true
+	case "Query.session":
+		if e.complexity.Query.Session == nil {
+			break
+		}
+
+		args, err := ec.field_Query_session_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.Session(childComplexity, args["id"].(string)), true
+	case "Query.sessions":
+		if e.complexity.Query.Sessions == nil {
+			break
+		}
+
+		return e.complexity.Query.Sessions(childComplexity), true
+	case "Query.webhook":
+		if e.complexity.Query.Webhook == nil {
+			break
+		}
+
+		args, err := ec.field_Query_webhook_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.Webhook(childComplexity, args["name"].(string)), true
+	case "Query.webhookDeliveries":
+		if e.complexity.Query.WebhookDeliveries == nil {
+			break
+		}
+
+		args, err := ec.field_Query_webhookDeliveries_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.WebhookDeliveries(childComplexity, args["name"].(string)), true
+	case "Query.webhooks":
+		if e.complexity.Query.Webhooks == nil {
+			break
+		}
+
+		return e.complexity.Query.Webhooks(childComplexity), true
 
 	case "Service.address":
 		if e.complexity.Service.Address == nil {
@@ -517,6 +842,12 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.complexity.Service.ExpiresAt(childComplexity), true
+	case "Service.id":
+		if e.complexity.Service.ID == nil {
+			break
+		}
+
+		return e.complexity.Service.ID(childComplexity), true
 	case "Service.metadata":
 		if e.complexity.Service.Metadata == nil {
 			break
@@ -529,6 +860,18 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.complexity.Service.Name(childComplexity), true
+	case "Service.namespace":
+		if e.complexity.Service.Namespace == nil {
+			break
+		}
+
+		return e.complexity.Service.Namespace(childComplexity), true
+	case "Service.node":
+		if e.complexity.Service.Node == nil {
+			break
+		}
+
+		return e.complexity.Service.Node(childComplexity), true
 	case "Service.port":
 		if e.complexity.Service.Port == nil {
 			break
@@ -548,12 +891,30 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Service.Tags(childComplexity), true
 
+	case "ServiceChangeEvent.check":
+		if e.complexity.ServiceChangeEvent.Check == nil {
+			break
+		}
+
+		return e.complexity.ServiceChangeEvent.Check(childComplexity), true
+	case "ServiceChangeEvent.previousStatus":
+		if e.complexity.ServiceChangeEvent.PreviousStatus == nil {
+			break
+		}
+
+		return e.complexity.ServiceChangeEvent.PreviousStatus(childComplexity), true
 	case "ServiceChangeEvent.service":
 		if e.complexity.ServiceChangeEvent.Service == nil {
 			break
 		}
 
 		return e.complexity.ServiceChangeEvent.Service(childComplexity), true
+	case "ServiceChangeEvent.status":
+		if e.complexity.ServiceChangeEvent.Status == nil {
+			break
+		}
+
+		return e.complexity.ServiceChangeEvent.Status(childComplexity), true
 	case "ServiceChangeEvent.timestamp":
 		if e.complexity.ServiceChangeEvent.Timestamp == nil {
 			break
@@ -586,6 +947,61 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.ServiceStats.Total(childComplexity), true
 
+	case "Session.behavior":
+		if e.complexity.Session.Behavior == nil {
+			break
+		}
+
+		return e.complexity.Session.Behavior(childComplexity), true
+	case "Session.checks":
+		if e.complexity.Session.Checks == nil {
+			break
+		}
+
+		return e.complexity.Session.Checks(childComplexity), true
+	case "Session.createIndex":
+		if e.complexity.Session.CreateIndex == nil {
+			break
+		}
+
+		return e.complexity.Session.CreateIndex(childComplexity), true
+	case "Session.expiresAt":
+		if e.complexity.Session.ExpiresAt == nil {
+			break
+		}
+
+		return e.complexity.Session.ExpiresAt(childComplexity), true
+	case "Session.id":
+		if e.complexity.Session.ID == nil {
+			break
+		}
+
+		return e.complexity.Session.ID(childComplexity), true
+	case "Session.modifyIndex":
+		if e.complexity.Session.ModifyIndex == nil {
+			break
+		}
+
+		return e.complexity.Session.ModifyIndex(childComplexity), true
+	case "Session.name":
+		if e.complexity.Session.Name == nil {
+			break
+		}
+
+		return e.complexity.Session.Name(childComplexity), true
+	case "Session.node":
+		if e.complexity.Session.Node == nil {
+			break
+		}
+
+		return e.complexity.Session.Node(childComplexity), true
+	case "Session.ttl":
+		if e.complexity.Session.TTL == nil {
+			break
+		}
+
+		return e.complexity.Session.TTL(childComplexity), true
+
 	case "Subscription.kvChanged":
 		if e.complexity.Subscription.KvChanged == nil {
 			break
@@ -607,7 +1023,7 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Subscription.ServiceChanged(childComplexity, args["name"].(*string)), true
+		return e.complexity.Subscription.ServiceChanged(childComplexity, args["name"].(*string), args["namespace"].(*string)), true
 
 	case "SystemHealth.kvStore":
 		if e.complexity.SystemHealth.KvStore == nil {
@@ -646,55 +1062,191 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.SystemHealth.Version(childComplexity), true
 
-	}
-	return 0, false
-}
+	case "Webhook.createdAt":
+		if e.complexity.Webhook.CreatedAt == nil {
+			break
+		}
 
-func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
-	opCtx := graphql.GetOperationContext(ctx)
-	ec := executionContext{opCtx, e, 0, 0, make(chan graphql.DeferredResult)}
-	inputUnmarshalMap := graphql.BuildUnmarshalerMap(
-		ec.unmarshalInputMetadataFilter,
-		ec.unmarshalInputMetadataInput,
-		ec.unmarshalInputRegisterServiceInput,
-	)
-	first := true
+		return e.complexity.Webhook.CreatedAt(childComplexity), true
+	case "Webhook.events":
+		if e.complexity.Webhook.Events == nil {
+			break
+		}
 
-	switch opCtx.Operation.Operation {
-	case ast.Query:
-		return func(ctx context.Context) *graphql.Response {
-			var response graphql.Response
-			var data graphql.Marshaler
-			if first {
-				first = false
-				ctx = graphql.WithUnmarshalerMap(ctx, inputUnmarshalMap)
-				data = ec._Query(ctx, opCtx.Operation.SelectionSet)
-			} else {
-				if atomic.LoadInt32(&ec.pendingDeferred) > 0 {
-					result := <-ec.deferredResults
-					atomic.AddInt32(&ec.pendingDeferred, -1)
-					data = result.Result
-					response.Path = result.Path
-					response.Label = result.Label
-					response.Errors = result.Errors
-				} else {
-					return nil
-				}
-			}
-			var buf bytes.Buffer
-			data.MarshalGQL(&buf)
-			response.Data = buf.Bytes()
-			if atomic.LoadInt32(&ec.deferred) > 0 {
-				hasNext := atomic.LoadInt32(&ec.pendingDeferred) > 0
-				response.HasNext = &hasNext
-			}
+		return e.complexity.Webhook.Events(childComplexity), true
+	case "Webhook.kind":
+		if e.complexity.Webhook.Kind == nil {
+			break
+		}
 
-			return &response
+		return e.complexity.Webhook.Kind(childComplexity), true
+	case "Webhook.name":
+		if e.complexity.Webhook.Name == nil {
+			break
 		}
-	case ast.Mutation:
-		return func(ctx context.Context) *graphql.Response {
-			if !first {
-				return nil
+
+		return e.complexity.Webhook.Name(childComplexity), true
+	case "Webhook.namespace":
+		if e.complexity.Webhook.Namespace == nil {
+			break
+		}
+
+		return e.complexity.Webhook.Namespace(childComplexity), true
+	case "Webhook.pattern":
+		if e.complexity.Webhook.Pattern == nil {
+			break
+		}
+
+		return e.complexity.Webhook.Pattern(childComplexity), true
+	case "Webhook.policies":
+		if e.complexity.Webhook.Policies == nil {
+			break
+		}
+
+		return e.complexity.Webhook.Policies(childComplexity), true
+	case "Webhook.signed":
+		if e.complexity.Webhook.Signed == nil {
+			break
+		}
+
+		return e.complexity.Webhook.Signed(childComplexity), true
+	case "Webhook.url":
+		if e.complexity.Webhook.URL == nil {
+			break
+		}
+
+		return e.complexity.Webhook.URL(childComplexity), true
+	case "Webhook.updatedAt":
+		if e.complexity.Webhook.UpdatedAt == nil {
+			break
+		}
+
+		return e.complexity.Webhook.UpdatedAt(childComplexity), true
+
+	case "WebhookDelivery.attempts":
+		if e.complexity.WebhookDelivery.Attempts == nil {
+			break
+		}
+
+		return e.complexity.WebhookDelivery.Attempts(childComplexity), true
+	case "WebhookDelivery.completedAt":
+		if e.complexity.WebhookDelivery.CompletedAt == nil {
+			break
+		}
+
+		return e.complexity.WebhookDelivery.CompletedAt(childComplexity), true
+	case "WebhookDelivery.createdAt":
+		if e.complexity.WebhookDelivery.CreatedAt == nil {
+			break
+		}
+
+		return e.complexity.WebhookDelivery.CreatedAt(childComplexity), true
+	case "WebhookDelivery.error":
+		if e.complexity.WebhookDelivery.Error == nil {
+			break
+		}
+
+		return e.complexity.WebhookDelivery.Error(childComplexity), true
+	case "WebhookDelivery.eventType":
+		if e.complexity.WebhookDelivery.EventType == nil {
+			break
+		}
+
+		return e.complexity.WebhookDelivery.EventType(childComplexity), true
+	case "WebhookDelivery.id":
+		if e.complexity.WebhookDelivery.ID == nil {
+			break
+		}
+
+		return e.complexity.WebhookDelivery.ID(childComplexity), true
+	case "WebhookDelivery.index":
+		if e.complexity.WebhookDelivery.Index == nil {
+			break
+		}
+
+		return e.complexity.WebhookDelivery.Index(childComplexity), true
+	case "WebhookDelivery.key":
+		if e.complexity.WebhookDelivery.Key == nil {
+			break
+		}
+
+		return e.complexity.WebhookDelivery.Key(childComplexity), true
+	case "WebhookDelivery.nextAttempt":
+		if e.complexity.WebhookDelivery.NextAttempt == nil {
+			break
+		}
+
+		return e.complexity.WebhookDelivery.NextAttempt(childComplexity), true
+	case "WebhookDelivery.status":
+		if e.complexity.WebhookDelivery.Status == nil {
+			break
+		}
+
+		return e.complexity.WebhookDelivery.Status(childComplexity), true
+	case "WebhookDelivery.statusCode":
+		if e.complexity.WebhookDelivery.StatusCode == nil {
+			break
+		}
+
+		return e.complexity.WebhookDelivery.StatusCode(childComplexity), true
+	case "WebhookDelivery.webhook":
+		if e.complexity.WebhookDelivery.Webhook == nil {
+			break
+		}
+
+		return e.complexity.WebhookDelivery.Webhook(childComplexity), true
+
+	}
+	return 0, false
+}
+
+func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
+	opCtx := graphql.GetOperationContext(ctx)
+	ec := executionContext{opCtx, e, 0, 0, make(chan graphql.DeferredResult)}
+	inputUnmarshalMap := graphql.BuildUnmarshalerMap(
+		ec.unmarshalInputCreateSessionInput,
+		ec.unmarshalInputMetadataFilter,
+		ec.unmarshalInputMetadataInput,
+		ec.unmarshalInputRegisterServiceInput,
+		ec.unmarshalInputWebhookInput,
+	)
+	first := true
+
+	switch opCtx.Operation.Operation {
+	case ast.Query:
+		return func(ctx context.Context) *graphql.Response {
+			var response graphql.Response
+			var data graphql.Marshaler
+			if first {
+				first = false
+				ctx = graphql.WithUnmarshalerMap(ctx, inputUnmarshalMap)
+				data = ec._Query(ctx, opCtx.Operation.SelectionSet)
+			} else {
+				if atomic.LoadInt32(&ec.pendingDeferred) > 0 {
+					result := <-ec.deferredResults
+					atomic.AddInt32(&ec.pendingDeferred, -1)
+					data = result.Result
+					response.Path = result.Path
+					response.Label = result.Label
+					response.Errors = result.Errors
+				} else {
+					return nil
+				}
+			}
+			var buf bytes.Buffer
+			data.MarshalGQL(&buf)
+			response.Data = buf.Bytes()
+			if atomic.LoadInt32(&ec.deferred) > 0 {
+				hasNext := atomic.LoadInt32(&ec.pendingDeferred) > 0
+				response.HasNext = &hasNext
+			}
+
+			return &response
+		}
+	case ast.Mutation:
+		return func(ctx context.Context) *graphql.Response {
+			if !first {
+				return nil
 			}
 			first = false
 			ctx = graphql.WithUnmarshalerMap(ctx, inputUnmarshalMap)
@@ -833,14 +1385,24 @@ type KVPair {
   """The key"""
   key: String!
 
-  """The value"""
+  """The value, empty for secrets"""
   value: String!
 
+  """Whether the value is a secret. Secrets are never returned over GraphQL;
+  read them from /kv with the decrypt capability"""
+  secret: Boolean!
+
   """Creation timestamp"""
   createdAt: Time
 
   """Last modification timestamp"""
   updatedAt: Time
+
+  """ID of the session holding the lock on this key"""
+  session: String
+
+  """When the key is deleted, if it was set with a TTL"""
+  expiresAt: Time
 }
 
 """
@@ -873,6 +1435,9 @@ type KVChangeEvent {
   """The previous value (if available)"""
   oldValue: String
 
+  """Whether the new value is a secret, whose value is then omitted"""
+  secret: Boolean!
+
   """Timestamp of the change"""
   timestamp: Time!
 }
@@ -887,6 +1452,26 @@ enum KVEventType {
   """Key was deleted"""
   DELETE
 }
+`, BuiltIn: false},
+	{Name: "../schema/namespace.graphql", Input: `"""
+Namespace isolates the KV keys and services of one tenant
+"""
+type Namespace {
+  """Namespace name"""
+  name: String!
+
+  """Human readable description"""
+  description: String
+
+  """Namespace metadata"""
+  metadata: [MetadataEntry!]!
+
+  """Index at which the namespace was created"""
+  createIndex: Int!
+
+  """Creation timestamp"""
+  createdAt: Time
+}
 `, BuiltIn: false},
 	{Name: "../schema/schema.graphql", Input: `# Root types
 schema {
@@ -900,18 +1485,37 @@ type Query {
   health: SystemHealth!
 
   # KV Store queries
-  kv(key: String!): KVPair
-  kvList(prefix: String, limit: Int, offset: Int): KVListResponse!
+  kv(key: String!, namespace: String): KVPair
+  kvList(prefix: String, limit: Int, offset: Int, namespace: String): KVListResponse!
 
   # Service Discovery queries
-  service(name: String!): Service
-  services(limit: Int, offset: Int): [Service!]!
+  service(name: String!, namespace: String): Service
+  # Instances with critical checks are left out; passing: true also leaves
+  # out instances with warning checks
+  serviceInstances(name: String!, namespace: String, passing: Boolean): [Service!]!
+  services(limit: Int, offset: Int, namespace: String, passing: Boolean): [Service!]!
   servicesCount: Int!
 
   # Service queries by tags and metadata
-  servicesByTags(tags: [String!]!): [Service!]!
-  servicesByMetadata(filters: [MetadataFilter!]!): [Service!]!
-  servicesByQuery(tags: [String!], metadata: [MetadataFilter!]): [Service!]!
+  servicesByTags(tags: [String!]!, namespace: String): [Service!]!
+  servicesByMetadata(filters: [MetadataFilter!]!, namespace: String): [Service!]!
+  servicesByQuery(tags: [String!], metadata: [MetadataFilter!], namespace: String): [Service!]!
+
+  # Recent results of a health check, oldest first
+  healthCheckHistory(id: String!): [HealthCheckResult!]
+
+  # Namespaces
+  namespaces: [Namespace!]!
+
+  # Webhooks of the node, and the pending and recent deliveries of one,
+  # newest first
+  webhooks: [Webhook!]!
+  webhook(name: String!): Webhook
+  webhookDeliveries(name: String!): [WebhookDelivery!]
+
+  # Sessions
+  session(id: String!): Session
+  sessions: [Session!]!
 }
 
 """
@@ -930,14 +1534,25 @@ Mutation type for write operations
 """
 type Mutation {
   # KV Store mutations
-  kvSet(key: String!, value: String!): KVPair!
-  kvDelete(key: String!): Boolean!
-  kvCAS(key: String!, value: String!, index: Int!): KVPair
+  kvSet(key: String!, value: String!, ttl: String, namespace: String): KVPair!
+  kvDelete(key: String!, namespace: String): Boolean!
+  kvCAS(key: String!, value: String!, index: Int!, ttl: String, namespace: String): KVPair
 
   # Service mutations
   registerService(input: RegisterServiceInput!): Service!
-  deregisterService(name: String!): Boolean!
-  updateHeartbeat(name: String!): Service!
+  deregisterService(name: String!, id: String, namespace: String): Boolean!
+  updateHeartbeat(name: String!, id: String, namespace: String): Service!
+
+  # Session and lock mutations
+  createSession(input: CreateSessionInput): Session!
+  renewSession(id: String!): Session!
+  destroySession(id: String!): Boolean!
+  acquireLock(key: String!, session: String!, value: String!): Boolean!
+  releaseLock(key: String!, session: String!): Boolean!
+
+  # Webhook mutations
+  setWebhook(input: WebhookInput!): Webhook!
+  deleteWebhook(name: String!): Boolean!
 }
 
 """
@@ -947,17 +1562,27 @@ type Subscription {
   # KV Store change events
   kvChanged(key: String, prefix: String): KVChangeEvent!
 
-  # Service change events
-  serviceChanged(name: String): ServiceChangeEvent!
+  # Service change events: instances registered and deregistered, and
+  # health checks changing status
+  serviceChanged(name: String, namespace: String): ServiceChangeEvent!
 }
 `, BuiltIn: false},
 	{Name: "../schema/service.graphql", Input: `"""
-Service represents a registered service in the service registry
+Service represents a registered service instance in the service registry
 """
 type Service {
-  """Service name (unique identifier)"""
+  """Instance ID (unique identifier, defaults to the service name)"""
+  id: String!
+
+  """Service name (shared by all instances of the service)"""
   name: String!
 
+  """Namespace the instance is registered in"""
+  namespace: String!
+
+  """Node the instance runs on"""
+  node: String
+
   """Service IP address or hostname"""
   address: String!
 
@@ -1015,7 +1640,7 @@ type HealthCheck {
   """Check name"""
   name: String!
 
-  """Check type (http, tcp, grpc, ttl)"""
+  """Check type (http, tcp, grpc, udp, script, ttl)"""
   type: HealthCheckType!
 
   """Current status"""
@@ -1032,6 +1657,23 @@ type HealthCheck {
 
   """Last check time"""
   lastChecked: Time
+
+  """Time the check turned critical, while it is critical"""
+  criticalSince: Time
+}
+
+"""
+Result of one run of a health check
+"""
+type HealthCheckResult {
+  """Status the run reported"""
+  status: HealthCheckStatus!
+
+  """Output of the run"""
+  output: String!
+
+  """Time of the run"""
+  time: Time!
 }
 
 """
@@ -1041,6 +1683,8 @@ enum HealthCheckType {
   HTTP
   TCP
   GRPC
+  UDP
+  SCRIPT
   TTL
 }
 
@@ -1057,9 +1701,18 @@ enum HealthCheckStatus {
 Input type for registering a service
 """
 input RegisterServiceInput {
-  """Service name (unique identifier)"""
+  """Instance ID (optional, defaults to the service name)"""
+  id: String
+
+  """Service name"""
   name: String!
 
+  """Namespace to register in (optional, defaults to the default namespace)"""
+  namespace: String
+
+  """Node the instance runs on (optional)"""
+  node: String
+
   """Service IP address or hostname"""
   address: String!
 
@@ -1094,6 +1747,15 @@ type ServiceChangeEvent {
   """The service that changed"""
   service: Service!
 
+  """Check that changed status, for HEALTH events"""
+  check: String
+
+  """New status of the check, for HEALTH events"""
+  status: HealthCheckStatus
+
+  """Previous status of the check, for HEALTH events"""
+  previousStatus: HealthCheckStatus
+
   """Timestamp of the change"""
   timestamp: Time!
 }
@@ -1110,6 +1772,196 @@ enum ServiceEventType {
 
   """Service heartbeat updated"""
   HEARTBEAT
+
+  """A health check of the service changed status"""
+  HEALTH
+}
+`, BuiltIn: false},
+	{Name: "../schema/session.graphql", Input: `"""
+Session is a lease that can hold locks on KV keys
+"""
+type Session {
+  """Unique session ID"""
+  id: String!
+
+  """Optional human-readable name"""
+  name: String
+
+  """Node the session belongs to"""
+  node: String
+
+  """Session TTL (e.g. 15s); empty if the session never expires"""
+  ttl: String
+
+  """What happens to held locks when the session is invalidated"""
+  behavior: SessionBehavior!
+
+  """IDs of linked health checks"""
+  checks: [String!]!
+
+  """Index at which the session was created"""
+  createIndex: Int!
+
+  """Index of the last renewal"""
+  modifyIndex: Int!
+
+  """When the session expires without a renewal"""
+  expiresAt: Time
+}
+
+"""
+Session behaviors on invalidation
+"""
+enum SessionBehavior {
+  """Release held locks and keep the keys"""
+  RELEASE
+
+  """Delete keys whose lock the session holds"""
+  DELETE
+}
+
+"""
+Input for creating a session
+"""
+input CreateSessionInput {
+  """Optional human-readable name"""
+  name: String
+
+  """Node the session belongs to"""
+  node: String
+
+  """Session TTL between 10s and 24h"""
+  ttl: String
+
+  """Behavior on invalidation (default RELEASE)"""
+  behavior: SessionBehavior
+
+  """IDs of health checks that invalidate the session when critical"""
+  checks: [String!]
+}
+`, BuiltIn: false},
+	{Name: "../schema/webhook.graphql", Input: `"""
+Webhook POSTs the watch events of the KV keys or services matching a pattern
+to a URL
+"""
+type Webhook {
+  """Webhook name"""
+  name: String!
+
+  """URL the events are POSTed to"""
+  url: String!
+
+  """Whether requests carry an HMAC-SHA256 signature header"""
+  signed: Boolean!
+
+  """Kind of resource watched"""
+  kind: WebhookKind!
+
+  """Namespace of the services watched"""
+  namespace: String
+
+  """Key or service name pattern, as for watches"""
+  pattern: String!
+
+  """Event types delivered; all when empty"""
+  events: [String!]!
+
+  """Policies events are filtered with"""
+  policies: [String!]!
+
+  """Creation timestamp"""
+  createdAt: Time
+
+  """Last update timestamp"""
+  updatedAt: Time
+}
+
+"""
+Kind of resource a webhook watches
+"""
+enum WebhookKind {
+  KV
+  SERVICE
+}
+
+"""
+Delivery of an event to a webhook
+"""
+type WebhookDelivery {
+  """Delivery ID, sent in the X-Konsul-Delivery header"""
+  id: String!
+
+  """Webhook name"""
+  webhook: String!
+
+  """Type of the event delivered"""
+  eventType: String!
+
+  """Key or service name of the event"""
+  key: String!
+
+  """Store index of the event"""
+  index: Int!
+
+  """Delivery status"""
+  status: WebhookDeliveryStatus!
+
+  """Attempts made so far"""
+  attempts: Int!
+
+  """Status of the last response"""
+  statusCode: Int
+
+  """Why the last attempt failed"""
+  error: String
+
+  """When the event was queued"""
+  createdAt: Time!
+
+  """When the next attempt is due, for pending deliveries"""
+  nextAttempt: Time
+
+  """When the delivery completed"""
+  completedAt: Time
+}
+
+"""
+Status of a webhook delivery
+"""
+enum WebhookDeliveryStatus {
+  PENDING
+  DELIVERED
+  FAILED
+}
+
+"""
+Input for creating or replacing a webhook
+"""
+input WebhookInput {
+  """Webhook name"""
+  name: String!
+
+  """URL the events are POSTed to"""
+  url: String!
+
+  """Key of the signature header; requests are not signed without it. A
+  replaced webhook keeps its secret when omitted."""
+  secret: String
+
+  """Remove the secret of the webhook being replaced"""
+  clearSecret: Boolean
+
+  """Kind of resource watched (default KV)"""
+  kind: WebhookKind
+
+  """Namespace of the services watched"""
+  namespace: String
+
+  """Key or service name pattern (default ** for KV and * for services)"""
+  pattern: String
+
+  """Event types delivered; all when empty"""
+  events: [String!]
 }
 `, BuiltIn: false},
 }
@@ -1119,18 +1971,7 @@ var parsedSchema = gqlparser.MustLoadSchema(sources...)
 
 // region    ***************************** args.gotpl *****************************
 
-func (ec *executionContext) field_Mutation_deregisterService_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
-	var err error
-	args := map[string]any{}
-	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "name", ec.unmarshalNString2string)
-	if err != nil {
-		return nil, err
-	}
-	args["name"] = arg0
-	return args, nil
-}
-
-func (ec *executionContext) field_Mutation_kvCAS_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+func (ec *executionContext) field_Mutation_acquireLock_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
 	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "key", ec.unmarshalNString2string)
@@ -1138,47 +1979,147 @@ func (ec *executionContext) field_Mutation_kvCAS_args(ctx context.Context, rawAr
 		return nil, err
 	}
 	args["key"] = arg0
-	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "value", ec.unmarshalNString2string)
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "session", ec.unmarshalNString2string)
 	if err != nil {
 		return nil, err
 	}
-	args["value"] = arg1
-	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "index", ec.unmarshalNInt2int)
+	args["session"] = arg1
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "value", ec.unmarshalNString2string)
 	if err != nil {
 		return nil, err
 	}
-	args["index"] = arg2
+	args["value"] = arg2
 	return args, nil
 }
 
-func (ec *executionContext) field_Mutation_kvDelete_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+func (ec *executionContext) field_Mutation_createSession_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
-	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "key", ec.unmarshalNString2string)
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "input", ec.unmarshalOCreateSessionInput2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐCreateSessionInput)
 	if err != nil {
 		return nil, err
 	}
-	args["key"] = arg0
+	args["input"] = arg0
 	return args, nil
 }
 
-func (ec *executionContext) field_Mutation_kvSet_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+func (ec *executionContext) field_Mutation_deleteWebhook_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
-	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "key", ec.unmarshalNString2string)
-	if err != nil {
-		return nil, err
-	}
-	args["key"] = arg0
-	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "value", ec.unmarshalNString2string)
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "name", ec.unmarshalNString2string)
 	if err != nil {
 		return nil, err
 	}
-	args["value"] = arg1
+	args["name"] = arg0
 	return args, nil
 }
 
-func (ec *executionContext) field_Mutation_registerService_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+func (ec *executionContext) field_Mutation_deregisterService_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "name", ec.unmarshalNString2string)
+	if err != nil {
+		return nil, err
+	}
+	args["name"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "id", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["id"] = arg1
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "namespace", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["namespace"] = arg2
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_destroySession_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "id", ec.unmarshalNString2string)
+	if err != nil {
+		return nil, err
+	}
+	args["id"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_kvCAS_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "key", ec.unmarshalNString2string)
+	if err != nil {
+		return nil, err
+	}
+	args["key"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "value", ec.unmarshalNString2string)
+	if err != nil {
+		return nil, err
+	}
+	args["value"] = arg1
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "index", ec.unmarshalNInt2int)
+	if err != nil {
+		return nil, err
+	}
+	args["index"] = arg2
+	arg3, err := graphql.ProcessArgField(ctx, rawArgs, "ttl", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["ttl"] = arg3
+	arg4, err := graphql.ProcessArgField(ctx, rawArgs, "namespace", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["namespace"] = arg4
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_kvDelete_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "key", ec.unmarshalNString2string)
+	if err != nil {
+		return nil, err
+	}
+	args["key"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "namespace", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["namespace"] = arg1
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_kvSet_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "key", ec.unmarshalNString2string)
+	if err != nil {
+		return nil, err
+	}
+	args["key"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "value", ec.unmarshalNString2string)
+	if err != nil {
+		return nil, err
+	}
+	args["value"] = arg1
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "ttl", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["ttl"] = arg2
+	arg3, err := graphql.ProcessArgField(ctx, rawArgs, "namespace", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["namespace"] = arg3
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_registerService_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
 	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "input", ec.unmarshalNRegisterServiceInput2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐRegisterServiceInput)
@@ -1189,6 +2130,44 @@ func (ec *executionContext) field_Mutation_registerService_args(ctx context.Cont
 	return args, nil
 }
 
+func (ec *executionContext) field_Mutation_releaseLock_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "key", ec.unmarshalNString2string)
+	if err != nil {
+		return nil, err
+	}
+	args["key"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "session", ec.unmarshalNString2string)
+	if err != nil {
+		return nil, err
+	}
+	args["session"] = arg1
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_renewSession_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "id", ec.unmarshalNString2string)
+	if err != nil {
+		return nil, err
+	}
+	args["id"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_setWebhook_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "input", ec.unmarshalNWebhookInput2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookInput)
+	if err != nil {
+		return nil, err
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Mutation_updateHeartbeat_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
@@ -1197,6 +2176,16 @@ func (ec *executionContext) field_Mutation_updateHeartbeat_args(ctx context.Cont
 		return nil, err
 	}
 	args["name"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "id", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["id"] = arg1
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "namespace", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["namespace"] = arg2
 	return args, nil
 }
 
@@ -1211,6 +2200,17 @@ func (ec *executionContext) field_Query___type_args(ctx context.Context, rawArgs
 	return args, nil
 }
 
+func (ec *executionContext) field_Query_healthCheckHistory_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "id", ec.unmarshalNString2string)
+	if err != nil {
+		return nil, err
+	}
+	args["id"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Query_kvList_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
@@ -1229,6 +2229,11 @@ func (ec *executionContext) field_Query_kvList_args(ctx context.Context, rawArgs
 		return nil, err
 	}
 	args["offset"] = arg2
+	arg3, err := graphql.ProcessArgField(ctx, rawArgs, "namespace", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["namespace"] = arg3
 	return args, nil
 }
 
@@ -1240,6 +2245,32 @@ func (ec *executionContext) field_Query_kv_args(ctx context.Context, rawArgs map
 		return nil, err
 	}
 	args["key"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "namespace", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["namespace"] = arg1
+	return args, nil
+}
+
+func (ec *executionContext) field_Query_serviceInstances_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "name", ec.unmarshalNString2string)
+	if err != nil {
+		return nil, err
+	}
+	args["name"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "namespace", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["namespace"] = arg1
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "passing", ec.unmarshalOBoolean2ᚖbool)
+	if err != nil {
+		return nil, err
+	}
+	args["passing"] = arg2
 	return args, nil
 }
 
@@ -1251,6 +2282,11 @@ func (ec *executionContext) field_Query_service_args(ctx context.Context, rawArg
 		return nil, err
 	}
 	args["name"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "namespace", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["namespace"] = arg1
 	return args, nil
 }
 
@@ -1262,6 +2298,11 @@ func (ec *executionContext) field_Query_servicesByMetadata_args(ctx context.Cont
 		return nil, err
 	}
 	args["filters"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "namespace", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["namespace"] = arg1
 	return args, nil
 }
 
@@ -1278,6 +2319,11 @@ func (ec *executionContext) field_Query_servicesByQuery_args(ctx context.Context
 		return nil, err
 	}
 	args["metadata"] = arg1
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "namespace", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["namespace"] = arg2
 	return args, nil
 }
 
@@ -1289,6 +2335,11 @@ func (ec *executionContext) field_Query_servicesByTags_args(ctx context.Context,
 		return nil, err
 	}
 	args["tags"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "namespace", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["namespace"] = arg1
 	return args, nil
 }
 
@@ -1305,6 +2356,49 @@ func (ec *executionContext) field_Query_services_args(ctx context.Context, rawAr
 		return nil, err
 	}
 	args["offset"] = arg1
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "namespace", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["namespace"] = arg2
+	arg3, err := graphql.ProcessArgField(ctx, rawArgs, "passing", ec.unmarshalOBoolean2ᚖbool)
+	if err != nil {
+		return nil, err
+	}
+	args["passing"] = arg3
+	return args, nil
+}
+
+func (ec *executionContext) field_Query_session_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "id", ec.unmarshalNString2string)
+	if err != nil {
+		return nil, err
+	}
+	args["id"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_Query_webhookDeliveries_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "name", ec.unmarshalNString2string)
+	if err != nil {
+		return nil, err
+	}
+	args["name"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_Query_webhook_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "name", ec.unmarshalNString2string)
+	if err != nil {
+		return nil, err
+	}
+	args["name"] = arg0
 	return args, nil
 }
 
@@ -1332,6 +2426,11 @@ func (ec *executionContext) field_Subscription_serviceChanged_args(ctx context.C
 		return nil, err
 	}
 	args["name"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "namespace", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["namespace"] = arg1
 	return args, nil
 }
 
@@ -1648,83 +2747,83 @@ func (ec *executionContext) fieldContext_HealthCheck_lastChecked(_ context.Conte
 	return fc, nil
 }
 
-func (ec *executionContext) _KVChangeEvent_type(ctx context.Context, field graphql.CollectedField, obj *model.KVChangeEvent) (ret graphql.Marshaler) {
+func (ec *executionContext) _HealthCheck_criticalSince(ctx context.Context, field graphql.CollectedField, obj *model.HealthCheck) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_KVChangeEvent_type,
+		ec.fieldContext_HealthCheck_criticalSince,
 		func(ctx context.Context) (any, error) {
-			return obj.Type, nil
+			return obj.CriticalSince, nil
 		},
 		nil,
-		ec.marshalNKVEventType2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐKVEventType,
-		true,
+		ec.marshalOTime2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
 		true,
+		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_KVChangeEvent_type(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_HealthCheck_criticalSince(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "KVChangeEvent",
+		Object:     "HealthCheck",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type KVEventType does not have child fields")
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _KVChangeEvent_key(ctx context.Context, field graphql.CollectedField, obj *model.KVChangeEvent) (ret graphql.Marshaler) {
+func (ec *executionContext) _HealthCheckResult_status(ctx context.Context, field graphql.CollectedField, obj *model.HealthCheckResult) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_KVChangeEvent_key,
+		ec.fieldContext_HealthCheckResult_status,
 		func(ctx context.Context) (any, error) {
-			return obj.Key, nil
+			return obj.Status, nil
 		},
 		nil,
-		ec.marshalNString2string,
+		ec.marshalNHealthCheckStatus2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐHealthCheckStatus,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_KVChangeEvent_key(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_HealthCheckResult_status(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "KVChangeEvent",
+		Object:     "HealthCheckResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type HealthCheckStatus does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _KVChangeEvent_value(ctx context.Context, field graphql.CollectedField, obj *model.KVChangeEvent) (ret graphql.Marshaler) {
+func (ec *executionContext) _HealthCheckResult_output(ctx context.Context, field graphql.CollectedField, obj *model.HealthCheckResult) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_KVChangeEvent_value,
+		ec.fieldContext_HealthCheckResult_output,
 		func(ctx context.Context) (any, error) {
-			return obj.Value, nil
+			return obj.Output, nil
 		},
 		nil,
-		ec.marshalOString2ᚖstring,
+		ec.marshalNString2string,
+		true,
 		true,
-		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_KVChangeEvent_value(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_HealthCheckResult_output(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "KVChangeEvent",
+		Object:     "HealthCheckResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -1735,52 +2834,197 @@ func (ec *executionContext) fieldContext_KVChangeEvent_value(_ context.Context,
 	return fc, nil
 }
 
-func (ec *executionContext) _KVChangeEvent_oldValue(ctx context.Context, field graphql.CollectedField, obj *model.KVChangeEvent) (ret graphql.Marshaler) {
+func (ec *executionContext) _HealthCheckResult_time(ctx context.Context, field graphql.CollectedField, obj *model.HealthCheckResult) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_KVChangeEvent_oldValue,
+		ec.fieldContext_HealthCheckResult_time,
 		func(ctx context.Context) (any, error) {
-			return obj.OldValue, nil
+			return obj.Time, nil
 		},
 		nil,
-		ec.marshalOString2ᚖstring,
+		ec.marshalNTime2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
+		true,
 		true,
-		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_KVChangeEvent_oldValue(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_HealthCheckResult_time(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "KVChangeEvent",
+		Object:     "HealthCheckResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _KVChangeEvent_timestamp(ctx context.Context, field graphql.CollectedField, obj *model.KVChangeEvent) (ret graphql.Marshaler) {
+func (ec *executionContext) _KVChangeEvent_type(ctx context.Context, field graphql.CollectedField, obj *model.KVChangeEvent) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_KVChangeEvent_timestamp,
+		ec.fieldContext_KVChangeEvent_type,
 		func(ctx context.Context) (any, error) {
-			return obj.Timestamp, nil
+			return obj.Type, nil
 		},
 		nil,
-		ec.marshalNTime2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
+		ec.marshalNKVEventType2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐKVEventType,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_KVChangeEvent_timestamp(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_KVChangeEvent_type(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "KVChangeEvent",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type KVEventType does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _KVChangeEvent_key(ctx context.Context, field graphql.CollectedField, obj *model.KVChangeEvent) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_KVChangeEvent_key,
+		func(ctx context.Context) (any, error) {
+			return obj.Key, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_KVChangeEvent_key(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "KVChangeEvent",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _KVChangeEvent_value(ctx context.Context, field graphql.CollectedField, obj *model.KVChangeEvent) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_KVChangeEvent_value,
+		func(ctx context.Context) (any, error) {
+			return obj.Value, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_KVChangeEvent_value(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "KVChangeEvent",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _KVChangeEvent_oldValue(ctx context.Context, field graphql.CollectedField, obj *model.KVChangeEvent) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_KVChangeEvent_oldValue,
+		func(ctx context.Context) (any, error) {
+			return obj.OldValue, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_KVChangeEvent_oldValue(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "KVChangeEvent",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _KVChangeEvent_secret(ctx context.Context, field graphql.CollectedField, obj *model.KVChangeEvent) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_KVChangeEvent_secret,
+		func(ctx context.Context) (any, error) {
+			return obj.Secret, nil
+		},
+		nil,
+		ec.marshalNBoolean2bool,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_KVChangeEvent_secret(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "KVChangeEvent",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _KVChangeEvent_timestamp(ctx context.Context, field graphql.CollectedField, obj *model.KVChangeEvent) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_KVChangeEvent_timestamp,
+		func(ctx context.Context) (any, error) {
+			return obj.Timestamp, nil
+		},
+		nil,
+		ec.marshalNTime2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_KVChangeEvent_timestamp(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "KVChangeEvent",
 		Field:      field,
@@ -1821,10 +3065,16 @@ func (ec *executionContext) fieldContext_KVListResponse_items(_ context.Context,
 				return ec.fieldContext_KVPair_key(ctx, field)
 			case "value":
 				return ec.fieldContext_KVPair_value(ctx, field)
+			case "secret":
+				return ec.fieldContext_KVPair_secret(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_KVPair_createdAt(ctx, field)
 			case "updatedAt":
 				return ec.fieldContext_KVPair_updatedAt(ctx, field)
+			case "session":
+				return ec.fieldContext_KVPair_session(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_KVPair_expiresAt(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type KVPair", field.Name)
 		},
@@ -1948,6 +3198,35 @@ func (ec *executionContext) fieldContext_KVPair_value(_ context.Context, field g
 	return fc, nil
 }
 
+func (ec *executionContext) _KVPair_secret(ctx context.Context, field graphql.CollectedField, obj *model.KVPair) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_KVPair_secret,
+		func(ctx context.Context) (any, error) {
+			return obj.Secret, nil
+		},
+		nil,
+		ec.marshalNBoolean2bool,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_KVPair_secret(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "KVPair",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _KVPair_createdAt(ctx context.Context, field graphql.CollectedField, obj *model.KVPair) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -2006,6 +3285,64 @@ func (ec *executionContext) fieldContext_KVPair_updatedAt(_ context.Context, fie
 	return fc, nil
 }
 
+func (ec *executionContext) _KVPair_session(ctx context.Context, field graphql.CollectedField, obj *model.KVPair) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_KVPair_session,
+		func(ctx context.Context) (any, error) {
+			return obj.Session, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_KVPair_session(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "KVPair",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _KVPair_expiresAt(ctx context.Context, field graphql.CollectedField, obj *model.KVPair) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_KVPair_expiresAt,
+		func(ctx context.Context) (any, error) {
+			return obj.ExpiresAt, nil
+		},
+		nil,
+		ec.marshalOTime2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_KVPair_expiresAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "KVPair",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Time does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _KVStats_totalKeys(ctx context.Context, field graphql.CollectedField, obj *model.KVStats) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -2101,7 +3438,7 @@ func (ec *executionContext) _Mutation_kvSet(ctx context.Context, field graphql.C
 		ec.fieldContext_Mutation_kvSet,
 		func(ctx context.Context) (any, error) {
 			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Mutation().KvSet(ctx, fc.Args["key"].(string), fc.Args["value"].(string))
+			return ec.resolvers.Mutation().KvSet(ctx, fc.Args["key"].(string), fc.Args["value"].(string), fc.Args["ttl"].(*string), fc.Args["namespace"].(*string))
 		},
 		nil,
 		ec.marshalNKVPair2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐKVPair,
@@ -2122,10 +3459,16 @@ func (ec *executionContext) fieldContext_Mutation_kvSet(ctx context.Context, fie
 				return ec.fieldContext_KVPair_key(ctx, field)
 			case "value":
 				return ec.fieldContext_KVPair_value(ctx, field)
+			case "secret":
+				return ec.fieldContext_KVPair_secret(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_KVPair_createdAt(ctx, field)
 			case "updatedAt":
 				return ec.fieldContext_KVPair_updatedAt(ctx, field)
+			case "session":
+				return ec.fieldContext_KVPair_session(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_KVPair_expiresAt(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type KVPair", field.Name)
 		},
@@ -2152,7 +3495,7 @@ func (ec *executionContext) _Mutation_kvDelete(ctx context.Context, field graphq
 		ec.fieldContext_Mutation_kvDelete,
 		func(ctx context.Context) (any, error) {
 			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Mutation().KvDelete(ctx, fc.Args["key"].(string))
+			return ec.resolvers.Mutation().KvDelete(ctx, fc.Args["key"].(string), fc.Args["namespace"].(*string))
 		},
 		nil,
 		ec.marshalNBoolean2bool,
@@ -2193,7 +3536,7 @@ func (ec *executionContext) _Mutation_kvCAS(ctx context.Context, field graphql.C
 		ec.fieldContext_Mutation_kvCAS,
 		func(ctx context.Context) (any, error) {
 			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Mutation().KvCas(ctx, fc.Args["key"].(string), fc.Args["value"].(string), fc.Args["index"].(int))
+			return ec.resolvers.Mutation().KvCas(ctx, fc.Args["key"].(string), fc.Args["value"].(string), fc.Args["index"].(int), fc.Args["ttl"].(*string), fc.Args["namespace"].(*string))
 		},
 		nil,
 		ec.marshalOKVPair2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐKVPair,
@@ -2214,10 +3557,16 @@ func (ec *executionContext) fieldContext_Mutation_kvCAS(ctx context.Context, fie
 				return ec.fieldContext_KVPair_key(ctx, field)
 			case "value":
 				return ec.fieldContext_KVPair_value(ctx, field)
+			case "secret":
+				return ec.fieldContext_KVPair_secret(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_KVPair_createdAt(ctx, field)
 			case "updatedAt":
 				return ec.fieldContext_KVPair_updatedAt(ctx, field)
+			case "session":
+				return ec.fieldContext_KVPair_session(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_KVPair_expiresAt(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type KVPair", field.Name)
 		},
@@ -2261,8 +3610,14 @@ func (ec *executionContext) fieldContext_Mutation_registerService(ctx context.Co
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
+			case "id":
+				return ec.fieldContext_Service_id(ctx, field)
 			case "name":
 				return ec.fieldContext_Service_name(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Service_namespace(ctx, field)
+			case "node":
+				return ec.fieldContext_Service_node(ctx, field)
 			case "address":
 				return ec.fieldContext_Service_address(ctx, field)
 			case "port":
@@ -2303,7 +3658,7 @@ func (ec *executionContext) _Mutation_deregisterService(ctx context.Context, fie
 		ec.fieldContext_Mutation_deregisterService,
 		func(ctx context.Context) (any, error) {
 			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Mutation().DeregisterService(ctx, fc.Args["name"].(string))
+			return ec.resolvers.Mutation().DeregisterService(ctx, fc.Args["name"].(string), fc.Args["id"].(*string), fc.Args["namespace"].(*string))
 		},
 		nil,
 		ec.marshalNBoolean2bool,
@@ -2344,7 +3699,7 @@ func (ec *executionContext) _Mutation_updateHeartbeat(ctx context.Context, field
 		ec.fieldContext_Mutation_updateHeartbeat,
 		func(ctx context.Context) (any, error) {
 			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Mutation().UpdateHeartbeat(ctx, fc.Args["name"].(string))
+			return ec.resolvers.Mutation().UpdateHeartbeat(ctx, fc.Args["name"].(string), fc.Args["id"].(*string), fc.Args["namespace"].(*string))
 		},
 		nil,
 		ec.marshalNService2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐService,
@@ -2361,8 +3716,14 @@ func (ec *executionContext) fieldContext_Mutation_updateHeartbeat(ctx context.Co
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
+			case "id":
+				return ec.fieldContext_Service_id(ctx, field)
 			case "name":
 				return ec.fieldContext_Service_name(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Service_namespace(ctx, field)
+			case "node":
+				return ec.fieldContext_Service_node(ctx, field)
 			case "address":
 				return ec.fieldContext_Service_address(ctx, field)
 			case "port":
@@ -2395,84 +3756,51 @@ func (ec *executionContext) fieldContext_Mutation_updateHeartbeat(ctx context.Co
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_health(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Mutation_createSession(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_health,
+		ec.fieldContext_Mutation_createSession,
 		func(ctx context.Context) (any, error) {
-			return ec.resolvers.Query().Health(ctx)
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Mutation().CreateSession(ctx, fc.Args["input"].(*model.CreateSessionInput))
 		},
 		nil,
-		ec.marshalNSystemHealth2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSystemHealth,
+		ec.marshalNSession2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSession,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_health(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Mutation_createSession(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "Mutation",
 		Field:      field,
 		IsMethod:   true,
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "status":
-				return ec.fieldContext_SystemHealth_status(ctx, field)
-			case "version":
-				return ec.fieldContext_SystemHealth_version(ctx, field)
-			case "uptime":
-				return ec.fieldContext_SystemHealth_uptime(ctx, field)
-			case "timestamp":
-				return ec.fieldContext_SystemHealth_timestamp(ctx, field)
-			case "services":
-				return ec.fieldContext_SystemHealth_services(ctx, field)
-			case "kvStore":
-				return ec.fieldContext_SystemHealth_kvStore(ctx, field)
+			case "id":
+				return ec.fieldContext_Session_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Session_name(ctx, field)
+			case "node":
+				return ec.fieldContext_Session_node(ctx, field)
+			case "ttl":
+				return ec.fieldContext_Session_ttl(ctx, field)
+			case "behavior":
+				return ec.fieldContext_Session_behavior(ctx, field)
+			case "checks":
+				return ec.fieldContext_Session_checks(ctx, field)
+			case "createIndex":
+				return ec.fieldContext_Session_createIndex(ctx, field)
+			case "modifyIndex":
+				return ec.fieldContext_Session_modifyIndex(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_Session_expiresAt(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type SystemHealth", field.Name)
-		},
-	}
-	return fc, nil
-}
-
-func (ec *executionContext) _Query_kv(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	return graphql.ResolveField(
-		ctx,
-		ec.OperationContext,
-		field,
-		ec.fieldContext_Query_kv,
-		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().Kv(ctx, fc.Args["key"].(string))
-		},
-		nil,
-		ec.marshalOKVPair2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐKVPair,
-		true,
-		false,
-	)
-}
-
-func (ec *executionContext) fieldContext_Query_kv(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
-	fc = &graphql.FieldContext{
-		Object:     "Query",
-		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
-		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "key":
-				return ec.fieldContext_KVPair_key(ctx, field)
-			case "value":
-				return ec.fieldContext_KVPair_value(ctx, field)
-			case "createdAt":
-				return ec.fieldContext_KVPair_createdAt(ctx, field)
-			case "updatedAt":
-				return ec.fieldContext_KVPair_updatedAt(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type KVPair", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Session", field.Name)
 		},
 	}
 	defer func() {
@@ -2482,46 +3810,58 @@ func (ec *executionContext) fieldContext_Query_kv(ctx context.Context, field gra
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_kv_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Mutation_createSession_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_kvList(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Mutation_renewSession(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_kvList,
+		ec.fieldContext_Mutation_renewSession,
 		func(ctx context.Context) (any, error) {
 			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().KvList(ctx, fc.Args["prefix"].(*string), fc.Args["limit"].(*int), fc.Args["offset"].(*int))
+			return ec.resolvers.Mutation().RenewSession(ctx, fc.Args["id"].(string))
 		},
 		nil,
-		ec.marshalNKVListResponse2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐKVListResponse,
+		ec.marshalNSession2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSession,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_kvList(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Mutation_renewSession(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "Mutation",
 		Field:      field,
 		IsMethod:   true,
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "items":
-				return ec.fieldContext_KVListResponse_items(ctx, field)
-			case "total":
-				return ec.fieldContext_KVListResponse_total(ctx, field)
-			case "hasMore":
-				return ec.fieldContext_KVListResponse_hasMore(ctx, field)
+			case "id":
+				return ec.fieldContext_Session_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Session_name(ctx, field)
+			case "node":
+				return ec.fieldContext_Session_node(ctx, field)
+			case "ttl":
+				return ec.fieldContext_Session_ttl(ctx, field)
+			case "behavior":
+				return ec.fieldContext_Session_behavior(ctx, field)
+			case "checks":
+				return ec.fieldContext_Session_checks(ctx, field)
+			case "createIndex":
+				return ec.fieldContext_Session_createIndex(ctx, field)
+			case "modifyIndex":
+				return ec.fieldContext_Session_modifyIndex(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_Session_expiresAt(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type KVListResponse", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Session", field.Name)
 		},
 	}
 	defer func() {
@@ -2531,56 +3871,38 @@ func (ec *executionContext) fieldContext_Query_kvList(ctx context.Context, field
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_kvList_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Mutation_renewSession_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_service(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Mutation_destroySession(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_service,
+		ec.fieldContext_Mutation_destroySession,
 		func(ctx context.Context) (any, error) {
 			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().Service(ctx, fc.Args["name"].(string))
+			return ec.resolvers.Mutation().DestroySession(ctx, fc.Args["id"].(string))
 		},
 		nil,
-		ec.marshalOService2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐService,
+		ec.marshalNBoolean2bool,
+		true,
 		true,
-		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_service(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Mutation_destroySession(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "Mutation",
 		Field:      field,
 		IsMethod:   true,
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "name":
-				return ec.fieldContext_Service_name(ctx, field)
-			case "address":
-				return ec.fieldContext_Service_address(ctx, field)
-			case "port":
-				return ec.fieldContext_Service_port(ctx, field)
-			case "status":
-				return ec.fieldContext_Service_status(ctx, field)
-			case "expiresAt":
-				return ec.fieldContext_Service_expiresAt(ctx, field)
-			case "tags":
-				return ec.fieldContext_Service_tags(ctx, field)
-			case "metadata":
-				return ec.fieldContext_Service_metadata(ctx, field)
-			case "checks":
-				return ec.fieldContext_Service_checks(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Service", field.Name)
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
 	defer func() {
@@ -2590,56 +3912,38 @@ func (ec *executionContext) fieldContext_Query_service(ctx context.Context, fiel
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_service_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Mutation_destroySession_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_services(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Mutation_acquireLock(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_services,
+		ec.fieldContext_Mutation_acquireLock,
 		func(ctx context.Context) (any, error) {
 			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().Services(ctx, fc.Args["limit"].(*int), fc.Args["offset"].(*int))
+			return ec.resolvers.Mutation().AcquireLock(ctx, fc.Args["key"].(string), fc.Args["session"].(string), fc.Args["value"].(string))
 		},
 		nil,
-		ec.marshalNService2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceᚄ,
+		ec.marshalNBoolean2bool,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_services(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Mutation_acquireLock(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "Mutation",
 		Field:      field,
 		IsMethod:   true,
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "name":
-				return ec.fieldContext_Service_name(ctx, field)
-			case "address":
-				return ec.fieldContext_Service_address(ctx, field)
-			case "port":
-				return ec.fieldContext_Service_port(ctx, field)
-			case "status":
-				return ec.fieldContext_Service_status(ctx, field)
-			case "expiresAt":
-				return ec.fieldContext_Service_expiresAt(ctx, field)
-			case "tags":
-				return ec.fieldContext_Service_tags(ctx, field)
-			case "metadata":
-				return ec.fieldContext_Service_metadata(ctx, field)
-			case "checks":
-				return ec.fieldContext_Service_checks(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Service", field.Name)
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
 	defer func() {
@@ -2649,85 +3953,101 @@ func (ec *executionContext) fieldContext_Query_services(ctx context.Context, fie
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_services_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Mutation_acquireLock_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_servicesCount(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Mutation_releaseLock(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_servicesCount,
+		ec.fieldContext_Mutation_releaseLock,
 		func(ctx context.Context) (any, error) {
-			return ec.resolvers.Query().ServicesCount(ctx)
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Mutation().ReleaseLock(ctx, fc.Args["key"].(string), fc.Args["session"].(string))
 		},
 		nil,
-		ec.marshalNInt2int,
+		ec.marshalNBoolean2bool,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_servicesCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Mutation_releaseLock(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "Mutation",
 		Field:      field,
 		IsMethod:   true,
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_releaseLock_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_servicesByTags(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Mutation_setWebhook(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_servicesByTags,
+		ec.fieldContext_Mutation_setWebhook,
 		func(ctx context.Context) (any, error) {
 			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().ServicesByTags(ctx, fc.Args["tags"].([]string))
+			return ec.resolvers.Mutation().SetWebhook(ctx, fc.Args["input"].(model.WebhookInput))
 		},
 		nil,
-		ec.marshalNService2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceᚄ,
+		ec.marshalNWebhook2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhook,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_servicesByTags(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Mutation_setWebhook(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "Mutation",
 		Field:      field,
 		IsMethod:   true,
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
 			case "name":
-				return ec.fieldContext_Service_name(ctx, field)
-			case "address":
-				return ec.fieldContext_Service_address(ctx, field)
-			case "port":
-				return ec.fieldContext_Service_port(ctx, field)
-			case "status":
-				return ec.fieldContext_Service_status(ctx, field)
-			case "expiresAt":
-				return ec.fieldContext_Service_expiresAt(ctx, field)
-			case "tags":
-				return ec.fieldContext_Service_tags(ctx, field)
-			case "metadata":
-				return ec.fieldContext_Service_metadata(ctx, field)
-			case "checks":
-				return ec.fieldContext_Service_checks(ctx, field)
+				return ec.fieldContext_Webhook_name(ctx, field)
+			case "url":
+				return ec.fieldContext_Webhook_url(ctx, field)
+			case "signed":
+				return ec.fieldContext_Webhook_signed(ctx, field)
+			case "kind":
+				return ec.fieldContext_Webhook_kind(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Webhook_namespace(ctx, field)
+			case "pattern":
+				return ec.fieldContext_Webhook_pattern(ctx, field)
+			case "events":
+				return ec.fieldContext_Webhook_events(ctx, field)
+			case "policies":
+				return ec.fieldContext_Webhook_policies(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_Webhook_createdAt(ctx, field)
+			case "updatedAt":
+				return ec.fieldContext_Webhook_updatedAt(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type Service", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Webhook", field.Name)
 		},
 	}
 	defer func() {
@@ -2737,56 +4057,38 @@ func (ec *executionContext) fieldContext_Query_servicesByTags(ctx context.Contex
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_servicesByTags_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Mutation_setWebhook_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_servicesByMetadata(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Mutation_deleteWebhook(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_servicesByMetadata,
+		ec.fieldContext_Mutation_deleteWebhook,
 		func(ctx context.Context) (any, error) {
 			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().ServicesByMetadata(ctx, fc.Args["filters"].([]*model.MetadataFilter))
+			return ec.resolvers.Mutation().DeleteWebhook(ctx, fc.Args["name"].(string))
 		},
 		nil,
-		ec.marshalNService2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceᚄ,
+		ec.marshalNBoolean2bool,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_servicesByMetadata(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Mutation_deleteWebhook(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "Mutation",
 		Field:      field,
 		IsMethod:   true,
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "name":
-				return ec.fieldContext_Service_name(ctx, field)
-			case "address":
-				return ec.fieldContext_Service_address(ctx, field)
-			case "port":
-				return ec.fieldContext_Service_port(ctx, field)
-			case "status":
-				return ec.fieldContext_Service_status(ctx, field)
-			case "expiresAt":
-				return ec.fieldContext_Service_expiresAt(ctx, field)
-			case "tags":
-				return ec.fieldContext_Service_tags(ctx, field)
-			case "metadata":
-				return ec.fieldContext_Service_metadata(ctx, field)
-			case "checks":
-				return ec.fieldContext_Service_checks(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Service", field.Name)
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
 	defer func() {
@@ -2796,186 +4098,2416 @@ func (ec *executionContext) fieldContext_Query_servicesByMetadata(ctx context.Co
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_servicesByMetadata_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Mutation_deleteWebhook_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_servicesByQuery(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Namespace_name(ctx context.Context, field graphql.CollectedField, obj *model.Namespace) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_servicesByQuery,
+		ec.fieldContext_Namespace_name,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().ServicesByQuery(ctx, fc.Args["tags"].([]string), fc.Args["metadata"].([]*model.MetadataFilter))
+			return obj.Name, nil
 		},
 		nil,
-		ec.marshalNService2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceᚄ,
+		ec.marshalNString2string,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_servicesByQuery(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Namespace_name(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "Namespace",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "name":
-				return ec.fieldContext_Service_name(ctx, field)
-			case "address":
-				return ec.fieldContext_Service_address(ctx, field)
-			case "port":
-				return ec.fieldContext_Service_port(ctx, field)
-			case "status":
-				return ec.fieldContext_Service_status(ctx, field)
-			case "expiresAt":
-				return ec.fieldContext_Service_expiresAt(ctx, field)
-			case "tags":
-				return ec.fieldContext_Service_tags(ctx, field)
-			case "metadata":
-				return ec.fieldContext_Service_metadata(ctx, field)
-			case "checks":
-				return ec.fieldContext_Service_checks(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Service", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_servicesByQuery_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Namespace_description(ctx context.Context, field graphql.CollectedField, obj *model.Namespace) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query___type,
+		ec.fieldContext_Namespace_description,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.introspectType(fc.Args["name"].(string))
+			return obj.Description, nil
 		},
 		nil,
-		ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType,
+		ec.marshalOString2ᚖstring,
 		true,
 		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query___type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Namespace_description(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "Namespace",
 		Field:      field,
-		IsMethod:   true,
+		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "kind":
-				return ec.fieldContext___Type_kind(ctx, field)
-			case "name":
-				return ec.fieldContext___Type_name(ctx, field)
-			case "description":
-				return ec.fieldContext___Type_description(ctx, field)
-			case "specifiedByURL":
-				return ec.fieldContext___Type_specifiedByURL(ctx, field)
-			case "fields":
-				return ec.fieldContext___Type_fields(ctx, field)
-			case "interfaces":
-				return ec.fieldContext___Type_interfaces(ctx, field)
-			case "possibleTypes":
-				return ec.fieldContext___Type_possibleTypes(ctx, field)
-			case "enumValues":
-				return ec.fieldContext___Type_enumValues(ctx, field)
-			case "inputFields":
-				return ec.fieldContext___Type_inputFields(ctx, field)
-			case "ofType":
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Namespace_metadata(ctx context.Context, field graphql.CollectedField, obj *model.Namespace) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Namespace_metadata,
+		func(ctx context.Context) (any, error) {
+			return obj.Metadata, nil
+		},
+		nil,
+		ec.marshalNMetadataEntry2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐMetadataEntryᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Namespace_metadata(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Namespace",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "key":
+				return ec.fieldContext_MetadataEntry_key(ctx, field)
+			case "value":
+				return ec.fieldContext_MetadataEntry_value(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type MetadataEntry", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Namespace_createIndex(ctx context.Context, field graphql.CollectedField, obj *model.Namespace) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Namespace_createIndex,
+		func(ctx context.Context) (any, error) {
+			return obj.CreateIndex, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Namespace_createIndex(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Namespace",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Namespace_createdAt(ctx context.Context, field graphql.CollectedField, obj *model.Namespace) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Namespace_createdAt,
+		func(ctx context.Context) (any, error) {
+			return obj.CreatedAt, nil
+		},
+		nil,
+		ec.marshalOTime2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Namespace_createdAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Namespace",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Time does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_health(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_health,
+		func(ctx context.Context) (any, error) {
+			return ec.resolvers.Query().Health(ctx)
+		},
+		nil,
+		ec.marshalNSystemHealth2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSystemHealth,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_health(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "status":
+				return ec.fieldContext_SystemHealth_status(ctx, field)
+			case "version":
+				return ec.fieldContext_SystemHealth_version(ctx, field)
+			case "uptime":
+				return ec.fieldContext_SystemHealth_uptime(ctx, field)
+			case "timestamp":
+				return ec.fieldContext_SystemHealth_timestamp(ctx, field)
+			case "services":
+				return ec.fieldContext_SystemHealth_services(ctx, field)
+			case "kvStore":
+				return ec.fieldContext_SystemHealth_kvStore(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type SystemHealth", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_kv(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_kv,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().Kv(ctx, fc.Args["key"].(string), fc.Args["namespace"].(*string))
+		},
+		nil,
+		ec.marshalOKVPair2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐKVPair,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_kv(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "key":
+				return ec.fieldContext_KVPair_key(ctx, field)
+			case "value":
+				return ec.fieldContext_KVPair_value(ctx, field)
+			case "secret":
+				return ec.fieldContext_KVPair_secret(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_KVPair_createdAt(ctx, field)
+			case "updatedAt":
+				return ec.fieldContext_KVPair_updatedAt(ctx, field)
+			case "session":
+				return ec.fieldContext_KVPair_session(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_KVPair_expiresAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type KVPair", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_kv_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_kvList(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_kvList,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().KvList(ctx, fc.Args["prefix"].(*string), fc.Args["limit"].(*int), fc.Args["offset"].(*int), fc.Args["namespace"].(*string))
+		},
+		nil,
+		ec.marshalNKVListResponse2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐKVListResponse,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_kvList(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "items":
+				return ec.fieldContext_KVListResponse_items(ctx, field)
+			case "total":
+				return ec.fieldContext_KVListResponse_total(ctx, field)
+			case "hasMore":
+				return ec.fieldContext_KVListResponse_hasMore(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type KVListResponse", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_kvList_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_service(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_service,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().Service(ctx, fc.Args["name"].(string), fc.Args["namespace"].(*string))
+		},
+		nil,
+		ec.marshalOService2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐService,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_service(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Service_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Service_name(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Service_namespace(ctx, field)
+			case "node":
+				return ec.fieldContext_Service_node(ctx, field)
+			case "address":
+				return ec.fieldContext_Service_address(ctx, field)
+			case "port":
+				return ec.fieldContext_Service_port(ctx, field)
+			case "status":
+				return ec.fieldContext_Service_status(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_Service_expiresAt(ctx, field)
+			case "tags":
+				return ec.fieldContext_Service_tags(ctx, field)
+			case "metadata":
+				return ec.fieldContext_Service_metadata(ctx, field)
+			case "checks":
+				return ec.fieldContext_Service_checks(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Service", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_service_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_serviceInstances(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_serviceInstances,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().ServiceInstances(ctx, fc.Args["name"].(string), fc.Args["namespace"].(*string), fc.Args["passing"].(*bool))
+		},
+		nil,
+		ec.marshalNService2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_serviceInstances(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Service_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Service_name(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Service_namespace(ctx, field)
+			case "node":
+				return ec.fieldContext_Service_node(ctx, field)
+			case "address":
+				return ec.fieldContext_Service_address(ctx, field)
+			case "port":
+				return ec.fieldContext_Service_port(ctx, field)
+			case "status":
+				return ec.fieldContext_Service_status(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_Service_expiresAt(ctx, field)
+			case "tags":
+				return ec.fieldContext_Service_tags(ctx, field)
+			case "metadata":
+				return ec.fieldContext_Service_metadata(ctx, field)
+			case "checks":
+				return ec.fieldContext_Service_checks(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Service", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_serviceInstances_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_services(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_services,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().Services(ctx, fc.Args["limit"].(*int), fc.Args["offset"].(*int), fc.Args["namespace"].(*string), fc.Args["passing"].(*bool))
+		},
+		nil,
+		ec.marshalNService2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_services(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Service_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Service_name(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Service_namespace(ctx, field)
+			case "node":
+				return ec.fieldContext_Service_node(ctx, field)
+			case "address":
+				return ec.fieldContext_Service_address(ctx, field)
+			case "port":
+				return ec.fieldContext_Service_port(ctx, field)
+			case "status":
+				return ec.fieldContext_Service_status(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_Service_expiresAt(ctx, field)
+			case "tags":
+				return ec.fieldContext_Service_tags(ctx, field)
+			case "metadata":
+				return ec.fieldContext_Service_metadata(ctx, field)
+			case "checks":
+				return ec.fieldContext_Service_checks(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Service", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_services_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_servicesCount(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_servicesCount,
+		func(ctx context.Context) (any, error) {
+			return ec.resolvers.Query().ServicesCount(ctx)
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_servicesCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_servicesByTags(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_servicesByTags,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().ServicesByTags(ctx, fc.Args["tags"].([]string), fc.Args["namespace"].(*string))
+		},
+		nil,
+		ec.marshalNService2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_servicesByTags(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Service_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Service_name(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Service_namespace(ctx, field)
+			case "node":
+				return ec.fieldContext_Service_node(ctx, field)
+			case "address":
+				return ec.fieldContext_Service_address(ctx, field)
+			case "port":
+				return ec.fieldContext_Service_port(ctx, field)
+			case "status":
+				return ec.fieldContext_Service_status(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_Service_expiresAt(ctx, field)
+			case "tags":
+				return ec.fieldContext_Service_tags(ctx, field)
+			case "metadata":
+				return ec.fieldContext_Service_metadata(ctx, field)
+			case "checks":
+				return ec.fieldContext_Service_checks(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Service", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_servicesByTags_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_servicesByMetadata(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_servicesByMetadata,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().ServicesByMetadata(ctx, fc.Args["filters"].([]*model.MetadataFilter), fc.Args["namespace"].(*string))
+		},
+		nil,
+		ec.marshalNService2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_servicesByMetadata(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Service_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Service_name(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Service_namespace(ctx, field)
+			case "node":
+				return ec.fieldContext_Service_node(ctx, field)
+			case "address":
+				return ec.fieldContext_Service_address(ctx, field)
+			case "port":
+				return ec.fieldContext_Service_port(ctx, field)
+			case "status":
+				return ec.fieldContext_Service_status(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_Service_expiresAt(ctx, field)
+			case "tags":
+				return ec.fieldContext_Service_tags(ctx, field)
+			case "metadata":
+				return ec.fieldContext_Service_metadata(ctx, field)
+			case "checks":
+				return ec.fieldContext_Service_checks(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Service", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_servicesByMetadata_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_servicesByQuery(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_servicesByQuery,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().ServicesByQuery(ctx, fc.Args["tags"].([]string), fc.Args["metadata"].([]*model.MetadataFilter), fc.Args["namespace"].(*string))
+		},
+		nil,
+		ec.marshalNService2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_servicesByQuery(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Service_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Service_name(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Service_namespace(ctx, field)
+			case "node":
+				return ec.fieldContext_Service_node(ctx, field)
+			case "address":
+				return ec.fieldContext_Service_address(ctx, field)
+			case "port":
+				return ec.fieldContext_Service_port(ctx, field)
+			case "status":
+				return ec.fieldContext_Service_status(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_Service_expiresAt(ctx, field)
+			case "tags":
+				return ec.fieldContext_Service_tags(ctx, field)
+			case "metadata":
+				return ec.fieldContext_Service_metadata(ctx, field)
+			case "checks":
+				return ec.fieldContext_Service_checks(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Service", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_servicesByQuery_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_healthCheckHistory(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_healthCheckHistory,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().HealthCheckHistory(ctx, fc.Args["id"].(string))
+		},
+		nil,
+		ec.marshalOHealthCheckResult2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐHealthCheckResultᚄ,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_healthCheckHistory(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "status":
+				return ec.fieldContext_HealthCheckResult_status(ctx, field)
+			case "output":
+				return ec.fieldContext_HealthCheckResult_output(ctx, field)
+			case "time":
+				return ec.fieldContext_HealthCheckResult_time(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type HealthCheckResult", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_healthCheckHistory_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_namespaces(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_namespaces,
+		func(ctx context.Context) (any, error) {
+			return ec.resolvers.Query().Namespaces(ctx)
+		},
+		nil,
+		ec.marshalNNamespace2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐNamespaceᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_namespaces(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "name":
+				return ec.fieldContext_Namespace_name(ctx, field)
+			case "description":
+				return ec.fieldContext_Namespace_description(ctx, field)
+			case "metadata":
+				return ec.fieldContext_Namespace_metadata(ctx, field)
+			case "createIndex":
+				return ec.fieldContext_Namespace_createIndex(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_Namespace_createdAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Namespace", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_webhooks(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_webhooks,
+		func(ctx context.Context) (any, error) {
+			return ec.resolvers.Query().Webhooks(ctx)
+		},
+		nil,
+		ec.marshalNWebhook2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_webhooks(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "name":
+				return ec.fieldContext_Webhook_name(ctx, field)
+			case "url":
+				return ec.fieldContext_Webhook_url(ctx, field)
+			case "signed":
+				return ec.fieldContext_Webhook_signed(ctx, field)
+			case "kind":
+				return ec.fieldContext_Webhook_kind(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Webhook_namespace(ctx, field)
+			case "pattern":
+				return ec.fieldContext_Webhook_pattern(ctx, field)
+			case "events":
+				return ec.fieldContext_Webhook_events(ctx, field)
+			case "policies":
+				return ec.fieldContext_Webhook_policies(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_Webhook_createdAt(ctx, field)
+			case "updatedAt":
+				return ec.fieldContext_Webhook_updatedAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Webhook", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_webhook(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_webhook,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().Webhook(ctx, fc.Args["name"].(string))
+		},
+		nil,
+		ec.marshalOWebhook2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhook,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_webhook(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "name":
+				return ec.fieldContext_Webhook_name(ctx, field)
+			case "url":
+				return ec.fieldContext_Webhook_url(ctx, field)
+			case "signed":
+				return ec.fieldContext_Webhook_signed(ctx, field)
+			case "kind":
+				return ec.fieldContext_Webhook_kind(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Webhook_namespace(ctx, field)
+			case "pattern":
+				return ec.fieldContext_Webhook_pattern(ctx, field)
+			case "events":
+				return ec.fieldContext_Webhook_events(ctx, field)
+			case "policies":
+				return ec.fieldContext_Webhook_policies(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_Webhook_createdAt(ctx, field)
+			case "updatedAt":
+				return ec.fieldContext_Webhook_updatedAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Webhook", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_webhook_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_webhookDeliveries(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_webhookDeliveries,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().WebhookDeliveries(ctx, fc.Args["name"].(string))
+		},
+		nil,
+		ec.marshalOWebhookDelivery2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookDeliveryᚄ,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_webhookDeliveries(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_WebhookDelivery_id(ctx, field)
+			case "webhook":
+				return ec.fieldContext_WebhookDelivery_webhook(ctx, field)
+			case "eventType":
+				return ec.fieldContext_WebhookDelivery_eventType(ctx, field)
+			case "key":
+				return ec.fieldContext_WebhookDelivery_key(ctx, field)
+			case "index":
+				return ec.fieldContext_WebhookDelivery_index(ctx, field)
+			case "status":
+				return ec.fieldContext_WebhookDelivery_status(ctx, field)
+			case "attempts":
+				return ec.fieldContext_WebhookDelivery_attempts(ctx, field)
+			case "statusCode":
+				return ec.fieldContext_WebhookDelivery_statusCode(ctx, field)
+			case "error":
+				return ec.fieldContext_WebhookDelivery_error(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_WebhookDelivery_createdAt(ctx, field)
+			case "nextAttempt":
+				return ec.fieldContext_WebhookDelivery_nextAttempt(ctx, field)
+			case "completedAt":
+				return ec.fieldContext_WebhookDelivery_completedAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type WebhookDelivery", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_webhookDeliveries_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_session(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_session,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().Session(ctx, fc.Args["id"].(string))
+		},
+		nil,
+		ec.marshalOSession2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSession,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_session(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Session_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Session_name(ctx, field)
+			case "node":
+				return ec.fieldContext_Session_node(ctx, field)
+			case "ttl":
+				return ec.fieldContext_Session_ttl(ctx, field)
+			case "behavior":
+				return ec.fieldContext_Session_behavior(ctx, field)
+			case "checks":
+				return ec.fieldContext_Session_checks(ctx, field)
+			case "createIndex":
+				return ec.fieldContext_Session_createIndex(ctx, field)
+			case "modifyIndex":
+				return ec.fieldContext_Session_modifyIndex(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_Session_expiresAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Session", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_session_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_sessions(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_sessions,
+		func(ctx context.Context) (any, error) {
+			return ec.resolvers.Query().Sessions(ctx)
+		},
+		nil,
+		ec.marshalNSession2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSessionᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_sessions(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Session_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Session_name(ctx, field)
+			case "node":
+				return ec.fieldContext_Session_node(ctx, field)
+			case "ttl":
+				return ec.fieldContext_Session_ttl(ctx, field)
+			case "behavior":
+				return ec.fieldContext_Session_behavior(ctx, field)
+			case "checks":
+				return ec.fieldContext_Session_checks(ctx, field)
+			case "createIndex":
+				return ec.fieldContext_Session_createIndex(ctx, field)
+			case "modifyIndex":
+				return ec.fieldContext_Session_modifyIndex(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_Session_expiresAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Session", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query___type,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.introspectType(fc.Args["name"].(string))
+		},
+		nil,
+		ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query___type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "kind":
+				return ec.fieldContext___Type_kind(ctx, field)
+			case "name":
+				return ec.fieldContext___Type_name(ctx, field)
+			case "description":
+				return ec.fieldContext___Type_description(ctx, field)
+			case "specifiedByURL":
+				return ec.fieldContext___Type_specifiedByURL(ctx, field)
+			case "fields":
+				return ec.fieldContext___Type_fields(ctx, field)
+			case "interfaces":
+				return ec.fieldContext___Type_interfaces(ctx, field)
+			case "possibleTypes":
+				return ec.fieldContext___Type_possibleTypes(ctx, field)
+			case "enumValues":
+				return ec.fieldContext___Type_enumValues(ctx, field)
+			case "inputFields":
+				return ec.fieldContext___Type_inputFields(ctx, field)
+			case "ofType":
 				return ec.fieldContext___Type_ofType(ctx, field)
 			case "isOneOf":
 				return ec.fieldContext___Type_isOneOf(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type __Type", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type __Type", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query___type_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query___schema,
+		func(ctx context.Context) (any, error) {
+			return ec.introspectSchema()
+		},
+		nil,
+		ec.marshalO__Schema2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐSchema,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query___schema(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "description":
+				return ec.fieldContext___Schema_description(ctx, field)
+			case "types":
+				return ec.fieldContext___Schema_types(ctx, field)
+			case "queryType":
+				return ec.fieldContext___Schema_queryType(ctx, field)
+			case "mutationType":
+				return ec.fieldContext___Schema_mutationType(ctx, field)
+			case "subscriptionType":
+				return ec.fieldContext___Schema_subscriptionType(ctx, field)
+			case "directives":
+				return ec.fieldContext___Schema_directives(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type __Schema", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Service_id(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Service_id,
+		func(ctx context.Context) (any, error) {
+			return obj.ID, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Service_id(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Service",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Service_name(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Service_name,
+		func(ctx context.Context) (any, error) {
+			return obj.Name, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Service_name(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Service",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Service_namespace(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Service_namespace,
+		func(ctx context.Context) (any, error) {
+			return obj.Namespace, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Service_namespace(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Service",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Service_node(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Service_node,
+		func(ctx context.Context) (any, error) {
+			return obj.Node, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Service_node(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Service",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Service_address(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Service_address,
+		func(ctx context.Context) (any, error) {
+			return obj.Address, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Service_address(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Service",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Service_port(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Service_port,
+		func(ctx context.Context) (any, error) {
+			return obj.Port, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Service_port(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Service",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Service_status(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Service_status,
+		func(ctx context.Context) (any, error) {
+			return obj.Status, nil
+		},
+		nil,
+		ec.marshalNServiceStatus2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceStatus,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Service_status(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Service",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type ServiceStatus does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Service_expiresAt(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Service_expiresAt,
+		func(ctx context.Context) (any, error) {
+			return obj.ExpiresAt, nil
+		},
+		nil,
+		ec.marshalNTime2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Service_expiresAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Service",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Time does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Service_tags(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Service_tags,
+		func(ctx context.Context) (any, error) {
+			return obj.Tags, nil
+		},
+		nil,
+		ec.marshalNString2ᚕstringᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Service_tags(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Service",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Service_metadata(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Service_metadata,
+		func(ctx context.Context) (any, error) {
+			return obj.Metadata, nil
+		},
+		nil,
+		ec.marshalNMetadataEntry2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐMetadataEntryᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Service_metadata(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Service",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "key":
+				return ec.fieldContext_MetadataEntry_key(ctx, field)
+			case "value":
+				return ec.fieldContext_MetadataEntry_value(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type MetadataEntry", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Service_checks(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Service_checks,
+		func(ctx context.Context) (any, error) {
+			return obj.Checks, nil
+		},
+		nil,
+		ec.marshalNHealthCheck2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐHealthCheckᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Service_checks(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Service",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_HealthCheck_id(ctx, field)
+			case "serviceId":
+				return ec.fieldContext_HealthCheck_serviceId(ctx, field)
+			case "name":
+				return ec.fieldContext_HealthCheck_name(ctx, field)
+			case "type":
+				return ec.fieldContext_HealthCheck_type(ctx, field)
+			case "status":
+				return ec.fieldContext_HealthCheck_status(ctx, field)
+			case "output":
+				return ec.fieldContext_HealthCheck_output(ctx, field)
+			case "interval":
+				return ec.fieldContext_HealthCheck_interval(ctx, field)
+			case "timeout":
+				return ec.fieldContext_HealthCheck_timeout(ctx, field)
+			case "lastChecked":
+				return ec.fieldContext_HealthCheck_lastChecked(ctx, field)
+			case "criticalSince":
+				return ec.fieldContext_HealthCheck_criticalSince(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type HealthCheck", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ServiceChangeEvent_type(ctx context.Context, field graphql.CollectedField, obj *model.ServiceChangeEvent) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ServiceChangeEvent_type,
+		func(ctx context.Context) (any, error) {
+			return obj.Type, nil
+		},
+		nil,
+		ec.marshalNServiceEventType2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceEventType,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ServiceChangeEvent_type(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ServiceChangeEvent",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type ServiceEventType does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ServiceChangeEvent_service(ctx context.Context, field graphql.CollectedField, obj *model.ServiceChangeEvent) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ServiceChangeEvent_service,
+		func(ctx context.Context) (any, error) {
+			return obj.Service, nil
+		},
+		nil,
+		ec.marshalNService2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐService,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ServiceChangeEvent_service(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ServiceChangeEvent",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Service_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Service_name(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Service_namespace(ctx, field)
+			case "node":
+				return ec.fieldContext_Service_node(ctx, field)
+			case "address":
+				return ec.fieldContext_Service_address(ctx, field)
+			case "port":
+				return ec.fieldContext_Service_port(ctx, field)
+			case "status":
+				return ec.fieldContext_Service_status(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_Service_expiresAt(ctx, field)
+			case "tags":
+				return ec.fieldContext_Service_tags(ctx, field)
+			case "metadata":
+				return ec.fieldContext_Service_metadata(ctx, field)
+			case "checks":
+				return ec.fieldContext_Service_checks(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Service", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ServiceChangeEvent_check(ctx context.Context, field graphql.CollectedField, obj *model.ServiceChangeEvent) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ServiceChangeEvent_check,
+		func(ctx context.Context) (any, error) {
+			return obj.Check, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_ServiceChangeEvent_check(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ServiceChangeEvent",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ServiceChangeEvent_status(ctx context.Context, field graphql.CollectedField, obj *model.ServiceChangeEvent) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ServiceChangeEvent_status,
+		func(ctx context.Context) (any, error) {
+			return obj.Status, nil
+		},
+		nil,
+		ec.marshalOHealthCheckStatus2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐHealthCheckStatus,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_ServiceChangeEvent_status(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ServiceChangeEvent",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type HealthCheckStatus does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ServiceChangeEvent_previousStatus(ctx context.Context, field graphql.CollectedField, obj *model.ServiceChangeEvent) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ServiceChangeEvent_previousStatus,
+		func(ctx context.Context) (any, error) {
+			return obj.PreviousStatus, nil
+		},
+		nil,
+		ec.marshalOHealthCheckStatus2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐHealthCheckStatus,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_ServiceChangeEvent_previousStatus(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ServiceChangeEvent",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type HealthCheckStatus does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ServiceChangeEvent_timestamp(ctx context.Context, field graphql.CollectedField, obj *model.ServiceChangeEvent) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ServiceChangeEvent_timestamp,
+		func(ctx context.Context) (any, error) {
+			return obj.Timestamp, nil
+		},
+		nil,
+		ec.marshalNTime2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ServiceChangeEvent_timestamp(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ServiceChangeEvent",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Time does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ServiceStats_total(ctx context.Context, field graphql.CollectedField, obj *model.ServiceStats) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ServiceStats_total,
+		func(ctx context.Context) (any, error) {
+			return obj.Total, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ServiceStats_total(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ServiceStats",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ServiceStats_active(ctx context.Context, field graphql.CollectedField, obj *model.ServiceStats) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ServiceStats_active,
+		func(ctx context.Context) (any, error) {
+			return obj.Active, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ServiceStats_active(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ServiceStats",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ServiceStats_expired(ctx context.Context, field graphql.CollectedField, obj *model.ServiceStats) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ServiceStats_expired,
+		func(ctx context.Context) (any, error) {
+			return obj.Expired, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ServiceStats_expired(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ServiceStats",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Session_id(ctx context.Context, field graphql.CollectedField, obj *model.Session) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Session_id,
+		func(ctx context.Context) (any, error) {
+			return obj.ID, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Session_id(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Session",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Session_name(ctx context.Context, field graphql.CollectedField, obj *model.Session) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Session_name,
+		func(ctx context.Context) (any, error) {
+			return obj.Name, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Session_name(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Session",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Session_node(ctx context.Context, field graphql.CollectedField, obj *model.Session) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Session_node,
+		func(ctx context.Context) (any, error) {
+			return obj.Node, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Session_node(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Session",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Session_ttl(ctx context.Context, field graphql.CollectedField, obj *model.Session) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Session_ttl,
+		func(ctx context.Context) (any, error) {
+			return obj.TTL, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Session_ttl(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Session",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Session_behavior(ctx context.Context, field graphql.CollectedField, obj *model.Session) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Session_behavior,
+		func(ctx context.Context) (any, error) {
+			return obj.Behavior, nil
+		},
+		nil,
+		ec.marshalNSessionBehavior2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSessionBehavior,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Session_behavior(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Session",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type SessionBehavior does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Session_checks(ctx context.Context, field graphql.CollectedField, obj *model.Session) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Session_checks,
+		func(ctx context.Context) (any, error) {
+			return obj.Checks, nil
+		},
+		nil,
+		ec.marshalNString2ᚕstringᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Session_checks(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Session",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Session_createIndex(ctx context.Context, field graphql.CollectedField, obj *model.Session) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Session_createIndex,
+		func(ctx context.Context) (any, error) {
+			return obj.CreateIndex, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Session_createIndex(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Session",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Session_modifyIndex(ctx context.Context, field graphql.CollectedField, obj *model.Session) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Session_modifyIndex,
+		func(ctx context.Context) (any, error) {
+			return obj.ModifyIndex, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Session_modifyIndex(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Session",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Session_expiresAt(ctx context.Context, field graphql.CollectedField, obj *model.Session) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Session_expiresAt,
+		func(ctx context.Context) (any, error) {
+			return obj.ExpiresAt, nil
+		},
+		nil,
+		ec.marshalOTime2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Session_expiresAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Session",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Time does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Subscription_kvChanged(ctx context.Context, field graphql.CollectedField) (ret func(ctx context.Context) graphql.Marshaler) {
+	return graphql.ResolveFieldStream(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Subscription_kvChanged,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Subscription().KvChanged(ctx, fc.Args["key"].(*string), fc.Args["prefix"].(*string))
+		},
+		nil,
+		ec.marshalNKVChangeEvent2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐKVChangeEvent,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Subscription_kvChanged(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Subscription",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "type":
+				return ec.fieldContext_KVChangeEvent_type(ctx, field)
+			case "key":
+				return ec.fieldContext_KVChangeEvent_key(ctx, field)
+			case "value":
+				return ec.fieldContext_KVChangeEvent_value(ctx, field)
+			case "oldValue":
+				return ec.fieldContext_KVChangeEvent_oldValue(ctx, field)
+			case "secret":
+				return ec.fieldContext_KVChangeEvent_secret(ctx, field)
+			case "timestamp":
+				return ec.fieldContext_KVChangeEvent_timestamp(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type KVChangeEvent", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Subscription_kvChanged_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Subscription_serviceChanged(ctx context.Context, field graphql.CollectedField) (ret func(ctx context.Context) graphql.Marshaler) {
+	return graphql.ResolveFieldStream(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Subscription_serviceChanged,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Subscription().ServiceChanged(ctx, fc.Args["name"].(*string), fc.Args["namespace"].(*string))
+		},
+		nil,
+		ec.marshalNServiceChangeEvent2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceChangeEvent,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Subscription_serviceChanged(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Subscription",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "type":
+				return ec.fieldContext_ServiceChangeEvent_type(ctx, field)
+			case "service":
+				return ec.fieldContext_ServiceChangeEvent_service(ctx, field)
+			case "check":
+				return ec.fieldContext_ServiceChangeEvent_check(ctx, field)
+			case "status":
+				return ec.fieldContext_ServiceChangeEvent_status(ctx, field)
+			case "previousStatus":
+				return ec.fieldContext_ServiceChangeEvent_previousStatus(ctx, field)
+			case "timestamp":
+				return ec.fieldContext_ServiceChangeEvent_timestamp(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type ServiceChangeEvent", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Subscription_serviceChanged_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _SystemHealth_status(ctx context.Context, field graphql.CollectedField, obj *model.SystemHealth) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_SystemHealth_status,
+		func(ctx context.Context) (any, error) {
+			return obj.Status, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_SystemHealth_status(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "SystemHealth",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _SystemHealth_version(ctx context.Context, field graphql.CollectedField, obj *model.SystemHealth) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_SystemHealth_version,
+		func(ctx context.Context) (any, error) {
+			return obj.Version, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_SystemHealth_version(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "SystemHealth",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _SystemHealth_uptime(ctx context.Context, field graphql.CollectedField, obj *model.SystemHealth) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_SystemHealth_uptime,
+		func(ctx context.Context) (any, error) {
+			return obj.Uptime, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_SystemHealth_uptime(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "SystemHealth",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _SystemHealth_timestamp(ctx context.Context, field graphql.CollectedField, obj *model.SystemHealth) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_SystemHealth_timestamp,
+		func(ctx context.Context) (any, error) {
+			return obj.Timestamp, nil
+		},
+		nil,
+		ec.marshalNTime2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_SystemHealth_timestamp(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "SystemHealth",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Time does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _SystemHealth_services(ctx context.Context, field graphql.CollectedField, obj *model.SystemHealth) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_SystemHealth_services,
+		func(ctx context.Context) (any, error) {
+			return obj.Services, nil
+		},
+		nil,
+		ec.marshalNServiceStats2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceStats,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_SystemHealth_services(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "SystemHealth",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "total":
+				return ec.fieldContext_ServiceStats_total(ctx, field)
+			case "active":
+				return ec.fieldContext_ServiceStats_active(ctx, field)
+			case "expired":
+				return ec.fieldContext_ServiceStats_expired(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type ServiceStats", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query___type_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _SystemHealth_kvStore(ctx context.Context, field graphql.CollectedField, obj *model.SystemHealth) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query___schema,
+		ec.fieldContext_SystemHealth_kvStore,
 		func(ctx context.Context) (any, error) {
-			return ec.introspectSchema()
+			return obj.KvStore, nil
 		},
 		nil,
-		ec.marshalO__Schema2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐSchema,
+		ec.marshalNKVStats2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐKVStats,
+		true,
 		true,
-		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query___schema(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_SystemHealth_kvStore(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "SystemHealth",
 		Field:      field,
-		IsMethod:   true,
+		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "description":
-				return ec.fieldContext___Schema_description(ctx, field)
-			case "types":
-				return ec.fieldContext___Schema_types(ctx, field)
-			case "queryType":
-				return ec.fieldContext___Schema_queryType(ctx, field)
-			case "mutationType":
-				return ec.fieldContext___Schema_mutationType(ctx, field)
-			case "subscriptionType":
-				return ec.fieldContext___Schema_subscriptionType(ctx, field)
-			case "directives":
-				return ec.fieldContext___Schema_directives(ctx, field)
+			case "totalKeys":
+				return ec.fieldContext_KVStats_totalKeys(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type __Schema", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type KVStats", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Service_name(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+func (ec *executionContext) _Webhook_name(ctx context.Context, field graphql.CollectedField, obj *model.Webhook) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Service_name,
+		ec.fieldContext_Webhook_name,
 		func(ctx context.Context) (any, error) {
 			return obj.Name, nil
 		},
@@ -2986,9 +6518,9 @@ func (ec *executionContext) _Service_name(ctx context.Context, field graphql.Col
 	)
 }
 
-func (ec *executionContext) fieldContext_Service_name(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Webhook_name(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Service",
+		Object:     "Webhook",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2999,14 +6531,14 @@ func (ec *executionContext) fieldContext_Service_name(_ context.Context, field g
 	return fc, nil
 }
 
-func (ec *executionContext) _Service_address(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+func (ec *executionContext) _Webhook_url(ctx context.Context, field graphql.CollectedField, obj *model.Webhook) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Service_address,
+		ec.fieldContext_Webhook_url,
 		func(ctx context.Context) (any, error) {
-			return obj.Address, nil
+			return obj.URL, nil
 		},
 		nil,
 		ec.marshalNString2string,
@@ -3015,9 +6547,9 @@ func (ec *executionContext) _Service_address(ctx context.Context, field graphql.
 	)
 }
 
-func (ec *executionContext) fieldContext_Service_address(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Webhook_url(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Service",
+		Object:     "Webhook",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -3028,112 +6560,112 @@ func (ec *executionContext) fieldContext_Service_address(_ context.Context, fiel
 	return fc, nil
 }
 
-func (ec *executionContext) _Service_port(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+func (ec *executionContext) _Webhook_signed(ctx context.Context, field graphql.CollectedField, obj *model.Webhook) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Service_port,
+		ec.fieldContext_Webhook_signed,
 		func(ctx context.Context) (any, error) {
-			return obj.Port, nil
+			return obj.Signed, nil
 		},
 		nil,
-		ec.marshalNInt2int,
+		ec.marshalNBoolean2bool,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Service_port(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Webhook_signed(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Service",
+		Object:     "Webhook",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Service_status(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+func (ec *executionContext) _Webhook_kind(ctx context.Context, field graphql.CollectedField, obj *model.Webhook) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Service_status,
+		ec.fieldContext_Webhook_kind,
 		func(ctx context.Context) (any, error) {
-			return obj.Status, nil
+			return obj.Kind, nil
 		},
 		nil,
-		ec.marshalNServiceStatus2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceStatus,
+		ec.marshalNWebhookKind2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookKind,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Service_status(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Webhook_kind(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Service",
+		Object:     "Webhook",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ServiceStatus does not have child fields")
+			return nil, errors.New("field of type WebhookKind does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Service_expiresAt(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+func (ec *executionContext) _Webhook_namespace(ctx context.Context, field graphql.CollectedField, obj *model.Webhook) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Service_expiresAt,
+		ec.fieldContext_Webhook_namespace,
 		func(ctx context.Context) (any, error) {
-			return obj.ExpiresAt, nil
+			return obj.Namespace, nil
 		},
 		nil,
-		ec.marshalNTime2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
-		true,
+		ec.marshalOString2ᚖstring,
 		true,
+		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_Service_expiresAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Webhook_namespace(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Service",
+		Object:     "Webhook",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Service_tags(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+func (ec *executionContext) _Webhook_pattern(ctx context.Context, field graphql.CollectedField, obj *model.Webhook) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Service_tags,
+		ec.fieldContext_Webhook_pattern,
 		func(ctx context.Context) (any, error) {
-			return obj.Tags, nil
+			return obj.Pattern, nil
 		},
 		nil,
-		ec.marshalNString2ᚕstringᚄ,
+		ec.marshalNString2string,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Service_tags(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Webhook_pattern(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Service",
+		Object:     "Webhook",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -3144,461 +6676,373 @@ func (ec *executionContext) fieldContext_Service_tags(_ context.Context, field g
 	return fc, nil
 }
 
-func (ec *executionContext) _Service_metadata(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+func (ec *executionContext) _Webhook_events(ctx context.Context, field graphql.CollectedField, obj *model.Webhook) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Service_metadata,
+		ec.fieldContext_Webhook_events,
 		func(ctx context.Context) (any, error) {
-			return obj.Metadata, nil
+			return obj.Events, nil
 		},
 		nil,
-		ec.marshalNMetadataEntry2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐMetadataEntryᚄ,
+		ec.marshalNString2ᚕstringᚄ,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Service_metadata(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Webhook_events(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Service",
+		Object:     "Webhook",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "key":
-				return ec.fieldContext_MetadataEntry_key(ctx, field)
-			case "value":
-				return ec.fieldContext_MetadataEntry_value(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type MetadataEntry", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Service_checks(ctx context.Context, field graphql.CollectedField, obj *model.Service) (ret graphql.Marshaler) {
+func (ec *executionContext) _Webhook_policies(ctx context.Context, field graphql.CollectedField, obj *model.Webhook) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Service_checks,
+		ec.fieldContext_Webhook_policies,
 		func(ctx context.Context) (any, error) {
-			return obj.Checks, nil
+			return obj.Policies, nil
 		},
 		nil,
-		ec.marshalNHealthCheck2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐHealthCheckᚄ,
+		ec.marshalNString2ᚕstringᚄ,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Service_checks(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Webhook_policies(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Service",
+		Object:     "Webhook",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_HealthCheck_id(ctx, field)
-			case "serviceId":
-				return ec.fieldContext_HealthCheck_serviceId(ctx, field)
-			case "name":
-				return ec.fieldContext_HealthCheck_name(ctx, field)
-			case "type":
-				return ec.fieldContext_HealthCheck_type(ctx, field)
-			case "status":
-				return ec.fieldContext_HealthCheck_status(ctx, field)
-			case "output":
-				return ec.fieldContext_HealthCheck_output(ctx, field)
-			case "interval":
-				return ec.fieldContext_HealthCheck_interval(ctx, field)
-			case "timeout":
-				return ec.fieldContext_HealthCheck_timeout(ctx, field)
-			case "lastChecked":
-				return ec.fieldContext_HealthCheck_lastChecked(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type HealthCheck", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ServiceChangeEvent_type(ctx context.Context, field graphql.CollectedField, obj *model.ServiceChangeEvent) (ret graphql.Marshaler) {
+func (ec *executionContext) _Webhook_createdAt(ctx context.Context, field graphql.CollectedField, obj *model.Webhook) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_ServiceChangeEvent_type,
+		ec.fieldContext_Webhook_createdAt,
 		func(ctx context.Context) (any, error) {
-			return obj.Type, nil
+			return obj.CreatedAt, nil
 		},
 		nil,
-		ec.marshalNServiceEventType2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceEventType,
-		true,
+		ec.marshalOTime2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
 		true,
+		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_ServiceChangeEvent_type(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Webhook_createdAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ServiceChangeEvent",
+		Object:     "Webhook",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ServiceEventType does not have child fields")
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ServiceChangeEvent_service(ctx context.Context, field graphql.CollectedField, obj *model.ServiceChangeEvent) (ret graphql.Marshaler) {
+func (ec *executionContext) _Webhook_updatedAt(ctx context.Context, field graphql.CollectedField, obj *model.Webhook) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_ServiceChangeEvent_service,
+		ec.fieldContext_Webhook_updatedAt,
 		func(ctx context.Context) (any, error) {
-			return obj.Service, nil
+			return obj.UpdatedAt, nil
 		},
 		nil,
-		ec.marshalNService2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐService,
-		true,
+		ec.marshalOTime2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
 		true,
+		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_ServiceChangeEvent_service(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Webhook_updatedAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ServiceChangeEvent",
+		Object:     "Webhook",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "name":
-				return ec.fieldContext_Service_name(ctx, field)
-			case "address":
-				return ec.fieldContext_Service_address(ctx, field)
-			case "port":
-				return ec.fieldContext_Service_port(ctx, field)
-			case "status":
-				return ec.fieldContext_Service_status(ctx, field)
-			case "expiresAt":
-				return ec.fieldContext_Service_expiresAt(ctx, field)
-			case "tags":
-				return ec.fieldContext_Service_tags(ctx, field)
-			case "metadata":
-				return ec.fieldContext_Service_metadata(ctx, field)
-			case "checks":
-				return ec.fieldContext_Service_checks(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Service", field.Name)
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ServiceChangeEvent_timestamp(ctx context.Context, field graphql.CollectedField, obj *model.ServiceChangeEvent) (ret graphql.Marshaler) {
+func (ec *executionContext) _WebhookDelivery_id(ctx context.Context, field graphql.CollectedField, obj *model.WebhookDelivery) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_ServiceChangeEvent_timestamp,
+		ec.fieldContext_WebhookDelivery_id,
 		func(ctx context.Context) (any, error) {
-			return obj.Timestamp, nil
+			return obj.ID, nil
 		},
 		nil,
-		ec.marshalNTime2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
+		ec.marshalNString2string,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_ServiceChangeEvent_timestamp(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WebhookDelivery_id(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ServiceChangeEvent",
+		Object:     "WebhookDelivery",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ServiceStats_total(ctx context.Context, field graphql.CollectedField, obj *model.ServiceStats) (ret graphql.Marshaler) {
+func (ec *executionContext) _WebhookDelivery_webhook(ctx context.Context, field graphql.CollectedField, obj *model.WebhookDelivery) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_ServiceStats_total,
+		ec.fieldContext_WebhookDelivery_webhook,
 		func(ctx context.Context) (any, error) {
-			return obj.Total, nil
+			return obj.Webhook, nil
 		},
 		nil,
-		ec.marshalNInt2int,
+		ec.marshalNString2string,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_ServiceStats_total(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WebhookDelivery_webhook(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ServiceStats",
+		Object:     "WebhookDelivery",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ServiceStats_active(ctx context.Context, field graphql.CollectedField, obj *model.ServiceStats) (ret graphql.Marshaler) {
+func (ec *executionContext) _WebhookDelivery_eventType(ctx context.Context, field graphql.CollectedField, obj *model.WebhookDelivery) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_ServiceStats_active,
+		ec.fieldContext_WebhookDelivery_eventType,
 		func(ctx context.Context) (any, error) {
-			return obj.Active, nil
+			return obj.EventType, nil
 		},
 		nil,
-		ec.marshalNInt2int,
+		ec.marshalNString2string,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_ServiceStats_active(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WebhookDelivery_eventType(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ServiceStats",
+		Object:     "WebhookDelivery",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ServiceStats_expired(ctx context.Context, field graphql.CollectedField, obj *model.ServiceStats) (ret graphql.Marshaler) {
+func (ec *executionContext) _WebhookDelivery_key(ctx context.Context, field graphql.CollectedField, obj *model.WebhookDelivery) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_ServiceStats_expired,
+		ec.fieldContext_WebhookDelivery_key,
 		func(ctx context.Context) (any, error) {
-			return obj.Expired, nil
+			return obj.Key, nil
 		},
 		nil,
-		ec.marshalNInt2int,
+		ec.marshalNString2string,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_ServiceStats_expired(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WebhookDelivery_key(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ServiceStats",
+		Object:     "WebhookDelivery",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Subscription_kvChanged(ctx context.Context, field graphql.CollectedField) (ret func(ctx context.Context) graphql.Marshaler) {
-	return graphql.ResolveFieldStream(
+func (ec *executionContext) _WebhookDelivery_index(ctx context.Context, field graphql.CollectedField, obj *model.WebhookDelivery) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Subscription_kvChanged,
+		ec.fieldContext_WebhookDelivery_index,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Subscription().KvChanged(ctx, fc.Args["key"].(*string), fc.Args["prefix"].(*string))
+			return obj.Index, nil
 		},
 		nil,
-		ec.marshalNKVChangeEvent2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐKVChangeEvent,
+		ec.marshalNInt2int,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Subscription_kvChanged(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WebhookDelivery_index(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Subscription",
+		Object:     "WebhookDelivery",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "type":
-				return ec.fieldContext_KVChangeEvent_type(ctx, field)
-			case "key":
-				return ec.fieldContext_KVChangeEvent_key(ctx, field)
-			case "value":
-				return ec.fieldContext_KVChangeEvent_value(ctx, field)
-			case "oldValue":
-				return ec.fieldContext_KVChangeEvent_oldValue(ctx, field)
-			case "timestamp":
-				return ec.fieldContext_KVChangeEvent_timestamp(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type KVChangeEvent", field.Name)
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Subscription_kvChanged_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Subscription_serviceChanged(ctx context.Context, field graphql.CollectedField) (ret func(ctx context.Context) graphql.Marshaler) {
-	return graphql.ResolveFieldStream(
+func (ec *executionContext) _WebhookDelivery_status(ctx context.Context, field graphql.CollectedField, obj *model.WebhookDelivery) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Subscription_serviceChanged,
+		ec.fieldContext_WebhookDelivery_status,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Subscription().ServiceChanged(ctx, fc.Args["name"].(*string))
+			return obj.Status, nil
 		},
 		nil,
-		ec.marshalNServiceChangeEvent2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceChangeEvent,
+		ec.marshalNWebhookDeliveryStatus2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookDeliveryStatus,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Subscription_serviceChanged(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WebhookDelivery_status(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Subscription",
+		Object:     "WebhookDelivery",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "type":
-				return ec.fieldContext_ServiceChangeEvent_type(ctx, field)
-			case "service":
-				return ec.fieldContext_ServiceChangeEvent_service(ctx, field)
-			case "timestamp":
-				return ec.fieldContext_ServiceChangeEvent_timestamp(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type ServiceChangeEvent", field.Name)
+			return nil, errors.New("field of type WebhookDeliveryStatus does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Subscription_serviceChanged_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _SystemHealth_status(ctx context.Context, field graphql.CollectedField, obj *model.SystemHealth) (ret graphql.Marshaler) {
+func (ec *executionContext) _WebhookDelivery_attempts(ctx context.Context, field graphql.CollectedField, obj *model.WebhookDelivery) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_SystemHealth_status,
+		ec.fieldContext_WebhookDelivery_attempts,
 		func(ctx context.Context) (any, error) {
-			return obj.Status, nil
+			return obj.Attempts, nil
 		},
 		nil,
-		ec.marshalNString2string,
+		ec.marshalNInt2int,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_SystemHealth_status(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WebhookDelivery_attempts(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "SystemHealth",
+		Object:     "WebhookDelivery",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _SystemHealth_version(ctx context.Context, field graphql.CollectedField, obj *model.SystemHealth) (ret graphql.Marshaler) {
+func (ec *executionContext) _WebhookDelivery_statusCode(ctx context.Context, field graphql.CollectedField, obj *model.WebhookDelivery) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_SystemHealth_version,
+		ec.fieldContext_WebhookDelivery_statusCode,
 		func(ctx context.Context) (any, error) {
-			return obj.Version, nil
+			return obj.StatusCode, nil
 		},
 		nil,
-		ec.marshalNString2string,
-		true,
+		ec.marshalOInt2ᚖint,
 		true,
+		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_SystemHealth_version(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WebhookDelivery_statusCode(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "SystemHealth",
+		Object:     "WebhookDelivery",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _SystemHealth_uptime(ctx context.Context, field graphql.CollectedField, obj *model.SystemHealth) (ret graphql.Marshaler) {
+func (ec *executionContext) _WebhookDelivery_error(ctx context.Context, field graphql.CollectedField, obj *model.WebhookDelivery) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_SystemHealth_uptime,
+		ec.fieldContext_WebhookDelivery_error,
 		func(ctx context.Context) (any, error) {
-			return obj.Uptime, nil
+			return obj.Error, nil
 		},
 		nil,
-		ec.marshalNString2string,
-		true,
+		ec.marshalOString2ᚖstring,
 		true,
+		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_SystemHealth_uptime(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WebhookDelivery_error(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "SystemHealth",
+		Object:     "WebhookDelivery",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -3609,14 +7053,14 @@ func (ec *executionContext) fieldContext_SystemHealth_uptime(_ context.Context,
 	return fc, nil
 }
 
-func (ec *executionContext) _SystemHealth_timestamp(ctx context.Context, field graphql.CollectedField, obj *model.SystemHealth) (ret graphql.Marshaler) {
+func (ec *executionContext) _WebhookDelivery_createdAt(ctx context.Context, field graphql.CollectedField, obj *model.WebhookDelivery) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_SystemHealth_timestamp,
+		ec.fieldContext_WebhookDelivery_createdAt,
 		func(ctx context.Context) (any, error) {
-			return obj.Timestamp, nil
+			return obj.CreatedAt, nil
 		},
 		nil,
 		ec.marshalNTime2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
@@ -3625,9 +7069,9 @@ func (ec *executionContext) _SystemHealth_timestamp(ctx context.Context, field g
 	)
 }
 
-func (ec *executionContext) fieldContext_SystemHealth_timestamp(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WebhookDelivery_createdAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "SystemHealth",
+		Object:     "WebhookDelivery",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -3638,71 +7082,59 @@ func (ec *executionContext) fieldContext_SystemHealth_timestamp(_ context.Contex
 	return fc, nil
 }
 
-func (ec *executionContext) _SystemHealth_services(ctx context.Context, field graphql.CollectedField, obj *model.SystemHealth) (ret graphql.Marshaler) {
+func (ec *executionContext) _WebhookDelivery_nextAttempt(ctx context.Context, field graphql.CollectedField, obj *model.WebhookDelivery) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_SystemHealth_services,
+		ec.fieldContext_WebhookDelivery_nextAttempt,
 		func(ctx context.Context) (any, error) {
-			return obj.Services, nil
+			return obj.NextAttempt, nil
 		},
 		nil,
-		ec.marshalNServiceStats2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceStats,
-		true,
+		ec.marshalOTime2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
 		true,
+		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_SystemHealth_services(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WebhookDelivery_nextAttempt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "SystemHealth",
+		Object:     "WebhookDelivery",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "total":
-				return ec.fieldContext_ServiceStats_total(ctx, field)
-			case "active":
-				return ec.fieldContext_ServiceStats_active(ctx, field)
-			case "expired":
-				return ec.fieldContext_ServiceStats_expired(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type ServiceStats", field.Name)
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _SystemHealth_kvStore(ctx context.Context, field graphql.CollectedField, obj *model.SystemHealth) (ret graphql.Marshaler) {
+func (ec *executionContext) _WebhookDelivery_completedAt(ctx context.Context, field graphql.CollectedField, obj *model.WebhookDelivery) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_SystemHealth_kvStore,
+		ec.fieldContext_WebhookDelivery_completedAt,
 		func(ctx context.Context) (any, error) {
-			return obj.KvStore, nil
+			return obj.CompletedAt, nil
 		},
 		nil,
-		ec.marshalNKVStats2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐKVStats,
-		true,
+		ec.marshalOTime2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐTime,
 		true,
+		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_SystemHealth_kvStore(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WebhookDelivery_completedAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "SystemHealth",
+		Object:     "WebhookDelivery",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "totalKeys":
-				return ec.fieldContext_KVStats_totalKeys(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type KVStats", field.Name)
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
@@ -5154,6 +8586,61 @@ func (ec *executionContext) fieldContext___Type_isOneOf(_ context.Context, field
 
 // region    **************************** input.gotpl *****************************
 
+func (ec *executionContext) unmarshalInputCreateSessionInput(ctx context.Context, obj any) (model.CreateSessionInput, error) {
+	var it model.CreateSessionInput
+	asMap := map[string]any{}
+	for k, v := range obj.(map[string]any) {
+		asMap[k] = v
+	}
+
+	fieldsInOrder := [...]string{"name", "node", "ttl", "behavior", "checks"}
+	for _, k := range fieldsInOrder {
+		v, ok := asMap[k]
+		if !ok {
+			continue
+		}
+		switch k {
+		case "name":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Name = data
+		case "node":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("node"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Node = data
+		case "ttl":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("ttl"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.TTL = data
+		case "behavior":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("behavior"))
+			data, err := ec.unmarshalOSessionBehavior2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSessionBehavior(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Behavior = data
+		case "checks":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("checks"))
+			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Checks = data
+		}
+	}
+
+	return it, nil
+}
+
 func (ec *executionContext) unmarshalInputMetadataFilter(ctx context.Context, obj any) (model.MetadataFilter, error) {
 	var it model.MetadataFilter
 	asMap := map[string]any{}
@@ -5229,13 +8716,20 @@ func (ec *executionContext) unmarshalInputRegisterServiceInput(ctx context.Conte
 		asMap[k] = v
 	}
 
-	fieldsInOrder := [...]string{"name", "address", "port", "tags", "metadata"}
+	fieldsInOrder := [...]string{"id", "name", "namespace", "node", "address", "port", "tags", "metadata"}
 	for _, k := range fieldsInOrder {
 		v, ok := asMap[k]
 		if !ok {
 			continue
 		}
 		switch k {
+		case "id":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("id"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.ID = data
 		case "name":
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
@@ -5243,6 +8737,20 @@ func (ec *executionContext) unmarshalInputRegisterServiceInput(ctx context.Conte
 				return it, err
 			}
 			it.Name = data
+		case "namespace":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Namespace = data
+		case "node":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("node"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Node = data
 		case "address":
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("address"))
 			data, err := ec.unmarshalNString2string(ctx, v)
@@ -5277,6 +8785,82 @@ func (ec *executionContext) unmarshalInputRegisterServiceInput(ctx context.Conte
 	return it, nil
 }
 
+func (ec *executionContext) unmarshalInputWebhookInput(ctx context.Context, obj any) (model.WebhookInput, error) {
+	var it model.WebhookInput
+	asMap := map[string]any{}
+	for k, v := range obj.(map[string]any) {
+		asMap[k] = v
+	}
+
+	fieldsInOrder := [...]string{"name", "url", "secret", "clearSecret", "kind", "namespace", "pattern", "events"}
+	for _, k := range fieldsInOrder {
+		v, ok := asMap[k]
+		if !ok {
+			continue
+		}
+		switch k {
+		case "name":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
+			data, err := ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Name = data
+		case "url":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("url"))
+			data, err := ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.URL = data
+		case "secret":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("secret"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Secret = data
+		case "clearSecret":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("clearSecret"))
+			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.ClearSecret = data
+		case "kind":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("kind"))
+			data, err := ec.unmarshalOWebhookKind2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookKind(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Kind = data
+		case "namespace":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Namespace = data
+		case "pattern":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pattern"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Pattern = data
+		case "events":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("events"))
+			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Events = data
+		}
+	}
+
+	return it, nil
+}
+
 // endregion **************************** input.gotpl *****************************
 
 // region    ************************** interface.gotpl ***************************
@@ -5295,40 +8879,91 @@ func (ec *executionContext) _HealthCheck(ctx context.Context, sel ast.SelectionS
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("HealthCheck")
-		case "id":
-			out.Values[i] = ec._HealthCheck_id(ctx, field, obj)
-			if out.Values[i] == graphql.Null {
-				out.Invalids++
-			}
-		case "serviceId":
-			out.Values[i] = ec._HealthCheck_serviceId(ctx, field, obj)
-			if out.Values[i] == graphql.Null {
-				out.Invalids++
-			}
-		case "name":
-			out.Values[i] = ec._HealthCheck_name(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("HealthCheck")
+		case "id":
+			out.Values[i] = ec._HealthCheck_id(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "serviceId":
+			out.Values[i] = ec._HealthCheck_serviceId(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "name":
+			out.Values[i] = ec._HealthCheck_name(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "type":
+			out.Values[i] = ec._HealthCheck_type(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "status":
+			out.Values[i] = ec._HealthCheck_status(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "output":
+			out.Values[i] = ec._HealthCheck_output(ctx, field, obj)
+		case "interval":
+			out.Values[i] = ec._HealthCheck_interval(ctx, field, obj)
+		case "timeout":
+			out.Values[i] = ec._HealthCheck_timeout(ctx, field, obj)
+		case "lastChecked":
+			out.Values[i] = ec._HealthCheck_lastChecked(ctx, field, obj)
+		case "criticalSince":
+			out.Values[i] = ec._HealthCheck_criticalSince(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var healthCheckResultImplementors = []string{"HealthCheckResult"}
+
+func (ec *executionContext) _HealthCheckResult(ctx context.Context, sel ast.SelectionSet, obj *model.HealthCheckResult) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, healthCheckResultImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("HealthCheckResult")
+		case "status":
+			out.Values[i] = ec._HealthCheckResult_status(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "type":
-			out.Values[i] = ec._HealthCheck_type(ctx, field, obj)
+		case "output":
+			out.Values[i] = ec._HealthCheckResult_output(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "status":
-			out.Values[i] = ec._HealthCheck_status(ctx, field, obj)
+		case "time":
+			out.Values[i] = ec._HealthCheckResult_time(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "output":
-			out.Values[i] = ec._HealthCheck_output(ctx, field, obj)
-		case "interval":
-			out.Values[i] = ec._HealthCheck_interval(ctx, field, obj)
-		case "timeout":
-			out.Values[i] = ec._HealthCheck_timeout(ctx, field, obj)
-		case "lastChecked":
-			out.Values[i] = ec._HealthCheck_lastChecked(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -5377,6 +9012,11 @@ func (ec *executionContext) _KVChangeEvent(ctx context.Context, sel ast.Selectio
 			out.Values[i] = ec._KVChangeEvent_value(ctx, field, obj)
 		case "oldValue":
 			out.Values[i] = ec._KVChangeEvent_oldValue(ctx, field, obj)
+		case "secret":
+			out.Values[i] = ec._KVChangeEvent_secret(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		case "timestamp":
 			out.Values[i] = ec._KVChangeEvent_timestamp(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -5475,10 +9115,19 @@ func (ec *executionContext) _KVPair(ctx context.Context, sel ast.SelectionSet, o
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "secret":
+			out.Values[i] = ec._KVPair_secret(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		case "createdAt":
 			out.Values[i] = ec._KVPair_createdAt(ctx, field, obj)
 		case "updatedAt":
 			out.Values[i] = ec._KVPair_updatedAt(ctx, field, obj)
+		case "session":
+			out.Values[i] = ec._KVPair_session(ctx, field, obj)
+		case "expiresAt":
+			out.Values[i] = ec._KVPair_expiresAt(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -5643,6 +9292,108 @@ func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "createSession":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_createSession(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "renewSession":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_renewSession(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "destroySession":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_destroySession(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "acquireLock":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_acquireLock(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "releaseLock":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_releaseLock(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "setWebhook":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_setWebhook(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "deleteWebhook":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_deleteWebhook(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var namespaceImplementors = []string{"Namespace"}
+
+func (ec *executionContext) _Namespace(ctx context.Context, sel ast.SelectionSet, obj *model.Namespace) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, namespaceImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Namespace")
+		case "name":
+			out.Values[i] = ec._Namespace_name(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "description":
+			out.Values[i] = ec._Namespace_description(ctx, field, obj)
+		case "metadata":
+			out.Values[i] = ec._Namespace_metadata(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "createIndex":
+			out.Values[i] = ec._Namespace_createIndex(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "createdAt":
+			out.Values[i] = ec._Namespace_createdAt(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -5668,24 +9419,172 @@ func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet)
 
 var queryImplementors = []string{"Query"}
 
-func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, queryImplementors)
-	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
-		Object: "Query",
-	})
+func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, queryImplementors)
+	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+		Object: "Query",
+	})
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		innerCtx := graphql.WithRootFieldContext(ctx, &graphql.RootFieldContext{
+			Object: field.Name,
+			Field:  field,
+		})
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Query")
+		case "health":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_health(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "kv":
+			field := field
+
+			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_kv(ctx, field)
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "kvList":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_kvList(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "service":
+			field := field
+
+			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_service(ctx, field)
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "serviceInstances":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_serviceInstances(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "services":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_services(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "servicesCount":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_servicesCount(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
 
-	out := graphql.NewFieldSet(fields)
-	deferred := make(map[string]*graphql.FieldSet)
-	for i, field := range fields {
-		innerCtx := graphql.WithRootFieldContext(ctx, &graphql.RootFieldContext{
-			Object: field.Name,
-			Field:  field,
-		})
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
 
-		switch field.Name {
-		case "__typename":
-			out.Values[i] = graphql.MarshalString("Query")
-		case "health":
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "servicesByTags":
 			field := field
 
 			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
@@ -5694,7 +9593,7 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 						ec.Error(ctx, ec.Recover(ctx, r))
 					}
 				}()
-				res = ec._Query_health(ctx, field)
+				res = ec._Query_servicesByTags(ctx, field)
 				if res == graphql.Null {
 					atomic.AddUint32(&fs.Invalids, 1)
 				}
@@ -5707,16 +9606,19 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 			}
 
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
-		case "kv":
+		case "servicesByMetadata":
 			field := field
 
-			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
 				defer func() {
 					if r := recover(); r != nil {
 						ec.Error(ctx, ec.Recover(ctx, r))
 					}
 				}()
-				res = ec._Query_kv(ctx, field)
+				res = ec._Query_servicesByMetadata(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
 				return res
 			}
 
@@ -5726,7 +9628,7 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 			}
 
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
-		case "kvList":
+		case "servicesByQuery":
 			field := field
 
 			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
@@ -5735,7 +9637,7 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 						ec.Error(ctx, ec.Recover(ctx, r))
 					}
 				}()
-				res = ec._Query_kvList(ctx, field)
+				res = ec._Query_servicesByQuery(ctx, field)
 				if res == graphql.Null {
 					atomic.AddUint32(&fs.Invalids, 1)
 				}
@@ -5748,7 +9650,7 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 			}
 
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
-		case "service":
+		case "healthCheckHistory":
 			field := field
 
 			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
@@ -5757,7 +9659,7 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 						ec.Error(ctx, ec.Recover(ctx, r))
 					}
 				}()
-				res = ec._Query_service(ctx, field)
+				res = ec._Query_healthCheckHistory(ctx, field)
 				return res
 			}
 
@@ -5767,7 +9669,7 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 			}
 
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
-		case "services":
+		case "namespaces":
 			field := field
 
 			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
@@ -5776,7 +9678,7 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 						ec.Error(ctx, ec.Recover(ctx, r))
 					}
 				}()
-				res = ec._Query_services(ctx, field)
+				res = ec._Query_namespaces(ctx, field)
 				if res == graphql.Null {
 					atomic.AddUint32(&fs.Invalids, 1)
 				}
@@ -5789,7 +9691,7 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 			}
 
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
-		case "servicesCount":
+		case "webhooks":
 			field := field
 
 			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
@@ -5798,7 +9700,7 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 						ec.Error(ctx, ec.Recover(ctx, r))
 					}
 				}()
-				res = ec._Query_servicesCount(ctx, field)
+				res = ec._Query_webhooks(ctx, field)
 				if res == graphql.Null {
 					atomic.AddUint32(&fs.Invalids, 1)
 				}
@@ -5811,19 +9713,16 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 			}
 
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
-		case "servicesByTags":
+		case "webhook":
 			field := field
 
-			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
 				defer func() {
 					if r := recover(); r != nil {
 						ec.Error(ctx, ec.Recover(ctx, r))
 					}
 				}()
-				res = ec._Query_servicesByTags(ctx, field)
-				if res == graphql.Null {
-					atomic.AddUint32(&fs.Invalids, 1)
-				}
+				res = ec._Query_webhook(ctx, field)
 				return res
 			}
 
@@ -5833,19 +9732,16 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 			}
 
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
-		case "servicesByMetadata":
+		case "webhookDeliveries":
 			field := field
 
-			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
 				defer func() {
 					if r := recover(); r != nil {
 						ec.Error(ctx, ec.Recover(ctx, r))
 					}
 				}()
-				res = ec._Query_servicesByMetadata(ctx, field)
-				if res == graphql.Null {
-					atomic.AddUint32(&fs.Invalids, 1)
-				}
+				res = ec._Query_webhookDeliveries(ctx, field)
 				return res
 			}
 
@@ -5855,7 +9751,26 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 			}
 
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
-		case "servicesByQuery":
+		case "session":
+			field := field
+
+			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_session(ctx, field)
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "sessions":
 			field := field
 
 			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
@@ -5864,7 +9779,7 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 						ec.Error(ctx, ec.Recover(ctx, r))
 					}
 				}()
-				res = ec._Query_servicesByQuery(ctx, field)
+				res = ec._Query_sessions(ctx, field)
 				if res == graphql.Null {
 					atomic.AddUint32(&fs.Invalids, 1)
 				}
@@ -5919,11 +9834,23 @@ func (ec *executionContext) _Service(ctx context.Context, sel ast.SelectionSet,
 		switch field.Name {
 		case "__typename":
 			out.Values[i] = graphql.MarshalString("Service")
+		case "id":
+			out.Values[i] = ec._Service_id(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		case "name":
 			out.Values[i] = ec._Service_name(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "namespace":
+			out.Values[i] = ec._Service_namespace(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "node":
+			out.Values[i] = ec._Service_node(ctx, field, obj)
 		case "address":
 			out.Values[i] = ec._Service_address(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -5979,32 +9906,240 @@ func (ec *executionContext) _Service(ctx context.Context, sel ast.SelectionSet,
 		})
 	}
 
-	return out
+	return out
+}
+
+var serviceChangeEventImplementors = []string{"ServiceChangeEvent"}
+
+func (ec *executionContext) _ServiceChangeEvent(ctx context.Context, sel ast.SelectionSet, obj *model.ServiceChangeEvent) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, serviceChangeEventImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("ServiceChangeEvent")
+		case "type":
+			out.Values[i] = ec._ServiceChangeEvent_type(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "service":
+			out.Values[i] = ec._ServiceChangeEvent_service(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "check":
+			out.Values[i] = ec._ServiceChangeEvent_check(ctx, field, obj)
+		case "status":
+			out.Values[i] = ec._ServiceChangeEvent_status(ctx, field, obj)
+		case "previousStatus":
+			out.Values[i] = ec._ServiceChangeEvent_previousStatus(ctx, field, obj)
+		case "timestamp":
+			out.Values[i] = ec._ServiceChangeEvent_timestamp(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var serviceStatsImplementors = []string{"ServiceStats"}
+
+func (ec *executionContext) _ServiceStats(ctx context.Context, sel ast.SelectionSet, obj *model.ServiceStats) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, serviceStatsImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("ServiceStats")
+		case "total":
+			out.Values[i] = ec._ServiceStats_total(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "active":
+			out.Values[i] = ec._ServiceStats_active(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "expired":
+			out.Values[i] = ec._ServiceStats_expired(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var sessionImplementors = []string{"Session"}
+
+func (ec *executionContext) _Session(ctx context.Context, sel ast.SelectionSet, obj *model.Session) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, sessionImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Session")
+		case "id":
+			out.Values[i] = ec._Session_id(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "name":
+			out.Values[i] = ec._Session_name(ctx, field, obj)
+		case "node":
+			out.Values[i] = ec._Session_node(ctx, field, obj)
+		case "ttl":
+			out.Values[i] = ec._Session_ttl(ctx, field, obj)
+		case "behavior":
+			out.Values[i] = ec._Session_behavior(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "checks":
+			out.Values[i] = ec._Session_checks(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "createIndex":
+			out.Values[i] = ec._Session_createIndex(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "modifyIndex":
+			out.Values[i] = ec._Session_modifyIndex(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "expiresAt":
+			out.Values[i] = ec._Session_expiresAt(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var subscriptionImplementors = []string{"Subscription"}
+
+func (ec *executionContext) _Subscription(ctx context.Context, sel ast.SelectionSet) func(ctx context.Context) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, subscriptionImplementors)
+	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+		Object: "Subscription",
+	})
+	if len(fields) != 1 {
+		graphql.AddErrorf(ctx, "must subscribe to exactly one stream")
+		return nil
+	}
+
+	switch fields[0].Name {
+	case "kvChanged":
+		return ec._Subscription_kvChanged(ctx, fields[0])
+	case "serviceChanged":
+		return ec._Subscription_serviceChanged(ctx, fields[0])
+	default:
+		panic("unknown field " + strconv.Quote(fields[0].Name))
+	}
 }
 
-var serviceChangeEventImplementors = []string{"ServiceChangeEvent"}
+var systemHealthImplementors = []string{"SystemHealth"}
 
-func (ec *executionContext) _ServiceChangeEvent(ctx context.Context, sel ast.SelectionSet, obj *model.ServiceChangeEvent) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, serviceChangeEventImplementors)
+func (ec *executionContext) _SystemHealth(ctx context.Context, sel ast.SelectionSet, obj *model.SystemHealth) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, systemHealthImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	deferred := make(map[string]*graphql.FieldSet)
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("ServiceChangeEvent")
-		case "type":
-			out.Values[i] = ec._ServiceChangeEvent_type(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("SystemHealth")
+		case "status":
+			out.Values[i] = ec._SystemHealth_status(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "service":
-			out.Values[i] = ec._ServiceChangeEvent_service(ctx, field, obj)
+		case "version":
+			out.Values[i] = ec._SystemHealth_version(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "uptime":
+			out.Values[i] = ec._SystemHealth_uptime(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
 		case "timestamp":
-			out.Values[i] = ec._ServiceChangeEvent_timestamp(ctx, field, obj)
+			out.Values[i] = ec._SystemHealth_timestamp(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "services":
+			out.Values[i] = ec._SystemHealth_services(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "kvStore":
+			out.Values[i] = ec._SystemHealth_kvStore(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
@@ -6031,32 +10166,58 @@ func (ec *executionContext) _ServiceChangeEvent(ctx context.Context, sel ast.Sel
 	return out
 }
 
-var serviceStatsImplementors = []string{"ServiceStats"}
+var webhookImplementors = []string{"Webhook"}
 
-func (ec *executionContext) _ServiceStats(ctx context.Context, sel ast.SelectionSet, obj *model.ServiceStats) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, serviceStatsImplementors)
+func (ec *executionContext) _Webhook(ctx context.Context, sel ast.SelectionSet, obj *model.Webhook) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, webhookImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	deferred := make(map[string]*graphql.FieldSet)
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("ServiceStats")
-		case "total":
-			out.Values[i] = ec._ServiceStats_total(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("Webhook")
+		case "name":
+			out.Values[i] = ec._Webhook_name(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "active":
-			out.Values[i] = ec._ServiceStats_active(ctx, field, obj)
+		case "url":
+			out.Values[i] = ec._Webhook_url(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "expired":
-			out.Values[i] = ec._ServiceStats_expired(ctx, field, obj)
+		case "signed":
+			out.Values[i] = ec._Webhook_signed(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "kind":
+			out.Values[i] = ec._Webhook_kind(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "namespace":
+			out.Values[i] = ec._Webhook_namespace(ctx, field, obj)
+		case "pattern":
+			out.Values[i] = ec._Webhook_pattern(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "events":
+			out.Values[i] = ec._Webhook_events(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "policies":
+			out.Values[i] = ec._Webhook_policies(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "createdAt":
+			out.Values[i] = ec._Webhook_createdAt(ctx, field, obj)
+		case "updatedAt":
+			out.Values[i] = ec._Webhook_updatedAt(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -6080,69 +10241,65 @@ func (ec *executionContext) _ServiceStats(ctx context.Context, sel ast.Selection
 	return out
 }
 
-var subscriptionImplementors = []string{"Subscription"}
-
-func (ec *executionContext) _Subscription(ctx context.Context, sel ast.SelectionSet) func(ctx context.Context) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, subscriptionImplementors)
-	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
-		Object: "Subscription",
-	})
-	if len(fields) != 1 {
-		graphql.AddErrorf(ctx, "must subscribe to exactly one stream")
-		return nil
-	}
-
-	switch fields[0].Name {
-	case "kvChanged":
-		return ec._Subscription_kvChanged(ctx, fields[0])
-	case "serviceChanged":
-		return ec._Subscription_serviceChanged(ctx, fields[0])
-	default:
-		panic("unknown field " + strconv.Quote(fields[0].Name))
-	}
-}
-
-var systemHealthImplementors = []string{"SystemHealth"}
+var webhookDeliveryImplementors = []string{"WebhookDelivery"}
 
-func (ec *executionContext) _SystemHealth(ctx context.Context, sel ast.SelectionSet, obj *model.SystemHealth) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, systemHealthImplementors)
+func (ec *executionContext) _WebhookDelivery(ctx context.Context, sel ast.SelectionSet, obj *model.WebhookDelivery) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, webhookDeliveryImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	deferred := make(map[string]*graphql.FieldSet)
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("SystemHealth")
-		case "status":
-			out.Values[i] = ec._SystemHealth_status(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("WebhookDelivery")
+		case "id":
+			out.Values[i] = ec._WebhookDelivery_id(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "version":
-			out.Values[i] = ec._SystemHealth_version(ctx, field, obj)
+		case "webhook":
+			out.Values[i] = ec._WebhookDelivery_webhook(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "uptime":
-			out.Values[i] = ec._SystemHealth_uptime(ctx, field, obj)
+		case "eventType":
+			out.Values[i] = ec._WebhookDelivery_eventType(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "timestamp":
-			out.Values[i] = ec._SystemHealth_timestamp(ctx, field, obj)
+		case "key":
+			out.Values[i] = ec._WebhookDelivery_key(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "services":
-			out.Values[i] = ec._SystemHealth_services(ctx, field, obj)
+		case "index":
+			out.Values[i] = ec._WebhookDelivery_index(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "kvStore":
-			out.Values[i] = ec._SystemHealth_kvStore(ctx, field, obj)
+		case "status":
+			out.Values[i] = ec._WebhookDelivery_status(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "attempts":
+			out.Values[i] = ec._WebhookDelivery_attempts(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "statusCode":
+			out.Values[i] = ec._WebhookDelivery_statusCode(ctx, field, obj)
+		case "error":
+			out.Values[i] = ec._WebhookDelivery_error(ctx, field, obj)
+		case "createdAt":
+			out.Values[i] = ec._WebhookDelivery_createdAt(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "nextAttempt":
+			out.Values[i] = ec._WebhookDelivery_nextAttempt(ctx, field, obj)
+		case "completedAt":
+			out.Values[i] = ec._WebhookDelivery_completedAt(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -6571,6 +10728,16 @@ func (ec *executionContext) marshalNHealthCheck2ᚖgithubᚗcomᚋneogan74ᚋkon
 	return ec._HealthCheck(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalNHealthCheckResult2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐHealthCheckResult(ctx context.Context, sel ast.SelectionSet, v *model.HealthCheckResult) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._HealthCheckResult(ctx, sel, v)
+}
+
 func (ec *executionContext) unmarshalNHealthCheckStatus2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐHealthCheckStatus(ctx context.Context, v any) (model.HealthCheckStatus, error) {
 	var res model.HealthCheckStatus
 	err := res.UnmarshalGQL(v)
@@ -6792,6 +10959,60 @@ func (ec *executionContext) unmarshalNMetadataInput2ᚖgithubᚗcomᚋneogan74
 	return &res, graphql.ErrorOnPath(ctx, err)
 }
 
+func (ec *executionContext) marshalNNamespace2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐNamespaceᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.Namespace) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNNamespace2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐNamespace(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNNamespace2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐNamespace(ctx context.Context, sel ast.SelectionSet, v *model.Namespace) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._Namespace(ctx, sel, v)
+}
+
 func (ec *executionContext) unmarshalNRegisterServiceInput2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐRegisterServiceInput(ctx context.Context, v any) (model.RegisterServiceInput, error) {
 	res, err := ec.unmarshalInputRegisterServiceInput(ctx, v)
 	return res, graphql.ErrorOnPath(ctx, err)
@@ -6850,52 +11071,120 @@ func (ec *executionContext) marshalNService2ᚖgithubᚗcomᚋneogan74ᚋkonsul
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
 		}
-		return graphql.Null
-	}
-	return ec._Service(ctx, sel, v)
-}
+		return graphql.Null
+	}
+	return ec._Service(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalNServiceChangeEvent2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceChangeEvent(ctx context.Context, sel ast.SelectionSet, v model.ServiceChangeEvent) graphql.Marshaler {
+	return ec._ServiceChangeEvent(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNServiceChangeEvent2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceChangeEvent(ctx context.Context, sel ast.SelectionSet, v *model.ServiceChangeEvent) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._ServiceChangeEvent(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalNServiceEventType2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceEventType(ctx context.Context, v any) (model.ServiceEventType, error) {
+	var res model.ServiceEventType
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNServiceEventType2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceEventType(ctx context.Context, sel ast.SelectionSet, v model.ServiceEventType) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) marshalNServiceStats2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceStats(ctx context.Context, sel ast.SelectionSet, v *model.ServiceStats) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._ServiceStats(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalNServiceStatus2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceStatus(ctx context.Context, v any) (model.ServiceStatus, error) {
+	var res model.ServiceStatus
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNServiceStatus2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceStatus(ctx context.Context, sel ast.SelectionSet, v model.ServiceStatus) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) marshalNSession2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSession(ctx context.Context, sel ast.SelectionSet, v model.Session) graphql.Marshaler {
+	return ec._Session(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNSession2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSessionᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.Session) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNSession2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSession(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
 
-func (ec *executionContext) marshalNServiceChangeEvent2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceChangeEvent(ctx context.Context, sel ast.SelectionSet, v model.ServiceChangeEvent) graphql.Marshaler {
-	return ec._ServiceChangeEvent(ctx, sel, &v)
-}
+	}
+	wg.Wait()
 
-func (ec *executionContext) marshalNServiceChangeEvent2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceChangeEvent(ctx context.Context, sel ast.SelectionSet, v *model.ServiceChangeEvent) graphql.Marshaler {
-	if v == nil {
-		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
-			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
 		}
-		return graphql.Null
 	}
-	return ec._ServiceChangeEvent(ctx, sel, v)
-}
-
-func (ec *executionContext) unmarshalNServiceEventType2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceEventType(ctx context.Context, v any) (model.ServiceEventType, error) {
-	var res model.ServiceEventType
-	err := res.UnmarshalGQL(v)
-	return res, graphql.ErrorOnPath(ctx, err)
-}
 
-func (ec *executionContext) marshalNServiceEventType2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceEventType(ctx context.Context, sel ast.SelectionSet, v model.ServiceEventType) graphql.Marshaler {
-	return v
+	return ret
 }
 
-func (ec *executionContext) marshalNServiceStats2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceStats(ctx context.Context, sel ast.SelectionSet, v *model.ServiceStats) graphql.Marshaler {
+func (ec *executionContext) marshalNSession2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSession(ctx context.Context, sel ast.SelectionSet, v *model.Session) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
 		}
 		return graphql.Null
 	}
-	return ec._ServiceStats(ctx, sel, v)
+	return ec._Session(ctx, sel, v)
 }
 
-func (ec *executionContext) unmarshalNServiceStatus2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceStatus(ctx context.Context, v any) (model.ServiceStatus, error) {
-	var res model.ServiceStatus
+func (ec *executionContext) unmarshalNSessionBehavior2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSessionBehavior(ctx context.Context, v any) (model.SessionBehavior, error) {
+	var res model.SessionBehavior
 	err := res.UnmarshalGQL(v)
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
-func (ec *executionContext) marshalNServiceStatus2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐServiceStatus(ctx context.Context, sel ast.SelectionSet, v model.ServiceStatus) graphql.Marshaler {
+func (ec *executionContext) marshalNSessionBehavior2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSessionBehavior(ctx context.Context, sel ast.SelectionSet, v model.SessionBehavior) graphql.Marshaler {
 	return v
 }
 
@@ -6969,6 +11258,99 @@ func (ec *executionContext) marshalNTime2githubᚗcomᚋneogan74ᚋkonsulᚋinte
 	return v
 }
 
+func (ec *executionContext) marshalNWebhook2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhook(ctx context.Context, sel ast.SelectionSet, v model.Webhook) graphql.Marshaler {
+	return ec._Webhook(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNWebhook2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.Webhook) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNWebhook2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhook(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNWebhook2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhook(ctx context.Context, sel ast.SelectionSet, v *model.Webhook) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._Webhook(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalNWebhookDelivery2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookDelivery(ctx context.Context, sel ast.SelectionSet, v *model.WebhookDelivery) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._WebhookDelivery(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalNWebhookDeliveryStatus2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookDeliveryStatus(ctx context.Context, v any) (model.WebhookDeliveryStatus, error) {
+	var res model.WebhookDeliveryStatus
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNWebhookDeliveryStatus2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookDeliveryStatus(ctx context.Context, sel ast.SelectionSet, v model.WebhookDeliveryStatus) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalNWebhookInput2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookInput(ctx context.Context, v any) (model.WebhookInput, error) {
+	res, err := ec.unmarshalInputWebhookInput(ctx, v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) unmarshalNWebhookKind2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookKind(ctx context.Context, v any) (model.WebhookKind, error) {
+	var res model.WebhookKind
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNWebhookKind2githubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookKind(ctx context.Context, sel ast.SelectionSet, v model.WebhookKind) graphql.Marshaler {
+	return v
+}
+
 func (ec *executionContext) marshalN__Directive2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐDirective(ctx context.Context, sel ast.SelectionSet, v introspection.Directive) graphql.Marshaler {
 	return ec.___Directive(ctx, sel, &v)
 }
@@ -7252,6 +11634,14 @@ func (ec *executionContext) marshalOBoolean2ᚖbool(ctx context.Context, sel ast
 	return res
 }
 
+func (ec *executionContext) unmarshalOCreateSessionInput2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐCreateSessionInput(ctx context.Context, v any) (*model.CreateSessionInput, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalInputCreateSessionInput(ctx, v)
+	return &res, graphql.ErrorOnPath(ctx, err)
+}
+
 func (ec *executionContext) unmarshalODuration2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋscalarᚐDuration(ctx context.Context, v any) (*scalar.Duration, error) {
 	if v == nil {
 		return nil, nil
@@ -7268,6 +11658,69 @@ func (ec *executionContext) marshalODuration2ᚖgithubᚗcomᚋneogan74ᚋkonsul
 	return v
 }
 
+func (ec *executionContext) marshalOHealthCheckResult2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐHealthCheckResultᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.HealthCheckResult) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNHealthCheckResult2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐHealthCheckResult(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) unmarshalOHealthCheckStatus2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐHealthCheckStatus(ctx context.Context, v any) (*model.HealthCheckStatus, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var res = new(model.HealthCheckStatus)
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOHealthCheckStatus2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐHealthCheckStatus(ctx context.Context, sel ast.SelectionSet, v *model.HealthCheckStatus) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
 func (ec *executionContext) unmarshalOInt2ᚖint(ctx context.Context, v any) (*int, error) {
 	if v == nil {
 		return nil, nil
@@ -7336,6 +11789,29 @@ func (ec *executionContext) marshalOService2ᚖgithubᚗcomᚋneogan74ᚋkonsul
 	return ec._Service(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalOSession2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSession(ctx context.Context, sel ast.SelectionSet, v *model.Session) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._Session(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalOSessionBehavior2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSessionBehavior(ctx context.Context, v any) (*model.SessionBehavior, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var res = new(model.SessionBehavior)
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOSessionBehavior2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐSessionBehavior(ctx context.Context, sel ast.SelectionSet, v *model.SessionBehavior) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
 func (ec *executionContext) unmarshalOString2ᚕstringᚄ(ctx context.Context, v any) ([]string, error) {
 	if v == nil {
 		return nil, nil
@@ -7406,6 +11882,76 @@ func (ec *executionContext) marshalOTime2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋi
 	return v
 }
 
+func (ec *executionContext) marshalOWebhook2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhook(ctx context.Context, sel ast.SelectionSet, v *model.Webhook) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._Webhook(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalOWebhookDelivery2ᚕᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookDeliveryᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.WebhookDelivery) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNWebhookDelivery2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookDelivery(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) unmarshalOWebhookKind2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookKind(ctx context.Context, v any) (*model.WebhookKind, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var res = new(model.WebhookKind)
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOWebhookKind2ᚖgithubᚗcomᚋneogan74ᚋkonsulᚋinternalᚋgraphqlᚋmodelᚐWebhookKind(ctx context.Context, sel ast.SelectionSet, v *model.WebhookKind) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
 func (ec *executionContext) marshalO__EnumValue2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐEnumValueᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.EnumValue) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
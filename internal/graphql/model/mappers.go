@@ -1,11 +1,16 @@
 package model
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/neogan74/konsul/internal/graphql/scalar"
 	"github.com/neogan74/konsul/internal/healthcheck"
 	"github.com/neogan74/konsul/internal/store"
+	"github.com/neogan74/konsul/internal/watch"
+	"github.com/neogan74/konsul/internal/webhook"
 )
 
 // MapKVPairFromStore converts store data to GraphQL KVPair model
@@ -19,6 +24,19 @@ func MapKVPairFromStore(key, value string) *KVPair {
 	}
 }
 
+// MapKVEntryFromStore converts a stored KV entry, including its lock and
+// expiry, to the GraphQL KVPair model. Secret values are redacted.
+func MapKVEntryFromStore(key string, entry store.KVEntry) *KVPair {
+	pair := MapKVPairFromStore(key, entry.Redacted().Value)
+	pair.Secret = entry.Secret
+	pair.Session = optionalString(entry.Session)
+	if !entry.ExpiresAt.IsZero() {
+		expiresAt := scalar.FromTime(entry.ExpiresAt)
+		pair.ExpiresAt = &expiresAt
+	}
+	return pair
+}
+
 // MapServiceFromStore converts store.Service to GraphQL Service model
 func MapServiceFromStore(svc store.Service, entry store.ServiceEntry) *Service {
 	status := ServiceStatusActive
@@ -43,8 +61,16 @@ func MapServiceFromStore(svc store.Service, entry store.ServiceEntry) *Service {
 		})
 	}
 
+	var node *string
+	if svc.Node != "" {
+		node = &svc.Node
+	}
+
 	return &Service{
+		ID:        svc.InstanceID(),
 		Name:      svc.Name,
+		Namespace: store.NormalizeNamespace(svc.Namespace),
+		Node:      node,
 		Address:   svc.Address,
 		Port:      svc.Port,
 		Status:    status,
@@ -76,7 +102,7 @@ func MapHealthCheckFromStore(check *healthcheck.Check) *HealthCheck {
 		timeout = &t
 	}
 
-	return &HealthCheck{
+	mapped := &HealthCheck{
 		ID:          check.ID,
 		ServiceID:   check.ServiceID,
 		Name:        check.Name,
@@ -87,6 +113,21 @@ func MapHealthCheckFromStore(check *healthcheck.Check) *HealthCheck {
 		Timeout:     timeout,
 		LastChecked: lastChecked,
 	}
+	if !check.CriticalSince.IsZero() {
+		t := scalar.FromTime(check.CriticalSince)
+		mapped.CriticalSince = &t
+	}
+	return mapped
+}
+
+// MapHealthCheckResultFromStore converts a check history entry to the GraphQL
+// HealthCheckResult model
+func MapHealthCheckResultFromStore(entry healthcheck.HistoryEntry) *HealthCheckResult {
+	return &HealthCheckResult{
+		Status: mapCheckStatus(entry.Status),
+		Output: entry.Output,
+		Time:   scalar.FromTime(entry.Time),
+	}
 }
 
 // mapCheckType converts healthcheck.CheckType to GraphQL HealthCheckType
@@ -98,6 +139,10 @@ func mapCheckType(checkType healthcheck.CheckType) HealthCheckType {
 		return HealthCheckTypeTCP
 	case healthcheck.CheckTypeGRPC:
 		return HealthCheckTypeGrpc
+	case healthcheck.CheckTypeUDP:
+		return HealthCheckTypeUDP
+	case healthcheck.CheckTypeScript:
+		return HealthCheckTypeScript
 	case healthcheck.CheckTypeTTL:
 		return HealthCheckTypeTTL
 	default:
@@ -118,3 +163,202 @@ func mapCheckStatus(status healthcheck.Status) HealthCheckStatus {
 		return HealthCheckStatusCritical // default to critical for safety
 	}
 }
+
+// MapServiceChangeEvent converts a service watch event to a GraphQL
+// ServiceChangeEvent. entry is the current entry of the instance, if any;
+// events about checks of a whole service carry only its name.
+func MapServiceChangeEvent(event watch.Event, entry store.ServiceEntry) (*ServiceChangeEvent, error) {
+	svc := store.Service{Name: event.Key, Namespace: event.Namespace}
+	if len(event.Service) > 0 {
+		if err := json.Unmarshal(event.Service, &svc); err != nil {
+			return nil, fmt.Errorf("failed to decode service: %w", err)
+		}
+	}
+
+	result := &ServiceChangeEvent{
+		Service:   MapServiceFromStore(svc, entry),
+		Timestamp: scalar.FromTime(time.Unix(event.Timestamp, 0)),
+	}
+	switch event.Type {
+	case watch.EventTypeRegister:
+		result.Type = ServiceEventTypeRegistered
+	case watch.EventTypeDeregister:
+		result.Type = ServiceEventTypeDeregistered
+	case watch.EventTypeHealth:
+		status := mapCheckStatus(healthcheck.Status(event.Value))
+		previous := mapCheckStatus(healthcheck.Status(event.OldValue))
+		result.Type = ServiceEventTypeHealth
+		result.Check = &event.Check
+		result.Status = &status
+		result.PreviousStatus = &previous
+	default:
+		return nil, fmt.Errorf("unknown service event type %q", event.Type)
+	}
+	return result, nil
+}
+
+// MapSessionFromStore converts store.Session to GraphQL Session model
+func MapSessionFromStore(session store.Session) *Session {
+	checks := session.Checks
+	if checks == nil {
+		checks = []string{}
+	}
+
+	var expiresAt *scalar.Time
+	if !session.ExpiresAt.IsZero() {
+		t := scalar.FromTime(session.ExpiresAt)
+		expiresAt = &t
+	}
+
+	return &Session{
+		ID:          session.ID,
+		Name:        optionalString(session.Name),
+		Node:        optionalString(session.Node),
+		TTL:         optionalString(session.TTL),
+		Behavior:    mapSessionBehavior(session.Behavior),
+		Checks:      checks,
+		CreateIndex: int(session.CreateIndex),
+		ModifyIndex: int(session.ModifyIndex),
+		ExpiresAt:   expiresAt,
+	}
+}
+
+// MapNamespaceFromStore converts store.Namespace to GraphQL Namespace model
+func MapNamespaceFromStore(ns store.Namespace) *Namespace {
+	metadata := make([]*MetadataEntry, 0, len(ns.Meta))
+	for key, value := range ns.Meta {
+		metadata = append(metadata, &MetadataEntry{
+			Key:   key,
+			Value: value,
+		})
+	}
+
+	var createdAt *scalar.Time
+	if !ns.CreatedAt.IsZero() {
+		t := scalar.FromTime(ns.CreatedAt)
+		createdAt = &t
+	}
+
+	return &Namespace{
+		Name:        ns.Name,
+		Description: optionalString(ns.Description),
+		Metadata:    metadata,
+		CreateIndex: int(ns.CreateIndex),
+		CreatedAt:   createdAt,
+	}
+}
+
+// MapSessionBehaviorToStore converts GraphQL SessionBehavior to store.SessionBehavior
+func MapSessionBehaviorToStore(behavior SessionBehavior) store.SessionBehavior {
+	if behavior == SessionBehaviorDelete {
+		return store.SessionBehaviorDelete
+	}
+	return store.SessionBehaviorRelease
+}
+
+// mapSessionBehavior converts store.SessionBehavior to GraphQL SessionBehavior
+func mapSessionBehavior(behavior store.SessionBehavior) SessionBehavior {
+	if behavior == store.SessionBehaviorDelete {
+		return SessionBehaviorDelete
+	}
+	return SessionBehaviorRelease
+}
+
+// MapWebhookFromStore converts a webhook.Webhook to the GraphQL Webhook model
+func MapWebhookFromStore(hook webhook.Webhook) *Webhook {
+	events := make([]string, 0, len(hook.Events))
+	for _, t := range hook.Events {
+		events = append(events, string(t))
+	}
+	kind := WebhookKindKv
+	if hook.Kind == watch.KindService {
+		kind = WebhookKindService
+	}
+	policies := hook.Policies
+	if policies == nil {
+		policies = []string{}
+	}
+
+	return &Webhook{
+		Name:      hook.Name,
+		URL:       hook.URL,
+		Signed:    hook.Signed,
+		Kind:      kind,
+		Namespace: optionalString(hook.Namespace),
+		Pattern:   hook.Pattern,
+		Events:    events,
+		Policies:  policies,
+		CreatedAt: optionalTime(hook.CreatedAt),
+		UpdatedAt: optionalTime(hook.UpdatedAt),
+	}
+}
+
+// MapWebhookInputToStore converts a GraphQL WebhookInput to a webhook.Webhook
+func MapWebhookInputToStore(input WebhookInput) webhook.Webhook {
+	hook := webhook.Webhook{
+		Name:      input.Name,
+		URL:       input.URL,
+		Secret:    stringValue(input.Secret),
+		Namespace: stringValue(input.Namespace),
+		Pattern:   stringValue(input.Pattern),
+	}
+	if input.ClearSecret != nil {
+		hook.ClearSecret = *input.ClearSecret
+	}
+	if input.Kind != nil {
+		hook.Kind = watch.Kind(strings.ToLower(string(*input.Kind)))
+	}
+	for _, t := range input.Events {
+		hook.Events = append(hook.Events, watch.EventType(t))
+	}
+	return hook
+}
+
+// MapWebhookDeliveryFromStore converts a webhook.Delivery to the GraphQL
+// WebhookDelivery model
+func MapWebhookDeliveryFromStore(delivery webhook.Delivery) *WebhookDelivery {
+	var statusCode *int
+	if delivery.StatusCode != 0 {
+		statusCode = &delivery.StatusCode
+	}
+
+	return &WebhookDelivery{
+		ID:          delivery.ID,
+		Webhook:     delivery.Webhook,
+		EventType:   string(delivery.Event.Type),
+		Key:         delivery.Event.Key,
+		Index:       int(delivery.Event.Index),
+		Status:      WebhookDeliveryStatus(strings.ToUpper(string(delivery.Status))),
+		Attempts:    delivery.Attempts,
+		StatusCode:  statusCode,
+		Error:       optionalString(delivery.Error),
+		CreatedAt:   scalar.FromTime(delivery.CreatedAt),
+		NextAttempt: optionalTime(delivery.NextAttempt),
+		CompletedAt: optionalTime(delivery.CompletedAt),
+	}
+}
+
+// optionalTime returns nil for a zero time
+func optionalTime(t time.Time) *scalar.Time {
+	if t.IsZero() {
+		return nil
+	}
+	st := scalar.FromTime(t)
+	return &st
+}
+
+// stringValue returns the string s points to, or "" for nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// optionalString returns nil for an empty string
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -11,6 +11,20 @@ import (
 	"github.com/neogan74/konsul/internal/graphql/scalar"
 )
 
+// Input for creating a session
+type CreateSessionInput struct {
+	// Optional human-readable name
+	Name *string `json:"name,omitempty"`
+	// Node the session belongs to
+	Node *string `json:"node,omitempty"`
+	// Session TTL between 10s and 24h
+	TTL *string `json:"ttl,omitempty"`
+	// Behavior on invalidation (default RELEASE)
+	Behavior *SessionBehavior `json:"behavior,omitempty"`
+	// IDs of health checks that invalidate the session when critical
+	Checks []string `json:"checks,omitempty"`
+}
+
 // Health check definition
 type HealthCheck struct {
 	// Check ID
@@ -19,7 +33,7 @@ type HealthCheck struct {
 	ServiceID string `json:"serviceId"`
 	// Check name
 	Name string `json:"name"`
-	// Check type (http, tcp, grpc, ttl)
+	// Check type (http, tcp, grpc, udp, script, ttl)
 	Type HealthCheckType `json:"type"`
 	// Current status
 	Status HealthCheckStatus `json:"status"`
@@ -31,6 +45,18 @@ type HealthCheck struct {
 	Timeout *scalar.Duration `json:"timeout,omitempty"`
 	// Last check time
 	LastChecked *scalar.Time `json:"lastChecked,omitempty"`
+	// Time the check turned critical, while it is critical
+	CriticalSince *scalar.Time `json:"criticalSince,omitempty"`
+}
+
+// Result of one run of a health check
+type HealthCheckResult struct {
+	// Status the run reported
+	Status HealthCheckStatus `json:"status"`
+	// Output of the run
+	Output string `json:"output"`
+	// Time of the run
+	Time scalar.Time `json:"time"`
 }
 
 // KV change event for subscriptions
@@ -43,6 +69,8 @@ type KVChangeEvent struct {
 	Value *string `json:"value,omitempty"`
 	// The previous value (if available)
 	OldValue *string `json:"oldValue,omitempty"`
+	// Whether the new value is a secret, whose value is then omitted
+	Secret bool `json:"secret"`
 	// Timestamp of the change
 	Timestamp scalar.Time `json:"timestamp"`
 }
@@ -61,12 +89,19 @@ type KVListResponse struct {
 type KVPair struct {
 	// The key
 	Key string `json:"key"`
-	// The value
+	// The value, empty for secrets
 	Value string `json:"value"`
+	// Whether the value is a secret. Secrets are never returned over GraphQL;
+	//   read them from /kv with the decrypt capability
+	Secret bool `json:"secret"`
 	// Creation timestamp
 	CreatedAt *scalar.Time `json:"createdAt,omitempty"`
 	// Last modification timestamp
 	UpdatedAt *scalar.Time `json:"updatedAt,omitempty"`
+	// ID of the session holding the lock on this key
+	Session *string `json:"session,omitempty"`
+	// When the key is deleted, if it was set with a TTL
+	ExpiresAt *scalar.Time `json:"expiresAt,omitempty"`
 }
 
 // KV store statistics
@@ -103,13 +138,33 @@ type MetadataInput struct {
 type Mutation struct {
 }
 
+// Namespace isolates the KV keys and services of one tenant
+type Namespace struct {
+	// Namespace name
+	Name string `json:"name"`
+	// Human readable description
+	Description *string `json:"description,omitempty"`
+	// Namespace metadata
+	Metadata []*MetadataEntry `json:"metadata"`
+	// Index at which the namespace was created
+	CreateIndex int `json:"createIndex"`
+	// Creation timestamp
+	CreatedAt *scalar.Time `json:"createdAt,omitempty"`
+}
+
 type Query struct {
 }
 
 // Input type for registering a service
 type RegisterServiceInput struct {
-	// Service name (unique identifier)
+	// Instance ID (optional, defaults to the service name)
+	ID *string `json:"id,omitempty"`
+	// Service name
 	Name string `json:"name"`
+	// Namespace to register in (optional, defaults to the default namespace)
+	Namespace *string `json:"namespace,omitempty"`
+	// Node the instance runs on (optional)
+	Node *string `json:"node,omitempty"`
 	// Service IP address or hostname
 	Address string `json:"address"`
 	// Service port number
@@ -120,10 +175,16 @@ type RegisterServiceInput struct {
 	Metadata []*MetadataInput `json:"metadata,omitempty"`
 }
 
-// Service represents a registered service in the service registry
+// Service represents a registered service instance in the service registry
 type Service struct {
-	// Service name (unique identifier)
+	// Instance ID (unique identifier, defaults to the service name)
+	ID string `json:"id"`
+	// Service name (shared by all instances of the service)
 	Name string `json:"name"`
+	// Namespace the instance is registered in
+	Namespace string `json:"namespace"`
+	// Node the instance runs on
+	Node *string `json:"node,omitempty"`
 	// Service IP address or hostname
 	Address string `json:"address"`
 	// Service port number
@@ -146,6 +207,12 @@ type ServiceChangeEvent struct {
 	Type ServiceEventType `json:"type"`
 	// The service that changed
 	Service *Service `json:"service"`
+	// Check that changed status, for HEALTH events
+	Check *string `json:"check,omitempty"`
+	// New status of the check, for HEALTH events
+	Status *HealthCheckStatus `json:"status,omitempty"`
+	// Previous status of the check, for HEALTH events
+	PreviousStatus *HealthCheckStatus `json:"previousStatus,omitempty"`
 	// Timestamp of the change
 	Timestamp scalar.Time `json:"timestamp"`
 }
@@ -160,6 +227,28 @@ type ServiceStats struct {
 	Expired int `json:"expired"`
 }
 
+// Session is a lease that can hold locks on KV keys
+type Session struct {
+	// Unique session ID
+	ID string `json:"id"`
+	// Optional human-readable name
+	Name *string `json:"name,omitempty"`
+	// Node the session belongs to
+	Node *string `json:"node,omitempty"`
+	// Session TTL (e.g. 15s); empty if the session never expires
+	TTL *string `json:"ttl,omitempty"`
+	// What happens to held locks when the session is invalidated
+	Behavior SessionBehavior `json:"behavior"`
+	// IDs of linked health checks
+	Checks []string `json:"checks"`
+	// Index at which the session was created
+	CreateIndex int `json:"createIndex"`
+	// Index of the last renewal
+	ModifyIndex int `json:"modifyIndex"`
+	// When the session expires without a renewal
+	ExpiresAt *scalar.Time `json:"expiresAt,omitempty"`
+}
+
 // Subscription type for real-time updates
 type Subscription struct {
 }
@@ -180,6 +269,80 @@ type SystemHealth struct {
 	KvStore *KVStats `json:"kvStore"`
 }
 
+// Webhook POSTs the watch events of the KV keys or services matching a pattern
+// to a URL
+type Webhook struct {
+	// Webhook name
+	Name string `json:"name"`
+	// URL the events are POSTed to
+	URL string `json:"url"`
+	// Whether requests carry an HMAC-SHA256 signature header
+	Signed bool `json:"signed"`
+	// Kind of resource watched
+	Kind WebhookKind `json:"kind"`
+	// Namespace of the services watched
+	Namespace *string `json:"namespace,omitempty"`
+	// Key or service name pattern, as for watches
+	Pattern string `json:"pattern"`
+	// Event types delivered; all when empty
+	Events []string `json:"events"`
+	// Policies events are filtered with
+	Policies []string `json:"policies"`
+	// Creation timestamp
+	CreatedAt *scalar.Time `json:"createdAt,omitempty"`
+	// Last update timestamp
+	UpdatedAt *scalar.Time `json:"updatedAt,omitempty"`
+}
+
+// Delivery of an event to a webhook
+type WebhookDelivery struct {
+	// Delivery ID, sent in the X-Konsul-Delivery header
+	ID string `json:"id"`
+	// Webhook name
+	Webhook string `json:"webhook"`
+	// Type of the event delivered
+	EventType string `json:"eventType"`
+	// Key or service name of the event
+	Key string `json:"key"`
+	// Store index of the event
+	Index int `json:"index"`
+	// Delivery status
+	Status WebhookDeliveryStatus `json:"status"`
+	// Attempts made so far
+	Attempts int `json:"attempts"`
+	// Status of the last response
+	StatusCode *int `json:"statusCode,omitempty"`
+	// Why the last attempt failed
+	Error *string `json:"error,omitempty"`
+	// When the event was queued
+	CreatedAt scalar.Time `json:"createdAt"`
+	// When the next attempt is due, for pending deliveries
+	NextAttempt *scalar.Time `json:"nextAttempt,omitempty"`
+	// When the delivery completed
+	CompletedAt *scalar.Time `json:"completedAt,omitempty"`
+}
+
+// Input for creating or replacing a webhook
+type WebhookInput struct {
+	// Webhook name
+	Name string `json:"name"`
+	// URL the events are POSTed to
+	URL string `json:"url"`
+	// Key of the signature header; requests are not signed without it. A
+	//   replaced webhook keeps its secret when omitted.
+	Secret *string `json:"secret,omitempty"`
+	// Remove the secret of the webhook being replaced
+	ClearSecret *bool `json:"clearSecret,omitempty"`
+	// Kind of resource watched (default KV)
+	Kind *WebhookKind `json:"kind,omitempty"`
+	// Namespace of the services watched
+	Namespace *string `json:"namespace,omitempty"`
+	// Key or service name pattern (default ** for KV and * for services)
+	Pattern *string `json:"pattern,omitempty"`
+	// Event types delivered; all when empty
+	Events []string `json:"events,omitempty"`
+}
+
 // Health check status
 type HealthCheckStatus string
 
@@ -242,22 +405,26 @@ func (e HealthCheckStatus) MarshalJSON() ([]byte, error) {
 type HealthCheckType string
 
 const (
-	HealthCheckTypeHTTP HealthCheckType = "HTTP"
-	HealthCheckTypeTCP  HealthCheckType = "TCP"
-	HealthCheckTypeGrpc HealthCheckType = "GRPC"
-	HealthCheckTypeTTL  HealthCheckType = "TTL"
+	HealthCheckTypeHTTP   HealthCheckType = "HTTP"
+	HealthCheckTypeTCP    HealthCheckType = "TCP"
+	HealthCheckTypeGrpc   HealthCheckType = "GRPC"
+	HealthCheckTypeUDP    HealthCheckType = "UDP"
+	HealthCheckTypeScript HealthCheckType = "SCRIPT"
+	HealthCheckTypeTTL    HealthCheckType = "TTL"
 )
 
 var AllHealthCheckType = []HealthCheckType{
 	HealthCheckTypeHTTP,
 	HealthCheckTypeTCP,
 	HealthCheckTypeGrpc,
+	HealthCheckTypeUDP,
+	HealthCheckTypeScript,
 	HealthCheckTypeTTL,
 }
 
 func (e HealthCheckType) IsValid() bool {
 	switch e {
-	case HealthCheckTypeHTTP, HealthCheckTypeTCP, HealthCheckTypeGrpc, HealthCheckTypeTTL:
+	case HealthCheckTypeHTTP, HealthCheckTypeTCP, HealthCheckTypeGrpc, HealthCheckTypeUDP, HealthCheckTypeScript, HealthCheckTypeTTL:
 		return true
 	}
 	return false
@@ -366,17 +533,20 @@ const (
 	ServiceEventTypeDeregistered ServiceEventType = "DEREGISTERED"
 	// Service heartbeat updated
 	ServiceEventTypeHeartbeat ServiceEventType = "HEARTBEAT"
+	// A health check of the service changed status
+	ServiceEventTypeHealth ServiceEventType = "HEALTH"
 )
 
 var AllServiceEventType = []ServiceEventType{
 	ServiceEventTypeRegistered,
 	ServiceEventTypeDeregistered,
 	ServiceEventTypeHeartbeat,
+	ServiceEventTypeHealth,
 }
 
 func (e ServiceEventType) IsValid() bool {
 	switch e {
-	case ServiceEventTypeRegistered, ServiceEventTypeDeregistered, ServiceEventTypeHeartbeat:
+	case ServiceEventTypeRegistered, ServiceEventTypeDeregistered, ServiceEventTypeHeartbeat, ServiceEventTypeHealth:
 		return true
 	}
 	return false
@@ -474,3 +644,175 @@ func (e ServiceStatus) MarshalJSON() ([]byte, error) {
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
+
+// Session behaviors on invalidation
+type SessionBehavior string
+
+const (
+	// Release held locks and keep the keys
+	SessionBehaviorRelease SessionBehavior = "RELEASE"
+	// Delete keys whose lock the session holds
+	SessionBehaviorDelete SessionBehavior = "DELETE"
+)
+
+var AllSessionBehavior = []SessionBehavior{
+	SessionBehaviorRelease,
+	SessionBehaviorDelete,
+}
+
+func (e SessionBehavior) IsValid() bool {
+	switch e {
+	case SessionBehaviorRelease, SessionBehaviorDelete:
+		return true
+	}
+	return false
+}
+
+func (e SessionBehavior) String() string {
+	return string(e)
+}
+
+func (e *SessionBehavior) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SessionBehavior(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SessionBehavior", str)
+	}
+	return nil
+}
+
+func (e SessionBehavior) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SessionBehavior) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SessionBehavior) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Status of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "DELIVERED"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+var AllWebhookDeliveryStatus = []WebhookDeliveryStatus{
+	WebhookDeliveryStatusPending,
+	WebhookDeliveryStatusDelivered,
+	WebhookDeliveryStatusFailed,
+}
+
+func (e WebhookDeliveryStatus) IsValid() bool {
+	switch e {
+	case WebhookDeliveryStatusPending, WebhookDeliveryStatusDelivered, WebhookDeliveryStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e WebhookDeliveryStatus) String() string {
+	return string(e)
+}
+
+func (e *WebhookDeliveryStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookDeliveryStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookDeliveryStatus", str)
+	}
+	return nil
+}
+
+func (e WebhookDeliveryStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WebhookDeliveryStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WebhookDeliveryStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Kind of resource a webhook watches
+type WebhookKind string
+
+const (
+	WebhookKindKv      WebhookKind = "KV"
+	WebhookKindService WebhookKind = "SERVICE"
+)
+
+var AllWebhookKind = []WebhookKind{
+	WebhookKindKv,
+	WebhookKindService,
+}
+
+func (e WebhookKind) IsValid() bool {
+	switch e {
+	case WebhookKindKv, WebhookKindService:
+		return true
+	}
+	return false
+}
+
+func (e WebhookKind) String() string {
+	return string(e)
+}
+
+func (e *WebhookKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookKind", str)
+	}
+	return nil
+}
+
+func (e WebhookKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WebhookKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WebhookKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
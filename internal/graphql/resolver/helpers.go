@@ -1,5 +1,18 @@
 package resolver
 
+import (
+	"errors"
+
+	"github.com/neogan74/konsul/internal/healthcheck"
+	"github.com/neogan74/konsul/internal/store"
+)
+
+// errSessionsDisabled is returned by session resolvers when no session store is configured
+var errSessionsDisabled = errors.New("sessions are not enabled")
+
+// errWebhooksDisabled is returned by webhook mutations when no webhook manager is configured
+var errWebhooksDisabled = errors.New("webhooks are not enabled")
+
 // stringOrEmpty returns empty string if pointer is nil, otherwise returns the value
 func stringOrEmpty(s *string) string {
 	if s == nil {
@@ -7,3 +20,60 @@ func stringOrEmpty(s *string) string {
 	}
 	return *s
 }
+
+// instanceIDOrName returns the instance ID if provided, otherwise the service name
+func instanceIDOrName(name string, id *string) string {
+	if id == nil || *id == "" {
+		return name
+	}
+	return *id
+}
+
+// resolveNamespace returns the namespace named by a namespace argument, or ""
+// for the default namespace. Other namespaces must be registered.
+func (r *Resolver) resolveNamespace(namespace *string) (string, error) {
+	ns := store.NormalizeNamespace(stringOrEmpty(namespace))
+	if ns == store.DefaultNamespace {
+		return "", nil
+	}
+	if err := store.ValidateNamespaceName(ns); err != nil {
+		return "", err
+	}
+	if r.namespaceStore == nil || !r.namespaceStore.Exists(ns) {
+		return "", &store.NotFoundError{Type: "namespace", Key: ns}
+	}
+	return ns, nil
+}
+
+// resolveKey returns the namespace and storage key of a KV key.
+func (r *Resolver) resolveKey(key string, namespace *string) (string, string, error) {
+	if err := store.ValidateKeyName(key); err != nil {
+		return "", "", err
+	}
+	ns, err := r.resolveNamespace(namespace)
+	if err != nil {
+		return "", "", err
+	}
+	return ns, store.NamespacedKey(ns, key), nil
+}
+
+// entriesInNamespace returns the service entries that belong to namespace ns.
+func entriesInNamespace(entries []store.ServiceEntry, ns string) []store.ServiceEntry {
+	ns = store.NormalizeNamespace(ns)
+	result := make([]store.ServiceEntry, 0, len(entries))
+	for _, entry := range entries {
+		if store.NormalizeNamespace(entry.Service.Namespace) == ns {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// healthStatuses returns the check statuses of the service instances to
+// return: passing only if passing is true, otherwise passing and warning.
+func healthStatuses(passing *bool) []healthcheck.Status {
+	if passing != nil && *passing {
+		return []healthcheck.Status{healthcheck.StatusPassing}
+	}
+	return store.DiscoveryStatuses
+}
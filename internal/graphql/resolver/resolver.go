@@ -9,6 +9,7 @@ import (
 	konsulraft "github.com/neogan74/konsul/internal/raft"
 	"github.com/neogan74/konsul/internal/store"
 	"github.com/neogan74/konsul/internal/watch"
+	"github.com/neogan74/konsul/internal/webhook"
 )
 
 // This file will not be regenerated automatically.
@@ -17,40 +18,49 @@ import (
 
 // Resolver is the root resolver
 type Resolver struct {
-	kvStore      *store.KVStore
-	serviceStore *store.ServiceStore
-	raftNode     *konsulraft.Node
-	watchManager *watch.Manager
-	aclEvaluator *acl.Evaluator
-	jwtService   *auth.JWTService
-	logger       logger.Logger
-	version      string
-	startTime    time.Time
+	kvStore        *store.KVStore
+	serviceStore   *store.ServiceStore
+	sessionStore   *store.SessionStore
+	namespaceStore *store.NamespaceStore
+	raftNode       *konsulraft.Node
+	watchManager   *watch.Manager
+	webhookManager *webhook.Manager
+	aclEvaluator   *acl.Evaluator
+	jwtService     *auth.JWTService
+	logger         logger.Logger
+	version        string
+	startTime      time.Time
 }
 
 // NewResolver creates a new resolver
 func NewResolver(deps ResolverDependencies) *Resolver {
 	return &Resolver{
-		kvStore:      deps.KVStore,
-		serviceStore: deps.ServiceStore,
-		raftNode:     deps.RaftNode,
-		watchManager: deps.WatchManager,
-		aclEvaluator: deps.ACLEvaluator,
-		jwtService:   deps.JWTService,
-		logger:       deps.Logger,
-		version:      deps.Version,
-		startTime:    time.Now(),
+		kvStore:        deps.KVStore,
+		serviceStore:   deps.ServiceStore,
+		sessionStore:   deps.SessionStore,
+		namespaceStore: deps.NamespaceStore,
+		raftNode:       deps.RaftNode,
+		watchManager:   deps.WatchManager,
+		webhookManager: deps.WebhookManager,
+		aclEvaluator:   deps.ACLEvaluator,
+		jwtService:     deps.JWTService,
+		logger:         deps.Logger,
+		version:        deps.Version,
+		startTime:      time.Now(),
 	}
 }
 
 // ResolverDependencies holds all dependencies for resolvers
 type ResolverDependencies struct {
-	KVStore      *store.KVStore
-	ServiceStore *store.ServiceStore
-	RaftNode     *konsulraft.Node
-	WatchManager *watch.Manager
-	ACLEvaluator *acl.Evaluator
-	JWTService   *auth.JWTService
-	Logger       logger.Logger
-	Version      string
+	KVStore        *store.KVStore
+	ServiceStore   *store.ServiceStore
+	SessionStore   *store.SessionStore
+	NamespaceStore *store.NamespaceStore
+	RaftNode       *konsulraft.Node
+	WatchManager   *watch.Manager
+	WebhookManager *webhook.Manager // nil when webhooks are disabled
+	ACLEvaluator   *acl.Evaluator
+	JWTService     *auth.JWTService
+	Logger         logger.Logger
+	Version        string
 }
@@ -21,19 +21,33 @@ import (
 	"github.com/neogan74/konsul/internal/metrics"
 	konsulraft "github.com/neogan74/konsul/internal/raft"
 	"github.com/neogan74/konsul/internal/store"
+	"github.com/neogan74/konsul/internal/watch"
+	"github.com/neogan74/konsul/internal/webhook"
 )
 
 // KvSet is the resolver for the kvSet field.
-func (r *mutationResolver) KvSet(ctx context.Context, key string, value string) (*model.KVPair, error) {
-	if err := r.authorizeMutation(ctx, acl.NewKVResource(key), acl.CapabilityWrite); err != nil {
+func (r *mutationResolver) KvSet(ctx context.Context, key string, value string, ttl *string, namespace *string) (*model.KVPair, error) {
+	if err := r.authorizeMutation(ctx, acl.NewKVResource(key).InNamespace(stringOrEmpty(namespace)), acl.CapabilityWrite); err != nil {
+		return nil, err
+	}
+
+	ns, storageKey, err := r.resolveKey(key, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	keyTTL, err := store.ParseKVTTL(stringOrEmpty(ttl))
+	if err != nil {
 		return nil, err
 	}
 
 	// Set the key-value pair
 	if r.raftNode != nil {
 		cmd, err := konsulraft.NewCommand(konsulraft.CmdKVSet, konsulraft.KVSetPayload{
-			Key:   key,
-			Value: value,
+			Namespace: ns,
+			Key:       key,
+			Value:     value,
+			ExpiresAt: store.KVExpiry(keyTTL),
 		})
 		if err != nil {
 			return nil, err
@@ -44,26 +58,37 @@ func (r *mutationResolver) KvSet(ctx context.Context, key string, value string)
 			}
 			return nil, err
 		}
+	} else if keyTTL > 0 {
+		r.kvStore.SetWithTTL(storageKey, value, 0, keyTTL)
 	} else {
-		r.kvStore.Set(key, value)
+		r.kvStore.Set(storageKey, value)
 	}
 
 	r.logger.Info("GraphQL: KV set",
 		logger.String("key", key),
+		logger.String("namespace", store.NormalizeNamespace(ns)),
 		logger.Int("value_length", len(value)))
 
 	// Return the updated KV pair
+	if entry, ok := r.kvStore.GetEntry(storageKey); ok {
+		return model.MapKVEntryFromStore(key, entry), nil
+	}
 	return model.MapKVPairFromStore(key, value), nil
 }
 
 // KvDelete is the resolver for the kvDelete field.
-func (r *mutationResolver) KvDelete(ctx context.Context, key string) (bool, error) {
-	if err := r.authorizeMutation(ctx, acl.NewKVResource(key), acl.CapabilityDelete); err != nil {
+func (r *mutationResolver) KvDelete(ctx context.Context, key string, namespace *string) (bool, error) {
+	if err := r.authorizeMutation(ctx, acl.NewKVResource(key).InNamespace(stringOrEmpty(namespace)), acl.CapabilityDelete); err != nil {
+		return false, err
+	}
+
+	ns, storageKey, err := r.resolveKey(key, namespace)
+	if err != nil {
 		return false, err
 	}
 
 	// Check if key exists before deleting
-	_, exists := r.kvStore.Get(key)
+	_, exists := r.kvStore.Get(storageKey)
 	if !exists {
 		return false, nil
 	}
@@ -71,7 +96,8 @@ func (r *mutationResolver) KvDelete(ctx context.Context, key string) (bool, erro
 	// Delete the key
 	if r.raftNode != nil {
 		cmd, err := konsulraft.NewCommand(konsulraft.CmdKVDelete, konsulraft.KVDeletePayload{
-			Key: key,
+			Namespace: ns,
+			Key:       key,
 		})
 		if err != nil {
 			return false, err
@@ -83,7 +109,7 @@ func (r *mutationResolver) KvDelete(ctx context.Context, key string) (bool, erro
 			return false, err
 		}
 	} else {
-		r.kvStore.Delete(key)
+		r.kvStore.Delete(storageKey)
 	}
 
 	r.logger.Info("GraphQL: KV deleted",
@@ -93,19 +119,30 @@ func (r *mutationResolver) KvDelete(ctx context.Context, key string) (bool, erro
 }
 
 // KvCas is the resolver for the kvCAS field.
-func (r *mutationResolver) KvCas(ctx context.Context, key string, value string, index int) (*model.KVPair, error) {
-	if err := r.authorizeMutation(ctx, acl.NewKVResource(key), acl.CapabilityWrite); err != nil {
+func (r *mutationResolver) KvCas(ctx context.Context, key string, value string, index int, ttl *string, namespace *string) (*model.KVPair, error) {
+	if err := r.authorizeMutation(ctx, acl.NewKVResource(key).InNamespace(stringOrEmpty(namespace)), acl.CapabilityWrite); err != nil {
+		return nil, err
+	}
+
+	ns, storageKey, err := r.resolveKey(key, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	keyTTL, err := store.ParseKVTTL(stringOrEmpty(ttl))
+	if err != nil {
 		return nil, err
 	}
 
 	// Perform Compare-And-Swap operation
 	var newIndex uint64
-	var err error
 	if r.raftNode != nil {
 		cmd, marshalErr := konsulraft.NewCommand(konsulraft.CmdKVSetCAS, konsulraft.KVSetCASPayload{
+			Namespace:     ns,
 			Key:           key,
 			Value:         value,
 			ExpectedIndex: uint64(index),
+			ExpiresAt:     store.KVExpiry(keyTTL),
 		})
 		if marshalErr != nil {
 			return nil, marshalErr
@@ -116,8 +153,10 @@ func (r *mutationResolver) KvCas(ctx context.Context, key string, value string,
 		} else if cast, ok := resp.(uint64); ok {
 			newIndex = cast
 		}
+	} else if keyTTL > 0 {
+		newIndex, err = r.kvStore.SetCASWithTTL(storageKey, value, uint64(index), keyTTL)
 	} else {
-		newIndex, err = r.kvStore.SetCAS(key, value, uint64(index))
+		newIndex, err = r.kvStore.SetCAS(storageKey, value, uint64(index))
 	}
 	if err != nil {
 		// CAS failed - index mismatch
@@ -139,12 +178,20 @@ func (r *mutationResolver) KvCas(ctx context.Context, key string, value string,
 		logger.Int("new_index", int(newIndex)))
 
 	// Return the updated KV pair
+	if entry, ok := r.kvStore.GetEntry(storageKey); ok {
+		return model.MapKVEntryFromStore(key, entry), nil
+	}
 	return model.MapKVPairFromStore(key, value), nil
 }
 
 // RegisterService is the resolver for the registerService field.
 func (r *mutationResolver) RegisterService(ctx context.Context, input model.RegisterServiceInput) (*model.Service, error) {
-	if err := r.authorizeMutation(ctx, acl.NewServiceResource(input.Name), acl.CapabilityRegister); err != nil {
+	if err := r.authorizeMutation(ctx, acl.NewServiceResource(input.Name).InNamespace(stringOrEmpty(input.Namespace)), acl.CapabilityRegister); err != nil {
+		return nil, err
+	}
+
+	ns, err := r.resolveNamespace(input.Namespace)
+	if err != nil {
 		return nil, err
 	}
 
@@ -158,12 +205,16 @@ func (r *mutationResolver) RegisterService(ctx context.Context, input model.Regi
 
 	// Create service from input
 	service := store.Service{
-		Name:    input.Name,
-		Address: input.Address,
-		Port:    input.Port,
-		Tags:    input.Tags,
-		Meta:    metadata,
+		ID:        stringOrEmpty(input.ID),
+		Name:      input.Name,
+		Namespace: ns,
+		Node:      stringOrEmpty(input.Node),
+		Address:   input.Address,
+		Port:      input.Port,
+		Tags:      input.Tags,
+		Meta:      metadata,
 	}
+	service.ID = service.InstanceID()
 
 	// Register the service
 	if r.raftNode != nil {
@@ -188,42 +239,43 @@ func (r *mutationResolver) RegisterService(ctx context.Context, input model.Regi
 
 	r.logger.Info("GraphQL: Service registered",
 		logger.String("name", input.Name),
+		logger.String("id", service.ID),
 		logger.String("address", input.Address),
 		logger.Int("port", input.Port))
 
 	// Get the registered service entry to return with expiration info
-	entries := r.serviceStore.ListAll()
-	var entry *store.ServiceEntry
-	for _, e := range entries {
-		if e.Service.Name == input.Name {
-			entry = &e
-			break
-		}
-	}
-
-	if entry == nil {
+	entry, ok := r.serviceStore.GetEntry(service.StorageKey())
+	if !ok {
 		return nil, fmt.Errorf("service registered but not found")
 	}
 
-	return model.MapServiceFromStore(service, *entry), nil
+	return model.MapServiceFromStore(service, entry), nil
 }
 
 // DeregisterService is the resolver for the deregisterService field.
-func (r *mutationResolver) DeregisterService(ctx context.Context, name string) (bool, error) {
-	if err := r.authorizeMutation(ctx, acl.NewServiceResource(name), acl.CapabilityDeregister); err != nil {
+func (r *mutationResolver) DeregisterService(ctx context.Context, name string, id *string, namespace *string) (bool, error) {
+	if err := r.authorizeMutation(ctx, acl.NewServiceResource(name).InNamespace(stringOrEmpty(namespace)), acl.CapabilityDeregister); err != nil {
 		return false, err
 	}
 
-	// Check if service exists
-	_, exists := r.serviceStore.Get(name)
-	if !exists {
+	ns, err := r.resolveNamespace(namespace)
+	if err != nil {
+		return false, err
+	}
+
+	// Check if the instance exists and belongs to the named service
+	instanceID := instanceIDOrName(name, id)
+	storageKey := store.NamespacedKey(ns, instanceID)
+	svc, exists := r.serviceStore.Get(storageKey)
+	if !exists || svc.Name != name {
 		return false, nil
 	}
 
-	// Deregister the service
+	// Deregister the instance
 	if r.raftNode != nil {
 		cmd, err := konsulraft.NewCommand(konsulraft.CmdServiceDeregister, konsulraft.ServiceDeregisterPayload{
-			Name: name,
+			Namespace: ns,
+			ID:        instanceID,
 		})
 		if err != nil {
 			return false, err
@@ -235,42 +287,42 @@ func (r *mutationResolver) DeregisterService(ctx context.Context, name string) (
 			return false, err
 		}
 	} else {
-		r.serviceStore.Deregister(name)
+		r.serviceStore.Deregister(storageKey)
 	}
 
 	r.logger.Info("GraphQL: Service deregistered",
-		logger.String("name", name))
+		logger.String("name", name),
+		logger.String("id", instanceID))
 
 	return true, nil
 }
 
 // UpdateHeartbeat is the resolver for the updateHeartbeat field.
-func (r *mutationResolver) UpdateHeartbeat(ctx context.Context, name string) (*model.Service, error) {
-	if err := r.authorizeMutation(ctx, acl.NewServiceResource(name), acl.CapabilityWrite); err != nil {
+func (r *mutationResolver) UpdateHeartbeat(ctx context.Context, name string, id *string, namespace *string) (*model.Service, error) {
+	if err := r.authorizeMutation(ctx, acl.NewServiceResource(name).InNamespace(stringOrEmpty(namespace)), acl.CapabilityWrite); err != nil {
+		return nil, err
+	}
+
+	ns, err := r.resolveNamespace(namespace)
+	if err != nil {
 		return nil, err
 	}
 
 	// Update heartbeat
+	instanceID := instanceIDOrName(name, id)
+	storageKey := store.NamespacedKey(ns, instanceID)
 	success := false
 	if r.raftNode != nil {
-		cmd, err := konsulraft.NewCommand(konsulraft.CmdServiceHeartbeat, konsulraft.ServiceHeartbeatPayload{
-			Name: name,
-		})
-		if err != nil {
-			return nil, err
-		}
-		resp, err := r.raftNode.ApplyEntry(cmd, 5*time.Second)
-		if err != nil {
-			if errors.Is(err, hashiraft.ErrNotLeader) {
+		err := r.raftNode.ServiceHeartbeat(storageKey)
+		if err != nil && !errors.Is(err, konsulraft.ErrServiceNotFound) {
+			if errors.Is(err, konsulraft.ErrNotLeader) {
 				return nil, fmt.Errorf("not leader: %w", err)
 			}
 			return nil, err
 		}
-		if cast, ok := resp.(bool); ok {
-			success = cast
-		}
+		success = err == nil
 	} else {
-		success = r.serviceStore.Heartbeat(name)
+		success = r.serviceStore.Heartbeat(storageKey)
 	}
 	if !success {
 		r.logger.Warn("GraphQL: Heartbeat failed - service not found",
@@ -282,20 +334,236 @@ func (r *mutationResolver) UpdateHeartbeat(ctx context.Context, name string) (*m
 		logger.String("name", name))
 
 	// Get the updated service entry
-	entries := r.serviceStore.ListAll()
-	var entry *store.ServiceEntry
-	for _, e := range entries {
-		if e.Service.Name == name {
-			entry = &e
-			break
+	entry, ok := r.serviceStore.GetEntry(storageKey)
+	if !ok {
+		return nil, fmt.Errorf("service not found after heartbeat")
+	}
+
+	return model.MapServiceFromStore(entry.Service, entry), nil
+}
+
+// CreateSession is the resolver for the createSession field.
+func (r *mutationResolver) CreateSession(ctx context.Context, input *model.CreateSessionInput) (*model.Session, error) {
+	if _, err := r.claimsFromGraphQLContext(ctx); err != nil {
+		return nil, err
+	}
+	if r.sessionStore == nil {
+		return nil, errSessionsDisabled
+	}
+
+	var session store.Session
+	if input != nil {
+		session.Name = stringOrEmpty(input.Name)
+		session.Node = stringOrEmpty(input.Node)
+		session.TTL = stringOrEmpty(input.TTL)
+		session.Checks = input.Checks
+		if input.Behavior != nil {
+			session.Behavior = model.MapSessionBehaviorToStore(*input.Behavior)
+		}
+	}
+	if err := store.PrepareSession(&session); err != nil {
+		return nil, err
+	}
+	if err := store.ValidateSessionChecks(session.Checks, r.serviceStore.CheckStatus); err != nil {
+		return nil, err
+	}
+
+	var created store.Session
+	var err error
+	if r.raftNode != nil {
+		created, err = r.raftNode.SessionCreate(session)
+	} else {
+		created, err = r.sessionStore.Create(session)
+	}
+	if err != nil {
+		if errors.Is(err, konsulraft.ErrNotLeader) {
+			return nil, fmt.Errorf("not leader: %w", err)
 		}
+		return nil, err
 	}
 
-	if entry == nil {
-		return nil, fmt.Errorf("service not found after heartbeat")
+	r.logger.Info("GraphQL: Session created",
+		logger.String("session", created.ID),
+		logger.String("name", created.Name))
+
+	return model.MapSessionFromStore(created), nil
+}
+
+// RenewSession is the resolver for the renewSession field.
+func (r *mutationResolver) RenewSession(ctx context.Context, id string) (*model.Session, error) {
+	if _, err := r.claimsFromGraphQLContext(ctx); err != nil {
+		return nil, err
+	}
+	if r.sessionStore == nil {
+		return nil, errSessionsDisabled
+	}
+
+	var session store.Session
+	var err error
+	if r.raftNode != nil {
+		session, err = r.raftNode.SessionRenew(id)
+	} else {
+		session, err = r.sessionStore.Renew(id)
+	}
+	if err != nil {
+		if errors.Is(err, konsulraft.ErrNotLeader) {
+			return nil, fmt.Errorf("not leader: %w", err)
+		}
+		return nil, err
+	}
+
+	return model.MapSessionFromStore(session), nil
+}
+
+// DestroySession is the resolver for the destroySession field.
+func (r *mutationResolver) DestroySession(ctx context.Context, id string) (bool, error) {
+	if _, err := r.claimsFromGraphQLContext(ctx); err != nil {
+		return false, err
+	}
+	if r.sessionStore == nil {
+		return false, errSessionsDisabled
+	}
+
+	var err error
+	if r.raftNode != nil {
+		err = r.raftNode.SessionDestroy(id)
+	} else {
+		err = r.sessionStore.Destroy(id)
+	}
+	if err != nil {
+		if store.IsNotFound(err) {
+			return false, nil
+		}
+		if errors.Is(err, konsulraft.ErrNotLeader) {
+			return false, fmt.Errorf("not leader: %w", err)
+		}
+		return false, err
+	}
+
+	r.logger.Info("GraphQL: Session destroyed", logger.String("session", id))
+
+	return true, nil
+}
+
+// AcquireLock is the resolver for the acquireLock field.
+func (r *mutationResolver) AcquireLock(ctx context.Context, key string, session string, value string) (bool, error) {
+	if err := r.authorizeMutation(ctx, acl.NewKVResource(key), acl.CapabilityWrite); err != nil {
+		return false, err
+	}
+	if r.sessionStore == nil {
+		return false, errSessionsDisabled
+	}
+
+	var acquired bool
+	var err error
+	if r.raftNode != nil {
+		acquired, err = r.raftNode.KVAcquire(key, value, session)
+	} else {
+		acquired, err = r.sessionStore.Acquire(key, value, session)
+	}
+	if err != nil {
+		if errors.Is(err, konsulraft.ErrNotLeader) {
+			return false, fmt.Errorf("not leader: %w", err)
+		}
+		return false, err
+	}
+
+	r.logger.Info("GraphQL: Lock acquire",
+		logger.String("key", key),
+		logger.String("session", session),
+		logger.String("acquired", fmt.Sprintf("%t", acquired)))
+
+	return acquired, nil
+}
+
+// ReleaseLock is the resolver for the releaseLock field.
+func (r *mutationResolver) ReleaseLock(ctx context.Context, key string, session string) (bool, error) {
+	if err := r.authorizeMutation(ctx, acl.NewKVResource(key), acl.CapabilityWrite); err != nil {
+		return false, err
+	}
+	if r.sessionStore == nil {
+		return false, errSessionsDisabled
+	}
+
+	var released bool
+	var err error
+	if r.raftNode != nil {
+		released, err = r.raftNode.KVRelease(key, session)
+	} else {
+		released, err = r.sessionStore.Release(key, session)
+	}
+	if err != nil {
+		if errors.Is(err, konsulraft.ErrNotLeader) {
+			return false, fmt.Errorf("not leader: %w", err)
+		}
+		return false, err
 	}
 
-	return model.MapServiceFromStore(entry.Service, *entry), nil
+	return released, nil
+}
+
+// SetWebhook is the resolver for the setWebhook field.
+func (r *mutationResolver) SetWebhook(ctx context.Context, input model.WebhookInput) (*model.Webhook, error) {
+	if err := r.authorizeMutation(ctx, acl.NewAdminResource(), acl.CapabilityWrite); err != nil {
+		return nil, err
+	}
+	if r.webhookManager == nil {
+		return nil, errWebhooksDisabled
+	}
+
+	hook := model.MapWebhookInputToStore(input)
+	claims, err := r.claimsFromGraphQLContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if claims != nil {
+		hook.Policies = claims.Policies
+	}
+	hook.Normalize()
+	if err := hook.Validate(); err != nil {
+		return nil, err
+	}
+	if hook.Kind == watch.KindService {
+		if hook.Namespace, err = r.resolveNamespace(&hook.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	if r.aclEvaluator != nil && !r.aclEvaluator.Evaluate(hook.Policies, hook.Resource(), acl.CapabilityRead) {
+		return nil, fmt.Errorf("forbidden: insufficient permissions to watch this pattern")
+	}
+
+	saved, err := r.webhookManager.Set(hook)
+	if err != nil {
+		r.logger.Error("Failed to set webhook via GraphQL",
+			logger.String("name", hook.Name),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to set webhook: %w", err)
+	}
+
+	r.logger.Info("Webhook set via GraphQL",
+		logger.String("name", saved.Name),
+		logger.String("url", saved.URL))
+	return model.MapWebhookFromStore(saved), nil
+}
+
+// DeleteWebhook is the resolver for the deleteWebhook field.
+func (r *mutationResolver) DeleteWebhook(ctx context.Context, name string) (bool, error) {
+	if err := r.authorizeMutation(ctx, acl.NewAdminResource(), acl.CapabilityWrite); err != nil {
+		return false, err
+	}
+	if r.webhookManager == nil {
+		return false, errWebhooksDisabled
+	}
+
+	if err := r.webhookManager.Delete(name); err != nil {
+		if errors.Is(err, webhook.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	r.logger.Info("Webhook deleted via GraphQL", logger.String("name", name))
+	return true, nil
 }
 
 // Health is the resolver for the health field.
@@ -332,15 +600,20 @@ func (r *queryResolver) Health(ctx context.Context) (*model.SystemHealth, error)
 }
 
 // Kv is the resolver for the kv field.
-func (r *queryResolver) Kv(ctx context.Context, key string) (*model.KVPair, error) {
+func (r *queryResolver) Kv(ctx context.Context, key string, namespace *string) (*model.KVPair, error) {
 	// Check authentication if required
 	// TODO: Add authentication check when auth middleware is implemented
 
 	// Check ACL permissions if enabled
 	// TODO: Add ACL check when ACL middleware is implemented
 
+	_, storageKey, err := r.resolveKey(key, namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	// Fetch from store
-	value, exists := r.kvStore.Get(key)
+	entry, exists := r.kvStore.GetEntry(storageKey)
 	if !exists {
 		return nil, nil // Return nil for not found (nullable field)
 	}
@@ -348,16 +621,21 @@ func (r *queryResolver) Kv(ctx context.Context, key string) (*model.KVPair, erro
 	r.logger.Debug("GraphQL: fetched KV pair",
 		logger.String("key", key))
 
-	return model.MapKVPairFromStore(key, value), nil
+	return model.MapKVEntryFromStore(key, entry), nil
 }
 
 // KvList is the resolver for the kvList field.
-func (r *queryResolver) KvList(ctx context.Context, prefix *string, limit *int, offset *int) (*model.KVListResponse, error) {
+func (r *queryResolver) KvList(ctx context.Context, prefix *string, limit *int, offset *int, namespace *string) (*model.KVListResponse, error) {
 	// Check authentication
 	// TODO: Add authentication check when auth middleware is implemented
 
-	// Get all keys
-	allKeys := r.kvStore.List()
+	ns, err := r.resolveNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get all keys of the namespace
+	allKeys := r.kvStore.ListNamespace(ns)
 
 	// Filter by prefix if provided
 	var filteredKeys []string
@@ -395,9 +673,9 @@ func (r *queryResolver) KvList(ctx context.Context, prefix *string, limit *int,
 	// Build response
 	items := make([]*model.KVPair, 0, len(paginatedKeys))
 	for _, key := range paginatedKeys {
-		if value, exists := r.kvStore.Get(key); exists {
+		if entry, exists := r.kvStore.GetEntry(store.NamespacedKey(ns, key)); exists {
 			// TODO: Check ACL for each key if enabled
-			items = append(items, model.MapKVPairFromStore(key, value))
+			items = append(items, model.MapKVEntryFromStore(key, entry))
 		}
 	}
 
@@ -414,40 +692,65 @@ func (r *queryResolver) KvList(ctx context.Context, prefix *string, limit *int,
 }
 
 // Service is the resolver for the service field.
-func (r *queryResolver) Service(ctx context.Context, name string) (*model.Service, error) {
+func (r *queryResolver) Service(ctx context.Context, name string, namespace *string) (*model.Service, error) {
 	// Check authentication
 	// TODO: Add authentication check when auth middleware is implemented
 
 	// Check ACL
 	// TODO: Add ACL check when ACL middleware is implemented
 
-	// Get all entries to find the one with expiration info
-	entries := r.serviceStore.ListAll()
-	var entry *store.ServiceEntry
-	for _, e := range entries {
-		if e.Service.Name == name {
-			entry = &e
-			break
-		}
+	ns, err := r.resolveNamespace(namespace)
+	if err != nil {
+		return nil, err
 	}
 
-	if entry == nil {
+	// Return the first live instance; use serviceInstances for the full set
+	entries := r.serviceStore.ListInstanceEntries(store.NamespacedKey(ns, name))
+	if len(entries) == 0 {
 		return nil, nil // Return nil for not found
 	}
 
 	r.logger.Debug("GraphQL: fetched service",
 		logger.String("name", name))
 
-	return model.MapServiceFromStore(entry.Service, *entry), nil
+	return model.MapServiceFromStore(entries[0].Service, entries[0]), nil
+}
+
+// ServiceInstances is the resolver for the serviceInstances field.
+func (r *queryResolver) ServiceInstances(ctx context.Context, name string, namespace *string, passing *bool) ([]*model.Service, error) {
+	ns, err := r.resolveNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := r.serviceStore.ListInstanceEntries(store.NamespacedKey(ns, name))
+	entries = r.serviceStore.FilterEntriesByStatus(entries, healthStatuses(passing)...)
+
+	services := make([]*model.Service, 0, len(entries))
+	for _, entry := range entries {
+		services = append(services, model.MapServiceFromStore(entry.Service, entry))
+	}
+
+	r.logger.Debug("GraphQL: fetched service instances",
+		logger.String("name", name),
+		logger.Int("count", len(services)))
+
+	return services, nil
 }
 
 // Services is the resolver for the services field.
-func (r *queryResolver) Services(ctx context.Context, limit *int, offset *int) ([]*model.Service, error) {
+func (r *queryResolver) Services(ctx context.Context, limit *int, offset *int, namespace *string, passing *bool) ([]*model.Service, error) {
 	// Check authentication
 	// TODO: Add authentication check when auth middleware is implemented
 
-	// Get all services
-	entries := r.serviceStore.ListAll()
+	ns, err := r.resolveNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get all services of the namespace that are not failing their checks
+	entries := entriesInNamespace(r.serviceStore.ListAll(), ns)
+	entries = r.serviceStore.FilterEntriesByStatus(entries, healthStatuses(passing)...)
 
 	// Apply pagination
 	start := 0
@@ -492,10 +795,15 @@ func (r *queryResolver) ServicesCount(ctx context.Context) (int, error) {
 }
 
 // ServicesByTags is the resolver for the servicesByTags field.
-func (r *queryResolver) ServicesByTags(ctx context.Context, tags []string) ([]*model.Service, error) {
+func (r *queryResolver) ServicesByTags(ctx context.Context, tags []string, namespace *string) ([]*model.Service, error) {
 	startTime := time.Now()
 	queryName := "servicesByTags"
 
+	ns, err := r.resolveNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check authentication
 	// TODO: Add authentication check when auth middleware is implemented
 
@@ -511,16 +819,16 @@ func (r *queryResolver) ServicesByTags(ctx context.Context, tags []string) ([]*m
 	// Create a map for quick lookup
 	serviceMap := make(map[string]bool)
 	for _, svc := range storeServices {
-		serviceMap[svc.Name] = true
+		serviceMap[svc.StorageKey()] = true
 	}
 
-	// Get all entries to include expiration info
-	allEntries := r.serviceStore.ListAll()
+	// Get the entries of the namespace to include expiration info
+	nsEntries := entriesInNamespace(r.serviceStore.ListAll(), ns)
 
 	// Map to GraphQL models
 	services := make([]*model.Service, 0, len(storeServices))
-	for _, entry := range allEntries {
-		if serviceMap[entry.Service.Name] {
+	for _, entry := range nsEntries {
+		if serviceMap[entry.Service.StorageKey()] {
 			services = append(services, model.MapServiceFromStore(entry.Service, entry))
 		}
 	}
@@ -539,10 +847,15 @@ func (r *queryResolver) ServicesByTags(ctx context.Context, tags []string) ([]*m
 }
 
 // ServicesByMetadata is the resolver for the servicesByMetadata field.
-func (r *queryResolver) ServicesByMetadata(ctx context.Context, filters []*model.MetadataFilter) ([]*model.Service, error) {
+func (r *queryResolver) ServicesByMetadata(ctx context.Context, filters []*model.MetadataFilter, namespace *string) ([]*model.Service, error) {
 	startTime := time.Now()
 	queryName := "servicesByMetadata"
 
+	ns, err := r.resolveNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check authentication
 	// TODO: Add authentication check when auth middleware is implemented
 
@@ -564,16 +877,16 @@ func (r *queryResolver) ServicesByMetadata(ctx context.Context, filters []*model
 	// Create a map for quick lookup
 	serviceMap := make(map[string]bool)
 	for _, svc := range storeServices {
-		serviceMap[svc.Name] = true
+		serviceMap[svc.StorageKey()] = true
 	}
 
-	// Get all entries to include expiration info
-	allEntries := r.serviceStore.ListAll()
+	// Get the entries of the namespace to include expiration info
+	nsEntries := entriesInNamespace(r.serviceStore.ListAll(), ns)
 
 	// Map to GraphQL models
 	services := make([]*model.Service, 0, len(storeServices))
-	for _, entry := range allEntries {
-		if serviceMap[entry.Service.Name] {
+	for _, entry := range nsEntries {
+		if serviceMap[entry.Service.StorageKey()] {
 			services = append(services, model.MapServiceFromStore(entry.Service, entry))
 		}
 	}
@@ -592,10 +905,15 @@ func (r *queryResolver) ServicesByMetadata(ctx context.Context, filters []*model
 }
 
 // ServicesByQuery is the resolver for the servicesByQuery field.
-func (r *queryResolver) ServicesByQuery(ctx context.Context, tags []string, metadata []*model.MetadataFilter) ([]*model.Service, error) {
+func (r *queryResolver) ServicesByQuery(ctx context.Context, tags []string, metadata []*model.MetadataFilter, namespace *string) ([]*model.Service, error) {
 	startTime := time.Now()
 	queryName := "servicesByQuery"
 
+	ns, err := r.resolveNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check authentication
 	// TODO: Add authentication check when auth middleware is implemented
 
@@ -624,16 +942,16 @@ func (r *queryResolver) ServicesByQuery(ctx context.Context, tags []string, meta
 	// Create a map for quick lookup
 	serviceMap := make(map[string]bool)
 	for _, svc := range storeServices {
-		serviceMap[svc.Name] = true
+		serviceMap[svc.StorageKey()] = true
 	}
 
-	// Get all entries to include expiration info
-	allEntries := r.serviceStore.ListAll()
+	// Get the entries of the namespace to include expiration info
+	nsEntries := entriesInNamespace(r.serviceStore.ListAll(), ns)
 
 	// Map to GraphQL models
 	services := make([]*model.Service, 0, len(storeServices))
-	for _, entry := range allEntries {
-		if serviceMap[entry.Service.Name] {
+	for _, entry := range nsEntries {
+		if serviceMap[entry.Service.StorageKey()] {
 			services = append(services, model.MapServiceFromStore(entry.Service, entry))
 		}
 	}
@@ -651,6 +969,118 @@ func (r *queryResolver) ServicesByQuery(ctx context.Context, tags []string, meta
 	return services, nil
 }
 
+// HealthCheckHistory is the resolver for the healthCheckHistory field.
+func (r *queryResolver) HealthCheckHistory(ctx context.Context, id string) ([]*model.HealthCheckResult, error) {
+	history, ok := r.serviceStore.CheckHistory(id)
+	if !ok {
+		return nil, nil // Return nil for not found
+	}
+
+	results := make([]*model.HealthCheckResult, 0, len(history))
+	for _, entry := range history {
+		results = append(results, model.MapHealthCheckResultFromStore(entry))
+	}
+	return results, nil
+}
+
+// Namespaces is the resolver for the namespaces field.
+func (r *queryResolver) Namespaces(ctx context.Context) ([]*model.Namespace, error) {
+	if r.namespaceStore == nil {
+		defaultNS := store.Namespace{Name: store.DefaultNamespace}
+		return []*model.Namespace{model.MapNamespaceFromStore(defaultNS)}, nil
+	}
+
+	namespaces := r.namespaceStore.List()
+	result := make([]*model.Namespace, 0, len(namespaces))
+	for _, ns := range namespaces {
+		result = append(result, model.MapNamespaceFromStore(ns))
+	}
+
+	return result, nil
+}
+
+// Webhooks is the resolver for the webhooks field.
+func (r *queryResolver) Webhooks(ctx context.Context) ([]*model.Webhook, error) {
+	if err := r.authorizeMutation(ctx, acl.NewAdminResource(), acl.CapabilityRead); err != nil {
+		return nil, err
+	}
+	if r.webhookManager == nil {
+		return []*model.Webhook{}, nil
+	}
+
+	hooks := r.webhookManager.List()
+	result := make([]*model.Webhook, 0, len(hooks))
+	for _, hook := range hooks {
+		result = append(result, model.MapWebhookFromStore(hook))
+	}
+	return result, nil
+}
+
+// Webhook is the resolver for the webhook field.
+func (r *queryResolver) Webhook(ctx context.Context, name string) (*model.Webhook, error) {
+	if err := r.authorizeMutation(ctx, acl.NewAdminResource(), acl.CapabilityRead); err != nil {
+		return nil, err
+	}
+	if r.webhookManager == nil {
+		return nil, nil
+	}
+
+	hook, err := r.webhookManager.Get(name)
+	if err != nil {
+		return nil, nil // Return nil for not found
+	}
+	return model.MapWebhookFromStore(hook), nil
+}
+
+// WebhookDeliveries is the resolver for the webhookDeliveries field.
+func (r *queryResolver) WebhookDeliveries(ctx context.Context, name string) ([]*model.WebhookDelivery, error) {
+	if err := r.authorizeMutation(ctx, acl.NewAdminResource(), acl.CapabilityRead); err != nil {
+		return nil, err
+	}
+	if r.webhookManager == nil {
+		return nil, nil
+	}
+
+	deliveries, err := r.webhookManager.Deliveries(name)
+	if err != nil {
+		return nil, nil // Return nil for not found
+	}
+	result := make([]*model.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		result = append(result, model.MapWebhookDeliveryFromStore(delivery))
+	}
+	return result, nil
+}
+
+// Session is the resolver for the session field.
+func (r *queryResolver) Session(ctx context.Context, id string) (*model.Session, error) {
+	if r.sessionStore == nil {
+		return nil, nil
+	}
+
+	session, exists := r.sessionStore.Get(id)
+	if !exists {
+		return nil, nil
+	}
+
+	return model.MapSessionFromStore(session), nil
+}
+
+// Sessions is the resolver for the sessions field.
+func (r *queryResolver) Sessions(ctx context.Context) ([]*model.Session, error) {
+	if r.sessionStore == nil {
+		return []*model.Session{}, nil
+	}
+
+	sessions := r.sessionStore.List()
+	result := make([]*model.Session, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, model.MapSessionFromStore(session))
+	}
+
+	return result, nil
+}
+
 // KvChanged is the resolver for the kvChanged field.
 func (r *subscriptionResolver) KvChanged(ctx context.Context, key *string, prefix *string) (<-chan *model.KVChangeEvent, error) {
 	// Check authentication if required
@@ -705,6 +1135,14 @@ func (r *subscriptionResolver) KvChanged(ctx context.Context, key *string, prefi
 					return
 				}
 
+				if watchEvent.Type == watch.EventTypeResync {
+					// The subscriber fell behind and missed events; ending
+					// the subscription makes it subscribe again
+					r.logger.Warn("GraphQL: KV subscriber fell behind, closing subscription",
+						logger.String("watcher_id", watcher.ID))
+					return
+				}
+
 				// Convert watch event to GraphQL event
 				eventType := model.KVEventType(watchEvent.Type)
 				timestamp := scalar.FromTime(time.Unix(watchEvent.Timestamp, 0))
@@ -713,6 +1151,7 @@ func (r *subscriptionResolver) KvChanged(ctx context.Context, key *string, prefi
 					Type:      eventType,
 					Key:       watchEvent.Key,
 					Timestamp: timestamp,
+					Secret:    watchEvent.Secret,
 				}
 
 				// Add value for set events
@@ -741,15 +1180,86 @@ func (r *subscriptionResolver) KvChanged(ctx context.Context, key *string, prefi
 }
 
 // ServiceChanged is the resolver for the serviceChanged field.
-func (r *subscriptionResolver) ServiceChanged(ctx context.Context, name *string) (<-chan *model.ServiceChangeEvent, error) {
-	// TODO: Implement service watch system
-	// Services don't currently have a watch/notification system like KV store does.
-	// This would require adding event notifications to ServiceStore similar to KVStore.
+func (r *subscriptionResolver) ServiceChanged(ctx context.Context, name *string, namespace *string) (<-chan *model.ServiceChangeEvent, error) {
+	if r.watchManager == nil {
+		return nil, fmt.Errorf("watch system is disabled")
+	}
 
-	r.logger.Warn("GraphQL: ServiceChanged subscription not yet implemented",
-		logger.String("name", stringOrEmpty(name)))
+	pattern := "*"
+	if name != nil && *name != "" {
+		pattern = *name
+	}
+	ns, err := r.resolveNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := acl.NewServiceResource(pattern).InNamespace(ns)
+	if err := r.authorizeMutation(ctx, resource, acl.CapabilityRead); err != nil {
+		return nil, err
+	}
+	var policies []string
+	if claims, _ := r.claimsFromGraphQLContext(ctx); claims != nil {
+		policies = claims.Policies
+	}
 
-	return nil, fmt.Errorf("service subscriptions are not yet implemented - see TODO in code")
+	watcher, err := r.watchManager.AddServiceWatcher(ns, pattern, policies, "graphql", "")
+	if err != nil {
+		r.logger.Error("GraphQL: Failed to create service watcher",
+			logger.String("pattern", pattern),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to create watcher: %w", err)
+	}
+
+	r.logger.Info("GraphQL: Service subscription created",
+		logger.String("pattern", pattern),
+		logger.String("namespace", store.NormalizeNamespace(ns)),
+		logger.String("watcher_id", watcher.ID))
+
+	eventChan := make(chan *model.ServiceChangeEvent, 100)
+
+	go func() {
+		defer func() {
+			r.watchManager.RemoveWatcher(watcher.ID)
+			close(eventChan)
+			r.logger.Debug("GraphQL: Service subscription closed",
+				logger.String("watcher_id", watcher.ID))
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case watchEvent, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if watchEvent.Type == watch.EventTypeResync {
+					r.logger.Warn("GraphQL: Service subscriber fell behind, closing subscription",
+						logger.String("watcher_id", watcher.ID))
+					return
+				}
+
+				entry, _ := r.serviceStore.GetEntry(store.NamespacedKey(ns, watchEvent.Instance))
+				gqlEvent, err := model.MapServiceChangeEvent(watchEvent, entry)
+				if err != nil {
+					r.logger.Warn("GraphQL: Dropping service event",
+						logger.String("service", watchEvent.Key),
+						logger.Error(err))
+					continue
+				}
+
+				select {
+				case eventChan <- gqlEvent:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return eventChan, nil
 }
 
 // Mutation returns generated.MutationResolver implementation.
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/neogan74/konsul/internal/acl"
 	"github.com/neogan74/konsul/internal/logger"
 	"github.com/neogan74/konsul/internal/middleware"
+	konsulraft "github.com/neogan74/konsul/internal/raft"
 )
 
 // ACLHandler handles ACL policy management
@@ -16,6 +18,7 @@ type ACLHandler struct {
 	evaluator *acl.Evaluator
 	policyDir string
 	log       logger.Logger
+	raftNode  *konsulraft.Node
 }
 
 // NewACLHandler creates a new ACL handler
@@ -27,10 +30,50 @@ func NewACLHandler(evaluator *acl.Evaluator, policyDir string, log logger.Logger
 	}
 }
 
+// WithRaft replicates policy writes through raftNode. Writes on followers are
+// redirected to the leader.
+func (h *ACLHandler) WithRaft(raftNode *konsulraft.Node) *ACLHandler {
+	h.raftNode = raftNode
+	return h
+}
+
+// isRaftEnabled returns true if Raft clustering is enabled.
+func (h *ACLHandler) isRaftEnabled() bool {
+	return h.raftNode != nil
+}
+
+// addPolicy creates a policy through Raft when clustering is enabled.
+func (h *ACLHandler) addPolicy(policy *acl.Policy) error {
+	if h.isRaftEnabled() {
+		return h.raftNode.ACLPolicyCreate(policy)
+	}
+	return h.evaluator.AddPolicy(policy)
+}
+
+// updatePolicy replaces a policy through Raft when clustering is enabled.
+func (h *ACLHandler) updatePolicy(policy *acl.Policy) error {
+	if h.isRaftEnabled() {
+		return h.raftNode.ACLPolicyUpdate(policy)
+	}
+	return h.evaluator.UpdatePolicy(policy)
+}
+
+// deletePolicy deletes a policy through Raft when clustering is enabled.
+func (h *ACLHandler) deletePolicy(name string) error {
+	if h.isRaftEnabled() {
+		return h.raftNode.ACLPolicyDelete(name)
+	}
+	return h.evaluator.DeletePolicy(name)
+}
+
 // CreatePolicy creates a new ACL policy
 func (h *ACLHandler) CreatePolicy(c *fiber.Ctx) error {
 	log := middleware.GetLogger(c)
 
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
 	var policy acl.Policy
 	if err := c.BodyParser(&policy); err != nil {
 		log.Debug("Failed to parse policy request body", logger.Error(err))
@@ -44,8 +87,8 @@ func (h *ACLHandler) CreatePolicy(c *fiber.Ctx) error {
 	}
 
 	// Add policy to evaluator
-	if err := h.evaluator.AddPolicy(&policy); err != nil {
-		if err == acl.ErrPolicyExists {
+	if err := h.addPolicy(&policy); err != nil {
+		if errors.Is(err, acl.ErrPolicyExists) {
 			return middleware.Conflict(c, "Policy already exists")
 		}
 		log.Error("Failed to add policy", logger.String("policy", policy.Name), logger.Error(err))
@@ -98,6 +141,10 @@ func (h *ACLHandler) UpdatePolicy(c *fiber.Ctx) error {
 	name := c.Params("name")
 	log := middleware.GetLogger(c)
 
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
 	var policy acl.Policy
 	if err := c.BodyParser(&policy); err != nil {
 		log.Debug("Failed to parse policy request body", logger.Error(err))
@@ -116,8 +163,8 @@ func (h *ACLHandler) UpdatePolicy(c *fiber.Ctx) error {
 	}
 
 	// Update policy in evaluator
-	if err := h.evaluator.UpdatePolicy(&policy); err != nil {
-		if err == acl.ErrPolicyNotFound {
+	if err := h.updatePolicy(&policy); err != nil {
+		if errors.Is(err, acl.ErrPolicyNotFound) {
 			return middleware.NotFound(c, "Policy not found")
 		}
 		log.Error("Failed to update policy", logger.String("policy", policy.Name), logger.Error(err))
@@ -143,8 +190,12 @@ func (h *ACLHandler) DeletePolicy(c *fiber.Ctx) error {
 	name := c.Params("name")
 	log := middleware.GetLogger(c)
 
-	if err := h.evaluator.DeletePolicy(name); err != nil {
-		if err == acl.ErrPolicyNotFound {
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
+	if err := h.deletePolicy(name); err != nil {
+		if errors.Is(err, acl.ErrPolicyNotFound) {
 			return middleware.NotFound(c, "Policy not found")
 		}
 		log.Error("Failed to delete policy", logger.String("policy", name), logger.Error(err))
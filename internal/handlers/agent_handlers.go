@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/neogan74/konsul/internal/agent"
+	"github.com/neogan74/konsul/internal/healthcheck"
 	"github.com/neogan74/konsul/internal/logger"
 	"github.com/neogan74/konsul/internal/store"
 )
@@ -186,7 +188,6 @@ func (h *AgentHandlers) HandleAgentSync(c *fiber.Ctx) error {
 	// Update last seen
 	h.registry.UpdateLastSeen(req.AgentID)
 
-	// Build sync response with delta updates
 	resp := agent.SyncResponse{
 		CurrentIndex:   h.getCurrentIndex(),
 		ServiceUpdates: []agent.ServiceUpdate{},
@@ -194,16 +195,11 @@ func (h *AgentHandlers) HandleAgentSync(c *fiber.Ctx) error {
 		HealthUpdates:  []agent.HealthUpdate{},
 	}
 
-	// For full sync or if last index is 0, return all data
-	if req.FullSync || req.LastSyncIndex == 0 {
-		resp.ServiceUpdates = h.getAllServiceUpdates()
-		resp.KVUpdates = h.getAllKVUpdates(req.WatchedPrefixes)
-	} else {
-		// For delta sync, return only changes since last index
-		// In a real implementation, you'd track changes and return only deltas
-		// For now, we'll return all data
-		resp.ServiceUpdates = h.getAllServiceUpdates()
-		resp.KVUpdates = h.getAllKVUpdates(req.WatchedPrefixes)
+	// Return only the changes since the agent's indexes, falling back to a
+	// full sync when asked to or when the change logs no longer reach back
+	// that far
+	if req.FullSync || !h.deltaSync(&resp, req) {
+		h.fullSync(&resp, req.WatchedPrefixes)
 	}
 
 	// Update agent's sync index
@@ -243,8 +239,12 @@ func (h *AgentHandlers) HandleBatchUpdate(c *fiber.Ctx) error {
 			}
 
 		case agent.UpdateTypeDelete:
-			// Deregister service
-			h.serviceStore.Deregister(update.ServiceName)
+			// Deregister the instance; older agents only send the service name
+			id := update.ServiceID
+			if id == "" {
+				id = update.ServiceName
+			}
+			h.serviceStore.Deregister(id)
 		}
 	}
 
@@ -322,56 +322,175 @@ func (h *AgentHandlers) getCurrentIndex() int64 {
 	return h.globalIndex
 }
 
-func (h *AgentHandlers) getAllServiceUpdates() []agent.ServiceUpdate {
+func (h *AgentHandlers) lastIndex() int64 {
+	h.indexMu.Lock()
+	defer h.indexMu.Unlock()
+
+	return h.globalIndex
+}
+
+// fullSync fills resp with all services, health checks and watched KV keys.
+func (h *AgentHandlers) fullSync(resp *agent.SyncResponse, prefixes []string) {
+	resp.FullSync = true
+	resp.ServiceUpdates, resp.Indexes.Service = h.getAllServiceUpdates()
+	resp.KVUpdates, resp.Indexes.KV = h.getAllKVUpdates(prefixes)
+	resp.HealthUpdates, resp.Indexes.Health = h.getAllHealthUpdates()
+}
+
+// deltaSync fills resp with the changes after the indexes of req. It returns
+// false, leaving resp alone, when a change log no longer covers an index.
+func (h *AgentHandlers) deltaSync(resp *agent.SyncResponse, req agent.SyncRequest) bool {
+	// An agent that never synced has nothing to apply changes to, and a sync
+	// index ahead of ours means this server restarted since the last sync
+	if req.LastSyncIndex == 0 || req.LastSyncIndex > h.lastIndex() {
+		return false
+	}
+
+	serviceChanges, serviceIndex, ok := h.serviceStore.ChangesSince(req.Indexes.Service)
+	if !ok {
+		return false
+	}
+	kvChanges, kvIndex, ok := h.kvStore.ChangesSince(req.Indexes.KV)
+	if !ok {
+		return false
+	}
+	checks, removedChecks, healthIndex, ok := h.serviceStore.HealthChangesSince(req.Indexes.Health)
+	if !ok {
+		return false
+	}
+
+	for _, change := range serviceChanges {
+		if change.Entry == nil {
+			resp.ServiceUpdates = append(resp.ServiceUpdates, agent.ServiceUpdate{
+				Type:      agent.UpdateTypeDelete,
+				ServiceID: change.ID,
+			})
+			continue
+		}
+		resp.ServiceUpdates = append(resp.ServiceUpdates, serviceUpdate(agent.UpdateTypeUpdate, change.ID, *change.Entry))
+	}
+
+	for _, change := range kvChanges {
+		if !matchesPrefixes(change.Key, req.WatchedPrefixes) {
+			continue
+		}
+		if change.Entry == nil {
+			resp.KVUpdates = append(resp.KVUpdates, agent.KVUpdate{
+				Type: agent.UpdateTypeDelete,
+				Key:  change.Key,
+			})
+			continue
+		}
+		entry := change.Entry.Redacted()
+		resp.KVUpdates = append(resp.KVUpdates, agent.KVUpdate{
+			Type:  agent.UpdateTypeUpdate,
+			Key:   change.Key,
+			Entry: &entry,
+		})
+	}
+
+	for i := range checks {
+		resp.HealthUpdates = append(resp.HealthUpdates, healthUpdate(&checks[i]))
+	}
+	for _, id := range removedChecks {
+		resp.HealthUpdates = append(resp.HealthUpdates, agent.HealthUpdate{
+			Type:    agent.UpdateTypeDelete,
+			CheckID: id,
+		})
+	}
+
+	resp.Indexes = agent.SyncIndexes{KV: kvIndex, Service: serviceIndex, Health: healthIndex}
+	return true
+}
+
+// getAllServiceUpdates returns every service instance and the service index
+// they are current to.
+func (h *AgentHandlers) getAllServiceUpdates() ([]agent.ServiceUpdate, uint64) {
 	h.serviceStore.Mutex.RLock()
 	defer h.serviceStore.Mutex.RUnlock()
 
-	updates := make([]agent.ServiceUpdate, 0)
-	for name, entry := range h.serviceStore.Data {
-		updates = append(updates, agent.ServiceUpdate{
-			Type:        agent.UpdateTypeAdd,
-			ServiceName: name,
-			Service:     &entry.Service,
-			Entry:       &entry,
-		})
+	updates := make([]agent.ServiceUpdate, 0, len(h.serviceStore.Data))
+	for id, entry := range h.serviceStore.Data {
+		updates = append(updates, serviceUpdate(agent.UpdateTypeAdd, id, entry))
 	}
 
-	return updates
+	// Writes hold the store lock, so the index matches the data read
+	return updates, h.serviceStore.Index()
 }
 
-func (h *AgentHandlers) getAllKVUpdates(prefixes []string) []agent.KVUpdate {
+// getAllKVUpdates returns every KV entry under the watched prefixes and the
+// KV index they are current to.
+func (h *AgentHandlers) getAllKVUpdates(prefixes []string) ([]agent.KVUpdate, uint64) {
 	h.kvStore.Mutex.RLock()
 	defer h.kvStore.Mutex.RUnlock()
 
 	updates := make([]agent.KVUpdate, 0)
-
-	// If no prefixes specified, return all
-	if len(prefixes) == 0 {
-		for key, entry := range h.kvStore.Data {
-			updates = append(updates, agent.KVUpdate{
-				Type:  agent.UpdateTypeAdd,
-				Key:   key,
-				Entry: &entry,
-			})
+	for key, entry := range h.kvStore.Data {
+		if !matchesPrefixes(key, prefixes) {
+			continue
 		}
-		return updates
+		// Agents cache entries for local readers, which have no way to
+		// check the decrypt capability
+		entry = entry.Redacted()
+		updates = append(updates, agent.KVUpdate{
+			Type:  agent.UpdateTypeAdd,
+			Key:   key,
+			Entry: &entry,
+		})
 	}
 
-	// Return only keys matching watched prefixes
-	for key, entry := range h.kvStore.Data {
-		for _, prefix := range prefixes {
-			if len(key) >= len(prefix) && key[:len(prefix)] == prefix {
-				updates = append(updates, agent.KVUpdate{
-					Type:  agent.UpdateTypeAdd,
-					Key:   key,
-					Entry: &entry,
-				})
-				break
-			}
-		}
+	return updates, h.kvStore.Index()
+}
+
+// getAllHealthUpdates returns the status of every health check and the
+// health index they are current to.
+func (h *AgentHandlers) getAllHealthUpdates() ([]agent.HealthUpdate, uint64) {
+	// Checks change outside the service store lock; reading the index first
+	// means a change racing with the read is sent again by the next sync
+	index := h.serviceStore.HealthIndex()
+	checks := h.serviceStore.GetAllHealthChecks()
+
+	updates := make([]agent.HealthUpdate, 0, len(checks))
+	for _, check := range checks {
+		updates = append(updates, healthUpdate(check))
+	}
+	return updates, index
+}
+
+// serviceUpdate builds the sync update of a service instance.
+func serviceUpdate(updateType agent.UpdateType, id string, entry store.ServiceEntry) agent.ServiceUpdate {
+	return agent.ServiceUpdate{
+		Type:        updateType,
+		ServiceName: entry.Service.Name,
+		ServiceID:   id,
+		Service:     &entry.Service,
+		Entry:       &entry,
+	}
+}
+
+// healthUpdate builds the sync update of a health check.
+func healthUpdate(check *healthcheck.Check) agent.HealthUpdate {
+	return agent.HealthUpdate{
+		Type:      agent.UpdateTypeUpdate,
+		ServiceID: check.ServiceID,
+		CheckID:   check.ID,
+		Status:    agent.HealthStatus(check.Status),
+		Output:    check.Output,
 	}
+}
 
-	return updates
+// matchesPrefixes reports whether key starts with one of prefixes; no
+// prefixes match every key.
+func matchesPrefixes(key string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
 }
 
 // CleanupStaleAgents periodically removes stale agents
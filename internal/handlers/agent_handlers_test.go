@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/neogan74/konsul/internal/agent"
+	"github.com/neogan74/konsul/internal/changelog"
+	"github.com/neogan74/konsul/internal/healthcheck"
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/store"
+)
+
+func setupAgentHandlers() (*AgentHandlers, *fiber.App) {
+	handlers := NewAgentHandlers(store.NewServiceStore(), store.NewKVStore(), logger.GetDefault())
+	app := fiber.New()
+	app.Post("/agent/sync", handlers.HandleAgentSync)
+	return handlers, app
+}
+
+// syncAgent sends a sync request and returns the response.
+func syncAgent(t *testing.T, app *fiber.App, req agent.SyncRequest) agent.SyncResponse {
+	t.Helper()
+
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest(http.MethodPost, "/agent/sync", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpResp, err := app.Test(httpReq)
+	if err != nil {
+		t.Fatalf("sync request failed: %v", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", httpResp.StatusCode)
+	}
+
+	var resp agent.SyncResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode sync response: %v", err)
+	}
+	return resp
+}
+
+// nextSync builds the request an agent sends after receiving resp.
+func nextSync(resp agent.SyncResponse) agent.SyncRequest {
+	return agent.SyncRequest{
+		AgentID:       "agent-1",
+		LastSyncIndex: resp.CurrentIndex,
+		Indexes:       resp.Indexes,
+	}
+}
+
+func TestAgentSync_FirstSyncIsFull(t *testing.T) {
+	handlers, app := setupAgentHandlers()
+	handlers.kvStore.Set("app/a", "1")
+	handlers.kvStore.Set("other/b", "2")
+	if err := handlers.serviceStore.Register(store.Service{Name: "web", Address: "10.0.0.1", Port: 80}); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+
+	resp := syncAgent(t, app, agent.SyncRequest{AgentID: "agent-1", WatchedPrefixes: []string{"app/"}})
+	if !resp.FullSync {
+		t.Error("expected the first sync to be a full sync")
+	}
+	if len(resp.KVUpdates) != 1 || resp.KVUpdates[0].Key != "app/a" {
+		t.Errorf("expected only the watched key, got %+v", resp.KVUpdates)
+	}
+	if len(resp.ServiceUpdates) != 1 {
+		t.Errorf("expected 1 service update, got %d", len(resp.ServiceUpdates))
+	}
+	if resp.Indexes.KV != handlers.kvStore.Index() || resp.Indexes.Service != handlers.serviceStore.Index() {
+		t.Errorf("expected the store indexes, got %+v", resp.Indexes)
+	}
+}
+
+func TestAgentSync_DeltaReturnsOnlyChanges(t *testing.T) {
+	handlers, app := setupAgentHandlers()
+	for i := 0; i < 10; i++ {
+		handlers.kvStore.Set(fmt.Sprintf("key-%d", i), "v")
+	}
+	for _, id := range []string{"web-1", "web-2"} {
+		if err := handlers.serviceStore.Register(store.Service{Name: "web", ID: id, Address: "10.0.0.1", Port: 80}); err != nil {
+			t.Fatalf("register failed: %v", err)
+		}
+	}
+	first := syncAgent(t, app, agent.SyncRequest{AgentID: "agent-1"})
+
+	// Nothing changed: nothing to send
+	resp := syncAgent(t, app, nextSync(first))
+	if resp.FullSync || len(resp.KVUpdates) != 0 || len(resp.ServiceUpdates) != 0 || len(resp.HealthUpdates) != 0 {
+		t.Fatalf("expected an empty delta, got %+v", resp)
+	}
+
+	handlers.kvStore.Set("key-3", "changed")
+	handlers.kvStore.Set("key-3", "changed again")
+	handlers.kvStore.Delete("key-5")
+	handlers.serviceStore.Deregister("web-2")
+
+	resp = syncAgent(t, app, nextSync(resp))
+	if resp.FullSync {
+		t.Fatal("expected a delta sync")
+	}
+	if len(resp.KVUpdates) != 2 {
+		t.Fatalf("expected 2 KV updates, got %+v", resp.KVUpdates)
+	}
+	updates := map[string]agent.KVUpdate{}
+	for _, u := range resp.KVUpdates {
+		updates[u.Key] = u
+	}
+	if u := updates["key-3"]; u.Type != agent.UpdateTypeUpdate || u.Entry == nil || u.Entry.Value != "changed again" {
+		t.Errorf("expected the latest value of key-3, got %+v", u)
+	}
+	if u := updates["key-5"]; u.Type != agent.UpdateTypeDelete {
+		t.Errorf("expected key-5 to be deleted, got %+v", u)
+	}
+	if len(resp.ServiceUpdates) != 1 || resp.ServiceUpdates[0].Type != agent.UpdateTypeDelete || resp.ServiceUpdates[0].ServiceID != "web-2" {
+		t.Errorf("expected web-2 to be deregistered, got %+v", resp.ServiceUpdates)
+	}
+	if resp.Indexes.KV != handlers.kvStore.Index() {
+		t.Errorf("expected KV index %d, got %d", handlers.kvStore.Index(), resp.Indexes.KV)
+	}
+}
+
+func TestAgentSync_TruncatedLogFallsBackToFullSync(t *testing.T) {
+	handlers, app := setupAgentHandlers()
+	handlers.kvStore.Set("keep", "v")
+	first := syncAgent(t, app, agent.SyncRequest{AgentID: "agent-1"})
+
+	// Overflow the KV change log
+	for i := 0; i <= changelog.DefaultCapacity; i++ {
+		handlers.kvStore.Set("churn", fmt.Sprintf("%d", i))
+	}
+
+	resp := syncAgent(t, app, nextSync(first))
+	if !resp.FullSync {
+		t.Fatal("expected a full sync once the change log was truncated")
+	}
+	if len(resp.KVUpdates) != 2 {
+		t.Errorf("expected all 2 keys, got %d", len(resp.KVUpdates))
+	}
+
+	// An explicit full sync request is honoured as well
+	resp = syncAgent(t, app, agent.SyncRequest{AgentID: "agent-1", LastSyncIndex: resp.CurrentIndex, Indexes: resp.Indexes, FullSync: true})
+	if !resp.FullSync {
+		t.Error("expected a full sync when the agent asks for one")
+	}
+}
+
+func TestAgentSync_RestoredStoreFallsBackToFullSync(t *testing.T) {
+	handlers, app := setupAgentHandlers()
+	handlers.kvStore.Set("a", "1")
+	handlers.kvStore.Set("b", "2")
+	first := syncAgent(t, app, agent.SyncRequest{AgentID: "agent-1"})
+
+	// A snapshot restore replaces the data and makes the log start over
+	handlers.kvStore.RestoreSnapshot(map[string]store.KVEntry{"c": {Value: "3", ModifyIndex: 1, CreateIndex: 1}}, 1)
+
+	resp := syncAgent(t, app, nextSync(first))
+	if !resp.FullSync {
+		t.Fatal("expected a full sync after a restore")
+	}
+	if len(resp.KVUpdates) != 1 || resp.KVUpdates[0].Key != "c" {
+		t.Errorf("expected only the restored key, got %+v", resp.KVUpdates)
+	}
+}
+
+func TestAgentSync_HealthDelta(t *testing.T) {
+	handlers, app := setupAgentHandlers()
+	err := handlers.serviceStore.Register(store.Service{
+		Name:    "api",
+		Address: "10.0.0.2",
+		Port:    8080,
+		Checks:  []*healthcheck.CheckDefinition{{ID: "api-ttl", TTL: "30s"}},
+	})
+	if err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	first := syncAgent(t, app, agent.SyncRequest{AgentID: "agent-1"})
+	if len(first.HealthUpdates) != 1 || first.HealthUpdates[0].Status != agent.HealthStatusCritical {
+		t.Fatalf("expected the critical check in the full sync, got %+v", first.HealthUpdates)
+	}
+
+	if err := handlers.serviceStore.UpdateTTLCheck("api-ttl"); err != nil {
+		t.Fatalf("TTL update failed: %v", err)
+	}
+
+	resp := syncAgent(t, app, nextSync(first))
+	if resp.FullSync {
+		t.Fatal("expected a delta sync")
+	}
+	if len(resp.HealthUpdates) != 1 || resp.HealthUpdates[0].CheckID != "api-ttl" || resp.HealthUpdates[0].Status != agent.HealthStatusPassing {
+		t.Errorf("expected the passing check, got %+v", resp.HealthUpdates)
+	}
+	if len(resp.ServiceUpdates) != 0 {
+		t.Errorf("expected no service updates, got %+v", resp.ServiceUpdates)
+	}
+}
+
+// applySync applies a sync response to an agent cache the way the agent's
+// sync engine does.
+func applySync(cache *agent.Cache, resp agent.SyncResponse) {
+	if resp.FullSync {
+		cache.Purge()
+	}
+	for _, u := range resp.ServiceUpdates {
+		cache.ApplyServiceUpdate(u)
+	}
+	for _, u := range resp.KVUpdates {
+		cache.ApplyKVUpdate(u)
+	}
+	for _, u := range resp.HealthUpdates {
+		cache.ApplyHealthUpdate(u)
+	}
+}
+
+// assertCacheConverged checks that the cache holds exactly the server state.
+func assertCacheConverged(t *testing.T, round int, handlers *AgentHandlers, cache *agent.Cache, keys, names []string) {
+	t.Helper()
+
+	for _, key := range keys {
+		want, exists := handlers.kvStore.GetEntry(key)
+		got, cached := cache.GetKV(key)
+		if exists != cached {
+			t.Fatalf("round %d: key %s exists=%v cached=%v", round, key, exists, cached)
+		}
+		if exists && got.Value != want.Value {
+			t.Fatalf("round %d: key %s cached %q, want %q", round, key, got.Value, want.Value)
+		}
+	}
+
+	for _, name := range names {
+		var want []string
+		for _, svc := range handlers.serviceStore.List() {
+			if svc.Name == name {
+				want = append(want, svc.ID)
+			}
+		}
+		var got []string
+		entries, _ := cache.GetService(name)
+		for _, entry := range entries {
+			got = append(got, entry.Service.ID)
+		}
+		sort.Strings(want)
+		sort.Strings(got)
+		if fmt.Sprint(want) != fmt.Sprint(got) {
+			t.Fatalf("round %d: service %s cached %v, want %v", round, name, got, want)
+		}
+	}
+}
+
+func TestAgentSync_CacheConverges(t *testing.T) {
+	handlers, app := setupAgentHandlers()
+	cache := agent.NewCache(agent.CacheConfig{
+		ServiceTTL: time.Hour,
+		KVTTL:      time.Hour,
+		HealthTTL:  time.Hour,
+		MaxEntries: 1000,
+	})
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	keys := []string{"a", "b", "c", "d", "e", "f"}
+	names := []string{"web", "api"}
+	ids := []string{"i1", "i2", "i3", "i4"}
+
+	resp := syncAgent(t, app, agent.SyncRequest{AgentID: "agent-1"})
+	applySync(cache, resp)
+
+	deltas := 0
+	for round := 0; round < 50; round++ {
+		for op := rng.IntN(5); op >= 0; op-- {
+			switch rng.IntN(4) {
+			case 0:
+				handlers.kvStore.Set(keys[rng.IntN(len(keys))], fmt.Sprintf("v%d", round))
+			case 1:
+				handlers.kvStore.Delete(keys[rng.IntN(len(keys))])
+			case 2:
+				err := handlers.serviceStore.Register(store.Service{
+					Name:    names[rng.IntN(len(names))],
+					ID:      ids[rng.IntN(len(ids))],
+					Address: "10.0.0.1",
+					Port:    round + 1,
+				})
+				if err != nil {
+					t.Fatalf("register failed: %v", err)
+				}
+			case 3:
+				handlers.serviceStore.Deregister(ids[rng.IntN(len(ids))])
+			}
+		}
+
+		resp = syncAgent(t, app, nextSync(resp))
+		if !resp.FullSync {
+			deltas++
+		}
+		applySync(cache, resp)
+		assertCacheConverged(t, round, handlers, cache, keys, names)
+	}
+
+	if deltas != 50 {
+		t.Errorf("expected every sync after the first to be a delta, got %d of 50", deltas)
+	}
+}
@@ -1,16 +1,21 @@
 package handlers
 
 import (
+	"errors"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/neogan74/konsul/internal/auth"
 	"github.com/neogan74/konsul/internal/middleware"
+	konsulraft "github.com/neogan74/konsul/internal/raft"
 )
 
 type AuthHandler struct {
 	jwtService    *auth.JWTService
 	apiKeyService *auth.APIKeyService
+	users         *auth.UserService
+	raftNode      *konsulraft.Node
 }
 
 const defaultUserRole = "user"
@@ -22,9 +27,27 @@ func NewAuthHandler(jwtService *auth.JWTService, apiKeyService *auth.APIKeyServi
 	}
 }
 
+// WithUsers checks logins against users. Without a user store no one can
+// log in.
+func (h *AuthHandler) WithUsers(users *auth.UserService) *AuthHandler {
+	h.users = users
+	return h
+}
+
+// WithRaft replicates API key writes through raftNode. Writes on followers
+// are redirected to the leader.
+func (h *AuthHandler) WithRaft(raftNode *konsulraft.Node) *AuthHandler {
+	h.raftNode = raftNode
+	return h
+}
+
+// isRaftEnabled returns true if Raft clustering is enabled.
+func (h *AuthHandler) isRaftEnabled() bool {
+	return h.raftNode != nil
+}
+
 // LoginRequest represents the login request body
 type LoginRequest struct {
-	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	Password string `json:"password"`
 }
@@ -41,7 +64,8 @@ type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
-// Login handles user login and returns JWT tokens
+// Login checks a username and password against the user store and returns
+// JWT tokens carrying the roles and policies of the user
 func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	var req LoginRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -51,42 +75,49 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	}
 
 	// Validate required fields
-	if req.UserID == "" || req.Username == "" {
+	if req.Username == "" || req.Password == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "user_id and username are required",
+			"error": "username and password are required",
 		})
 	}
 
-	// TODO: Add actual password validation against a user store
-	// For now, this is a simple implementation that generates tokens
-
-	// Never trust client-provided roles.
-	roles := []string{defaultUserRole}
-
-	// Generate access token
-	token, err := h.jwtService.GenerateToken(req.UserID, req.Username, roles)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to generate token",
+	if h.users == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "no user store configured",
 		})
 	}
 
-	// Generate refresh token
-	refreshToken, err := h.jwtService.GenerateRefreshToken(req.UserID, req.Username, roles)
+	user, err := h.users.Authenticate(req.Username, req.Password)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to generate refresh token",
-		})
+		switch {
+		case errors.Is(err, auth.ErrInvalidCredentials):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "invalid username or password",
+			})
+		case errors.Is(err, auth.ErrAccountLocked):
+			if until := h.users.LockedUntil(req.Username); !until.IsZero() {
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(time.Until(until).Seconds())+1))
+			}
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": "account locked after too many failed logins",
+			})
+		case errors.Is(err, auth.ErrUserDisabled):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "user is disabled",
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "failed to authenticate",
+			})
+		}
 	}
 
-	return c.JSON(LoginResponse{
-		Token:        token,
-		RefreshToken: refreshToken,
-		ExpiresIn:    int64(15 * 60), // 15 minutes in seconds
-	})
+	return h.issueTokens(c, user)
 }
 
-// Refresh handles token refresh
+// Refresh handles token refresh. The new tokens carry the current roles and
+// policies of the user, and users that were deleted or disabled since the
+// refresh token was issued are rejected.
 func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
 	var req RefreshRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -102,8 +133,7 @@ func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
 		})
 	}
 
-	// Refresh tokens
-	newToken, newRefreshToken, err := h.jwtService.RefreshToken(req.RefreshToken)
+	claims, err := h.jwtService.ValidateRefreshToken(req.RefreshToken)
 	if err != nil {
 		switch err {
 		case auth.ErrTokenExpired:
@@ -121,9 +151,53 @@ func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
 		}
 	}
 
+	if h.users == nil {
+		return h.issueTokens(c, &auth.User{
+			ID:       claims.UserID,
+			Username: claims.Username,
+			Roles:    claims.Roles,
+			Policies: claims.Policies,
+		})
+	}
+
+	// A user deleted and created again under the same name gets a new ID, so
+	// the refresh tokens of the old account do not carry over
+	user, err := h.users.GetUser(claims.Username)
+	if err != nil || user.ID != claims.UserID || user.Disabled {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "user is no longer active",
+		})
+	}
+	return h.issueTokens(c, user)
+}
+
+// issueTokens responds with an access and refresh token for user. Users
+// without roles get the default role.
+func (h *AuthHandler) issueTokens(c *fiber.Ctx, user *auth.User) error {
+	roles := user.Roles
+	if len(roles) == 0 {
+		roles = []string{defaultUserRole}
+	}
+
+	// Generate access token
+	token, err := h.jwtService.GenerateTokenWithPolicies(user.ID, user.Username, roles, user.Policies)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to generate token",
+		})
+	}
+
+	// Generate refresh token
+	refreshToken, err := h.jwtService.GenerateRefreshTokenWithPolicies(user.ID, user.Username, roles, user.Policies)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to generate refresh token",
+		})
+	}
+
 	return c.JSON(LoginResponse{
-		Token:        newToken,
-		RefreshToken: newRefreshToken,
+		Token:        token,
+		RefreshToken: refreshToken,
 		ExpiresIn:    int64(15 * 60), // 15 minutes in seconds
 	})
 }
@@ -162,6 +236,10 @@ type CreateAPIKeyResponse struct {
 
 // CreateAPIKey creates a new API key
 func (h *AuthHandler) CreateAPIKey(c *fiber.Ctx) error {
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
 	var req CreateAPIKeyRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -183,8 +261,15 @@ func (h *AuthHandler) CreateAPIKey(c *fiber.Ctx) error {
 		expiresAt = &exp
 	}
 
-	// Generate API key
-	keyString, apiKey, err := h.apiKeyService.GenerateAPIKey(req.Name, req.Permissions, req.Metadata, expiresAt)
+	// Generate API key; in clustered mode only its hash is replicated
+	keyString, apiKey, err := h.apiKeyService.NewAPIKey(req.Name, req.Permissions, req.Metadata, expiresAt)
+	if err == nil {
+		if h.isRaftEnabled() {
+			err = h.raftNode.APIKeyCreate(apiKey)
+		} else {
+			err = h.apiKeyService.AddAPIKey(apiKey)
+		}
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "failed to generate API key",
@@ -232,6 +317,10 @@ func (h *AuthHandler) GetAPIKey(c *fiber.Ctx) error {
 
 // RevokeAPIKey revokes an API key
 func (h *AuthHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
 	keyID := c.Params("id")
 	if keyID == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -239,8 +328,14 @@ func (h *AuthHandler) RevokeAPIKey(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.apiKeyService.RevokeAPIKey(keyID); err != nil {
-		if err == auth.ErrAPIKeyNotFound {
+	var err error
+	if h.isRaftEnabled() {
+		err = h.raftNode.APIKeyRevoke(keyID)
+	} else {
+		err = h.apiKeyService.RevokeAPIKey(keyID)
+	}
+	if err != nil {
+		if errors.Is(err, auth.ErrAPIKeyNotFound) {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error": "API key not found",
 			})
@@ -257,6 +352,10 @@ func (h *AuthHandler) RevokeAPIKey(c *fiber.Ctx) error {
 
 // DeleteAPIKey deletes an API key
 func (h *AuthHandler) DeleteAPIKey(c *fiber.Ctx) error {
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
 	keyID := c.Params("id")
 	if keyID == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -264,8 +363,14 @@ func (h *AuthHandler) DeleteAPIKey(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.apiKeyService.DeleteAPIKey(keyID); err != nil {
-		if err == auth.ErrAPIKeyNotFound {
+	var err error
+	if h.isRaftEnabled() {
+		err = h.raftNode.APIKeyDelete(keyID)
+	} else {
+		err = h.apiKeyService.DeleteAPIKey(keyID)
+	}
+	if err != nil {
+		if errors.Is(err, auth.ErrAPIKeyNotFound) {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error": "API key not found",
 			})
@@ -290,6 +395,10 @@ type UpdateAPIKeyRequest struct {
 
 // UpdateAPIKey updates an API key
 func (h *AuthHandler) UpdateAPIKey(c *fiber.Ctx) error {
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
 	keyID := c.Params("id")
 	if keyID == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -304,8 +413,14 @@ func (h *AuthHandler) UpdateAPIKey(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.apiKeyService.UpdateAPIKey(keyID, req.Name, req.Permissions, req.Metadata, req.Enabled); err != nil {
-		if err == auth.ErrAPIKeyNotFound {
+	var err error
+	if h.isRaftEnabled() {
+		err = h.raftNode.APIKeyUpdate(keyID, req.Name, req.Permissions, req.Metadata, req.Enabled)
+	} else {
+		err = h.apiKeyService.UpdateAPIKey(keyID, req.Name, req.Permissions, req.Metadata, req.Enabled)
+	}
+	if err != nil {
+		if errors.Is(err, auth.ErrAPIKeyNotFound) {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error": "API key not found",
 			})
@@ -15,7 +15,11 @@ import (
 func setupAuthHandler() (*AuthHandler, *fiber.App) {
 	jwtService := auth.NewJWTService("test-secret-key-for-testing-purposes-only", 15*time.Minute, 60*time.Minute, "konsul-test")
 	apiKeyService := auth.NewAPIKeyService("konsul-test")
-	handler := NewAuthHandler(jwtService, apiKeyService)
+	users := auth.NewUserService(3, time.Minute)
+	if _, err := users.CreateUser("testuser", "testpassword", nil, nil, nil); err != nil {
+		panic(err)
+	}
+	handler := NewAuthHandler(jwtService, apiKeyService).WithUsers(users)
 
 	app := fiber.New()
 
@@ -38,7 +42,7 @@ func setupAuthHandler() (*AuthHandler, *fiber.App) {
 func TestAuthHandler_Login_Success(t *testing.T) {
 	handler, app := setupAuthHandler()
 
-	body := bytes.NewReader([]byte(`{"user_id": "user1", "username": "testuser", "roles": ["admin"]}`))
+	body := bytes.NewReader([]byte(`{"username": "testuser", "password": "testpassword", "roles": ["admin"]}`))
 	req := httptest.NewRequest(http.MethodPost, "/auth/login", body)
 	req.Header.Set("Content-Type", "application/json")
 
@@ -78,7 +82,7 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 func TestAuthHandler_Login_IgnoresClientRoles(t *testing.T) {
 	handler, app := setupAuthHandler()
 
-	body := bytes.NewReader([]byte(`{"user_id": "user1", "username": "testuser", "roles": ["admin","superuser"]}`))
+	body := bytes.NewReader([]byte(`{"username": "testuser", "password": "testpassword", "roles": ["admin","superuser"]}`))
 	req := httptest.NewRequest(http.MethodPost, "/auth/login", body)
 	req.Header.Set("Content-Type", "application/json")
 
@@ -112,8 +116,8 @@ func TestAuthHandler_Login_MissingFields(t *testing.T) {
 		name string
 		body string
 	}{
-		{"missing user_id", `{"username": "testuser"}`},
-		{"missing username", `{"user_id": "user1"}`},
+		{"missing password", `{"username": "testuser"}`},
+		{"missing username", `{"password": "testpassword"}`},
 		{"empty body", `{}`},
 	}
 
@@ -156,7 +160,7 @@ func TestAuthHandler_Refresh_Success(t *testing.T) {
 	handler, app := setupAuthHandler()
 
 	// First, login to get tokens
-	loginBody := bytes.NewReader([]byte(`{"user_id": "user1", "username": "testuser", "roles": ["admin"]}`))
+	loginBody := bytes.NewReader([]byte(`{"username": "testuser", "password": "testpassword", "roles": ["admin"]}`))
 	loginReq := httptest.NewRequest(http.MethodPost, "/auth/login", loginBody)
 	loginReq.Header.Set("Content-Type", "application/json")
 
@@ -211,7 +215,7 @@ func TestAuthHandler_Refresh_Success(t *testing.T) {
 func TestAuthHandler_Refresh_DoesNotTrustBodyIdentityFields(t *testing.T) {
 	handler, app := setupAuthHandler()
 
-	loginBody := bytes.NewReader([]byte(`{"user_id": "user1", "username": "testuser"}`))
+	loginBody := bytes.NewReader([]byte(`{"username": "testuser", "password": "testpassword"}`))
 	loginReq := httptest.NewRequest(http.MethodPost, "/auth/login", loginBody)
 	loginReq.Header.Set("Content-Type", "application/json")
 
@@ -254,6 +258,44 @@ func TestAuthHandler_Refresh_DoesNotTrustBodyIdentityFields(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_Refresh_RecreatedUser(t *testing.T) {
+	handler, app := setupAuthHandler()
+
+	loginBody := bytes.NewReader([]byte(`{"username": "testuser", "password": "testpassword"}`))
+	loginReq := httptest.NewRequest(http.MethodPost, "/auth/login", loginBody)
+	loginReq.Header.Set("Content-Type", "application/json")
+
+	loginResp, err := app.Test(loginReq)
+	if err != nil {
+		t.Fatalf("Login request failed: %v", err)
+	}
+
+	var loginResult LoginResponse
+	if err := json.NewDecoder(loginResp.Body).Decode(&loginResult); err != nil {
+		t.Fatalf("failed to decode login response: %v", err)
+	}
+
+	// A new account under the same name must not inherit the refresh token
+	if err := handler.users.DeleteUser("testuser"); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+	if _, err := handler.users.CreateUser("testuser", "otherpassword", []string{"admin"}, nil, nil); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	refreshBody := bytes.NewReader([]byte(`{"refresh_token": "` + loginResult.RefreshToken + `"}`))
+	refreshReq := httptest.NewRequest(http.MethodPost, "/auth/refresh", refreshBody)
+	refreshReq.Header.Set("Content-Type", "application/json")
+
+	refreshResp, err := app.Test(refreshReq)
+	if err != nil {
+		t.Fatalf("Refresh request failed: %v", err)
+	}
+	if refreshResp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", refreshResp.StatusCode)
+	}
+}
+
 func TestAuthHandler_Refresh_MissingFields(t *testing.T) {
 	_, app := setupAuthHandler()
 
@@ -578,3 +620,78 @@ func TestAuthHandler_DeleteAPIKey_NotFound(t *testing.T) {
 		t.Errorf("expected 404, got %d", resp.StatusCode)
 	}
 }
+
+func TestAuthHandler_Login_WrongPasswordAndLockout(t *testing.T) {
+	_, app := setupAuthHandler()
+
+	login := func(password string) int {
+		body := bytes.NewReader([]byte(`{"username": "testuser", "password": "` + password + `"}`))
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", body)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Login request failed: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	// The test user service locks accounts after 3 failed logins
+	for i := 0; i < 3; i++ {
+		if status := login("wrong-password"); status != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: expected 401, got %d", i+1, status)
+		}
+	}
+	if status := login("testpassword"); status != http.StatusTooManyRequests {
+		t.Errorf("expected 429 for locked account, got %d", status)
+	}
+}
+
+func TestAuthHandler_Login_UnknownUser(t *testing.T) {
+	_, app := setupAuthHandler()
+
+	body := bytes.NewReader([]byte(`{"username": "nobody", "password": "testpassword"}`))
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", body)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Login request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", resp.StatusCode)
+	}
+}
+
+func TestAuthHandler_Login_UserPolicies(t *testing.T) {
+	handler, app := setupAuthHandler()
+	if _, err := handler.users.CreateUser("ops", "ops-password", []string{"admin"}, []string{"operators"}, nil); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	body := bytes.NewReader([]byte(`{"username": "ops", "password": "ops-password"}`))
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", body)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Login request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var result LoginResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	claims, err := handler.jwtService.ValidateToken(result.Token)
+	if err != nil {
+		t.Fatalf("failed to validate token: %v", err)
+	}
+	if len(claims.Roles) != 1 || claims.Roles[0] != "admin" {
+		t.Errorf("expected roles [admin], got %v", claims.Roles)
+	}
+	if len(claims.Policies) != 1 || claims.Policies[0] != "operators" {
+		t.Errorf("expected policies [operators], got %v", claims.Policies)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/neogan74/konsul/internal/logger"
 	"github.com/neogan74/konsul/internal/middleware"
 	"github.com/neogan74/konsul/internal/persistence"
+	"github.com/neogan74/konsul/internal/store"
 )
 
 // BackupHandler handles backup and restore operations
@@ -102,6 +104,8 @@ func (h *BackupHandler) ExportData(c *fiber.Ctx) error {
 			return middleware.InternalServerError(c, "Failed to export data")
 		}
 
+		redactSecrets(data)
+
 		log.Info("Data exported successfully")
 		return c.JSON(data)
 	}
@@ -124,6 +128,7 @@ func (h *BackupHandler) ImportData(c *fiber.Ctx) error {
 
 	// Check if engine has import capability
 	if badgerEngine, ok := h.engine.(*persistence.BadgerEngine); ok {
+		dropRedactedSecrets(data)
 		if err := badgerEngine.ImportData(data); err != nil {
 			log.Error("Failed to import data", logger.Error(err))
 			return middleware.InternalServerError(c, "Failed to import data")
@@ -149,3 +154,43 @@ func (h *BackupHandler) ListBackups(c *fiber.Ctx) error {
 		"note":    "Check the ./backups directory for available backup files",
 	})
 }
+
+// redactSecrets removes the values of secret KV entries from exported data.
+// Exports are meant to be read, so they get the same redaction as /kv reads.
+func redactSecrets(data map[string]interface{}) {
+	kvData, ok := data["kv"].(map[string]string)
+	if !ok {
+		return
+	}
+	for key, raw := range kvData {
+		var entry store.KVEntry
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil || !entry.Secret {
+			continue
+		}
+		redacted, err := json.Marshal(entry.Redacted())
+		if err != nil {
+			continue
+		}
+		kvData[key] = string(redacted)
+	}
+}
+
+// dropRedactedSecrets removes the secret KV entries of an export from data
+// to import: their values were redacted, so importing them would overwrite
+// the secrets with values that cannot be decrypted.
+func dropRedactedSecrets(data map[string]interface{}) {
+	kvData, ok := data["kv"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for key, raw := range kvData {
+		value, ok := raw.(string)
+		if !ok {
+			continue
+		}
+		var entry store.KVEntry
+		if err := json.Unmarshal([]byte(value), &entry); err == nil && entry.Secret && entry.Value == "" {
+			delete(kvData, key)
+		}
+	}
+}
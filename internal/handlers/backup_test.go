@@ -11,6 +11,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/neogan74/konsul/internal/logger"
 	"github.com/neogan74/konsul/internal/persistence"
+	"github.com/neogan74/konsul/internal/store"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -21,12 +22,16 @@ type MockPersistenceEngine struct {
 	CloseFunc         func() error
 	GetFunc           func(key string) ([]byte, error)
 	SetFunc           func(key string, value []byte) error
+	SetWithTTLFunc    func(key string, value []byte, ttl time.Duration) error
 	DeleteFunc        func(key string) error
 	ListFunc          func(prefix string) ([]string, error)
 	GetServiceFunc    func(name string) ([]byte, error)
 	SetServiceFunc    func(name string, data []byte, ttl time.Duration) error
 	DeleteServiceFunc func(name string) error
 	ListServicesFunc  func() ([]string, error)
+	SetACLFunc        func(kind, id string, data []byte) error
+	DeleteACLFunc     func(kind, id string) error
+	ListACLFunc       func(kind string) (map[string][]byte, error)
 	BatchSetFunc      func(items map[string][]byte) error
 	BatchDeleteFunc   func(keys []string) error
 	BeginTxFunc       func() (persistence.Transaction, error)
@@ -61,6 +66,13 @@ func (m *MockPersistenceEngine) List(prefix string) ([]string, error) {
 	return []string{}, nil
 }
 
+func (m *MockPersistenceEngine) SetWithTTL(key string, value []byte, ttl time.Duration) error {
+	if m.SetWithTTLFunc != nil {
+		return m.SetWithTTLFunc(key, value, ttl)
+	}
+	return nil
+}
+
 // Service operations
 func (m *MockPersistenceEngine) GetService(name string) ([]byte, error) {
 	if m.GetServiceFunc != nil {
@@ -90,6 +102,28 @@ func (m *MockPersistenceEngine) ListServices() ([]string, error) {
 	return []string{}, nil
 }
 
+// ACL operations
+func (m *MockPersistenceEngine) SetACL(kind, id string, data []byte) error {
+	if m.SetACLFunc != nil {
+		return m.SetACLFunc(kind, id, data)
+	}
+	return nil
+}
+
+func (m *MockPersistenceEngine) DeleteACL(kind, id string) error {
+	if m.DeleteACLFunc != nil {
+		return m.DeleteACLFunc(kind, id)
+	}
+	return nil
+}
+
+func (m *MockPersistenceEngine) ListACL(kind string) (map[string][]byte, error) {
+	if m.ListACLFunc != nil {
+		return m.ListACLFunc(kind)
+	}
+	return map[string][]byte{}, nil
+}
+
 // Batch operations
 func (m *MockPersistenceEngine) BatchSet(items map[string][]byte) error {
 	if m.BatchSetFunc != nil {
@@ -356,6 +390,39 @@ func TestBackupHandler_ExportData_NilEngine(t *testing.T) {
 	}
 }
 
+func TestRedactSecrets(t *testing.T) {
+	secret, _ := json.Marshal(store.KVEntry{Value: "konsul:v1:abcd:wrapped:sealed", Secret: true})
+	plain, _ := json.Marshal(store.KVEntry{Value: "visible"})
+	data := map[string]interface{}{
+		"kv": map[string]string{"db/password": string(secret), "app/name": string(plain)},
+	}
+
+	redactSecrets(data)
+	kvData := data["kv"].(map[string]string)
+	var entry store.KVEntry
+	if err := json.Unmarshal([]byte(kvData["db/password"]), &entry); err != nil {
+		t.Fatalf("failed to decode exported entry: %v", err)
+	}
+	if !entry.Secret || entry.Value != "" {
+		t.Errorf("expected redacted secret in export, got %+v", entry)
+	}
+	if kvData["app/name"] != string(plain) {
+		t.Errorf("expected plain entry to be exported unchanged, got %s", kvData["app/name"])
+	}
+
+	// Re-importing the export skips the redacted secrets
+	imported := map[string]interface{}{
+		"kv": map[string]interface{}{"db/password": kvData["db/password"], "app/name": kvData["app/name"]},
+	}
+	dropRedactedSecrets(imported)
+	if _, ok := imported["kv"].(map[string]interface{})["db/password"]; ok {
+		t.Error("expected redacted secret to be dropped from import")
+	}
+	if _, ok := imported["kv"].(map[string]interface{})["app/name"]; !ok {
+		t.Error("expected plain entry to be imported")
+	}
+}
+
 func TestBackupHandler_ImportData_Success(t *testing.T) {
 	mockEngine := &MockPersistenceEngine{}
 
@@ -18,6 +18,7 @@ import (
 type BatchHandler struct {
 	kvStore      *store.KVStore
 	serviceStore *store.ServiceStore
+	namespaces   *store.NamespaceStore
 	raftNode     *konsulraft.Node
 }
 
@@ -30,6 +31,13 @@ func NewBatchHandler(kvStore *store.KVStore, serviceStore *store.ServiceStore, r
 	}
 }
 
+// WithNamespaces scopes batch requests to the namespace of the request.
+// Without a registry only the default namespace is available.
+func (h *BatchHandler) WithNamespaces(namespaces *store.NamespaceStore) *BatchHandler {
+	h.namespaces = namespaces
+	return h
+}
+
 // BatchKVGetRequest represents a request to get multiple keys
 type BatchKVGetRequest struct {
 	Keys []string `json:"keys"`
@@ -44,6 +52,7 @@ type BatchKVGetResponse struct {
 // BatchKVSetRequest represents a request to set multiple key-value pairs
 type BatchKVSetRequest struct {
 	Items map[string]string `json:"items"`
+	TTL   string            `json:"ttl,omitempty"` // Optional TTL applied to every key
 }
 
 // BatchKVSetResponse represents the response for batch set
@@ -110,6 +119,15 @@ func (h *BatchHandler) BatchKVGet(c *fiber.Ctx) error {
 		return middleware.BadRequest(c, "Maximum 1000 keys per batch request")
 	}
 
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+	keys, err := namespacedKeys(ns, req.Keys)
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+
 	log.Debug("Batch getting keys", logger.Int("count", len(req.Keys)))
 
 	if c.Query("consistent") == "true" && h.raftNode != nil {
@@ -121,7 +139,8 @@ func (h *BatchHandler) BatchKVGet(c *fiber.Ctx) error {
 		}
 	}
 
-	found, notFound := h.kvStore.BatchGet(req.Keys)
+	found, notFound := h.kvStore.BatchGet(keys)
+	found, notFound = stripNamespaceMap(found), stripNamespaceKeys(notFound)
 
 	log.Info("Batch get completed",
 		logger.Int("found", len(found)),
@@ -153,11 +172,27 @@ func (h *BatchHandler) BatchKVSet(c *fiber.Ctx) error {
 		return middleware.BadRequest(c, "Maximum 1000 items per batch request")
 	}
 
+	ttl, err := store.ParseKVTTL(req.TTL)
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+	items, err := namespacedMap(ns, req.Items)
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+
 	log.Debug("Batch setting keys", logger.Int("count", len(req.Items)))
 
 	if h.raftNode != nil {
 		cmd, marshalErr := konsulraft.NewCommand(konsulraft.CmdKVBatchSet, konsulraft.KVBatchSetPayload{
-			Items: req.Items,
+			Namespace: ns,
+			Items:     req.Items,
+			ExpiresAt: store.KVExpiry(ttl),
 		})
 		if marshalErr != nil {
 			log.Error("Failed to build raft log entry", logger.Error(marshalErr))
@@ -173,7 +208,12 @@ func (h *BatchHandler) BatchKVSet(c *fiber.Ctx) error {
 			log.Error("Failed to batch set keys", logger.Error(err))
 			return middleware.InternalError(c, "Failed to set keys")
 		}
-	} else if err := h.kvStore.BatchSet(req.Items); err != nil {
+	} else if ttl > 0 {
+		if err := h.kvStore.BatchSetWithTTL(items, ttl); err != nil {
+			log.Error("Failed to batch set keys", logger.Error(err))
+			return middleware.InternalError(c, "Failed to set keys")
+		}
+	} else if err := h.kvStore.BatchSet(items); err != nil {
 		log.Error("Failed to batch set keys", logger.Error(err))
 		return middleware.InternalError(c, "Failed to set keys")
 	}
@@ -213,11 +253,21 @@ func (h *BatchHandler) BatchKVDelete(c *fiber.Ctx) error {
 		return middleware.BadRequest(c, "Maximum 1000 keys per batch request")
 	}
 
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+	keys, err := namespacedKeys(ns, req.Keys)
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+
 	log.Debug("Batch deleting keys", logger.Int("count", len(req.Keys)))
 
 	if h.raftNode != nil {
 		cmd, marshalErr := konsulraft.NewCommand(konsulraft.CmdKVBatchDelete, konsulraft.KVBatchDeletePayload{
-			Keys: req.Keys,
+			Namespace: ns,
+			Keys:      req.Keys,
 		})
 		if marshalErr != nil {
 			log.Error("Failed to build raft log entry", logger.Error(marshalErr))
@@ -233,7 +283,7 @@ func (h *BatchHandler) BatchKVDelete(c *fiber.Ctx) error {
 			log.Error("Failed to batch delete keys", logger.Error(err))
 			return middleware.InternalError(c, "Failed to delete keys")
 		}
-	} else if err := h.kvStore.BatchDelete(req.Keys); err != nil {
+	} else if err := h.kvStore.BatchDelete(keys); err != nil {
 		log.Error("Failed to batch delete keys", logger.Error(err))
 		return middleware.InternalError(c, "Failed to delete keys")
 	}
@@ -275,12 +325,24 @@ func (h *BatchHandler) BatchKVSetCAS(c *fiber.Ctx) error {
 		}
 	}
 
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+	items, err := namespacedMap(ns, req.Items)
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+	expectedIndices, err := namespacedMap(ns, req.ExpectedIndices)
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+
 	log.Debug("Batch setting keys with CAS", logger.Int("count", len(req.Items)))
 
 	var newIndices map[string]uint64
-	var err error
 	if h.raftNode != nil {
-		newIndices, err = h.raftNode.KVBatchSetCAS(req.Items, req.ExpectedIndices)
+		newIndices, err = h.raftNode.KVBatchSetCAS(items, expectedIndices)
 		if errors.Is(err, konsulraft.ErrNotLeader) {
 			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
 				"error":  "not leader",
@@ -288,7 +350,7 @@ func (h *BatchHandler) BatchKVSetCAS(c *fiber.Ctx) error {
 			})
 		}
 	} else {
-		newIndices, err = h.kvStore.BatchSetCAS(req.Items, req.ExpectedIndices)
+		newIndices, err = h.kvStore.BatchSetCAS(items, expectedIndices)
 	}
 	if err != nil {
 		if store.IsCASConflict(err) {
@@ -315,7 +377,7 @@ func (h *BatchHandler) BatchKVSetCAS(c *fiber.Ctx) error {
 
 	return c.JSON(BatchKVSetCASResponse{
 		Message:    fmt.Sprintf("Successfully set %d keys with CAS", len(req.Items)),
-		NewIndices: newIndices,
+		NewIndices: stripNamespaceMap(newIndices),
 		Count:      len(req.Items),
 	})
 }
@@ -346,11 +408,23 @@ func (h *BatchHandler) BatchKVDeleteCAS(c *fiber.Ctx) error {
 		}
 	}
 
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+	keys, err := namespacedKeys(ns, req.Keys)
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+	expectedIndices, err := namespacedMap(ns, req.ExpectedIndices)
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+
 	log.Debug("Batch deleting keys with CAS", logger.Int("count", len(req.Keys)))
 
-	var err error
 	if h.raftNode != nil {
-		err = h.raftNode.KVBatchDeleteCAS(req.Keys, req.ExpectedIndices)
+		err = h.raftNode.KVBatchDeleteCAS(keys, expectedIndices)
 		if errors.Is(err, konsulraft.ErrNotLeader) {
 			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
 				"error":  "not leader",
@@ -358,7 +432,7 @@ func (h *BatchHandler) BatchKVDeleteCAS(c *fiber.Ctx) error {
 			})
 		}
 	} else {
-		err = h.kvStore.BatchDeleteCAS(req.Keys, req.ExpectedIndices)
+		err = h.kvStore.BatchDeleteCAS(keys, expectedIndices)
 	}
 	if err != nil {
 		if store.IsCASConflict(err) {
@@ -434,6 +508,11 @@ func (h *BatchHandler) BatchServiceRegister(c *fiber.Ctx) error {
 		return middleware.BadRequest(c, "Maximum 100 services per batch request")
 	}
 
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+
 	log.Debug("Batch registering services", logger.Int("count", len(req.Services)))
 
 	registered := make([]string, 0, len(req.Services))
@@ -453,6 +532,15 @@ func (h *BatchHandler) BatchServiceRegister(c *fiber.Ctx) error {
 			failed = append(failed, svc.Name)
 			continue
 		}
+		if !sameNamespace(svc.Namespace, ns) {
+			failed = append(failed, svc.Name)
+			continue
+		}
+		if err := h.serviceStore.ValidateChecks(svc.Checks); err != nil {
+			failed = append(failed, svc.Name)
+			continue
+		}
+		svc.Namespace = ns
 
 		// Register the service
 		if h.raftNode != nil {
@@ -527,6 +615,11 @@ func (h *BatchHandler) BatchServiceDeregister(c *fiber.Ctx) error {
 		return middleware.BadRequest(c, "Maximum 100 services per batch request")
 	}
 
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+
 	log.Debug("Batch deregistering services", logger.Int("count", len(req.Names)))
 
 	deregistered := make([]string, 0, len(req.Names))
@@ -534,7 +627,8 @@ func (h *BatchHandler) BatchServiceDeregister(c *fiber.Ctx) error {
 	for _, name := range req.Names {
 		if h.raftNode != nil {
 			cmd, marshalErr := konsulraft.NewCommand(konsulraft.CmdServiceDeregister, konsulraft.ServiceDeregisterPayload{
-				Name: name,
+				Namespace: ns,
+				Name:      name,
 			})
 			if marshalErr != nil {
 				log.Error("Failed to build raft log entry", logger.Error(marshalErr))
@@ -553,7 +647,7 @@ func (h *BatchHandler) BatchServiceDeregister(c *fiber.Ctx) error {
 				return middleware.InternalError(c, "Failed to deregister services")
 			}
 		} else {
-			h.serviceStore.Deregister(name)
+			h.serviceStore.Deregister(store.NamespacedKey(ns, name))
 		}
 		deregistered = append(deregistered, name)
 	}
@@ -599,13 +693,18 @@ func (h *BatchHandler) BatchServiceGet(c *fiber.Ctx) error {
 		return middleware.BadRequest(c, "Maximum 100 services per batch request")
 	}
 
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+
 	log.Debug("Batch getting services", logger.Int("count", len(req.Names)))
 
 	found := make(map[string]store.Service)
 	notFound := make([]string, 0)
 
 	for _, name := range req.Names {
-		if svc, ok := h.serviceStore.Get(name); ok {
+		if svc, ok := h.serviceStore.Get(store.NamespacedKey(ns, name)); ok {
 			found[name] = svc
 		} else {
 			notFound = append(notFound, name)
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/neogan74/konsul/internal/blocking"
+)
+
+// IndexHeader carries the store index of the data returned by a read.
+// Passing it back as ?index= blocks the next read until the data changes.
+const IndexHeader = "X-Konsul-Index"
+
+// disconnectPollInterval is how often a blocking query checks whether the
+// client closed its connection.
+const disconnectPollInterval = time.Second
+
+// setIndexHeader sets IndexHeader on the response.
+func setIndexHeader(c *fiber.Ctx, index uint64) {
+	c.Set(IndexHeader, strconv.FormatUint(index, 10))
+}
+
+// blockOnIndex runs the blocking query with wait and sets IndexHeader to the
+// index it returns.
+func blockOnIndex(c *fiber.Ctx, query blocking.Query, wait func(context.Context, uint64, time.Duration) uint64) {
+	ctx, cancel := blockingContext(c, query)
+	defer cancel()
+	setIndexHeader(c, wait(ctx, query.Index, query.Wait))
+}
+
+// blockingContext returns the context a blocking query waits on. fasthttp
+// only cancels c.Context() when the server shuts down, so while the query
+// blocks the connection is checked every disconnectPollInterval and the
+// context is cancelled once the client has closed it. Callers must call the
+// returned cancel function.
+func blockingContext(c *fiber.Ctx, query blocking.Query) (context.Context, context.CancelFunc) {
+	if query.Index == 0 {
+		// Does not block
+		return c.Context(), func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	conn := c.Context().Conn()
+	serverDone := c.Context().Done()
+	go func() {
+		ticker := time.NewTicker(disconnectPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-serverDone:
+				cancel()
+				return
+			case <-ticker.C:
+				if connClosed(conn) {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+	return ctx, cancel
+}
@@ -28,6 +28,8 @@ func (h *ClusterHandler) RegisterRoutes(app *fiber.App) {
 	cluster.Post("/join", h.Join)
 	cluster.Delete("/leave/:id", h.Leave)
 	cluster.Post("/snapshot", h.Snapshot)
+	cluster.Get("/autopilot/health", h.AutopilotHealth)
+	cluster.Get("/autopilot/config", h.AutopilotConfig)
 }
 
 // checkRaftEnabled returns error response if Raft is not enabled.
@@ -214,3 +216,49 @@ func (h *ClusterHandler) Snapshot(c *fiber.Ctx) error {
 		"message": "Snapshot created successfully",
 	})
 }
+
+// AutopilotHealth returns the health of the cluster servers as seen by the
+// leader.
+// GET /cluster/autopilot/health
+func (h *ClusterHandler) AutopilotHealth(c *fiber.Ctx) error {
+	if !h.checkRaftEnabled(c) {
+		return nil
+	}
+
+	// Only the leader tracks the health of servers
+	if !h.raftNode.IsLeader() {
+		leaderAddr := h.raftNode.LeaderAddr()
+		return c.Status(fiber.StatusTemporaryRedirect).JSON(fiber.Map{
+			"error":       "not leader",
+			"message":     "This node is not the leader. Redirect to leader.",
+			"leader_addr": leaderAddr,
+		})
+	}
+
+	health, err := h.raftNode.AutopilotHealth()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(health)
+}
+
+// AutopilotConfig returns the autopilot configuration of this node.
+// GET /cluster/autopilot/config
+func (h *ClusterHandler) AutopilotConfig(c *fiber.Ctx) error {
+	if !h.checkRaftEnabled(c) {
+		return nil
+	}
+
+	cfg := h.raftNode.AutopilotConfig()
+	return c.JSON(fiber.Map{
+		"enabled":                   cfg.Enabled,
+		"cleanup_dead_servers":      cfg.CleanupDeadServers,
+		"last_contact_threshold":    cfg.LastContactThreshold.String(),
+		"max_trailing_logs":         cfg.MaxTrailingLogs,
+		"server_stabilization_time": cfg.ServerStabilizationTime.String(),
+		"dead_server_threshold":     cfg.DeadServerThreshold.String(),
+	})
+}
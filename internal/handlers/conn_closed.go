@@ -0,0 +1,43 @@
+//go:build linux || darwin || freebsd
+
+package handlers
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"syscall"
+)
+
+// connClosed returns true if the peer closed conn. It peeks at the socket
+// without consuming anything, so a request pipelined behind the current one
+// is left for the server to read.
+func connClosed(conn net.Conn) bool {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return false
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return true
+	}
+
+	closed := false
+	err = raw.Read(func(fd uintptr) bool {
+		var buf [1]byte
+		n, _, err := syscall.Recvfrom(int(fd), buf[:], syscall.MSG_PEEK|syscall.MSG_DONTWAIT)
+		switch {
+		case err == nil:
+			// Zero bytes means the peer sent its FIN
+			closed = n == 0
+		case errors.Is(err, syscall.EAGAIN), errors.Is(err, syscall.EINTR):
+		default:
+			closed = true
+		}
+		return true
+	})
+	return closed || err != nil
+}
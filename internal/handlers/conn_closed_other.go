@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !freebsd
+
+package handlers
+
+import "net"
+
+// connClosed cannot tell whether the peer closed conn on this platform;
+// blocking queries then wait until they time out.
+func connClosed(net.Conn) bool {
+	return false
+}
@@ -0,0 +1,98 @@
+//go:build linux || darwin || freebsd
+
+package handlers
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/neogan74/konsul/internal/blocking"
+)
+
+func TestBlockingContext_CancelledOnDisconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/watch", func(c *fiber.Ctx) error {
+		blockOnIndex(c, blocking.Query{Index: 1, Wait: time.Minute}, func(ctx context.Context, index uint64, wait time.Duration) uint64 {
+			close(started)
+			<-ctx.Done()
+			close(cancelled)
+			return index
+		})
+		return nil
+	})
+	go func() { _ = app.Listener(ln) }()
+	defer func() { _ = app.Shutdown() }()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	if _, err := conn.Write([]byte("GET /watch?index=1 HTTP/1.1\r\nHost: localhost\r\n\r\n")); err != nil {
+		t.Fatalf("failed to write request: %v", err)
+	}
+	select {
+	case <-started:
+	case <-time.After(3 * time.Second):
+		t.Fatal("blocking query never started")
+	}
+
+	// The query must not stay blocked once the client goes away
+	_ = conn.Close()
+	select {
+	case <-cancelled:
+	case <-time.After(3 * disconnectPollInterval):
+		t.Fatal("blocking query was not cancelled after the client disconnected")
+	}
+}
+
+func TestBlockingContext_OpenConnectionKeepsWaiting(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/watch", func(c *fiber.Ctx) error {
+		blockOnIndex(c, blocking.Query{Index: 1, Wait: time.Minute}, func(ctx context.Context, index uint64, wait time.Duration) uint64 {
+			select {
+			case <-ctx.Done():
+				return 0
+			case <-time.After(2 * disconnectPollInterval):
+				return index + 1
+			}
+		})
+		return c.SendStatus(fiber.StatusOK)
+	})
+	go func() { _ = app.Listener(ln) }()
+	defer func() { _ = app.Shutdown() }()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("GET /watch?index=1 HTTP/1.1\r\nHost: localhost\r\n\r\n")); err != nil {
+		t.Fatalf("failed to write request: %v", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 512)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+	if want := IndexHeader + ": 2"; !strings.Contains(string(buf[:n]), want) {
+		t.Errorf("expected %q in response, got %q", want, buf[:n])
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	hashiraft "github.com/hashicorp/raft"
+	"github.com/neogan74/konsul/internal/blocking"
 	"github.com/neogan74/konsul/internal/healthcheck"
 	"github.com/neogan74/konsul/internal/logger"
 	"github.com/neogan74/konsul/internal/middleware"
@@ -15,6 +16,7 @@ import (
 
 type HealthCheckHandler struct {
 	serviceStore *store.ServiceStore
+	namespaces   *store.NamespaceStore
 	raftNode     *konsulraft.Node
 }
 
@@ -25,11 +27,29 @@ func NewHealthCheckHandler(serviceStore *store.ServiceStore, raftNode *konsulraf
 	}
 }
 
+// WithNamespaces scopes check listings to the namespace of the request.
+// Without a registry only the default namespace is available.
+func (h *HealthCheckHandler) WithNamespaces(namespaces *store.NamespaceStore) *HealthCheckHandler {
+	h.namespaces = namespaces
+	return h
+}
+
 func (h *HealthCheckHandler) ListChecks(c *fiber.Ctx) error {
 	log := middleware.GetLogger(c)
 	log.Debug("Listing all health checks")
 
-	checks := h.serviceStore.GetAllHealthChecks()
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+
+	query, err := blocking.ParseQuery(c.Query("index"), c.Query("wait"))
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+	blockOnIndex(c, query, h.serviceStore.WaitForHealthIndex)
+
+	checks := checksInNamespace(h.serviceStore.GetAllHealthChecks(), ns)
 
 	log.Info("Health checks listed successfully", logger.Int("count", len(checks)))
 	return c.JSON(checks)
@@ -41,7 +61,18 @@ func (h *HealthCheckHandler) GetServiceChecks(c *fiber.Ctx) error {
 
 	log.Debug("Getting health checks for service", logger.String("service", serviceName))
 
-	checks := h.serviceStore.GetHealthChecks(serviceName)
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+
+	query, err := blocking.ParseQuery(c.Query("index"), c.Query("wait"))
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+	blockOnIndex(c, query, h.serviceStore.WaitForHealthIndex)
+
+	checks := h.serviceStore.GetHealthChecks(store.NamespacedKey(ns, serviceName))
 
 	log.Info("Service health checks retrieved",
 		logger.String("service", serviceName),
@@ -50,6 +81,36 @@ func (h *HealthCheckHandler) GetServiceChecks(c *fiber.Ctx) error {
 	return c.JSON(checks)
 }
 
+// GetCheckHistory returns the recent results of a health check, oldest
+// first.
+func (h *HealthCheckHandler) GetCheckHistory(c *fiber.Ctx) error {
+	checkID := c.Params("id")
+	log := middleware.GetLogger(c)
+
+	history, ok := h.serviceStore.CheckHistory(checkID)
+	if !ok {
+		return middleware.NotFound(c, "Health check not found")
+	}
+
+	log.Debug("Health check history retrieved",
+		logger.String("check_id", checkID),
+		logger.Int("count", len(history)))
+
+	return c.JSON(fiber.Map{"check_id": checkID, "history": history})
+}
+
+// checksInNamespace returns the checks of services in namespace ns.
+func checksInNamespace(checks []*healthcheck.Check, ns string) []*healthcheck.Check {
+	ns = store.NormalizeNamespace(ns)
+	result := make([]*healthcheck.Check, 0, len(checks))
+	for _, check := range checks {
+		if checkNS, _ := store.SplitNamespacedKey(check.ServiceID); checkNS == ns {
+			result = append(result, check)
+		}
+	}
+	return result
+}
+
 func (h *HealthCheckHandler) UpdateTTLCheck(c *fiber.Ctx) error {
 	checkID := c.Params("id")
 	log := middleware.GetLogger(c)
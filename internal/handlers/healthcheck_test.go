@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/neogan74/konsul/internal/healthcheck"
+	"github.com/neogan74/konsul/internal/store"
+)
+
+func TestHealthCheckHandler_GetCheckHistory(t *testing.T) {
+	app := fiber.New()
+
+	serviceStore := store.NewServiceStore()
+	defer func() { _ = serviceStore.Close() }()
+	if err := serviceStore.Register(store.Service{
+		Name:    "web",
+		Address: "127.0.0.1",
+		Port:    8080,
+		Checks:  []*healthcheck.CheckDefinition{{ID: "web-ttl", TTL: "60s"}},
+	}); err != nil {
+		t.Fatalf("register service: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := serviceStore.UpdateTTLCheck("web-ttl"); err != nil {
+			t.Fatalf("update TTL check: %v", err)
+		}
+	}
+
+	handler := NewHealthCheckHandler(serviceStore, nil)
+	app.Get("/health/check/:id/history", handler.GetCheckHistory)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/health/check/web-ttl/history", nil), -1)
+	if err != nil {
+		t.Fatalf("Failed to perform request: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	var body struct {
+		CheckID string                     `json:"check_id"`
+		History []healthcheck.HistoryEntry `json:"history"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.CheckID != "web-ttl" || len(body.History) != 2 {
+		t.Fatalf("expected 2 results for web-ttl, got %+v", body)
+	}
+	if body.History[0].Status != healthcheck.StatusPassing {
+		t.Errorf("expected passing results, got %s", body.History[0].Status)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/health/check/missing/history", nil), -1)
+	if err != nil {
+		t.Fatalf("Failed to perform request: %v", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.StatusCode)
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/neogan74/konsul/internal/acl"
+	"github.com/neogan74/konsul/internal/blocking"
 	"github.com/neogan74/konsul/internal/logger"
 	"github.com/neogan74/konsul/internal/metrics"
 	"github.com/neogan74/konsul/internal/middleware"
@@ -14,8 +17,11 @@ import (
 )
 
 type KVHandler struct {
-	store    *store.KVStore
-	raftNode *konsulraft.Node
+	store      *store.KVStore
+	sessions   *store.SessionStore
+	namespaces *store.NamespaceStore
+	aclEval    *acl.Evaluator
+	raftNode   *konsulraft.Node
 }
 
 func NewKVHandler(kvStore *store.KVStore, raftNode *konsulraft.Node) *KVHandler {
@@ -30,36 +36,43 @@ func NewKVHandlerWithRaft(kvStore *store.KVStore, raftNode *konsulraft.Node) *KV
 	}
 }
 
-// isRaftEnabled returns true if Raft clustering is enabled.
-func (h *KVHandler) isRaftEnabled() bool {
-	return h.raftNode != nil
+// WithSessions enables the acquire and release query parameters on Set.
+func (h *KVHandler) WithSessions(sessions *store.SessionStore) *KVHandler {
+	h.sessions = sessions
+	return h
 }
 
-// checkLeaderForWrite checks if this node can handle writes.
-// Returns nil if writes are allowed, or an error response if not leader.
-func (h *KVHandler) checkLeaderForWrite(c *fiber.Ctx) error {
-	if !h.isRaftEnabled() {
-		return nil // Standalone mode, writes allowed
-	}
+// WithNamespaces enables the ns query parameter and X-Konsul-Namespace header.
+// Without a registry only the default namespace is available.
+func (h *KVHandler) WithNamespaces(namespaces *store.NamespaceStore) *KVHandler {
+	h.namespaces = namespaces
+	return h
+}
 
-	if h.raftNode.IsLeader() {
-		return nil // Leader, writes allowed
-	}
+// WithACL requires the decrypt capability to read the plaintext of secret
+// values. Without an evaluator ACLs are disabled and any reader may decrypt.
+func (h *KVHandler) WithACL(evaluator *acl.Evaluator) *KVHandler {
+	h.aclEval = evaluator
+	return h
+}
 
-	// Not leader, return redirect response
-	leaderAddr := h.raftNode.LeaderAddr()
-	if leaderAddr == "" {
-		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
-			"error":   "no leader",
-			"message": "No leader is currently elected. The cluster may be initializing or partitioned.",
-		})
+// canDecrypt reports whether the request may read the plaintext of the
+// secret key.
+func (h *KVHandler) canDecrypt(c *fiber.Ctx, key string) bool {
+	if h.aclEval == nil {
+		return true
 	}
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		return false
+	}
+	resource := acl.NewKVResource(key).InNamespace(middleware.GetNamespace(c))
+	return h.aclEval.Evaluate(claims.Policies, resource, acl.CapabilityDecrypt)
+}
 
-	return c.Status(fiber.StatusTemporaryRedirect).JSON(fiber.Map{
-		"error":       "not leader",
-		"message":     "This node is not the leader. Redirect to leader for write operations.",
-		"leader_addr": leaderAddr,
-	})
+// isRaftEnabled returns true if Raft clustering is enabled.
+func (h *KVHandler) isRaftEnabled() bool {
+	return h.raftNode != nil
 }
 
 // getKey extracts the KV path, supporting nested keys via wildcard routes.
@@ -75,7 +88,18 @@ func (h *KVHandler) Get(c *fiber.Ctx) error {
 	key := getKey(c)
 	log := middleware.GetLogger(c)
 
-	log.Debug("Getting key", logger.String("key", key))
+	storageKey, err := resolveKey(c, h.namespaces, key)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+
+	log.Debug("Getting key", logger.String("key", storageKey))
+
+	query, err := blocking.ParseQuery(c.Query("index"), c.Query("wait"))
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+	blockOnIndex(c, query, h.store.WaitForIndex)
 
 	if c.Query("consistent") == "true" && h.raftNode != nil {
 		if err := h.raftNode.EnsureLinearizableRead(5 * time.Second); err != nil {
@@ -86,37 +110,64 @@ func (h *KVHandler) Get(c *fiber.Ctx) error {
 		}
 	}
 
+	entry, ok := h.store.GetEntry(storageKey)
+	if !ok {
+		log.Warn("Key not found", logger.String("key", storageKey))
+		metrics.KVOperationsTotal.WithLabelValues("get", "not_found").Inc()
+		return middleware.NotFound(c, "Key not found")
+	}
+
+	// Secret values are redacted unless the client asks for the plaintext
+	// and holds the decrypt capability
+	value := entry.Redacted().Value
+	if entry.Secret && c.Query("decrypt") == "true" {
+		if !h.canDecrypt(c, key) {
+			metrics.KVOperationsTotal.WithLabelValues("get", "forbidden").Inc()
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":      "forbidden",
+				"message":    "insufficient permissions to decrypt this key",
+				"capability": string(acl.CapabilityDecrypt),
+			})
+		}
+		value, err = h.store.Reveal(storageKey, entry)
+		if err != nil {
+			log.Error("Failed to decrypt secret", logger.String("key", key), logger.Error(err))
+			metrics.KVOperationsTotal.WithLabelValues("get", "error").Inc()
+			return middleware.InternalError(c, "Failed to decrypt secret")
+		}
+	}
+
 	// Check if client wants full entry with indices
 	includeMetadata := c.Query("metadata", "false") == "true"
 
 	if includeMetadata {
-		entry, ok := h.store.GetEntry(key)
-		if !ok {
-			log.Warn("Key not found", logger.String("key", key))
-			metrics.KVOperationsTotal.WithLabelValues("get", "not_found").Inc()
-			return middleware.NotFound(c, "Key not found")
-		}
 		log.Info("Key retrieved successfully with metadata", logger.String("key", key))
 		metrics.KVOperationsTotal.WithLabelValues("get", "success").Inc()
-		return c.JSON(fiber.Map{
+		response := fiber.Map{
 			"key":          key,
-			"value":        entry.Value,
+			"value":        value,
 			"modify_index": entry.ModifyIndex,
 			"create_index": entry.CreateIndex,
 			"flags":        entry.Flags,
-		})
-	}
-
-	value, ok := h.store.Get(key)
-	if !ok {
-		log.Warn("Key not found", logger.String("key", key))
-		metrics.KVOperationsTotal.WithLabelValues("get", "not_found").Inc()
-		return middleware.NotFound(c, "Key not found")
+			"session":      entry.Session,
+			"lock_index":   entry.LockIndex,
+		}
+		if !entry.ExpiresAt.IsZero() {
+			response["expires_at"] = entry.ExpiresAt
+		}
+		if entry.Secret {
+			response["secret"] = true
+		}
+		return c.JSON(response)
 	}
 
 	log.Info("Key retrieved successfully", logger.String("key", key))
 	metrics.KVOperationsTotal.WithLabelValues("get", "success").Inc()
-	return c.JSON(fiber.Map{"key": key, "value": value})
+	response := fiber.Map{"key": key, "value": value}
+	if entry.Secret {
+		response["secret"] = true
+	}
+	return c.JSON(response)
 }
 
 func (h *KVHandler) Set(c *fiber.Ctx) error {
@@ -124,14 +175,23 @@ func (h *KVHandler) Set(c *fiber.Ctx) error {
 	log := middleware.GetLogger(c)
 
 	// Check if this node can handle writes (leader check for Raft mode)
-	if err := h.checkLeaderForWrite(c); err != nil {
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
 		return err
 	}
 
+	storageKey, err := resolveKey(c, h.namespaces, key)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+
 	body := struct {
 		Value string  `json:"value"`
 		CAS   *uint64 `json:"cas,omitempty"` // Optional CAS index
 		Flags uint64  `json:"flags,omitempty"`
+		TTL   string  `json:"ttl,omitempty"` // Optional TTL, e.g. "30s"; the key is deleted once it elapses
+		// Secret stores the value encrypted with the transit key. Readers
+		// get it redacted unless they hold the decrypt capability.
+		Secret bool `json:"secret,omitempty"`
 	}{}
 
 	if err := c.BodyParser(&body); err != nil {
@@ -145,20 +205,53 @@ func (h *KVHandler) Set(c *fiber.Ctx) error {
 		logger.String("key", key),
 		logger.String("value_length", fmt.Sprintf("%d", len(body.Value))))
 
+	acquire, release := c.Query("acquire"), c.Query("release")
+	if body.Secret {
+		if acquire != "" || release != "" {
+			return middleware.BadRequest(c, "Secret values cannot be combined with lock operations")
+		}
+		if !h.store.SecretsEnabled() {
+			return middleware.BadRequest(c, store.ErrSecretsDisabled.Error())
+		}
+	}
+
+	// Lock operations: ?acquire=<session> or ?release=<session>
+	if session := acquire; session != "" {
+		return h.lock(c, key, storageKey, body.Value, session, true)
+	}
+	if session := release; session != "" {
+		return h.lock(c, key, storageKey, body.Value, session, false)
+	}
+
+	ttl, err := store.ParseKVTTL(body.TTL)
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+
+	if body.Secret {
+		return h.setSecret(c, key, storageKey, body.Value, body.Flags, body.CAS, ttl)
+	}
+
 	// Use CAS if provided (CAS operations are not replicated via Raft in this implementation)
 	if body.CAS != nil {
 		var newIndex uint64
 		var err error
 		if h.raftNode != nil {
-			newIndex, err = h.raftNode.KVSetCAS(key, body.Value, *body.CAS)
+			if ttl > 0 {
+				newIndex, err = h.raftNode.KVSetCASWithTTL(storageKey, body.Value, *body.CAS, ttl)
+			} else {
+				newIndex, err = h.raftNode.KVSetCAS(storageKey, body.Value, *body.CAS)
+			}
 			if errors.Is(err, konsulraft.ErrNotLeader) {
 				return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
 					"error":  "not leader",
 					"leader": h.raftNode.Leader(),
 				})
 			}
+		} else if ttl > 0 {
+			newIndex, err = h.store.SetCASWithTTL(storageKey, body.Value, *body.CAS, ttl)
 		} else {
-			newIndex, err = h.store.SetCAS(key, body.Value, *body.CAS)
+			newIndex, err = h.store.SetCAS(storageKey, body.Value, *body.CAS)
 		}
 		if err != nil {
 			if store.IsCASConflict(err) {
@@ -191,10 +284,13 @@ func (h *KVHandler) Set(c *fiber.Ctx) error {
 	// Use Raft for replicated writes if enabled
 	if h.isRaftEnabled() {
 		var err error
-		if body.Flags > 0 {
-			err = h.raftNode.KVSetWithFlags(key, body.Value, body.Flags)
-		} else {
-			err = h.raftNode.KVSet(key, body.Value)
+		switch {
+		case ttl > 0:
+			err = h.raftNode.KVSetWithTTL(storageKey, body.Value, body.Flags, ttl)
+		case body.Flags > 0:
+			err = h.raftNode.KVSetWithFlags(storageKey, body.Value, body.Flags)
+		default:
+			err = h.raftNode.KVSet(storageKey, body.Value)
 		}
 		if err != nil {
 			log.Error("Raft KV set failed", logger.String("key", key), logger.Error(err))
@@ -206,10 +302,13 @@ func (h *KVHandler) Set(c *fiber.Ctx) error {
 		}
 	} else {
 		// Standalone mode: direct store write
-		if body.Flags > 0 {
-			h.store.SetWithFlags(key, body.Value, body.Flags)
-		} else {
-			h.store.Set(key, body.Value)
+		switch {
+		case ttl > 0:
+			h.store.SetWithTTL(storageKey, body.Value, body.Flags, ttl)
+		case body.Flags > 0:
+			h.store.SetWithFlags(storageKey, body.Value, body.Flags)
+		default:
+			h.store.Set(storageKey, body.Value)
 		}
 	}
 
@@ -218,11 +317,149 @@ func (h *KVHandler) Set(c *fiber.Ctx) error {
 	metrics.KVStoreSize.Set(float64(len(h.store.List())))
 
 	// Return the new index
-	entry, _ := h.store.GetEntry(key)
-	return c.JSON(fiber.Map{
+	entry, _ := h.store.GetEntry(storageKey)
+	response := fiber.Map{
 		"message":      "key set",
 		"key":          key,
 		"modify_index": entry.ModifyIndex,
+	}
+	if !entry.ExpiresAt.IsZero() {
+		response["expires_at"] = entry.ExpiresAt
+	}
+	return c.JSON(response)
+}
+
+// setSecret stores a secret value. With Raft the leader seals the value and
+// replicates only the sealed form, so the plaintext never enters the log.
+func (h *KVHandler) setSecret(c *fiber.Ctx, key, storageKey, value string, flags uint64, cas *uint64, ttl time.Duration) error {
+	log := middleware.GetLogger(c)
+
+	var newIndex uint64
+	var err error
+	switch {
+	case h.isRaftEnabled():
+		var sealed string
+		sealed, err = h.store.SealSecret(storageKey, value)
+		if err != nil {
+			break
+		}
+		if cas != nil {
+			newIndex, err = h.raftNode.KVSetSecretCAS(storageKey, sealed, *cas, ttl)
+		} else {
+			err = h.raftNode.KVSetSecret(storageKey, sealed, flags, ttl)
+		}
+		if errors.Is(err, konsulraft.ErrNotLeader) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error":  "not leader",
+				"leader": h.raftNode.Leader(),
+			})
+		}
+	case cas != nil:
+		newIndex, err = h.store.SetSecretCAS(storageKey, value, *cas, ttl)
+	default:
+		err = h.store.SetSecret(storageKey, value, flags, ttl)
+	}
+	if err != nil {
+		if store.IsCASConflict(err) {
+			log.Warn("CAS conflict", logger.String("key", key), logger.Error(err))
+			metrics.KVOperationsTotal.WithLabelValues("set", "cas_conflict").Inc()
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":   "CAS conflict",
+				"message": err.Error(),
+			})
+		}
+		if store.IsNotFound(err) {
+			log.Warn("Key not found for CAS update", logger.String("key", key))
+			metrics.KVOperationsTotal.WithLabelValues("set", "not_found").Inc()
+			return middleware.NotFound(c, "Key not found")
+		}
+		log.Error("Failed to set secret", logger.String("key", key), logger.Error(err))
+		metrics.KVOperationsTotal.WithLabelValues("set", "error").Inc()
+		return middleware.InternalError(c, "Failed to set key")
+	}
+
+	log.Info("Secret set successfully", logger.String("key", key))
+	metrics.KVOperationsTotal.WithLabelValues("set", "success").Inc()
+	metrics.KVStoreSize.Set(float64(len(h.store.List())))
+
+	entry, _ := h.store.GetEntry(storageKey)
+	if cas == nil {
+		newIndex = entry.ModifyIndex
+	}
+	response := fiber.Map{
+		"message":      "key set",
+		"key":          key,
+		"modify_index": newIndex,
+		"secret":       true,
+	}
+	if !entry.ExpiresAt.IsZero() {
+		response["expires_at"] = entry.ExpiresAt
+	}
+	return c.JSON(response)
+}
+
+// lock acquires or releases the lock on key, stored under storageKey, for a
+// session. A refused lock is not an error: the response reports it with
+// "result": false.
+func (h *KVHandler) lock(c *fiber.Ctx, key, storageKey, value, session string, acquire bool) error {
+	log := middleware.GetLogger(c)
+	operation := "release"
+	if acquire {
+		operation = "acquire"
+	}
+
+	if h.sessions == nil {
+		return middleware.BadRequest(c, "Sessions are not enabled")
+	}
+
+	// The key outlives the request once a lock is recorded, so detach it
+	// from fiber's reused request buffer.
+	storageKey = utils.CopyString(storageKey)
+
+	var ok bool
+	var err error
+	switch {
+	case h.isRaftEnabled() && acquire:
+		ok, err = h.raftNode.KVAcquire(storageKey, value, session)
+	case h.isRaftEnabled():
+		ok, err = h.raftNode.KVRelease(storageKey, session)
+	case acquire:
+		ok, err = h.sessions.Acquire(storageKey, value, session)
+	default:
+		ok, err = h.sessions.Release(storageKey, session)
+	}
+	if err != nil {
+		if store.IsNotFound(err) {
+			metrics.SessionOperationsTotal.WithLabelValues(operation, "not_found").Inc()
+			return middleware.NotFound(c, "Session not found")
+		}
+		log.Error("Lock operation failed",
+			logger.String("key", key),
+			logger.String("session", session),
+			logger.String("operation", operation),
+			logger.Error(err))
+		metrics.SessionOperationsTotal.WithLabelValues(operation, "error").Inc()
+		return middleware.InternalError(c, "Failed to "+operation+" lock")
+	}
+
+	status := "success"
+	if !ok {
+		status = "refused"
+	}
+	log.Info("Lock operation completed",
+		logger.String("key", key),
+		logger.String("session", session),
+		logger.String("operation", operation),
+		logger.String("status", status))
+	metrics.SessionOperationsTotal.WithLabelValues(operation, status).Inc()
+
+	entry, _ := h.store.GetEntry(storageKey)
+	return c.JSON(fiber.Map{
+		"key":          key,
+		"result":       ok,
+		"session":      entry.Session,
+		"lock_index":   entry.LockIndex,
+		"modify_index": entry.ModifyIndex,
 	})
 }
 
@@ -231,11 +468,16 @@ func (h *KVHandler) Delete(c *fiber.Ctx) error {
 	log := middleware.GetLogger(c)
 
 	// Check if this node can handle writes (leader check for Raft mode)
-	if err := h.checkLeaderForWrite(c); err != nil {
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
 		return err
 	}
 
-	log.Debug("Deleting key", logger.String("key", key))
+	storageKey, err := resolveKey(c, h.namespaces, key)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+
+	log.Debug("Deleting key", logger.String("key", storageKey))
 
 	// Check if CAS is requested via query parameter (CAS operations not replicated via Raft)
 	casParam := c.Query("cas")
@@ -248,7 +490,7 @@ func (h *KVHandler) Delete(c *fiber.Ctx) error {
 
 		var err error
 		if h.raftNode != nil {
-			err = h.raftNode.KVDeleteCAS(key, expectedIndex)
+			err = h.raftNode.KVDeleteCAS(storageKey, expectedIndex)
 			if errors.Is(err, konsulraft.ErrNotLeader) {
 				return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
 					"error":  "not leader",
@@ -256,7 +498,7 @@ func (h *KVHandler) Delete(c *fiber.Ctx) error {
 				})
 			}
 		} else {
-			err = h.store.DeleteCAS(key, expectedIndex)
+			err = h.store.DeleteCAS(storageKey, expectedIndex)
 		}
 		if err != nil {
 			if store.IsCASConflict(err) {
@@ -285,7 +527,7 @@ func (h *KVHandler) Delete(c *fiber.Ctx) error {
 
 	// Use Raft for replicated deletes if enabled
 	if h.isRaftEnabled() {
-		if err := h.raftNode.KVDelete(key); err != nil {
+		if err := h.raftNode.KVDelete(storageKey); err != nil {
 			log.Error("Raft KV delete failed", logger.String("key", key), logger.Error(err))
 			metrics.KVOperationsTotal.WithLabelValues("delete", "error").Inc()
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -295,7 +537,7 @@ func (h *KVHandler) Delete(c *fiber.Ctx) error {
 		}
 	} else {
 		// Standalone mode: direct store delete
-		h.store.Delete(key)
+		h.store.Delete(storageKey)
 	}
 
 	log.Info("Key deleted successfully", logger.String("key", key))
@@ -307,7 +549,18 @@ func (h *KVHandler) Delete(c *fiber.Ctx) error {
 func (h *KVHandler) List(c *fiber.Ctx) error {
 	log := middleware.GetLogger(c)
 
-	log.Debug("Listing all keys")
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+
+	log.Debug("Listing all keys", logger.String("namespace", store.NormalizeNamespace(ns)))
+
+	query, err := blocking.ParseQuery(c.Query("index"), c.Query("wait"))
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+	blockOnIndex(c, query, h.store.WaitForIndex)
 
 	if c.Query("consistent") == "true" && h.raftNode != nil {
 		if err := h.raftNode.EnsureLinearizableRead(5 * time.Second); err != nil {
@@ -318,7 +571,7 @@ func (h *KVHandler) List(c *fiber.Ctx) error {
 		}
 	}
 
-	keys := h.store.List()
+	keys := h.store.ListNamespace(ns)
 
 	log.Info("Keys listed successfully", logger.Int("count", len(keys)))
 	metrics.KVOperationsTotal.WithLabelValues("list", "success").Inc()
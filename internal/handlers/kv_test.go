@@ -5,9 +5,15 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/neogan74/konsul/internal/acl"
+	"github.com/neogan74/konsul/internal/auth"
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/secrets"
 	"github.com/neogan74/konsul/internal/store"
 )
 
@@ -94,6 +100,147 @@ func TestKVHandler_Set(t *testing.T) {
 	}
 }
 
+func TestKVHandler_SetWithTTL(t *testing.T) {
+	handler, app := setupKVHandler()
+
+	body := bytes.NewReader([]byte(`{"value": "on", "ttl": "30s"}`))
+	req := httptest.NewRequest(http.MethodPut, "/kv/feature-override", body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("PUT request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 for PUT with TTL, got %d", resp.StatusCode)
+	}
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if _, ok := result["expires_at"]; !ok {
+		t.Errorf("expected expires_at in response: %+v", result)
+	}
+
+	entry, ok := handler.store.GetEntry("feature-override")
+	if !ok || entry.ExpiresAt.IsZero() {
+		t.Errorf("expected key to be stored with an expiry, got %+v", entry)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/kv/feature-override?metadata=true", nil)
+	resp, _ = app.Test(req)
+	result = nil
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if _, ok := result["expires_at"]; !ok {
+		t.Errorf("expected expires_at in metadata: %+v", result)
+	}
+
+	// Invalid TTLs are rejected
+	for _, payload := range []string{`{"value": "x", "ttl": "soon"}`, `{"value": "x", "ttl": "100ms"}`} {
+		req = httptest.NewRequest(http.MethodPut, "/kv/bad-ttl", bytes.NewReader([]byte(payload)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ = app.Test(req)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected 400 for %s, got %d", payload, resp.StatusCode)
+		}
+	}
+}
+
+func TestKVHandler_Secrets(t *testing.T) {
+	handler, app := setupKVHandler()
+
+	put := func(payload string) *http.Response {
+		req := httptest.NewRequest(http.MethodPut, "/kv/db-password", bytes.NewReader([]byte(payload)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("PUT request failed: %v", err)
+		}
+		return resp
+	}
+	get := func(path string) (int, map[string]interface{}) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		if err != nil {
+			t.Fatalf("GET request failed: %v", err)
+		}
+		var result map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, result
+	}
+
+	// Secrets need a transit key
+	if resp := put(`{"value": "hunter2", "secret": true}`); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 without a transit key, got %d", resp.StatusCode)
+	}
+
+	transit, err := secrets.NewTransit(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("failed to create transit: %v", err)
+	}
+	handler.store.SetTransit(transit)
+	if resp := put(`{"value": "hunter2", "secret": true}`); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 for secret PUT, got %d", resp.StatusCode)
+	}
+
+	status, result := get("/kv/db-password")
+	if status != http.StatusOK || result["value"] != "" || result["secret"] != true {
+		t.Errorf("expected redacted secret, got %d %+v", status, result)
+	}
+	_, result = get("/kv/db-password?metadata=true")
+	if result["value"] != "" || result["secret"] != true {
+		t.Errorf("expected redacted secret in metadata, got %+v", result)
+	}
+
+	// Without ACLs any reader may decrypt
+	status, result = get("/kv/db-password?decrypt=true")
+	if status != http.StatusOK || result["value"] != "hunter2" {
+		t.Errorf("expected plaintext, got %d %+v", status, result)
+	}
+
+	// With ACLs decrypting needs the decrypt capability
+	evaluator := acl.NewEvaluator(logger.GetDefault())
+	for name, caps := range map[string][]acl.Capability{
+		"reader":    {acl.CapabilityRead},
+		"decrypter": {acl.CapabilityRead, acl.CapabilityDecrypt},
+	} {
+		if err := evaluator.AddPolicy(&acl.Policy{
+			Name: name,
+			KV:   []acl.KVRule{{Path: "db-*", Capabilities: caps}},
+		}); err != nil {
+			t.Fatalf("failed to add policy: %v", err)
+		}
+	}
+	handler.WithACL(evaluator)
+
+	for policy, want := range map[string]int{"reader": http.StatusForbidden, "decrypter": http.StatusOK} {
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("claims", &auth.Claims{Policies: []string{policy}})
+			return c.Next()
+		})
+		app.Get("/kv/:key", handler.Get)
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/kv/db-password?decrypt=true", nil))
+		if err != nil {
+			t.Fatalf("GET request failed: %v", err)
+		}
+		if resp.StatusCode != want {
+			t.Errorf("expected %d for policy %s, got %d", want, policy, resp.StatusCode)
+		}
+	}
+	if status, _ := get("/kv/db-password?decrypt=true"); status != http.StatusForbidden {
+		t.Errorf("expected 403 without claims, got %d", status)
+	}
+
+	// Secrets cannot hold locks
+	req := httptest.NewRequest(http.MethodPut, "/kv/db-password?acquire=abc", bytes.NewReader([]byte(`{"value": "x", "secret": true}`)))
+	req.Header.Set("Content-Type", "application/json")
+	if resp, _ := app.Test(req); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for secret lock, got %d", resp.StatusCode)
+	}
+}
+
 func TestKVHandler_Delete(t *testing.T) {
 	handler, app := setupKVHandler()
 
@@ -125,6 +272,108 @@ func TestKVHandler_Delete(t *testing.T) {
 	}
 }
 
+func TestKVHandler_BlockingGet(t *testing.T) {
+	handler, app := setupKVHandler()
+	handler.store.Set("config", "v1")
+
+	req := httptest.NewRequest(http.MethodGet, "/kv/config", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("GET request failed: %v", err)
+	}
+	index := resp.Header.Get(IndexHeader)
+	if index != strconv.FormatUint(handler.store.Index(), 10) {
+		t.Fatalf("expected %s header to carry the store index, got %q", IndexHeader, index)
+	}
+
+	// A blocking read returns as soon as the key changes
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		handler.store.Set("config", "v2")
+	}()
+	start := time.Now()
+	req = httptest.NewRequest(http.MethodGet, "/kv/config?index="+index+"&wait=5s", nil)
+	resp, err = app.Test(req, 10000)
+	if err != nil {
+		t.Fatalf("blocking GET request failed: %v", err)
+	}
+	if time.Since(start) > 4*time.Second {
+		t.Error("expected blocking read to return on change, not at the wait timeout")
+	}
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result["value"] != "v2" {
+		t.Errorf("expected updated value v2, got %v", result["value"])
+	}
+	if resp.Header.Get(IndexHeader) == index {
+		t.Error("expected index to advance")
+	}
+
+	// Deletes advance the index as well
+	index = resp.Header.Get(IndexHeader)
+	handler.store.Delete("config")
+	req = httptest.NewRequest(http.MethodGet, "/kv/config?index="+index+"&wait=5s", nil)
+	resp, _ = app.Test(req, 10000)
+	if resp.StatusCode != http.StatusNotFound || resp.Header.Get(IndexHeader) == index {
+		t.Errorf("expected 404 with a new index, got %d and %s", resp.StatusCode, resp.Header.Get(IndexHeader))
+	}
+
+	// A wait without changes times out and returns the same index
+	index = resp.Header.Get(IndexHeader)
+	req = httptest.NewRequest(http.MethodGet, "/kv/config?index="+index+"&wait=100ms", nil)
+	resp, _ = app.Test(req, 10000)
+	if resp.Header.Get(IndexHeader) != index {
+		t.Errorf("expected unchanged index %s, got %s", index, resp.Header.Get(IndexHeader))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/kv/config?index=abc", nil)
+	if resp, _ := app.Test(req); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid index, got %d", resp.StatusCode)
+	}
+}
+
+func TestKVHandler_Namespaces(t *testing.T) {
+	handler, app := setupKVHandler()
+	namespaces := store.NewNamespaceStore(handler.store, nil)
+	handler.WithNamespaces(namespaces)
+	if _, err := namespaces.Create(store.Namespace{Name: "team-a"}); err != nil {
+		t.Fatalf("failed to create namespace: %v", err)
+	}
+
+	// Unknown namespaces are rejected
+	req := httptest.NewRequest(http.MethodGet, "/kv/config?ns=team-b", nil)
+	if resp, _ := app.Test(req); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown namespace, got %d", resp.StatusCode)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/kv/config", bytes.NewReader([]byte(`{"value": "a"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Konsul-Namespace", "team-a")
+	if resp, _ := app.Test(req); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 for namespaced PUT, got %d", resp.StatusCode)
+	}
+
+	// The key is only visible in its own namespace
+	req = httptest.NewRequest(http.MethodGet, "/kv/config", nil)
+	if resp, _ := app.Test(req); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 in default namespace, got %d", resp.StatusCode)
+	}
+	req = httptest.NewRequest(http.MethodGet, "/kv/config?ns=team-a", nil)
+	resp, err := app.Test(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 in team-a, got %v %v", resp, err)
+	}
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result["key"] != "config" || result["value"] != "a" {
+		t.Errorf("unexpected response: %+v", result)
+	}
+}
+
 func TestKVHandler_NewKVHandler(t *testing.T) {
 	kvStore := store.NewKVStore()
 	handler := NewKVHandler(kvStore, nil)
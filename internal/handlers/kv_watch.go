@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,6 +26,11 @@ type KVWatchHandler struct {
 	log          logger.Logger
 }
 
+// LastEventIDHeader carries the id of the last event an SSE client got when
+// it reconnects. Event ids are store indexes, so it resumes the watch like
+// the since query parameter.
+const LastEventIDHeader = "Last-Event-ID"
+
 // NewKVWatchHandler creates a new KV watch handler
 func NewKVWatchHandler(kvStore *store.KVStore, watchManager *watch.Manager, aclEval *acl.Evaluator, log logger.Logger) *KVWatchHandler {
 	return &KVWatchHandler{
@@ -43,6 +49,16 @@ func (h *KVWatchHandler) WatchWebSocket(c *websocket.Conn) {
 		pattern = c.Query("key", "*")
 	}
 
+	since, resume, err := watchSince(c.Query("since"), "")
+	if err != nil {
+		_ = c.WriteJSON(fiber.Map{
+			"error":   "invalid since",
+			"message": err.Error(),
+		})
+		_ = c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseUnsupportedData, err.Error()))
+		return
+	}
+
 	// Get claims from locals (set before WebSocket upgrade)
 	claimsVal := c.Locals("claims")
 	var claims *auth.Claims
@@ -84,7 +100,7 @@ func (h *KVWatchHandler) WatchWebSocket(c *websocket.Conn) {
 	}
 
 	// Add watcher
-	watcher, err := h.watchManager.AddWatcher(pattern, policies, watch.TransportWebSocket, userID)
+	watcher, initial, err := h.addWatcher(pattern, policies, watch.TransportWebSocket, userID, since, resume)
 	if err != nil {
 		h.log.Error("Failed to add watcher", logger.Error(err))
 		_ = c.WriteJSON(fiber.Map{
@@ -98,20 +114,15 @@ func (h *KVWatchHandler) WatchWebSocket(c *websocket.Conn) {
 
 	h.log.Info("Watcher added", logger.String("watcher_id", watcher.ID))
 
-	// Send initial value if exact key match (not a wildcard)
-	if pattern != "*" && pattern != "**" && !containsWildcard(pattern) {
-		if value, ok := h.store.Get(pattern); ok {
-			initialEvent := watch.Event{
-				Type:      watch.EventTypeSet,
-				Key:       pattern,
-				Value:     value,
-				Timestamp: time.Now().Unix(),
-			}
-			if err := c.WriteJSON(initialEvent); err != nil {
-				h.log.Error("Failed to send initial value", logger.Error(err))
-				return
-			}
-			h.log.Debug("Sent initial value")
+	// Send the replayed events or the initial value
+	for _, event := range initial {
+		if err := c.WriteJSON(event); err != nil {
+			h.log.Error("Failed to send initial events", logger.Error(err))
+			return
+		}
+		if event.Type == watch.EventTypeResync {
+			closeResync(c)
+			return
 		}
 	}
 
@@ -152,6 +163,10 @@ func (h *KVWatchHandler) WatchWebSocket(c *websocket.Conn) {
 				h.log.Error("Failed to write event", logger.Error(err))
 				return
 			}
+			if event.Type == watch.EventTypeResync {
+				closeResync(c)
+				return
+			}
 
 			h.log.Debug("Sent watch event",
 				logger.String("key", event.Key),
@@ -178,6 +193,11 @@ func (h *KVWatchHandler) WatchSSE(c *fiber.Ctx) error {
 		pattern = c.Query("key", "*")
 	}
 
+	since, resume, err := watchSince(c.Query("since"), c.Get(LastEventIDHeader))
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+
 	// Get claims from context (set by JWT middleware)
 	claims := middleware.GetClaims(c)
 	if claims == nil {
@@ -210,7 +230,7 @@ func (h *KVWatchHandler) WatchSSE(c *fiber.Ctx) error {
 	}
 
 	// Add watcher
-	watcher, err := h.watchManager.AddWatcher(pattern, claims.Policies, watch.TransportSSE, userID)
+	watcher, initial, err := h.addWatcher(pattern, claims.Policies, watch.TransportSSE, userID, since, resume)
 	if err != nil {
 		h.log.Error("Failed to add watcher", logger.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -218,8 +238,6 @@ func (h *KVWatchHandler) WatchSSE(c *fiber.Ctx) error {
 			"message": err.Error(),
 		})
 	}
-	defer h.watchManager.RemoveWatcher(watcher.ID)
-
 	h.log.Info("Watcher added", logger.String("watcher_id", watcher.ID))
 
 	// Set SSE headers
@@ -229,18 +247,16 @@ func (h *KVWatchHandler) WatchSSE(c *fiber.Ctx) error {
 	c.Set("X-Accel-Buffering", "no") // Disable nginx buffering
 	c.Set("Access-Control-Allow-Origin", "*")
 
+	// The request context must not be used once the handler returns;
+	// disconnected clients are noticed when writing to them.
+	serverDone := c.Context().Done()
 	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
-		// Send initial value if exact key match
-		if pattern != "*" && pattern != "**" && !containsWildcard(pattern) {
-			if value, ok := h.store.Get(pattern); ok {
-				initialEvent := watch.Event{
-					Type:      watch.EventTypeSet,
-					Key:       pattern,
-					Value:     value,
-					Timestamp: time.Now().Unix(),
-				}
-				_ = sendSSEEvent(w, initialEvent)
-				h.log.Debug("Sent initial value")
+		defer h.watchManager.RemoveWatcher(watcher.ID)
+
+		// Send the replayed events or the initial value
+		for _, event := range initial {
+			if err := sendSSEEvent(w, "kv-change", event); err != nil || event.Type == watch.EventTypeResync {
+				return
 			}
 		}
 
@@ -258,10 +274,13 @@ func (h *KVWatchHandler) WatchSSE(c *fiber.Ctx) error {
 				}
 
 				// Send event
-				if err := sendSSEEvent(w, event); err != nil {
+				if err := sendSSEEvent(w, "kv-change", event); err != nil {
 					h.log.Error("Failed to send SSE event", logger.Error(err))
 					return
 				}
+				if event.Type == watch.EventTypeResync {
+					return
+				}
 
 				h.log.Debug("Sent SSE event",
 					logger.String("key", event.Key),
@@ -278,8 +297,7 @@ func (h *KVWatchHandler) WatchSSE(c *fiber.Ctx) error {
 					return
 				}
 
-			case <-c.Context().Done():
-				h.log.Info("Client disconnected")
+			case <-serverDone:
 				return
 			}
 		}
@@ -288,14 +306,73 @@ func (h *KVWatchHandler) WatchSSE(c *fiber.Ctx) error {
 	return nil
 }
 
-// sendSSEEvent sends a watch event in SSE format
-func sendSSEEvent(w *bufio.Writer, event watch.Event) error {
+// addWatcher adds a watcher for pattern and returns the events to send
+// before the watched ones: the events replayed when resuming after since,
+// or else the current value of a single watched key.
+func (h *KVWatchHandler) addWatcher(pattern string, policies []string, transport watch.TransportType, userID string, since uint64, resume bool) (*watch.Watcher, []watch.Event, error) {
+	if resume {
+		return h.watchManager.ResumeWatcher(pattern, policies, transport, userID, since)
+	}
+	watcher, err := h.watchManager.AddWatcher(pattern, policies, transport, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if containsWildcard(pattern) {
+		return watcher, nil, nil
+	}
+	entry, ok := h.store.GetEntry(pattern)
+	if !ok {
+		return watcher, nil, nil
+	}
+	return watcher, []watch.Event{{
+		Type:      watch.EventTypeSet,
+		Key:       pattern,
+		Index:     entry.ModifyIndex,
+		Value:     entry.Redacted().Value,
+		Timestamp: time.Now().Unix(),
+		Secret:    entry.Secret,
+	}}, nil
+}
+
+// watchSince returns the store index a watch resumes after, from the since
+// query parameter or else the Last-Event-ID header. resume is false for a
+// new watch.
+func watchSince(query, lastEventID string) (since uint64, resume bool, err error) {
+	value := query
+	if value == "" {
+		value = lastEventID
+	}
+	if value == "" {
+		return 0, false, nil
+	}
+	since, err = strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid since: %s", value)
+	}
+	return since, true, nil
+}
+
+// closeResync closes a WebSocket watch after sending it a resync event; the
+// client has to reload and watch again.
+func closeResync(c *websocket.Conn) {
+	_ = c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "resync required"))
+}
+
+// sendSSEEvent sends a watch event in SSE format under the event name name.
+// Events with a store index carry it as their id, which browsers send back
+// in the Last-Event-ID header when they reconnect.
+func sendSSEEvent(w *bufio.Writer, name string, event watch.Event) error {
 	data, err := json.Marshal(event)
 	if err != nil {
 		return err
 	}
 
-	if _, err := fmt.Fprintf(w, "event: kv-change\n"); err != nil {
+	if event.Index > 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", event.Index); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\n", name); err != nil {
 		return err
 	}
 	if _, err := fmt.Fprintf(w, "data: %s\n\n", string(data)); err != nil {
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	konsulraft "github.com/neogan74/konsul/internal/raft"
+)
+
+// checkLeaderForWrite checks if this node can handle writes: in standalone
+// mode, on the leader, or on a node forwarding writes to the leader.
+// Returns nil if writes are allowed, or the response sent otherwise: a
+// redirect to the leader, or 503 while no leader is elected.
+func checkLeaderForWrite(c *fiber.Ctx, raftNode *konsulraft.Node) error {
+	if raftNode == nil || raftNode.AcceptsWrites() {
+		return nil
+	}
+
+	leaderAddr := raftNode.LeaderAddr()
+	if leaderAddr == "" {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error":   "no leader",
+			"message": "No leader is currently elected. The cluster may be initializing or partitioned.",
+		})
+	}
+
+	return c.Status(fiber.StatusTemporaryRedirect).JSON(fiber.Map{
+		"error":       "not leader",
+		"message":     "This node is not the leader. Redirect to leader for write operations.",
+		"leader_addr": leaderAddr,
+	})
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/middleware"
+	konsulraft "github.com/neogan74/konsul/internal/raft"
+	"github.com/neogan74/konsul/internal/store"
+)
+
+// NamespaceHandler manages the namespace registry.
+type NamespaceHandler struct {
+	store    *store.NamespaceStore
+	raftNode *konsulraft.Node
+}
+
+// NewNamespaceHandler creates a namespace handler; raftNode may be nil.
+func NewNamespaceHandler(namespaceStore *store.NamespaceStore, raftNode *konsulraft.Node) *NamespaceHandler {
+	return &NamespaceHandler{store: namespaceStore, raftNode: raftNode}
+}
+
+// isRaftEnabled returns true if Raft clustering is enabled.
+func (h *NamespaceHandler) isRaftEnabled() bool {
+	return h.raftNode != nil
+}
+
+// List handles GET /namespaces.
+func (h *NamespaceHandler) List(c *fiber.Ctx) error {
+	return c.JSON(h.store.List())
+}
+
+// Get handles GET /namespaces/:name.
+func (h *NamespaceHandler) Get(c *fiber.Ctx) error {
+	ns, ok := h.store.Get(c.Params("name"))
+	if !ok {
+		return middleware.NotFound(c, "Namespace not found")
+	}
+	return c.JSON(ns)
+}
+
+// Create handles PUT /namespaces/:name.
+func (h *NamespaceHandler) Create(c *fiber.Ctx) error {
+	log := middleware.GetLogger(c)
+
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
+	var ns store.Namespace
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&ns); err != nil {
+			log.Error("Failed to parse namespace body", logger.Error(err))
+			return middleware.BadRequest(c, "Invalid JSON body")
+		}
+	}
+	ns.Name = utils.CopyString(c.Params("name"))
+	if err := store.ValidateNamespaceName(ns.Name); err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+
+	var created store.Namespace
+	var err error
+	if h.isRaftEnabled() {
+		created, err = h.raftNode.NamespaceCreate(ns)
+	} else {
+		created, err = h.store.Create(ns)
+	}
+	if err != nil {
+		if store.IsNamespaceConflict(err) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":   "namespace conflict",
+				"message": err.Error(),
+			})
+		}
+		log.Error("Failed to create namespace", logger.String("namespace", ns.Name), logger.Error(err))
+		return middleware.InternalError(c, "Failed to create namespace")
+	}
+
+	log.Info("Namespace created", logger.String("namespace", created.Name))
+	return c.Status(fiber.StatusCreated).JSON(created)
+}
+
+// Delete handles DELETE /namespaces/:name. Only empty namespaces can be deleted.
+func (h *NamespaceHandler) Delete(c *fiber.Ctx) error {
+	name := c.Params("name")
+	log := middleware.GetLogger(c)
+
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
+	var err error
+	if h.isRaftEnabled() {
+		err = h.raftNode.NamespaceDelete(name)
+	} else {
+		err = h.store.Delete(name)
+	}
+	if err != nil {
+		if store.IsNotFound(err) {
+			return middleware.NotFound(c, "Namespace not found")
+		}
+		if store.IsNamespaceConflict(err) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":   "namespace conflict",
+				"message": err.Error(),
+			})
+		}
+		log.Error("Failed to delete namespace", logger.String("namespace", name), logger.Error(err))
+		return middleware.InternalError(c, "Failed to delete namespace")
+	}
+
+	log.Info("Namespace deleted", logger.String("namespace", name))
+	return c.JSON(fiber.Map{"message": "namespace deleted", "name": name})
+}
+
+// requestNamespace returns the namespace a request targets, or "" for the
+// default namespace. Other namespaces must be registered; without a registry
+// only the default namespace is available.
+func requestNamespace(c *fiber.Ctx, namespaces *store.NamespaceStore) (string, error) {
+	return checkNamespace(middleware.GetNamespace(c), namespaces)
+}
+
+// checkNamespace is requestNamespace for the namespace name ns.
+func checkNamespace(ns string, namespaces *store.NamespaceStore) (string, error) {
+	ns = store.NormalizeNamespace(ns)
+	if ns == store.DefaultNamespace {
+		return "", nil
+	}
+	if err := store.ValidateNamespaceName(ns); err != nil {
+		return "", err
+	}
+	if namespaces == nil || !namespaces.Exists(ns) {
+		return "", &store.NotFoundError{Type: "namespace", Key: ns}
+	}
+	return ns, nil
+}
+
+// sameNamespace reports whether the namespace set on a request body agrees
+// with the namespace of the request; an empty body namespace always does.
+func sameNamespace(bodyNS, requestNS string) bool {
+	return bodyNS == "" || store.NormalizeNamespace(bodyNS) == store.NormalizeNamespace(requestNS)
+}
+
+// inNamespace returns the services that belong to namespace ns.
+func inNamespace(services []store.Service, ns string) []store.Service {
+	ns = store.NormalizeNamespace(ns)
+	result := make([]store.Service, 0, len(services))
+	for _, svc := range services {
+		if store.NormalizeNamespace(svc.Namespace) == ns {
+			result = append(result, svc)
+		}
+	}
+	return result
+}
+
+// resolveKey returns the storage key of a KV key in the namespace of the request.
+func resolveKey(c *fiber.Ctx, namespaces *store.NamespaceStore, key string) (string, error) {
+	if err := store.ValidateKeyName(key); err != nil {
+		return "", err
+	}
+	ns, err := requestNamespace(c, namespaces)
+	if err != nil {
+		return "", err
+	}
+	return store.NamespacedKey(ns, key), nil
+}
+
+// namespaceError writes the response for a request whose namespace or key
+// could not be resolved.
+func namespaceError(c *fiber.Ctx, err error) error {
+	if store.IsNotFound(err) {
+		return middleware.NotFound(c, err.Error())
+	}
+	return middleware.BadRequest(c, err.Error())
+}
+
+// namespacedKeys returns the storage keys of keys in namespace ns.
+func namespacedKeys(ns string, keys []string) ([]string, error) {
+	result := make([]string, len(keys))
+	for i, key := range keys {
+		if err := store.ValidateKeyName(key); err != nil {
+			return nil, err
+		}
+		result[i] = store.NamespacedKey(ns, key)
+	}
+	return result, nil
+}
+
+// namespacedMap returns a copy of items keyed by storage keys in namespace ns.
+func namespacedMap[V any](ns string, items map[string]V) (map[string]V, error) {
+	result := make(map[string]V, len(items))
+	for key, v := range items {
+		if err := store.ValidateKeyName(key); err != nil {
+			return nil, err
+		}
+		result[store.NamespacedKey(ns, key)] = v
+	}
+	return result, nil
+}
+
+// stripNamespaceMap maps storage keys back to the keys of the request.
+func stripNamespaceMap[V any](items map[string]V) map[string]V {
+	result := make(map[string]V, len(items))
+	for storageKey, v := range items {
+		_, key := store.SplitNamespacedKey(storageKey)
+		result[key] = v
+	}
+	return result
+}
+
+// stripNamespaceKeys maps storage keys back to the keys of the request.
+func stripNamespaceKeys(storageKeys []string) []string {
+	result := make([]string, len(storageKeys))
+	for i, storageKey := range storageKeys {
+		_, result[i] = store.SplitNamespacedKey(storageKey)
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/middleware"
+	konsulraft "github.com/neogan74/konsul/internal/raft"
+	"github.com/neogan74/konsul/internal/store"
+)
+
+// PreparedQueryHandler manages and executes prepared queries.
+type PreparedQueryHandler struct {
+	store    *store.PreparedQueryStore
+	raftNode *konsulraft.Node
+}
+
+// NewPreparedQueryHandler creates a prepared query handler; raftNode may be nil.
+func NewPreparedQueryHandler(queryStore *store.PreparedQueryStore, raftNode *konsulraft.Node) *PreparedQueryHandler {
+	return &PreparedQueryHandler{store: queryStore, raftNode: raftNode}
+}
+
+// isRaftEnabled returns true if Raft clustering is enabled.
+func (h *PreparedQueryHandler) isRaftEnabled() bool {
+	return h.raftNode != nil
+}
+
+// List handles GET /query.
+func (h *PreparedQueryHandler) List(c *fiber.Ctx) error {
+	return c.JSON(h.store.List())
+}
+
+// Get handles GET /query/:name.
+func (h *PreparedQueryHandler) Get(c *fiber.Ctx) error {
+	q, ok := h.store.Get(c.Params("name"))
+	if !ok {
+		return middleware.NotFound(c, "Prepared query not found")
+	}
+	return c.JSON(q)
+}
+
+// Set handles PUT /query/:name. It creates the prepared query or replaces
+// an existing one.
+func (h *PreparedQueryHandler) Set(c *fiber.Ctx) error {
+	log := middleware.GetLogger(c)
+
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
+	var q store.PreparedQuery
+	if err := c.BodyParser(&q); err != nil {
+		log.Error("Failed to parse prepared query body", logger.Error(err))
+		return middleware.BadRequest(c, "Invalid JSON body")
+	}
+	q.Name = utils.CopyString(c.Params("name"))
+	if err := store.ValidatePreparedQuery(q); err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+
+	var stored store.PreparedQuery
+	var err error
+	if h.isRaftEnabled() {
+		stored, err = h.raftNode.PreparedQuerySet(q)
+	} else {
+		stored, err = h.store.Set(q)
+	}
+	if err != nil {
+		log.Error("Failed to store prepared query", logger.String("query", q.Name), logger.Error(err))
+		return middleware.InternalError(c, "Failed to store prepared query")
+	}
+
+	log.Info("Prepared query stored", logger.String("query", stored.Name))
+	return c.JSON(stored)
+}
+
+// Delete handles DELETE /query/:name.
+func (h *PreparedQueryHandler) Delete(c *fiber.Ctx) error {
+	name := c.Params("name")
+	log := middleware.GetLogger(c)
+
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
+	var err error
+	if h.isRaftEnabled() {
+		err = h.raftNode.PreparedQueryDelete(name)
+	} else {
+		err = h.store.Delete(name)
+	}
+	if err != nil {
+		if store.IsNotFound(err) {
+			return middleware.NotFound(c, "Prepared query not found")
+		}
+		log.Error("Failed to delete prepared query", logger.String("query", name), logger.Error(err))
+		return middleware.InternalError(c, "Failed to delete prepared query")
+	}
+
+	log.Info("Prepared query deleted", logger.String("query", name))
+	return c.JSON(fiber.Map{"message": "prepared query deleted", "name": name})
+}
+
+// Execute handles GET /query/:name/execute.
+func (h *PreparedQueryHandler) Execute(c *fiber.Ctx) error {
+	result, err := h.store.Execute(c.Params("name"))
+	if err != nil {
+		if store.IsNotFound(err) {
+			return middleware.NotFound(c, "Prepared query not found")
+		}
+		return middleware.InternalError(c, "Failed to execute prepared query")
+	}
+	return c.JSON(result)
+}
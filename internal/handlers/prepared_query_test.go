@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/neogan74/konsul/internal/store"
+)
+
+func TestPreparedQueryHandler(t *testing.T) {
+	services := store.NewServiceStore()
+	handler := NewPreparedQueryHandler(store.NewPreparedQueryStore(services), nil)
+	app := fiber.New()
+	app.Get("/query/", handler.List)
+	app.Get("/query/:name", handler.Get)
+	app.Get("/query/:name/execute", handler.Execute)
+	app.Put("/query/:name", handler.Set)
+	app.Delete("/query/:name", handler.Delete)
+
+	if err := services.Register(store.Service{ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80, Tags: []string{"prod"}}); err != nil {
+		t.Fatalf("register service: %v", err)
+	}
+
+	do := func(method, path string, body interface{}) *http.Response {
+		t.Helper()
+		var reader *bytes.Reader
+		if body != nil {
+			data, _ := json.Marshal(body)
+			reader = bytes.NewReader(data)
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, path, err)
+		}
+		return resp
+	}
+
+	if resp := do(http.MethodPut, "/query/web-prod", map[string]interface{}{"tags": []string{"prod"}}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for a query without service, got %d", resp.StatusCode)
+	}
+	if resp := do(http.MethodPut, "/query/web-prod", store.PreparedQuery{Service: "web", Tags: []string{"prod"}}); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if resp := do(http.MethodGet, "/query/web-prod", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	resp := do(http.MethodGet, "/query/web-prod/execute", nil)
+	var result store.PreparedQueryResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(result.Instances) != 1 || result.Instances[0].ID != "web-1" {
+		t.Errorf("expected web-1, got %+v", result)
+	}
+
+	if resp := do(http.MethodDelete, "/query/web-prod", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+	if resp := do(http.MethodGet, "/query/web-prod/execute", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 after delete, got %d", resp.StatusCode)
+	}
+}
@@ -3,10 +3,13 @@ package handlers
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	hashiraft "github.com/hashicorp/raft"
+	"github.com/neogan74/konsul/internal/blocking"
+	"github.com/neogan74/konsul/internal/healthcheck"
 	"github.com/neogan74/konsul/internal/logger"
 	"github.com/neogan74/konsul/internal/metrics"
 	"github.com/neogan74/konsul/internal/middleware"
@@ -15,8 +18,9 @@ import (
 )
 
 type ServiceHandler struct {
-	store    *store.ServiceStore
-	raftNode *konsulraft.Node
+	store      *store.ServiceStore
+	namespaces *store.NamespaceStore
+	raftNode   *konsulraft.Node
 }
 
 func NewServiceHandler(serviceStore *store.ServiceStore, raftNode *konsulraft.Node) *ServiceHandler {
@@ -31,43 +35,23 @@ func NewServiceHandlerWithRaft(serviceStore *store.ServiceStore, raftNode *konsu
 	}
 }
 
+// WithNamespaces enables the ns query parameter and X-Konsul-Namespace header.
+// Without a registry only the default namespace is available.
+func (h *ServiceHandler) WithNamespaces(namespaces *store.NamespaceStore) *ServiceHandler {
+	h.namespaces = namespaces
+	return h
+}
+
 // isRaftEnabled returns true if Raft clustering is enabled.
 func (h *ServiceHandler) isRaftEnabled() bool {
 	return h.raftNode != nil
 }
 
-// checkLeaderForWrite checks if this node can handle writes.
-// Returns nil if writes are allowed, or an error response if not leader.
-func (h *ServiceHandler) checkLeaderForWrite(c *fiber.Ctx) error {
-	if !h.isRaftEnabled() {
-		return nil // Standalone mode, writes allowed
-	}
-
-	if h.raftNode.IsLeader() {
-		return nil // Leader, writes allowed
-	}
-
-	// Not leader, return redirect response
-	leaderAddr := h.raftNode.LeaderAddr()
-	if leaderAddr == "" {
-		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
-			"error":   "no leader",
-			"message": "No leader is currently elected. The cluster may be initializing or partitioned.",
-		})
-	}
-
-	return c.Status(fiber.StatusTemporaryRedirect).JSON(fiber.Map{
-		"error":       "not leader",
-		"message":     "This node is not the leader. Redirect to leader for write operations.",
-		"leader_addr": leaderAddr,
-	})
-}
-
 func (h *ServiceHandler) Register(c *fiber.Ctx) error {
 	log := middleware.GetLogger(c)
 
 	// Check if this node can handle writes (leader check for Raft mode)
-	if err := h.checkLeaderForWrite(c); err != nil {
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
 		return err
 	}
 
@@ -81,10 +65,25 @@ func (h *ServiceHandler) Register(c *fiber.Ctx) error {
 		return middleware.BadRequest(c, "Invalid JSON body")
 	}
 
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+	if !sameNamespace(body.Namespace, ns) {
+		return middleware.BadRequest(c, "Service namespace does not match the request namespace")
+	}
+
 	svc := body.Service
+	svc.ID = svc.InstanceID()
+	svc.Namespace = ns
+
+	if err := h.store.ValidateChecks(svc.Checks); err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
 
 	log.Info("Registering service",
 		logger.String("service_name", svc.Name),
+		logger.String("service_id", svc.ID),
 		logger.String("address", svc.Address),
 		logger.Int("port", svc.Port),
 		logger.Int("tags", len(svc.Tags)),
@@ -157,7 +156,7 @@ func (h *ServiceHandler) Register(c *fiber.Ctx) error {
 
 	// Use Raft for replicated registration if enabled
 	if h.isRaftEnabled() {
-		if err := h.raftNode.ServiceRegister(svc.Name, svc.Address, svc.Port, svc.Tags, svc.Meta); err != nil {
+		if err := h.raftNode.ServiceRegister(svc); err != nil {
 			log.Error("Raft service registration failed",
 				logger.String("service", svc.Name),
 				logger.Error(err))
@@ -190,7 +189,7 @@ func (h *ServiceHandler) Register(c *fiber.Ctx) error {
 	metrics.ServiceMetadataKeysPerService.Observe(float64(len(svc.Meta)))
 
 	// Return with index
-	entry, _ := h.store.GetEntry(svc.Name)
+	entry, _ := h.store.GetEntry(svc.StorageKey())
 	return c.JSON(fiber.Map{
 		"message":      "service registered",
 		"service":      svc,
@@ -198,61 +197,136 @@ func (h *ServiceHandler) Register(c *fiber.Ctx) error {
 	})
 }
 
+// healthStatuses returns the aggregated check statuses of the instances a
+// service query selects: only passing ones with ?passing, those listed in
+// ?status=passing,warning, or store.DiscoveryStatuses by default. A nil
+// result, for ?status=any, selects every instance.
+func healthStatuses(c *fiber.Ctx) ([]healthcheck.Status, error) {
+	if c.Context().QueryArgs().Has("passing") && c.Query("passing") != "false" {
+		return []healthcheck.Status{healthcheck.StatusPassing}, nil
+	}
+	param := c.Query("status")
+	switch param {
+	case "":
+		return store.DiscoveryStatuses, nil
+	case "any":
+		return nil, nil
+	}
+	var statuses []healthcheck.Status
+	for _, name := range strings.Split(param, ",") {
+		status, err := healthcheck.ParseStatus(name)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// filterHealth returns the services whose check status is one of statuses;
+// nil statuses keep every service.
+func (h *ServiceHandler) filterHealth(services []store.Service, statuses []healthcheck.Status) []store.Service {
+	if statuses == nil {
+		return services
+	}
+	return h.store.FilterByStatus(services, statuses...)
+}
+
 func (h *ServiceHandler) List(c *fiber.Ctx) error {
 	log := middleware.GetLogger(c)
-	services := h.store.List()
+
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+
+	statuses, err := healthStatuses(c)
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+
+	query, err := blocking.ParseQuery(c.Query("index"), c.Query("wait"))
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+	blockOnIndex(c, query, h.store.WaitForIndex)
+
+	services := h.filterHealth(h.store.ListNamespace(ns), statuses)
 
 	log.Debug("Listing services", logger.Int("count", len(services)))
 	return c.JSON(services)
 }
 
+// Get handles GET /services/:name
+// Returns all live instances of the named service
 func (h *ServiceHandler) Get(c *fiber.Ctx) error {
 	name := c.Params("name")
 	log := middleware.GetLogger(c)
 
 	log.Debug("Getting service", logger.String("service_name", name))
 
-	// Check if client wants full entry with indices
-	includeMetadata := c.Query("metadata", "false") == "true"
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
 
-	if includeMetadata {
-		entry, ok := h.store.GetEntry(name)
-		if !ok {
-			log.Warn("Service not found", logger.String("service_name", name))
-			metrics.ServiceOperationsTotal.WithLabelValues("get", "not_found").Inc()
-			return middleware.NotFound(c, "Service not found")
-		}
-		log.Info("Service retrieved successfully with metadata", logger.String("service_name", name))
-		metrics.ServiceOperationsTotal.WithLabelValues("get", "success").Inc()
-		return c.JSON(fiber.Map{
-			"service":      entry.Service,
-			"expires_at":   entry.ExpiresAt,
-			"modify_index": entry.ModifyIndex,
-			"create_index": entry.CreateIndex,
-		})
+	statuses, err := healthStatuses(c)
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+
+	query, err := blocking.ParseQuery(c.Query("index"), c.Query("wait"))
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
 	}
+	blockOnIndex(c, query, h.store.WaitForIndex)
 
-	svc, ok := h.store.Get(name)
-	if !ok {
+	entries := h.store.ListInstanceEntries(store.NamespacedKey(ns, name))
+	if len(entries) == 0 {
 		log.Warn("Service not found", logger.String("service_name", name))
 		metrics.ServiceOperationsTotal.WithLabelValues("get", "not_found").Inc()
 		return middleware.NotFound(c, "Service not found")
 	}
+	// A service whose instances are all filtered out exists, so it is
+	// returned with no instances rather than as not found
+	if statuses != nil {
+		entries = h.store.FilterEntriesByStatus(entries, statuses...)
+	}
 
-	log.Info("Service retrieved successfully", logger.String("service_name", name))
+	log.Info("Service retrieved successfully",
+		logger.String("service_name", name),
+		logger.Int("instances", len(entries)))
 	metrics.ServiceOperationsTotal.WithLabelValues("get", "success").Inc()
-	return c.JSON(svc)
+
+	// Check if client wants full entries with indices
+	if c.Query("metadata", "false") == "true" {
+		return c.JSON(entries)
+	}
+
+	services := make([]store.Service, 0, len(entries))
+	for _, entry := range entries {
+		services = append(services, entry.Service)
+	}
+	return c.JSON(services)
 }
 
+// Deregister handles DELETE /deregister/:name
+// The path segment is the instance ID, which defaults to the service name
 func (h *ServiceHandler) Deregister(c *fiber.Ctx) error {
 	name := c.Params("name")
 	log := middleware.GetLogger(c)
 
 	// Check if this node can handle writes (leader check for Raft mode)
-	if err := h.checkLeaderForWrite(c); err != nil {
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
 		return err
 	}
 
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+	id := store.NamespacedKey(ns, name)
+
 	log.Info("Deregistering service", logger.String("service_name", name))
 
 	// Check if CAS is requested via query parameter (CAS operations not replicated via Raft)
@@ -267,7 +341,8 @@ func (h *ServiceHandler) Deregister(c *fiber.Ctx) error {
 		var err error
 		if h.raftNode != nil {
 			cmd, marshalErr := konsulraft.NewCommand(konsulraft.CmdServiceDeregisterCAS, konsulraft.ServiceDeregisterCASPayload{
-				Name:          name,
+				Namespace:     ns,
+				ID:            name,
 				ExpectedIndex: expectedIndex,
 			})
 			if marshalErr != nil {
@@ -284,7 +359,7 @@ func (h *ServiceHandler) Deregister(c *fiber.Ctx) error {
 				err = applyErr
 			}
 		} else {
-			err = h.store.DeregisterCAS(name, expectedIndex)
+			err = h.store.DeregisterCAS(id, expectedIndex)
 		}
 		if err != nil {
 			if store.IsCASConflict(err) {
@@ -313,7 +388,7 @@ func (h *ServiceHandler) Deregister(c *fiber.Ctx) error {
 
 	// Use Raft for replicated deregistration if enabled
 	if h.isRaftEnabled() {
-		if err := h.raftNode.ServiceDeregister(name); err != nil {
+		if err := h.raftNode.ServiceDeregister(id); err != nil {
 			log.Error("Raft service deregistration failed",
 				logger.String("service", name),
 				logger.Error(err))
@@ -325,7 +400,7 @@ func (h *ServiceHandler) Deregister(c *fiber.Ctx) error {
 		}
 	} else {
 		// Standalone mode: direct store deregistration
-		h.store.Deregister(name)
+		h.store.Deregister(id)
 	}
 
 	log.Info("Service deregistered successfully", logger.String("service_name", name))
@@ -334,20 +409,33 @@ func (h *ServiceHandler) Deregister(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"message": "service deregistered", "name": name})
 }
 
+// Heartbeat handles PUT /heartbeat/:name
+// The path segment is the instance ID, which defaults to the service name
 func (h *ServiceHandler) Heartbeat(c *fiber.Ctx) error {
 	name := c.Params("name")
 	log := middleware.GetLogger(c)
 
 	// Check if this node can handle writes (leader check for Raft mode)
-	if err := h.checkLeaderForWrite(c); err != nil {
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
 		return err
 	}
 
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+	id := store.NamespacedKey(ns, name)
+
 	log.Debug("Processing heartbeat", logger.String("service_name", name))
 
-	// Use Raft for replicated heartbeat if enabled
+	// In a cluster the leader tracks heartbeats, see konsulraft.Node.ServiceHeartbeat
 	if h.isRaftEnabled() {
-		if err := h.raftNode.ServiceHeartbeat(name); err != nil {
+		if err := h.raftNode.ServiceHeartbeat(id); err != nil {
+			if errors.Is(err, konsulraft.ErrServiceNotFound) {
+				log.Warn("Heartbeat failed - service not found", logger.String("service_name", name))
+				metrics.ServiceHeartbeatsTotal.WithLabelValues(name, "not_found").Inc()
+				return middleware.NotFound(c, "Service not found")
+			}
 			log.Error("Raft service heartbeat failed",
 				logger.String("service", name),
 				logger.Error(err))
@@ -357,13 +445,13 @@ func (h *ServiceHandler) Heartbeat(c *fiber.Ctx) error {
 				"message": err.Error(),
 			})
 		}
-		log.Info("Heartbeat updated successfully via Raft", logger.String("service_name", name))
+		log.Info("Heartbeat updated successfully on the leader", logger.String("service_name", name))
 		metrics.ServiceHeartbeatsTotal.WithLabelValues(name, "success").Inc()
 		return c.JSON(fiber.Map{"message": "heartbeat updated", "service": name})
 	}
 
 	// Standalone mode: direct store heartbeat
-	if h.store.Heartbeat(name) {
+	if h.store.Heartbeat(id) {
 		log.Info("Heartbeat updated successfully", logger.String("service_name", name))
 		metrics.ServiceHeartbeatsTotal.WithLabelValues(name, "success").Inc()
 		return c.JSON(fiber.Map{"message": "heartbeat updated", "service": name})
@@ -406,11 +494,21 @@ func (h *ServiceHandler) QueryByTags(c *fiber.Ctx) error {
 		return middleware.BadRequest(c, "At least one tag must be specified")
 	}
 
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+
+	statuses, err := healthStatuses(c)
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+
 	log.Info("Querying services by tags",
 		logger.Int("tag_count", len(tagList)),
 		logger.String("tags", tags))
 
-	services := h.store.QueryByTags(tagList)
+	services := h.filterHealth(inNamespace(h.store.QueryByTags(tagList), ns), statuses)
 
 	// Record metrics
 	duration := c.Context().Time().Sub(startTime).Seconds()
@@ -434,11 +532,26 @@ func (h *ServiceHandler) QueryByMetadata(c *fiber.Ctx) error {
 	log := middleware.GetLogger(c)
 	startTime := c.Context().Time()
 
-	// Parse all query parameters as metadata filters
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+
+	statuses, err := healthStatuses(c)
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+
+	// Parse all query parameters except ns and the health filters as
+	// metadata filters
 	filters := make(map[string]string)
 	parser := c.Context().QueryArgs()
 	parser.VisitAll(func(key, value []byte) {
-		filters[string(key)] = string(value)
+		switch string(key) {
+		case "ns", "passing", "status":
+		default:
+			filters[string(key)] = string(value)
+		}
 	})
 
 	if len(filters) == 0 {
@@ -450,7 +563,7 @@ func (h *ServiceHandler) QueryByMetadata(c *fiber.Ctx) error {
 	log.Info("Querying services by metadata",
 		logger.Int("filter_count", len(filters)))
 
-	services := h.store.QueryByMetadata(filters)
+	services := h.filterHealth(inNamespace(h.store.QueryByMetadata(filters), ns), statuses)
 
 	// Record metrics
 	duration := c.Context().Time().Sub(startTime).Seconds()
@@ -501,11 +614,21 @@ func (h *ServiceHandler) QueryByTagsAndMetadata(c *fiber.Ctx) error {
 		return middleware.BadRequest(c, "At least one tag or metadata filter must be specified")
 	}
 
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+
+	statuses, err := healthStatuses(c)
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+
 	log.Info("Querying services by tags and metadata",
 		logger.Int("tag_count", len(tagList)),
 		logger.Int("filter_count", len(filters)))
 
-	services := h.store.QueryByTagsAndMetadata(tagList, filters)
+	services := h.filterHealth(inNamespace(h.store.QueryByTagsAndMetadata(tagList, filters), ns), statuses)
 
 	// Record metrics
 	duration := c.Context().Time().Sub(startTime).Seconds()
@@ -6,8 +6,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/neogan74/konsul/internal/healthcheck"
 	"github.com/neogan74/konsul/internal/store"
 )
 
@@ -74,6 +76,41 @@ func TestServiceHandler_Register(t *testing.T) {
 	}
 }
 
+func TestServiceHandler_RegisterValidatesChecks(t *testing.T) {
+	handler, app := setupServiceHandler()
+
+	register := func(service store.Service) int {
+		t.Helper()
+		body, _ := json.Marshal(service)
+		req := httptest.NewRequest(http.MethodPut, "/register", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("register request failed: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	script := store.Service{Name: "legacy", Address: "127.0.0.1", Port: 8080, Checks: []*healthcheck.CheckDefinition{
+		{Name: "legacy-script", Args: []string{"true"}},
+	}}
+	if status := register(script); status != http.StatusBadRequest {
+		t.Errorf("expected 400 for a script check while disabled, got %d", status)
+	}
+
+	badRegex := store.Service{Name: "web", Address: "127.0.0.1", Port: 8080, Checks: []*healthcheck.CheckDefinition{
+		{Name: "web-http", HTTP: "http://127.0.0.1:8080/health", BodyMatch: "("},
+	}}
+	if status := register(badRegex); status != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid body regex, got %d", status)
+	}
+
+	handler.store.EnableScriptChecks(true)
+	if status := register(script); status != http.StatusOK {
+		t.Errorf("expected 200 for a script check once enabled, got %d", status)
+	}
+}
+
 func TestServiceHandler_List(t *testing.T) {
 	handler, app := setupServiceHandler()
 
@@ -102,6 +139,44 @@ func TestServiceHandler_List(t *testing.T) {
 	}
 }
 
+func TestServiceHandler_BlockingList(t *testing.T) {
+	handler, app := setupServiceHandler()
+	_ = handler.store.Register(store.Service{Name: "web", Address: "127.0.0.1", Port: 8080})
+
+	req := httptest.NewRequest(http.MethodGet, "/services/", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("list request failed: %v", err)
+	}
+	index := resp.Header.Get(IndexHeader)
+	if index == "" {
+		t.Fatalf("expected %s header", IndexHeader)
+	}
+
+	// Heartbeats do not wake blocking queries, deregistration does
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		handler.store.Heartbeat("web")
+		time.Sleep(50 * time.Millisecond)
+		handler.store.Deregister("web")
+	}()
+	req = httptest.NewRequest(http.MethodGet, "/services/?index="+index+"&wait=5s", nil)
+	resp, err = app.Test(req, 10000)
+	if err != nil {
+		t.Fatalf("blocking list request failed: %v", err)
+	}
+	var services []store.Service
+	if err := json.NewDecoder(resp.Body).Decode(&services); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(services) != 0 {
+		t.Errorf("expected the deregistration to be returned, got %d services", len(services))
+	}
+	if resp.Header.Get(IndexHeader) == index {
+		t.Error("expected index to advance")
+	}
+}
+
 func TestServiceHandler_Get(t *testing.T) {
 	handler, app := setupServiceHandler()
 
@@ -129,12 +204,61 @@ func TestServiceHandler_Get(t *testing.T) {
 		t.Errorf("expected 200 for existing service, got %d", resp.StatusCode)
 	}
 
-	var result store.Service
+	var result []store.Service
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		t.Fatalf("decode response: %v", err)
 	}
-	if result.Name != service.Name || result.Address != service.Address || result.Port != service.Port {
-		t.Errorf("expected %+v, got %+v", service, result)
+	if len(result) != 1 {
+		t.Fatalf("expected 1 instance, got %d", len(result))
+	}
+	if result[0].Name != service.Name || result[0].Address != service.Address || result[0].Port != service.Port {
+		t.Errorf("expected %+v, got %+v", service, result[0])
+	}
+}
+
+func TestServiceHandler_GetMultipleInstances(t *testing.T) {
+	handler, app := setupServiceHandler()
+
+	_ = handler.store.Register(store.Service{ID: "api-2", Name: "api", Node: "node-b", Address: "10.0.0.2", Port: 8080})
+	_ = handler.store.Register(store.Service{ID: "api-1", Name: "api", Node: "node-a", Address: "10.0.0.1", Port: 8080})
+	_ = handler.store.Register(store.Service{Name: "db", Address: "10.0.0.3", Port: 5432})
+
+	req := httptest.NewRequest(http.MethodGet, "/services/api", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("get request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var result []store.Service
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 instances, got %d", len(result))
+	}
+	if result[0].ID != "api-1" || result[1].ID != "api-2" {
+		t.Errorf("expected instances ordered by ID, got %q and %q", result[0].ID, result[1].ID)
+	}
+	if result[0].Node != "node-a" || result[1].Node != "node-b" {
+		t.Errorf("unexpected nodes: %q, %q", result[0].Node, result[1].Node)
+	}
+
+	// Entries with indices
+	req = httptest.NewRequest(http.MethodGet, "/services/api?metadata=true", nil)
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("get request failed: %v", err)
+	}
+
+	var entries []store.ServiceEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ModifyIndex == 0 {
+		t.Errorf("expected 2 entries with indices, got %+v", entries)
 	}
 }
 
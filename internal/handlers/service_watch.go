@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/neogan74/konsul/internal/acl"
+	"github.com/neogan74/konsul/internal/auth"
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/middleware"
+	"github.com/neogan74/konsul/internal/store"
+	"github.com/neogan74/konsul/internal/watch"
+)
+
+// ServiceWatchHandler handles watch/subscribe requests for the service catalog
+type ServiceWatchHandler struct {
+	store        *store.ServiceStore
+	watchManager *watch.Manager
+	aclEval      *acl.Evaluator
+	namespaces   *store.NamespaceStore
+	log          logger.Logger
+}
+
+// NewServiceWatchHandler creates a new service watch handler
+func NewServiceWatchHandler(serviceStore *store.ServiceStore, watchManager *watch.Manager, aclEval *acl.Evaluator, log logger.Logger) *ServiceWatchHandler {
+	return &ServiceWatchHandler{
+		store:        serviceStore,
+		watchManager: watchManager,
+		aclEval:      aclEval,
+		log:          log,
+	}
+}
+
+// WithNamespaces sets the namespace registry watches are checked against.
+func (h *ServiceWatchHandler) WithNamespaces(namespaces *store.NamespaceStore) *ServiceWatchHandler {
+	h.namespaces = namespaces
+	return h
+}
+
+// watchedServices returns the pattern of the services watched from the name
+// path parameter or, without one, the name query parameter. All services
+// are watched by default.
+func watchedServices(param, query string) string {
+	if pattern, err := url.PathUnescape(param); err == nil && pattern != "" {
+		return pattern
+	}
+	if query != "" {
+		return query
+	}
+	return "*"
+}
+
+// addWatcher adds a watcher for the services of namespace ns matching
+// pattern and returns the events to send before the watched ones: the
+// events replayed when resuming after since, or else the register events of
+// the current instances of a single watched service.
+func (h *ServiceWatchHandler) addWatcher(ns, pattern string, policies []string, transport watch.TransportType, userID string, since uint64, resume bool) (*watch.Watcher, []watch.Event, error) {
+	if resume {
+		return h.watchManager.ResumeServiceWatcher(ns, pattern, policies, transport, userID, since)
+	}
+	watcher, err := h.watchManager.AddServiceWatcher(ns, pattern, policies, transport, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if containsWildcard(pattern) {
+		return watcher, nil, nil
+	}
+	return watcher, h.store.RegisterEvents(ns, pattern), nil
+}
+
+// WatchWebSocket handles WebSocket watch connections
+func (h *ServiceWatchHandler) WatchWebSocket(c *websocket.Conn) {
+	pattern := watchedServices(c.Params("name"), c.Query("name"))
+
+	ns := c.Query("ns")
+	if ns == "" {
+		ns = c.Headers(middleware.NamespaceHeader)
+	}
+	ns, err := checkNamespace(ns, h.namespaces)
+	if err != nil {
+		_ = c.WriteJSON(fiber.Map{
+			"error":   "invalid namespace",
+			"message": err.Error(),
+		})
+		_ = c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseUnsupportedData, err.Error()))
+		return
+	}
+
+	since, resume, err := watchSince(c.Query("since"), "")
+	if err != nil {
+		_ = c.WriteJSON(fiber.Map{
+			"error":   "invalid since",
+			"message": err.Error(),
+		})
+		_ = c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseUnsupportedData, err.Error()))
+		return
+	}
+
+	// Get claims from locals (set before WebSocket upgrade)
+	var userID string
+	var policies []string
+	if claims, ok := c.Locals("claims").(*auth.Claims); ok && claims != nil {
+		userID = claims.UserID
+		if userID == "" {
+			userID = claims.Username
+		}
+		policies = claims.Policies
+	}
+	if userID == "" {
+		userID = "anonymous"
+	}
+
+	h.log.Info("WebSocket service watch connection established",
+		logger.String("user_id", userID),
+		logger.String("pattern", pattern),
+		logger.String("namespace", store.NormalizeNamespace(ns)),
+		logger.String("transport", "websocket"))
+
+	// Check ACL permission - user must have read permission for the services
+	resource := acl.NewServiceResource(pattern).InNamespace(ns)
+	if h.aclEval != nil && !h.aclEval.Evaluate(policies, resource, acl.CapabilityRead) {
+		h.log.Warn("WebSocket service watch: ACL check failed")
+		_ = c.WriteJSON(fiber.Map{
+			"error":   "forbidden",
+			"message": "insufficient permissions to watch these services",
+		})
+		_ = c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "forbidden"))
+		return
+	}
+
+	watcher, initial, err := h.addWatcher(ns, pattern, policies, watch.TransportWebSocket, userID, since, resume)
+	if err != nil {
+		h.log.Error("Failed to add service watcher", logger.Error(err))
+		_ = c.WriteJSON(fiber.Map{
+			"error":   "failed to add watcher",
+			"message": err.Error(),
+		})
+		_ = c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, err.Error()))
+		return
+	}
+	defer h.watchManager.RemoveWatcher(watcher.ID)
+
+	for _, event := range initial {
+		if err := c.WriteJSON(event); err != nil {
+			h.log.Error("Failed to send initial events", logger.Error(err))
+			return
+		}
+		if event.Type == watch.EventTypeResync {
+			closeResync(c)
+			return
+		}
+	}
+
+	// Setup ping/pong for connection health
+	_ = c.SetReadDeadline(time.Now().Add(60 * time.Second))
+	c.SetPongHandler(func(string) error {
+		_ = c.SetReadDeadline(time.Now().Add(60 * time.Second))
+		return nil
+	})
+
+	pingTicker := time.NewTicker(30 * time.Second)
+	defer pingTicker.Stop()
+
+	// Read loop (to detect client disconnect)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			if _, _, err := c.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if err := c.WriteJSON(event); err != nil {
+				h.log.Error("Failed to write service event", logger.Error(err))
+				return
+			}
+			if event.Type == watch.EventTypeResync {
+				closeResync(c)
+				return
+			}
+
+		case <-pingTicker.C:
+			if err := c.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
+				h.log.Debug("Failed to send ping", logger.Error(err))
+				return
+			}
+
+		case <-done:
+			h.log.Info("Client disconnected")
+			return
+		}
+	}
+}
+
+// WatchSSE handles Server-Sent Events watch connections
+func (h *ServiceWatchHandler) WatchSSE(c *fiber.Ctx) error {
+	pattern := watchedServices(c.Params("name"), c.Query("name"))
+
+	ns, err := requestNamespace(c, h.namespaces)
+	if err != nil {
+		return namespaceError(c, err)
+	}
+
+	since, resume, err := watchSince(c.Query("since"), c.Get(LastEventIDHeader))
+	if err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+
+	// Claims are set by the JWT middleware when authentication is enabled
+	var userID string
+	var policies []string
+	if claims := middleware.GetClaims(c); claims != nil {
+		userID = claims.UserID
+		if userID == "" {
+			userID = claims.Username
+		}
+		policies = claims.Policies
+	}
+	if userID == "" {
+		userID = "anonymous"
+	}
+
+	h.log.Info("SSE service watch connection established",
+		logger.String("user_id", userID),
+		logger.String("pattern", pattern),
+		logger.String("namespace", store.NormalizeNamespace(ns)),
+		logger.String("transport", "sse"))
+
+	resource := acl.NewServiceResource(pattern).InNamespace(ns)
+	if h.aclEval != nil && !h.aclEval.Evaluate(policies, resource, acl.CapabilityRead) {
+		h.log.Warn("SSE service watch: ACL check failed")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":   "forbidden",
+			"message": "insufficient permissions to watch these services",
+		})
+	}
+
+	watcher, initial, err := h.addWatcher(ns, pattern, policies, watch.TransportSSE, userID, since, resume)
+	if err != nil {
+		h.log.Error("Failed to add service watcher", logger.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "failed to add watcher",
+			"message": err.Error(),
+		})
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no") // Disable nginx buffering
+	c.Set("Access-Control-Allow-Origin", "*")
+
+	// The request context must not be used once the handler returns;
+	// disconnected clients are noticed when writing to them.
+	serverDone := c.Context().Done()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer h.watchManager.RemoveWatcher(watcher.ID)
+
+		// Send the headers at once, even without initial events
+		if _, err := fmt.Fprintf(w, ": watching\n\n"); err != nil {
+			return
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+		for _, event := range initial {
+			if err := sendSSEEvent(w, "service-change", event); err != nil || event.Type == watch.EventTypeResync {
+				return
+			}
+		}
+
+		keepAliveTicker := time.NewTicker(30 * time.Second)
+		defer keepAliveTicker.Stop()
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if err := sendSSEEvent(w, "service-change", event); err != nil {
+					h.log.Error("Failed to send SSE event", logger.Error(err))
+					return
+				}
+				if event.Type == watch.EventTypeResync {
+					return
+				}
+
+			case <-keepAliveTicker.C:
+				if _, err := fmt.Fprintf(w, ": keep-alive\n\n"); err != nil {
+					return
+				}
+				if err := w.Flush(); err != nil {
+					return
+				}
+
+			case <-serverDone:
+				return
+			}
+		}
+	})
+
+	return nil
+}
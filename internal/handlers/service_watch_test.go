@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/neogan74/konsul/internal/acl"
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/store"
+	"github.com/neogan74/konsul/internal/watch"
+)
+
+func TestServiceWatchHandler_WatchSSERejects(t *testing.T) {
+	serviceStore := store.NewServiceStore()
+	defer func() { _ = serviceStore.Close() }()
+	log := logger.GetDefault()
+	wm := watch.NewManager(nil, log, 10, 0)
+
+	tests := []struct {
+		name    string
+		aclEval *acl.Evaluator
+		path    string
+		status  int
+	}{
+		{"unknown namespace", nil, "/services/watch/web?ns=staging", http.StatusNotFound},
+		{"invalid namespace", nil, "/services/watch/web?ns=Bad_NS", http.StatusBadRequest},
+		{"no read permission", acl.NewEvaluator(log), "/services/watch/web", http.StatusForbidden},
+		{"invalid since", nil, "/services/watch/web?since=latest", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewServiceWatchHandler(serviceStore, wm, tt.aclEval, log)
+			app := fiber.New()
+			app.Get("/services/watch/:name", handler.WatchSSE)
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil), -1)
+			if err != nil {
+				t.Fatalf("Failed to perform request: %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			if count := wm.GetActiveWatcherCount(); count != 0 {
+				t.Errorf("expected no watcher, got %d", count)
+			}
+		})
+	}
+}
+
+func TestServiceWatchHandler_WatchSSEResync(t *testing.T) {
+	serviceStore := store.NewServiceStore()
+	defer func() { _ = serviceStore.Close() }()
+	log := logger.GetDefault()
+	wm := watch.NewManager(nil, log, 10, 0)
+	serviceStore.SetWatchManager(wm)
+	if err := serviceStore.Register(store.Service{Name: "web", Address: "10.0.0.1", Port: 80}); err != nil {
+		t.Fatalf("register service: %v", err)
+	}
+
+	handler := NewServiceWatchHandler(serviceStore, wm, nil, log)
+	app := fiber.New()
+	app.Get("/services/watch/:name", handler.WatchSSE)
+
+	// Resuming from an index the journal does not know ends the stream
+	// with a resync event
+	req := httptest.NewRequest("GET", "/services/watch/web", nil)
+	req.Header.Set(LastEventIDHeader, "5")
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("Failed to perform request: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read stream: %v", err)
+	}
+	if !strings.Contains(string(body), "id: 1\nevent: service-change\ndata: {\"type\":\"resync\"") {
+		t.Errorf("expected a resync event at index 1, got %q", body)
+	}
+	if count := wm.GetActiveWatcherCount(); count != 0 {
+		t.Errorf("expected the watcher to be removed, got %d", count)
+	}
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/metrics"
+	"github.com/neogan74/konsul/internal/middleware"
+	konsulraft "github.com/neogan74/konsul/internal/raft"
+	"github.com/neogan74/konsul/internal/store"
+)
+
+type SessionHandler struct {
+	store    *store.SessionStore
+	services *store.ServiceStore
+	raftNode *konsulraft.Node
+}
+
+// NewSessionHandler creates a session handler. The service store is used to
+// look up health checks linked to sessions; raftNode may be nil.
+func NewSessionHandler(sessionStore *store.SessionStore, serviceStore *store.ServiceStore, raftNode *konsulraft.Node) *SessionHandler {
+	return &SessionHandler{store: sessionStore, services: serviceStore, raftNode: raftNode}
+}
+
+// isRaftEnabled returns true if Raft clustering is enabled.
+func (h *SessionHandler) isRaftEnabled() bool {
+	return h.raftNode != nil
+}
+
+// Create handles PUT /session/create.
+func (h *SessionHandler) Create(c *fiber.Ctx) error {
+	log := middleware.GetLogger(c)
+
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
+	var session store.Session
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&session); err != nil {
+			log.Error("Failed to parse session body", logger.Error(err))
+			return middleware.BadRequest(c, "Invalid JSON body")
+		}
+	}
+
+	if err := store.PrepareSession(&session); err != nil {
+		metrics.SessionOperationsTotal.WithLabelValues("create", "invalid").Inc()
+		return middleware.BadRequest(c, err.Error())
+	}
+	if err := store.ValidateSessionChecks(session.Checks, h.services.CheckStatus); err != nil {
+		metrics.SessionOperationsTotal.WithLabelValues("create", "invalid").Inc()
+		return middleware.BadRequest(c, err.Error())
+	}
+
+	var created store.Session
+	var err error
+	if h.isRaftEnabled() {
+		created, err = h.raftNode.SessionCreate(session)
+	} else {
+		created, err = h.store.Create(session)
+	}
+	if err != nil {
+		log.Error("Failed to create session", logger.String("session", session.ID), logger.Error(err))
+		metrics.SessionOperationsTotal.WithLabelValues("create", "error").Inc()
+		return middleware.InternalError(c, "Failed to create session")
+	}
+
+	log.Info("Session created",
+		logger.String("session", created.ID),
+		logger.String("name", created.Name),
+		logger.String("behavior", string(created.Behavior)))
+	metrics.SessionOperationsTotal.WithLabelValues("create", "success").Inc()
+	return c.JSON(created)
+}
+
+// Renew handles PUT /session/renew/:id.
+func (h *SessionHandler) Renew(c *fiber.Ctx) error {
+	id := c.Params("id")
+	log := middleware.GetLogger(c)
+
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
+	var session store.Session
+	var err error
+	if h.isRaftEnabled() {
+		session, err = h.raftNode.SessionRenew(id)
+	} else {
+		session, err = h.store.Renew(id)
+	}
+	if err != nil {
+		if store.IsNotFound(err) {
+			metrics.SessionOperationsTotal.WithLabelValues("renew", "not_found").Inc()
+			return middleware.NotFound(c, "Session not found")
+		}
+		log.Error("Failed to renew session", logger.String("session", id), logger.Error(err))
+		metrics.SessionOperationsTotal.WithLabelValues("renew", "error").Inc()
+		return middleware.InternalError(c, "Failed to renew session")
+	}
+
+	log.Debug("Session renewed", logger.String("session", id))
+	metrics.SessionOperationsTotal.WithLabelValues("renew", "success").Inc()
+	return c.JSON(session)
+}
+
+// Destroy handles PUT /session/destroy/:id. Locks held by the session are
+// released or their keys deleted, depending on the session behavior.
+func (h *SessionHandler) Destroy(c *fiber.Ctx) error {
+	id := c.Params("id")
+	log := middleware.GetLogger(c)
+
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
+	var err error
+	if h.isRaftEnabled() {
+		err = h.raftNode.SessionDestroy(id)
+	} else {
+		err = h.store.Destroy(id)
+	}
+	if err != nil {
+		if store.IsNotFound(err) {
+			metrics.SessionOperationsTotal.WithLabelValues("destroy", "not_found").Inc()
+			return middleware.NotFound(c, "Session not found")
+		}
+		log.Error("Failed to destroy session", logger.String("session", id), logger.Error(err))
+		metrics.SessionOperationsTotal.WithLabelValues("destroy", "error").Inc()
+		return middleware.InternalError(c, "Failed to destroy session")
+	}
+
+	log.Info("Session destroyed", logger.String("session", id))
+	metrics.SessionOperationsTotal.WithLabelValues("destroy", "success").Inc()
+	return c.JSON(fiber.Map{"message": "session destroyed", "id": id})
+}
+
+// Info handles GET /session/info/:id.
+func (h *SessionHandler) Info(c *fiber.Ctx) error {
+	session, ok := h.store.Get(c.Params("id"))
+	if !ok {
+		return middleware.NotFound(c, "Session not found")
+	}
+	return c.JSON(session)
+}
+
+// List handles GET /session/list.
+func (h *SessionHandler) List(c *fiber.Ctx) error {
+	return c.JSON(h.store.List())
+}
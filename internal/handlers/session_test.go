@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/neogan74/konsul/internal/store"
+)
+
+func setupSessionHandler() (*SessionHandler, *store.KVStore, *fiber.App) {
+	kvStore := store.NewKVStore()
+	sessions := store.NewSessionStore(kvStore)
+	handler := NewSessionHandler(sessions, store.NewServiceStore(), nil)
+	kvHandler := NewKVHandler(kvStore, nil).WithSessions(sessions)
+
+	app := fiber.New()
+	app.Put("/session/create", handler.Create)
+	app.Put("/session/renew/:id", handler.Renew)
+	app.Put("/session/destroy/:id", handler.Destroy)
+	app.Get("/session/info/:id", handler.Info)
+	app.Get("/session/list", handler.List)
+	app.Put("/kv/*", kvHandler.Set)
+
+	return handler, kvStore, app
+}
+
+func createTestSession(t *testing.T, app *fiber.App, body string) store.Session {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPut, "/session/create", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("create request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 creating session, got %d", resp.StatusCode)
+	}
+	var session store.Session
+	if err := json.NewDecoder(resp.Body).Decode(&session); err != nil {
+		t.Fatalf("failed to decode session: %v", err)
+	}
+	return session
+}
+
+func lockRequest(t *testing.T, app *fiber.App, key, op, session, value string) bool {
+	t.Helper()
+	body, _ := json.Marshal(map[string]string{"value": value})
+	req := httptest.NewRequest(http.MethodPut, "/kv/"+key+"?"+op+"="+session, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("%s request failed: %v", op, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 for %s, got %d", op, resp.StatusCode)
+	}
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return result["result"] == true
+}
+
+func TestSessionHandler_CreateInfoList(t *testing.T) {
+	_, _, app := setupSessionHandler()
+
+	session := createTestSession(t, app, `{"name":"worker","ttl":"15s"}`)
+	if session.ID == "" || session.Behavior != store.SessionBehaviorRelease {
+		t.Fatalf("unexpected session: %+v", session)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/session/info/"+session.ID, nil)
+	resp, _ := app.Test(req)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 for info, got %d", resp.StatusCode)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/session/list", nil)
+	resp, _ = app.Test(req)
+	var list []store.Session
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode list: %v", err)
+	}
+	if len(list) != 1 {
+		t.Errorf("expected 1 session, got %d", len(list))
+	}
+
+	// Invalid TTL and unknown checks are rejected
+	for _, body := range []string{`{"ttl":"1s"}`, `{"checks":["missing"]}`} {
+		req = httptest.NewRequest(http.MethodPut, "/session/create", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ = app.Test(req)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected 400 for %s, got %d", body, resp.StatusCode)
+		}
+	}
+}
+
+func TestSessionHandler_RenewDestroy(t *testing.T) {
+	_, _, app := setupSessionHandler()
+	session := createTestSession(t, app, `{"ttl":"10s"}`)
+
+	req := httptest.NewRequest(http.MethodPut, "/session/renew/"+session.ID, nil)
+	resp, _ := app.Test(req)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 for renew, got %d", resp.StatusCode)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/session/destroy/"+session.ID, nil)
+	resp, _ = app.Test(req)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 for destroy, got %d", resp.StatusCode)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/session/renew/"+session.ID, nil)
+	resp, _ = app.Test(req)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 renewing destroyed session, got %d", resp.StatusCode)
+	}
+}
+
+func TestSessionHandler_KVLocks(t *testing.T) {
+	_, kv, app := setupSessionHandler()
+	a := createTestSession(t, app, `{"name":"a"}`)
+	b := createTestSession(t, app, `{"name":"b"}`)
+
+	if !lockRequest(t, app, "service/leader", "acquire", a.ID, "node-a") {
+		t.Fatal("expected a to acquire the lock")
+	}
+	if lockRequest(t, app, "service/leader", "acquire", b.ID, "node-b") {
+		t.Fatal("expected b to be refused while a holds the lock")
+	}
+	if value, _ := kv.Get("service/leader"); value != "node-a" {
+		t.Errorf("expected value node-a, got %s", value)
+	}
+
+	if !lockRequest(t, app, "service/leader", "release", a.ID, "") {
+		t.Fatal("expected a to release the lock")
+	}
+	if !lockRequest(t, app, "service/leader", "acquire", b.ID, "node-b") {
+		t.Fatal("expected b to acquire after release")
+	}
+
+	// Destroying the holder frees the lock for others
+	req := httptest.NewRequest(http.MethodPut, "/session/destroy/"+b.ID, nil)
+	if resp, _ := app.Test(req); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 for destroy, got %d", resp.StatusCode)
+	}
+	entry, _ := kv.GetEntry("service/leader")
+	if entry.Session != "" {
+		t.Errorf("expected lock to be released on destroy, held by %q", entry.Session)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/kv/service/leader?acquire=missing", bytes.NewBufferString(`{"value":"x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	if resp, _ := app.Test(req); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown session, got %d", resp.StatusCode)
+	}
+}
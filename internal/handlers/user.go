@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/neogan74/konsul/internal/auth"
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/middleware"
+	konsulraft "github.com/neogan74/konsul/internal/raft"
+)
+
+// UserHandler manages the users that can log in at /auth/login.
+type UserHandler struct {
+	users    *auth.UserService
+	raftNode *konsulraft.Node
+}
+
+// NewUserHandler creates a user handler; raftNode may be nil.
+func NewUserHandler(users *auth.UserService, raftNode *konsulraft.Node) *UserHandler {
+	return &UserHandler{users: users, raftNode: raftNode}
+}
+
+// CreateUserRequest represents the user creation request body
+type CreateUserRequest struct {
+	Username string            `json:"username"`
+	Password string            `json:"password"`
+	Roles    []string          `json:"roles"`
+	Policies []string          `json:"policies"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// UpdateUserRequest represents the user update request body; omitted fields
+// are left unchanged.
+type UpdateUserRequest struct {
+	Roles    []string          `json:"roles"`
+	Policies []string          `json:"policies"`
+	Metadata map[string]string `json:"metadata"`
+	Disabled *bool             `json:"disabled"`
+}
+
+// SetPasswordRequest represents the password change request body
+type SetPasswordRequest struct {
+	Password string `json:"password"`
+}
+
+// isRaftEnabled returns true if Raft clustering is enabled.
+func (h *UserHandler) isRaftEnabled() bool {
+	return h.raftNode != nil
+}
+
+// updateUser updates a user through Raft when clustering is enabled.
+func (h *UserHandler) updateUser(username string, update auth.UserUpdate) error {
+	if h.isRaftEnabled() {
+		return h.raftNode.UserUpdate(username, update)
+	}
+	return h.users.UpdateUser(username, update)
+}
+
+// Create handles POST /auth/users.
+func (h *UserHandler) Create(c *fiber.Ctx) error {
+	log := middleware.GetLogger(c)
+
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
+	var req CreateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return middleware.BadRequest(c, "Invalid JSON body")
+	}
+
+	// The password is hashed here so that only the hash is replicated
+	user, err := h.users.NewUser(req.Username, req.Password, req.Roles, req.Policies, req.Metadata)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidUsername) || errors.Is(err, auth.ErrPasswordTooShort) {
+			return middleware.BadRequest(c, err.Error())
+		}
+		log.Error("Failed to create user", logger.String("username", req.Username), logger.Error(err))
+		return middleware.InternalError(c, "Failed to create user")
+	}
+
+	if h.isRaftEnabled() {
+		err = h.raftNode.UserCreate(user)
+	} else {
+		err = h.users.AddUser(user)
+	}
+	if err != nil {
+		if errors.Is(err, auth.ErrUserExists) {
+			return middleware.Conflict(c, "User already exists")
+		}
+		log.Error("Failed to create user", logger.String("username", user.Username), logger.Error(err))
+		return middleware.InternalError(c, "Failed to create user")
+	}
+
+	log.Info("User created", logger.String("username", user.Username))
+	created, err := h.users.GetUser(user.Username)
+	if err != nil {
+		return middleware.InternalError(c, "Failed to read created user")
+	}
+	return c.Status(fiber.StatusCreated).JSON(created)
+}
+
+// List handles GET /auth/users.
+func (h *UserHandler) List(c *fiber.Ctx) error {
+	users := h.users.ListUsers()
+	return c.JSON(fiber.Map{
+		"users": users,
+		"count": len(users),
+	})
+}
+
+// Get handles GET /auth/users/:username.
+func (h *UserHandler) Get(c *fiber.Ctx) error {
+	user, err := h.users.GetUser(c.Params("username"))
+	if err != nil {
+		return middleware.NotFound(c, "User not found")
+	}
+	return c.JSON(user)
+}
+
+// Update handles PUT /auth/users/:username.
+func (h *UserHandler) Update(c *fiber.Ctx) error {
+	username := utils.CopyString(c.Params("username"))
+	log := middleware.GetLogger(c)
+
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
+	var req UpdateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return middleware.BadRequest(c, "Invalid JSON body")
+	}
+
+	err := h.updateUser(username, auth.UserUpdate{
+		Roles:    req.Roles,
+		Policies: req.Policies,
+		Metadata: req.Metadata,
+		Disabled: req.Disabled,
+	})
+	if err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			return middleware.NotFound(c, "User not found")
+		}
+		log.Error("Failed to update user", logger.String("username", username), logger.Error(err))
+		return middleware.InternalError(c, "Failed to update user")
+	}
+
+	log.Info("User updated", logger.String("username", username))
+	user, err := h.users.GetUser(username)
+	if err != nil {
+		return middleware.NotFound(c, "User not found")
+	}
+	return c.JSON(user)
+}
+
+// SetPassword handles PUT /auth/users/:username/password. Setting a password
+// also lifts a lockout of the user.
+func (h *UserHandler) SetPassword(c *fiber.Ctx) error {
+	username := utils.CopyString(c.Params("username"))
+	log := middleware.GetLogger(c)
+
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
+	var req SetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return middleware.BadRequest(c, "Invalid JSON body")
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		if errors.Is(err, auth.ErrPasswordTooShort) {
+			return middleware.BadRequest(c, err.Error())
+		}
+		log.Error("Failed to hash password", logger.Error(err))
+		return middleware.InternalError(c, "Failed to set password")
+	}
+
+	if err := h.updateUser(username, auth.UserUpdate{PasswordHash: hash}); err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			return middleware.NotFound(c, "User not found")
+		}
+		log.Error("Failed to set password", logger.String("username", username), logger.Error(err))
+		return middleware.InternalError(c, "Failed to set password")
+	}
+
+	log.Info("User password changed", logger.String("username", username))
+	return c.JSON(fiber.Map{"message": "password updated", "username": username})
+}
+
+// Delete handles DELETE /auth/users/:username.
+func (h *UserHandler) Delete(c *fiber.Ctx) error {
+	username := utils.CopyString(c.Params("username"))
+	log := middleware.GetLogger(c)
+
+	if err := checkLeaderForWrite(c, h.raftNode); err != nil {
+		return err
+	}
+
+	var err error
+	if h.isRaftEnabled() {
+		err = h.raftNode.UserDelete(username)
+	} else {
+		err = h.users.DeleteUser(username)
+	}
+	if err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			return middleware.NotFound(c, "User not found")
+		}
+		log.Error("Failed to delete user", logger.String("username", username), logger.Error(err))
+		return middleware.InternalError(c, "Failed to delete user")
+	}
+
+	log.Info("User deleted", logger.String("username", username))
+	return c.JSON(fiber.Map{"message": "user deleted", "username": username})
+}
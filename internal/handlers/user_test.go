@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/neogan74/konsul/internal/auth"
+)
+
+func setupUserHandler() (*auth.UserService, *fiber.App) {
+	users := auth.NewUserService(5, time.Minute)
+	handler := NewUserHandler(users, nil)
+
+	app := fiber.New()
+	app.Post("/auth/users", handler.Create)
+	app.Get("/auth/users", handler.List)
+	app.Get("/auth/users/:username", handler.Get)
+	app.Put("/auth/users/:username", handler.Update)
+	app.Put("/auth/users/:username/password", handler.SetPassword)
+	app.Delete("/auth/users/:username", handler.Delete)
+
+	return users, app
+}
+
+func userRequest(t *testing.T, app *fiber.App, method, path, body string) *http.Response {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = bytes.NewBufferString(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	return resp
+}
+
+func TestUserHandler_CRUD(t *testing.T) {
+	users, app := setupUserHandler()
+
+	resp := userRequest(t, app, http.MethodPost, "/auth/users",
+		`{"username": "alice", "password": "alice-password", "roles": ["admin"], "policies": ["developer"]}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if strings.Contains(string(body), "password_hash") {
+		t.Errorf("response must not contain the password hash: %s", body)
+	}
+
+	resp = userRequest(t, app, http.MethodPost, "/auth/users", `{"username": "alice", "password": "alice-password"}`)
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected 409 for duplicate user, got %d", resp.StatusCode)
+	}
+
+	resp = userRequest(t, app, http.MethodPut, "/auth/users/alice", `{"policies": ["operator"], "disabled": true}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var user auth.User
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		t.Fatalf("failed to decode user: %v", err)
+	}
+	if !user.Disabled || len(user.Policies) != 1 || user.Policies[0] != "operator" {
+		t.Errorf("unexpected user after update: %+v", user)
+	}
+	if len(user.Roles) != 1 || user.Roles[0] != "admin" {
+		t.Errorf("expected roles to be left unchanged, got %v", user.Roles)
+	}
+
+	resp = userRequest(t, app, http.MethodPut, "/auth/users/alice/password", `{"password": "new-password"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if _, err := users.Authenticate("alice", "new-password"); err != auth.ErrUserDisabled {
+		t.Errorf("expected new password to be accepted for disabled user, got %v", err)
+	}
+
+	resp = userRequest(t, app, http.MethodGet, "/auth/users", "")
+	var list struct {
+		Users []auth.User `json:"users"`
+		Count int         `json:"count"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode list: %v", err)
+	}
+	if list.Count != 1 || list.Users[0].Username != "alice" {
+		t.Errorf("unexpected user list: %+v", list)
+	}
+
+	resp = userRequest(t, app, http.MethodDelete, "/auth/users/alice", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	resp = userRequest(t, app, http.MethodGet, "/auth/users/alice", "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 after delete, got %d", resp.StatusCode)
+	}
+}
+
+func TestUserHandler_Validation(t *testing.T) {
+	_, app := setupUserHandler()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"short password", `{"username": "bob", "password": "short"}`},
+		{"invalid username", `{"username": "bob smith", "password": "bob-password"}`},
+		{"invalid JSON", `not json`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := userRequest(t, app, http.MethodPost, "/auth/users", tt.body)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d", resp.StatusCode)
+			}
+		})
+	}
+
+	resp := userRequest(t, app, http.MethodPut, "/auth/users/nobody/password", `{"password": "long-enough"}`)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown user, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/neogan74/konsul/internal/acl"
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/middleware"
+	"github.com/neogan74/konsul/internal/store"
+	"github.com/neogan74/konsul/internal/watch"
+	"github.com/neogan74/konsul/internal/webhook"
+)
+
+// WebhookHandler manages the outbound webhooks of the node.
+type WebhookHandler struct {
+	manager    *webhook.Manager
+	aclEval    *acl.Evaluator
+	namespaces *store.NamespaceStore
+}
+
+// NewWebhookHandler creates a webhook handler; aclEval may be nil.
+func NewWebhookHandler(manager *webhook.Manager, aclEval *acl.Evaluator) *WebhookHandler {
+	return &WebhookHandler{manager: manager, aclEval: aclEval}
+}
+
+// WithNamespaces sets the namespace registry service webhooks are checked
+// against.
+func (h *WebhookHandler) WithNamespaces(namespaces *store.NamespaceStore) *WebhookHandler {
+	h.namespaces = namespaces
+	return h
+}
+
+// List handles GET /webhooks.
+func (h *WebhookHandler) List(c *fiber.Ctx) error {
+	return c.JSON(h.manager.List())
+}
+
+// Get handles GET /webhooks/:name.
+func (h *WebhookHandler) Get(c *fiber.Ctx) error {
+	hook, err := h.manager.Get(c.Params("name"))
+	if err != nil {
+		return middleware.NotFound(c, "Webhook not found")
+	}
+	return c.JSON(hook)
+}
+
+// Set handles PUT /webhooks/:name. The webhook gets the policies of the
+// caller, who must be able to read the keys or services it watches.
+func (h *WebhookHandler) Set(c *fiber.Ctx) error {
+	log := middleware.GetLogger(c)
+
+	var hook webhook.Webhook
+	if err := c.BodyParser(&hook); err != nil {
+		log.Error("Failed to parse webhook body", logger.Error(err))
+		return middleware.BadRequest(c, "Invalid JSON body")
+	}
+	hook.Name = utils.CopyString(c.Params("name"))
+	hook.Policies = nil
+	if claims := middleware.GetClaims(c); claims != nil {
+		hook.Policies = claims.Policies
+	}
+
+	hook.Normalize()
+	if err := hook.Validate(); err != nil {
+		return middleware.BadRequest(c, err.Error())
+	}
+	if hook.Kind == watch.KindService {
+		ns, err := checkNamespace(hook.Namespace, h.namespaces)
+		if err != nil {
+			return middleware.BadRequest(c, err.Error())
+		}
+		hook.Namespace = ns
+	}
+	if h.aclEval != nil && !h.aclEval.Evaluate(hook.Policies, hook.Resource(), acl.CapabilityRead) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":   "forbidden",
+			"message": "insufficient permissions to watch this pattern",
+		})
+	}
+
+	saved, err := h.manager.Set(hook)
+	if err != nil {
+		log.Error("Failed to set webhook", logger.String("name", hook.Name), logger.Error(err))
+		return middleware.InternalError(c, "Failed to set webhook")
+	}
+
+	log.Info("Webhook set", logger.String("name", saved.Name), logger.String("url", saved.URL))
+	return c.JSON(saved)
+}
+
+// Delete handles DELETE /webhooks/:name.
+func (h *WebhookHandler) Delete(c *fiber.Ctx) error {
+	name := c.Params("name")
+	log := middleware.GetLogger(c)
+
+	if err := h.manager.Delete(name); err != nil {
+		if errors.Is(err, webhook.ErrNotFound) {
+			return middleware.NotFound(c, "Webhook not found")
+		}
+		log.Error("Failed to delete webhook", logger.String("name", name), logger.Error(err))
+		return middleware.InternalError(c, "Failed to delete webhook")
+	}
+
+	log.Info("Webhook deleted", logger.String("name", name))
+	return c.JSON(fiber.Map{"message": "webhook deleted", "name": name})
+}
+
+// Deliveries handles GET /webhooks/:name/deliveries: the pending and recent
+// deliveries of the webhook, newest first.
+func (h *WebhookHandler) Deliveries(c *fiber.Ctx) error {
+	name := c.Params("name")
+	deliveries, err := h.manager.Deliveries(name)
+	if err != nil {
+		return middleware.NotFound(c, "Webhook not found")
+	}
+	return c.JSON(fiber.Map{
+		"webhook":    name,
+		"deliveries": deliveries,
+	})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/neogan74/konsul/internal/acl"
+	"github.com/neogan74/konsul/internal/auth"
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/watch"
+	"github.com/neogan74/konsul/internal/webhook"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	wm := watch.NewManager(nil, logger.GetDefault(), 10, 0)
+	manager, err := webhook.NewManager(wm, nil, webhook.Config{}, logger.GetDefault())
+	if err != nil {
+		t.Fatalf("failed to create webhook manager: %v", err)
+	}
+	defer manager.Close()
+
+	evaluator := acl.NewEvaluator(logger.GetDefault())
+	if err := evaluator.AddPolicy(&acl.Policy{
+		Name: "app-reader",
+		KV:   []acl.KVRule{{Path: "app/*", Capabilities: []acl.Capability{acl.CapabilityRead}}},
+	}); err != nil {
+		t.Fatalf("failed to add policy: %v", err)
+	}
+	handler := NewWebhookHandler(manager, evaluator)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("claims", &auth.Claims{Policies: []string{"app-reader"}})
+		return c.Next()
+	})
+	app.Get("/webhooks", handler.List)
+	app.Get("/webhooks/:name", handler.Get)
+	app.Put("/webhooks/:name", handler.Set)
+	app.Delete("/webhooks/:name", handler.Delete)
+	app.Get("/webhooks/:name/deliveries", handler.Deliveries)
+
+	do := func(method, path, body string) (int, map[string]any) {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, path, err)
+		}
+		var result map[string]any
+		_ = json.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, result
+	}
+
+	status, result := do("PUT", "/webhooks/deploy", `{"url": "http://ci.example.com/hook", "secret": "s3cret", "pattern": "app/**"}`)
+	if status != http.StatusOK {
+		t.Fatalf("expected 200, got %d %+v", status, result)
+	}
+	if result["secret"] != nil || result["signed"] != true || result["kind"] != "kv" {
+		t.Errorf("expected a redacted, signed KV webhook, got %+v", result)
+	}
+	if policies, _ := result["policies"].([]any); len(policies) != 1 || policies[0] != "app-reader" {
+		t.Errorf("expected the caller's policies, got %v", result["policies"])
+	}
+
+	if status, _ := do("PUT", "/webhooks/all", `{"url": "http://ci.example.com/hook"}`); status != http.StatusForbidden {
+		t.Errorf("expected 403 for a pattern the caller cannot read, got %d", status)
+	}
+	if status, _ := do("PUT", "/webhooks/bad", `{"url": "ftp://ci", "pattern": "app/**"}`); status != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid URL, got %d", status)
+	}
+	if status, _ := do("PUT", "/webhooks/svc", `{"url": "http://ci", "kind": "service", "namespace": "team-a"}`); status != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown namespace, got %d", status)
+	}
+
+	if status, result := do("GET", "/webhooks/deploy", ""); status != http.StatusOK || result["url"] != "http://ci.example.com/hook" {
+		t.Errorf("expected the webhook, got %d %+v", status, result)
+	}
+	if status, result := do("GET", "/webhooks/deploy/deliveries", ""); status != http.StatusOK || result["webhook"] != "deploy" {
+		t.Errorf("expected the deliveries, got %d %+v", status, result)
+	}
+
+	if status, _ := do("DELETE", "/webhooks/deploy", ""); status != http.StatusOK {
+		t.Errorf("expected 200, got %d", status)
+	}
+	for _, path := range []string{"/webhooks/deploy", "/webhooks/deploy/deliveries"} {
+		if status, _ := do("GET", path, ""); status != http.StatusNotFound {
+			t.Errorf("GET %s: expected 404, got %d", path, status)
+		}
+	}
+	if status, _ := do("DELETE", "/webhooks/deploy", ""); status != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", status)
+	}
+}
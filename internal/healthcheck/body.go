@@ -0,0 +1,128 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxBodySize bounds the part of a response body that HTTP checks assert on.
+const maxBodySize = 64 * 1024
+
+// assertBody checks the body of a successful HTTP check response against
+// the body assertions of the check, if it has any.
+func assertBody(check *Check, body io.Reader) error {
+	if check.BodyMatch == "" && check.BodyJSONPath == "" {
+		return nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, maxBodySize))
+	if err != nil {
+		return fmt.Errorf("failed to read body: %w", err)
+	}
+
+	if check.BodyMatch != "" {
+		re, err := regexp.Compile(check.BodyMatch)
+		if err != nil {
+			return fmt.Errorf("invalid body_match: %w", err)
+		}
+		if !re.Match(data) {
+			return fmt.Errorf("body does not match %q", check.BodyMatch)
+		}
+	}
+
+	if check.BodyJSONPath != "" {
+		var doc any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("body is not JSON: %w", err)
+		}
+		value, err := lookupJSONPath(doc, check.BodyJSONPath)
+		if err != nil {
+			return err
+		}
+		if check.BodyJSONValue != "" && jsonScalar(value) != check.BodyJSONValue {
+			return fmt.Errorf("%s is %s, expected %s", check.BodyJSONPath, jsonScalar(value), check.BodyJSONValue)
+		}
+	}
+	return nil
+}
+
+// splitJSONPath splits a JSON path of dotted keys and array indexes, like
+// $.checks[0].status or checks.0.status, into its segments.
+func splitJSONPath(path string) ([]string, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	if path == "" {
+		return nil, fmt.Errorf("empty JSON path")
+	}
+	segments := strings.Split(path, ".")
+	for _, segment := range segments {
+		if segment == "" {
+			return nil, fmt.Errorf("invalid JSON path %q", path)
+		}
+	}
+	return segments, nil
+}
+
+// lookupJSONPath returns the value at path in a decoded JSON document.
+func lookupJSONPath(doc any, path string) (any, error) {
+	segments, err := splitJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	value := doc
+	for i, segment := range segments {
+		switch node := value.(type) {
+		case map[string]any:
+			next, ok := node[segment]
+			if !ok {
+				return nil, fmt.Errorf("%s not found in body", strings.Join(segments[:i+1], "."))
+			}
+			value = next
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("%s not found in body", strings.Join(segments[:i+1], "."))
+			}
+			value = node[index]
+		default:
+			return nil, fmt.Errorf("%s not found in body", strings.Join(segments[:i+1], "."))
+		}
+	}
+	return value, nil
+}
+
+// jsonScalar formats a decoded JSON value for comparison with an expected
+// value: strings as is, other values as JSON.
+func jsonScalar(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// validateBodyAssertions checks the body assertions of a check definition.
+func validateBodyAssertions(def *CheckDefinition) error {
+	if def.BodyMatch != "" {
+		if _, err := regexp.Compile(def.BodyMatch); err != nil {
+			return fmt.Errorf("invalid body_match: %w", err)
+		}
+	}
+	if def.BodyJSONPath != "" {
+		if _, err := splitJSONPath(def.BodyJSONPath); err != nil {
+			return fmt.Errorf("invalid body_json_path: %w", err)
+		}
+	}
+	if def.BodyJSONValue != "" && def.BodyJSONPath == "" {
+		return fmt.Errorf("body_json_value requires body_json_path")
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package healthcheck
+
+import "time"
+
+// DefaultHistorySize is the number of recent results kept per check.
+const DefaultHistorySize = 10
+
+// HistoryEntry is the result of one run of a check. Status is the result
+// itself, which only moves the status of a check with flap thresholds once
+// enough results agree.
+type HistoryEntry struct {
+	Status Status    `json:"status"`
+	Output string    `json:"output"`
+	Time   time.Time `json:"time"`
+}
+
+// resultHistory is a ring buffer of the most recent results of a check.
+type resultHistory struct {
+	entries []HistoryEntry
+	next    int  // Slot the next result goes to
+	full    bool // Every slot holds a result
+}
+
+func newResultHistory(size int) *resultHistory {
+	return &resultHistory{entries: make([]HistoryEntry, size)}
+}
+
+// add records a result, overwriting the oldest one once the buffer is full.
+func (h *resultHistory) add(entry HistoryEntry) {
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % len(h.entries)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// list returns the recorded results, oldest first.
+func (h *resultHistory) list() []HistoryEntry {
+	if !h.full {
+		return append(make([]HistoryEntry, 0, h.next), h.entries[:h.next]...)
+	}
+	list := make([]HistoryEntry, 0, len(h.entries))
+	list = append(list, h.entries[h.next:]...)
+	return append(list, h.entries[:h.next]...)
+}
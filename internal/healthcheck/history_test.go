@@ -0,0 +1,268 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neogan74/konsul/internal/logger"
+)
+
+func TestResultHistory_KeepsMostRecent(t *testing.T) {
+	history := newResultHistory(3)
+	if entries := history.list(); len(entries) != 0 {
+		t.Fatalf("expected an empty history, got %v", entries)
+	}
+
+	for i, output := range []string{"a", "b", "c", "d", "e"} {
+		history.add(HistoryEntry{Status: StatusPassing, Output: output})
+		if entries := history.list(); len(entries) != min(i+1, 3) {
+			t.Fatalf("expected %d entries, got %d", min(i+1, 3), len(entries))
+		}
+	}
+
+	entries := history.list()
+	for i, want := range []string{"c", "d", "e"} {
+		if entries[i].Output != want {
+			t.Errorf("entry %d: expected %q, got %q", i, want, entries[i].Output)
+		}
+	}
+}
+
+func TestCheck_Threshold(t *testing.T) {
+	check := &Check{Status: StatusCritical, SuccessBeforePassing: 2, FailuresBeforeCritical: 3}
+
+	steps := []struct {
+		result Status
+		want   Status
+	}{
+		{StatusPassing, StatusCritical},
+		{StatusCritical, StatusCritical},
+		{StatusPassing, StatusCritical},
+		{StatusPassing, StatusPassing},
+		{StatusCritical, StatusPassing},
+		{StatusCritical, StatusPassing},
+		{StatusWarning, StatusPassing}, // Counts as a success, resetting failures
+		{StatusWarning, StatusWarning},
+		{StatusCritical, StatusWarning},
+		{StatusCritical, StatusWarning},
+		{StatusCritical, StatusCritical},
+	}
+	for i, step := range steps {
+		check.Status = check.threshold(step.result)
+		if check.Status != step.want {
+			t.Fatalf("step %d: result %s: expected status %s, got %s", i, step.result, step.want, check.Status)
+		}
+	}
+
+	// Without thresholds every result applies at once
+	check = &Check{Status: StatusPassing}
+	if status := check.threshold(StatusCritical); status != StatusCritical {
+		t.Errorf("expected critical, got %s", status)
+	}
+}
+
+func TestManager_FlapThresholds(t *testing.T) {
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	manager := NewManager(logger.GetDefault())
+	defer manager.Stop()
+
+	if _, err := manager.AddCheck(&CheckDefinition{
+		ID:                     "web",
+		HTTP:                   server.URL,
+		Interval:               "10ms",
+		SuccessBeforePassing:   3,
+		FailuresBeforeCritical: 3,
+	}); err != nil {
+		t.Fatalf("AddCheck failed: %v", err)
+	}
+	waitForStatus(t, manager, "web", StatusPassing)
+
+	history, _ := manager.History("web")
+	if len(history) < 3 {
+		t.Errorf("expected at least 3 passing results before passing, got %d", len(history))
+	}
+
+	failing.Store(true)
+	waitForStatus(t, manager, "web", StatusCritical)
+
+	history, _ = manager.History("web")
+	failures := 0
+	for i := len(history) - 1; i >= 0 && history[i].Status == StatusCritical; i-- {
+		failures++
+	}
+	if failures < 3 {
+		t.Errorf("expected at least 3 failed results before critical, got %d", failures)
+	}
+}
+
+func TestManager_History(t *testing.T) {
+	manager := NewManager(logger.GetDefault())
+	defer manager.Stop()
+
+	if _, err := manager.AddCheck(&CheckDefinition{ID: "ttl", TTL: "1h"}); err != nil {
+		t.Fatalf("AddCheck failed: %v", err)
+	}
+	for i := 0; i < DefaultHistorySize+2; i++ {
+		if err := manager.UpdateTTLCheck("ttl"); err != nil {
+			t.Fatalf("UpdateTTLCheck failed: %v", err)
+		}
+	}
+
+	history, ok := manager.History("ttl")
+	if !ok {
+		t.Fatal("expected a history for the check")
+	}
+	if len(history) != DefaultHistorySize {
+		t.Fatalf("expected %d results, got %d", DefaultHistorySize, len(history))
+	}
+	for i := 1; i < len(history); i++ {
+		if history[i].Time.Before(history[i-1].Time) {
+			t.Fatal("expected results oldest first")
+		}
+	}
+
+	if err := manager.RemoveCheck("ttl"); err != nil {
+		t.Fatalf("RemoveCheck failed: %v", err)
+	}
+	if _, ok := manager.History("ttl"); ok {
+		t.Error("expected no history for a removed check")
+	}
+}
+
+func TestManager_CriticalServices(t *testing.T) {
+	manager := NewManager(logger.GetDefault())
+	defer manager.Stop()
+
+	for _, def := range []*CheckDefinition{
+		{ID: "web-1", ServiceID: "web-1", TTL: "1h", DeregisterCriticalServiceAfter: "1m"},
+		{ID: "web-2", ServiceID: "web-2", TTL: "1h", DeregisterCriticalServiceAfter: "1m"},
+		{ID: "db", ServiceID: "db", TTL: "1h"},
+	} {
+		if _, err := manager.AddCheck(def); err != nil {
+			t.Fatalf("AddCheck failed: %v", err)
+		}
+	}
+	if err := manager.UpdateTTLCheck("web-2"); err != nil {
+		t.Fatalf("UpdateTTLCheck failed: %v", err)
+	}
+
+	if ids := manager.CriticalServices(time.Now()); len(ids) != 0 {
+		t.Errorf("expected no service critical for long enough, got %v", ids)
+	}
+	ids := manager.CriticalServices(time.Now().Add(2 * time.Minute))
+	if len(ids) != 1 || ids[0] != "web-1" {
+		t.Errorf("expected [web-1], got %v", ids)
+	}
+
+	check, _ := manager.GetCheck("web-2")
+	if !check.CriticalSince.IsZero() {
+		t.Error("expected a passing check to have no critical since time")
+	}
+}
+
+func TestManager_OnStatusChange(t *testing.T) {
+	manager := NewManager(logger.GetDefault())
+	defer manager.Stop()
+
+	type change struct {
+		id       string
+		status   Status
+		previous Status
+	}
+	var mu sync.Mutex
+	var changes []change
+	manager.OnStatusChange(func(check Check, previous Status) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, change{check.ID, check.Status, previous})
+	})
+
+	if _, err := manager.AddCheck(&CheckDefinition{ID: "ttl", TTL: "50ms"}); err != nil {
+		t.Fatalf("AddCheck failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := manager.UpdateTTLCheck("ttl"); err != nil {
+			t.Fatalf("UpdateTTLCheck failed: %v", err)
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	manager.GetCheck("ttl")
+	time.Sleep(20 * time.Millisecond) // Listeners run asynchronously
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []change{
+		{"ttl", StatusPassing, StatusCritical},
+		{"ttl", StatusCritical, StatusPassing},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %v, got %v", want, changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("change %d: expected %v, got %v", i, want[i], changes[i])
+		}
+	}
+}
+
+func TestCheckDefinition_ValidateThresholds(t *testing.T) {
+	tests := []struct {
+		name    string
+		def     CheckDefinition
+		wantErr bool
+	}{
+		{"thresholds", CheckDefinition{TTL: "10s", SuccessBeforePassing: 2, FailuresBeforeCritical: 3}, false},
+		{"negative threshold", CheckDefinition{TTL: "10s", FailuresBeforeCritical: -1}, true},
+		{"deregister after", CheckDefinition{TTL: "10s", DeregisterCriticalServiceAfter: "90s"}, false},
+		{"invalid deregister after", CheckDefinition{TTL: "10s", DeregisterCriticalServiceAfter: "soon"}, true},
+		{"zero deregister after", CheckDefinition{TTL: "10s", DeregisterCriticalServiceAfter: "0s"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.def.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestManager_RemoveServiceChecks(t *testing.T) {
+	manager := NewManager(logger.GetDefault())
+	defer manager.Stop()
+
+	for _, def := range []*CheckDefinition{
+		{ID: "web-ttl", ServiceID: "web", TTL: "1h"},
+		{ID: "web-http", ServiceID: "web", HTTP: "http://127.0.0.1:1", Interval: "1h"},
+		{ID: "db-ttl", ServiceID: "db", TTL: "1h"},
+	} {
+		if _, err := manager.AddCheck(def); err != nil {
+			t.Fatalf("AddCheck failed: %v", err)
+		}
+	}
+
+	if removed := manager.RemoveServiceChecks("web"); removed != 2 {
+		t.Errorf("expected 2 checks removed, got %d", removed)
+	}
+	if checks := manager.ListChecks(); len(checks) != 1 || checks[0].ID != "db-ttl" {
+		t.Errorf("expected only db-ttl left, got %d checks", len(checks))
+	}
+	manager.mutex.RLock()
+	scheduled := len(manager.queue)
+	manager.mutex.RUnlock()
+	if scheduled != 0 {
+		t.Errorf("expected no scheduled check, got %d", scheduled)
+	}
+}
@@ -8,21 +8,32 @@ import (
 	"time"
 )
 
+// defaultHTTPTimeout is the timeout of the HTTP checks that have none.
+const defaultHTTPTimeout = 10 * time.Second
+
+// HTTPChecker probes HTTP checks. It is safe for concurrent use: checks
+// with TLSSkipVerify use a client of their own, and the timeout of a check
+// applies to its request only.
 type HTTPChecker struct {
-	client *http.Client
+	client         *http.Client
+	insecureClient *http.Client // Client of the checks with TLSSkipVerify
 }
 
 func NewHTTPChecker() *HTTPChecker {
 	return &HTTPChecker{
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: false,
-				},
-				DisableKeepAlives:   true,
-				MaxIdleConnsPerHost: 1,
+		client:         newHTTPCheckClient(false),
+		insecureClient: newHTTPCheckClient(true),
+	}
+}
+
+func newHTTPCheckClient(skipVerify bool) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: skipVerify,
 			},
+			DisableKeepAlives:   true,
+			MaxIdleConnsPerHost: 1,
 		},
 	}
 }
@@ -32,6 +43,14 @@ func (h *HTTPChecker) Check(ctx context.Context, check *Check) (Status, string,
 		return StatusCritical, "HTTP URL not specified", fmt.Errorf("HTTP URL required")
 	}
 
+	// Set timeout from check
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	// Create request with context for timeout
 	method := check.Method
 	if method == "" {
@@ -54,18 +73,13 @@ func (h *HTTPChecker) Check(ctx context.Context, check *Check) (Status, string,
 	}
 
 	// Configure TLS
+	client := h.client
 	if check.TLSSkipVerify {
-		transport := h.client.Transport.(*http.Transport)
-		transport.TLSClientConfig.InsecureSkipVerify = true
-	}
-
-	// Set timeout from check
-	if check.Timeout > 0 {
-		h.client.Timeout = check.Timeout
+		client = h.insecureClient
 	}
 
 	start := time.Now()
-	resp, err := h.client.Do(req)
+	resp, err := client.Do(req)
 	duration := time.Since(start)
 
 	if err != nil {
@@ -81,6 +95,9 @@ func (h *HTTPChecker) Check(ctx context.Context, check *Check) (Status, string,
 	output := fmt.Sprintf("HTTP %d %s (%.3fs)", resp.StatusCode, resp.Status, duration.Seconds())
 
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if err := assertBody(check, resp.Body); err != nil {
+			return StatusCritical, fmt.Sprintf("%s: %v", output, err), err
+		}
 		return StatusPassing, output, nil
 	} else if resp.StatusCode >= 300 && resp.StatusCode < 400 {
 		return StatusWarning, output, nil
@@ -296,3 +296,71 @@ func stringContains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestHTTPChecker_Check_BodyAssertions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/text" {
+			_, _ = w.Write([]byte("status: degraded"))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status": "ok", "checks": [{"name": "db", "up": true, "latency": 1.5}]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		check  Check
+		status Status
+	}{
+		{"regex matches", Check{BodyMatch: `"status":\s*"ok"`}, StatusPassing},
+		{"regex does not match", Check{BodyMatch: `"status":\s*"down"`}, StatusCritical},
+		{"path exists", Check{BodyJSONPath: "$.checks[0].name"}, StatusPassing},
+		{"path missing", Check{BodyJSONPath: "$.checks[1].name"}, StatusCritical},
+		{"string value", Check{BodyJSONPath: "status", BodyJSONValue: "ok"}, StatusPassing},
+		{"bool value", Check{BodyJSONPath: "checks.0.up", BodyJSONValue: "true"}, StatusPassing},
+		{"number value", Check{BodyJSONPath: "$.checks[0].latency", BodyJSONValue: "1.5"}, StatusPassing},
+		{"wrong value", Check{BodyJSONPath: "status", BodyJSONValue: "down"}, StatusCritical},
+		{"body not JSON", Check{HTTP: server.URL + "/text", BodyJSONPath: "status"}, StatusCritical},
+	}
+
+	checker := NewHTTPChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := tt.check
+			if check.HTTP == "" {
+				check.HTTP = server.URL
+			}
+			check.Timeout = 5 * time.Second
+
+			status, output, err := checker.Check(context.Background(), &check)
+			if status != tt.status {
+				t.Errorf("expected %s, got %s: %s", tt.status, status, output)
+			}
+			if (status == StatusCritical) != (err != nil) {
+				t.Errorf("expected an error with critical status only, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckDefinition_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		def     CheckDefinition
+		wantErr bool
+	}{
+		{"http with assertions", CheckDefinition{HTTP: "http://x", BodyMatch: "ok", BodyJSONPath: "$.a", BodyJSONValue: "b"}, false},
+		{"invalid regex", CheckDefinition{HTTP: "http://x", BodyMatch: "("}, true},
+		{"empty path", CheckDefinition{HTTP: "http://x", BodyJSONPath: "$"}, true},
+		{"value without path", CheckDefinition{HTTP: "http://x", BodyJSONValue: "ok"}, true},
+		{"assertions on tcp check", CheckDefinition{TCP: "localhost:80", BodyMatch: "ok"}, true},
+		{"script", CheckDefinition{Args: []string{"true"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.def.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,12 +4,18 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/neogan74/konsul/internal/blocking"
+	"github.com/neogan74/konsul/internal/changelog"
 	"github.com/neogan74/konsul/internal/logger"
 )
 
+// Manager runs health checks. Probes of HTTP, TCP and gRPC checks run on a
+// bounded pool of workers, outside the manager's lock, so that slow targets
+// neither block reads nor delay the other checks.
 type Manager struct {
 	checks map[string]*Check
 	mutex  sync.RWMutex
@@ -18,50 +24,143 @@ type Manager struct {
 	cancel context.CancelFunc
 	stopCh chan struct{}
 
-	httpChecker *HTTPChecker
-	tcpChecker  *TCPChecker
-	grpcChecker *GRPCChecker
+	httpChecker   *HTTPChecker
+	tcpChecker    *TCPChecker
+	grpcChecker   *GRPCChecker
+	udpChecker    *UDPChecker
+	scriptChecker *ScriptChecker
+	scriptChecks  bool // Script checks may be added
+
+	scheduled map[string]*scheduledCheck // Scheduling state of probed checks
+	queue     checkQueue                 // Probed checks by next probe
+	wake      chan struct{}              // Wakes the scheduler when a check is queued
+	jobs      chan *scheduledCheck       // Due checks handed to the workers
+	workers   int
+	startOnce sync.Once
+
+	history    map[string]*resultHistory // Recent results of each check
+	onChange   StatusListener            // Called when a check changes status
+	statusCh   chan statusChange         // Status changes waiting for onChange
+	listenOnce sync.Once
+
+	index     uint64            // Advances when a check is added, removed or changes status
+	changes   blocking.Notifier // Wakes blocking queries when the index changes
+	changeLog *changelog.Log    // Checks changed at recent indexes, for delta syncs
 }
 
+// StatusListener is called when a check changes status, with a copy of the
+// check and its previous status. Listeners run one change at a time, in
+// order, outside the manager's lock.
+type StatusListener func(check Check, previous Status)
+
+// statusChangeBuffer bounds the status changes waiting for the listener;
+// changes beyond it are dropped.
+const statusChangeBuffer = 1024
+
+// statusChange is a check status change waiting for the status listener.
+type statusChange struct {
+	check    Check
+	previous Status
+}
+
+// NewManager creates a manager that runs up to DefaultWorkers probes at once.
 func NewManager(log logger.Logger) *Manager {
+	return NewManagerWithWorkers(log, DefaultWorkers)
+}
+
+// NewManagerWithWorkers creates a manager that runs up to workers probes at
+// once. The workers start with the first probed check.
+func NewManagerWithWorkers(log logger.Logger, workers int) *Manager {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Manager{
-		checks:      make(map[string]*Check),
-		log:         log,
-		ctx:         ctx,
-		cancel:      cancel,
-		stopCh:      make(chan struct{}),
-		httpChecker: NewHTTPChecker(),
-		tcpChecker:  NewTCPChecker(),
-		grpcChecker: NewGRPCChecker(),
+		checks:        make(map[string]*Check),
+		log:           log,
+		ctx:           ctx,
+		cancel:        cancel,
+		stopCh:        make(chan struct{}),
+		httpChecker:   NewHTTPChecker(),
+		tcpChecker:    NewTCPChecker(),
+		grpcChecker:   NewGRPCChecker(),
+		udpChecker:    NewUDPChecker(),
+		scriptChecker: NewScriptChecker(),
+		scheduled:     make(map[string]*scheduledCheck),
+		wake:          make(chan struct{}, 1),
+		jobs:          make(chan *scheduledCheck),
+		workers:       workers,
+		history:       make(map[string]*resultHistory),
+		statusCh:      make(chan statusChange, statusChangeBuffer),
+		changeLog:     changelog.New(changelog.DefaultCapacity),
+	}
+}
+
+// EnableScriptChecks allows or forbids adding script checks. Script checks
+// run commands on the host and are forbidden by default; checks already
+// added keep running.
+func (m *Manager) EnableScriptChecks(enabled bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.scriptChecks = enabled
+}
+
+// OnStatusChange sets the listener called when a check changes status.
+func (m *Manager) OnStatusChange(listener StatusListener) {
+	m.mutex.Lock()
+	m.onChange = listener
+	m.mutex.Unlock()
+
+	m.listenOnce.Do(func() { go m.runListener() })
+}
+
+// runListener hands status changes to the listener until the manager stops.
+func (m *Manager) runListener() {
+	for {
+		select {
+		case change := <-m.statusCh:
+			m.mutex.RLock()
+			listener := m.onChange
+			m.mutex.RUnlock()
+			if listener != nil {
+				listener(change.check, change.previous)
+			}
+		case <-m.ctx.Done():
+			return
+		}
 	}
 }
 
+// ScriptChecksEnabled reports whether script checks may be added.
+func (m *Manager) ScriptChecksEnabled() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.scriptChecks
+}
+
 func (m *Manager) AddCheck(def *CheckDefinition) (*Check, error) {
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	// Determine check type
+	checkType := def.Type()
+	if checkType == CheckTypeScript && !m.scriptChecks {
+		return nil, ErrScriptChecksDisabled
+	}
+
 	// Generate ID if not provided
 	if def.ID == "" {
 		def.ID = uuid.New().String()
 	}
 
-	// Determine check type
-	checkType := CheckTypeTTL
-	if def.HTTP != "" {
-		checkType = CheckTypeHTTP
-	} else if def.TCP != "" {
-		checkType = CheckTypeTCP
-	} else if def.GRPC != "" {
-		checkType = CheckTypeGRPC
-	} else if def.TTL != "" {
-		checkType = CheckTypeTTL
-	}
-
 	// Parse durations
 	interval := 30 * time.Second
 	if def.Interval != "" {
-		if parsed, err := time.ParseDuration(def.Interval); err == nil {
+		if parsed, err := time.ParseDuration(def.Interval); err == nil && parsed > 0 {
 			interval = parsed
 		}
 	}
@@ -80,6 +179,12 @@ func (m *Manager) AddCheck(def *CheckDefinition) (*Check, error) {
 		}
 	}
 
+	var deregisterAfter time.Duration
+	if def.DeregisterCriticalServiceAfter != "" {
+		deregisterAfter, _ = time.ParseDuration(def.DeregisterCriticalServiceAfter)
+	}
+
+	now := time.Now()
 	check := &Check{
 		ID:            def.ID,
 		Name:          def.Name,
@@ -92,11 +197,22 @@ func (m *Manager) AddCheck(def *CheckDefinition) (*Check, error) {
 		Method:        def.Method,
 		Headers:       def.Headers,
 		TLSSkipVerify: def.TLSSkipVerify,
+		BodyMatch:     def.BodyMatch,
+		BodyJSONPath:  def.BodyJSONPath,
+		BodyJSONValue: def.BodyJSONValue,
 		TCP:           def.TCP,
 		GRPC:          def.GRPC,
 		GRPCUseTLS:    def.GRPCUseTLS,
+		UDP:           def.UDP,
+		UDPPayload:    def.UDPPayload,
+		Args:          def.Args,
 		TTL:           ttl,
-		LastCheck:     time.Now(),
+		LastCheck:     now,
+
+		SuccessBeforePassing:           def.SuccessBeforePassing,
+		FailuresBeforeCritical:         def.FailuresBeforeCritical,
+		DeregisterCriticalServiceAfter: deregisterAfter,
+		CriticalSince:                  now,
 	}
 
 	if checkType == CheckTypeTTL && ttl > 0 {
@@ -104,10 +220,15 @@ func (m *Manager) AddCheck(def *CheckDefinition) (*Check, error) {
 	}
 
 	m.checks[check.ID] = check
+	m.history[check.ID] = newResultHistory(DefaultHistorySize)
+	m.touch(check.ID)
 
-	// Start monitoring for non-TTL checks
+	// Probe non-TTL checks; a check replacing one with the same ID takes
+	// over its schedule
 	if checkType != CheckTypeTTL {
-		go m.runCheck(check)
+		m.scheduleCheck(check)
+	} else {
+		m.unscheduleCheck(check.ID)
 	}
 
 	m.log.Info("Health check added",
@@ -116,12 +237,14 @@ func (m *Manager) AddCheck(def *CheckDefinition) (*Check, error) {
 		logger.String("type", string(checkType)),
 		logger.String("service", check.ServiceID))
 
-	return check, nil
+	added := *check
+	return &added, nil
 }
 
+// GetCheck returns a copy of the check id.
 func (m *Manager) GetCheck(id string) (*Check, bool) {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 
 	check, exists := m.checks[id]
 	if !exists {
@@ -129,33 +252,222 @@ func (m *Manager) GetCheck(id string) (*Check, bool) {
 	}
 
 	// For TTL checks, verify if they're still valid
-	if check.Type == CheckTypeTTL && !check.ExpiresAt.IsZero() && check.ExpiresAt.Before(time.Now()) {
-		check.Status = StatusCritical
-		check.Output = "TTL expired"
-	}
+	m.expireTTLCheck(check, time.Now())
 
-	return check, true
+	found := *check
+	return &found, true
 }
 
+// ListChecks returns copies of all checks.
 func (m *Manager) ListChecks() []*Check {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 
 	checks := make([]*Check, 0, len(m.checks))
 	now := time.Now()
 
 	for _, check := range m.checks {
 		// Update TTL check status
-		if check.Type == CheckTypeTTL && !check.ExpiresAt.IsZero() && check.ExpiresAt.Before(now) {
-			check.Status = StatusCritical
-			check.Output = "TTL expired"
-		}
-		checks = append(checks, check)
+		m.expireTTLCheck(check, now)
+		listed := *check
+		checks = append(checks, &listed)
 	}
 
 	return checks
 }
 
+// History returns the recent results of the check id, oldest first.
+func (m *Manager) History(id string) ([]HistoryEntry, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	check, exists := m.checks[id]
+	if !exists {
+		return nil, false
+	}
+	m.expireTTLCheck(check, time.Now())
+	return m.history[id].list(), true
+}
+
+// CriticalServices returns the IDs of the services with a check that has
+// been critical for longer than its DeregisterCriticalServiceAfter at now.
+func (m *Manager) CriticalServices(now time.Time) []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, check := range m.checks {
+		m.expireTTLCheck(check, now)
+		if check.DeregisterCriticalServiceAfter <= 0 || check.Status != StatusCritical || seen[check.ServiceID] {
+			continue
+		}
+		if now.Sub(check.CriticalSince) > check.DeregisterCriticalServiceAfter {
+			seen[check.ServiceID] = true
+			ids = append(ids, check.ServiceID)
+		}
+	}
+	return ids
+}
+
+// ServiceStatuses returns the aggregated status of the checks of every
+// service ID that has checks. See AggregateStatus.
+func (m *Manager) ServiceStatuses() map[string]Status {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	statuses := make(map[string]Status)
+	now := time.Now()
+	for _, check := range m.checks {
+		m.expireTTLCheck(check, now)
+		if current, ok := statuses[check.ServiceID]; ok {
+			statuses[check.ServiceID] = AggregateStatus(current, check.Status)
+		} else {
+			statuses[check.ServiceID] = AggregateStatus(check.Status)
+		}
+	}
+	return statuses
+}
+
+// Index returns the current check index.
+func (m *Manager) Index() uint64 {
+	return atomic.LoadUint64(&m.index)
+}
+
+// WaitForIndex blocks until the check index differs from index, wait elapses
+// or ctx is done, and returns the current index. A TTL check running out
+// while waiting counts as a status change.
+func (m *Manager) WaitForIndex(ctx context.Context, index uint64, wait time.Duration) uint64 {
+	if index == 0 {
+		return m.Index()
+	}
+
+	deadline := time.Now().Add(wait)
+	for {
+		// Wake up in time to notice the next TTL check running out
+		timeout := time.Until(deadline)
+		if next := m.expireTTLChecks(time.Now()); !next.IsZero() {
+			timeout = min(timeout, time.Until(next))
+		}
+
+		current := m.changes.Wait(ctx, index, timeout, m.Index)
+		if current != index || ctx.Err() != nil || !time.Now().Before(deadline) {
+			return current
+		}
+	}
+}
+
+// expireTTLChecks marks every TTL check that ran out at now as critical and
+// returns the earliest expiry still ahead, or the zero time if there is none.
+func (m *Manager) expireTTLChecks(now time.Time) time.Time {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var next time.Time
+	for _, check := range m.checks {
+		if m.expireTTLCheck(check, now) || check.Type != CheckTypeTTL || check.ExpiresAt.IsZero() {
+			continue
+		}
+		if next.IsZero() || check.ExpiresAt.Before(next) {
+			next = check.ExpiresAt
+		}
+	}
+	return next
+}
+
+// expireTTLCheck marks a TTL check as critical once it has run out and
+// reports whether it has. Callers must hold m.mutex.
+func (m *Manager) expireTTLCheck(check *Check, now time.Time) bool {
+	if check.Type != CheckTypeTTL || check.ExpiresAt.IsZero() || !check.ExpiresAt.Before(now) {
+		return false
+	}
+	if check.Status != StatusCritical {
+		m.record(check, StatusCritical, "TTL expired", now)
+	}
+	m.setStatus(check, StatusCritical, now)
+	check.Output = "TTL expired"
+	return true
+}
+
+// record adds a result to the history of check. Callers must hold m.mutex.
+func (m *Manager) record(check *Check, status Status, output string, at time.Time) {
+	if history, ok := m.history[check.ID]; ok {
+		history.add(HistoryEntry{Status: status, Output: output, Time: at})
+	}
+}
+
+// setStatus moves check to status, tracking since when it is critical, and
+// reports a change to blocking queries and the status listener. Callers must
+// hold m.mutex.
+func (m *Manager) setStatus(check *Check, status Status, at time.Time) {
+	if check.Status == status {
+		return
+	}
+	previous := check.Status
+	check.Status = status
+	if status == StatusCritical {
+		check.CriticalSince = at
+	} else {
+		check.CriticalSince = time.Time{}
+	}
+	m.touch(check.ID)
+	if m.onChange == nil {
+		return
+	}
+	select {
+	case m.statusCh <- statusChange{check: *check, previous: previous}:
+	default:
+		m.log.Warn("Status listener is behind, dropping a check status change",
+			logger.String("id", check.ID),
+			logger.String("status", string(status)))
+	}
+}
+
+// touch advances the check index, records it as a change of the check id and
+// wakes blocking queries. Callers must hold m.mutex.
+func (m *Manager) touch(id string) {
+	index := atomic.AddUint64(&m.index, 1)
+	m.changeLog.Record(index, id)
+	m.changes.Notify()
+}
+
+// ResetIndex moves the check index forward to index, for a manager that
+// replaces another one. Changes up to index are unknown to ChangesSince.
+func (m *Manager) ResetIndex(index uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	atomic.StoreUint64(&m.index, index)
+	m.changeLog.Reset(index)
+	m.changes.Notify()
+}
+
+// ChangesSince returns copies of the checks added or changed after index, the
+// IDs of the checks removed since, and the index they bring a reader up to.
+// ok is false when the change log no longer covers index; the reader then
+// has to list all checks instead.
+func (m *Manager) ChangesSince(index uint64) (changed []Check, removed []string, last uint64, ok bool) {
+	// TTL checks that ran out unobserved count as changed
+	m.expireTTLChecks(time.Now())
+
+	ids, last, ok := m.changeLog.Since(index)
+	if !ok {
+		return nil, nil, last, false
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for _, id := range ids {
+		if check, exists := m.checks[id]; exists {
+			changed = append(changed, *check)
+		} else {
+			removed = append(removed, id)
+		}
+	}
+	return changed, removed, last, true
+}
+
 func (m *Manager) UpdateTTLCheck(id string) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -169,11 +481,14 @@ func (m *Manager) UpdateTTLCheck(id string) error {
 		return fmt.Errorf("check is not a TTL check")
 	}
 
-	check.Status = StatusPassing
+	now := time.Now()
+	m.expireTTLCheck(check, now)
+	m.record(check, StatusPassing, "TTL check passed", now)
+	m.setStatus(check, StatusPassing, now)
 	check.Output = "TTL check passed"
-	check.LastCheck = time.Now()
+	check.LastCheck = now
 	if check.TTL > 0 {
-		check.ExpiresAt = time.Now().Add(check.TTL)
+		check.ExpiresAt = now.Add(check.TTL)
 	}
 
 	m.log.Info("TTL check updated",
@@ -192,75 +507,32 @@ func (m *Manager) RemoveCheck(id string) error {
 	}
 
 	delete(m.checks, id)
+	delete(m.history, id)
+	m.unscheduleCheck(id)
+	m.touch(id)
 
 	m.log.Info("Health check removed", logger.String("id", id))
 	return nil
 }
 
-func (m *Manager) runCheck(check *Check) {
-	ticker := time.NewTicker(check.Interval)
-	defer ticker.Stop()
-
-	// Run initial check
-	m.performCheck(check)
-
-	for {
-		select {
-		case <-ticker.C:
-			m.performCheck(check)
-		case <-m.ctx.Done():
-			return
-		}
-	}
-}
-
-func (m *Manager) performCheck(check *Check) {
+// RemoveServiceChecks removes the checks of the service serviceID and
+// returns how many there were.
+func (m *Manager) RemoveServiceChecks(serviceID string) int {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	// Check if the check still exists (might have been removed)
-	if _, exists := m.checks[check.ID]; !exists {
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(m.ctx, check.Timeout)
-	defer cancel()
-
-	var status Status
-	var output string
-	var err error
-
-	switch check.Type {
-	case CheckTypeHTTP:
-		status, output, err = m.httpChecker.Check(ctx, check)
-	case CheckTypeTCP:
-		status, output, err = m.tcpChecker.Check(ctx, check)
-	case CheckTypeGRPC:
-		status, output, err = m.grpcChecker.Check(ctx, check)
-	default:
-		status = StatusCritical
-		output = fmt.Sprintf("Unknown check type: %s", check.Type)
-		err = fmt.Errorf("unknown check type")
-	}
-
-	check.Status = status
-	check.Output = output
-	check.LastCheck = time.Now()
-
-	if err != nil {
-		m.log.Warn("Health check failed",
-			logger.String("id", check.ID),
-			logger.String("name", check.Name),
-			logger.String("type", string(check.Type)),
-			logger.String("status", string(status)),
-			logger.Error(err))
-	} else {
-		m.log.Debug("Health check completed",
-			logger.String("id", check.ID),
-			logger.String("name", check.Name),
-			logger.String("type", string(check.Type)),
-			logger.String("status", string(status)))
+	removed := 0
+	for id, check := range m.checks {
+		if check.ServiceID != serviceID {
+			continue
+		}
+		delete(m.checks, id)
+		delete(m.history, id)
+		m.unscheduleCheck(id)
+		m.touch(id)
+		removed++
 	}
+	return removed
 }
 
 func (m *Manager) Stop() {
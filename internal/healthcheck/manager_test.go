@@ -1,6 +1,7 @@
 package healthcheck
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -450,6 +451,48 @@ func TestManager_TTLCheckExpiration(t *testing.T) {
 	}
 }
 
+func TestManager_WaitForIndex(t *testing.T) {
+	log := logger.GetDefault()
+	manager := NewManager(log)
+	defer manager.Stop()
+
+	if _, err := manager.AddCheck(&CheckDefinition{ID: "ttl-check", Name: "ttl", TTL: "200ms"}); err != nil {
+		t.Fatalf("AddCheck failed: %v", err)
+	}
+	index := manager.Index()
+	if index == 0 {
+		t.Fatal("expected adding a check to advance the index")
+	}
+
+	// Passing the TTL check changes its status from critical
+	if err := manager.UpdateTTLCheck("ttl-check"); err != nil {
+		t.Fatalf("UpdateTTLCheck failed: %v", err)
+	}
+	if got := manager.WaitForIndex(context.Background(), index, time.Second); got == index {
+		t.Fatal("expected status change to advance the index")
+	}
+
+	// Passing again is not a change
+	index = manager.Index()
+	_ = manager.UpdateTTLCheck("ttl-check")
+	if manager.Index() != index {
+		t.Error("expected repeated pass to leave the index alone")
+	}
+
+	// The TTL running out wakes a blocking query
+	start := time.Now()
+	if got := manager.WaitForIndex(context.Background(), index, 5*time.Second); got == index {
+		t.Fatal("expected TTL expiry to advance the index")
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Error("expected wait to return when the TTL ran out")
+	}
+	check, _ := manager.GetCheck("ttl-check")
+	if check.Status != StatusCritical {
+		t.Errorf("expected check to be critical, got %s", check.Status)
+	}
+}
+
 func TestManager_Stop(t *testing.T) {
 	log := logger.GetDefault()
 	manager := NewManager(log)
@@ -530,3 +573,73 @@ func TestManager_CheckTypePrecedence(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_ChangesSince(t *testing.T) {
+	log := logger.GetDefault()
+	manager := NewManager(log)
+	defer manager.Stop()
+
+	for _, id := range []string{"ttl-1", "ttl-2"} {
+		if _, err := manager.AddCheck(&CheckDefinition{ID: id, Name: id, TTL: "60s"}); err != nil {
+			t.Fatalf("AddCheck failed: %v", err)
+		}
+	}
+	index := manager.Index()
+
+	if err := manager.UpdateTTLCheck("ttl-1"); err != nil {
+		t.Fatalf("UpdateTTLCheck failed: %v", err)
+	}
+	if err := manager.RemoveCheck("ttl-2"); err != nil {
+		t.Fatalf("RemoveCheck failed: %v", err)
+	}
+
+	changed, removed, last, ok := manager.ChangesSince(index)
+	if !ok {
+		t.Fatal("expected the change log to cover the index")
+	}
+	if len(changed) != 1 || changed[0].ID != "ttl-1" || changed[0].Status != StatusPassing {
+		t.Errorf("expected ttl-1 passing, got %+v", changed)
+	}
+	if len(removed) != 1 || removed[0] != "ttl-2" {
+		t.Errorf("expected ttl-2 removed, got %v", removed)
+	}
+	if last != manager.Index() {
+		t.Errorf("expected last index %d, got %d", manager.Index(), last)
+	}
+
+	// A replacement manager carries the index on and reports earlier
+	// indexes as no longer covered
+	replacement := NewManager(log)
+	defer replacement.Stop()
+	replacement.ResetIndex(last + 1)
+	if _, _, _, ok := replacement.ChangesSince(last); ok {
+		t.Error("expected an index from before the reset to be not covered")
+	}
+}
+
+func TestAggregateStatus(t *testing.T) {
+	tests := []struct {
+		statuses []Status
+		want     Status
+	}{
+		{nil, StatusPassing},
+		{[]Status{StatusPassing, StatusPassing}, StatusPassing},
+		{[]Status{StatusPassing, StatusWarning}, StatusWarning},
+		{[]Status{StatusWarning, StatusCritical, StatusPassing}, StatusCritical},
+		{[]Status{"unknown"}, StatusCritical},
+	}
+	for _, tt := range tests {
+		if got := AggregateStatus(tt.statuses...); got != tt.want {
+			t.Errorf("AggregateStatus(%v) = %s, want %s", tt.statuses, got, tt.want)
+		}
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	if status, err := ParseStatus(" Warning "); err != nil || status != StatusWarning {
+		t.Errorf("ParseStatus() = %s, %v, want warning", status, err)
+	}
+	if _, err := ParseStatus("healthy"); err == nil {
+		t.Error("ParseStatus() expected error for unknown status")
+	}
+}
@@ -0,0 +1,244 @@
+package healthcheck
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/neogan74/konsul/internal/logger"
+)
+
+const (
+	// DefaultWorkers is the number of probes a manager runs at once.
+	DefaultWorkers = 256
+
+	// maxInitialStagger bounds the random delay of the first probe of a
+	// check, which spreads the probes of checks added together.
+	maxInitialStagger = time.Second
+
+	// maxProbeJitter bounds the random delay of every later probe, which
+	// is also at most a tenth of the interval. It keeps checks that end up
+	// due together, such as after a restart, from staying in step.
+	maxProbeJitter = 500 * time.Millisecond
+
+	// idleWait is how long the scheduler sleeps when no check is scheduled.
+	idleWait = time.Hour
+)
+
+// scheduledCheck is the scheduling state of a probed (non-TTL) check. It is
+// guarded by the manager's mutex.
+type scheduledCheck struct {
+	check   *Check
+	slot    time.Time // When the next probe is due before jitter
+	next    time.Time // When the next probe is due
+	running bool      // A worker is probing the check
+	index   int       // Position in the queue; -1 once unscheduled
+}
+
+// checkQueue orders scheduled checks by their next probe, as a heap.
+type checkQueue []*scheduledCheck
+
+func (q checkQueue) Len() int           { return len(q) }
+func (q checkQueue) Less(i, j int) bool { return q[i].next.Before(q[j].next) }
+
+func (q checkQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *checkQueue) Push(x any) {
+	sc := x.(*scheduledCheck)
+	sc.index = len(*q)
+	*q = append(*q, sc)
+}
+
+func (q *checkQueue) Pop() any {
+	old := *q
+	sc := old[len(old)-1]
+	old[len(old)-1] = nil
+	sc.index = -1
+	*q = old[:len(old)-1]
+	return sc
+}
+
+// scheduleCheck queues the first probe of check after a random stagger and
+// starts the scheduler and the workers on first use. Callers must hold
+// m.mutex.
+func (m *Manager) scheduleCheck(check *Check) {
+	m.startOnce.Do(func() {
+		go m.runScheduler()
+		for i := 0; i < m.workers; i++ {
+			go m.runWorker()
+		}
+	})
+
+	m.unscheduleCheck(check.ID)
+	first := time.Now().Add(rand.N(min(check.Interval, maxInitialStagger)))
+	sc := &scheduledCheck{check: check, slot: first, next: first}
+	heap.Push(&m.queue, sc)
+	m.scheduled[check.ID] = sc
+
+	// Wake the scheduler in case the check is due before its next wakeup
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// unscheduleCheck stops probing the check id. A probe already running is
+// discarded when it completes. Callers must hold m.mutex.
+func (m *Manager) unscheduleCheck(id string) {
+	sc, ok := m.scheduled[id]
+	if !ok {
+		return
+	}
+	if sc.index >= 0 {
+		heap.Remove(&m.queue, sc.index)
+	}
+	delete(m.scheduled, id)
+}
+
+// runScheduler hands due checks to the workers until the manager stops.
+// Checks are probed at a fixed rate: a check whose probe is still running
+// when the next one is due skips that turn, so slow targets never pile up
+// probes nor delay other checks beyond the capacity of the worker pool.
+func (m *Manager) runScheduler() {
+	timer := time.NewTimer(idleWait)
+	defer timer.Stop()
+
+	for {
+		due, wait := m.dueChecks(time.Now())
+		for _, sc := range due {
+			select {
+			case m.jobs <- sc:
+			case <-m.ctx.Done():
+				return
+			}
+		}
+		if len(due) > 0 {
+			// Handing checks over may have blocked on busy workers
+			continue
+		}
+
+		timer.Reset(wait)
+		select {
+		case <-timer.C:
+		case <-m.wake:
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// dueChecks reschedules the checks due at now and returns the ones to probe,
+// along with the time until the next check is due. Slots keep the interval
+// between them; each probe is delayed from its slot by a new jitter.
+func (m *Manager) dueChecks(now time.Time) ([]*scheduledCheck, time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var due []*scheduledCheck
+	for len(m.queue) > 0 {
+		sc := m.queue[0]
+		if sc.next.After(now) {
+			return due, sc.next.Sub(now)
+		}
+
+		interval := sc.check.Interval
+		sc.slot = sc.slot.Add((now.Sub(sc.slot)/interval + 1) * interval)
+		sc.next = sc.slot.Add(probeJitter(interval))
+		heap.Fix(&m.queue, 0)
+
+		if !sc.running {
+			sc.running = true
+			due = append(due, sc)
+		}
+	}
+	return due, idleWait
+}
+
+// probeJitter returns a random delay for a probe of a check run every
+// interval.
+func probeJitter(interval time.Duration) time.Duration {
+	bound := min(interval/10, maxProbeJitter)
+	if bound <= 0 {
+		return 0
+	}
+	return rand.N(bound)
+}
+
+// runWorker probes the checks handed over by the scheduler until the manager
+// stops.
+func (m *Manager) runWorker() {
+	for {
+		select {
+		case sc := <-m.jobs:
+			m.probe(sc)
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// probe runs a check against its target without holding the manager's lock,
+// then records the result unless the check was removed or replaced meanwhile.
+// The status of the check follows the result once its flap thresholds are
+// met; the output always does.
+func (m *Manager) probe(sc *scheduledCheck) {
+	m.mutex.RLock()
+	check := *sc.check
+	m.mutex.RUnlock()
+
+	ctx, cancel := context.WithTimeout(m.ctx, check.Timeout)
+	status, output, err := m.runChecker(ctx, &check)
+	cancel()
+
+	m.mutex.Lock()
+	sc.running = false
+	if m.checks[check.ID] != sc.check {
+		m.mutex.Unlock()
+		return
+	}
+	now := time.Now()
+	m.record(sc.check, status, output, now)
+	m.setStatus(sc.check, sc.check.threshold(status), now)
+	sc.check.Output = output
+	sc.check.LastCheck = now
+	m.mutex.Unlock()
+
+	if err != nil {
+		m.log.Warn("Health check failed",
+			logger.String("id", check.ID),
+			logger.String("name", check.Name),
+			logger.String("type", string(check.Type)),
+			logger.String("status", string(status)),
+			logger.Error(err))
+	} else {
+		m.log.Debug("Health check completed",
+			logger.String("id", check.ID),
+			logger.String("name", check.Name),
+			logger.String("type", string(check.Type)),
+			logger.String("status", string(status)))
+	}
+}
+
+// runChecker probes check with the checker of its type.
+func (m *Manager) runChecker(ctx context.Context, check *Check) (Status, string, error) {
+	switch check.Type {
+	case CheckTypeHTTP:
+		return m.httpChecker.Check(ctx, check)
+	case CheckTypeTCP:
+		return m.tcpChecker.Check(ctx, check)
+	case CheckTypeGRPC:
+		return m.grpcChecker.Check(ctx, check)
+	case CheckTypeUDP:
+		return m.udpChecker.Check(ctx, check)
+	case CheckTypeScript:
+		return m.scriptChecker.Check(ctx, check)
+	default:
+		return StatusCritical, fmt.Sprintf("Unknown check type: %s", check.Type), fmt.Errorf("unknown check type")
+	}
+}
@@ -0,0 +1,280 @@
+package healthcheck
+
+import (
+	"container/heap"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neogan74/konsul/internal/logger"
+)
+
+// probeRecorder is an HTTP target that records when each path is probed.
+type probeRecorder struct {
+	mu     sync.Mutex
+	probes map[string][]time.Time
+}
+
+func newProbeRecorder() *probeRecorder {
+	return &probeRecorder{probes: make(map[string][]time.Time)}
+}
+
+func (p *probeRecorder) record(path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.probes[path] = append(p.probes[path], time.Now())
+}
+
+func (p *probeRecorder) count(path string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.probes[path])
+}
+
+// maxGap returns the longest time between two consecutive probes of a path.
+func (p *probeRecorder) maxGap() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var worst time.Duration
+	for _, times := range p.probes {
+		for i := 1; i < len(times); i++ {
+			worst = max(worst, times[i].Sub(times[i-1]))
+		}
+	}
+	return worst
+}
+
+func waitForStatus(t *testing.T, manager *Manager, id string, status Status) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if check, ok := manager.GetCheck(id); ok && check.Status == status {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("check %s did not become %s", id, status)
+}
+
+func TestManager_SlowProbeDoesNotBlockOtherChecks(t *testing.T) {
+	release := make(chan struct{})
+	recorder := newProbeRecorder()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder.record(r.URL.Path)
+		if r.URL.Path == "/slow" {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	manager := NewManagerWithWorkers(logger.GetDefault(), 4)
+	defer manager.Stop()
+
+	if _, err := manager.AddCheck(&CheckDefinition{
+		ID: "slow", HTTP: server.URL + "/slow", Interval: "20ms", Timeout: "10s",
+	}); err != nil {
+		t.Fatalf("AddCheck failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := manager.AddCheck(&CheckDefinition{
+			ID: fmt.Sprintf("fast-%d", i), HTTP: fmt.Sprintf("%s/fast-%d", server.URL, i), Interval: "20ms", Timeout: "1s",
+		}); err != nil {
+			t.Fatalf("AddCheck failed: %v", err)
+		}
+	}
+
+	waitForStatus(t, manager, "fast-0", StatusPassing)
+	time.Sleep(200 * time.Millisecond)
+
+	// Reads do not wait for the slow probe
+	start := time.Now()
+	if checks := manager.ListChecks(); len(checks) != 4 {
+		t.Fatalf("expected 4 checks, got %d", len(checks))
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("ListChecks took %v while a probe was running", elapsed)
+	}
+
+	for i := 0; i < 3; i++ {
+		if probes := recorder.count(fmt.Sprintf("/fast-%d", i)); probes < 5 {
+			t.Errorf("expected fast check %d to keep being probed, got %d probes", i, probes)
+		}
+	}
+	// The slow check skips its turns instead of piling up probes
+	if probes := recorder.count("/slow"); probes != 1 {
+		t.Errorf("expected a single probe of the slow check in flight, got %d", probes)
+	}
+	if check, _ := manager.GetCheck("slow"); check.Status != StatusCritical {
+		t.Errorf("expected the slow check to stay critical until probed, got %s", check.Status)
+	}
+}
+
+func TestManager_RemovedCheckIgnoresRunningProbe(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	manager := NewManager(logger.GetDefault())
+	defer manager.Stop()
+
+	if _, err := manager.AddCheck(&CheckDefinition{ID: "web", HTTP: server.URL, Interval: "1h"}); err != nil {
+		t.Fatalf("AddCheck failed: %v", err)
+	}
+	<-started
+
+	if err := manager.RemoveCheck("web"); err != nil {
+		t.Fatalf("RemoveCheck failed: %v", err)
+	}
+	index := manager.Index()
+	close(release)
+
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := manager.GetCheck("web"); ok {
+		t.Error("expected the removed check to stay removed")
+	}
+	if manager.Index() != index {
+		t.Error("expected the probe of the removed check to be discarded")
+	}
+}
+
+func TestManager_ReplacedCheckIsScheduledOnce(t *testing.T) {
+	var probes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probes.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	manager := NewManager(logger.GetDefault())
+	defer manager.Stop()
+
+	for i := 0; i < 3; i++ {
+		if _, err := manager.AddCheck(&CheckDefinition{ID: "web", HTTP: server.URL, Interval: "1h"}); err != nil {
+			t.Fatalf("AddCheck failed: %v", err)
+		}
+	}
+	waitForStatus(t, manager, "web", StatusPassing)
+	time.Sleep(50 * time.Millisecond)
+
+	manager.mutex.RLock()
+	scheduled := len(manager.queue)
+	manager.mutex.RUnlock()
+	if scheduled != 1 {
+		t.Errorf("expected 1 scheduled check, got %d", scheduled)
+	}
+	if got := probes.Load(); got > 3 {
+		t.Errorf("expected at most one probe per AddCheck, got %d", got)
+	}
+
+	// Turning the check into a TTL check stops the probes
+	if _, err := manager.AddCheck(&CheckDefinition{ID: "web", TTL: "30s"}); err != nil {
+		t.Fatalf("AddCheck failed: %v", err)
+	}
+	manager.mutex.RLock()
+	scheduled = len(manager.queue)
+	manager.mutex.RUnlock()
+	if scheduled != 0 {
+		t.Errorf("expected no scheduled check, got %d", scheduled)
+	}
+}
+
+// BenchmarkManager_SlowTargets runs a thousand HTTP checks against targets
+// that take 100ms to answer, for one check interval per iteration, and
+// reports the longest gap between two probes of a check. It stays close to
+// the interval as long as the workers cover the probe load (here 1000 checks
+// * 100ms / 1s = 100 probes in flight).
+func BenchmarkManager_SlowTargets(b *testing.B) {
+	const (
+		checks   = 1000
+		interval = time.Second
+		latency  = 100 * time.Millisecond
+	)
+
+	recorder := newProbeRecorder()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder.record(r.URL.Path)
+		time.Sleep(latency)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	manager := NewManager(logger.GetDefault())
+	defer manager.Stop()
+
+	for i := 0; i < checks; i++ {
+		if _, err := manager.AddCheck(&CheckDefinition{
+			ID:       fmt.Sprintf("check-%d", i),
+			HTTP:     fmt.Sprintf("%s/%d", server.URL, i),
+			Interval: interval.String(),
+			Timeout:  "5s",
+		}); err != nil {
+			b.Fatalf("AddCheck failed: %v", err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		time.Sleep(interval)
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(recorder.maxGap().Milliseconds()), "max-gap-ms")
+}
+
+func TestManager_ReschedulesWithJitter(t *testing.T) {
+	manager := NewManager(logger.GetDefault())
+	defer manager.Stop()
+
+	// Queued by hand, so that no scheduler runs
+	interval := 10 * time.Second
+	start := time.Now()
+	sc := &scheduledCheck{check: &Check{ID: "web", Interval: interval}, slot: start, next: start}
+	heap.Push(&manager.queue, sc)
+
+	offsets := make(map[time.Duration]bool)
+	for i := 1; i <= 20; i++ {
+		due, _ := manager.dueChecks(sc.next)
+		if len(due) != 1 {
+			t.Fatalf("expected the check to be due, got %d checks", len(due))
+		}
+		sc.running = false
+
+		// Probes keep the interval on average, each delayed by its own jitter
+		if want := start.Add(time.Duration(i) * interval); !sc.slot.Equal(want) {
+			t.Fatalf("expected slot %v, got %v", want, sc.slot)
+		}
+		offset := sc.next.Sub(sc.slot)
+		if offset < 0 || offset >= maxProbeJitter {
+			t.Fatalf("expected a jitter below %v, got %v", maxProbeJitter, offset)
+		}
+		offsets[offset] = true
+	}
+	if len(offsets) < 2 {
+		t.Error("expected the jitter to change between probes")
+	}
+
+	if jitter := probeJitter(time.Second); jitter >= 100*time.Millisecond {
+		t.Errorf("expected a jitter below a tenth of the interval, got %v", jitter)
+	}
+	if jitter := probeJitter(time.Nanosecond); jitter != 0 {
+		t.Errorf("expected no jitter for a tiny interval, got %v", jitter)
+	}
+}
@@ -0,0 +1,87 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// maxScriptOutput bounds the output of a script check kept as check output.
+const maxScriptOutput = 4096
+
+// ErrScriptChecksDisabled is returned when adding a script check to a
+// manager that does not allow them.
+var ErrScriptChecksDisabled = errors.New("script checks are disabled")
+
+// ScriptChecker runs script checks: commands whose exit code gives the
+// status of the check. Exit code 0 is passing, 1 is warning and any other
+// code, a failure to start or a timeout is critical.
+type ScriptChecker struct{}
+
+func NewScriptChecker() *ScriptChecker {
+	return &ScriptChecker{}
+}
+
+func (s *ScriptChecker) Check(ctx context.Context, check *Check) (Status, string, error) {
+	if len(check.Args) == 0 {
+		return StatusCritical, "Script arguments not specified", fmt.Errorf("script args required")
+	}
+
+	if check.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, check.Timeout)
+		defer cancel()
+	}
+
+	var out cappedBuffer
+	cmd := exec.CommandContext(ctx, check.Args[0], check.Args[1:]...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	// Do not wait for children that keep the output open after a kill
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	output := out.String()
+
+	if ctx.Err() != nil {
+		return StatusCritical, fmt.Sprintf("Script timed out: %s", output), ctx.Err()
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return StatusPassing, output, nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		return StatusWarning, output, nil
+	case errors.As(err, &exitErr):
+		return StatusCritical, output, fmt.Errorf("script exited with code %d", exitErr.ExitCode())
+	default:
+		return StatusCritical, fmt.Sprintf("Failed to run script: %v", err), err
+	}
+}
+
+// cappedBuffer keeps the first maxScriptOutput bytes written to it.
+type cappedBuffer struct {
+	buf       strings.Builder
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := maxScriptOutput - b.buf.Len(); len(p) > room {
+		b.buf.Write(p[:room])
+		b.truncated = true
+	} else {
+		b.buf.Write(p)
+	}
+	return len(p), nil
+}
+
+func (b *cappedBuffer) String() string {
+	output := strings.TrimSpace(b.buf.String())
+	if b.truncated {
+		output += " ... (truncated)"
+	}
+	return output
+}
@@ -0,0 +1,97 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neogan74/konsul/internal/logger"
+)
+
+func TestScriptChecker_ExitCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		status Status
+		output string
+	}{
+		{"exit 0 is passing", "echo all good", StatusPassing, "all good"},
+		{"exit 1 is warning", "echo degraded; exit 1", StatusWarning, "degraded"},
+		{"other codes are critical", "echo broken >&2; exit 2", StatusCritical, "broken"},
+	}
+
+	checker := NewScriptChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := &Check{Args: []string{"sh", "-c", tt.script}, Timeout: 5 * time.Second}
+			status, output, _ := checker.Check(context.Background(), check)
+			if status != tt.status {
+				t.Errorf("expected status %s, got %s", tt.status, status)
+			}
+			if output != tt.output {
+				t.Errorf("expected output %q, got %q", tt.output, output)
+			}
+		})
+	}
+}
+
+func TestScriptChecker_Failures(t *testing.T) {
+	checker := NewScriptChecker()
+
+	status, _, err := checker.Check(context.Background(), &Check{})
+	if status != StatusCritical || err == nil {
+		t.Errorf("expected critical without args, got %s (%v)", status, err)
+	}
+
+	status, _, err = checker.Check(context.Background(), &Check{Args: []string{"/nonexistent/check"}})
+	if status != StatusCritical || err == nil {
+		t.Errorf("expected critical for a missing command, got %s (%v)", status, err)
+	}
+
+	start := time.Now()
+	status, output, err := checker.Check(context.Background(), &Check{
+		Args:    []string{"sh", "-c", "sleep 10"},
+		Timeout: 100 * time.Millisecond,
+	})
+	if status != StatusCritical || err == nil || !strings.Contains(output, "timed out") {
+		t.Errorf("expected a timeout, got %s %q (%v)", status, output, err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("expected the script to be killed at the timeout, took %v", elapsed)
+	}
+}
+
+func TestScriptChecker_TruncatesOutput(t *testing.T) {
+	checker := NewScriptChecker()
+	check := &Check{Args: []string{"sh", "-c", "head -c 10000 /dev/zero | tr '\\0' x"}, Timeout: 5 * time.Second}
+
+	_, output, _ := checker.Check(context.Background(), check)
+	if !strings.HasSuffix(output, "(truncated)") || len(output) > maxScriptOutput+20 {
+		t.Errorf("expected output truncated to %d bytes, got %d bytes", maxScriptOutput, len(output))
+	}
+}
+
+func TestManager_ScriptChecksGate(t *testing.T) {
+	manager := NewManager(logger.GetDefault())
+	defer manager.Stop()
+
+	def := &CheckDefinition{ID: "script", Name: "script", Args: []string{"true"}, Interval: "1h"}
+	if _, err := manager.AddCheck(def); !errors.Is(err, ErrScriptChecksDisabled) {
+		t.Fatalf("expected ErrScriptChecksDisabled, got %v", err)
+	}
+	if _, ok := manager.GetCheck("script"); ok {
+		t.Fatal("expected the script check not to be added")
+	}
+
+	manager.EnableScriptChecks(true)
+	check, err := manager.AddCheck(def)
+	if err != nil {
+		t.Fatalf("AddCheck failed: %v", err)
+	}
+	if check.Type != CheckTypeScript {
+		t.Errorf("expected type script, got %s", check.Type)
+	}
+	waitForStatus(t, manager, "script", StatusPassing)
+}
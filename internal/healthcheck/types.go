@@ -2,16 +2,20 @@ package healthcheck
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 )
 
 type CheckType string
 
 const (
-	CheckTypeTTL  CheckType = "ttl"
-	CheckTypeHTTP CheckType = "http"
-	CheckTypeTCP  CheckType = "tcp"
-	CheckTypeGRPC CheckType = "grpc"
+	CheckTypeTTL    CheckType = "ttl"
+	CheckTypeHTTP   CheckType = "http"
+	CheckTypeTCP    CheckType = "tcp"
+	CheckTypeGRPC   CheckType = "grpc"
+	CheckTypeUDP    CheckType = "udp"
+	CheckTypeScript CheckType = "script"
 )
 
 type Status string
@@ -22,6 +26,45 @@ const (
 	StatusCritical Status = "critical"
 )
 
+// severity orders statuses from best to worst. Unknown statuses count as
+// critical.
+func (s Status) severity() int {
+	switch s {
+	case StatusPassing:
+		return 0
+	case StatusWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// AggregateStatus returns the worst of statuses: critical if any status is
+// critical, else warning if any is warning, else passing. No statuses at all
+// aggregate to passing, so that instances without checks are healthy.
+func AggregateStatus(statuses ...Status) Status {
+	worst := StatusPassing
+	for _, status := range statuses {
+		if status.severity() > worst.severity() {
+			worst = status
+		}
+	}
+	if worst.severity() == StatusCritical.severity() {
+		return StatusCritical
+	}
+	return worst
+}
+
+// ParseStatus parses a check status name.
+func ParseStatus(s string) (Status, error) {
+	switch status := Status(strings.ToLower(strings.TrimSpace(s))); status {
+	case StatusPassing, StatusWarning, StatusCritical:
+		return status, nil
+	default:
+		return "", fmt.Errorf("invalid check status %q (must be passing, warning or critical)", s)
+	}
+}
+
 type Check struct {
 	ID        string    `json:"id"`
 	Name      string    `json:"name"`
@@ -40,6 +83,9 @@ type Check struct {
 	Method        string            `json:"method,omitempty"`
 	Headers       map[string]string `json:"headers,omitempty"`
 	TLSSkipVerify bool              `json:"tls_skip_verify,omitempty"`
+	BodyMatch     string            `json:"body_match,omitempty"`      // Regular expression the body must match
+	BodyJSONPath  string            `json:"body_json_path,omitempty"`  // Path that must exist in the JSON body
+	BodyJSONValue string            `json:"body_json_value,omitempty"` // Value expected at BodyJSONPath
 
 	// TCP specific
 	TCP string `json:"tcp,omitempty"`
@@ -48,9 +94,50 @@ type Check struct {
 	GRPC       string `json:"grpc,omitempty"`
 	GRPCUseTLS bool   `json:"grpc_use_tls,omitempty"`
 
+	// UDP specific
+	UDP        string `json:"udp,omitempty"`
+	UDPPayload string `json:"udp_payload,omitempty"`
+
+	// Script specific: command and arguments
+	Args []string `json:"args,omitempty"`
+
 	// TTL specific
 	TTL       time.Duration `json:"ttl,omitempty"`
 	ExpiresAt time.Time     `json:"expires_at,omitempty"`
+
+	// Flap damping: consecutive results needed to turn passing or critical
+	SuccessBeforePassing   int `json:"success_before_passing,omitempty"`
+	FailuresBeforeCritical int `json:"failures_before_critical,omitempty"`
+
+	// DeregisterCriticalServiceAfter deregisters the service of a check
+	// critical for that long; zero keeps it registered
+	DeregisterCriticalServiceAfter time.Duration `json:"deregister_critical_service_after,omitempty"`
+	CriticalSince                  time.Time     `json:"critical_since,omitempty"`
+
+	successes int // Consecutive passing or warning results
+	failures  int // Consecutive critical results
+}
+
+// threshold counts result towards the flap thresholds of the check and
+// returns the status the check moves to: result once enough consecutive
+// results agree, else the current status. Warning results count as
+// successes.
+func (c *Check) threshold(result Status) Status {
+	if result == StatusCritical {
+		c.failures++
+		c.successes = 0
+		if c.failures >= c.FailuresBeforeCritical {
+			return result
+		}
+		return c.Status
+	}
+
+	c.successes++
+	c.failures = 0
+	if c.successes >= c.SuccessBeforePassing {
+		return result
+	}
+	return c.Status
 }
 
 type CheckDefinition struct {
@@ -59,10 +146,12 @@ type CheckDefinition struct {
 	ServiceID string `json:"service_id,omitempty"`
 
 	// Check type and common settings
-	HTTP string `json:"http,omitempty"`
-	TCP  string `json:"tcp,omitempty"`
-	GRPC string `json:"grpc,omitempty"`
-	TTL  string `json:"ttl,omitempty"`
+	HTTP string   `json:"http,omitempty"`
+	TCP  string   `json:"tcp,omitempty"`
+	GRPC string   `json:"grpc,omitempty"`
+	UDP  string   `json:"udp,omitempty"`
+	Args []string `json:"args,omitempty"` // Script check command; needs script checks enabled
+	TTL  string   `json:"ttl,omitempty"`
 
 	Interval string `json:"interval,omitempty"`
 	Timeout  string `json:"timeout,omitempty"`
@@ -71,9 +160,56 @@ type CheckDefinition struct {
 	Method        string            `json:"method,omitempty"`
 	Headers       map[string]string `json:"headers,omitempty"`
 	TLSSkipVerify bool              `json:"tls_skip_verify,omitempty"`
+	BodyMatch     string            `json:"body_match,omitempty"`
+	BodyJSONPath  string            `json:"body_json_path,omitempty"`
+	BodyJSONValue string            `json:"body_json_value,omitempty"`
 
 	// gRPC specific
 	GRPCUseTLS bool `json:"grpc_use_tls,omitempty"`
+
+	// UDP specific
+	UDPPayload string `json:"udp_payload,omitempty"`
+
+	// Flap damping and cleanup of failed services
+	SuccessBeforePassing           int    `json:"success_before_passing,omitempty"`
+	FailuresBeforeCritical         int    `json:"failures_before_critical,omitempty"`
+	DeregisterCriticalServiceAfter string `json:"deregister_critical_service_after,omitempty"`
+}
+
+// Type returns the type of the check the definition describes.
+func (d *CheckDefinition) Type() CheckType {
+	switch {
+	case d.HTTP != "":
+		return CheckTypeHTTP
+	case d.TCP != "":
+		return CheckTypeTCP
+	case d.GRPC != "":
+		return CheckTypeGRPC
+	case d.UDP != "":
+		return CheckTypeUDP
+	case len(d.Args) > 0:
+		return CheckTypeScript
+	default:
+		return CheckTypeTTL
+	}
+}
+
+// Validate checks the fields of a check definition that a check cannot run
+// without.
+func (d *CheckDefinition) Validate() error {
+	if d.Type() != CheckTypeHTTP && (d.BodyMatch != "" || d.BodyJSONPath != "" || d.BodyJSONValue != "") {
+		return fmt.Errorf("body assertions need an HTTP check")
+	}
+	if d.SuccessBeforePassing < 0 || d.FailuresBeforeCritical < 0 {
+		return fmt.Errorf("success_before_passing and failures_before_critical must not be negative")
+	}
+	if d.DeregisterCriticalServiceAfter != "" {
+		after, err := time.ParseDuration(d.DeregisterCriticalServiceAfter)
+		if err != nil || after <= 0 {
+			return fmt.Errorf("invalid deregister_critical_service_after %q (must be a positive duration)", d.DeregisterCriticalServiceAfter)
+		}
+	}
+	return validateBodyAssertions(d)
 }
 
 type Checker interface {
@@ -0,0 +1,73 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	// defaultUDPPayload is the datagram sent by UDP checks without a payload.
+	defaultUDPPayload = "ping"
+
+	// udpReadWait bounds how long a UDP check waits for a response. Silence
+	// is not a failure: UDP services need not answer.
+	udpReadWait = time.Second
+)
+
+// UDPChecker runs UDP checks. It sends a datagram to the target and is
+// passing unless the target is unreachable, which the host learns from an
+// ICMP error, usually port unreachable.
+type UDPChecker struct{}
+
+func NewUDPChecker() *UDPChecker {
+	return &UDPChecker{}
+}
+
+func (u *UDPChecker) Check(ctx context.Context, check *Check) (Status, string, error) {
+	if check.UDP == "" {
+		return StatusCritical, "UDP address not specified", fmt.Errorf("UDP address required")
+	}
+
+	start := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", check.UDP)
+	if err != nil {
+		return StatusCritical, fmt.Sprintf("UDP connection to %s failed: %v", check.UDP, err), err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	payload := check.UDPPayload
+	if payload == "" {
+		payload = defaultUDPPayload
+	}
+	if _, err := conn.Write([]byte(payload)); err != nil {
+		return StatusCritical, fmt.Sprintf("UDP send to %s failed: %v", check.UDP, err), err
+	}
+
+	deadline := start.Add(udpReadWait)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return StatusCritical, fmt.Sprintf("UDP check of %s failed: %v", check.UDP, err), err
+	}
+
+	buf := make([]byte, 512)
+	n, err := conn.Read(buf)
+	duration := time.Since(start)
+
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return StatusPassing, fmt.Sprintf("UDP response from %s: %d bytes (%.3fs)", check.UDP, n, duration.Seconds()), nil
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return StatusPassing, fmt.Sprintf("UDP datagram sent to %s, no response (%.3fs)", check.UDP, duration.Seconds()), nil
+	default:
+		return StatusCritical, fmt.Sprintf("UDP check of %s failed after %v: %v", check.UDP, duration, err), err
+	}
+}
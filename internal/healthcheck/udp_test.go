@@ -0,0 +1,69 @@
+package healthcheck
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestUDPChecker_Check(t *testing.T) {
+	// A target that answers
+	echo, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer echo.Close()
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := echo.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			_, _ = echo.WriteTo(buf[:n], addr)
+		}
+	}()
+
+	// A target that stays silent
+	silent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer silent.Close()
+
+	// A port nothing listens on
+	closed, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	closedAddr := closed.LocalAddr().String()
+	closed.Close()
+
+	checker := NewUDPChecker()
+	tests := []struct {
+		name   string
+		addr   string
+		status Status
+	}{
+		{"answering target", echo.LocalAddr().String(), StatusPassing},
+		{"silent target", silent.LocalAddr().String(), StatusPassing},
+		{"unreachable target", closedAddr, StatusCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+
+			status, output, _ := checker.Check(ctx, &Check{UDP: tt.addr})
+			if status != tt.status {
+				t.Errorf("expected %s, got %s: %s", tt.status, status, output)
+			}
+		})
+	}
+
+	status, _, err := checker.Check(context.Background(), &Check{})
+	if status != StatusCritical || err == nil {
+		t.Errorf("expected critical without an address, got %s (%v)", status, err)
+	}
+}
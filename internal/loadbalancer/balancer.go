@@ -62,7 +62,7 @@ func New(serviceStore *store.ServiceStore, strategy Strategy) *Balancer {
 // Returns the selected service and true if successful, or an empty Service and false if no instances available
 func (b *Balancer) SelectService(serviceTag string) (store.Service, bool) {
 	// Get all instances with the specified tag
-	instances := b.store.QueryByTags([]string{serviceTag})
+	instances := b.healthy(b.store.QueryByTags([]string{serviceTag}))
 
 	if len(instances) == 0 {
 		return store.Service{}, false
@@ -87,7 +87,7 @@ func (b *Balancer) SelectService(serviceTag string) (store.Service, bool) {
 
 // SelectServiceByTags selects a service instance that matches all specified tags
 func (b *Balancer) SelectServiceByTags(tags []string) (store.Service, bool) {
-	services := b.store.QueryByTags(tags)
+	services := b.healthy(b.store.QueryByTags(tags))
 	if len(services) == 0 {
 		return store.Service{}, false
 	}
@@ -108,7 +108,7 @@ func (b *Balancer) SelectServiceByTags(tags []string) (store.Service, bool) {
 
 // SelectServiceByMetadata selects a service instance that matches all specified metadata
 func (b *Balancer) SelectServiceByMetadata(filters map[string]string) (store.Service, bool) {
-	services := b.store.QueryByMetadata(filters)
+	services := b.healthy(b.store.QueryByMetadata(filters))
 	if len(services) == 0 {
 		return store.Service{}, false
 	}
@@ -129,7 +129,7 @@ func (b *Balancer) SelectServiceByMetadata(filters map[string]string) (store.Ser
 
 // SelectServiceByQuery selects a service instance matching both tags and metadata
 func (b *Balancer) SelectServiceByQuery(tags []string, metadata map[string]string) (store.Service, bool) {
-	services := b.store.QueryByTagsAndMetadata(tags, metadata)
+	services := b.healthy(b.store.QueryByTagsAndMetadata(tags, metadata))
 	if len(services) == 0 {
 		return store.Service{}, false
 	}
@@ -148,6 +148,12 @@ func (b *Balancer) SelectServiceByQuery(tags []string, metadata map[string]strin
 	}
 }
 
+// healthy returns the instances that discovery hands out: those whose
+// health checks are not critical.
+func (b *Balancer) healthy(instances []store.Service) []store.Service {
+	return b.store.FilterByStatus(instances, store.DiscoveryStatuses...)
+}
+
 // selectRoundRobin implements round-robin selection
 func (b *Balancer) selectRoundRobin(serviceName string, instances []store.Service) store.Service {
 	b.mutex.Lock()
@@ -255,7 +261,7 @@ func (b *Balancer) SetStrategy(strategy Strategy) {
 // Supports IP hash, ring hash, and latency-based strategies
 func (b *Balancer) SelectServiceWithOptions(serviceTag string, opts SelectOptions) (store.Service, bool) {
 	// Get all instances with the specified tag
-	instances := b.store.QueryByTags([]string{serviceTag})
+	instances := b.healthy(b.store.QueryByTags([]string{serviceTag}))
 
 	if len(instances) == 0 {
 		return store.Service{}, false
@@ -276,7 +282,7 @@ func (b *Balancer) SelectServiceWithOptions(serviceTag string, opts SelectOption
 
 // SelectServiceByTagsWithOptions selects a service instance matching tags with advanced options
 func (b *Balancer) SelectServiceByTagsWithOptions(tags []string, opts SelectOptions) (store.Service, bool) {
-	services := b.store.QueryByTags(tags)
+	services := b.healthy(b.store.QueryByTags(tags))
 	if len(services) == 0 {
 		return store.Service{}, false
 	}
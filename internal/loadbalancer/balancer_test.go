@@ -3,6 +3,7 @@ package loadbalancer
 import (
 	"testing"
 
+	"github.com/neogan74/konsul/internal/healthcheck"
 	"github.com/neogan74/konsul/internal/store"
 )
 
@@ -242,6 +243,42 @@ func TestSelectService_NoInstances(t *testing.T) {
 	}
 }
 
+func TestSelectService_SkipsCriticalInstances(t *testing.T) {
+	svcStore := setupTestStore()
+	balancer := New(svcStore, StrategyRoundRobin)
+
+	services := []store.Service{
+		{Name: "api-1", Address: "10.0.0.1", Port: 8080, Tags: []string{"service:api"}},
+		{Name: "api-2", Address: "10.0.0.2", Port: 8080, Tags: []string{"service:api"},
+			Checks: []*healthcheck.CheckDefinition{{ID: "api-2-ttl", TTL: "60s"}}},
+	}
+	for _, svc := range services {
+		if err := svcStore.Register(svc); err != nil {
+			t.Fatalf("Failed to register service: %v", err)
+		}
+	}
+
+	// api-2 stays critical until its TTL check is updated
+	for i := 0; i < 4; i++ {
+		svc, ok := balancer.SelectService("service:api")
+		if !ok || svc.Address != "10.0.0.1" {
+			t.Fatalf("Expected only the healthy instance, got %+v (%v)", svc, ok)
+		}
+	}
+
+	if err := svcStore.UpdateTTLCheck("api-2-ttl"); err != nil {
+		t.Fatalf("Failed to update check: %v", err)
+	}
+	seen := make(map[string]bool)
+	for i := 0; i < 4; i++ {
+		svc, _ := balancer.SelectService("service:api")
+		seen[svc.Address] = true
+	}
+	if !seen["10.0.0.2"] {
+		t.Error("Expected the instance to be selected once its check passes")
+	}
+}
+
 func TestConnectionTracking(t *testing.T) {
 	svcStore := setupTestStore()
 	balancer := New(svcStore, StrategyLeastConnections)
@@ -47,6 +47,13 @@ var (
 		},
 	)
 
+	ExpiredKeysTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "konsul_kv_expired_keys_total",
+			Help: "Total number of keys deleted because their TTL ran out",
+		},
+	)
+
 	// Service Discovery metrics
 	ServiceOperationsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -78,6 +85,29 @@ var (
 		},
 	)
 
+	CriticalServicesDeregisteredTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "konsul_critical_services_deregistered_total",
+			Help: "Total number of services deregistered after their checks stayed critical",
+		},
+	)
+
+	// Session metrics
+	SessionOperationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "konsul_session_operations_total",
+			Help: "Total number of session and lock operations",
+		},
+		[]string{"operation", "status"},
+	)
+
+	InvalidatedSessionsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "konsul_invalidated_sessions_total",
+			Help: "Total number of sessions invalidated by TTL expiry or failing health checks",
+		},
+	)
+
 	// System metrics
 	BuildInfo = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -238,6 +268,23 @@ var (
 		[]string{"selection_type"},
 	)
 
+	// DNS metrics
+	DNSForwardedQueriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "konsul_dns_forwarded_queries_total",
+			Help: "Total number of DNS queries forwarded to recursors",
+		},
+		[]string{"status"},
+	)
+
+	DNSCacheLookupsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "konsul_dns_cache_lookups_total",
+			Help: "Total number of lookups in the DNS recursor answer cache",
+		},
+		[]string{"result"},
+	)
+
 	// GraphQL metrics
 	GraphQLQueriesTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -324,6 +371,14 @@ var (
 		[]string{"reason"},
 	)
 
+	WatchResyncsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "konsul_watch_resyncs_total",
+			Help: "Total number of resync events sent to watchers",
+		},
+		[]string{"reason"},
+	)
+
 	WatchACLDenials = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "konsul_watch_acl_denials_total",
@@ -332,6 +387,22 @@ var (
 		[]string{"reason"},
 	)
 
+	// Webhook metrics
+	WebhookDeliveriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "konsul_webhook_deliveries_total",
+			Help: "Total number of webhook delivery outcomes",
+		},
+		[]string{"status"},
+	)
+
+	WebhookDeliveriesPending = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "konsul_webhook_deliveries_pending",
+			Help: "Number of webhook deliveries queued or waiting for a retry",
+		},
+	)
+
 	// Audit logging metrics
 	AuditEventsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -34,13 +34,13 @@ func ACLMiddleware(evaluator *acl.Evaluator, resourceType acl.ResourceType, capa
 			if key == "" {
 				key = "*" // List all keys
 			}
-			resource = acl.NewKVResource(key)
+			resource = acl.NewKVResource(key).InNamespace(GetNamespace(c))
 		case acl.ResourceTypeService:
 			name := c.Params("name")
 			if name == "" {
 				name = "*" // List all services
 			}
-			resource = acl.NewServiceResource(name)
+			resource = acl.NewServiceResource(name).InNamespace(GetNamespace(c))
 		case acl.ResourceTypeHealth:
 			resource = acl.NewHealthResource()
 		case acl.ResourceTypeBackup:
@@ -99,6 +99,7 @@ func DynamicACLMiddleware(evaluator *acl.Evaluator) fiber.Handler {
 				"resource":   string(resource.Type),
 				"capability": string(capability),
 				"path":       resource.Path,
+				"namespace":  resource.Namespace,
 			})
 		}
 
@@ -127,7 +128,7 @@ func inferResourceAndCapability(c *fiber.Ctx) (acl.Resource, acl.Capability) {
 		if key == "" {
 			key = "*"
 		}
-		resource := acl.NewKVResource(key)
+		resource := acl.NewKVResource(key).InNamespace(GetNamespace(c))
 
 		switch method {
 		case "GET":
@@ -150,7 +151,7 @@ func inferResourceAndCapability(c *fiber.Ctx) (acl.Resource, acl.Capability) {
 		if serviceName == "" {
 			serviceName = "*"
 		}
-		resource := acl.NewServiceResource(serviceName)
+		resource := acl.NewServiceResource(serviceName).InNamespace(GetNamespace(c))
 
 		switch {
 		case strings.HasPrefix(path, "/register"):
@@ -195,8 +196,9 @@ func inferResourceAndCapability(c *fiber.Ctx) (acl.Resource, acl.Capability) {
 		}
 	}
 
-	// Admin endpoints (ACL management, metrics, etc.)
-	if strings.HasPrefix(path, "/acl/") || strings.HasPrefix(path, "/metrics") {
+	// Admin endpoints (ACL management, namespaces, webhooks, metrics, etc.)
+	if strings.HasPrefix(path, "/acl/") || strings.HasPrefix(path, "/namespaces") ||
+		strings.HasPrefix(path, "/webhooks") || strings.HasPrefix(path, "/metrics") {
 		resource := acl.NewAdminResource()
 		switch method {
 		case "GET":
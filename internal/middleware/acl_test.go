@@ -223,6 +223,69 @@ func TestACLMiddleware_KVResource(t *testing.T) {
 	}
 }
 
+func TestACLMiddleware_KVNamespace(t *testing.T) {
+	jwtService := auth.NewJWTService("test-secret", 15*time.Minute, 24*time.Hour, "konsul")
+	log := logger.GetDefault()
+	evaluator := acl.NewEvaluator(log)
+
+	policy := &acl.Policy{
+		Name: "team-a",
+		KV: []acl.KVRule{
+			{
+				Namespace:    "team-a",
+				Path:         "*",
+				Capabilities: []acl.Capability{acl.CapabilityRead},
+			},
+		},
+	}
+	if err := evaluator.AddPolicy(policy); err != nil {
+		t.Fatalf("failed to add policy: %v", err)
+	}
+
+	token, err := jwtService.GenerateTokenWithPolicies("user123", "testuser", []string{"user"}, []string{"team-a"})
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	app := fiber.New()
+	app.Use(JWTAuth(jwtService, []string{}))
+	app.Get("/kv/:key",
+		ACLMiddleware(evaluator, acl.ResourceTypeKV, acl.CapabilityRead),
+		func(c *fiber.Ctx) error {
+			return c.SendString("success")
+		},
+	)
+
+	tests := []struct {
+		name     string
+		target   string
+		header   string
+		expected int
+	}{
+		{"query parameter", "/kv/mykey?ns=team-a", "", fiber.StatusOK},
+		{"header", "/kv/mykey", "team-a", fiber.StatusOK},
+		{"default namespace", "/kv/mykey", "", fiber.StatusForbidden},
+		{"other namespace", "/kv/mykey?ns=team-b", "", fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.target, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			if tt.header != "" {
+				req.Header.Set(NamespaceHeader, tt.header)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			if resp.StatusCode != tt.expected {
+				t.Errorf("expected status %d, got %d", tt.expected, resp.StatusCode)
+			}
+		})
+	}
+}
+
 func TestACLMiddleware_ServiceResource(t *testing.T) {
 	jwtService := auth.NewJWTService("test-secret", 15*time.Minute, 24*time.Hour, "konsul")
 	log := logger.GetDefault()
@@ -901,6 +964,13 @@ func TestInferResourceAndCapability_Admin(t *testing.T) {
 			expectedResource: acl.ResourceTypeAdmin,
 			expectedCap:      acl.CapabilityWrite,
 		},
+		{
+			name:             "PUT /webhooks/deploy - write",
+			path:             "/webhooks/deploy",
+			method:           "PUT",
+			expectedResource: acl.ResourceTypeAdmin,
+			expectedCap:      acl.CapabilityWrite,
+		},
 		{
 			name:             "GET /metrics - read",
 			path:             "/metrics",
@@ -175,6 +175,45 @@ func BackupActionMapper(c *fiber.Ctx) string {
 	}
 }
 
+// NamespaceActionMapper provides specific action mapping for namespace operations.
+func NamespaceActionMapper(c *fiber.Ctx) string {
+	method := c.Method()
+	switch method {
+	case "PUT":
+		return "namespace.create"
+	case "DELETE":
+		return "namespace.delete"
+	case "GET":
+		if c.Params("name") != "" {
+			return "namespace.get"
+		}
+		return "namespace.list"
+	default:
+		return "namespace." + method
+	}
+}
+
+// WebhookActionMapper provides specific action mapping for webhook operations.
+func WebhookActionMapper(c *fiber.Ctx) string {
+	method := c.Method()
+	switch method {
+	case "PUT":
+		return "webhook.set"
+	case "DELETE":
+		return "webhook.delete"
+	case "GET":
+		if auditContains(c.Path(), "/deliveries") {
+			return "webhook.deliveries"
+		}
+		if c.Params("name") != "" {
+			return "webhook.get"
+		}
+		return "webhook.list"
+	default:
+		return "webhook." + method
+	}
+}
+
 // AdminActionMapper provides specific action mapping for admin operations.
 func AdminActionMapper(c *fiber.Ctx) string {
 	path := c.Path()
@@ -305,3 +305,74 @@ func TestBackupActionMapper(t *testing.T) {
 		})
 	}
 }
+
+func TestNamespaceActionMapper(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		uri      string
+		expected string
+	}{
+		{"create", "PUT", "/namespaces/team-a", "namespace.create"},
+		{"delete", "DELETE", "/namespaces/team-a", "namespace.delete"},
+		{"get", "GET", "/namespaces/team-a", "namespace.get"},
+		{"list", "GET", "/namespaces", "namespace.list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+
+			app.Add(tt.method, "/namespaces/:name?", func(c *fiber.Ctx) error {
+				action := NamespaceActionMapper(c)
+				if action != tt.expected {
+					t.Errorf("expected action %q, got %q", tt.expected, action)
+				}
+				return c.SendStatus(200)
+			})
+
+			req := httptest.NewRequest(tt.method, tt.uri, nil)
+			_, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestWebhookActionMapper(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		uri      string
+		expected string
+	}{
+		{"set", "PUT", "/webhooks/deploy", "webhook.set"},
+		{"delete", "DELETE", "/webhooks/deploy", "webhook.delete"},
+		{"get", "GET", "/webhooks/deploy", "webhook.get"},
+		{"deliveries", "GET", "/webhooks/deploy/deliveries", "webhook.deliveries"},
+		{"list", "GET", "/webhooks", "webhook.list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+
+			handler := func(c *fiber.Ctx) error {
+				action := WebhookActionMapper(c)
+				if action != tt.expected {
+					t.Errorf("expected action %q, got %q", tt.expected, action)
+				}
+				return c.SendStatus(200)
+			}
+			app.Add(tt.method, "/webhooks/:name?", handler)
+			app.Add(tt.method, "/webhooks/:name/deliveries", handler)
+
+			req := httptest.NewRequest(tt.method, tt.uri, nil)
+			_, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+		})
+	}
+}
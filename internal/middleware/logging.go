@@ -42,14 +42,19 @@ func RequestLogging(log logger.Logger) fiber.Handler {
 		// Calculate duration
 		duration := time.Since(start)
 
-		// Log response
+		// Log response. Streamed bodies, such as SSE watches, are left
+		// unread: reading one here would consume the stream.
 		status := c.Response().StatusCode()
+		responseSize := -1
+		if !c.Response().IsBodyStream() {
+			responseSize = len(c.Response().Body())
+		}
 		logFields := []logger.Field{
 			logger.String("method", c.Method()),
 			logger.String("path", c.Path()),
 			logger.Int("status", status),
 			logger.Duration("duration", duration),
-			logger.Int("response_size", len(c.Response().Body())),
+			logger.Int("response_size", responseSize),
 		}
 
 		// Log level based on status code
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// NamespaceHeader selects the namespace of a request when the ns query
+// parameter is not set
+const NamespaceHeader = "X-Konsul-Namespace"
+
+// GetNamespace returns the namespace a request targets, taken from the ns
+// query parameter or the X-Konsul-Namespace header. An empty result means the
+// default namespace.
+func GetNamespace(c *fiber.Ctx) string {
+	if ns := c.Query("ns"); ns != "" {
+		return utils.CopyString(ns)
+	}
+	return utils.CopyString(c.Get(NamespaceHeader))
+}
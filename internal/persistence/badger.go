@@ -1,9 +1,11 @@
 package persistence
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -16,16 +18,26 @@ import (
 const (
 	kvPrefix      = "kv:"
 	servicePrefix = "svc:"
+	aclPrefix     = "acl:"
 )
 
 // BadgerEngine implements Engine using BadgerDB
 type BadgerEngine struct {
-	db  *badger.DB
-	log logger.Logger
+	db         *badger.DB
+	log        logger.Logger
+	encryption EncryptionConfig
 }
 
 // NewBadgerEngine creates a new BadgerDB persistence engine
 func NewBadgerEngine(dataDir string, syncWrites bool, log logger.Logger) (*BadgerEngine, error) {
+	return NewEncryptedBadgerEngine(dataDir, syncWrites, EncryptionConfig{}, log)
+}
+
+// NewEncryptedBadgerEngine creates a BadgerDB persistence engine that
+// encrypts its data files and backups when enc has a key. If enc also has
+// the previous key and the data directory is still encrypted with it, the
+// directory is re-keyed to the new key.
+func NewEncryptedBadgerEngine(dataDir string, syncWrites bool, enc EncryptionConfig, log logger.Logger) (*BadgerEngine, error) {
 	// Create directory if it doesn't exist
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create data directory: %w", err)
@@ -54,14 +66,31 @@ func NewBadgerEngine(dataDir string, syncWrites bool, log logger.Logger) (*Badge
 		log.Info("WAL enabled with asynchronous writes for better performance")
 	}
 
+	if enc.Enabled() {
+		if enc.DataKeyRotation <= 0 {
+			enc.DataKeyRotation = DefaultDataKeyRotation
+		}
+		opts.EncryptionKey = enc.Key
+		opts.EncryptionKeyRotationDuration = enc.DataKeyRotation
+		opts.IndexCacheSize = 64 << 20 // Required by BadgerDB with encryption
+	}
+
 	db, err := badger.Open(opts)
+	if errors.Is(err, badger.ErrEncryptionKeyMismatch) && len(enc.PreviousKey) > 0 {
+		if err := rotateBadgerKey(dataDir, enc.PreviousKey, enc.Key, enc.DataKeyRotation); err != nil {
+			return nil, fmt.Errorf("failed to rotate encryption key: %w", err)
+		}
+		log.Info("Rotated BadgerDB encryption key", logger.String("data_dir", dataDir))
+		db, err = badger.Open(opts)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to open BadgerDB: %w", err)
 	}
 
 	engine := &BadgerEngine{
-		db:  db,
-		log: log,
+		db:         db,
+		log:        log,
+		encryption: enc,
 	}
 
 	// Start garbage collection routine
@@ -69,7 +98,8 @@ func NewBadgerEngine(dataDir string, syncWrites bool, log logger.Logger) (*Badge
 
 	log.Info("BadgerDB persistence engine initialized with WAL support",
 		logger.String("data_dir", dataDir),
-		logger.String("sync_writes", fmt.Sprintf("%t", syncWrites)))
+		logger.String("sync_writes", fmt.Sprintf("%t", syncWrites)),
+		logger.String("encryption", fmt.Sprintf("%t", enc.Enabled())))
 
 	return engine, nil
 }
@@ -108,6 +138,13 @@ func (b *BadgerEngine) Set(key string, value []byte) error {
 	})
 }
 
+func (b *BadgerEngine) SetWithTTL(key string, value []byte, ttl time.Duration) error {
+	return b.db.Update(func(txn *badger.Txn) error {
+		e := badger.NewEntry([]byte(kvPrefix+key), value).WithTTL(ttl)
+		return txn.SetEntry(e)
+	})
+}
+
 func (b *BadgerEngine) Delete(key string) error {
 	return b.db.Update(func(txn *badger.Txn) error {
 		return txn.Delete([]byte(kvPrefix + key))
@@ -196,6 +233,39 @@ func (b *BadgerEngine) ListServices() ([]string, error) {
 	return names, err
 }
 
+func (b *BadgerEngine) SetACL(kind, id string, data []byte) error {
+	return b.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(aclPrefix+kind+":"+id), data)
+	})
+}
+
+func (b *BadgerEngine) DeleteACL(kind, id string) error {
+	return b.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(aclPrefix + kind + ":" + id))
+	})
+}
+
+func (b *BadgerEngine) ListACL(kind string) (map[string][]byte, error) {
+	records := make(map[string][]byte)
+	err := b.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		prefix := aclPrefix + kind + ":"
+		prefixBytes := []byte(prefix)
+		for it.Seek(prefixBytes); it.ValidForPrefix(prefixBytes); it.Next() {
+			item := it.Item()
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			records[strings.TrimPrefix(string(item.Key()), prefix)] = value
+		}
+		return nil
+	})
+	return records, err
+}
+
 func (b *BadgerEngine) BatchSet(items map[string][]byte) error {
 	return b.db.Update(func(txn *badger.Txn) error {
 		for key, value := range items {
@@ -222,6 +292,8 @@ func (b *BadgerEngine) Close() error {
 	return b.db.Close()
 }
 
+// Backup writes a full backup to path. With encryption enabled the backup
+// is encrypted with the current key.
 func (b *BadgerEngine) Backup(path string) error {
 	// Ensure backup directory exists
 	dir := filepath.Dir(path)
@@ -235,8 +307,25 @@ func (b *BadgerEngine) Backup(path string) error {
 	}
 	defer func() { _ = file.Close() }()
 
-	_, err = b.db.Backup(file, 0)
+	if !b.encryption.Enabled() {
+		if _, err := b.db.Backup(file, 0); err != nil {
+			return fmt.Errorf("backup failed: %w", err)
+		}
+		b.log.Info("Backup completed successfully", logger.String("path", path))
+		return nil
+	}
+
+	w, err := newEncryptedBackupWriter(file, b.encryption.Key)
 	if err != nil {
+		return fmt.Errorf("failed to encrypt backup: %w", err)
+	}
+	if _, err := b.db.Backup(w, 0); err != nil {
+		return fmt.Errorf("backup failed: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("backup failed: %w", err)
+	}
+	if err := file.Sync(); err != nil {
 		return fmt.Errorf("backup failed: %w", err)
 	}
 
@@ -244,6 +333,8 @@ func (b *BadgerEngine) Backup(path string) error {
 	return nil
 }
 
+// Restore loads a backup written by Backup. Encrypted backups are decrypted
+// with the current or previous key; plaintext backups are accepted too.
 func (b *BadgerEngine) Restore(path string) error {
 	file, err := os.Open(path)
 	if err != nil {
@@ -251,7 +342,16 @@ func (b *BadgerEngine) Restore(path string) error {
 	}
 	defer func() { _ = file.Close() }()
 
-	err = b.db.Load(file, 256)
+	buffered := bufio.NewReader(file)
+	var r io.Reader = buffered
+	if isEncryptedBackup(buffered) {
+		r, err = newEncryptedBackupReader(buffered, b.encryption.Key, b.encryption.PreviousKey)
+		if err != nil {
+			return fmt.Errorf("restore failed: %w", err)
+		}
+	}
+
+	err = b.db.Load(r, 256)
 	if err != nil {
 		return fmt.Errorf("restore failed: %w", err)
 	}
@@ -123,6 +123,50 @@ func TestBadgerEngine_Services(t *testing.T) {
 	}
 }
 
+func TestBadgerEngine_ACLRecords(t *testing.T) {
+	tempDir := t.TempDir()
+	log := logger.NewFromConfig("info", "text")
+
+	engine, err := NewBadgerEngine(tempDir, true, log)
+	if err != nil {
+		t.Fatalf("Failed to create BadgerEngine: %v", err)
+	}
+
+	if err := engine.SetACL("policy", "readonly", []byte(`{"name":"readonly"}`)); err != nil {
+		t.Fatalf("Failed to set ACL record: %v", err)
+	}
+	if err := engine.SetACL("apikey", "k1", []byte(`{"id":"k1"}`)); err != nil {
+		t.Fatalf("Failed to set ACL record: %v", err)
+	}
+	// ACL records are not KV keys
+	if keys, _ := engine.List(""); len(keys) != 0 {
+		t.Errorf("Expected no KV keys, got %v", keys)
+	}
+
+	// Records survive a reopen
+	_ = engine.Close()
+	engine, err = NewBadgerEngine(tempDir, true, log)
+	if err != nil {
+		t.Fatalf("Failed to reopen BadgerEngine: %v", err)
+	}
+	defer func() { _ = engine.Close() }()
+
+	policies, err := engine.ListACL("policy")
+	if err != nil {
+		t.Fatalf("Failed to list ACL records: %v", err)
+	}
+	if len(policies) != 1 || string(policies["readonly"]) != `{"name":"readonly"}` {
+		t.Errorf("Unexpected policies: %v", policies)
+	}
+
+	if err := engine.DeleteACL("apikey", "k1"); err != nil {
+		t.Fatalf("Failed to delete ACL record: %v", err)
+	}
+	if keys, _ := engine.ListACL("apikey"); len(keys) != 0 {
+		t.Errorf("Expected no API keys, got %v", keys)
+	}
+}
+
 func TestBadgerEngine_BatchOperations(t *testing.T) {
 	tempDir := t.TempDir()
 	log := logger.NewFromConfig("info", "text")
@@ -0,0 +1,319 @@
+package persistence
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v4"
+)
+
+// DefaultDataKeyRotation is how often Badger replaces the data keys it
+// encrypts new files with, matching Badger's own default
+const DefaultDataKeyRotation = 10 * 24 * time.Hour
+
+var (
+	// ErrEncryptedBackup is returned when restoring an encrypted backup
+	// without a key that can decrypt it.
+	ErrEncryptedBackup = errors.New("backup is encrypted with a different or no configured key")
+	// ErrCorruptBackup is returned when an encrypted backup fails to decrypt,
+	// was truncated or was tampered with.
+	ErrCorruptBackup = errors.New("encrypted backup is corrupt or truncated")
+)
+
+// EncryptionConfig configures encryption at rest. Badger encrypts its files
+// with data keys that are themselves encrypted with Key, the master key.
+type EncryptionConfig struct {
+	Key             []byte        // AES master key of 16, 24 or 32 bytes; nil disables encryption
+	PreviousKey     []byte        // Master key before a rotation; the data directory is re-keyed on open
+	DataKeyRotation time.Duration // How often new data keys are generated
+}
+
+// Enabled reports whether encryption at rest is configured.
+func (c EncryptionConfig) Enabled() bool {
+	return len(c.Key) > 0
+}
+
+// ParseEncryptionKey decodes a base64 encoded AES key of 16, 24 or 32 bytes,
+// as generated by `openssl rand -base64 32`.
+func ParseEncryptionKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("encryption key is not valid base64: %w", err)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("encryption key must be 16, 24 or 32 bytes, got %d", len(key))
+	}
+}
+
+// LoadEncryptionKey returns the key given directly as value or read from
+// file; at most one of them may be set. It returns nil if neither is.
+func LoadEncryptionKey(value, file string) ([]byte, error) {
+	switch {
+	case value != "" && file != "":
+		return nil, errors.New("encryption key and key file are mutually exclusive")
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption key file: %w", err)
+		}
+		return ParseEncryptionKey(string(data))
+	case value != "":
+		return ParseEncryptionKey(value)
+	default:
+		return nil, nil
+	}
+}
+
+// rotateBadgerKey re-encrypts the data keys of the Badger directory dir,
+// which must not be open, from oldKey to newKey. The data files themselves
+// are encrypted with the data keys and stay as they are.
+func rotateBadgerKey(dir string, oldKey, newKey []byte, dataKeyRotation time.Duration) error {
+	opt := badger.KeyRegistryOptions{
+		Dir:                           dir,
+		ReadOnly:                      true,
+		EncryptionKey:                 oldKey,
+		EncryptionKeyRotationDuration: dataKeyRotation,
+	}
+	registry, err := badger.OpenKeyRegistry(opt)
+	if err != nil {
+		return fmt.Errorf("failed to open key registry with the previous key: %w", err)
+	}
+	defer func() { _ = registry.Close() }()
+
+	opt.EncryptionKey = newKey
+	return badger.WriteKeyRegistry(registry, opt)
+}
+
+// Encrypted backups start with backupMagic, a version byte, the ID of the key
+// they are encrypted with and a random nonce prefix, followed by chunks of
+// at most backupChunkSize bytes. Each chunk is sealed with AES-GCM under the
+// nonce prefix and its sequence number, with the header and a final-chunk
+// flag as additional data, so that reordered, truncated or extended archives
+// fail to decrypt.
+const (
+	backupMagic     = "KONSULBK"
+	backupVersion   = 1
+	backupKeyIDLen  = 8
+	backupPrefixLen = 8
+	backupHeaderLen = len(backupMagic) + 1 + backupKeyIDLen + backupPrefixLen
+	backupChunkSize = 64 << 10
+)
+
+// keyID returns the ID a backup records for key: a prefix of its SHA-256
+// hash, which tells keys apart without revealing them.
+func keyID(key []byte) []byte {
+	sum := sha256.Sum256(key)
+	return sum[:backupKeyIDLen]
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// backupNonce returns the nonce of chunk seq.
+func backupNonce(prefix []byte, seq uint32) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[backupPrefixLen:], seq)
+	return nonce
+}
+
+// backupAAD returns the additional data of a chunk.
+func backupAAD(header []byte, final bool) []byte {
+	aad := append([]byte{}, header...)
+	if final {
+		return append(aad, 1)
+	}
+	return append(aad, 0)
+}
+
+// encryptedBackupWriter encrypts a backup stream. Close must be called to
+// write the final chunk; it does not close the underlying writer.
+type encryptedBackupWriter struct {
+	w      io.Writer
+	gcm    cipher.AEAD
+	header []byte
+	prefix []byte
+	buf    []byte
+	seq    uint32
+}
+
+func newEncryptedBackupWriter(w io.Writer, key []byte) (*encryptedBackupWriter, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := make([]byte, backupPrefixLen)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	header := make([]byte, 0, backupHeaderLen)
+	header = append(header, backupMagic...)
+	header = append(header, backupVersion)
+	header = append(header, keyID(key)...)
+	header = append(header, prefix...)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &encryptedBackupWriter{
+		w:      w,
+		gcm:    gcm,
+		header: header,
+		prefix: prefix,
+		buf:    make([]byte, 0, backupChunkSize),
+	}, nil
+}
+
+func (e *encryptedBackupWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), backupChunkSize-len(e.buf))
+		e.buf = append(e.buf, p[:n]...)
+		p = p[n:]
+		written += n
+		if len(e.buf) == backupChunkSize {
+			if err := e.flush(false); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Close seals and writes the final chunk.
+func (e *encryptedBackupWriter) Close() error {
+	return e.flush(true)
+}
+
+func (e *encryptedBackupWriter) flush(final bool) error {
+	sealed := e.gcm.Seal(nil, backupNonce(e.prefix, e.seq), e.buf, backupAAD(e.header, final))
+	e.seq++
+	e.buf = e.buf[:0]
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(sealed)))
+	if _, err := e.w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err := e.w.Write(sealed)
+	return err
+}
+
+// encryptedBackupReader decrypts a backup stream written by
+// encryptedBackupWriter.
+type encryptedBackupReader struct {
+	r      io.Reader
+	gcm    cipher.AEAD
+	header []byte
+	prefix []byte
+	buf    []byte
+	seq    uint32
+	done   bool
+}
+
+// newEncryptedBackupReader reads the header of an encrypted backup and picks
+// the one of keys it was encrypted with.
+func newEncryptedBackupReader(r io.Reader, keys ...[]byte) (*encryptedBackupReader, error) {
+	header := make([]byte, backupHeaderLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, ErrCorruptBackup
+	}
+	if header[len(backupMagic)] != backupVersion {
+		return nil, fmt.Errorf("unsupported encrypted backup version %d", header[len(backupMagic)])
+	}
+	id := header[len(backupMagic)+1 : len(backupMagic)+1+backupKeyIDLen]
+
+	for _, key := range keys {
+		if len(key) == 0 || !bytes.Equal(keyID(key), id) {
+			continue
+		}
+		gcm, err := newGCM(key)
+		if err != nil {
+			return nil, err
+		}
+		return &encryptedBackupReader{
+			r:      r,
+			gcm:    gcm,
+			header: header,
+			prefix: header[backupHeaderLen-backupPrefixLen:],
+		}, nil
+	}
+	return nil, ErrEncryptedBackup
+}
+
+func (d *encryptedBackupReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+// next decrypts the next chunk into d.buf.
+func (d *encryptedBackupReader) next() error {
+	var length [4]byte
+	if _, err := io.ReadFull(d.r, length[:]); err != nil {
+		// The stream must end with a final chunk
+		return ErrCorruptBackup
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size > backupChunkSize+uint32(d.gcm.Overhead()) {
+		return ErrCorruptBackup
+	}
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		return ErrCorruptBackup
+	}
+
+	nonce := backupNonce(d.prefix, d.seq)
+	d.seq++
+	if plain, err := d.gcm.Open(nil, nonce, sealed, backupAAD(d.header, false)); err == nil {
+		d.buf = plain
+		return nil
+	}
+	plain, err := d.gcm.Open(sealed[:0], nonce, sealed, backupAAD(d.header, true))
+	if err != nil {
+		return ErrCorruptBackup
+	}
+	// Nothing may follow the final chunk
+	if n, _ := d.r.Read(make([]byte, 1)); n > 0 {
+		return ErrCorruptBackup
+	}
+	d.buf = plain
+	d.done = true
+	return nil
+}
+
+// isEncryptedBackup reports whether r, which must not have been read from,
+// holds an encrypted backup, without consuming it.
+func isEncryptedBackup(r *bufio.Reader) bool {
+	magic, err := r.Peek(len(backupMagic))
+	return err == nil && string(magic) == backupMagic
+}
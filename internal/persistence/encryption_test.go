@@ -0,0 +1,300 @@
+package persistence
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neogan74/konsul/internal/logger"
+)
+
+var secretValue = []byte("hunter2-Zq8Lx7Vb3Nm1Kc9Wt4")
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+// filesContain reports whether any file below dir contains needle.
+func filesContain(t *testing.T, dir string, needle []byte) bool {
+	t.Helper()
+	found := false
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.Contains(data, needle) {
+			found = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", dir, err)
+	}
+	return found
+}
+
+// writeSecret stores secretValue in a new engine at dir and closes it.
+func writeSecret(t *testing.T, dir string, enc EncryptionConfig) {
+	t.Helper()
+	engine, err := NewEncryptedBadgerEngine(dir, true, enc, logger.NewFromConfig("error", "text"))
+	if err != nil {
+		t.Fatalf("Failed to create BadgerEngine: %v", err)
+	}
+	if err := engine.Set("db/password", secretValue); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+	if err := engine.SetService("db", secretValue, 0); err != nil {
+		t.Fatalf("Failed to set service: %v", err)
+	}
+	if err := engine.Close(); err != nil {
+		t.Fatalf("Failed to close engine: %v", err)
+	}
+}
+
+func TestBadgerEngine_EncryptionAtRest(t *testing.T) {
+	// Without a key the value can be found in the raw files, which shows
+	// that the check below would catch plaintext
+	plainDir := t.TempDir()
+	writeSecret(t, plainDir, EncryptionConfig{})
+	if !filesContain(t, plainDir, secretValue) {
+		t.Fatal("Expected plaintext value in unencrypted data directory")
+	}
+
+	dir := t.TempDir()
+	enc := EncryptionConfig{Key: testKey(1)}
+	writeSecret(t, dir, enc)
+	if filesContain(t, dir, secretValue) {
+		t.Fatal("Found plaintext value in encrypted data directory")
+	}
+
+	// The data is readable again with the key
+	engine, err := NewEncryptedBadgerEngine(dir, true, enc, logger.NewFromConfig("error", "text"))
+	if err != nil {
+		t.Fatalf("Failed to reopen encrypted BadgerEngine: %v", err)
+	}
+	defer func() { _ = engine.Close() }()
+	value, err := engine.Get("db/password")
+	if err != nil {
+		t.Fatalf("Failed to get key: %v", err)
+	}
+	if !bytes.Equal(value, secretValue) {
+		t.Errorf("Expected %s, got %s", secretValue, value)
+	}
+}
+
+func TestBadgerEngine_WrongKey(t *testing.T) {
+	dir := t.TempDir()
+	writeSecret(t, dir, EncryptionConfig{Key: testKey(1)})
+
+	log := logger.NewFromConfig("error", "text")
+	if engine, err := NewEncryptedBadgerEngine(dir, true, EncryptionConfig{Key: testKey(2)}, log); err == nil {
+		_ = engine.Close()
+		t.Fatal("Expected error opening with the wrong key")
+	}
+	if engine, err := NewBadgerEngine(dir, true, log); err == nil {
+		_ = engine.Close()
+		t.Fatal("Expected error opening without a key")
+	}
+}
+
+func TestBadgerEngine_KeyRotation(t *testing.T) {
+	dir := t.TempDir()
+	oldKey, newKey := testKey(1), testKey(2)
+	writeSecret(t, dir, EncryptionConfig{Key: oldKey})
+
+	log := logger.NewFromConfig("error", "text")
+	engine, err := NewEncryptedBadgerEngine(dir, true, EncryptionConfig{Key: newKey, PreviousKey: oldKey}, log)
+	if err != nil {
+		t.Fatalf("Failed to rotate key: %v", err)
+	}
+	value, err := engine.Get("db/password")
+	if err != nil || !bytes.Equal(value, secretValue) {
+		t.Fatalf("Expected %s after rotation, got %s (%v)", secretValue, value, err)
+	}
+	if err := engine.Close(); err != nil {
+		t.Fatalf("Failed to close engine: %v", err)
+	}
+
+	// The new key alone opens the directory now, the old one no longer does
+	engine, err = NewEncryptedBadgerEngine(dir, true, EncryptionConfig{Key: newKey}, log)
+	if err != nil {
+		t.Fatalf("Failed to open with the new key: %v", err)
+	}
+	_ = engine.Close()
+	if engine, err := NewEncryptedBadgerEngine(dir, true, EncryptionConfig{Key: oldKey}, log); err == nil {
+		_ = engine.Close()
+		t.Fatal("Expected error opening with the old key")
+	}
+}
+
+func TestBadgerEngine_EncryptedBackupRestore(t *testing.T) {
+	log := logger.NewFromConfig("error", "text")
+	oldKey, newKey := testKey(1), testKey(2)
+
+	source, err := NewEncryptedBadgerEngine(t.TempDir(), true, EncryptionConfig{Key: oldKey}, log)
+	if err != nil {
+		t.Fatalf("Failed to create BadgerEngine: %v", err)
+	}
+	defer func() { _ = source.Close() }()
+	if err := source.Set("db/password", secretValue); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+	// Enough data for several backup chunks
+	big := bytes.Repeat([]byte("x"), 3*backupChunkSize)
+	if err := source.Set("big", big); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	if err := source.Backup(backupPath); err != nil {
+		t.Fatalf("Failed to back up: %v", err)
+	}
+	if filesContain(t, filepath.Dir(backupPath), secretValue) {
+		t.Fatal("Found plaintext value in encrypted backup")
+	}
+
+	// A node without the key cannot restore the backup
+	plain, err := NewBadgerEngine(t.TempDir(), true, log)
+	if err != nil {
+		t.Fatalf("Failed to create BadgerEngine: %v", err)
+	}
+	defer func() { _ = plain.Close() }()
+	if err := plain.Restore(backupPath); !errors.Is(err, ErrEncryptedBackup) {
+		t.Fatalf("Expected ErrEncryptedBackup, got %v", err)
+	}
+
+	// A node that rotated to a new key still restores backups taken with the
+	// previous one
+	target, err := NewEncryptedBadgerEngine(t.TempDir(), true, EncryptionConfig{Key: newKey, PreviousKey: oldKey}, log)
+	if err != nil {
+		t.Fatalf("Failed to create BadgerEngine: %v", err)
+	}
+	defer func() { _ = target.Close() }()
+	if err := target.Restore(backupPath); err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+	value, err := target.Get("db/password")
+	if err != nil || !bytes.Equal(value, secretValue) {
+		t.Fatalf("Expected %s after restore, got %s (%v)", secretValue, value, err)
+	}
+	value, err = target.Get("big")
+	if err != nil || !bytes.Equal(value, big) {
+		t.Fatalf("Expected big value after restore (%v)", err)
+	}
+}
+
+func TestBadgerEngine_RestorePlaintextBackup(t *testing.T) {
+	log := logger.NewFromConfig("error", "text")
+
+	source, err := NewBadgerEngine(t.TempDir(), true, log)
+	if err != nil {
+		t.Fatalf("Failed to create BadgerEngine: %v", err)
+	}
+	defer func() { _ = source.Close() }()
+	if err := source.Set("db/password", secretValue); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	if err := source.Backup(backupPath); err != nil {
+		t.Fatalf("Failed to back up: %v", err)
+	}
+
+	// Restoring a plaintext backup is how an existing directory is migrated
+	// to encryption
+	target, err := NewEncryptedBadgerEngine(t.TempDir(), true, EncryptionConfig{Key: testKey(1)}, log)
+	if err != nil {
+		t.Fatalf("Failed to create BadgerEngine: %v", err)
+	}
+	defer func() { _ = target.Close() }()
+	if err := target.Restore(backupPath); err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+	value, err := target.Get("db/password")
+	if err != nil || !bytes.Equal(value, secretValue) {
+		t.Fatalf("Expected %s after restore, got %s (%v)", secretValue, value, err)
+	}
+}
+
+func TestEncryptedBackup_RejectsTampering(t *testing.T) {
+	key := testKey(1)
+	payload := bytes.Repeat([]byte("payload-"), backupChunkSize/4)
+
+	var buf bytes.Buffer
+	w, err := newEncryptedBackupWriter(&buf, key)
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	if _, err := w.Write(payload); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+	archive := buf.Bytes()
+
+	read := func(data []byte) ([]byte, error) {
+		r, err := newEncryptedBackupReader(bytes.NewReader(data), key)
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(r)
+	}
+
+	got, err := read(archive)
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Fatalf("Expected payload to round-trip, got %d bytes (%v)", len(got), err)
+	}
+
+	flipped := bytes.Clone(archive)
+	flipped[len(flipped)/2] ^= 1
+	if _, err := read(flipped); !errors.Is(err, ErrCorruptBackup) {
+		t.Errorf("Expected ErrCorruptBackup for modified archive, got %v", err)
+	}
+	if _, err := read(archive[:len(archive)-backupChunkSize/2]); !errors.Is(err, ErrCorruptBackup) {
+		t.Errorf("Expected ErrCorruptBackup for truncated archive, got %v", err)
+	}
+	if _, err := read(append(bytes.Clone(archive), 0)); !errors.Is(err, ErrCorruptBackup) {
+		t.Errorf("Expected ErrCorruptBackup for extended archive, got %v", err)
+	}
+}
+
+func TestLoadEncryptionKey(t *testing.T) {
+	key := testKey(7)
+	encoded := base64.StdEncoding.EncodeToString(key)
+
+	got, err := LoadEncryptionKey(encoded, "")
+	if err != nil || !bytes.Equal(got, key) {
+		t.Fatalf("Expected key from value, got %v (%v)", got, err)
+	}
+
+	file := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(file, []byte(encoded+"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+	got, err = LoadEncryptionKey("", file)
+	if err != nil || !bytes.Equal(got, key) {
+		t.Fatalf("Expected key from file, got %v (%v)", got, err)
+	}
+
+	if got, err := LoadEncryptionKey("", ""); err != nil || got != nil {
+		t.Errorf("Expected no key, got %v (%v)", got, err)
+	}
+	if _, err := LoadEncryptionKey(encoded, file); err == nil {
+		t.Error("Expected error for key and key file")
+	}
+	if _, err := LoadEncryptionKey("not base64!", ""); err == nil {
+		t.Error("Expected error for invalid base64")
+	}
+	if _, err := LoadEncryptionKey(base64.StdEncoding.EncodeToString([]byte("short")), ""); err == nil {
+		t.Error("Expected error for invalid key length")
+	}
+}
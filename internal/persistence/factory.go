@@ -18,11 +18,32 @@ func NewEngine(cfg Config, log logger.Logger) (Engine, error) {
 		log.Info("Using in-memory persistence")
 		return NewMemoryEngine(), nil
 	case "badger":
+		enc, err := encryptionConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
 		log.Info("Using BadgerDB persistence",
 			logger.String("data_dir", cfg.DataDir),
-			logger.String("sync_writes", fmt.Sprintf("%t", cfg.SyncWrites)))
-		return NewBadgerEngine(cfg.DataDir, cfg.SyncWrites, log)
+			logger.String("sync_writes", fmt.Sprintf("%t", cfg.SyncWrites)),
+			logger.String("encryption", fmt.Sprintf("%t", enc.Enabled())))
+		return NewEncryptedBadgerEngine(cfg.DataDir, cfg.SyncWrites, enc, log)
 	default:
 		return nil, fmt.Errorf("unsupported persistence type: %s", cfg.Type)
 	}
 }
+
+// encryptionConfig loads the encryption keys named by cfg.
+func encryptionConfig(cfg Config) (EncryptionConfig, error) {
+	key, err := LoadEncryptionKey(cfg.EncryptionKey, cfg.EncryptionKeyFile)
+	if err != nil {
+		return EncryptionConfig{}, err
+	}
+	previous, err := LoadEncryptionKey(cfg.PreviousEncryptionKey, cfg.PreviousEncryptionKeyFile)
+	if err != nil {
+		return EncryptionConfig{}, fmt.Errorf("previous key: %w", err)
+	}
+	if key == nil && previous != nil {
+		return EncryptionConfig{}, fmt.Errorf("previous encryption key requires an encryption key")
+	}
+	return EncryptionConfig{Key: key, PreviousKey: previous, DataKeyRotation: cfg.DataKeyRotation}, nil
+}
@@ -9,6 +9,7 @@ type Engine interface {
 	// KV operations
 	Get(key string) ([]byte, error)
 	Set(key string, value []byte) error
+	SetWithTTL(key string, value []byte, ttl time.Duration) error
 	Delete(key string) error
 	List(prefix string) ([]string, error)
 
@@ -18,6 +19,11 @@ type Engine interface {
 	DeleteService(name string) error
 	ListServices() ([]string, error)
 
+	// ACL operations: policies and API keys are stored as records of a kind
+	SetACL(kind, id string, data []byte) error
+	DeleteACL(kind, id string) error
+	ListACL(kind string) (map[string][]byte, error)
+
 	// Batch operations
 	BatchSet(items map[string][]byte) error
 	BatchDelete(keys []string) error
@@ -47,4 +53,11 @@ type Config struct {
 	BackupDir  string
 	SyncWrites bool
 	WALEnabled bool
+
+	// Encryption at rest, see LoadEncryptionKey for the key format
+	EncryptionKey             string
+	EncryptionKeyFile         string
+	PreviousEncryptionKey     string
+	PreviousEncryptionKeyFile string
+	DataKeyRotation           time.Duration
 }
@@ -11,9 +11,11 @@ import (
 
 // MemoryEngine is an in-memory implementation of Engine
 type MemoryEngine struct {
-	mu      sync.RWMutex
-	kvData  map[string][]byte
-	svcData map[string]serviceEntry
+	mu       sync.RWMutex
+	kvData   map[string][]byte
+	kvExpiry map[string]time.Time
+	svcData  map[string]serviceEntry
+	aclData  map[string]map[string][]byte // kind -> id -> record
 }
 
 type serviceEntry struct {
@@ -24,8 +26,10 @@ type serviceEntry struct {
 // NewMemoryEngine creates a new in-memory persistence engine
 func NewMemoryEngine() *MemoryEngine {
 	return &MemoryEngine{
-		kvData:  make(map[string][]byte),
-		svcData: make(map[string]serviceEntry),
+		kvData:   make(map[string][]byte),
+		kvExpiry: make(map[string]time.Time),
+		svcData:  make(map[string]serviceEntry),
+		aclData:  make(map[string]map[string][]byte),
 	}
 }
 
@@ -33,7 +37,7 @@ func (m *MemoryEngine) Get(key string) ([]byte, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if val, ok := m.kvData[key]; ok {
+	if val, ok := m.kvData[key]; ok && !m.kvExpired(key, time.Now()) {
 		return val, nil
 	}
 	return nil, errors.New("key not found")
@@ -44,14 +48,32 @@ func (m *MemoryEngine) Set(key string, value []byte) error {
 	defer m.mu.Unlock()
 
 	m.kvData[key] = value
+	delete(m.kvExpiry, key)
 	return nil
 }
 
+func (m *MemoryEngine) SetWithTTL(key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.kvData[key] = value
+	m.kvExpiry[key] = time.Now().Add(ttl)
+	return nil
+}
+
+// kvExpired reports whether a key written with a TTL has expired at now.
+// Callers must hold m.mu.
+func (m *MemoryEngine) kvExpired(key string, now time.Time) bool {
+	expiresAt, ok := m.kvExpiry[key]
+	return ok && !now.Before(expiresAt)
+}
+
 func (m *MemoryEngine) Delete(key string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	delete(m.kvData, key)
+	delete(m.kvExpiry, key)
 	return nil
 }
 
@@ -60,8 +82,9 @@ func (m *MemoryEngine) List(prefix string) ([]string, error) {
 	defer m.mu.RUnlock()
 
 	var keys []string
+	now := time.Now()
 	for key := range m.kvData {
-		if strings.HasPrefix(key, prefix) {
+		if strings.HasPrefix(key, prefix) && !m.kvExpired(key, now) {
 			keys = append(keys, key)
 		}
 	}
@@ -113,12 +136,43 @@ func (m *MemoryEngine) ListServices() ([]string, error) {
 	return names, nil
 }
 
+func (m *MemoryEngine) SetACL(kind, id string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.aclData[kind] == nil {
+		m.aclData[kind] = make(map[string][]byte)
+	}
+	m.aclData[kind][id] = data
+	return nil
+}
+
+func (m *MemoryEngine) DeleteACL(kind, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.aclData[kind], id)
+	return nil
+}
+
+func (m *MemoryEngine) ListACL(kind string) (map[string][]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	records := make(map[string][]byte, len(m.aclData[kind]))
+	for id, data := range m.aclData[kind] {
+		records[id] = data
+	}
+	return records, nil
+}
+
 func (m *MemoryEngine) BatchSet(items map[string][]byte) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	for key, value := range items {
 		m.kvData[key] = value
+		delete(m.kvExpiry, key)
 	}
 	return nil
 }
@@ -129,6 +183,7 @@ func (m *MemoryEngine) BatchDelete(keys []string) error {
 
 	for _, key := range keys {
 		delete(m.kvData, key)
+		delete(m.kvExpiry, key)
 	}
 	return nil
 }
@@ -170,6 +225,7 @@ func (m *MemoryEngine) Restore(path string) error {
 
 	// Clear existing data
 	m.kvData = make(map[string][]byte)
+	m.kvExpiry = make(map[string]time.Time)
 	m.svcData = make(map[string]serviceEntry)
 
 	// Restore KV data
@@ -217,6 +273,7 @@ func (tx *memoryTx) Commit() error {
 		} else {
 			tx.engine.kvData[key] = value.([]byte)
 		}
+		delete(tx.engine.kvExpiry, key)
 	}
 	return nil
 }
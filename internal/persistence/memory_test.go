@@ -106,6 +106,35 @@ func TestMemoryEngine_Services(t *testing.T) {
 	}
 }
 
+func TestMemoryEngine_KVTTL(t *testing.T) {
+	engine := NewMemoryEngine()
+
+	if err := engine.SetWithTTL("ephemeral", []byte("v"), 100*time.Millisecond); err != nil {
+		t.Fatalf("Failed to set key with TTL: %v", err)
+	}
+	if _, err := engine.Get("ephemeral"); err != nil {
+		t.Fatalf("Expected key before expiry: %v", err)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	if _, err := engine.Get("ephemeral"); err == nil {
+		t.Error("Expected error when getting expired key")
+	}
+	keys, _ := engine.List("")
+	if len(keys) != 0 {
+		t.Errorf("Expected expired key to be hidden from list, got %v", keys)
+	}
+
+	// A plain Set removes the TTL
+	_ = engine.SetWithTTL("ephemeral", []byte("v"), 100*time.Millisecond)
+	_ = engine.Set("ephemeral", []byte("v2"))
+	time.Sleep(150 * time.Millisecond)
+	if _, err := engine.Get("ephemeral"); err != nil {
+		t.Errorf("Expected key to outlive its old TTL after Set: %v", err)
+	}
+}
+
 func TestMemoryEngine_BatchOperations(t *testing.T) {
 	engine := NewMemoryEngine()
 
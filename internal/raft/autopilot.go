@@ -0,0 +1,409 @@
+package raft
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/raft"
+)
+
+// autopilotInterval is how often the leader checks the health of servers.
+const autopilotInterval = time.Second
+
+// AutopilotConfig configures the autopilot run by the leader.
+type AutopilotConfig struct {
+	// Enabled adds joining servers as non-voters, promotes them once they
+	// are stable and removes dead servers. When disabled, servers join as
+	// voters and the leader only reports their health.
+	Enabled bool
+
+	// CleanupDeadServers removes servers the leader has not heard from for
+	// DeadServerThreshold.
+	// Default: true
+	CleanupDeadServers bool
+
+	// LastContactThreshold is how long a server may go without answering the
+	// leader before it is unhealthy.
+	// Default: 1s
+	LastContactThreshold time.Duration
+
+	// MaxTrailingLogs is how many log entries a server may lag behind the
+	// leader before it is unhealthy.
+	// Default: 250
+	MaxTrailingLogs uint64
+
+	// ServerStabilizationTime is how long a non-voter must stay healthy
+	// before it is promoted to voter.
+	// Default: 10s
+	ServerStabilizationTime time.Duration
+
+	// DeadServerThreshold is how long a server may go without answering the
+	// leader before it is removed from the cluster.
+	// Default: 5m
+	DeadServerThreshold time.Duration
+}
+
+// DefaultAutopilotConfig returns the default autopilot configuration, with
+// the autopilot disabled.
+func DefaultAutopilotConfig() AutopilotConfig {
+	return AutopilotConfig{
+		CleanupDeadServers:      true,
+		LastContactThreshold:    time.Second,
+		MaxTrailingLogs:         250,
+		ServerStabilizationTime: 10 * time.Second,
+		DeadServerThreshold:     5 * time.Minute,
+	}
+}
+
+// withDefaults returns c with the unset thresholds set to their defaults.
+func (c AutopilotConfig) withDefaults() AutopilotConfig {
+	defaults := DefaultAutopilotConfig()
+	if c.LastContactThreshold == 0 {
+		c.LastContactThreshold = defaults.LastContactThreshold
+	}
+	if c.MaxTrailingLogs == 0 {
+		c.MaxTrailingLogs = defaults.MaxTrailingLogs
+	}
+	if c.ServerStabilizationTime == 0 {
+		c.ServerStabilizationTime = defaults.ServerStabilizationTime
+	}
+	if c.DeadServerThreshold == 0 {
+		c.DeadServerThreshold = defaults.DeadServerThreshold
+	}
+	return c
+}
+
+// ServerHealth is the health of a server as seen by the leader.
+type ServerHealth struct {
+	ID       string `json:"id"`
+	Address  string `json:"address"`
+	Suffrage string `json:"suffrage"`
+	Leader   bool   `json:"leader"`
+	Healthy  bool   `json:"healthy"`
+
+	// ReadReplica is true for the non-voters that joined as read replicas,
+	// which are never promoted.
+	ReadReplica bool `json:"read_replica,omitempty"`
+
+	// LastContact is how long ago the server last answered the leader, or
+	// "never" since the leader was elected. Empty for the leader.
+	LastContact string `json:"last_contact,omitempty"`
+
+	LastIndex uint64 `json:"last_index"`
+	Lag       uint64 `json:"lag"`
+
+	// StableSince is when the server became healthy; zero while unhealthy.
+	StableSince time.Time `json:"stable_since,omitzero"`
+
+	// silent is how long the server has not answered the leader.
+	silent time.Duration
+}
+
+// AutopilotHealth is the health of the cluster as seen by the leader.
+type AutopilotHealth struct {
+	// Healthy is true when every server is healthy.
+	Healthy bool `json:"healthy"`
+
+	// FailureTolerance is how many voters can fail without losing quorum.
+	FailureTolerance int `json:"failure_tolerance"`
+
+	Servers []ServerHealth `json:"servers"`
+}
+
+// peerContact is the last successful AppendEntries exchange with a peer.
+type peerContact struct {
+	at        time.Time
+	lastIndex uint64
+}
+
+// observedTransport records when each peer last answered an AppendEntries
+// RPC, heartbeats included, and the last log index it reported, for the
+// health checks of the autopilot.
+type observedTransport struct {
+	*raft.NetworkTransport
+
+	mu       sync.Mutex
+	contacts map[raft.ServerID]peerContact
+}
+
+func newObservedTransport(trans *raft.NetworkTransport) *observedTransport {
+	return &observedTransport{
+		NetworkTransport: trans,
+		contacts:         make(map[raft.ServerID]peerContact),
+	}
+}
+
+// AppendEntries sends an AppendEntries RPC and records the answer.
+func (t *observedTransport) AppendEntries(id raft.ServerID, target raft.ServerAddress, args *raft.AppendEntriesRequest, resp *raft.AppendEntriesResponse) error {
+	if err := t.NetworkTransport.AppendEntries(id, target, args, resp); err != nil {
+		return err
+	}
+	t.record(id, resp.LastLog, time.Now())
+	return nil
+}
+
+func (t *observedTransport) record(id raft.ServerID, lastIndex uint64, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.contacts[id] = peerContact{at: at, lastIndex: lastIndex}
+}
+
+// snapshot returns a copy of the recorded contacts.
+func (t *observedTransport) snapshot() map[raft.ServerID]peerContact {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	contacts := make(map[raft.ServerID]peerContact, len(t.contacts))
+	for id, contact := range t.contacts {
+		contacts[id] = contact
+	}
+	return contacts
+}
+
+// reset forgets the recorded contacts.
+func (t *observedTransport) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	clear(t.contacts)
+}
+
+// serverState is what the autopilot remembers of a server across checks.
+type serverState struct {
+	firstSeen   time.Time
+	stableSince time.Time
+}
+
+// autopilot tracks the health of the servers while this node is the leader.
+// Its state starts over with each leadership so that contacts recorded in an
+// earlier term never count.
+type autopilot struct {
+	cfg       AutopilotConfig
+	transport *observedTransport
+
+	// isReadReplica reports the servers that joined as read replicas
+	isReadReplica func(id string) bool
+
+	mu          sync.Mutex
+	leaderSince time.Time
+	servers     map[raft.ServerID]*serverState
+}
+
+func newAutopilot(cfg AutopilotConfig, transport *observedTransport, isReadReplica func(id string) bool) *autopilot {
+	return &autopilot{
+		cfg:           cfg.withDefaults(),
+		transport:     transport,
+		isReadReplica: isReadReplica,
+		servers:       make(map[raft.ServerID]*serverState),
+	}
+}
+
+// reset forgets everything tracked; called when this node is not the leader.
+func (a *autopilot) reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.leaderSince.IsZero() {
+		return
+	}
+	a.leaderSince = time.Time{}
+	clear(a.servers)
+	a.transport.reset()
+}
+
+// health returns the health at now of the servers of the configuration led
+// by leader, whose last log index is lastIndex.
+func (a *autopilot) health(servers []raft.Server, leader raft.ServerID, lastIndex uint64, now time.Time) AutopilotHealth {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.leaderSince.IsZero() {
+		a.leaderSince = now
+		a.transport.reset()
+	}
+	contacts := a.transport.snapshot()
+
+	result := AutopilotHealth{Healthy: true}
+	voters, healthyVoters := 0, 0
+	seen := make(map[raft.ServerID]bool, len(servers))
+	for _, srv := range servers {
+		seen[srv.ID] = true
+		state, ok := a.servers[srv.ID]
+		if !ok {
+			state = &serverState{firstSeen: now}
+			a.servers[srv.ID] = state
+		}
+
+		server := ServerHealth{
+			ID:       string(srv.ID),
+			Address:  string(srv.Address),
+			Suffrage: srv.Suffrage.String(),
+			Leader:   srv.ID == leader,
+		}
+		if srv.Suffrage == raft.Nonvoter && a.isReadReplica != nil {
+			server.ReadReplica = a.isReadReplica(string(srv.ID))
+		}
+		if server.Leader {
+			server.Healthy = true
+			server.LastIndex = lastIndex
+		} else if contact, ok := contacts[srv.ID]; ok {
+			server.silent = now.Sub(contact.at)
+			server.LastContact = server.silent.Round(time.Millisecond).String()
+			server.LastIndex = contact.lastIndex
+			if lastIndex > contact.lastIndex {
+				server.Lag = lastIndex - contact.lastIndex
+			}
+			server.Healthy = server.silent <= a.cfg.LastContactThreshold && server.Lag <= a.cfg.MaxTrailingLogs
+		} else {
+			server.silent = now.Sub(state.firstSeen)
+			server.LastContact = "never"
+			server.Lag = lastIndex
+		}
+
+		if !server.Healthy {
+			state.stableSince = time.Time{}
+		} else if state.stableSince.IsZero() {
+			state.stableSince = now
+		}
+		server.StableSince = state.stableSince
+
+		if srv.Suffrage == raft.Voter {
+			voters++
+			if server.Healthy {
+				healthyVoters++
+			}
+		}
+		result.Healthy = result.Healthy && server.Healthy
+		result.Servers = append(result.Servers, server)
+	}
+
+	for id := range a.servers {
+		if !seen[id] {
+			delete(a.servers, id)
+		}
+	}
+
+	result.FailureTolerance = max(healthyVoters-(voters/2+1), 0)
+	sort.Slice(result.Servers, func(i, j int) bool { return result.Servers[i].ID < result.Servers[j].ID })
+	return result
+}
+
+// plan returns the non-voters of health to promote, read replicas aside,
+// and the servers to remove at now. Dead voters are only removed while they are a minority of
+// the voters, so that the autopilot never shrinks the cluster below the
+// quorum it would need to recover them.
+func (a *autopilot) plan(health AutopilotHealth, now time.Time) (promote, remove []ServerHealth) {
+	voters := 0
+	var deadVoters []ServerHealth
+	for _, server := range health.Servers {
+		if server.Suffrage == raft.Voter.String() {
+			voters++
+		}
+		if server.Leader {
+			continue
+		}
+
+		if a.cfg.CleanupDeadServers && server.silent > a.cfg.DeadServerThreshold {
+			if server.Suffrage == raft.Voter.String() {
+				deadVoters = append(deadVoters, server)
+			} else {
+				remove = append(remove, server)
+			}
+			continue
+		}
+
+		if server.Suffrage == raft.Nonvoter.String() && !server.ReadReplica && server.Healthy &&
+			now.Sub(server.StableSince) >= a.cfg.ServerStabilizationTime {
+			promote = append(promote, server)
+		}
+	}
+
+	if 2*len(deadVoters) < voters {
+		remove = append(remove, deadVoters...)
+	}
+	return promote, remove
+}
+
+// AutopilotConfig returns the autopilot configuration in effect.
+func (n *Node) AutopilotConfig() AutopilotConfig {
+	return n.autopilot.cfg
+}
+
+// AutopilotHealth returns the health of the cluster. Must be called on the
+// leader.
+func (n *Node) AutopilotHealth() (*AutopilotHealth, error) {
+	if n.raft.State() != raft.Leader {
+		return nil, ErrNotLeader
+	}
+	return n.autopilotHealth(time.Now())
+}
+
+func (n *Node) autopilotHealth(now time.Time) (*AutopilotHealth, error) {
+	configuration, err := n.GetConfiguration()
+	if err != nil {
+		return nil, err
+	}
+	health := n.autopilot.health(configuration.Servers, raft.ServerID(n.config.NodeID), n.raft.LastIndex(), now)
+	return &health, nil
+}
+
+// runAutopilot checks the health of the servers while this node is the
+// leader and, when the autopilot is enabled, promotes stable non-voters and
+// removes dead servers. This runs in a background goroutine for the
+// lifetime of the node.
+func (n *Node) runAutopilot() {
+	ticker := time.NewTicker(autopilotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if n.raft.State() != raft.Leader {
+				n.autopilot.reset()
+				continue
+			}
+			n.autopilotStep(time.Now())
+		case <-n.shutdownCh:
+			return
+		}
+	}
+}
+
+// autopilotStep runs one autopilot check on the leader.
+func (n *Node) autopilotStep(now time.Time) {
+	health, err := n.autopilotHealth(now)
+	if err != nil {
+		n.logger.Warn("autopilot failed to get configuration", "error", err)
+		return
+	}
+	n.metrics.SetAutopilotHealth(n.config.NodeID, health.Healthy, health.FailureTolerance)
+
+	if !n.autopilot.cfg.Enabled {
+		return
+	}
+
+	promote, remove := n.autopilot.plan(*health, now)
+	for _, server := range remove {
+		n.logger.Warn("autopilot removing dead server",
+			"node_id", server.ID,
+			"suffrage", server.Suffrage,
+			"last_contact", server.LastContact,
+		)
+		if err := n.raft.RemoveServer(raft.ServerID(server.ID), 0, 0).Error(); err != nil {
+			n.logger.Error("autopilot failed to remove server", "node_id", server.ID, "error", err)
+			return
+		}
+		n.metrics.IncAutopilotAction(n.config.NodeID, "remove")
+	}
+	if len(remove) > 0 {
+		// Promote on the next check, against the new configuration
+		return
+	}
+
+	for _, server := range promote {
+		n.logger.Info("autopilot promoting server to voter", "node_id", server.ID, "addr", server.Address)
+		if err := n.raft.AddVoter(raft.ServerID(server.ID), raft.ServerAddress(server.Address), 0, 0).Error(); err != nil {
+			n.logger.Error("autopilot failed to promote server", "node_id", server.ID, "error", err)
+			return
+		}
+		n.metrics.IncAutopilotAction(n.config.NodeID, "promote")
+	}
+}
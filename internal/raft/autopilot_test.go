@@ -0,0 +1,196 @@
+package raft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAutopilot(cfg AutopilotConfig) *autopilot {
+	return newAutopilot(cfg, newObservedTransport(nil), nil)
+}
+
+func testServers(suffrages ...raft.ServerSuffrage) []raft.Server {
+	servers := make([]raft.Server, len(suffrages))
+	for i, suffrage := range suffrages {
+		id := raft.ServerID(string(rune('a' + i)))
+		servers[i] = raft.Server{ID: id, Address: raft.ServerAddress(string(id) + ":7000"), Suffrage: suffrage}
+	}
+	return servers
+}
+
+func TestAutopilot_Health(t *testing.T) {
+	a := newTestAutopilot(AutopilotConfig{LastContactThreshold: time.Second, MaxTrailingLogs: 10})
+	servers := testServers(raft.Voter, raft.Voter, raft.Voter, raft.Nonvoter)
+	now := time.Now()
+
+	// Contacts recorded before the leadership do not count
+	a.transport.record("b", 100, now)
+	health := a.health(servers, "a", 100, now)
+	assert.False(t, health.Healthy)
+	assert.Equal(t, 0, health.FailureTolerance)
+	require.Len(t, health.Servers, 4)
+	assert.True(t, health.Servers[0].Leader)
+	assert.True(t, health.Servers[0].Healthy)
+	assert.Equal(t, "never", health.Servers[1].LastContact)
+
+	a.transport.record("b", 100, now)
+	a.transport.record("c", 95, now)
+	a.transport.record("d", 50, now)
+	now = now.Add(500 * time.Millisecond)
+	health = a.health(servers, "a", 100, now)
+
+	b, c, d := health.Servers[1], health.Servers[2], health.Servers[3]
+	assert.True(t, b.Healthy)
+	assert.Equal(t, "500ms", b.LastContact)
+	assert.Equal(t, now, b.StableSince)
+	assert.True(t, c.Healthy)
+	assert.Equal(t, uint64(5), c.Lag)
+	assert.False(t, d.Healthy, "a server lagging behind is unhealthy")
+	assert.Equal(t, uint64(50), d.Lag)
+	assert.True(t, d.StableSince.IsZero())
+	assert.False(t, health.Healthy)
+	assert.Equal(t, 1, health.FailureTolerance)
+
+	// A server silent for longer than the threshold becomes unhealthy
+	a.transport.record("b", 100, now)
+	a.transport.record("d", 100, now)
+	later := now.Add(1500 * time.Millisecond)
+	a.transport.record("c", 100, later)
+	health = a.health(servers, "a", 100, later)
+	assert.False(t, health.Servers[1].Healthy)
+	assert.True(t, health.Servers[2].Healthy)
+	assert.Equal(t, now, health.Servers[2].StableSince, "a server stays stable while healthy")
+	assert.Equal(t, 0, health.FailureTolerance)
+
+	// Losing the leadership starts the tracking over
+	a.reset()
+	health = a.health(servers, "a", 100, later)
+	assert.Equal(t, "never", health.Servers[2].LastContact)
+}
+
+func TestAutopilot_Plan(t *testing.T) {
+	a := newTestAutopilot(AutopilotConfig{
+		CleanupDeadServers:      true,
+		ServerStabilizationTime: 10 * time.Second,
+		DeadServerThreshold:     time.Minute,
+	})
+	servers := testServers(raft.Voter, raft.Voter, raft.Voter, raft.Nonvoter, raft.Nonvoter)
+	start := time.Now()
+	a.health(servers, "a", 10, start)
+
+	for _, id := range []raft.ServerID{"b", "c", "d", "e"} {
+		a.transport.record(id, 10, start)
+	}
+	health := a.health(servers, "a", 10, start)
+	promote, remove := a.plan(health, start)
+	assert.Empty(t, promote, "non-voters are promoted only once stable")
+	assert.Empty(t, remove)
+
+	// d stays healthy, c and e go silent
+	now := start.Add(2 * time.Minute)
+	a.transport.record("b", 10, now)
+	a.transport.record("d", 10, now)
+	health = a.health(servers, "a", 10, now)
+	now = now.Add(11 * time.Second)
+	a.transport.record("b", 10, now)
+	a.transport.record("d", 10, now)
+	health = a.health(servers, "a", 10, now)
+
+	promote, remove = a.plan(health, now)
+	require.Len(t, promote, 1)
+	assert.Equal(t, "d", promote[0].ID)
+	var removed []string
+	for _, server := range remove {
+		removed = append(removed, server.ID)
+	}
+	assert.ElementsMatch(t, []string{"c", "e"}, removed)
+
+	// Dead voters are kept when they are not a minority
+	servers = testServers(raft.Voter, raft.Voter, raft.Voter, raft.Voter)
+	a = newTestAutopilot(a.cfg)
+	a.health(servers, "a", 10, start)
+	a.transport.record("b", 10, now)
+	health = a.health(servers, "a", 10, now)
+	_, remove = a.plan(health, now)
+	assert.Empty(t, remove)
+
+	// No removals when cleanup is disabled
+	a.cfg.CleanupDeadServers = false
+	servers = testServers(raft.Voter, raft.Voter, raft.Voter)
+	a = newTestAutopilot(a.cfg)
+	a.health(servers, "a", 10, start)
+	a.transport.record("b", 10, now)
+	health = a.health(servers, "a", 10, now)
+	_, remove = a.plan(health, now)
+	assert.Empty(t, remove)
+
+	// Read replicas are never promoted
+	a = newAutopilot(a.cfg, newObservedTransport(nil), func(id string) bool { return id == "b" })
+	servers = testServers(raft.Voter, raft.Nonvoter)
+	a.health(servers, "a", 10, start)
+	a.transport.record("b", 10, start)
+	a.health(servers, "a", 10, start)
+	a.transport.record("b", 10, now)
+	health = a.health(servers, "a", 10, now)
+	assert.True(t, health.Servers[1].ReadReplica)
+	promote, _ = a.plan(health, now)
+	assert.Empty(t, promote)
+}
+
+func TestAutopilot_PromoteAndRemove(t *testing.T) {
+	opts := clusterOptions{}
+	override := func(cfg *Config) {
+		cfg.Autopilot.Enabled = true
+		cfg.Autopilot.ServerStabilizationTime = time.Second
+		cfg.Autopilot.DeadServerThreshold = 3 * time.Second
+	}
+
+	addr1 := getFreeAddr(t)
+	cfg1 := newClusterConfigWithOverrides(t, "node-1", addr1, true, opts, override)
+	node1 := startTestNode(t, cfg1)
+	defer func() { _ = node1.Shutdown() }()
+	require.NoError(t, node1.WaitForLeader(5*time.Second))
+
+	var followers []*Node
+	for _, id := range []string{"node-2", "node-3"} {
+		cfg := newClusterConfigWithOverrides(t, id, getFreeAddr(t), false, opts, override)
+		node := startTestNode(t, cfg)
+		defer func() { _ = node.Shutdown() }()
+		followers = append(followers, node)
+		require.NoError(t, node1.Join(cfg.NodeID, cfg.AdvertiseAddr))
+	}
+
+	suffrages := func() map[string]raft.ServerSuffrage {
+		cfg, err := node1.GetConfiguration()
+		require.NoError(t, err)
+		result := make(map[string]raft.ServerSuffrage)
+		for _, srv := range cfg.Servers {
+			result[string(srv.ID)] = srv.Suffrage
+		}
+		return result
+	}
+	assert.Equal(t, raft.Nonvoter, suffrages()["node-2"], "servers join as non-voters")
+
+	require.Eventually(t, func() bool {
+		s := suffrages()
+		return s["node-2"] == raft.Voter && s["node-3"] == raft.Voter
+	}, 15*time.Second, 100*time.Millisecond, "stable servers are promoted")
+
+	health, err := node1.AutopilotHealth()
+	require.NoError(t, err)
+	assert.True(t, health.Healthy)
+	assert.Equal(t, 1, health.FailureTolerance)
+
+	_, err = followers[0].AutopilotHealth()
+	assert.ErrorIs(t, err, ErrNotLeader)
+
+	require.NoError(t, followers[1].Shutdown())
+	require.Eventually(t, func() bool {
+		_, ok := suffrages()["node-3"]
+		return !ok
+	}, 15*time.Second, 100*time.Millisecond, "dead servers are removed")
+}
@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/neogan74/konsul/internal/acl"
+	"github.com/neogan74/konsul/internal/auth"
 	"github.com/neogan74/konsul/internal/store"
 )
 
@@ -41,11 +43,64 @@ const (
 	CmdServiceDeregister
 	// CmdServiceDeregisterCAS deregisters a service with CAS
 	CmdServiceDeregisterCAS
-	// CmdServiceHeartbeat updates service TTL
+	// CmdServiceHeartbeat updates service TTL. No longer written, as the
+	// leader tracks heartbeats in memory; entries already in the log still apply.
 	CmdServiceHeartbeat
 
 	// CmdHealthTTLUpdate updates health check TTL
 	CmdHealthTTLUpdate
+
+	// CmdSessionCreate creates a session
+	CmdSessionCreate
+	// CmdSessionRenew renews a session TTL
+	CmdSessionRenew
+	// CmdSessionDestroy destroys a session and releases its locks
+	CmdSessionDestroy
+	// CmdKVAcquire acquires a lock on a key for a session
+	CmdKVAcquire
+	// CmdKVRelease releases a session's lock on a key
+	CmdKVRelease
+
+	// CmdKVExpire deletes keys whose TTL has run out
+	CmdKVExpire
+
+	// CmdNamespaceCreate creates a namespace
+	CmdNamespaceCreate
+	// CmdNamespaceDelete deletes an empty namespace
+	CmdNamespaceDelete
+
+	// CmdACLPolicyCreate creates an ACL policy
+	CmdACLPolicyCreate
+	// CmdACLPolicyUpdate replaces an ACL policy
+	CmdACLPolicyUpdate
+	// CmdACLPolicyDelete deletes an ACL policy
+	CmdACLPolicyDelete
+	// CmdAPIKeyCreate stores a generated API key
+	CmdAPIKeyCreate
+	// CmdAPIKeyUpdate updates an API key
+	CmdAPIKeyUpdate
+	// CmdAPIKeyRevoke disables an API key
+	CmdAPIKeyRevoke
+	// CmdAPIKeyDelete deletes an API key
+	CmdAPIKeyDelete
+
+	// CmdUserCreate stores a new user
+	CmdUserCreate
+	// CmdUserUpdate updates a user
+	CmdUserUpdate
+	// CmdUserDelete deletes a user
+	CmdUserDelete
+
+	// CmdPreparedQuerySet creates or replaces a prepared query
+	CmdPreparedQuerySet
+	// CmdPreparedQueryDelete deletes a prepared query
+	CmdPreparedQueryDelete
+
+	// CmdServerRoleSet records whether a server is a read replica
+	CmdServerRoleSet
+
+	// CmdServiceExpire removes service instances whose TTL has run out
+	CmdServiceExpire
 )
 
 // String returns the string representation of the command type.
@@ -81,6 +136,50 @@ func (c CommandType) String() string {
 		return "service_heartbeat"
 	case CmdHealthTTLUpdate:
 		return "health_ttl_update"
+	case CmdSessionCreate:
+		return "session_create"
+	case CmdSessionRenew:
+		return "session_renew"
+	case CmdSessionDestroy:
+		return "session_destroy"
+	case CmdKVAcquire:
+		return "kv_acquire"
+	case CmdKVRelease:
+		return "kv_release"
+	case CmdKVExpire:
+		return "kv_expire"
+	case CmdNamespaceCreate:
+		return "namespace_create"
+	case CmdNamespaceDelete:
+		return "namespace_delete"
+	case CmdACLPolicyCreate:
+		return "acl_policy_create"
+	case CmdACLPolicyUpdate:
+		return "acl_policy_update"
+	case CmdACLPolicyDelete:
+		return "acl_policy_delete"
+	case CmdAPIKeyCreate:
+		return "apikey_create"
+	case CmdAPIKeyUpdate:
+		return "apikey_update"
+	case CmdAPIKeyRevoke:
+		return "apikey_revoke"
+	case CmdAPIKeyDelete:
+		return "apikey_delete"
+	case CmdUserCreate:
+		return "user_create"
+	case CmdUserUpdate:
+		return "user_update"
+	case CmdUserDelete:
+		return "user_delete"
+	case CmdPreparedQuerySet:
+		return "prepared_query_set"
+	case CmdPreparedQueryDelete:
+		return "prepared_query_delete"
+	case CmdServerRoleSet:
+		return "server_role_set"
+	case CmdServiceExpire:
+		return "service_expire"
 	default:
 		return "unknown"
 	}
@@ -128,50 +227,81 @@ func UnmarshalCommand(data []byte) (*Command, error) {
 
 // --- Payload Definitions ---
 
+// ExpiresAt on the set payloads is the absolute expiry of a key written with a
+// TTL. It is computed by the leader so that every node expires the key at the
+// same moment; the zero value means the key does not expire.
+//
+// Namespace on the KV and service payloads scopes their keys and instance IDs;
+// empty means the default namespace. Batch payloads use one namespace for all
+// of their keys.
+
 type KVSetPayload struct {
-	Key   string `json:"key"`
-	Value string `json:"value"`
+	Namespace string    `json:"namespace,omitempty"`
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
 }
 
 type KVSetWithFlagsPayload struct {
-	Key   string `json:"key"`
-	Value string `json:"value"`
-	Flags uint64 `json:"flags"`
+	Namespace string    `json:"namespace,omitempty"`
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	Flags     uint64    `json:"flags"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+	Secret    bool      `json:"secret,omitempty"` // Value is sealed by the leader, see store.KVStore.SealSecret
 }
 
 type KVSetCASPayload struct {
-	Key           string `json:"key"`
-	Value         string `json:"value"`
-	ExpectedIndex uint64 `json:"expected_index"`
+	Namespace     string    `json:"namespace,omitempty"`
+	Key           string    `json:"key"`
+	Value         string    `json:"value"`
+	ExpectedIndex uint64    `json:"expected_index"`
+	ExpiresAt     time.Time `json:"expires_at,omitzero"`
+	Secret        bool      `json:"secret,omitempty"`
 }
 
 type KVDeletePayload struct {
-	Key string `json:"key"`
+	Namespace string `json:"namespace,omitempty"`
+	Key       string `json:"key"`
 }
 
 type KVDeleteCASPayload struct {
+	Namespace     string `json:"namespace,omitempty"`
 	Key           string `json:"key"`
 	ExpectedIndex uint64 `json:"expected_index"`
 }
 
 type KVBatchSetPayload struct {
-	Items map[string]string `json:"items"`
+	Namespace string            `json:"namespace,omitempty"`
+	Items     map[string]string `json:"items"`
+	ExpiresAt time.Time         `json:"expires_at,omitzero"`
 }
 
 type KVBatchSetCASPayload struct {
+	Namespace       string            `json:"namespace,omitempty"`
 	Items           map[string]string `json:"items"`
 	ExpectedIndices map[string]uint64 `json:"expected_indices"`
 }
 
 type KVBatchDeletePayload struct {
-	Keys []string `json:"keys"`
+	Namespace string   `json:"namespace,omitempty"`
+	Keys      []string `json:"keys"`
 }
 
 type KVBatchDeleteCASPayload struct {
+	Namespace       string            `json:"namespace,omitempty"`
 	Keys            []string          `json:"keys"`
 	ExpectedIndices map[string]uint64 `json:"expected_indices"`
 }
 
+// KVExpirePayload lists keys the leader found expired at Now. Keys rewritten
+// before the entry is applied are no longer expired at Now and are kept. The
+// keys are storage keys and already carry their namespace.
+type KVExpirePayload struct {
+	Keys []string  `json:"keys"`
+	Now  time.Time `json:"now"`
+}
+
 type ServiceRegisterPayload struct {
 	Service store.Service `json:"service"`
 }
@@ -181,23 +311,184 @@ type ServiceRegisterCASPayload struct {
 	ExpectedIndex uint64        `json:"expected_index"`
 }
 
+// ServiceDeregisterPayload identifies the instance to remove. Entries written
+// before instance IDs existed only carry Name, which doubles as the instance ID.
 type ServiceDeregisterPayload struct {
-	Name string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	ID        string `json:"id,omitempty"`
 }
 
 type ServiceDeregisterCASPayload struct {
-	Name          string `json:"name"`
+	Namespace     string `json:"namespace,omitempty"`
+	Name          string `json:"name,omitempty"`
+	ID            string `json:"id,omitempty"`
 	ExpectedIndex uint64 `json:"expected_index"`
 }
 
 type ServiceHeartbeatPayload struct {
-	Name string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	ID        string `json:"id,omitempty"`
+}
+
+// ServiceExpirePayload lists the instances the leader found expired, with
+// the ModifyIndex each was found expired at. Instances registered again
+// before the entry is applied are kept. The IDs are storage keys and already
+// carry their namespace.
+type ServiceExpirePayload struct {
+	Instances map[string]uint64 `json:"instances"`
+}
+
+// serviceInstanceID returns the instance ID carried by a service payload,
+// falling back to the service name for entries without one.
+func serviceInstanceID(name, id string) string {
+	if id != "" {
+		return id
+	}
+	return name
+}
+
+// namespacedItems qualifies the keys of a batch with its namespace.
+func namespacedItems[V any](ns string, items map[string]V) map[string]V {
+	if ns == "" || ns == store.DefaultNamespace || items == nil {
+		return items
+	}
+	result := make(map[string]V, len(items))
+	for key, v := range items {
+		result[store.NamespacedKey(ns, key)] = v
+	}
+	return result
+}
+
+// namespacedKeys qualifies a list of keys with their namespace.
+func namespacedKeys(ns string, keys []string) []string {
+	if ns == "" || ns == store.DefaultNamespace {
+		return keys
+	}
+	result := make([]string, len(keys))
+	for i, key := range keys {
+		result[i] = store.NamespacedKey(ns, key)
+	}
+	return result
+}
+
+// unqualifiedIndices maps the indices of a namespaced batch back to the keys
+// of its payload.
+func unqualifiedIndices(ns string, indices map[string]uint64) map[string]uint64 {
+	if ns == "" || ns == store.DefaultNamespace || indices == nil {
+		return indices
+	}
+	result := make(map[string]uint64, len(indices))
+	for storageKey, index := range indices {
+		_, key := store.SplitNamespacedKey(storageKey)
+		result[key] = index
+	}
+	return result
+}
+
+// payloadKey splits a storage key into the namespace and key of a payload.
+func payloadKey(storageKey string) (ns, key string) {
+	ns, key = store.SplitNamespacedKey(storageKey)
+	if ns == store.DefaultNamespace {
+		return "", key
+	}
+	return ns, key
 }
 
 type HealthTTLUpdatePayload struct {
 	CheckID string `json:"check_id"`
 }
 
+// SessionCreatePayload carries a prepared session; the ID and the expiry
+// (Session.ExpiresAt) are assigned before the entry is replicated.
+type SessionCreatePayload struct {
+	Session store.Session `json:"session"`
+}
+
+// SessionRenewPayload carries the new expiry of the session, like the set
+// payloads; it is zero for sessions without a TTL.
+type SessionRenewPayload struct {
+	ID        string    `json:"id"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+type SessionDestroyPayload struct {
+	ID string `json:"id"`
+}
+
+// KVLockPayload is shared by acquire and release; Value is ignored on release.
+type KVLockPayload struct {
+	Namespace string `json:"namespace,omitempty"`
+	Key       string `json:"key"`
+	Value     string `json:"value,omitempty"`
+	Session   string `json:"session"`
+}
+
+type NamespaceCreatePayload struct {
+	Namespace store.Namespace `json:"namespace"`
+}
+
+type NamespaceDeletePayload struct {
+	Name string `json:"name"`
+}
+
+// ACLPolicyPayload is shared by policy create and update.
+type ACLPolicyPayload struct {
+	Policy *acl.Policy `json:"policy"`
+}
+
+type ACLPolicyDeletePayload struct {
+	Name string `json:"name"`
+}
+
+// APIKeyCreatePayload carries a key generated by the leader. Only the hash of
+// the key is replicated.
+type APIKeyCreatePayload struct {
+	Key auth.APIKey `json:"key"`
+}
+
+type APIKeyUpdatePayload struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name,omitempty"`
+	Permissions []string          `json:"permissions,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Enabled     *bool             `json:"enabled,omitempty"`
+}
+
+// APIKeyDeletePayload is shared by revoke and delete.
+type APIKeyDeletePayload struct {
+	ID string `json:"id"`
+}
+
+// UserCreatePayload carries a user whose password the leader has hashed.
+type UserCreatePayload struct {
+	User auth.User `json:"user"`
+}
+
+type UserUpdatePayload struct {
+	Username string          `json:"username"`
+	Update   auth.UserUpdate `json:"update"`
+}
+
+type UserDeletePayload struct {
+	Username string `json:"username"`
+}
+
+type PreparedQuerySetPayload struct {
+	Query store.PreparedQuery `json:"query"`
+}
+
+type PreparedQueryDeletePayload struct {
+	Name string `json:"name"`
+}
+
+// ServerRolePayload records the role a server joined the cluster with.
+type ServerRolePayload struct {
+	NodeID      string `json:"node_id"`
+	ReadReplica bool   `json:"read_replica"`
+}
+
 // CASResult carries the result of a Compare-And-Swap operation through the Raft FSM.
 // FSM.Apply() returns *CASResult for all CAS command types so callers can extract
 // both the new index and any error from a single interface{} return value.
@@ -209,3 +500,28 @@ type CASResult struct {
 
 // Error implements the error interface for convenience.
 func (r *CASResult) Error() error { return r.Err }
+
+// SessionResult carries the session produced by create and renew commands.
+type SessionResult struct {
+	Session store.Session
+	Err     error
+}
+
+// NamespaceResult carries the namespace produced by create commands.
+type NamespaceResult struct {
+	Namespace store.Namespace
+	Err       error
+}
+
+// PreparedQueryResult carries the prepared query produced by set commands.
+type PreparedQueryResult struct {
+	Query store.PreparedQuery
+	Err   error
+}
+
+// LockResult carries the outcome of acquire and release commands.
+// Ok is false when the lock is held by another session.
+type LockResult struct {
+	Ok  bool
+	Err error
+}
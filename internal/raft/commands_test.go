@@ -137,6 +137,7 @@ func TestCommandTypeName(t *testing.T) {
 		{CmdServiceRegister, "service_register"},
 		{CmdServiceDeregister, "service_deregister"},
 		{CmdServiceHeartbeat, "service_heartbeat"},
+		{CmdServiceExpire, "service_expire"},
 		{CommandType(255), "unknown"},
 	}
 
@@ -16,6 +16,39 @@ type TLSConfig struct {
 	ServerName string
 }
 
+// ForwardingConfig configures how nodes other than the leader forward the
+// writes they receive to the leader.
+type ForwardingConfig struct {
+	// Enabled forwards the writes received by followers to the leader instead
+	// of rejecting them with ErrNotLeader. Read replicas always forward.
+	// Default: true
+	Enabled bool
+
+	// MaxAttempts is how many times a write is sent while no leader is known
+	// or the leader changes before accepting it, e.g. during an election.
+	// Default: 5
+	MaxAttempts int
+
+	// RetryWait is how long to wait before sending a write again.
+	// Default: 500ms
+	RetryWait time.Duration
+}
+
+// ServiceTTLConfig configures how the leader tracks the TTL of service
+// instances. Heartbeats only update the memory of the leader; the instances
+// whose TTL runs out are removed through the log.
+type ServiceTTLConfig struct {
+	// ExpiryInterval is how often the leader removes the instances whose TTL
+	// has run out. Zero disables the expiry.
+	// Default: 5s
+	ExpiryInterval time.Duration
+
+	// Grace is added to the TTL of every instance when a node becomes the
+	// leader, as the instances heartbeated the previous leader until then.
+	// Default: 10s
+	Grace time.Duration
+}
+
 // Config contains configuration for the Raft node.
 type Config struct {
 	// NodeID is the unique identifier for this node in the cluster.
@@ -37,6 +70,11 @@ type Config struct {
 	// Only set to true for the FIRST node in a new cluster.
 	Bootstrap bool
 
+	// NonVoter makes this node a read replica: it joins as a non-voter that
+	// never counts towards quorum and is never promoted by the autopilot. It
+	// serves reads locally and forwards writes to the leader.
+	NonVoter bool
+
 	// HeartbeatTimeout is the time between heartbeats from the leader.
 	// Default: 1s
 	HeartbeatTimeout time.Duration
@@ -67,6 +105,11 @@ type Config struct {
 	// Default: 2
 	SnapshotRetention int
 
+	// SnapshotCompression compresses snapshots: "zstd", "gzip" or "none".
+	// Snapshots are read whatever algorithm they were written with.
+	// Default: zstd
+	SnapshotCompression string
+
 	// MaxAppendEntries is the maximum number of log entries to send in a single AppendEntries RPC.
 	// Default: 64
 	MaxAppendEntries int
@@ -81,21 +124,42 @@ type Config struct {
 
 	// TLS Configuration
 	TLS TLSConfig
+
+	// Autopilot configures the health checks, promotions and dead server
+	// removals run by the leader.
+	Autopilot AutopilotConfig
+
+	// Forwarding configures the forwarding of writes to the leader.
+	Forwarding ForwardingConfig
+
+	// ServiceTTL configures the heartbeats and expiry of service instances.
+	ServiceTTL ServiceTTLConfig
 }
 
 // DefaultConfig returns a Config with sensible defaults.
 func DefaultConfig() *Config {
 	return &Config{
-		HeartbeatTimeout:   1000 * time.Millisecond,
-		ElectionTimeout:    1000 * time.Millisecond,
-		LeaderLeaseTimeout: 500 * time.Millisecond,
-		CommitTimeout:      50 * time.Millisecond,
-		SnapshotInterval:   120 * time.Second,
-		SnapshotThreshold:  8192,
-		SnapshotRetention:  2,
-		MaxAppendEntries:   64,
-		TrailingLogs:       10240,
-		LogLevel:           "info",
+		HeartbeatTimeout:    1000 * time.Millisecond,
+		ElectionTimeout:     1000 * time.Millisecond,
+		LeaderLeaseTimeout:  500 * time.Millisecond,
+		CommitTimeout:       50 * time.Millisecond,
+		SnapshotInterval:    120 * time.Second,
+		SnapshotThreshold:   8192,
+		SnapshotRetention:   2,
+		SnapshotCompression: "zstd",
+		MaxAppendEntries:    64,
+		TrailingLogs:        10240,
+		LogLevel:            "info",
+		Autopilot:           DefaultAutopilotConfig(),
+		Forwarding: ForwardingConfig{
+			Enabled:     true,
+			MaxAttempts: 5,
+			RetryWait:   500 * time.Millisecond,
+		},
+		ServiceTTL: ServiceTTLConfig{
+			ExpiryInterval: 5 * time.Second,
+			Grace:          10 * time.Second,
+		},
 	}
 }
 
@@ -110,6 +174,9 @@ func (c *Config) Validate() error {
 	if c.DataDir == "" {
 		return fmt.Errorf("DataDir is required")
 	}
+	if c.NonVoter && c.Bootstrap {
+		return fmt.Errorf("a NonVoter node cannot bootstrap the cluster")
+	}
 	if c.HeartbeatTimeout <= 0 {
 		return fmt.Errorf("HeartbeatTimeout must be positive")
 	}
@@ -122,6 +189,9 @@ func (c *Config) Validate() error {
 	if c.SnapshotThreshold == 0 {
 		return fmt.Errorf("SnapshotThreshold must be positive")
 	}
+	if _, err := snapshotCompression(c.SnapshotCompression); err != nil {
+		return err
+	}
 	if c.TLS.Enabled {
 		if c.TLS.CertFile == "" {
 			return fmt.Errorf("CertFile is required when TLS is enabled")
@@ -133,6 +203,23 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("CAFile is required when peer verification is enabled")
 		}
 	}
+	if c.Forwarding.MaxAttempts < 0 || c.Forwarding.RetryWait < 0 {
+		return fmt.Errorf("Forwarding.MaxAttempts and Forwarding.RetryWait must not be negative")
+	}
+	if c.ServiceTTL.ExpiryInterval < 0 || c.ServiceTTL.Grace < 0 {
+		return fmt.Errorf("ServiceTTL.ExpiryInterval and ServiceTTL.Grace must not be negative")
+	}
+	if c.Autopilot.Enabled {
+		if c.Autopilot.LastContactThreshold < 0 {
+			return fmt.Errorf("Autopilot.LastContactThreshold must not be negative")
+		}
+		if c.Autopilot.ServerStabilizationTime < 0 {
+			return fmt.Errorf("Autopilot.ServerStabilizationTime must not be negative")
+		}
+		if c.Autopilot.DeadServerThreshold < 0 {
+			return fmt.Errorf("Autopilot.DeadServerThreshold must not be negative")
+		}
+	}
 	return nil
 }
 
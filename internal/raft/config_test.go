@@ -17,9 +17,12 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal(t, 120*time.Second, cfg.SnapshotInterval)
 	assert.Equal(t, uint64(8192), cfg.SnapshotThreshold)
 	assert.Equal(t, 2, cfg.SnapshotRetention)
+	assert.Equal(t, "zstd", cfg.SnapshotCompression)
 	assert.Equal(t, 64, cfg.MaxAppendEntries)
 	assert.Equal(t, uint64(10240), cfg.TrailingLogs)
 	assert.Equal(t, "info", cfg.LogLevel)
+	assert.Equal(t, 5*time.Second, cfg.ServiceTTL.ExpiryInterval)
+	assert.Equal(t, 10*time.Second, cfg.ServiceTTL.Grace)
 }
 
 func TestConfig_Validate(t *testing.T) {
@@ -77,6 +80,21 @@ func TestConfig_Validate(t *testing.T) {
 			expectError: true,
 			errorMsg:    "DataDir is required",
 		},
+		{
+			name: "bootstrapping non-voter",
+			config: &Config{
+				NodeID:            "node1",
+				BindAddr:          "0.0.0.0:7000",
+				DataDir:           "/tmp/raft",
+				Bootstrap:         true,
+				NonVoter:          true,
+				HeartbeatTimeout:  1 * time.Second,
+				ElectionTimeout:   1 * time.Second,
+				SnapshotThreshold: 1000,
+			},
+			expectError: true,
+			errorMsg:    "cannot bootstrap",
+		},
 		{
 			name: "zero heartbeat timeout",
 			config: &Config{
@@ -116,6 +134,34 @@ func TestConfig_Validate(t *testing.T) {
 			expectError: true,
 			errorMsg:    "ElectionTimeout should be >= HeartbeatTimeout",
 		},
+		{
+			name: "unknown snapshot compression",
+			config: &Config{
+				NodeID:              "node1",
+				BindAddr:            "0.0.0.0:7000",
+				DataDir:             "/tmp/raft",
+				HeartbeatTimeout:    1 * time.Second,
+				ElectionTimeout:     1 * time.Second,
+				SnapshotThreshold:   1000,
+				SnapshotCompression: "lz4",
+			},
+			expectError: true,
+			errorMsg:    "unknown snapshot compression",
+		},
+		{
+			name: "negative service TTL grace",
+			config: &Config{
+				NodeID:            "node1",
+				BindAddr:          "0.0.0.0:7000",
+				DataDir:           "/tmp/raft",
+				HeartbeatTimeout:  1 * time.Second,
+				ElectionTimeout:   1 * time.Second,
+				SnapshotThreshold: 1000,
+				ServiceTTL:        ServiceTTLConfig{Grace: -time.Second},
+			},
+			expectError: true,
+			errorMsg:    "ServiceTTL.ExpiryInterval and ServiceTTL.Grace must not be negative",
+		},
 		{
 			name: "zero snapshot threshold",
 			config: &Config{
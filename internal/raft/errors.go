@@ -24,6 +24,31 @@ var (
 	// ErrApplyTimeout is returned when a Raft apply operation times out.
 	ErrApplyTimeout = errors.New("raft apply timeout")
 
+	// ErrLeadershipLost is returned when the leader lost its leadership before
+	// a write was committed. The write may or may not be applied.
+	ErrLeadershipLost = errors.New("leadership lost while committing write")
+
 	// ErrShutdown is returned when operations are attempted on a shutdown Raft node.
 	ErrShutdown = errors.New("raft node is shut down")
+
+	// ErrServiceNotFound is returned when a heartbeat is sent for a service instance the leader does not know.
+	ErrServiceNotFound = errors.New("service instance not found")
+
+	// ErrSessionsDisabled is returned when a session command reaches an FSM without a session store.
+	ErrSessionsDisabled = errors.New("session store not configured")
+
+	// ErrNamespacesDisabled is returned when a namespace command reaches an FSM without a namespace store.
+	ErrNamespacesDisabled = errors.New("namespace store not configured")
+
+	// ErrACLDisabled is returned when an ACL policy command reaches an FSM without a policy store.
+	ErrACLDisabled = errors.New("ACL policy store not configured")
+
+	// ErrAPIKeysDisabled is returned when an API key command reaches an FSM without an API key store.
+	ErrAPIKeysDisabled = errors.New("API key store not configured")
+
+	// ErrUsersDisabled is returned when a user command reaches an FSM without a user store.
+	ErrUsersDisabled = errors.New("user store not configured")
+
+	// ErrPreparedQueriesDisabled is returned when a prepared query command reaches an FSM without a prepared query store.
+	ErrPreparedQueriesDisabled = errors.New("prepared query store not configured")
 )
@@ -0,0 +1,112 @@
+package raft
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/raft"
+)
+
+// errResultUnavailable is returned when a forwarded command was applied but
+// its response was replaced, e.g. by a snapshot restore, before it was read.
+var errResultUnavailable = errors.New("forwarded command was applied but its result is unavailable")
+
+// forwardsWrites returns true if this node forwards writes to the leader
+// when it is not the leader itself.
+func (n *Node) forwardsWrites() bool {
+	return n.config.NonVoter || n.config.Forwarding.Enabled
+}
+
+// AcceptsWrites returns true if writes may be applied through this node:
+// the leader applies them, the other nodes forward them to the leader unless
+// forwarding is disabled.
+func (n *Node) AcceptsWrites() bool {
+	return n.raft.State() == raft.Leader || n.forwardsWrites()
+}
+
+// leaderAddress returns the address of the leader, or ErrNoLeader.
+func (n *Node) leaderAddress() (raft.ServerAddress, error) {
+	addr, _ := n.raft.LeaderWithID()
+	if addr == "" {
+		return "", ErrNoLeader
+	}
+	return addr, nil
+}
+
+// retryableForward returns true for the forwarding errors after which the
+// leader has certainly not applied the command, so that it can be sent again.
+func retryableForward(err error) bool {
+	return errors.Is(err, ErrNoLeader) || errors.Is(err, ErrNotLeader) || errors.Is(err, errDial)
+}
+
+// forwardEntry has the leader apply data, sending it again while no leader
+// is known or the leader changes, and returns the response of the local FSM
+// once it has applied the entry too. Callers thus get the same typed
+// responses as on the leader and read their own writes on this node.
+func (n *Node) forwardEntry(data []byte, timeout time.Duration) (interface{}, error) {
+	var resp interface{}
+	err := n.forwardWithRetries(func() (err error) {
+		resp, err = n.forwardOnce(data, timeout)
+		return err
+	}, func() (err error) {
+		resp, err = n.applyLocal(data, timeout)
+		return err
+	})
+	return resp, err
+}
+
+// forwardWithRetries calls send until it succeeds or fails with an error
+// that is not retryable, up to Forwarding.MaxAttempts times. If this node
+// wins an election in the meantime, it calls local instead.
+func (n *Node) forwardWithRetries(send, local func() error) error {
+	attempts := max(n.config.Forwarding.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		err := send()
+		if err == nil {
+			n.metrics.IncForwardedWrite(n.config.NodeID, "success")
+			return nil
+		}
+		if attempt >= attempts || !retryableForward(err) {
+			n.metrics.IncForwardedWrite(n.config.NodeID, "error")
+			return err
+		}
+
+		n.metrics.IncForwardedWrite(n.config.NodeID, "retry")
+		n.logger.Debug("retrying forwarded write", "attempt", attempt, "error", err)
+		select {
+		case <-time.After(n.config.Forwarding.RetryWait):
+		case <-n.shutdownCh:
+			return ErrShutdown
+		}
+
+		// This node may have won the election
+		if n.raft.State() == raft.Leader {
+			return local()
+		}
+	}
+}
+
+func (n *Node) forwardOnce(data []byte, timeout time.Duration) (interface{}, error) {
+	addr, err := n.leaderAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ForwardResponse
+	req := &ForwardRequest{From: n.config.NodeID, Command: data, Timeout: timeout}
+	if err := n.cluster.call(addr, "Cluster.Forward", req, &resp, timeout+rpcTimeout); err != nil {
+		return nil, err
+	}
+
+	if !n.fsm.applied.wait(resp.Index, timeout, n.shutdownCh) {
+		return nil, fmt.Errorf("timed out waiting for forwarded command at index %d", resp.Index)
+	}
+	if result, ok := n.fsm.applied.result(resp.Index); ok {
+		return result, nil
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return nil, errResultUnavailable
+}
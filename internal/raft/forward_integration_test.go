@@ -0,0 +1,210 @@
+package raft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neogan74/konsul/internal/acl"
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type forwardingTestNode struct {
+	*Node
+	kv       *store.KVStore
+	services *store.ServiceStore
+	sessions *store.SessionStore
+	acls     *acl.Evaluator
+}
+
+func startForwardingTestNode(t testing.TB, cfg *Config, serviceTTL time.Duration) *forwardingTestNode {
+	t.Helper()
+
+	kv := store.NewKVStore()
+	services := store.NewServiceStoreWithTTL(serviceTTL)
+	services.EnableReplicatedExpiry(true)
+	sessions := store.NewSessionStore(kv)
+	acls := acl.NewEvaluator(logger.GetDefault())
+	node, err := NewNodeWithFSM(cfg, FSMConfig{
+		KVStore:      kv,
+		ServiceStore: services,
+		SessionStore: sessions,
+		ACLStore:     acls,
+	})
+	require.NoError(t, err)
+	return &forwardingTestNode{Node: node, kv: kv, services: services, sessions: sessions, acls: acls}
+}
+
+// newForwardingCluster starts a three node cluster backed by real KV,
+// service, session and ACL stores and returns its nodes, the leader first.
+func newForwardingCluster(t *testing.T, override func(*Config)) []*forwardingTestNode {
+	t.Helper()
+	return newForwardingClusterWithServiceTTL(t, 30*time.Second, override)
+}
+
+func newForwardingClusterWithServiceTTL(t *testing.T, serviceTTL time.Duration, override func(*Config)) []*forwardingTestNode {
+	t.Helper()
+
+	var nodes []*forwardingTestNode
+	var cfgs []*Config
+	for i, id := range []string{"node-1", "node-2", "node-3"} {
+		cfg := newClusterConfigWithOverrides(t, id, getFreeAddr(t), i == 0, clusterOptions{}, override)
+		node := startForwardingTestNode(t, cfg, serviceTTL)
+		t.Cleanup(func() { _ = node.Shutdown() })
+		if i == 0 {
+			require.NoError(t, node.WaitForLeader(10*time.Second))
+		}
+		nodes = append(nodes, node)
+		cfgs = append(cfgs, cfg)
+	}
+
+	for _, cfg := range cfgs[1:] {
+		require.NoError(t, nodes[0].Join(cfg.NodeID, cfg.AdvertiseAddr))
+	}
+	for _, node := range nodes[1:] {
+		require.NoError(t, node.WaitForLeader(10*time.Second))
+	}
+	return nodes
+}
+
+func TestForwarding_WritesThroughFollower(t *testing.T) {
+	nodes := newForwardingCluster(t, nil)
+	leader, follower := nodes[0], nodes[1]
+	require.True(t, leader.IsLeader())
+	assert.True(t, follower.AcceptsWrites())
+
+	// KV writes are applied by the leader and read back on the follower
+	require.NoError(t, follower.KVSet("app/config", "v1"))
+	value, ok := leader.kv.Get("app/config")
+	assert.True(t, ok)
+	assert.Equal(t, "v1", value)
+	value, ok = follower.kv.Get("app/config")
+	assert.True(t, ok, "the follower returns once it has applied its write")
+	assert.Equal(t, "v1", value)
+	waitForKVEntry(t, nodes[2].kv, "app/config", "v1", 5*time.Second)
+
+	// CAS and batch responses come back typed
+	index, err := follower.KVSetCAS("app/lock", "a", 0)
+	require.NoError(t, err)
+	assert.NotZero(t, index)
+	_, err = follower.KVSetCAS("app/lock", "b", 0)
+	assert.ErrorContains(t, err, "CAS conflict")
+
+	indices, err := follower.KVBatchSetCAS(map[string]string{"batch/a": "1", "batch/b": "2"},
+		map[string]uint64{"batch/a": 0, "batch/b": 0})
+	require.NoError(t, err)
+	assert.Len(t, indices, 2)
+	value, ok = leader.kv.Get("batch/b")
+	assert.True(t, ok)
+	assert.Equal(t, "2", value)
+
+	// Services
+	require.NoError(t, follower.ServiceRegister(store.Service{Name: "web", Address: "10.0.0.1", Port: 80}))
+	_, ok = leader.services.Get("web")
+	assert.True(t, ok)
+
+	// Sessions
+	session := store.Session{Name: "worker", Behavior: store.SessionBehaviorDelete}
+	require.NoError(t, store.PrepareSession(&session))
+	created, err := follower.SessionCreate(session)
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, created.ID)
+	_, ok = leader.sessions.Get(session.ID)
+	assert.True(t, ok)
+
+	// ACL policies, with the errors of the FSM passed through
+	policy := &acl.Policy{
+		Name: "readers",
+		KV:   []acl.KVRule{{Path: "app/*", Capabilities: []acl.Capability{acl.CapabilityRead}}},
+	}
+	require.NoError(t, follower.ACLPolicyCreate(policy))
+	_, err = leader.acls.GetPolicy("readers")
+	require.NoError(t, err)
+	assert.ErrorIs(t, follower.ACLPolicyCreate(policy), acl.ErrPolicyExists)
+}
+
+func TestForwarding_SessionExpiryReplicated(t *testing.T) {
+	nodes := newForwardingCluster(t, nil)
+	follower := nodes[1]
+
+	session := store.Session{Name: "worker", TTL: "30s"}
+	require.NoError(t, store.PrepareSession(&session))
+	created, err := follower.SessionCreate(session)
+	require.NoError(t, err)
+	require.False(t, created.ExpiresAt.IsZero())
+
+	// Every node stores the expiry computed before the entry was replicated
+	sameExpiry := func(expiresAt time.Time) bool {
+		for _, node := range nodes {
+			stored, ok := node.sessions.Get(session.ID)
+			if !ok || !stored.ExpiresAt.Equal(expiresAt) {
+				return false
+			}
+		}
+		return true
+	}
+	require.Eventually(t, func() bool { return sameExpiry(created.ExpiresAt) }, 5*time.Second, 50*time.Millisecond)
+
+	time.Sleep(10 * time.Millisecond)
+	renewed, err := follower.SessionRenew(session.ID)
+	require.NoError(t, err)
+	assert.True(t, renewed.ExpiresAt.After(created.ExpiresAt))
+	require.Eventually(t, func() bool { return sameExpiry(renewed.ExpiresAt) }, 5*time.Second, 50*time.Millisecond)
+
+	_, err = follower.SessionRenew("unknown")
+	assert.True(t, store.IsNotFound(err))
+}
+
+func TestForwarding_Disabled(t *testing.T) {
+	nodes := newForwardingCluster(t, func(cfg *Config) {
+		cfg.Forwarding.Enabled = false
+	})
+	leader, follower := nodes[0], nodes[1]
+
+	assert.True(t, leader.AcceptsWrites())
+	assert.False(t, follower.AcceptsWrites())
+	assert.ErrorIs(t, follower.KVSet("app/config", "v1"), ErrNotLeader)
+	_, ok := leader.kv.Get("app/config")
+	assert.False(t, ok)
+}
+
+func TestForwarding_RetriesAcrossElection(t *testing.T) {
+	nodes := newForwardingCluster(t, func(cfg *Config) {
+		cfg.Forwarding.MaxAttempts = 30
+		cfg.Forwarding.RetryWait = 200 * time.Millisecond
+	})
+	require.NoError(t, nodes[0].Shutdown())
+
+	// The write is sent again until one of the remaining nodes is elected
+	follower := nodes[1]
+	require.NoError(t, follower.KVSet("app/config", "after-election"))
+
+	leader := waitForSingleLeader(t, []*Node{nodes[1].Node, nodes[2].Node}, 10*time.Second)
+	assert.NotEqual(t, nodes[0].Node, leader)
+	for _, node := range nodes[1:] {
+		waitForKVEntry(t, node.kv, "app/config", "after-election", 5*time.Second)
+	}
+}
+
+func TestForwarding_NoRetries(t *testing.T) {
+	nodes := newForwardingCluster(t, func(cfg *Config) {
+		cfg.Forwarding.MaxAttempts = 1
+	})
+	require.NoError(t, nodes[0].Shutdown())
+
+	// Without retries the write fails while the leader is gone
+	err := nodes[1].KVSet("app/config", "v1")
+	require.Error(t, err)
+	assert.True(t, retryableForward(err), "unexpected error: %v", err)
+}
+
+// waitForKVEntry polls until kv has key=expected or timeout expires.
+func waitForKVEntry(t *testing.T, kv *store.KVStore, key, expected string, timeout time.Duration) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		value, ok := kv.Get(key)
+		return ok && value == expected
+	}, timeout, 50*time.Millisecond, "key %q never became %q", key, expected)
+}
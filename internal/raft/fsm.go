@@ -1,13 +1,17 @@
 package raft
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"sync"
 
 	"github.com/hashicorp/raft"
 
+	"github.com/neogan74/konsul/internal/acl"
+	"github.com/neogan74/konsul/internal/auth"
 	"github.com/neogan74/konsul/internal/store"
 )
 
@@ -17,6 +21,21 @@ type KonsulFSM struct {
 	mu           sync.RWMutex
 	kvStore      KVStoreInterface
 	serviceStore ServiceStoreInterface
+	sessionStore SessionStoreInterface
+	nsStore      NamespaceStoreInterface
+	aclStore     PolicyStoreInterface
+	apiKeyStore  APIKeyStoreInterface
+	userStore    UserStoreInterface
+	queryStore   PreparedQueryStoreInterface
+
+	// readReplicas holds the IDs of the servers that joined as read replicas
+	readReplicas map[string]bool
+
+	// applied tracks the entries applied, for read replicas
+	applied *appliedResults
+
+	// compression is the algorithm snapshots are compressed with
+	compression byte
 
 	// Metrics callbacks (optional)
 	onApply func(cmdType CommandType, duration float64, err error)
@@ -24,16 +43,36 @@ type KonsulFSM struct {
 
 // FSMConfig contains configuration for the FSM.
 type FSMConfig struct {
-	KVStore      KVStoreInterface
-	ServiceStore ServiceStoreInterface
-	OnApply      func(cmdType CommandType, duration float64, err error)
+	KVStore        KVStoreInterface
+	ServiceStore   ServiceStoreInterface
+	SessionStore   SessionStoreInterface       // optional; session commands fail without it
+	NamespaceStore NamespaceStoreInterface     // optional; namespace commands fail without it
+	ACLStore       PolicyStoreInterface        // optional; ACL policy commands fail without it
+	APIKeyStore    APIKeyStoreInterface        // optional; API key commands fail without it
+	UserStore      UserStoreInterface          // optional; user commands fail without it
+	QueryStore     PreparedQueryStoreInterface // optional; prepared query commands fail without it
+	OnApply        func(cmdType CommandType, duration float64, err error)
+
+	// SnapshotCompression is "zstd" (default), "gzip" or "none"; see Config
+	SnapshotCompression string
 }
 
-// NewFSM creates a new KonsulFSM instance.
+// NewFSM creates a new KonsulFSM instance. An unknown SnapshotCompression
+// falls back to zstd.
 func NewFSM(cfg FSMConfig) *KonsulFSM {
+	compression, _ := snapshotCompression(cfg.SnapshotCompression)
 	return &KonsulFSM{
 		kvStore:      cfg.KVStore,
 		serviceStore: cfg.ServiceStore,
+		sessionStore: cfg.SessionStore,
+		nsStore:      cfg.NamespaceStore,
+		aclStore:     cfg.ACLStore,
+		apiKeyStore:  cfg.APIKeyStore,
+		userStore:    cfg.UserStore,
+		queryStore:   cfg.QueryStore,
+		readReplicas: make(map[string]bool),
+		applied:      newAppliedResults(),
+		compression:  compression,
 		onApply:      cfg.OnApply,
 	}
 }
@@ -44,8 +83,22 @@ func NewFSM(cfg FSMConfig) *KonsulFSM {
 //
 // Return value convention:
 //   - CAS commands return *CASResult (carries both NewIndex and Err)
+//   - Session create/renew return *SessionResult, acquire/release return *LockResult
+//   - Namespace create returns *NamespaceResult
 //   - All other commands return error (or nil)
 func (f *KonsulFSM) Apply(log *raft.Log) interface{} {
+	resp := f.applyLog(log)
+	f.applied.record(log.Index, resp)
+	return resp
+}
+
+// StoreConfiguration implements raft.ConfigurationStore. Configuration
+// entries change no state but count as applied for read replicas.
+func (f *KonsulFSM) StoreConfiguration(index uint64, _ raft.Configuration) {
+	f.applied.record(index, nil)
+}
+
+func (f *KonsulFSM) applyLog(log *raft.Log) interface{} {
 	cmd, err := UnmarshalCommand(log.Data)
 	if err != nil {
 		return fmt.Errorf("failed to unmarshal command: %w", err)
@@ -63,6 +116,8 @@ func (f *KonsulFSM) Apply(log *raft.Log) interface{} {
 		return f.applyKVBatchSet(cmd.Payload)
 	case CmdKVBatchDelete:
 		return f.applyKVBatchDelete(cmd.Payload)
+	case CmdKVExpire:
+		return f.applyKVExpire(cmd.Payload)
 
 	// --- CAS KV — return *CASResult ---
 	case CmdKVSetCAS:
@@ -81,6 +136,8 @@ func (f *KonsulFSM) Apply(log *raft.Log) interface{} {
 		return f.applyServiceDeregister(cmd.Payload)
 	case CmdServiceHeartbeat:
 		return f.applyServiceHeartbeat(cmd.Payload)
+	case CmdServiceExpire:
+		return f.applyServiceExpire(cmd.Payload)
 	case CmdHealthTTLUpdate:
 		return f.applyHealthTTLUpdate(cmd.Payload)
 
@@ -90,6 +147,58 @@ func (f *KonsulFSM) Apply(log *raft.Log) interface{} {
 	case CmdServiceDeregisterCAS:
 		return f.applyServiceDeregisterCAS(cmd.Payload)
 
+	// --- Sessions and locks ---
+	case CmdSessionCreate:
+		return f.applySessionCreate(cmd.Payload)
+	case CmdSessionRenew:
+		return f.applySessionRenew(cmd.Payload)
+	case CmdSessionDestroy:
+		return f.applySessionDestroy(cmd.Payload)
+	case CmdKVAcquire:
+		return f.applyKVAcquire(cmd.Payload)
+	case CmdKVRelease:
+		return f.applyKVRelease(cmd.Payload)
+
+	// --- Namespaces ---
+	case CmdNamespaceCreate:
+		return f.applyNamespaceCreate(cmd.Payload)
+	case CmdNamespaceDelete:
+		return f.applyNamespaceDelete(cmd.Payload)
+
+	// --- ACL policies and API keys ---
+	case CmdACLPolicyCreate:
+		return f.applyACLPolicyCreate(cmd.Payload)
+	case CmdACLPolicyUpdate:
+		return f.applyACLPolicyUpdate(cmd.Payload)
+	case CmdACLPolicyDelete:
+		return f.applyACLPolicyDelete(cmd.Payload)
+	case CmdAPIKeyCreate:
+		return f.applyAPIKeyCreate(cmd.Payload)
+	case CmdAPIKeyUpdate:
+		return f.applyAPIKeyUpdate(cmd.Payload)
+	case CmdAPIKeyRevoke:
+		return f.applyAPIKeyRevoke(cmd.Payload)
+	case CmdAPIKeyDelete:
+		return f.applyAPIKeyDelete(cmd.Payload)
+
+	// --- Users ---
+	case CmdUserCreate:
+		return f.applyUserCreate(cmd.Payload)
+	case CmdUserUpdate:
+		return f.applyUserUpdate(cmd.Payload)
+	case CmdUserDelete:
+		return f.applyUserDelete(cmd.Payload)
+
+	// --- Prepared queries ---
+	case CmdPreparedQuerySet:
+		return f.applyPreparedQuerySet(cmd.Payload)
+	case CmdPreparedQueryDelete:
+		return f.applyPreparedQueryDelete(cmd.Payload)
+
+	// --- Servers ---
+	case CmdServerRoleSet:
+		return f.applyServerRoleSet(cmd.Payload)
+
 	default:
 		return fmt.Errorf("unknown command type: %d", cmd.Type)
 	}
@@ -106,7 +215,12 @@ func (f *KonsulFSM) applyKVSet(payload []byte) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	f.kvStore.SetLocal(p.Key, p.Value)
+	key := store.NamespacedKey(p.Namespace, p.Key)
+	if !p.ExpiresAt.IsZero() {
+		f.kvStore.SetWithExpiryLocal(key, p.Value, 0, p.ExpiresAt)
+		return nil
+	}
+	f.kvStore.SetLocal(key, p.Value)
 	return nil
 }
 
@@ -119,7 +233,16 @@ func (f *KonsulFSM) applyKVSetWithFlags(payload []byte) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	f.kvStore.SetWithFlagsLocal(p.Key, p.Value, p.Flags)
+	key := store.NamespacedKey(p.Namespace, p.Key)
+	if p.Secret {
+		f.kvStore.SetSealedSecretLocal(key, p.Value, p.Flags, p.ExpiresAt)
+		return nil
+	}
+	if !p.ExpiresAt.IsZero() {
+		f.kvStore.SetWithExpiryLocal(key, p.Value, p.Flags, p.ExpiresAt)
+		return nil
+	}
+	f.kvStore.SetWithFlagsLocal(key, p.Value, p.Flags)
 	return nil
 }
 
@@ -132,7 +255,7 @@ func (f *KonsulFSM) applyKVDelete(payload []byte) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	f.kvStore.DeleteLocal(p.Key)
+	f.kvStore.DeleteLocal(store.NamespacedKey(p.Namespace, p.Key))
 	return nil
 }
 
@@ -145,7 +268,11 @@ func (f *KonsulFSM) applyKVBatchSet(payload []byte) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	return f.kvStore.BatchSetLocal(p.Items)
+	items := namespacedItems(p.Namespace, p.Items)
+	if !p.ExpiresAt.IsZero() {
+		return f.kvStore.BatchSetWithExpiryLocal(items, p.ExpiresAt)
+	}
+	return f.kvStore.BatchSetLocal(items)
 }
 
 func (f *KonsulFSM) applyKVBatchDelete(payload []byte) error {
@@ -157,7 +284,20 @@ func (f *KonsulFSM) applyKVBatchDelete(payload []byte) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	return f.kvStore.BatchDeleteLocal(p.Keys)
+	return f.kvStore.BatchDeleteLocal(namespacedKeys(p.Namespace, p.Keys))
+}
+
+func (f *KonsulFSM) applyKVExpire(payload []byte) error {
+	var p KVExpirePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal KVExpirePayload: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.kvStore.DeleteExpiredLocal(p.Keys, p.Now)
+	return nil
 }
 
 func (f *KonsulFSM) applyKVSetCAS(payload []byte) *CASResult {
@@ -169,7 +309,16 @@ func (f *KonsulFSM) applyKVSetCAS(payload []byte) *CASResult {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	newIndex, err := f.kvStore.SetCASLocal(p.Key, p.Value, p.ExpectedIndex)
+	key := store.NamespacedKey(p.Namespace, p.Key)
+	var newIndex uint64
+	var err error
+	if p.Secret {
+		newIndex, err = f.kvStore.SetSealedSecretCASLocal(key, p.Value, p.ExpectedIndex, p.ExpiresAt)
+	} else if !p.ExpiresAt.IsZero() {
+		newIndex, err = f.kvStore.SetCASWithExpiryLocal(key, p.Value, p.ExpectedIndex, p.ExpiresAt)
+	} else {
+		newIndex, err = f.kvStore.SetCASLocal(key, p.Value, p.ExpectedIndex)
+	}
 	return &CASResult{NewIndex: newIndex, Err: err}
 }
 
@@ -182,7 +331,7 @@ func (f *KonsulFSM) applyKVDeleteCAS(payload []byte) *CASResult {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	err := f.kvStore.DeleteCASLocal(p.Key, p.ExpectedIndex)
+	err := f.kvStore.DeleteCASLocal(store.NamespacedKey(p.Namespace, p.Key), p.ExpectedIndex)
 	return &CASResult{Err: err}
 }
 
@@ -195,8 +344,11 @@ func (f *KonsulFSM) applyKVBatchSetCAS(payload []byte) *CASResult {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	indices, err := f.kvStore.BatchSetCASLocal(p.Items, p.ExpectedIndices)
-	return &CASResult{NewIndices: indices, Err: err}
+	indices, err := f.kvStore.BatchSetCASLocal(
+		namespacedItems(p.Namespace, p.Items),
+		namespacedItems(p.Namespace, p.ExpectedIndices),
+	)
+	return &CASResult{NewIndices: unqualifiedIndices(p.Namespace, indices), Err: err}
 }
 
 func (f *KonsulFSM) applyKVBatchDeleteCAS(payload []byte) *CASResult {
@@ -208,7 +360,10 @@ func (f *KonsulFSM) applyKVBatchDeleteCAS(payload []byte) *CASResult {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	err := f.kvStore.BatchDeleteCASLocal(p.Keys, p.ExpectedIndices)
+	err := f.kvStore.BatchDeleteCASLocal(
+		namespacedKeys(p.Namespace, p.Keys),
+		namespacedItems(p.Namespace, p.ExpectedIndices),
+	)
 	return &CASResult{Err: err}
 }
 
@@ -224,11 +379,14 @@ func (f *KonsulFSM) applyServiceRegister(payload []byte) error {
 	defer f.mu.Unlock()
 
 	service := store.ServiceDataSnapshot{
-		Name:    p.Service.Name,
-		Address: p.Service.Address,
-		Port:    p.Service.Port,
-		Tags:    p.Service.Tags,
-		Meta:    p.Service.Meta,
+		ID:        p.Service.ID,
+		Name:      p.Service.Name,
+		Node:      p.Service.Node,
+		Address:   p.Service.Address,
+		Port:      p.Service.Port,
+		Tags:      p.Service.Tags,
+		Meta:      p.Service.Meta,
+		Namespace: p.Service.Namespace,
 	}
 
 	return f.serviceStore.RegisterLocal(service)
@@ -243,10 +401,12 @@ func (f *KonsulFSM) applyServiceDeregister(payload []byte) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	f.serviceStore.DeregisterLocal(p.Name)
+	f.serviceStore.DeregisterLocal(store.NamespacedKey(p.Namespace, serviceInstanceID(p.Name, p.ID)))
 	return nil
 }
 
+// applyServiceHeartbeat applies the heartbeats written to the log before the
+// leader tracked them in memory.
 func (f *KonsulFSM) applyServiceHeartbeat(payload []byte) error {
 	var p ServiceHeartbeatPayload
 	if err := json.Unmarshal(payload, &p); err != nil {
@@ -256,7 +416,20 @@ func (f *KonsulFSM) applyServiceHeartbeat(payload []byte) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	f.serviceStore.HeartbeatLocal(p.Name)
+	f.serviceStore.HeartbeatLocal(store.NamespacedKey(p.Namespace, serviceInstanceID(p.Name, p.ID)))
+	return nil
+}
+
+func (f *KonsulFSM) applyServiceExpire(payload []byte) error {
+	var p ServiceExpirePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal ServiceExpirePayload: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.serviceStore.ExpireLocal(p.Instances)
 	return nil
 }
 
@@ -270,11 +443,14 @@ func (f *KonsulFSM) applyServiceRegisterCAS(payload []byte) *CASResult {
 	defer f.mu.Unlock()
 
 	service := store.ServiceDataSnapshot{
-		Name:    p.Service.Name,
-		Address: p.Service.Address,
-		Port:    p.Service.Port,
-		Tags:    p.Service.Tags,
-		Meta:    p.Service.Meta,
+		ID:        p.Service.ID,
+		Name:      p.Service.Name,
+		Node:      p.Service.Node,
+		Address:   p.Service.Address,
+		Port:      p.Service.Port,
+		Tags:      p.Service.Tags,
+		Meta:      p.Service.Meta,
+		Namespace: p.Service.Namespace,
 	}
 
 	newIndex, err := f.serviceStore.RegisterCASLocal(service, p.ExpectedIndex)
@@ -290,7 +466,7 @@ func (f *KonsulFSM) applyServiceDeregisterCAS(payload []byte) *CASResult {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	err := f.serviceStore.DeregisterCASLocal(p.Name, p.ExpectedIndex)
+	err := f.serviceStore.DeregisterCASLocal(store.NamespacedKey(p.Namespace, serviceInstanceID(p.Name, p.ID)), p.ExpectedIndex)
 	return &CASResult{Err: err}
 }
 
@@ -306,73 +482,564 @@ func (f *KonsulFSM) applyHealthTTLUpdate(payload []byte) error {
 	return f.serviceStore.UpdateTTLCheck(p.CheckID)
 }
 
+// --- Session Apply Methods ---
+
+func (f *KonsulFSM) applySessionCreate(payload []byte) *SessionResult {
+	var p SessionCreatePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return &SessionResult{Err: fmt.Errorf("failed to unmarshal SessionCreatePayload: %w", err)}
+	}
+	if f.sessionStore == nil {
+		return &SessionResult{Err: ErrSessionsDisabled}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	session, err := f.sessionStore.CreateLocal(p.Session)
+	return &SessionResult{Session: session, Err: err}
+}
+
+func (f *KonsulFSM) applySessionRenew(payload []byte) *SessionResult {
+	var p SessionRenewPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return &SessionResult{Err: fmt.Errorf("failed to unmarshal SessionRenewPayload: %w", err)}
+	}
+	if f.sessionStore == nil {
+		return &SessionResult{Err: ErrSessionsDisabled}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	session, err := f.sessionStore.RenewLocal(p.ID, p.ExpiresAt)
+	return &SessionResult{Session: session, Err: err}
+}
+
+func (f *KonsulFSM) applySessionDestroy(payload []byte) error {
+	var p SessionDestroyPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal SessionDestroyPayload: %w", err)
+	}
+	if f.sessionStore == nil {
+		return ErrSessionsDisabled
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.sessionStore.DestroyLocal(p.ID)
+}
+
+func (f *KonsulFSM) applyKVAcquire(payload []byte) *LockResult {
+	var p KVLockPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return &LockResult{Err: fmt.Errorf("failed to unmarshal KVLockPayload: %w", err)}
+	}
+	if f.sessionStore == nil {
+		return &LockResult{Err: ErrSessionsDisabled}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ok, err := f.sessionStore.AcquireLocal(store.NamespacedKey(p.Namespace, p.Key), p.Value, p.Session)
+	return &LockResult{Ok: ok, Err: err}
+}
+
+func (f *KonsulFSM) applyKVRelease(payload []byte) *LockResult {
+	var p KVLockPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return &LockResult{Err: fmt.Errorf("failed to unmarshal KVLockPayload: %w", err)}
+	}
+	if f.sessionStore == nil {
+		return &LockResult{Err: ErrSessionsDisabled}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ok, err := f.sessionStore.ReleaseLocal(store.NamespacedKey(p.Namespace, p.Key), p.Session)
+	return &LockResult{Ok: ok, Err: err}
+}
+
+// --- Namespace Apply Methods ---
+
+func (f *KonsulFSM) applyNamespaceCreate(payload []byte) *NamespaceResult {
+	var p NamespaceCreatePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return &NamespaceResult{Err: fmt.Errorf("failed to unmarshal NamespaceCreatePayload: %w", err)}
+	}
+	if f.nsStore == nil {
+		return &NamespaceResult{Err: ErrNamespacesDisabled}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ns, err := f.nsStore.Create(p.Namespace)
+	return &NamespaceResult{Namespace: ns, Err: err}
+}
+
+func (f *KonsulFSM) applyNamespaceDelete(payload []byte) error {
+	var p NamespaceDeletePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal NamespaceDeletePayload: %w", err)
+	}
+	if f.nsStore == nil {
+		return ErrNamespacesDisabled
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.nsStore.Delete(p.Name)
+}
+
+// --- ACL Policy Apply Methods ---
+
+func (f *KonsulFSM) applyACLPolicyCreate(payload []byte) error {
+	var p ACLPolicyPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal ACLPolicyPayload: %w", err)
+	}
+	if f.aclStore == nil {
+		return ErrACLDisabled
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.aclStore.AddPolicy(p.Policy)
+}
+
+func (f *KonsulFSM) applyACLPolicyUpdate(payload []byte) error {
+	var p ACLPolicyPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal ACLPolicyPayload: %w", err)
+	}
+	if f.aclStore == nil {
+		return ErrACLDisabled
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.aclStore.UpdatePolicy(p.Policy)
+}
+
+func (f *KonsulFSM) applyACLPolicyDelete(payload []byte) error {
+	var p ACLPolicyDeletePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal ACLPolicyDeletePayload: %w", err)
+	}
+	if f.aclStore == nil {
+		return ErrACLDisabled
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.aclStore.DeletePolicy(p.Name)
+}
+
+// --- API Key Apply Methods ---
+
+func (f *KonsulFSM) applyAPIKeyCreate(payload []byte) error {
+	var p APIKeyCreatePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal APIKeyCreatePayload: %w", err)
+	}
+	if f.apiKeyStore == nil {
+		return ErrAPIKeysDisabled
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.apiKeyStore.AddAPIKey(&p.Key)
+}
+
+func (f *KonsulFSM) applyAPIKeyUpdate(payload []byte) error {
+	var p APIKeyUpdatePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal APIKeyUpdatePayload: %w", err)
+	}
+	if f.apiKeyStore == nil {
+		return ErrAPIKeysDisabled
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.apiKeyStore.UpdateAPIKey(p.ID, p.Name, p.Permissions, p.Metadata, p.Enabled)
+}
+
+func (f *KonsulFSM) applyAPIKeyRevoke(payload []byte) error {
+	var p APIKeyDeletePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal APIKeyDeletePayload: %w", err)
+	}
+	if f.apiKeyStore == nil {
+		return ErrAPIKeysDisabled
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.apiKeyStore.RevokeAPIKey(p.ID)
+}
+
+func (f *KonsulFSM) applyAPIKeyDelete(payload []byte) error {
+	var p APIKeyDeletePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal APIKeyDeletePayload: %w", err)
+	}
+	if f.apiKeyStore == nil {
+		return ErrAPIKeysDisabled
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.apiKeyStore.DeleteAPIKey(p.ID)
+}
+
+// --- User Apply Methods ---
+
+func (f *KonsulFSM) applyUserCreate(payload []byte) error {
+	var p UserCreatePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal UserCreatePayload: %w", err)
+	}
+	if f.userStore == nil {
+		return ErrUsersDisabled
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.userStore.AddUser(&p.User)
+}
+
+func (f *KonsulFSM) applyUserUpdate(payload []byte) error {
+	var p UserUpdatePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal UserUpdatePayload: %w", err)
+	}
+	if f.userStore == nil {
+		return ErrUsersDisabled
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.userStore.UpdateUser(p.Username, p.Update)
+}
+
+func (f *KonsulFSM) applyUserDelete(payload []byte) error {
+	var p UserDeletePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal UserDeletePayload: %w", err)
+	}
+	if f.userStore == nil {
+		return ErrUsersDisabled
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.userStore.DeleteUser(p.Username)
+}
+
+// --- Prepared Query Apply Methods ---
+
+func (f *KonsulFSM) applyPreparedQuerySet(payload []byte) *PreparedQueryResult {
+	var p PreparedQuerySetPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return &PreparedQueryResult{Err: fmt.Errorf("failed to unmarshal PreparedQuerySetPayload: %w", err)}
+	}
+	if f.queryStore == nil {
+		return &PreparedQueryResult{Err: ErrPreparedQueriesDisabled}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	q, err := f.queryStore.Set(p.Query)
+	return &PreparedQueryResult{Query: q, Err: err}
+}
+
+func (f *KonsulFSM) applyPreparedQueryDelete(payload []byte) error {
+	var p PreparedQueryDeletePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal PreparedQueryDeletePayload: %w", err)
+	}
+	if f.queryStore == nil {
+		return ErrPreparedQueriesDisabled
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.queryStore.Delete(p.Name)
+}
+
+// --- Server Apply Methods ---
+
+func (f *KonsulFSM) applyServerRoleSet(payload []byte) error {
+	var p ServerRolePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal ServerRolePayload: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if p.ReadReplica {
+		f.readReplicas[p.NodeID] = true
+	} else {
+		delete(f.readReplicas, p.NodeID)
+	}
+	return nil
+}
+
+// IsReadReplica returns true if the server nodeID joined as a read replica.
+func (f *KonsulFSM) IsReadReplica(nodeID string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.readReplicas[nodeID]
+}
+
 // Snapshot implements raft.FSM.Snapshot.
 // It returns a snapshot of the current state for persistence.
 // Raft calls this periodically to compact the log.
+//
+// Raft keeps applying entries while the snapshot is persisted. KV and service
+// data are not copied here: Persist reads them chunk by chunk from views
+// opened on the stores, which keep the records of the keys changed in the
+// meantime (see store.KVStore.OpenSnapshot). The other sections are small
+// and copied here.
 func (f *KonsulFSM) Snapshot() (raft.FSMSnapshot, error) {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
-	// Create deep copies of the data
-	kvData := f.kvStore.GetAllData()
-	serviceData := f.serviceStore.GetAllData()
+	// No entry is applied while Snapshot runs, so the views and the copies
+	// of the other sections are all taken at the same index
+	kvRecords := f.kvStore.OpenSnapshot()
+	serviceRecords := f.serviceStore.OpenSnapshot()
+
+	var sessionData map[string]store.Session
+	if f.sessionStore != nil {
+		sessionData = f.sessionStore.GetAllData()
+	}
+
+	var namespaceData map[string]store.Namespace
+	if f.nsStore != nil {
+		namespaceData = f.nsStore.GetAllData()
+	}
+
+	var aclPolicies map[string]*acl.Policy
+	if f.aclStore != nil {
+		aclPolicies = f.aclStore.GetAllPolicies()
+	}
+
+	var apiKeys map[string]auth.APIKey
+	if f.apiKeyStore != nil {
+		apiKeys = f.apiKeyStore.GetAllAPIKeys()
+	}
+
+	var users map[string]auth.User
+	if f.userStore != nil {
+		users = f.userStore.GetAllUsers()
+	}
+
+	var preparedQueries map[string]store.PreparedQuery
+	if f.queryStore != nil {
+		preparedQueries = f.queryStore.GetAllData()
+	}
+
+	readReplicas := make([]string, 0, len(f.readReplicas))
+	for id := range f.readReplicas {
+		readReplicas = append(readReplicas, id)
+	}
+	sort.Strings(readReplicas)
 
 	return &KonsulSnapshot{
-		KVData:      kvData,
-		ServiceData: serviceData,
+		Index:           f.applied.lastIndex(),
+		KVRecords:       kvRecords,
+		ServiceRecords:  serviceRecords,
+		SessionData:     sessionData,
+		NamespaceData:   namespaceData,
+		ACLPolicies:     aclPolicies,
+		APIKeys:         apiKeys,
+		Users:           users,
+		PreparedQueries: preparedQueries,
+		ReadReplicas:    readReplicas,
+		compression:     f.compression,
 	}, nil
 }
 
 // Restore implements raft.FSM.Restore.
 // It restores the FSM state from a snapshot.
 // This is called when a node joins the cluster or recovers from a crash.
+//
+// KV and service records are decoded chunk by chunk into the new data of the
+// stores, which is only put in use once the whole snapshot has been read, so
+// that a truncated or corrupt snapshot leaves the FSM as it was. JSON
+// snapshots written by older versions are decoded in one go.
 func (f *KonsulFSM) Restore(rc io.ReadCloser) error {
 	defer func() { _ = rc.Close() }()
 
-	var snapshot SnapshotData
-	if err := json.NewDecoder(rc).Decode(&snapshot); err != nil {
-		return fmt.Errorf("failed to decode snapshot: %w", err)
+	var (
+		snapshot      SnapshotData
+		reader        *snapshotReader
+		kvChunks      func() ([]store.KVRecord, error)
+		serviceChunks func() ([]store.ServiceRecord, error)
+	)
+	r := bufio.NewReaderSize(rc, 64<<10)
+	if isSnapshotFormat(r) {
+		var err error
+		if reader, err = newSnapshotReader(r); err != nil {
+			return err
+		}
+		defer reader.close()
+		snapshot.Index = reader.index
+		kvChunks, serviceChunks = reader.kvChunks, reader.serviceChunks
+	} else {
+		if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+			return fmt.Errorf("failed to decode snapshot: %w", err)
+		}
+		kvChunks = singleChunk(kvRecords(snapshot.KVData))
+		serviceChunks = singleChunk(serviceRecords(snapshot.ServiceData))
+	}
+
+	commitKV, err := f.kvStore.PrepareRestore(kvChunks)
+	if err != nil {
+		return fmt.Errorf("failed to restore KV store: %w", err)
+	}
+	commitServices, err := f.serviceStore.PrepareRestore(serviceChunks)
+	if err != nil {
+		return fmt.Errorf("failed to restore service store: %w", err)
+	}
+	if reader != nil {
+		if err := reader.readSections(&snapshot); err != nil {
+			return fmt.Errorf("failed to decode snapshot: %w", err)
+		}
 	}
 
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	// Restore KV store
-	if err := f.kvStore.RestoreFromSnapshot(snapshot.KVData); err != nil {
-		return fmt.Errorf("failed to restore KV store: %w", err)
+	// Restore the KV and service stores
+	commitKV()
+	commitServices()
+
+	// Restore sessions; snapshots taken before sessions existed have none
+	if f.sessionStore != nil {
+		if err := f.sessionStore.RestoreFromSnapshot(snapshot.SessionData); err != nil {
+			return fmt.Errorf("failed to restore session store: %w", err)
+		}
 	}
 
-	// Restore service store
-	if err := f.serviceStore.RestoreFromSnapshot(snapshot.ServiceData); err != nil {
-		return fmt.Errorf("failed to restore service store: %w", err)
+	// Restore namespaces; snapshots taken before namespaces existed have none
+	if f.nsStore != nil {
+		if err := f.nsStore.RestoreFromSnapshot(snapshot.NamespaceData); err != nil {
+			return fmt.Errorf("failed to restore namespace store: %w", err)
+		}
 	}
 
+	// Restore ACL policies and API keys; snapshots taken before they were
+	// replicated have none
+	if f.aclStore != nil {
+		if err := f.aclStore.RestorePolicies(snapshot.ACLPolicies); err != nil {
+			return fmt.Errorf("failed to restore ACL policies: %w", err)
+		}
+	}
+	if f.apiKeyStore != nil {
+		if err := f.apiKeyStore.RestoreAPIKeys(snapshot.APIKeys); err != nil {
+			return fmt.Errorf("failed to restore API keys: %w", err)
+		}
+	}
+
+	// Restore users; snapshots taken before users existed have none
+	if f.userStore != nil {
+		if err := f.userStore.RestoreUsers(snapshot.Users); err != nil {
+			return fmt.Errorf("failed to restore users: %w", err)
+		}
+	}
+
+	// Restore prepared queries; snapshots taken before they existed have none
+	if f.queryStore != nil {
+		if err := f.queryStore.RestoreFromSnapshot(snapshot.PreparedQueries); err != nil {
+			return fmt.Errorf("failed to restore prepared queries: %w", err)
+		}
+	}
+
+	clear(f.readReplicas)
+	for _, id := range snapshot.ReadReplicas {
+		f.readReplicas[id] = true
+	}
+
+	f.applied.restored(snapshot.Index)
 	return nil
 }
 
-// SnapshotData represents the data structure stored in a snapshot.
+// kvRecords converts the KV data of a JSON snapshot.
+func kvRecords(data map[string]store.KVEntrySnapshot) []store.KVRecord {
+	records := make([]store.KVRecord, 0, len(data))
+	for key, entry := range data {
+		records = append(records, store.KVRecord{Key: key, Entry: entry})
+	}
+	return records
+}
+
+// serviceRecords converts the service data of a JSON snapshot.
+func serviceRecords(data map[string]store.ServiceEntrySnapshot) []store.ServiceRecord {
+	records := make([]store.ServiceRecord, 0, len(data))
+	for id, entry := range data {
+		records = append(records, store.ServiceRecord{ID: id, Entry: entry})
+	}
+	return records
+}
+
+// SnapshotData represents the data structure stored in a JSON snapshot, the
+// format used before the chunked one (see snapshot.go). The sections that
+// follow the KV and service records are decoded into it too.
 type SnapshotData struct {
-	KVData      map[string]store.KVEntrySnapshot      `json:"kv_data"`
-	ServiceData map[string]store.ServiceEntrySnapshot `json:"service_data"`
+	Index           uint64                                `json:"index,omitempty"`
+	KVData          map[string]store.KVEntrySnapshot      `json:"kv_data"`
+	ServiceData     map[string]store.ServiceEntrySnapshot `json:"service_data"`
+	SessionData     map[string]store.Session              `json:"session_data,omitempty"`
+	NamespaceData   map[string]store.Namespace            `json:"namespace_data,omitempty"`
+	ACLPolicies     map[string]*acl.Policy                `json:"acl_policies,omitempty"`
+	APIKeys         map[string]auth.APIKey                `json:"api_keys,omitempty"`
+	Users           map[string]auth.User                  `json:"users,omitempty"`
+	PreparedQueries map[string]store.PreparedQuery        `json:"prepared_queries,omitempty"`
+	ReadReplicas    []string                              `json:"read_replicas,omitempty"`
 }
 
 // KonsulSnapshot implements raft.FSMSnapshot.
 // It holds a point-in-time snapshot of the FSM state.
 type KonsulSnapshot struct {
-	KVData      map[string]store.KVEntrySnapshot
-	ServiceData map[string]store.ServiceEntrySnapshot
+	Index           uint64 // index of the last entry applied
+	KVRecords       store.RecordReader[store.KVRecord]
+	ServiceRecords  store.RecordReader[store.ServiceRecord]
+	SessionData     map[string]store.Session
+	NamespaceData   map[string]store.Namespace
+	ACLPolicies     map[string]*acl.Policy
+	APIKeys         map[string]auth.APIKey
+	Users           map[string]auth.User
+	PreparedQueries map[string]store.PreparedQuery
+	ReadReplicas    []string
+
+	compression byte
 }
 
 // Persist implements raft.FSMSnapshot.Persist.
-// It writes the snapshot to the given sink.
+// It streams the snapshot to the given sink in the chunked format.
 func (s *KonsulSnapshot) Persist(sink raft.SnapshotSink) error {
-	data := SnapshotData{
-		KVData:      s.KVData,
-		ServiceData: s.ServiceData,
-	}
-
-	// Encode the snapshot as JSON
-	if err := json.NewEncoder(sink).Encode(data); err != nil {
+	if err := s.write(sink); err != nil {
 		_ = sink.Cancel()
 		return fmt.Errorf("failed to encode snapshot: %w", err)
 	}
@@ -383,5 +1050,8 @@ func (s *KonsulSnapshot) Persist(sink raft.SnapshotSink) error {
 // Release implements raft.FSMSnapshot.Release.
 // It is called when Raft is finished with the snapshot.
 func (s *KonsulSnapshot) Release() {
-	// Nothing to release - we use plain Go maps
+	// Stops the stores from keeping records for the snapshot, if Persist
+	// failed or was never called
+	s.KVRecords.Close()
+	s.ServiceRecords.Close()
 }
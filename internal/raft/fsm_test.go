@@ -1,9 +1,11 @@
 package raft
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"testing"
 	"time"
 
@@ -11,6 +13,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/neogan74/konsul/internal/acl"
+	"github.com/neogan74/konsul/internal/auth"
+	"github.com/neogan74/konsul/internal/logger"
 	"github.com/neogan74/konsul/internal/store"
 )
 
@@ -105,27 +110,116 @@ func (m *mockKVStore) BatchDeleteCASLocal(keys []string, expectedIndices map[str
 	return nil
 }
 
-func (m *mockKVStore) GetAllData() map[string]store.KVEntrySnapshot {
-	result := make(map[string]store.KVEntrySnapshot, len(m.data))
-	for k, v := range m.data {
-		result[k] = v
+func (m *mockKVStore) SetWithExpiryLocal(key, value string, flags uint64, expiresAt time.Time) {
+	m.SetLocal(key, value)
+	entry := m.data[key]
+	entry.Flags = flags
+	entry.ExpiresAt = expiresAt
+	m.data[key] = entry
+}
+
+func (m *mockKVStore) SetCASWithExpiryLocal(key, value string, expectedIndex uint64, expiresAt time.Time) (uint64, error) {
+	idx, err := m.SetCASLocal(key, value, expectedIndex)
+	if err != nil {
+		return 0, err
 	}
-	return result
+	entry := m.data[key]
+	entry.ExpiresAt = expiresAt
+	m.data[key] = entry
+	return idx, nil
 }
 
-func (m *mockKVStore) RestoreFromSnapshot(data map[string]store.KVEntrySnapshot) error {
-	m.data = make(map[string]store.KVEntrySnapshot, len(data))
-	for k, v := range data {
-		m.data[k] = v
+func (m *mockKVStore) SetSealedSecretLocal(key, sealed string, flags uint64, expiresAt time.Time) {
+	m.SetWithExpiryLocal(key, sealed, flags, expiresAt)
+	entry := m.data[key]
+	entry.Secret = true
+	m.data[key] = entry
+}
+
+func (m *mockKVStore) SetSealedSecretCASLocal(key, sealed string, expectedIndex uint64, expiresAt time.Time) (uint64, error) {
+	idx, err := m.SetCASWithExpiryLocal(key, sealed, expectedIndex, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	entry := m.data[key]
+	entry.Secret = true
+	m.data[key] = entry
+	return idx, nil
+}
+
+func (m *mockKVStore) BatchSetWithExpiryLocal(items map[string]string, expiresAt time.Time) error {
+	for k, v := range items {
+		m.SetWithExpiryLocal(k, v, 0, expiresAt)
 	}
 	return nil
 }
 
+func (m *mockKVStore) DeleteExpiredLocal(keys []string, now time.Time) []string {
+	var deleted []string
+	for _, k := range keys {
+		entry, ok := m.data[k]
+		if ok && !entry.ExpiresAt.IsZero() && !now.Before(entry.ExpiresAt) {
+			delete(m.data, k)
+			deleted = append(deleted, k)
+		}
+	}
+	return deleted
+}
+
+func (m *mockKVStore) OpenSnapshot() store.RecordReader[store.KVRecord] {
+	records := make([]store.KVRecord, 0, len(m.data))
+	for k, v := range m.data {
+		records = append(records, store.KVRecord{Key: k, Entry: v})
+	}
+	return &sliceRecords[store.KVRecord]{records: records}
+}
+
+// sliceRecords returns the records of a mock store snapshot.
+type sliceRecords[R any] struct {
+	records []R
+}
+
+func (r *sliceRecords[R]) Next(n int) ([]R, error) {
+	if len(r.records) == 0 {
+		return nil, io.EOF
+	}
+	chunk := r.records[:min(n, len(r.records))]
+	r.records = r.records[len(chunk):]
+	return chunk, nil
+}
+
+func (r *sliceRecords[R]) Close() {}
+
+func (m *mockKVStore) PrepareRestore(next func() ([]store.KVRecord, error)) (func(), error) {
+	data := make(map[string]store.KVEntrySnapshot)
+	for {
+		records, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			data[r.Key] = r.Entry
+		}
+	}
+	return func() { m.data = data }, nil
+}
+
 func (m *mockKVStore) GetEntrySnapshot(key string) (store.KVEntrySnapshot, bool) {
 	entry, ok := m.data[key]
 	return entry, ok
 }
 
+// mockInstanceID mirrors the store's instance keying: ID, falling back to Name.
+func mockInstanceID(service store.ServiceDataSnapshot) string {
+	if service.ID != "" {
+		return service.ID
+	}
+	return service.Name
+}
+
 // mockServiceStore implements ServiceStoreInterface for testing.
 type mockServiceStore struct {
 	data map[string]store.ServiceEntrySnapshot
@@ -138,7 +232,7 @@ func newMockServiceStore() *mockServiceStore {
 }
 
 func (m *mockServiceStore) RegisterLocal(service store.ServiceDataSnapshot) error {
-	m.data[service.Name] = store.ServiceEntrySnapshot{
+	m.data[mockInstanceID(service)] = store.ServiceEntrySnapshot{
 		Service:     service,
 		ExpiresAt:   time.Now().Add(30 * time.Second),
 		ModifyIndex: uint64(len(m.data) + 1),
@@ -160,24 +254,64 @@ func (m *mockServiceStore) HeartbeatLocal(name string) bool {
 	return false
 }
 
-func (m *mockServiceStore) GetAllData() map[string]store.ServiceEntrySnapshot {
-	result := make(map[string]store.ServiceEntrySnapshot, len(m.data))
+func (m *mockServiceStore) ExpiredInstances(now time.Time) map[string]uint64 {
+	expired := make(map[string]uint64)
 	for k, v := range m.data {
-		result[k] = v
+		if v.ExpiresAt.Before(now) {
+			expired[k] = v.ModifyIndex
+		}
 	}
-	return result
+	return expired
 }
 
-func (m *mockServiceStore) RestoreFromSnapshot(data map[string]store.ServiceEntrySnapshot) error {
-	m.data = make(map[string]store.ServiceEntrySnapshot, len(data))
-	for k, v := range data {
-		m.data[k] = v
+func (m *mockServiceStore) ExpireLocal(instances map[string]uint64) []string {
+	var expired []string
+	for k, index := range instances {
+		if entry, ok := m.data[k]; ok && entry.ModifyIndex == index {
+			delete(m.data, k)
+			expired = append(expired, k)
+		}
+	}
+	return expired
+}
+
+func (m *mockServiceStore) ExtendTTLLocal(grace time.Duration) {
+	deadline := time.Now().Add(30*time.Second + grace)
+	for k, v := range m.data {
+		if v.ExpiresAt.Before(deadline) {
+			v.ExpiresAt = deadline
+			m.data[k] = v
+		}
 	}
-	return nil
+}
+
+func (m *mockServiceStore) OpenSnapshot() store.RecordReader[store.ServiceRecord] {
+	records := make([]store.ServiceRecord, 0, len(m.data))
+	for k, v := range m.data {
+		records = append(records, store.ServiceRecord{ID: k, Entry: v})
+	}
+	return &sliceRecords[store.ServiceRecord]{records: records}
+}
+
+func (m *mockServiceStore) PrepareRestore(next func() ([]store.ServiceRecord, error)) (func(), error) {
+	data := make(map[string]store.ServiceEntrySnapshot)
+	for {
+		records, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			data[r.ID] = r.Entry
+		}
+	}
+	return func() { m.data = data }, nil
 }
 
 func (m *mockServiceStore) RegisterCASLocal(service store.ServiceDataSnapshot, expectedIndex uint64) (uint64, error) {
-	entry, ok := m.data[service.Name]
+	entry, ok := m.data[mockInstanceID(service)]
 	if expectedIndex == 0 {
 		if ok {
 			return 0, fmt.Errorf("service already exists")
@@ -188,7 +322,7 @@ func (m *mockServiceStore) RegisterCASLocal(service store.ServiceDataSnapshot, e
 		}
 	}
 	_ = m.RegisterLocal(service)
-	return m.data[service.Name].ModifyIndex, nil
+	return m.data[mockInstanceID(service)].ModifyIndex, nil
 }
 
 func (m *mockServiceStore) DeregisterCASLocal(name string, expectedIndex uint64) error {
@@ -272,6 +406,40 @@ func TestFSM_Apply_KVSetWithFlags(t *testing.T) {
 	assert.Equal(t, uint64(42), entry.Flags)
 }
 
+func TestFSM_Apply_KVSetSecret(t *testing.T) {
+	kvStore := newMockKVStore()
+
+	fsm := NewFSM(FSMConfig{
+		KVStore:      kvStore,
+		ServiceStore: newMockServiceStore(),
+	})
+
+	cmd, err := NewCommand(CmdKVSetWithFlags, KVSetWithFlagsPayload{
+		Key:    "db/password",
+		Value:  "konsul:v1:abcd:wrapped:sealed",
+		Secret: true,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, fsm.Apply(makeLog(t, cmd)))
+
+	entry, ok := kvStore.data["db/password"]
+	require.True(t, ok)
+	assert.True(t, entry.Secret)
+	assert.Equal(t, "konsul:v1:abcd:wrapped:sealed", entry.Value)
+
+	cmd, err = NewCommand(CmdKVSetCAS, KVSetCASPayload{
+		Key:           "db/password",
+		Value:         "konsul:v1:abcd:wrapped:resealed",
+		ExpectedIndex: entry.ModifyIndex,
+		Secret:        true,
+	})
+	require.NoError(t, err)
+	res, ok := fsm.Apply(makeLog(t, cmd)).(*CASResult)
+	require.True(t, ok)
+	require.NoError(t, res.Err)
+	assert.True(t, kvStore.data["db/password"].Secret)
+}
+
 func TestFSM_Apply_KVDelete(t *testing.T) {
 	kvStore := newMockKVStore()
 	serviceStore := newMockServiceStore()
@@ -419,6 +587,40 @@ func TestFSM_Apply_ServiceDeregister(t *testing.T) {
 	assert.False(t, ok)
 }
 
+func TestFSM_Apply_ServiceInstances(t *testing.T) {
+	kvStore := newMockKVStore()
+	serviceStore := newMockServiceStore()
+
+	fsm := NewFSM(FSMConfig{
+		KVStore:      kvStore,
+		ServiceStore: serviceStore,
+	})
+
+	// Register two instances of the same service on different nodes
+	for _, svc := range []store.Service{
+		{ID: "web-1", Name: "web", Node: "node-a", Address: "10.0.0.1", Port: 8080},
+		{ID: "web-2", Name: "web", Node: "node-b", Address: "10.0.0.2", Port: 8080},
+	} {
+		cmd, err := NewCommand(CmdServiceRegister, ServiceRegisterPayload{Service: svc})
+		require.NoError(t, err)
+		assert.Nil(t, fsm.Apply(makeLog(t, cmd)))
+	}
+
+	require.Len(t, serviceStore.data, 2)
+	assert.Equal(t, "node-a", serviceStore.data["web-1"].Service.Node)
+	assert.Equal(t, "node-b", serviceStore.data["web-2"].Service.Node)
+
+	// Deregister a single instance by ID
+	cmd, err := NewCommand(CmdServiceDeregister, ServiceDeregisterPayload{ID: "web-1"})
+	require.NoError(t, err)
+	assert.Nil(t, fsm.Apply(makeLog(t, cmd)))
+
+	_, ok := serviceStore.data["web-1"]
+	assert.False(t, ok)
+	_, ok = serviceStore.data["web-2"]
+	assert.True(t, ok)
+}
+
 func TestFSM_Apply_ServiceHeartbeat(t *testing.T) {
 	kvStore := newMockKVStore()
 	serviceStore := newMockServiceStore()
@@ -449,6 +651,27 @@ func TestFSM_Apply_ServiceHeartbeat(t *testing.T) {
 	assert.True(t, entry.ExpiresAt.After(oldExpiry))
 }
 
+func TestFSM_Apply_ServiceExpire(t *testing.T) {
+	serviceStore := newMockServiceStore()
+	serviceStore.data["web-1"] = store.ServiceEntrySnapshot{Service: store.ServiceDataSnapshot{Name: "web"}, ModifyIndex: 3}
+	serviceStore.data["web-2"] = store.ServiceEntrySnapshot{Service: store.ServiceDataSnapshot{Name: "web"}, ModifyIndex: 7}
+
+	fsm := NewFSM(FSMConfig{
+		KVStore:      newMockKVStore(),
+		ServiceStore: serviceStore,
+	})
+
+	// web-2 was registered again since the leader found it expired
+	cmd, err := NewCommand(CmdServiceExpire, ServiceExpirePayload{Instances: map[string]uint64{"web-1": 3, "web-2": 5}})
+	require.NoError(t, err)
+	assert.Nil(t, fsm.Apply(makeLog(t, cmd)))
+
+	_, ok := serviceStore.data["web-1"]
+	assert.False(t, ok)
+	_, ok = serviceStore.data["web-2"]
+	assert.True(t, ok)
+}
+
 func TestFSM_Apply_UnknownCommand(t *testing.T) {
 	kvStore := newMockKVStore()
 	serviceStore := newMockServiceStore()
@@ -497,16 +720,29 @@ func TestFSM_Snapshot(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify snapshot contains expected data
-	var data SnapshotData
-	err = json.Unmarshal(buf.Bytes(), &data)
+	reader, err := newSnapshotReader(bufio.NewReader(&buf))
 	require.NoError(t, err)
+	defer reader.close()
 
-	assert.Len(t, data.KVData, 2)
-	assert.Equal(t, "value1", data.KVData["key1"].Value)
-	assert.Equal(t, "value2", data.KVData["key2"].Value)
+	kvRecords, err := reader.kvChunks()
+	require.NoError(t, err)
+	kvData := make(map[string]store.KVEntrySnapshot)
+	for _, record := range kvRecords {
+		kvData[record.Key] = record.Entry
+	}
+	assert.Len(t, kvData, 2)
+	assert.Equal(t, "value1", kvData["key1"].Value)
+	assert.Equal(t, "value2", kvData["key2"].Value)
+	_, err = reader.kvChunks()
+	assert.Equal(t, io.EOF, err)
 
-	assert.Len(t, data.ServiceData, 1)
-	assert.Equal(t, "web", data.ServiceData["web"].Service.Name)
+	services, err := reader.serviceChunks()
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+	assert.Equal(t, "web", services[0].Entry.Service.Name)
+
+	var data SnapshotData
+	require.NoError(t, reader.readSections(&data))
 }
 
 func TestFSM_Restore(t *testing.T) {
@@ -738,3 +974,381 @@ func TestFSM_BatchCASResultReturned(t *testing.T) {
 		assert.Greater(t, idx, uint64(0), "key %s should have positive NewIndex", k)
 	}
 }
+
+func TestFSM_Apply_SessionLocks(t *testing.T) {
+	kvStore := store.NewKVStore()
+	sessionStore := store.NewSessionStore(kvStore)
+	fsm := NewFSM(FSMConfig{
+		KVStore:      kvStore,
+		ServiceStore: newMockServiceStore(),
+		SessionStore: sessionStore,
+	})
+
+	session := store.Session{Name: "leader", Behavior: store.SessionBehaviorDelete}
+	require.NoError(t, store.PrepareSession(&session))
+
+	cmd, _ := NewCommand(CmdSessionCreate, SessionCreatePayload{Session: session})
+	created, ok := fsm.Apply(makeLog(t, cmd)).(*SessionResult)
+	require.True(t, ok, "expected *SessionResult")
+	require.NoError(t, created.Err)
+	assert.Equal(t, session.ID, created.Session.ID)
+
+	cmd, _ = NewCommand(CmdKVAcquire, KVLockPayload{Key: "locks/leader", Value: "node-1", Session: session.ID})
+	lock, ok := fsm.Apply(makeLog(t, cmd)).(*LockResult)
+	require.True(t, ok, "expected *LockResult")
+	require.NoError(t, lock.Err)
+	assert.True(t, lock.Ok)
+
+	entry, ok := kvStore.GetEntry("locks/leader")
+	require.True(t, ok)
+	assert.Equal(t, session.ID, entry.Session)
+
+	cmd, _ = NewCommand(CmdKVAcquire, KVLockPayload{Key: "locks/leader", Value: "node-2", Session: "unknown"})
+	lock = fsm.Apply(makeLog(t, cmd)).(*LockResult)
+	assert.True(t, store.IsNotFound(lock.Err))
+
+	// Snapshot and restore into fresh stores keeps both the session and the lock
+	snap, err := fsm.Snapshot()
+	require.NoError(t, err)
+	sink := &mockSnapshotSink{buf: &bytes.Buffer{}}
+	require.NoError(t, snap.Persist(sink))
+
+	restoredKV := store.NewKVStore()
+	restoredSessions := store.NewSessionStore(restoredKV)
+	restored := NewFSM(FSMConfig{
+		KVStore:      restoredKV,
+		ServiceStore: newMockServiceStore(),
+		SessionStore: restoredSessions,
+	})
+	require.NoError(t, restored.Restore(&mockReadCloser{buf: sink.buf}))
+	_, ok = restoredSessions.Get(session.ID)
+	assert.True(t, ok)
+
+	// Destroying a delete-behavior session removes the keys it holds
+	cmd, _ = NewCommand(CmdSessionDestroy, SessionDestroyPayload{ID: session.ID})
+	assert.Nil(t, fsm.Apply(makeLog(t, cmd)))
+	_, ok = kvStore.GetEntry("locks/leader")
+	assert.False(t, ok)
+
+	// Without a session store, session commands fail cleanly
+	bare := NewFSM(FSMConfig{KVStore: newMockKVStore()})
+	assert.ErrorIs(t, bare.Apply(makeLog(t, cmd)).(error), ErrSessionsDisabled)
+}
+
+func TestFSM_Apply_SessionExpiry(t *testing.T) {
+	kvStore := store.NewKVStore()
+	sessionStore := store.NewSessionStore(kvStore)
+	fsm := NewFSM(FSMConfig{
+		KVStore:      kvStore,
+		ServiceStore: newMockServiceStore(),
+		SessionStore: sessionStore,
+	})
+
+	// The expiry in the entry is stored as is, however late it is applied
+	session := store.Session{TTL: "30s", ExpiresAt: time.Now().Add(time.Minute).UTC()}
+	require.NoError(t, store.PrepareSession(&session))
+	cmd, _ := NewCommand(CmdSessionCreate, SessionCreatePayload{Session: session})
+	created := fsm.Apply(makeLog(t, cmd)).(*SessionResult)
+	require.NoError(t, created.Err)
+	assert.True(t, session.ExpiresAt.Equal(created.Session.ExpiresAt))
+
+	renewedAt := time.Now().Add(2 * time.Minute).UTC()
+	cmd, _ = NewCommand(CmdSessionRenew, SessionRenewPayload{ID: session.ID, ExpiresAt: renewedAt})
+	renewed := fsm.Apply(makeLog(t, cmd)).(*SessionResult)
+	require.NoError(t, renewed.Err)
+	stored, ok := sessionStore.Get(session.ID)
+	require.True(t, ok)
+	assert.True(t, renewedAt.Equal(stored.ExpiresAt))
+
+	// Entries written by older versions carry no expiry
+	cmd, _ = NewCommand(CmdSessionRenew, SessionRenewPayload{ID: session.ID})
+	renewed = fsm.Apply(makeLog(t, cmd)).(*SessionResult)
+	require.NoError(t, renewed.Err)
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), renewed.Session.ExpiresAt, 5*time.Second)
+}
+
+func TestFSM_Apply_KVExpiry(t *testing.T) {
+	kvStore := store.NewKVStore()
+	fsm := NewFSM(FSMConfig{
+		KVStore:      kvStore,
+		ServiceStore: newMockServiceStore(),
+	})
+
+	expiresAt := time.Now().Add(time.Minute).UTC()
+	cmd, _ := NewCommand(CmdKVSet, KVSetPayload{Key: "presence/a", Value: "up", ExpiresAt: expiresAt})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+
+	cmd, _ = NewCommand(CmdKVBatchSet, KVBatchSetPayload{
+		Items:     map[string]string{"presence/b": "up", "presence/c": "up"},
+		ExpiresAt: expiresAt,
+	})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+
+	cmd, _ = NewCommand(CmdKVSetCAS, KVSetCASPayload{Key: "presence/d", Value: "up", ExpiresAt: expiresAt})
+	res := fsm.Apply(makeLog(t, cmd)).(*CASResult)
+	require.NoError(t, res.Err)
+
+	entry, ok := kvStore.GetEntry("presence/a")
+	require.True(t, ok)
+	assert.True(t, entry.ExpiresAt.Equal(expiresAt), "expiry should be taken from the log entry")
+
+	// A key rewritten without a TTL is no longer expired and survives
+	cmd, _ = NewCommand(CmdKVSet, KVSetPayload{Key: "presence/c", Value: "permanent"})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+
+	cmd, _ = NewCommand(CmdKVExpire, KVExpirePayload{
+		Keys: []string{"presence/a", "presence/b", "presence/c", "presence/d"},
+		Now:  expiresAt.Add(time.Second),
+	})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+
+	assert.Equal(t, []string{"presence/c"}, kvStore.List())
+}
+
+func TestFSM_Apply_Namespaces(t *testing.T) {
+	kvStore := store.NewKVStore()
+	nsStore := store.NewNamespaceStore(kvStore, nil)
+	fsm := NewFSM(FSMConfig{
+		KVStore:        kvStore,
+		ServiceStore:   newMockServiceStore(),
+		NamespaceStore: nsStore,
+	})
+
+	cmd, _ := NewCommand(CmdNamespaceCreate, NamespaceCreatePayload{Namespace: store.Namespace{Name: "team-a"}})
+	nsRes := fsm.Apply(makeLog(t, cmd)).(*NamespaceResult)
+	require.NoError(t, nsRes.Err)
+	assert.Equal(t, "team-a", nsRes.Namespace.Name)
+
+	// The same key in two namespaces refers to two entries
+	cmd, _ = NewCommand(CmdKVSet, KVSetPayload{Key: "config", Value: "default"})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+	cmd, _ = NewCommand(CmdKVSet, KVSetPayload{Namespace: "team-a", Key: "config", Value: "team-a"})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+
+	value, _ := kvStore.Get("config")
+	assert.Equal(t, "default", value)
+	value, _ = kvStore.Get(store.NamespacedKey("team-a", "config"))
+	assert.Equal(t, "team-a", value)
+
+	// Batch CAS indices are keyed by the keys of the payload
+	cmd, _ = NewCommand(CmdKVBatchSetCAS, KVBatchSetCASPayload{
+		Namespace:       "team-a",
+		Items:           map[string]string{"a": "1", "b": "2"},
+		ExpectedIndices: map[string]uint64{"a": 0, "b": 0},
+	})
+	casRes := fsm.Apply(makeLog(t, cmd)).(*CASResult)
+	require.NoError(t, casRes.Err)
+	assert.Contains(t, casRes.NewIndices, "a")
+	assert.Contains(t, casRes.NewIndices, "b")
+	assert.ElementsMatch(t, []string{"a", "b", "config"}, kvStore.ListNamespace("team-a"))
+
+	// A namespace holding keys cannot be deleted
+	cmd, _ = NewCommand(CmdNamespaceDelete, NamespaceDeletePayload{Name: "team-a"})
+	assert.Error(t, fsm.Apply(makeLog(t, cmd)).(error))
+
+	// Namespaces survive a snapshot round trip
+	snapshot, err := fsm.Snapshot()
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Persist(&mockSnapshotSink{buf: &buf}))
+
+	restoredNS := store.NewNamespaceStore(nil, nil)
+	restored := NewFSM(FSMConfig{
+		KVStore:        store.NewKVStore(),
+		ServiceStore:   newMockServiceStore(),
+		NamespaceStore: restoredNS,
+	})
+	require.NoError(t, restored.Restore(&mockReadCloser{buf: &buf}))
+	assert.True(t, restoredNS.Exists("team-a"))
+
+	bare := NewFSM(FSMConfig{KVStore: newMockKVStore(), ServiceStore: newMockServiceStore()})
+	cmd, _ = NewCommand(CmdNamespaceDelete, NamespaceDeletePayload{Name: "team-a"})
+	assert.ErrorIs(t, bare.Apply(makeLog(t, cmd)).(error), ErrNamespacesDisabled)
+}
+
+func TestFSM_Apply_ACLPolicies(t *testing.T) {
+	evaluator := acl.NewEvaluator(logger.GetDefault())
+	fsm := NewFSM(FSMConfig{
+		KVStore:      newMockKVStore(),
+		ServiceStore: newMockServiceStore(),
+		ACLStore:     evaluator,
+	})
+
+	policy := &acl.Policy{
+		Name: "readers",
+		KV:   []acl.KVRule{{Path: "app/*", Capabilities: []acl.Capability{acl.CapabilityRead}}},
+	}
+	cmd, _ := NewCommand(CmdACLPolicyCreate, ACLPolicyPayload{Policy: policy})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+	assert.True(t, evaluator.Evaluate([]string{"readers"}, acl.NewKVResource("app/config"), acl.CapabilityRead))
+
+	// Creating it again fails on every node alike
+	assert.ErrorIs(t, fsm.Apply(makeLog(t, cmd)).(error), acl.ErrPolicyExists)
+
+	policy.Description = "updated"
+	cmd, _ = NewCommand(CmdACLPolicyUpdate, ACLPolicyPayload{Policy: policy})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+
+	// Policies survive a snapshot round trip
+	snapshot, err := fsm.Snapshot()
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Persist(&mockSnapshotSink{buf: &buf}))
+
+	restoredEvaluator := acl.NewEvaluator(logger.GetDefault())
+	restored := NewFSM(FSMConfig{
+		KVStore:      newMockKVStore(),
+		ServiceStore: newMockServiceStore(),
+		ACLStore:     restoredEvaluator,
+	})
+	require.NoError(t, restored.Restore(&mockReadCloser{buf: &buf}))
+	restoredPolicy, err := restoredEvaluator.GetPolicy("readers")
+	require.NoError(t, err)
+	assert.Equal(t, "updated", restoredPolicy.Description)
+	assert.True(t, restoredEvaluator.Evaluate([]string{"readers"}, acl.NewKVResource("app/config"), acl.CapabilityRead))
+
+	cmd, _ = NewCommand(CmdACLPolicyDelete, ACLPolicyDeletePayload{Name: "readers"})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+	assert.Equal(t, 0, evaluator.Count())
+
+	bare := NewFSM(FSMConfig{KVStore: newMockKVStore(), ServiceStore: newMockServiceStore()})
+	assert.ErrorIs(t, bare.Apply(makeLog(t, cmd)).(error), ErrACLDisabled)
+}
+
+func TestFSM_Apply_APIKeys(t *testing.T) {
+	service := auth.NewAPIKeyService("konsul")
+	fsm := NewFSM(FSMConfig{
+		KVStore:      newMockKVStore(),
+		ServiceStore: newMockServiceStore(),
+		APIKeyStore:  service,
+	})
+
+	// The leader generates the key; only its record is replicated
+	keyString, apiKey, err := service.NewAPIKey("ci", []string{"kv:read"}, nil, nil)
+	require.NoError(t, err)
+	cmd, _ := NewCommand(CmdAPIKeyCreate, APIKeyCreatePayload{Key: *apiKey})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+	_, err = service.ValidateAPIKey(keyString)
+	require.NoError(t, err)
+
+	cmd, _ = NewCommand(CmdAPIKeyUpdate, APIKeyUpdatePayload{ID: apiKey.ID, Name: "ci-renamed"})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+
+	// Keys survive a snapshot round trip
+	snapshot, err := fsm.Snapshot()
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Persist(&mockSnapshotSink{buf: &buf}))
+
+	restoredService := auth.NewAPIKeyService("konsul")
+	restored := NewFSM(FSMConfig{
+		KVStore:      newMockKVStore(),
+		ServiceStore: newMockServiceStore(),
+		APIKeyStore:  restoredService,
+	})
+	require.NoError(t, restored.Restore(&mockReadCloser{buf: &buf}))
+	validated, err := restoredService.ValidateAPIKey(keyString)
+	require.NoError(t, err)
+	assert.Equal(t, "ci-renamed", validated.Name)
+
+	cmd, _ = NewCommand(CmdAPIKeyRevoke, APIKeyDeletePayload{ID: apiKey.ID})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+	_, err = service.ValidateAPIKey(keyString)
+	assert.Error(t, err)
+
+	cmd, _ = NewCommand(CmdAPIKeyDelete, APIKeyDeletePayload{ID: apiKey.ID})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+	assert.ErrorIs(t, fsm.Apply(makeLog(t, cmd)).(error), auth.ErrAPIKeyNotFound)
+
+	bare := NewFSM(FSMConfig{KVStore: newMockKVStore(), ServiceStore: newMockServiceStore()})
+	assert.ErrorIs(t, bare.Apply(makeLog(t, cmd)).(error), ErrAPIKeysDisabled)
+}
+
+func TestFSM_Apply_Users(t *testing.T) {
+	users := auth.NewUserService(0, 0)
+	fsm := NewFSM(FSMConfig{
+		KVStore:      newMockKVStore(),
+		ServiceStore: newMockServiceStore(),
+		UserStore:    users,
+	})
+
+	// The leader hashes the password; only the hash is replicated
+	user, err := users.NewUser("alice", "alice-password", []string{"admin"}, nil, nil)
+	require.NoError(t, err)
+	cmd, _ := NewCommand(CmdUserCreate, UserCreatePayload{User: *user})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+	assert.ErrorIs(t, fsm.Apply(makeLog(t, cmd)).(error), auth.ErrUserExists)
+
+	cmd, _ = NewCommand(CmdUserUpdate, UserUpdatePayload{
+		Username: "alice",
+		Update:   auth.UserUpdate{Policies: []string{"developer"}},
+	})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+
+	// Users survive a snapshot round trip
+	snapshot, err := fsm.Snapshot()
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Persist(&mockSnapshotSink{buf: &buf}))
+
+	restoredUsers := auth.NewUserService(0, 0)
+	restored := NewFSM(FSMConfig{
+		KVStore:      newMockKVStore(),
+		ServiceStore: newMockServiceStore(),
+		UserStore:    restoredUsers,
+	})
+	require.NoError(t, restored.Restore(&mockReadCloser{buf: &buf}))
+	authenticated, err := restoredUsers.Authenticate("alice", "alice-password")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"developer"}, authenticated.Policies)
+
+	cmd, _ = NewCommand(CmdUserDelete, UserDeletePayload{Username: "alice"})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+	assert.Equal(t, 0, users.Count())
+
+	bare := NewFSM(FSMConfig{KVStore: newMockKVStore(), ServiceStore: newMockServiceStore()})
+	assert.ErrorIs(t, bare.Apply(makeLog(t, cmd)).(error), ErrUsersDisabled)
+}
+
+func TestFSM_Apply_PreparedQueries(t *testing.T) {
+	queries := store.NewPreparedQueryStore(store.NewServiceStore())
+	fsm := NewFSM(FSMConfig{
+		KVStore:      newMockKVStore(),
+		ServiceStore: newMockServiceStore(),
+		QueryStore:   queries,
+	})
+
+	cmd, _ := NewCommand(CmdPreparedQuerySet, PreparedQuerySetPayload{
+		Query: store.PreparedQuery{Name: "web-prod", Service: "web", Tags: []string{"prod"}},
+	})
+	res := fsm.Apply(makeLog(t, cmd)).(*PreparedQueryResult)
+	require.NoError(t, res.Err)
+	assert.Equal(t, "web-prod", res.Query.Name)
+
+	cmd, _ = NewCommand(CmdPreparedQuerySet, PreparedQuerySetPayload{Query: store.PreparedQuery{Name: "no-service"}})
+	assert.Error(t, fsm.Apply(makeLog(t, cmd)).(*PreparedQueryResult).Err)
+
+	// Prepared queries survive a snapshot round trip
+	snapshot, err := fsm.Snapshot()
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Persist(&mockSnapshotSink{buf: &buf}))
+
+	restoredQueries := store.NewPreparedQueryStore(store.NewServiceStore())
+	restored := NewFSM(FSMConfig{
+		KVStore:      newMockKVStore(),
+		ServiceStore: newMockServiceStore(),
+		QueryStore:   restoredQueries,
+	})
+	require.NoError(t, restored.Restore(&mockReadCloser{buf: &buf}))
+	q, ok := restoredQueries.Get("web-prod")
+	require.True(t, ok)
+	assert.Equal(t, []string{"prod"}, q.Tags)
+
+	cmd, _ = NewCommand(CmdPreparedQueryDelete, PreparedQueryDeletePayload{Name: "web-prod"})
+	require.Nil(t, fsm.Apply(makeLog(t, cmd)))
+	assert.Empty(t, queries.List())
+
+	bare := NewFSM(FSMConfig{KVStore: newMockKVStore(), ServiceStore: newMockServiceStore()})
+	assert.ErrorIs(t, bare.Apply(makeLog(t, cmd)).(error), ErrPreparedQueriesDisabled)
+}
@@ -31,7 +31,7 @@ func (o clusterOptions) withDefaults() clusterOptions {
 	return o
 }
 
-func getFreeAddr(t *testing.T) string {
+func getFreeAddr(t testing.TB) string {
 	t.Helper()
 
 	ln, err := net.Listen("tcp", "127.0.0.1:0")
@@ -41,7 +41,7 @@ func getFreeAddr(t *testing.T) string {
 	return ln.Addr().String()
 }
 
-func newClusterConfig(t *testing.T, nodeID, addr string, bootstrap bool, opts clusterOptions) *Config {
+func newClusterConfig(t testing.TB, nodeID, addr string, bootstrap bool, opts clusterOptions) *Config {
 	t.Helper()
 
 	opts = opts.withDefaults()
@@ -65,7 +65,7 @@ func newClusterConfig(t *testing.T, nodeID, addr string, bootstrap bool, opts cl
 	return cfg
 }
 
-func newClusterConfigWithOverrides(t *testing.T, nodeID, addr string, bootstrap bool, opts clusterOptions, override func(*Config)) *Config {
+func newClusterConfigWithOverrides(t testing.TB, nodeID, addr string, bootstrap bool, opts clusterOptions, override func(*Config)) *Config {
 	t.Helper()
 
 	cfg := newClusterConfig(t, nodeID, addr, bootstrap, opts)
@@ -38,6 +38,12 @@ type Metrics struct {
 	// Replication metrics
 	replicationLag *prometheus.GaugeVec
 	fsmPending     *prometheus.GaugeVec
+
+	// Autopilot metrics
+	autopilotHealthy          *prometheus.GaugeVec
+	autopilotFailureTolerance *prometheus.GaugeVec
+	autopilotActions          *prometheus.CounterVec
+	forwardedWrites           *prometheus.CounterVec
 }
 
 // NewMetrics creates and registers Raft metrics.
@@ -212,6 +218,45 @@ func NewMetrics(namespace string) *Metrics {
 				},
 				[]string{"node_id"},
 			),
+
+			autopilotHealthy: promauto.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Namespace: namespace,
+					Subsystem: "raft",
+					Name:      "autopilot_healthy",
+					Help:      "Whether every server is healthy (1) or not (0), reported by the leader",
+				},
+				[]string{"node_id"},
+			),
+
+			autopilotFailureTolerance: promauto.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Namespace: namespace,
+					Subsystem: "raft",
+					Name:      "autopilot_failure_tolerance",
+					Help:      "Number of voters that can fail without losing quorum, reported by the leader",
+				},
+				[]string{"node_id"},
+			),
+
+			autopilotActions: promauto.NewCounterVec(
+				prometheus.CounterOpts{
+					Namespace: namespace,
+					Subsystem: "raft",
+					Name:      "autopilot_actions_total",
+					Help:      "Total number of servers promoted or removed by the autopilot",
+				},
+				[]string{"node_id", "action"},
+			),
+			forwardedWrites: promauto.NewCounterVec(
+				prometheus.CounterOpts{
+					Namespace: namespace,
+					Subsystem: "raft",
+					Name:      "forwarded_writes_total",
+					Help:      "Total number of writes forwarded to the leader by result",
+				},
+				[]string{"node_id", "result"},
+			),
 		}
 	})
 
@@ -285,3 +330,24 @@ func (m *Metrics) ObserveCommitLatency(nodeID string, seconds float64) {
 func (m *Metrics) ObserveSnapshotLatency(nodeID string, seconds float64) {
 	m.snapshotLatency.WithLabelValues(nodeID).Observe(seconds)
 }
+
+// SetAutopilotHealth updates the autopilot health metrics.
+func (m *Metrics) SetAutopilotHealth(nodeID string, healthy bool, failureTolerance int) {
+	if healthy {
+		m.autopilotHealthy.WithLabelValues(nodeID).Set(1)
+	} else {
+		m.autopilotHealthy.WithLabelValues(nodeID).Set(0)
+	}
+	m.autopilotFailureTolerance.WithLabelValues(nodeID).Set(float64(failureTolerance))
+}
+
+// IncAutopilotAction increments the autopilot action counter.
+func (m *Metrics) IncAutopilotAction(nodeID, action string) {
+	m.autopilotActions.WithLabelValues(nodeID, action).Inc()
+}
+
+// IncForwardedWrite increments the forwarded write counter for result:
+// success, error or retry.
+func (m *Metrics) IncForwardedWrite(nodeID, result string) {
+	m.forwardedWrites.WithLabelValues(nodeID, result).Inc()
+}
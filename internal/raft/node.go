@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/rpc"
 	"os"
 	"path/filepath"
 	"sync"
@@ -13,6 +14,8 @@ import (
 	"github.com/hashicorp/raft"
 	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
 
+	"github.com/neogan74/konsul/internal/acl"
+	"github.com/neogan74/konsul/internal/auth"
 	"github.com/neogan74/konsul/internal/store"
 )
 
@@ -26,8 +29,10 @@ type Node struct {
 	stable    *raftboltdb.BoltStore
 	snapshots *raft.FileSnapshotStore
 
-	logger  hclog.Logger
-	metrics *Metrics
+	logger    hclog.Logger
+	metrics   *Metrics
+	autopilot *autopilot
+	cluster   *clusterClient
 
 	shutdownCh chan struct{}
 	mu         sync.RWMutex
@@ -35,6 +40,15 @@ type Node struct {
 
 // NewNode creates a new Raft node with the given configuration.
 func NewNode(cfg *Config, kvStore KVStoreInterface, serviceStore ServiceStoreInterface) (*Node, error) {
+	return NewNodeWithFSM(cfg, FSMConfig{
+		KVStore:      kvStore,
+		ServiceStore: serviceStore,
+	})
+}
+
+// NewNodeWithFSM creates a new Raft node whose FSM applies entries to the
+// stores in fsmCfg. Use it to wire optional stores such as sessions.
+func NewNodeWithFSM(cfg *Config, fsmCfg FSMConfig) (*Node, error) {
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
@@ -57,10 +71,8 @@ func NewNode(cfg *Config, kvStore KVStoreInterface, serviceStore ServiceStoreInt
 	})
 
 	// Create FSM
-	fsm := NewFSM(FSMConfig{
-		KVStore:      kvStore,
-		ServiceStore: serviceStore,
-	})
+	fsmCfg.SnapshotCompression = cfg.SnapshotCompression
+	fsm := NewFSM(fsmCfg)
 
 	// Create Raft configuration
 	raftConfig := raft.DefaultConfig()
@@ -75,12 +87,21 @@ func NewNode(cfg *Config, kvStore KVStoreInterface, serviceStore ServiceStoreInt
 	raftConfig.MaxAppendEntries = cfg.MaxAppendEntries
 	raftConfig.Logger = logger
 
-	// Create Transport (TCP or TLS)
+	// Create Transport (TCP or TLS), sharing its listener with the cluster
+	// RPCs the node serves once it is created
 	advertiseAddr := cfg.GetAdvertiseAddr()
-	transport, err := NewTransport(cfg, logger)
+	stream, err := newStreamLayer(cfg, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transport: %w", err)
 	}
+	endpoint := &clusterEndpoint{}
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("Cluster", endpoint); err != nil {
+		_ = stream.Close()
+		return nil, fmt.Errorf("failed to register cluster RPCs: %w", err)
+	}
+	mux := newMuxStreamLayer(stream, rpcServer, logger)
+	transport := newNetworkTransport(mux, logger)
 
 	// Create BoltDB store for logs
 	logStorePath := filepath.Join(cfg.DataDir, "raft-log.db")
@@ -120,8 +141,9 @@ func NewNode(cfg *Config, kvStore KVStoreInterface, serviceStore ServiceStoreInt
 		return nil, fmt.Errorf("failed to create snapshot store: %w", err)
 	}
 
-	// Create Raft instance
-	r, err := raft.NewRaft(raftConfig, fsm, logStore, stable, snapshots, transport)
+	// Create Raft instance, observing the answers of peers for the autopilot
+	observed := newObservedTransport(transport)
+	r, err := raft.NewRaft(raftConfig, fsm, logStore, stable, snapshots, observed)
 	if err != nil {
 		if closeErr := stable.Close(); closeErr != nil {
 			logger.Warn("failed to close stable store", "error", closeErr)
@@ -148,12 +170,21 @@ func NewNode(cfg *Config, kvStore KVStoreInterface, serviceStore ServiceStoreInt
 		snapshots:  snapshots,
 		logger:     logger,
 		metrics:    metrics,
+		autopilot:  newAutopilot(cfg.Autopilot, observed, fsm.IsReadReplica),
+		cluster:    newClusterClient(stream),
 		shutdownCh: make(chan struct{}),
 	}
+	endpoint.node.Store(node)
 
 	// Start metrics monitoring goroutine
 	go node.monitorState()
 
+	// Start the autopilot, which only acts while this node is the leader
+	go node.runAutopilot()
+
+	// Expire the service instances that stop heartbeating the leader
+	go node.runServiceExpiry()
+
 	// Bootstrap if this is the first node
 	if cfg.Bootstrap {
 		configuration := raft.Configuration{
@@ -181,6 +212,7 @@ func NewNode(cfg *Config, kvStore KVStoreInterface, serviceStore ServiceStoreInt
 		"bind_addr", cfg.BindAddr,
 		"advertise_addr", advertiseAddr,
 		"bootstrap", cfg.Bootstrap,
+		"non_voter", cfg.NonVoter,
 	)
 
 	return node, nil
@@ -218,8 +250,13 @@ func (n *Node) State() raft.RaftState {
 
 // EnsureLinearizableRead blocks until this leader has applied all prior writes.
 // Call this before serving linearizable reads from the local state machine.
+// On a read replica, the leader confirms the reads and the replica waits to
+// have applied the writes the leader had.
 func (n *Node) EnsureLinearizableRead(timeout time.Duration) error {
 	if n.raft.State() != raft.Leader {
+		if n.config.NonVoter {
+			return n.replicaReadIndex(timeout)
+		}
 		return ErrNotLeader
 	}
 
@@ -297,6 +334,7 @@ func (n *Node) Shutdown() error {
 	}
 
 	// Close transport
+	n.cluster.Close()
 	if err := n.transport.Close(); err != nil {
 		n.logger.Error("failed to close transport", "error", err)
 	}
@@ -326,15 +364,25 @@ func (n *Node) WaitForLeader(timeout time.Duration) error {
 	}
 }
 
+// Barrier blocks until every entry committed before it has been applied to
+// the FSM. Must be called on the leader.
+func (n *Node) Barrier(timeout time.Duration) error {
+	if n.raft.State() != raft.Leader {
+		return ErrNotLeader
+	}
+	return n.raft.Barrier(timeout).Error()
+}
+
 // =============================================================================
 // Apply Methods - These send commands through Raft
 // =============================================================================
 
 // ApplyEntry applies a Command through Raft consensus.
 // This method bridges the handler calls with the Raft apply logic.
-// Returns the response from FSM and any error.
+// Returns the response from FSM and any error. Nodes other than the leader
+// forward the command to the leader, see ForwardingConfig.
 func (n *Node) ApplyEntry(cmd *Command, timeout time.Duration) (interface{}, error) {
-	if n.raft.State() != raft.Leader {
+	if n.raft.State() != raft.Leader && !n.forwardsWrites() {
 		return nil, ErrNotLeader
 	}
 
@@ -343,6 +391,14 @@ func (n *Node) ApplyEntry(cmd *Command, timeout time.Duration) (interface{}, err
 		return nil, fmt.Errorf("failed to marshal command: %w", err)
 	}
 
+	if n.raft.State() != raft.Leader {
+		return n.forwardEntry(data, timeout)
+	}
+	return n.applyLocal(data, timeout)
+}
+
+// applyLocal applies data through Raft on the leader.
+func (n *Node) applyLocal(data []byte, timeout time.Duration) (interface{}, error) {
 	future := n.raft.Apply(data, timeout)
 	if err := future.Error(); err != nil {
 		if err == raft.ErrLeadershipLost {
@@ -361,8 +417,12 @@ func (n *Node) applyCommand(cmd *Command, timeout time.Duration) error {
 }
 
 // KVSet sets a key-value pair through Raft consensus.
+//
+// The KV and service helpers take storage keys (see store.NamespacedKey) and
+// split them into the namespace and key of the payload.
 func (n *Node) KVSet(key, value string) error {
-	cmd, err := NewCommand(CmdKVSet, KVSetPayload{Key: key, Value: value})
+	ns, key := payloadKey(key)
+	cmd, err := NewCommand(CmdKVSet, KVSetPayload{Namespace: ns, Key: key, Value: value})
 	if err != nil {
 		return err
 	}
@@ -371,16 +431,64 @@ func (n *Node) KVSet(key, value string) error {
 
 // KVSetWithFlags sets a key-value pair with flags through Raft consensus.
 func (n *Node) KVSetWithFlags(key, value string, flags uint64) error {
-	cmd, err := NewCommand(CmdKVSetWithFlags, KVSetWithFlagsPayload{Key: key, Value: value, Flags: flags})
+	ns, key := payloadKey(key)
+	cmd, err := NewCommand(CmdKVSetWithFlags, KVSetWithFlagsPayload{Namespace: ns, Key: key, Value: value, Flags: flags})
+	if err != nil {
+		return err
+	}
+	return n.applyCommand(cmd, 5*time.Second)
+}
+
+// KVSetWithTTL sets a key that expires after ttl through Raft consensus.
+// Flags of 0 keep the existing flags. The expiry is fixed here, on the
+// leader, so that all nodes expire the key at the same time.
+func (n *Node) KVSetWithTTL(key, value string, flags uint64, ttl time.Duration) error {
+	ns, key := payloadKey(key)
+	cmd, err := NewCommand(CmdKVSetWithFlags, KVSetWithFlagsPayload{
+		Namespace: ns,
+		Key:       key,
+		Value:     value,
+		Flags:     flags,
+		ExpiresAt: store.KVExpiry(ttl),
+	})
 	if err != nil {
 		return err
 	}
 	return n.applyCommand(cmd, 5*time.Second)
 }
 
+// KVSetSecret stores a secret value sealed with store.KVStore.SealSecret
+// through Raft consensus, so that only the sealed value is replicated. A ttl
+// of 0 means the key never expires; flags of 0 keep the existing flags.
+func (n *Node) KVSetSecret(key, sealed string, flags uint64, ttl time.Duration) error {
+	ns, key := payloadKey(key)
+	cmd, err := NewCommand(CmdKVSetWithFlags, KVSetWithFlagsPayload{
+		Namespace: ns,
+		Key:       key,
+		Value:     sealed,
+		Flags:     flags,
+		ExpiresAt: store.KVExpiry(ttl),
+		Secret:    true,
+	})
+	if err != nil {
+		return err
+	}
+	return n.applyCommand(cmd, 5*time.Second)
+}
+
+// KVExpire deletes keys found expired at now through Raft consensus.
+func (n *Node) KVExpire(keys []string, now time.Time) error {
+	cmd, err := NewCommand(CmdKVExpire, KVExpirePayload{Keys: keys, Now: now})
+	if err != nil {
+		return err
+	}
+	return n.applyCommand(cmd, 10*time.Second)
+}
+
 // KVDelete deletes a key through Raft consensus.
 func (n *Node) KVDelete(key string) error {
-	cmd, err := NewCommand(CmdKVDelete, KVDeletePayload{Key: key})
+	ns, key := payloadKey(key)
+	cmd, err := NewCommand(CmdKVDelete, KVDeletePayload{Namespace: ns, Key: key})
 	if err != nil {
 		return err
 	}
@@ -388,6 +496,7 @@ func (n *Node) KVDelete(key string) error {
 }
 
 // KVBatchSet sets multiple key-value pairs through Raft consensus.
+// Batch keys are passed through as storage keys.
 func (n *Node) KVBatchSet(items map[string]string) error {
 	cmd, err := NewCommand(CmdKVBatchSet, KVBatchSetPayload{Items: items})
 	if err != nil {
@@ -409,10 +518,61 @@ func (n *Node) KVBatchDelete(keys []string) error {
 // expectedIndex=0 means "create only if not exists".
 // Returns the new ModifyIndex on success.
 func (n *Node) KVSetCAS(key, value string, expectedIndex uint64) (uint64, error) {
+	ns, key := payloadKey(key)
+	cmd, err := NewCommand(CmdKVSetCAS, KVSetCASPayload{
+		Namespace:     ns,
+		Key:           key,
+		Value:         value,
+		ExpectedIndex: expectedIndex,
+	})
+	if err != nil {
+		return 0, err
+	}
+	resp, err := n.ApplyEntry(cmd, 5*time.Second)
+	if err != nil {
+		return 0, err
+	}
+	res, ok := resp.(*CASResult)
+	if !ok {
+		return 0, fmt.Errorf("unexpected FSM response type: %T", resp)
+	}
+	return res.NewIndex, res.Err
+}
+
+// KVSetCASWithTTL is KVSetCAS for a key that expires after ttl.
+func (n *Node) KVSetCASWithTTL(key, value string, expectedIndex uint64, ttl time.Duration) (uint64, error) {
+	ns, key := payloadKey(key)
 	cmd, err := NewCommand(CmdKVSetCAS, KVSetCASPayload{
+		Namespace:     ns,
 		Key:           key,
 		Value:         value,
 		ExpectedIndex: expectedIndex,
+		ExpiresAt:     store.KVExpiry(ttl),
+	})
+	if err != nil {
+		return 0, err
+	}
+	resp, err := n.ApplyEntry(cmd, 5*time.Second)
+	if err != nil {
+		return 0, err
+	}
+	res, ok := resp.(*CASResult)
+	if !ok {
+		return 0, fmt.Errorf("unexpected FSM response type: %T", resp)
+	}
+	return res.NewIndex, res.Err
+}
+
+// KVSetSecretCAS is KVSetCAS for a sealed secret value, see KVSetSecret.
+func (n *Node) KVSetSecretCAS(key, sealed string, expectedIndex uint64, ttl time.Duration) (uint64, error) {
+	ns, key := payloadKey(key)
+	cmd, err := NewCommand(CmdKVSetCAS, KVSetCASPayload{
+		Namespace:     ns,
+		Key:           key,
+		Value:         sealed,
+		ExpectedIndex: expectedIndex,
+		ExpiresAt:     store.KVExpiry(ttl),
+		Secret:        true,
 	})
 	if err != nil {
 		return 0, err
@@ -430,7 +590,9 @@ func (n *Node) KVSetCAS(key, value string, expectedIndex uint64) (uint64, error)
 
 // KVDeleteCAS atomically deletes a key only if its ModifyIndex matches expectedIndex.
 func (n *Node) KVDeleteCAS(key string, expectedIndex uint64) error {
+	ns, key := payloadKey(key)
 	cmd, err := NewCommand(CmdKVDeleteCAS, KVDeleteCASPayload{
+		Namespace:     ns,
 		Key:           key,
 		ExpectedIndex: expectedIndex,
 	})
@@ -489,16 +651,10 @@ func (n *Node) KVBatchDeleteCAS(keys []string, expectedIndices map[string]uint64
 	return res.Err
 }
 
-// ServiceRegister registers a service through Raft consensus.
-func (n *Node) ServiceRegister(name, address string, port int, tags []string, meta map[string]string) error {
+// ServiceRegister registers a service instance through Raft consensus.
+func (n *Node) ServiceRegister(svc store.Service) error {
 	cmd, err := NewCommand(CmdServiceRegister, ServiceRegisterPayload{
-		Service: store.Service{
-			Name:    name,
-			Address: address,
-			Port:    port,
-			Tags:    tags,
-			Meta:    meta,
-		},
+		Service: svc,
 	})
 	if err != nil {
 		return err
@@ -506,22 +662,245 @@ func (n *Node) ServiceRegister(name, address string, port int, tags []string, me
 	return n.applyCommand(cmd, 5*time.Second)
 }
 
-// ServiceDeregister deregisters a service through Raft consensus.
-func (n *Node) ServiceDeregister(name string) error {
-	cmd, err := NewCommand(CmdServiceDeregister, ServiceDeregisterPayload{Name: name})
+// ServiceDeregister deregisters a service instance through Raft consensus.
+func (n *Node) ServiceDeregister(id string) error {
+	ns, id := payloadKey(id)
+	cmd, err := NewCommand(CmdServiceDeregister, ServiceDeregisterPayload{Namespace: ns, ID: id})
 	if err != nil {
 		return err
 	}
 	return n.applyCommand(cmd, 5*time.Second)
 }
 
-// ServiceHeartbeat updates service TTL through Raft consensus.
-func (n *Node) ServiceHeartbeat(name string) error {
-	cmd, err := NewCommand(CmdServiceHeartbeat, ServiceHeartbeatPayload{Name: name})
+// SessionCreate replicates a prepared session and returns it as stored. Its
+// expiry is computed here, so that every node expires it at the same moment.
+func (n *Node) SessionCreate(session store.Session) (store.Session, error) {
+	session.ExpiresAt = store.SessionExpiry(session)
+	cmd, err := NewCommand(CmdSessionCreate, SessionCreatePayload{Session: session})
+	if err != nil {
+		return store.Session{}, err
+	}
+	resp, err := n.ApplyEntry(cmd, 5*time.Second)
+	if err != nil {
+		return store.Session{}, err
+	}
+	res, ok := resp.(*SessionResult)
+	if !ok {
+		return store.Session{}, fmt.Errorf("unexpected FSM response type: %T", resp)
+	}
+	return res.Session, res.Err
+}
+
+// SessionRenew extends a session TTL through Raft. The new expiry is
+// computed here from the TTL of the session, so that every node expires it
+// at the same moment.
+func (n *Node) SessionRenew(id string) (store.Session, error) {
+	if n.fsm.sessionStore == nil {
+		return store.Session{}, ErrSessionsDisabled
+	}
+	session, ok := n.fsm.sessionStore.Get(id)
+	if !ok {
+		return store.Session{}, &store.NotFoundError{Type: "session", Key: id}
+	}
+	cmd, err := NewCommand(CmdSessionRenew, SessionRenewPayload{ID: id, ExpiresAt: store.SessionExpiry(session)})
+	if err != nil {
+		return store.Session{}, err
+	}
+	resp, err := n.ApplyEntry(cmd, 5*time.Second)
+	if err != nil {
+		return store.Session{}, err
+	}
+	res, ok := resp.(*SessionResult)
+	if !ok {
+		return store.Session{}, fmt.Errorf("unexpected FSM response type: %T", resp)
+	}
+	return res.Session, res.Err
+}
+
+// SessionDestroy destroys a session and releases its locks through Raft.
+func (n *Node) SessionDestroy(id string) error {
+	cmd, err := NewCommand(CmdSessionDestroy, SessionDestroyPayload{ID: id})
 	if err != nil {
 		return err
 	}
-	return n.applyCommand(cmd, 5*time.Second)
+	resp, err := n.ApplyEntry(cmd, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+	return nil
+}
+
+// KVAcquire takes a lock on key for a session through Raft.
+// Returns false if another session holds the lock.
+func (n *Node) KVAcquire(key, value, session string) (bool, error) {
+	return n.applyLock(CmdKVAcquire, KVLockPayload{Key: key, Value: value, Session: session})
+}
+
+// KVRelease drops a session's lock on key through Raft.
+func (n *Node) KVRelease(key, session string) (bool, error) {
+	return n.applyLock(CmdKVRelease, KVLockPayload{Key: key, Session: session})
+}
+
+func (n *Node) applyLock(cmdType CommandType, payload KVLockPayload) (bool, error) {
+	payload.Namespace, payload.Key = payloadKey(payload.Key)
+	cmd, err := NewCommand(cmdType, payload)
+	if err != nil {
+		return false, err
+	}
+	resp, err := n.ApplyEntry(cmd, 5*time.Second)
+	if err != nil {
+		return false, err
+	}
+	res, ok := resp.(*LockResult)
+	if !ok {
+		return false, fmt.Errorf("unexpected FSM response type: %T", resp)
+	}
+	return res.Ok, res.Err
+}
+
+// NamespaceCreate replicates a new namespace and returns it as stored.
+func (n *Node) NamespaceCreate(ns store.Namespace) (store.Namespace, error) {
+	// Fix the creation time here so that every node stores the same one
+	if ns.CreatedAt.IsZero() {
+		ns.CreatedAt = time.Now()
+	}
+	cmd, err := NewCommand(CmdNamespaceCreate, NamespaceCreatePayload{Namespace: ns})
+	if err != nil {
+		return store.Namespace{}, err
+	}
+	resp, err := n.ApplyEntry(cmd, 5*time.Second)
+	if err != nil {
+		return store.Namespace{}, err
+	}
+	res, ok := resp.(*NamespaceResult)
+	if !ok {
+		return store.Namespace{}, fmt.Errorf("unexpected FSM response type: %T", resp)
+	}
+	return res.Namespace, res.Err
+}
+
+// NamespaceDelete deletes an empty namespace through Raft.
+func (n *Node) NamespaceDelete(name string) error {
+	cmd, err := NewCommand(CmdNamespaceDelete, NamespaceDeletePayload{Name: name})
+	if err != nil {
+		return err
+	}
+	resp, err := n.ApplyEntry(cmd, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+	return nil
+}
+
+// ACLPolicyCreate creates an ACL policy through Raft.
+func (n *Node) ACLPolicyCreate(policy *acl.Policy) error {
+	return n.applyWithResponse(CmdACLPolicyCreate, ACLPolicyPayload{Policy: policy})
+}
+
+// ACLPolicyUpdate replaces an ACL policy through Raft.
+func (n *Node) ACLPolicyUpdate(policy *acl.Policy) error {
+	return n.applyWithResponse(CmdACLPolicyUpdate, ACLPolicyPayload{Policy: policy})
+}
+
+// ACLPolicyDelete deletes an ACL policy through Raft.
+func (n *Node) ACLPolicyDelete(name string) error {
+	return n.applyWithResponse(CmdACLPolicyDelete, ACLPolicyDeletePayload{Name: name})
+}
+
+// APIKeyCreate stores an API key generated by auth.APIKeyService.NewAPIKey
+// through Raft.
+func (n *Node) APIKeyCreate(apiKey *auth.APIKey) error {
+	return n.applyWithResponse(CmdAPIKeyCreate, APIKeyCreatePayload{Key: *apiKey})
+}
+
+// APIKeyUpdate updates an API key through Raft.
+func (n *Node) APIKeyUpdate(keyID, name string, permissions []string, metadata map[string]string, enabled *bool) error {
+	return n.applyWithResponse(CmdAPIKeyUpdate, APIKeyUpdatePayload{
+		ID:          keyID,
+		Name:        name,
+		Permissions: permissions,
+		Metadata:    metadata,
+		Enabled:     enabled,
+	})
+}
+
+// APIKeyRevoke disables an API key through Raft.
+func (n *Node) APIKeyRevoke(keyID string) error {
+	return n.applyWithResponse(CmdAPIKeyRevoke, APIKeyDeletePayload{ID: keyID})
+}
+
+// APIKeyDelete deletes an API key through Raft.
+func (n *Node) APIKeyDelete(keyID string) error {
+	return n.applyWithResponse(CmdAPIKeyDelete, APIKeyDeletePayload{ID: keyID})
+}
+
+// UserCreate stores a user built by auth.UserService.NewUser through Raft.
+func (n *Node) UserCreate(user *auth.User) error {
+	return n.applyWithResponse(CmdUserCreate, UserCreatePayload{User: *user})
+}
+
+// UserUpdate updates a user through Raft.
+func (n *Node) UserUpdate(username string, update auth.UserUpdate) error {
+	// Fix the update time here so that every node stores the same one
+	if update.UpdatedAt.IsZero() {
+		update.UpdatedAt = time.Now()
+	}
+	return n.applyWithResponse(CmdUserUpdate, UserUpdatePayload{Username: username, Update: update})
+}
+
+// UserDelete deletes a user through Raft.
+func (n *Node) UserDelete(username string) error {
+	return n.applyWithResponse(CmdUserDelete, UserDeletePayload{Username: username})
+}
+
+// PreparedQuerySet creates or replaces a prepared query through Raft and
+// returns it as stored.
+func (n *Node) PreparedQuerySet(q store.PreparedQuery) (store.PreparedQuery, error) {
+	// Fix the creation time here so that every node stores the same one
+	if q.CreatedAt.IsZero() {
+		q.CreatedAt = time.Now()
+	}
+	cmd, err := NewCommand(CmdPreparedQuerySet, PreparedQuerySetPayload{Query: q})
+	if err != nil {
+		return store.PreparedQuery{}, err
+	}
+	resp, err := n.ApplyEntry(cmd, 5*time.Second)
+	if err != nil {
+		return store.PreparedQuery{}, err
+	}
+	res, ok := resp.(*PreparedQueryResult)
+	if !ok {
+		return store.PreparedQuery{}, fmt.Errorf("unexpected FSM response type: %T", resp)
+	}
+	return res.Query, res.Err
+}
+
+// PreparedQueryDelete deletes a prepared query through Raft.
+func (n *Node) PreparedQueryDelete(name string) error {
+	return n.applyWithResponse(CmdPreparedQueryDelete, PreparedQueryDeletePayload{Name: name})
+}
+
+// applyWithResponse applies a command whose FSM response is an error and
+// returns that error, unlike applyCommand which only reports Raft failures.
+func (n *Node) applyWithResponse(cmdType CommandType, payload interface{}) error {
+	cmd, err := NewCommand(cmdType, payload)
+	if err != nil {
+		return err
+	}
+	resp, err := n.ApplyEntry(cmd, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+	return nil
 }
 
 // =============================================================================
@@ -557,6 +936,41 @@ func (n *Node) Join(nodeID, addr string) error {
 		}
 	}
 
+	// Read replicas stay non-voters. Their role is replicated so that every
+	// server reports it and the autopilot never promotes them
+	readReplica, err := n.serverRole(raft.ServerAddress(addr))
+	if err != nil {
+		return fmt.Errorf("failed to get role of node %s: %w", nodeID, err)
+	}
+	if readReplica != n.fsm.IsReadReplica(nodeID) {
+		cmd, err := NewCommand(CmdServerRoleSet, ServerRolePayload{NodeID: nodeID, ReadReplica: readReplica})
+		if err != nil {
+			return err
+		}
+		if err := n.applyCommand(cmd, 5*time.Second); err != nil {
+			return fmt.Errorf("failed to record role of node %s: %w", nodeID, err)
+		}
+	}
+	if readReplica {
+		future := n.raft.AddNonvoter(raft.ServerID(nodeID), raft.ServerAddress(addr), 0, 0)
+		if err := future.Error(); err != nil {
+			return fmt.Errorf("failed to add non-voter: %w", err)
+		}
+		n.logger.Info("node joined cluster as read replica", "node_id", nodeID, "addr", addr)
+		return nil
+	}
+
+	// With the autopilot, the new node joins as a non-voter and is promoted
+	// once it has caught up and stayed healthy
+	if n.config.Autopilot.Enabled {
+		future := n.raft.AddNonvoter(raft.ServerID(nodeID), raft.ServerAddress(addr), 0, 0)
+		if err := future.Error(); err != nil {
+			return fmt.Errorf("failed to add non-voter: %w", err)
+		}
+		n.logger.Info("node joined cluster as non-voter", "node_id", nodeID, "addr", addr)
+		return nil
+	}
+
 	// Add the new node as a voter
 	future := n.raft.AddVoter(raft.ServerID(nodeID), raft.ServerAddress(addr), 0, 0)
 	if err := future.Error(); err != nil {
@@ -612,7 +1026,7 @@ type ClusterInfo struct {
 type PeerInfo struct {
 	ID      string `json:"id"`
 	Address string `json:"address"`
-	State   string `json:"state"` // Voter, Nonvoter, Staging
+	State   string `json:"state"` // Voter, Nonvoter, Staging, ReadReplica
 }
 
 // RaftStats contains Raft statistics.
@@ -646,6 +1060,9 @@ func (n *Node) GetClusterInfo() (*ClusterInfo, error) {
 		switch srv.Suffrage {
 		case raft.Nonvoter:
 			state = "Nonvoter"
+			if n.fsm.IsReadReplica(string(srv.ID)) {
+				state = "ReadReplica"
+			}
 		case raft.Staging:
 			state = "Staging"
 		}
@@ -2,6 +2,7 @@ package raft
 
 import (
 	"fmt"
+	"io"
 	"sync"
 	"testing"
 	"time"
@@ -196,29 +197,106 @@ func (m *MockKVStore) BatchDeleteCASLocal(keys []string, expectedIndices map[str
 	return nil
 }
 
-func (m *MockKVStore) GetAllData() map[string]store.KVEntrySnapshot {
+func (m *MockKVStore) SetWithExpiryLocal(key, value string, flags uint64, expiresAt time.Time) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	result := make(map[string]store.KVEntrySnapshot, len(m.data))
-	for k, v := range m.data {
-		result[k] = v
+	m.setLocked(key, value)
+	entry := m.data[key]
+	entry.Flags = flags
+	entry.ExpiresAt = expiresAt
+	m.data[key] = entry
+}
+
+func (m *MockKVStore) SetCASWithExpiryLocal(key, value string, expectedIndex uint64, expiresAt time.Time) (uint64, error) {
+	idx, err := m.SetCASLocal(key, value, expectedIndex)
+	if err != nil {
+		return 0, err
 	}
-	return result
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := m.data[key]
+	entry.ExpiresAt = expiresAt
+	m.data[key] = entry
+	return idx, nil
 }
 
-func (m *MockKVStore) RestoreFromSnapshot(data map[string]store.KVEntrySnapshot) error {
+func (m *MockKVStore) SetSealedSecretLocal(key, sealed string, flags uint64, expiresAt time.Time) {
+	m.SetWithExpiryLocal(key, sealed, flags, expiresAt)
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.data = make(map[string]store.KVEntrySnapshot, len(data))
-	for k, v := range data {
-		m.data[k] = v
-		if v.ModifyIndex > m.nextIndex {
-			m.nextIndex = v.ModifyIndex
-		}
+	entry := m.data[key]
+	entry.Secret = true
+	m.data[key] = entry
+}
+
+func (m *MockKVStore) SetSealedSecretCASLocal(key, sealed string, expectedIndex uint64, expiresAt time.Time) (uint64, error) {
+	idx, err := m.SetCASWithExpiryLocal(key, sealed, expectedIndex, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := m.data[key]
+	entry.Secret = true
+	m.data[key] = entry
+	return idx, nil
+}
+
+func (m *MockKVStore) BatchSetWithExpiryLocal(items map[string]string, expiresAt time.Time) error {
+	for k, v := range items {
+		m.SetWithExpiryLocal(k, v, 0, expiresAt)
 	}
 	return nil
 }
 
+func (m *MockKVStore) DeleteExpiredLocal(keys []string, now time.Time) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted []string
+	for _, k := range keys {
+		entry, ok := m.data[k]
+		if ok && !entry.ExpiresAt.IsZero() && !now.Before(entry.ExpiresAt) {
+			delete(m.data, k)
+			deleted = append(deleted, k)
+		}
+	}
+	return deleted
+}
+
+func (m *MockKVStore) OpenSnapshot() store.RecordReader[store.KVRecord] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	records := make([]store.KVRecord, 0, len(m.data))
+	for k, v := range m.data {
+		records = append(records, store.KVRecord{Key: k, Entry: v})
+	}
+	return &sliceRecords[store.KVRecord]{records: records}
+}
+
+func (m *MockKVStore) PrepareRestore(next func() ([]store.KVRecord, error)) (func(), error) {
+	data := make(map[string]store.KVEntrySnapshot)
+	var maxIndex uint64
+	for {
+		records, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			data[r.Key] = r.Entry
+			maxIndex = max(maxIndex, r.Entry.ModifyIndex)
+		}
+	}
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.data = data
+		m.nextIndex = max(m.nextIndex, maxIndex)
+	}, nil
+}
+
 func (m *MockKVStore) GetEntrySnapshot(key string) (store.KVEntrySnapshot, bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -273,6 +351,16 @@ func (m *MockServiceStore) HeartbeatLocal(_ string) bool {
 	return true
 }
 
+func (m *MockServiceStore) ExpiredInstances(_ time.Time) map[string]uint64 {
+	return nil
+}
+
+func (m *MockServiceStore) ExpireLocal(_ map[string]uint64) []string {
+	return nil
+}
+
+func (m *MockServiceStore) ExtendTTLLocal(_ time.Duration) {}
+
 func (m *MockServiceStore) RegisterCASLocal(_ store.ServiceDataSnapshot, _ uint64) (uint64, error) {
 	return 0, nil
 }
@@ -306,22 +394,32 @@ func (m *MockServiceStore) GetEntrySnapshot(name string) (store.ServiceEntrySnap
 	}, true
 }
 
-func (m *MockServiceStore) GetAllData() map[string]store.ServiceEntrySnapshot {
-	result := make(map[string]store.ServiceEntrySnapshot, len(m.services))
+func (m *MockServiceStore) OpenSnapshot() store.RecordReader[store.ServiceRecord] {
+	records := make([]store.ServiceRecord, 0, len(m.services))
 	for name := range m.services {
-		result[name] = store.ServiceEntrySnapshot{
-			Service: store.ServiceDataSnapshot{Name: name},
-		}
+		records = append(records, store.ServiceRecord{
+			ID:    name,
+			Entry: store.ServiceEntrySnapshot{Service: store.ServiceDataSnapshot{Name: name}},
+		})
 	}
-	return result
+	return &sliceRecords[store.ServiceRecord]{records: records}
 }
 
-func (m *MockServiceStore) RestoreFromSnapshot(data map[string]store.ServiceEntrySnapshot) error {
-	m.services = make(map[string]interface{}, len(data))
-	for name, entry := range data {
-		m.services[name] = entry.Service
+func (m *MockServiceStore) PrepareRestore(next func() ([]store.ServiceRecord, error)) (func(), error) {
+	services := make(map[string]interface{})
+	for {
+		records, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			services[r.ID] = r.Entry.Service
+		}
 	}
-	return nil
+	return func() { m.services = services }, nil
 }
 
 func newTestConfig(t *testing.T, nodeID string, bootstrap bool) *Config {
@@ -0,0 +1,129 @@
+package raft
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/raft"
+)
+
+// appliedResultsSize is how many FSM responses are kept for the commands
+// forwarded to the leader.
+const appliedResultsSize = 512
+
+type appliedResult struct {
+	index uint64
+	resp  interface{}
+}
+
+// appliedResults tracks how far the FSM has applied the log and keeps the
+// responses of the most recent commands, so that a node can wait for the
+// commands it forwarded to the leader and a read replica for the reads it
+// confirmed with the leader.
+type appliedResults struct {
+	mu      sync.Mutex
+	entries [appliedResultsSize]appliedResult
+	last    uint64
+	notify  chan struct{}
+}
+
+func newAppliedResults() *appliedResults {
+	return &appliedResults{notify: make(chan struct{})}
+}
+
+// record notes that the entry at index was applied with resp.
+func (a *appliedResults) record(index uint64, resp interface{}) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.entries[index%appliedResultsSize] = appliedResult{index: index, resp: resp}
+	a.advance(index)
+}
+
+// restored notes that the FSM was replaced by a snapshot taken at index.
+// Snapshots only move the FSM forward, so the responses kept stay valid.
+func (a *appliedResults) restored(index uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.advance(index)
+}
+
+// advance must be called with mu held.
+func (a *appliedResults) advance(index uint64) {
+	a.last = max(a.last, index)
+	close(a.notify)
+	a.notify = make(chan struct{})
+}
+
+// lastIndex returns the index up to which the FSM has applied the log.
+func (a *appliedResults) lastIndex() uint64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.last
+}
+
+// result returns the response of the command at index, if still kept.
+func (a *appliedResults) result(index uint64) (interface{}, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	entry := a.entries[index%appliedResultsSize]
+	return entry.resp, entry.index == index
+}
+
+// wait blocks until the FSM has applied the log up to index. Returns false
+// if it has not after timeout or once done is closed.
+func (a *appliedResults) wait(index uint64, timeout time.Duration, done <-chan struct{}) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		a.mu.Lock()
+		last, notify := a.last, a.notify
+		a.mu.Unlock()
+		if last >= index {
+			return true
+		}
+
+		select {
+		case <-notify:
+		case <-timer.C:
+			return false
+		case <-done:
+			return false
+		}
+	}
+}
+
+// IsReadReplica returns true if this node is a read replica.
+func (n *Node) IsReadReplica() bool {
+	return n.config.NonVoter
+}
+
+// replicaReadIndex makes reads on this replica linearizable: the leader
+// confirms its leadership and returns the index it has applied the log up
+// to, which this node then waits to apply too.
+func (n *Node) replicaReadIndex(timeout time.Duration) error {
+	addr, err := n.leaderAddress()
+	if err != nil {
+		return err
+	}
+
+	var resp ReadIndexResponse
+	if err := n.cluster.call(addr, "Cluster.ReadIndex", &ReadIndexRequest{Timeout: timeout}, &resp, timeout+rpcTimeout); err != nil {
+		return err
+	}
+
+	if !n.fsm.applied.wait(resp.Index, timeout, n.shutdownCh) {
+		return fmt.Errorf("timed out waiting to apply index %d", resp.Index)
+	}
+	return nil
+}
+
+// serverRole asks the node at addr whether it is a read replica.
+func (n *Node) serverRole(addr raft.ServerAddress) (bool, error) {
+	var resp RoleResponse
+	req := &RoleRequest{From: n.config.NodeID}
+	if err := n.cluster.call(addr, "Cluster.Role", req, &resp, rpcTimeout); err != nil {
+		return false, err
+	}
+	return resp.ReadReplica, nil
+}
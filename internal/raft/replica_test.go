@@ -0,0 +1,123 @@
+package raft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppliedResults(t *testing.T) {
+	a := newAppliedResults()
+	a.record(5, "five")
+	a.record(6, nil)
+
+	resp, ok := a.result(5)
+	assert.True(t, ok)
+	assert.Equal(t, "five", resp)
+	_, ok = a.result(5 + appliedResultsSize)
+	assert.False(t, ok)
+
+	assert.True(t, a.wait(6, time.Millisecond, nil))
+	assert.False(t, a.wait(7, 10*time.Millisecond, nil))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		a.record(7, "seven")
+	}()
+	assert.True(t, a.wait(7, time.Second, nil))
+
+	// A restore moves the index forward and keeps the responses
+	a.restored(100)
+	assert.Equal(t, uint64(100), a.lastIndex())
+	resp, ok = a.result(7)
+	assert.True(t, ok)
+	assert.Equal(t, "seven", resp)
+	a.restored(0)
+	assert.Equal(t, uint64(100), a.lastIndex())
+
+	done := make(chan struct{})
+	close(done)
+	assert.False(t, a.wait(101, time.Second, done))
+}
+
+func TestReadReplica(t *testing.T) {
+	opts := clusterOptions{}
+	override := func(cfg *Config) {
+		cfg.Autopilot.Enabled = true
+		cfg.Autopilot.ServerStabilizationTime = 500 * time.Millisecond
+	}
+
+	cfg1 := newClusterConfigWithOverrides(t, "node-1", getFreeAddr(t), true, opts, override)
+	leader := startTestNode(t, cfg1)
+	defer func() { _ = leader.Shutdown() }()
+	require.NoError(t, leader.WaitForLeader(5*time.Second))
+	waitForSingleLeader(t, []*Node{leader}, 5*time.Second)
+
+	cfg2 := newClusterConfigWithOverrides(t, "node-2", getFreeAddr(t), false, opts, func(cfg *Config) {
+		override(cfg)
+		cfg.NonVoter = true
+	})
+	replica := startTestNode(t, cfg2)
+	defer func() { _ = replica.Shutdown() }()
+	require.NoError(t, leader.Join(cfg2.NodeID, cfg2.AdvertiseAddr))
+	require.NoError(t, replica.WaitForLeader(5*time.Second))
+
+	assert.True(t, replica.IsReadReplica())
+	assert.True(t, replica.AcceptsWrites())
+	assert.False(t, leader.IsReadReplica())
+
+	peerState := func(node *Node) string {
+		info, err := node.GetClusterInfo()
+		require.NoError(t, err)
+		for _, peer := range info.Peers {
+			if peer.ID == "node-2" {
+				return peer.State
+			}
+		}
+		return ""
+	}
+	assert.Equal(t, "ReadReplica", peerState(leader))
+	require.Eventually(t, func() bool { return peerState(replica) == "ReadReplica" },
+		5*time.Second, 50*time.Millisecond)
+
+	// Writes through the replica are applied by the leader
+	require.NoError(t, replica.KVSet("app/config", "v1"))
+	value, ok, err := leader.fsm.kvStore.(*MockKVStore).Get("app/config")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "v1", value)
+	value, ok, _ = replica.fsm.kvStore.(*MockKVStore).Get("app/config")
+	assert.True(t, ok, "the replica returns once it has applied its write")
+	assert.Equal(t, "v1", value)
+
+	// CAS responses come back typed, as on the leader
+	index, err := replica.KVSetCAS("app/lock", "a", 0)
+	require.NoError(t, err)
+	assert.NotZero(t, index)
+	_, err = replica.KVSetCAS("app/lock", "b", 0)
+	assert.ErrorContains(t, err, "CAS conflict")
+
+	// Linearizable reads go through the leader
+	require.NoError(t, leader.KVSet("app/direct", "v2"))
+	require.NoError(t, replica.EnsureLinearizableRead(5*time.Second))
+	value, ok, _ = replica.fsm.kvStore.(*MockKVStore).Get("app/direct")
+	assert.True(t, ok)
+	assert.Equal(t, "v2", value)
+
+	// The autopilot never promotes a read replica
+	time.Sleep(2 * autopilotInterval)
+	configuration, err := leader.GetConfiguration()
+	require.NoError(t, err)
+	for _, srv := range configuration.Servers {
+		if srv.ID == "node-2" {
+			assert.Equal(t, raft.Nonvoter, srv.Suffrage)
+		}
+	}
+	health, err := leader.AutopilotHealth()
+	require.NoError(t, err)
+	require.Len(t, health.Servers, 2)
+	assert.True(t, health.Servers[1].ReadReplica)
+}
@@ -0,0 +1,345 @@
+package raft
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+)
+
+// rpcCluster is the first byte of the connections carrying cluster RPCs on
+// the Raft port. Raft starts its own connections with the type of their
+// first RPC, which is always below it.
+const rpcCluster byte = 0x80
+
+// rpcTimeout bounds the cluster RPCs that carry no timeout of their own.
+const rpcTimeout = 5 * time.Second
+
+var (
+	// errRPCTimeout is returned when a cluster RPC gets no answer in time.
+	errRPCTimeout = errors.New("cluster RPC timed out")
+
+	// errDial is returned when a connection to a node cannot be opened, so
+	// that no call was sent on it.
+	errDial = errors.New("failed to connect to node")
+)
+
+// muxStreamLayer shares the Raft listener with the cluster RPCs: it serves
+// the connections starting with rpcCluster itself and hands all the others
+// to Raft.
+type muxStreamLayer struct {
+	raft.StreamLayer
+
+	server *rpc.Server
+	logger hclog.Logger
+
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newMuxStreamLayer(stream raft.StreamLayer, server *rpc.Server, logger hclog.Logger) *muxStreamLayer {
+	m := &muxStreamLayer{
+		StreamLayer: stream,
+		server:      server,
+		logger:      logger,
+		conns:       make(chan net.Conn),
+		closed:      make(chan struct{}),
+	}
+	go m.acceptLoop()
+	return m
+}
+
+func (m *muxStreamLayer) acceptLoop() {
+	var backoff time.Duration
+	for {
+		conn, err := m.StreamLayer.Accept()
+		if err != nil {
+			select {
+			case <-m.closed:
+				return
+			default:
+			}
+			backoff = min(max(2*backoff, 5*time.Millisecond), time.Second)
+			m.logger.Error("failed to accept connection", "error", err)
+			time.Sleep(backoff)
+			continue
+		}
+		backoff = 0
+		go m.route(conn)
+	}
+}
+
+// route reads the first byte of conn to tell cluster RPCs from Raft traffic.
+func (m *muxStreamLayer) route(conn net.Conn) {
+	_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	reader := bufio.NewReader(conn)
+	first, err := reader.Peek(1)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	if first[0] == rpcCluster {
+		_, _ = reader.Discard(1)
+		m.server.ServeConn(&bufferedConn{Conn: conn, reader: reader})
+		return
+	}
+
+	select {
+	case m.conns <- &bufferedConn{Conn: conn, reader: reader}:
+	case <-m.closed:
+		_ = conn.Close()
+	}
+}
+
+// Accept implements net.Listener.Accept for Raft.
+func (m *muxStreamLayer) Accept() (net.Conn, error) {
+	select {
+	case conn := <-m.conns:
+		return conn, nil
+	case <-m.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close implements net.Listener.Close.
+func (m *muxStreamLayer) Close() error {
+	m.closeOnce.Do(func() { close(m.closed) })
+	return m.StreamLayer.Close()
+}
+
+// bufferedConn is a connection whose first bytes were read ahead.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// RoleRequest asks a server for its role.
+type RoleRequest struct {
+	From string
+}
+
+// RoleResponse is the role of a server.
+type RoleResponse struct {
+	ReadReplica bool
+}
+
+// ReadIndexRequest asks the leader for an index that makes reads
+// linearizable.
+type ReadIndexRequest struct {
+	Timeout time.Duration
+}
+
+// ReadIndexResponse carries the index of the last command the leader applied
+// after confirming its leadership.
+type ReadIndexResponse struct {
+	Index uint64
+}
+
+// ForwardRequest carries a marshaled Command for the leader to apply. The
+// node that received the write authorized it before forwarding it.
+type ForwardRequest struct {
+	From    string
+	Command []byte
+	Timeout time.Duration
+}
+
+// ForwardResponse carries the log index of a forwarded command, and its error
+// on the leader if the FSM returned one.
+type ForwardResponse struct {
+	Index uint64
+	Error string
+}
+
+// HeartbeatRequest carries the heartbeat of a service instance for the
+// leader to track.
+type HeartbeatRequest struct {
+	From string
+	ID   string
+}
+
+// HeartbeatResponse acknowledges a heartbeat.
+type HeartbeatResponse struct{}
+
+// clusterEndpoint serves the cluster RPCs of a node.
+type clusterEndpoint struct {
+	node atomic.Pointer[Node]
+}
+
+func (e *clusterEndpoint) leader() (*Node, error) {
+	n := e.node.Load()
+	if n == nil {
+		return nil, ErrShutdown
+	}
+	if n.raft.State() != raft.Leader {
+		return nil, ErrNotLeader
+	}
+	return n, nil
+}
+
+// Role returns the role of this node.
+func (e *clusterEndpoint) Role(_ *RoleRequest, resp *RoleResponse) error {
+	n := e.node.Load()
+	if n == nil {
+		return ErrShutdown
+	}
+	resp.ReadReplica = n.config.NonVoter
+	return nil
+}
+
+// ReadIndex confirms the leadership of this node and returns the index of
+// the last command it applied.
+func (e *clusterEndpoint) ReadIndex(req *ReadIndexRequest, resp *ReadIndexResponse) error {
+	n, err := e.leader()
+	if err != nil {
+		return err
+	}
+	if err := n.EnsureLinearizableRead(req.Timeout); err != nil {
+		return err
+	}
+	resp.Index = n.fsm.applied.lastIndex()
+	return nil
+}
+
+// Forward applies a command forwarded by another node.
+func (e *clusterEndpoint) Forward(req *ForwardRequest, resp *ForwardResponse) error {
+	n, err := e.leader()
+	if err != nil {
+		return err
+	}
+	n.logger.Debug("applying forwarded command", "from", req.From)
+
+	future := n.raft.Apply(req.Command, req.Timeout)
+	if err := future.Error(); err != nil {
+		switch {
+		case errors.Is(err, raft.ErrNotLeader):
+			return ErrNotLeader
+		case errors.Is(err, raft.ErrLeadershipLost):
+			return ErrLeadershipLost
+		case errors.Is(err, raft.ErrRaftShutdown):
+			return ErrShutdown
+		}
+		return err
+	}
+	resp.Index = future.Index()
+	if err, ok := future.Response().(error); ok && err != nil {
+		resp.Error = err.Error()
+	}
+	return nil
+}
+
+// Heartbeat extends the TTL of a service instance for another node.
+func (e *clusterEndpoint) Heartbeat(req *HeartbeatRequest, _ *HeartbeatResponse) error {
+	n, err := e.leader()
+	if err != nil {
+		return err
+	}
+	return n.heartbeatLocal(req.ID)
+}
+
+// clusterClient calls the cluster RPCs of other nodes, keeping one
+// connection per node.
+type clusterClient struct {
+	stream raft.StreamLayer
+
+	mu      sync.Mutex
+	clients map[raft.ServerAddress]*rpc.Client
+}
+
+func newClusterClient(stream raft.StreamLayer) *clusterClient {
+	return &clusterClient{
+		stream:  stream,
+		clients: make(map[raft.ServerAddress]*rpc.Client),
+	}
+}
+
+func (c *clusterClient) client(addr raft.ServerAddress, timeout time.Duration) (*rpc.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if client, ok := c.clients[addr]; ok {
+		return client, nil
+	}
+
+	conn, err := c.stream.Dial(addr, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %v", errDial, addr, err)
+	}
+	if _, err := conn.Write([]byte{rpcCluster}); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("%w %s: %v", errDial, addr, err)
+	}
+	client := rpc.NewClient(conn)
+	c.clients[addr] = client
+	return client, nil
+}
+
+// drop closes the connection to addr if it is still client.
+func (c *clusterClient) drop(addr raft.ServerAddress, client *rpc.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.clients[addr] == client {
+		delete(c.clients, addr)
+	}
+	_ = client.Close()
+}
+
+// call calls method on the node at addr. Errors returned by the node that
+// match the errors of this package are returned as such. Only a failure to
+// connect returns errDial: once the call is started any other failure,
+// rpc.ErrShutdown included, leaves unknown whether the node received it.
+func (c *clusterClient) call(addr raft.ServerAddress, method string, args, reply any, timeout time.Duration) error {
+	client, err := c.client(addr, timeout)
+	if err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	call := client.Go(method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		err = call.Error
+	case <-timer.C:
+		err = errRPCTimeout
+	}
+
+	var serverErr rpc.ServerError
+	if errors.As(err, &serverErr) {
+		for _, known := range []error{ErrNotLeader, ErrNoLeader, ErrLeadershipLost, ErrShutdown, ErrServiceNotFound} {
+			if string(serverErr) == known.Error() {
+				return known
+			}
+		}
+		return errors.New(string(serverErr))
+	}
+	if err != nil {
+		// The connection may be broken or out of step with the node. Another
+		// call dropping it also fails the calls already sent on it with
+		// rpc.ErrShutdown, so that error is not taken as a call never sent
+		c.drop(addr, client)
+	}
+	return err
+}
+
+// Close closes the connections to other nodes.
+func (c *clusterClient) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for addr, client := range c.clients {
+		_ = client.Close()
+		delete(c.clients, addr)
+	}
+}
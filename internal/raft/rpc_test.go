@@ -0,0 +1,103 @@
+package raft
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/rpc"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pipeStream is a stream layer whose connections go to a node that reads
+// the calls sent to it and never answers.
+type pipeStream struct {
+	received chan struct{}
+	dialErr  error
+}
+
+func (s *pipeStream) Accept() (net.Conn, error) { return nil, errors.New("not accepting") }
+func (s *pipeStream) Close() error              { return nil }
+func (s *pipeStream) Addr() net.Addr            { return &net.TCPAddr{} }
+
+// eofConn reads io.EOF once closed, as a connection closed by its peer
+// does, so that net/rpc fails the calls pending on it with rpc.ErrShutdown.
+type eofConn struct {
+	net.Conn
+}
+
+func (c eofConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if errors.Is(err, io.ErrClosedPipe) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (s *pipeStream) Dial(_ raft.ServerAddress, _ time.Duration) (net.Conn, error) {
+	if s.dialErr != nil {
+		return nil, s.dialErr
+	}
+	client, server := net.Pipe()
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, err := server.Read(buf)
+			if err != nil {
+				return
+			}
+			// The first read is the rpcCluster byte, the next ones the call
+			if n > 0 && buf[0] != rpcCluster {
+				select {
+				case s.received <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return eofConn{client}, nil
+}
+
+func TestClusterClient_DialFailureIsRetryable(t *testing.T) {
+	c := newClusterClient(&pipeStream{dialErr: errors.New("connection refused")})
+	defer c.Close()
+
+	err := c.call("node2", "Cluster.Forward", &ForwardRequest{}, &ForwardResponse{}, time.Second)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errDial), "unexpected error: %v", err)
+	assert.True(t, retryableForward(err))
+}
+
+func TestClusterClient_DroppedCallIsNotRetryable(t *testing.T) {
+	stream := &pipeStream{received: make(chan struct{}, 1)}
+	c := newClusterClient(stream)
+	defer c.Close()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.call("node2", "Cluster.Forward", &ForwardRequest{}, &ForwardResponse{}, 10*time.Second)
+	}()
+	select {
+	case <-stream.received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("call never sent")
+	}
+
+	// Another call failing drops the connection the first one was sent on
+	client, err := c.client("node2", time.Second)
+	require.NoError(t, err)
+	c.drop("node2", client)
+
+	select {
+	case err = <-errCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("call not failed by the dropped connection")
+	}
+	require.ErrorIs(t, err, rpc.ErrShutdown)
+	assert.False(t, errors.Is(err, errDial), "unexpected error: %v", err)
+	assert.False(t, retryableForward(err))
+}
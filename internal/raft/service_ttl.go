@@ -0,0 +1,143 @@
+package raft
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/raft"
+)
+
+// maxServiceExpireBatch bounds the instances expired by one log entry.
+const maxServiceExpireBatch = 1024
+
+// ServiceHeartbeat extends the TTL of a service instance. Heartbeats are not
+// written to the log: the leader tracks them in memory and removes the
+// instances that stop heartbeating through the log, see runServiceExpiry.
+// Other nodes send the heartbeat to the leader. Returns ErrServiceNotFound
+// if the leader does not know the instance.
+func (n *Node) ServiceHeartbeat(id string) error {
+	if n.raft.State() == raft.Leader {
+		return n.heartbeatLocal(id)
+	}
+	if !n.forwardsWrites() {
+		return ErrNotLeader
+	}
+
+	err := n.forwardWithRetries(func() error {
+		return n.forwardHeartbeat(id)
+	}, func() error {
+		return n.heartbeatLocal(id)
+	})
+	if err != nil {
+		return err
+	}
+	// Keep the deadline of this node close to the leader's, should it be
+	// elected next
+	n.fsm.serviceStore.HeartbeatLocal(id)
+	return nil
+}
+
+func (n *Node) heartbeatLocal(id string) error {
+	if !n.fsm.serviceStore.HeartbeatLocal(id) {
+		return ErrServiceNotFound
+	}
+	return nil
+}
+
+func (n *Node) forwardHeartbeat(id string) error {
+	addr, err := n.leaderAddress()
+	if err != nil {
+		return err
+	}
+	req := &HeartbeatRequest{From: n.config.NodeID, ID: id}
+	err = n.cluster.call(addr, "Cluster.Heartbeat", req, &HeartbeatResponse{}, rpcTimeout)
+	if err != nil && strings.HasPrefix(err.Error(), "rpc: can't find") {
+		// Leaders running an older version track heartbeats in the log
+		return n.forwardLegacyHeartbeat(id)
+	}
+	return err
+}
+
+func (n *Node) forwardLegacyHeartbeat(id string) error {
+	ns, id := payloadKey(id)
+	cmd, err := NewCommand(CmdServiceHeartbeat, ServiceHeartbeatPayload{Namespace: ns, ID: id})
+	if err != nil {
+		return err
+	}
+	data, err := cmd.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal command: %w", err)
+	}
+	_, err = n.forwardOnce(data, 5*time.Second)
+	return err
+}
+
+// ServiceExpire removes the service instances the leader found expired, with
+// the ModifyIndex they were found expired at, through Raft consensus.
+func (n *Node) ServiceExpire(instances map[string]uint64) error {
+	cmd, err := NewCommand(CmdServiceExpire, ServiceExpirePayload{Instances: instances})
+	if err != nil {
+		return err
+	}
+	return n.applyCommand(cmd, 10*time.Second)
+}
+
+// runServiceExpiry removes the service instances whose TTL has run out
+// while this node is the leader. A node that becomes the leader first
+// extends every TTL by ServiceTTL.Grace, as the instances heartbeated the
+// previous leader. This runs in a background goroutine for the lifetime of
+// the node.
+func (n *Node) runServiceExpiry() {
+	if n.config.ServiceTTL.ExpiryInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(n.config.ServiceTTL.ExpiryInterval)
+	defer ticker.Stop()
+
+	// The term this node was last elected in
+	var leaderTerm uint64
+	for {
+		select {
+		case <-ticker.C:
+			if n.raft.State() != raft.Leader {
+				continue
+			}
+			if term := n.raft.CurrentTerm(); term != leaderTerm {
+				n.fsm.serviceStore.ExtendTTLLocal(n.config.ServiceTTL.Grace)
+				leaderTerm = term
+				continue
+			}
+			n.expireServices(time.Now())
+		case <-n.shutdownCh:
+			return
+		}
+	}
+}
+
+// expireServices removes the service instances whose TTL has run out at now,
+// in batches of maxServiceExpireBatch.
+func (n *Node) expireServices(now time.Time) {
+	expired := n.fsm.serviceStore.ExpiredInstances(now)
+	ids := make([]string, 0, len(expired))
+	for id := range expired {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for len(ids) > 0 {
+		batch := ids[:min(len(ids), maxServiceExpireBatch)]
+		ids = ids[len(batch):]
+
+		instances := make(map[string]uint64, len(batch))
+		for _, id := range batch {
+			instances[id] = expired[id]
+		}
+		if err := n.ServiceExpire(instances); err != nil {
+			n.logger.Warn("failed to expire services", "count", len(instances), "error", err)
+			return
+		}
+		n.logger.Debug("expired services", "count", len(instances))
+	}
+}
@@ -0,0 +1,154 @@
+package raft
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neogan74/konsul/internal/store"
+)
+
+func TestServiceTTL_HeartbeatsStayOutOfLog(t *testing.T) {
+	nodes := newForwardingCluster(t, nil)
+	leader, follower := nodes[0], nodes[1]
+
+	require.NoError(t, follower.ServiceRegister(store.Service{ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80}))
+	lastIndex := leader.raft.LastIndex()
+
+	for i := 0; i < 50; i++ {
+		require.NoError(t, leader.ServiceHeartbeat("web-1"))
+		require.NoError(t, follower.ServiceHeartbeat("web-1"))
+	}
+	assert.Equal(t, lastIndex, leader.raft.LastIndex(), "heartbeats must not be written to the log")
+
+	// Unknown instances are reported by the leader, also through a follower
+	assert.ErrorIs(t, leader.ServiceHeartbeat("db-1"), ErrServiceNotFound)
+	assert.ErrorIs(t, follower.ServiceHeartbeat("db-1"), ErrServiceNotFound)
+}
+
+func TestServiceTTL_LeaderExpiresInstances(t *testing.T) {
+	nodes := newForwardingClusterWithServiceTTL(t, time.Second, func(cfg *Config) {
+		cfg.ServiceTTL.ExpiryInterval = 100 * time.Millisecond
+		cfg.ServiceTTL.Grace = 0
+	})
+	follower := nodes[1]
+
+	require.NoError(t, follower.ServiceRegister(store.Service{ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80}))
+	require.NoError(t, follower.ServiceRegister(store.Service{ID: "web-2", Name: "web", Address: "10.0.0.2", Port: 80}))
+
+	// Only web-2 heartbeats; web-1 is removed from every node
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		require.NoError(t, follower.ServiceHeartbeat("web-2"))
+		time.Sleep(200 * time.Millisecond)
+	}
+	for _, node := range nodes {
+		_, ok := node.services.Get("web-1")
+		assert.False(t, ok, "%s still lists web-1", node.config.NodeID)
+		_, ok = node.services.Get("web-2")
+		assert.True(t, ok, "%s lost web-2", node.config.NodeID)
+	}
+
+	for _, node := range nodes {
+		waitForServiceRemoved(t, node.services, "web-2", 5*time.Second)
+	}
+	assert.ErrorIs(t, follower.ServiceHeartbeat("web-2"), ErrServiceNotFound)
+}
+
+func TestServiceTTL_NewLeaderGrantsGrace(t *testing.T) {
+	nodes := newForwardingClusterWithServiceTTL(t, time.Second, func(cfg *Config) {
+		cfg.ServiceTTL.ExpiryInterval = 100 * time.Millisecond
+		cfg.ServiceTTL.Grace = 2 * time.Second
+	})
+	require.NoError(t, nodes[0].ServiceRegister(store.Service{ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80}))
+
+	// Heartbeats only reach the leader, so the deadlines of the other nodes
+	// run out
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		require.NoError(t, nodes[0].ServiceHeartbeat("web-1"))
+		time.Sleep(200 * time.Millisecond)
+	}
+	require.NoError(t, nodes[0].Shutdown())
+
+	leader := waitForSingleLeader(t, []*Node{nodes[1].Node, nodes[2].Node}, 10*time.Second)
+	elected := nodes[1]
+	if leader == nodes[2].Node {
+		elected = nodes[2]
+	}
+
+	// The new leader waits for the TTL and the grace before expiring web-1
+	assert.Never(t, func() bool {
+		_, ok := elected.services.Get("web-1")
+		return !ok
+	}, 2*time.Second, 100*time.Millisecond)
+	waitForServiceRemoved(t, elected.services, "web-1", 5*time.Second)
+}
+
+// waitForServiceRemoved polls until services no longer lists id or timeout
+// expires.
+func waitForServiceRemoved(t *testing.T, services *store.ServiceStore, id string, timeout time.Duration) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		_, ok := services.Get(id)
+		return !ok
+	}, timeout, 50*time.Millisecond, "service %q was never removed", id)
+}
+
+// BenchmarkServiceHeartbeat_LogGrowth compares the log growth of heartbeats
+// written to the log, as they used to be, with heartbeats tracked by the
+// leader in memory.
+func BenchmarkServiceHeartbeat_LogGrowth(b *testing.B) {
+	cfg := newClusterConfig(b, "node-1", getFreeAddr(b), true, clusterOptions{})
+	// Keep every entry in the log to measure it
+	cfg.SnapshotThreshold = math.MaxUint64
+	cfg.SnapshotInterval = time.Hour
+	node := startForwardingTestNode(b, cfg, 30*time.Second)
+	b.Cleanup(func() { _ = node.Shutdown() })
+	require.NoError(b, node.WaitForLeader(10*time.Second))
+	require.NoError(b, node.ServiceRegister(store.Service{ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80}))
+
+	for _, bench := range []struct {
+		name      string
+		heartbeat func() error
+	}{
+		{"log", func() error {
+			cmd, err := NewCommand(CmdServiceHeartbeat, ServiceHeartbeatPayload{ID: "web-1"})
+			if err != nil {
+				return err
+			}
+			_, err = node.ApplyEntry(cmd, 5*time.Second)
+			return err
+		}},
+		{"leader", func() error {
+			return node.ServiceHeartbeat("web-1")
+		}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			first := node.raft.LastIndex()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := bench.heartbeat(); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+
+			last := node.raft.LastIndex()
+			var size int
+			for index := first + 1; index <= last; index++ {
+				var entry raft.Log
+				if err := node.logStore.GetLog(index, &entry); err != nil {
+					b.Fatal(err)
+				}
+				size += len(entry.Data)
+			}
+			b.ReportMetric(float64(last-first)/float64(b.N), "log-entries/op")
+			b.ReportMetric(float64(size)/float64(b.N), "log-bytes/op")
+		})
+	}
+}
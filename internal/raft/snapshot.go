@@ -0,0 +1,505 @@
+package raft
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/neogan74/konsul/internal/store"
+)
+
+// Snapshots start with a header that is never compressed:
+//
+//	magic "KSNP" | version (1 byte) | compression (1 byte) | index (8 bytes, big endian)
+//
+// followed by the compressed records, each made of a kind byte, the uvarint
+// length of its payload and the payload. KV entries are written in chunks of
+// snapshotChunkSize with a compact binary encoding, service instances in
+// chunks of JSON, and every other section as a single JSON record. The last
+// record is a recordEnd, so that truncated snapshots are detected.
+//
+// Snapshots taken before this format are a single JSON document (see
+// SnapshotData), which Restore still reads.
+const (
+	snapshotMagic     = "KSNP"
+	snapshotVersion   = 1
+	snapshotChunkSize = 1024
+
+	// maxSnapshotRecordSize bounds the payload of a record, so that a
+	// corrupt length does not make Restore allocate without limit.
+	maxSnapshotRecordSize = 256 << 20
+)
+
+// Snapshot compression algorithms, as stored in the header.
+const (
+	compressionNone byte = iota
+	compressionGzip
+	compressionZstd
+)
+
+type snapshotRecordKind byte
+
+const (
+	recordKV snapshotRecordKind = iota + 1
+	recordServices
+	recordSessions
+	recordNamespaces
+	recordACLPolicies
+	recordAPIKeys
+	recordUsers
+	recordPreparedQueries
+	recordReadReplicas
+
+	recordEnd snapshotRecordKind = 0xff
+)
+
+// errSnapshotTruncated is returned when a snapshot ends before its recordEnd.
+var errSnapshotTruncated = errors.New("snapshot is truncated")
+
+// snapshotCompression returns the compression algorithm called name; the
+// empty name selects zstd.
+func snapshotCompression(name string) (byte, error) {
+	switch name {
+	case "", "zstd":
+		return compressionZstd, nil
+	case "gzip":
+		return compressionGzip, nil
+	case "none":
+		return compressionNone, nil
+	default:
+		return compressionZstd, fmt.Errorf("unknown snapshot compression %q (want zstd, gzip or none)", name)
+	}
+}
+
+// write encodes the snapshot to w.
+func (s *KonsulSnapshot) write(w io.Writer) error {
+	sw, err := newSnapshotWriter(w, s.compression, s.Index)
+	if err != nil {
+		return err
+	}
+
+	for {
+		chunk, err := s.KVRecords.Next(snapshotChunkSize)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		sw.chunk = appendKVChunk(sw.chunk[:0], chunk)
+		if err := sw.writeRecord(recordKV, sw.chunk); err != nil {
+			return err
+		}
+	}
+	for {
+		chunk, err := s.ServiceRecords.Next(snapshotChunkSize)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := sw.writeJSON(recordServices, chunk); err != nil {
+			return err
+		}
+	}
+
+	sections := []struct {
+		kind  snapshotRecordKind
+		value any
+		empty bool
+	}{
+		{recordSessions, s.SessionData, s.SessionData == nil},
+		{recordNamespaces, s.NamespaceData, s.NamespaceData == nil},
+		{recordACLPolicies, s.ACLPolicies, s.ACLPolicies == nil},
+		{recordAPIKeys, s.APIKeys, s.APIKeys == nil},
+		{recordUsers, s.Users, s.Users == nil},
+		{recordPreparedQueries, s.PreparedQueries, s.PreparedQueries == nil},
+		{recordReadReplicas, s.ReadReplicas, len(s.ReadReplicas) == 0},
+	}
+	for _, section := range sections {
+		if section.empty {
+			continue
+		}
+		if err := sw.writeJSON(section.kind, section.value); err != nil {
+			return err
+		}
+	}
+
+	return sw.close()
+}
+
+// singleChunk returns a PrepareRestore callback that returns records once.
+func singleChunk[T any](records []T) func() ([]T, error) {
+	done := false
+	return func() ([]T, error) {
+		if done {
+			return nil, io.EOF
+		}
+		done = true
+		return records, nil
+	}
+}
+
+// snapshotWriter writes the records of a snapshot.
+type snapshotWriter struct {
+	buf    *bufio.Writer
+	out    io.WriteCloser // compresses into buf
+	prefix [1 + binary.MaxVarintLen64]byte
+	chunk  []byte // reused between KV chunks
+}
+
+func newSnapshotWriter(w io.Writer, compression byte, index uint64) (*snapshotWriter, error) {
+	sw := &snapshotWriter{buf: bufio.NewWriterSize(w, 64<<10)}
+
+	header := make([]byte, 0, len(snapshotMagic)+10)
+	header = append(header, snapshotMagic...)
+	header = append(header, snapshotVersion, compression)
+	header = binary.BigEndian.AppendUint64(header, index)
+	if _, err := sw.buf.Write(header); err != nil {
+		return nil, err
+	}
+
+	switch compression {
+	case compressionNone:
+		sw.out = nopWriteCloser{sw.buf}
+	case compressionGzip:
+		sw.out = gzip.NewWriter(sw.buf)
+	case compressionZstd:
+		enc, err := zstd.NewWriter(sw.buf, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		sw.out = enc
+	default:
+		return nil, fmt.Errorf("unknown snapshot compression %d", compression)
+	}
+	return sw, nil
+}
+
+func (sw *snapshotWriter) writeRecord(kind snapshotRecordKind, payload []byte) error {
+	sw.prefix[0] = byte(kind)
+	n := binary.PutUvarint(sw.prefix[1:], uint64(len(payload)))
+	if _, err := sw.out.Write(sw.prefix[:1+n]); err != nil {
+		return err
+	}
+	_, err := sw.out.Write(payload)
+	return err
+}
+
+func (sw *snapshotWriter) writeJSON(kind snapshotRecordKind, v any) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot record %d: %w", kind, err)
+	}
+	return sw.writeRecord(kind, payload)
+}
+
+// close ends the snapshot and flushes it.
+func (sw *snapshotWriter) close() error {
+	if err := sw.writeRecord(recordEnd, nil); err != nil {
+		return err
+	}
+	if err := sw.out.Close(); err != nil {
+		return err
+	}
+	return sw.buf.Flush()
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// isSnapshotFormat returns true if r starts with the header of a snapshot
+// in the chunked format rather than with a JSON snapshot.
+func isSnapshotFormat(r *bufio.Reader) bool {
+	magic, err := r.Peek(len(snapshotMagic))
+	return err == nil && string(magic) == snapshotMagic
+}
+
+// snapshotReader reads the records of a snapshot written by snapshotWriter.
+type snapshotReader struct {
+	index uint64
+	in    *bufio.Reader // decompressed records
+	close func()
+
+	// the record read last, returned again by next after unread
+	kind    snapshotRecordKind
+	payload []byte
+	pending bool
+
+	kvChunk []store.KVRecord // reused between chunks
+}
+
+func newSnapshotReader(r *bufio.Reader) (*snapshotReader, error) {
+	header := make([]byte, len(snapshotMagic)+10)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot header: %w", err)
+	}
+	if version := header[len(snapshotMagic)]; version != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", version)
+	}
+	sr := &snapshotReader{
+		index: binary.BigEndian.Uint64(header[len(snapshotMagic)+2:]),
+		close: func() {},
+	}
+
+	switch compression := header[len(snapshotMagic)+1]; compression {
+	case compressionNone:
+		sr.in = r
+	case compressionGzip:
+		dec, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open snapshot: %w", err)
+		}
+		sr.in = bufio.NewReaderSize(dec, 64<<10)
+		sr.close = func() { _ = dec.Close() }
+	case compressionZstd:
+		dec, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open snapshot: %w", err)
+		}
+		sr.in = bufio.NewReaderSize(dec, 64<<10)
+		sr.close = dec.Close
+	default:
+		return nil, fmt.Errorf("unknown snapshot compression %d", compression)
+	}
+	return sr, nil
+}
+
+// next returns the next record. The payload is only valid until the
+// following call.
+func (sr *snapshotReader) next() (snapshotRecordKind, []byte, error) {
+	if sr.pending {
+		sr.pending = false
+		return sr.kind, sr.payload, nil
+	}
+
+	kind, err := sr.in.ReadByte()
+	if err != nil {
+		return 0, nil, truncated(err)
+	}
+	size, err := binary.ReadUvarint(sr.in)
+	if err != nil {
+		return 0, nil, truncated(err)
+	}
+	if size > maxSnapshotRecordSize {
+		return 0, nil, fmt.Errorf("snapshot record %d is too large: %d bytes", kind, size)
+	}
+	if uint64(cap(sr.payload)) < size {
+		sr.payload = make([]byte, size)
+	}
+	sr.payload = sr.payload[:size]
+	if _, err := io.ReadFull(sr.in, sr.payload); err != nil {
+		return 0, nil, truncated(err)
+	}
+	sr.kind = snapshotRecordKind(kind)
+	return sr.kind, sr.payload, nil
+}
+
+// unread makes next return the last record again.
+func (sr *snapshotReader) unread() {
+	sr.pending = true
+}
+
+func truncated(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errSnapshotTruncated
+	}
+	return err
+}
+
+// kvChunks is a KVStoreInterface.PrepareRestore callback.
+func (sr *snapshotReader) kvChunks() ([]store.KVRecord, error) {
+	kind, payload, err := sr.next()
+	if err != nil {
+		return nil, err
+	}
+	if kind != recordKV {
+		sr.unread()
+		return nil, io.EOF
+	}
+	sr.kvChunk, err = decodeKVChunk(sr.kvChunk[:0], payload)
+	return sr.kvChunk, err
+}
+
+// serviceChunks is a ServiceStoreInterface.PrepareRestore callback.
+func (sr *snapshotReader) serviceChunks() ([]store.ServiceRecord, error) {
+	kind, payload, err := sr.next()
+	if err != nil {
+		return nil, err
+	}
+	if kind != recordServices {
+		sr.unread()
+		return nil, io.EOF
+	}
+	var chunk []store.ServiceRecord
+	if err := json.Unmarshal(payload, &chunk); err != nil {
+		return nil, fmt.Errorf("failed to decode service records: %w", err)
+	}
+	return chunk, nil
+}
+
+// readSections reads the records that follow the KV and service records
+// into snapshot, up to the end of the snapshot.
+func (sr *snapshotReader) readSections(snapshot *SnapshotData) error {
+	for {
+		kind, payload, err := sr.next()
+		if err != nil {
+			return err
+		}
+
+		var target any
+		switch kind {
+		case recordSessions:
+			target = &snapshot.SessionData
+		case recordNamespaces:
+			target = &snapshot.NamespaceData
+		case recordACLPolicies:
+			target = &snapshot.ACLPolicies
+		case recordAPIKeys:
+			target = &snapshot.APIKeys
+		case recordUsers:
+			target = &snapshot.Users
+		case recordPreparedQueries:
+			target = &snapshot.PreparedQueries
+		case recordReadReplicas:
+			target = &snapshot.ReadReplicas
+		case recordEnd:
+			return nil
+		default:
+			return fmt.Errorf("unexpected snapshot record %d", kind)
+		}
+		if err := json.Unmarshal(payload, target); err != nil {
+			return fmt.Errorf("failed to decode snapshot record %d: %w", kind, err)
+		}
+	}
+}
+
+// appendKVChunk appends the binary encoding of records to dst: the number of
+// records, then for each the key, value, modify index, create index, flags,
+// session, lock index, expiry in Unix nanoseconds (0 if none) and secret flag.
+// Strings are length-prefixed and integers uvarints.
+func appendKVChunk(dst []byte, records []store.KVRecord) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(records)))
+	for _, record := range records {
+		entry := record.Entry
+		dst = appendString(dst, record.Key)
+		dst = appendString(dst, entry.Value)
+		dst = binary.AppendUvarint(dst, entry.ModifyIndex)
+		dst = binary.AppendUvarint(dst, entry.CreateIndex)
+		dst = binary.AppendUvarint(dst, entry.Flags)
+		dst = appendString(dst, entry.Session)
+		dst = binary.AppendUvarint(dst, entry.LockIndex)
+		var expiresAt int64
+		if !entry.ExpiresAt.IsZero() {
+			expiresAt = entry.ExpiresAt.UnixNano()
+		}
+		dst = binary.AppendVarint(dst, expiresAt)
+		secret := byte(0)
+		if entry.Secret {
+			secret = 1
+		}
+		dst = append(dst, secret)
+	}
+	return dst
+}
+
+func appendString(dst []byte, s string) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(s)))
+	return append(dst, s...)
+}
+
+// decodeKVChunk appends the records encoded by appendKVChunk to dst.
+func decodeKVChunk(dst []store.KVRecord, payload []byte) ([]store.KVRecord, error) {
+	d := kvDecoder{buf: payload}
+	count := d.uvarint()
+	for i := uint64(0); i < count && d.err == nil; i++ {
+		var record store.KVRecord
+		record.Key = d.text()
+		record.Entry.Value = d.text()
+		record.Entry.ModifyIndex = d.uvarint()
+		record.Entry.CreateIndex = d.uvarint()
+		record.Entry.Flags = d.uvarint()
+		record.Entry.Session = d.text()
+		record.Entry.LockIndex = d.uvarint()
+		if expiresAt := d.varint(); expiresAt != 0 {
+			record.Entry.ExpiresAt = time.Unix(0, expiresAt)
+		}
+		record.Entry.Secret = d.flag()
+		dst = append(dst, record)
+	}
+	if d.err == nil && len(d.buf) != 0 {
+		d.err = fmt.Errorf("%d trailing bytes", len(d.buf))
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("failed to decode KV records: %w", d.err)
+	}
+	return dst, nil
+}
+
+// kvDecoder reads the fields of a KV chunk, keeping the first error.
+type kvDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *kvDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *kvDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *kvDecoder) flag() bool {
+	if d.err != nil {
+		return false
+	}
+	if len(d.buf) == 0 {
+		d.err = io.ErrUnexpectedEOF
+		return false
+	}
+	v := d.buf[0] == 1
+	d.buf = d.buf[1:]
+	return v
+}
+
+func (d *kvDecoder) text() string {
+	n := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if n > uint64(len(d.buf)) {
+		d.err = io.ErrUnexpectedEOF
+		return ""
+	}
+	v := string(d.buf[:n])
+	d.buf = d.buf[n:]
+	return v
+}
@@ -0,0 +1,310 @@
+package raft
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neogan74/konsul/internal/store"
+)
+
+// persistSnapshot takes a snapshot of fsm and returns it encoded.
+func persistSnapshot(t testing.TB, fsm *KonsulFSM) []byte {
+	t.Helper()
+
+	snapshot, err := fsm.Snapshot()
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Persist(&mockSnapshotSink{buf: &buf}))
+	return buf.Bytes()
+}
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	session := store.Session{Name: "worker", Behavior: store.SessionBehaviorRelease}
+	require.NoError(t, store.PrepareSession(&session))
+
+	for _, compression := range []string{"zstd", "gzip", "none"} {
+		t.Run(compression, func(t *testing.T) {
+			kvStore := store.NewKVStore()
+			sessions := store.NewSessionStore(kvStore)
+			services := newMockServiceStore()
+			fsm := NewFSM(FSMConfig{
+				KVStore:             kvStore,
+				ServiceStore:        services,
+				SessionStore:        sessions,
+				SnapshotCompression: compression,
+			})
+
+			// More keys than fit in one chunk
+			for i := 0; i < 2*snapshotChunkSize+10; i++ {
+				kvStore.SetLocal(fmt.Sprintf("app/key-%d", i), fmt.Sprintf("value-%d", i))
+			}
+			kvStore.SetWithExpiryLocal("app/ttl", "soon", 7, expiresAt)
+			kvStore.SetSealedSecretLocal("app/secret", "sealed", 0, time.Time{})
+			require.NoError(t, services.RegisterLocal(store.ServiceDataSnapshot{
+				ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80, Tags: []string{"v1"},
+			}))
+			_, err := sessions.Create(session)
+			require.NoError(t, err)
+			fsm.readReplicas["node-4"] = true
+			fsm.applied.record(42, nil)
+
+			data := persistSnapshot(t, fsm)
+			require.True(t, bytes.HasPrefix(data, []byte(snapshotMagic)))
+			want, _ := snapshotCompression(compression)
+			assert.Equal(t, want, data[len(snapshotMagic)+1])
+
+			restoredKV := store.NewKVStore()
+			restoredSessions := store.NewSessionStore(restoredKV)
+			restoredServices := newMockServiceStore()
+			restored := NewFSM(FSMConfig{
+				KVStore:      restoredKV,
+				ServiceStore: restoredServices,
+				SessionStore: restoredSessions,
+			})
+			restoredKV.SetLocal("stale", "gone")
+			require.NoError(t, restored.Restore(&mockReadCloser{buf: bytes.NewBuffer(data)}))
+
+			assert.ElementsMatch(t, kvStore.List(), restoredKV.List())
+			for _, key := range []string{"app/key-0", "app/key-2057", "app/ttl", "app/secret"} {
+				orig, ok := kvStore.GetEntry(key)
+				require.True(t, ok, key)
+				got, ok := restoredKV.GetEntry(key)
+				require.True(t, ok, key)
+				assert.Equal(t, orig.Value, got.Value, key)
+				assert.Equal(t, orig.ModifyIndex, got.ModifyIndex, key)
+				assert.Equal(t, orig.CreateIndex, got.CreateIndex, key)
+				assert.Equal(t, orig.Flags, got.Flags, key)
+				assert.Equal(t, orig.Secret, got.Secret, key)
+				assert.True(t, orig.ExpiresAt.Equal(got.ExpiresAt), key)
+			}
+			assert.Equal(t, kvStore.Index(), restoredKV.Index())
+
+			require.Len(t, restoredServices.data, 1)
+			assert.Equal(t, services.data["web-1"].Service, restoredServices.data["web-1"].Service)
+			assert.True(t, services.data["web-1"].ExpiresAt.Equal(restoredServices.data["web-1"].ExpiresAt))
+			_, ok := restoredSessions.Get(session.ID)
+			assert.True(t, ok)
+			assert.True(t, restored.IsReadReplica("node-4"))
+			assert.Equal(t, uint64(42), restored.applied.lastIndex())
+		})
+	}
+}
+
+func TestSnapshot_Corrupt(t *testing.T) {
+	kvStore := newMockKVStore()
+	for i := 0; i < 3*snapshotChunkSize; i++ {
+		kvStore.data[fmt.Sprintf("key-%d", i)] = store.KVEntrySnapshot{Value: "v", ModifyIndex: uint64(i + 1)}
+	}
+	fsm := NewFSM(FSMConfig{
+		KVStore:             kvStore,
+		ServiceStore:        newMockServiceStore(),
+		SnapshotCompression: "none",
+	})
+	data := persistSnapshot(t, fsm)
+
+	restore := func(data []byte) (*mockKVStore, error) {
+		restoredKV := newMockKVStore()
+		restoredKV.data["existing"] = store.KVEntrySnapshot{Value: "kept"}
+		restored := NewFSM(FSMConfig{KVStore: restoredKV, ServiceStore: newMockServiceStore()})
+		return restoredKV, restored.Restore(&mockReadCloser{buf: bytes.NewBuffer(data)})
+	}
+
+	// A snapshot cut in the middle of the KV records leaves the store as is
+	restoredKV, err := restore(data[:len(data)/2])
+	assert.ErrorIs(t, err, errSnapshotTruncated)
+	assert.Equal(t, map[string]store.KVEntrySnapshot{"existing": {Value: "kept"}}, restoredKV.data)
+
+	// A snapshot missing its end record is detected too
+	_, err = restore(data[:len(data)-2])
+	assert.ErrorIs(t, err, errSnapshotTruncated)
+
+	unknown := bytes.Clone(data)
+	unknown[len(snapshotMagic)] = snapshotVersion + 1
+	_, err = restore(unknown)
+	assert.ErrorContains(t, err, "unsupported snapshot version")
+
+	_, err = restore(data)
+	assert.NoError(t, err)
+}
+
+func TestSnapshot_PersistsStateAtSnapshot(t *testing.T) {
+	kvStore := store.NewKVStore()
+	fsm := NewFSM(FSMConfig{
+		KVStore:      kvStore,
+		ServiceStore: newMockServiceStore(),
+	})
+	for i := 0; i < 3*snapshotChunkSize; i++ {
+		kvStore.SetLocal(fmt.Sprintf("app/key-%d", i), "v1")
+	}
+	want := kvStore.GetAllData()
+
+	snapshot, err := fsm.Snapshot()
+	require.NoError(t, err)
+	defer snapshot.Release()
+
+	// Entries applied while the snapshot is persisted are not part of it
+	for i := 0; i < 3*snapshotChunkSize; i += 2 {
+		cmd, err := NewCommand(CmdKVSet, KVSetPayload{Key: fmt.Sprintf("app/key-%d", i), Value: "v2"})
+		require.NoError(t, err)
+		fsm.Apply(makeLog(t, cmd))
+	}
+	cmd, err := NewCommand(CmdKVDelete, KVDeletePayload{Key: "app/key-1"})
+	require.NoError(t, err)
+	fsm.Apply(makeLog(t, cmd))
+
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Persist(&mockSnapshotSink{buf: &buf}))
+
+	restoredKV := store.NewKVStore()
+	restored := NewFSM(FSMConfig{KVStore: restoredKV, ServiceStore: newMockServiceStore()})
+	require.NoError(t, restored.Restore(&mockReadCloser{buf: &buf}))
+	assert.Equal(t, want, restoredKV.GetAllData())
+}
+
+func TestSnapshot_TruncatedInSections(t *testing.T) {
+	session := store.Session{Name: "worker", Behavior: store.SessionBehaviorRelease}
+	require.NoError(t, store.PrepareSession(&session))
+
+	kvStore := store.NewKVStore()
+	sessions := store.NewSessionStore(kvStore)
+	services := newMockServiceStore()
+	fsm := NewFSM(FSMConfig{
+		KVStore:             kvStore,
+		ServiceStore:        services,
+		SessionStore:        sessions,
+		SnapshotCompression: "none",
+	})
+	kvStore.SetLocal("app/key", "value")
+	require.NoError(t, services.RegisterLocal(store.ServiceDataSnapshot{ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80}))
+	_, err := sessions.Create(session)
+	require.NoError(t, err)
+	fsm.readReplicas["node-4"] = true
+	data := persistSnapshot(t, fsm)
+
+	inSessions := bytes.Index(data, []byte(session.ID))
+	require.Positive(t, inSessions)
+	for name, cut := range map[string]int{
+		"sessions":      inSessions,
+		"read replicas": len(data) - 6,
+	} {
+		t.Run(name, func(t *testing.T) {
+			restoredKV := store.NewKVStore()
+			restoredServices := newMockServiceStore()
+			restored := NewFSM(FSMConfig{
+				KVStore:      restoredKV,
+				ServiceStore: restoredServices,
+				SessionStore: store.NewSessionStore(restoredKV),
+			})
+			restoredKV.SetLocal("existing", "kept")
+			restoredServices.data["db-1"] = store.ServiceEntrySnapshot{Service: store.ServiceDataSnapshot{ID: "db-1", Name: "db"}}
+
+			// The KV and service records were read in full, but none of the
+			// snapshot is restored without its end
+			err := restored.Restore(&mockReadCloser{buf: bytes.NewBuffer(data[:cut])})
+			assert.ErrorIs(t, err, errSnapshotTruncated)
+			assert.Equal(t, []string{"existing"}, restoredKV.List())
+			assert.Equal(t, []string{"db-1"}, slices.Collect(maps.Keys(restoredServices.data)))
+			assert.False(t, restored.IsReadReplica("node-4"))
+		})
+	}
+}
+
+// BenchmarkSnapshot_1MKeys compares persisting and restoring a snapshot of
+// one million keys in the JSON format snapshots used to be written in and
+// in the chunked format with each compression. open measures Snapshot, which
+// opens views on the stores instead of copying them.
+func BenchmarkSnapshot_1MKeys(b *testing.B) {
+	const keys = 1_000_000
+
+	kvStore := store.NewKVStore()
+	for i := 0; i < keys; i++ {
+		kvStore.SetLocal(fmt.Sprintf("config/service-%d/settings", i), fmt.Sprintf(`{"replicas":%d,"enabled":true}`, i%10))
+	}
+	newBenchFSM := func(kv KVStoreInterface, compression string) *KonsulFSM {
+		return NewFSM(FSMConfig{
+			KVStore:             kv,
+			ServiceStore:        newMockServiceStore(),
+			SnapshotCompression: compression,
+		})
+	}
+
+	persistJSON := func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(SnapshotData{
+			KVData:      kvStore.GetAllData(),
+			ServiceData: map[string]store.ServiceEntrySnapshot{},
+		})
+	}
+
+	b.Run("open", func(b *testing.B) {
+		fsm := newBenchFSM(kvStore, "zstd")
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			snapshot, err := fsm.Snapshot()
+			if err != nil {
+				b.Fatal(err)
+			}
+			snapshot.Release()
+		}
+	})
+
+	for _, format := range []string{"json", "none", "gzip", "zstd"} {
+		persist := func(w io.Writer) error {
+			if format == "json" {
+				return persistJSON(w)
+			}
+			snapshot, err := newBenchFSM(kvStore, format).Snapshot()
+			if err != nil {
+				return err
+			}
+			defer snapshot.Release()
+			return snapshot.(*KonsulSnapshot).write(w)
+		}
+
+		b.Run(format+"/persist", func(b *testing.B) {
+			b.ReportAllocs()
+			var size countingWriter
+			for i := 0; i < b.N; i++ {
+				size = 0
+				if err := persist(&size); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(size), "bytes/snapshot")
+		})
+
+		b.Run(format+"/restore", func(b *testing.B) {
+			var buf bytes.Buffer
+			if err := persist(&buf); err != nil {
+				b.Fatal(err)
+			}
+			data := buf.Bytes()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				fsm := newBenchFSM(store.NewKVStore(), format)
+				if err := fsm.Restore(io.NopCloser(bytes.NewReader(data))); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
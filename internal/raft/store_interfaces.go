@@ -1,6 +1,10 @@
 package raft
 
 import (
+	"time"
+
+	"github.com/neogan74/konsul/internal/acl"
+	"github.com/neogan74/konsul/internal/auth"
 	"github.com/neogan74/konsul/internal/store"
 )
 
@@ -34,14 +38,33 @@ type KVStoreInterface interface {
 	// BatchDeleteCASLocal performs atomic batch delete with CAS checks (without persistence)
 	BatchDeleteCASLocal(keys []string, expectedIndices map[string]uint64) error
 
+	// SetWithExpiryLocal stores a key that expires at expiresAt (without persistence)
+	SetWithExpiryLocal(key, value string, flags uint64, expiresAt time.Time)
+
+	// SetCASWithExpiryLocal performs Compare-And-Swap for an expiring key (without persistence)
+	SetCASWithExpiryLocal(key, value string, expectedIndex uint64, expiresAt time.Time) (uint64, error)
+
+	// BatchSetWithExpiryLocal sets multiple keys that expire at expiresAt (without persistence)
+	BatchSetWithExpiryLocal(items map[string]string, expiresAt time.Time) error
+
+	// SetSealedSecretLocal stores a sealed secret value (without persistence)
+	SetSealedSecretLocal(key, sealed string, flags uint64, expiresAt time.Time)
+
+	// SetSealedSecretCASLocal stores a sealed secret value with a CAS check (without persistence)
+	SetSealedSecretCASLocal(key, sealed string, expectedIndex uint64, expiresAt time.Time) (uint64, error)
+
+	// DeleteExpiredLocal deletes the given keys that are still expired at now (without persistence)
+	DeleteExpiredLocal(keys []string, now time.Time) []string
+
 	// GetEntrySnapshot returns a snapshot of the KVEntry with version information
 	GetEntrySnapshot(key string) (store.KVEntrySnapshot, bool)
 
-	// GetAllData returns all KV data for snapshotting
-	GetAllData() map[string]store.KVEntrySnapshot
+	// OpenSnapshot returns the KV records as they are now, read while the store keeps changing
+	OpenSnapshot() store.RecordReader[store.KVRecord]
 
-	// RestoreFromSnapshot restores KV data from a snapshot
-	RestoreFromSnapshot(data map[string]store.KVEntrySnapshot) error
+	// PrepareRestore decodes KV data from the chunks returned by next until io.EOF;
+	// the returned function puts it in use
+	PrepareRestore(next func() ([]store.KVRecord, error)) (func(), error)
 }
 
 // ServiceStoreInterface defines the interface for service store operations used by FSM.
@@ -52,24 +75,148 @@ type ServiceStoreInterface interface {
 	// RegisterCASLocal performs Compare-And-Swap registration (without persistence)
 	RegisterCASLocal(service store.ServiceDataSnapshot, expectedIndex uint64) (uint64, error)
 
-	// DeregisterLocal removes a service (without persistence)
-	DeregisterLocal(name string)
+	// DeregisterLocal removes a service instance (without persistence)
+	DeregisterLocal(id string)
 
 	// DeregisterCASLocal performs Compare-And-Swap deregistration (without persistence)
-	DeregisterCASLocal(name string, expectedIndex uint64) error
+	DeregisterCASLocal(id string, expectedIndex uint64) error
+
+	// HeartbeatLocal updates a service instance TTL (without persistence)
+	HeartbeatLocal(id string) bool
+
+	// ExpiredInstances returns the instances whose TTL has run out at now, with their ModifyIndex
+	ExpiredInstances(now time.Time) map[string]uint64
 
-	// HeartbeatLocal updates service TTL (without persistence)
-	HeartbeatLocal(name string) bool
+	// ExpireLocal removes the given instances still at their ModifyIndex (without persistence)
+	ExpireLocal(instances map[string]uint64) []string
+
+	// ExtendTTLLocal pushes the deadline of every instance to at least the TTL plus grace from now
+	ExtendTTLLocal(grace time.Duration)
 
 	// UpdateTTLCheck updates a TTL-based health check (without persistence)
 	UpdateTTLCheck(checkID string) error
 
 	// GetEntrySnapshot returns a snapshot of the ServiceEntry with version information
-	GetEntrySnapshot(name string) (store.ServiceEntrySnapshot, bool)
+	GetEntrySnapshot(id string) (store.ServiceEntrySnapshot, bool)
+
+	// OpenSnapshot returns the service records as they are now, read while the store keeps changing
+	OpenSnapshot() store.RecordReader[store.ServiceRecord]
+
+	// PrepareRestore decodes service data from the chunks returned by next until io.EOF;
+	// the returned function puts it in use
+	PrepareRestore(next func() ([]store.ServiceRecord, error)) (func(), error)
+}
+
+// SessionStoreInterface defines the interface for session and lock operations used by FSM.
+type SessionStoreInterface interface {
+	// CreateLocal stores a prepared session with the expiry it carries
+	CreateLocal(session store.Session) (store.Session, error)
+
+	// RenewLocal extends a session TTL to expiresAt
+	RenewLocal(id string, expiresAt time.Time) (store.Session, error)
+
+	// Get returns a session by ID
+	Get(id string) (store.Session, bool)
+
+	// DestroyLocal removes a session and releases its locks (without persistence)
+	DestroyLocal(id string) error
+
+	// AcquireLocal takes a lock on a key for a session (without persistence)
+	AcquireLocal(key, value, id string) (bool, error)
+
+	// ReleaseLocal drops a session's lock on a key (without persistence)
+	ReleaseLocal(key, id string) (bool, error)
+
+	// GetAllData returns all sessions for snapshotting
+	GetAllData() map[string]store.Session
+
+	// RestoreFromSnapshot restores sessions from a snapshot
+	RestoreFromSnapshot(data map[string]store.Session) error
+}
+
+// NamespaceStoreInterface defines the interface for namespace operations used by FSM.
+type NamespaceStoreInterface interface {
+	// Create registers a namespace
+	Create(ns store.Namespace) (store.Namespace, error)
+
+	// Delete removes an empty namespace
+	Delete(name string) error
+
+	// GetAllData returns all namespaces for snapshotting
+	GetAllData() map[string]store.Namespace
+
+	// RestoreFromSnapshot restores namespaces from a snapshot
+	RestoreFromSnapshot(data map[string]store.Namespace) error
+}
+
+// PolicyStoreInterface defines the interface for ACL policy operations used by FSM.
+type PolicyStoreInterface interface {
+	// AddPolicy creates a policy
+	AddPolicy(policy *acl.Policy) error
+
+	// UpdatePolicy replaces an existing policy
+	UpdatePolicy(policy *acl.Policy) error
+
+	// DeletePolicy removes a policy
+	DeletePolicy(name string) error
+
+	// GetAllPolicies returns all policies for snapshotting
+	GetAllPolicies() map[string]*acl.Policy
+
+	// RestorePolicies restores policies from a snapshot
+	RestorePolicies(policies map[string]*acl.Policy) error
+}
+
+// APIKeyStoreInterface defines the interface for API key operations used by FSM.
+type APIKeyStoreInterface interface {
+	// AddAPIKey stores a generated key
+	AddAPIKey(apiKey *auth.APIKey) error
+
+	// UpdateAPIKey updates the name, permissions, metadata and enabled flag of a key
+	UpdateAPIKey(keyID string, name string, permissions []string, metadata map[string]string, enabled *bool) error
+
+	// RevokeAPIKey disables a key
+	RevokeAPIKey(keyID string) error
+
+	// DeleteAPIKey removes a key
+	DeleteAPIKey(keyID string) error
+
+	// GetAllAPIKeys returns all keys for snapshotting
+	GetAllAPIKeys() map[string]auth.APIKey
+
+	// RestoreAPIKeys restores keys from a snapshot
+	RestoreAPIKeys(keys map[string]auth.APIKey) error
+}
+
+// UserStoreInterface defines the interface for user operations used by FSM.
+type UserStoreInterface interface {
+	// AddUser stores a user with a hashed password
+	AddUser(user *auth.User) error
+
+	// UpdateUser updates a user
+	UpdateUser(username string, update auth.UserUpdate) error
+
+	// DeleteUser removes a user
+	DeleteUser(username string) error
+
+	// GetAllUsers returns all users for snapshotting
+	GetAllUsers() map[string]auth.User
+
+	// RestoreUsers restores users from a snapshot
+	RestoreUsers(users map[string]auth.User) error
+}
+
+// PreparedQueryStoreInterface defines the interface for prepared query operations used by FSM.
+type PreparedQueryStoreInterface interface {
+	// Set creates or replaces a prepared query
+	Set(q store.PreparedQuery) (store.PreparedQuery, error)
+
+	// Delete removes a prepared query
+	Delete(name string) error
 
-	// GetAllData returns all service data for snapshotting
-	GetAllData() map[string]store.ServiceEntrySnapshot
+	// GetAllData returns all prepared queries for snapshotting
+	GetAllData() map[string]store.PreparedQuery
 
-	// RestoreFromSnapshot restores service data from a snapshot
-	RestoreFromSnapshot(data map[string]store.ServiceEntrySnapshot) error
+	// RestoreFromSnapshot restores prepared queries from a snapshot
+	RestoreFromSnapshot(data map[string]store.PreparedQuery) error
 }
@@ -15,6 +15,25 @@ import (
 // NewTransport creates a new Raft network transport.
 // If TLS is enabled in config, it uses secure communication.
 func NewTransport(cfg *Config, logger hclog.Logger) (*raft.NetworkTransport, error) {
+	stream, err := newStreamLayer(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	return newNetworkTransport(stream, logger), nil
+}
+
+// newNetworkTransport creates a Raft network transport over stream.
+func newNetworkTransport(stream raft.StreamLayer, logger hclog.Logger) *raft.NetworkTransport {
+	return raft.NewNetworkTransportWithConfig(&raft.NetworkTransportConfig{
+		Stream:  stream,
+		MaxPool: 3,
+		Timeout: 10 * time.Second,
+		Logger:  logger,
+	})
+}
+
+// newStreamLayer creates the TCP or TLS stream layer Raft communicates over.
+func newStreamLayer(cfg *Config, logger hclog.Logger) (raft.StreamLayer, error) {
 	// Resolve advertise address
 	advertiseAddrStr := cfg.GetAdvertiseAddr()
 	addr, err := net.ResolveTCPAddr("tcp", advertiseAddrStr)
@@ -23,14 +42,47 @@ func NewTransport(cfg *Config, logger hclog.Logger) (*raft.NetworkTransport, err
 	}
 
 	if !cfg.TLS.Enabled {
-		return raft.NewTCPTransport(cfg.BindAddr, addr, 3, 10*time.Second, os.Stderr)
+		return newTCPStreamLayer(cfg.BindAddr, addr)
 	}
 
 	// TLS Enabled
-	return newTLSTransport(cfg, addr, logger)
+	return newTLSStreamLayer(cfg, addr, logger)
 }
 
-func newTLSTransport(cfg *Config, advertiseAddr net.Addr, logger hclog.Logger) (*raft.NetworkTransport, error) {
+// tcpStreamLayer implements raft.StreamLayer over plain TCP
+type tcpStreamLayer struct {
+	net.Listener
+	advertise net.Addr
+}
+
+func newTCPStreamLayer(bindAddr string, advertise net.Addr) (*tcpStreamLayer, error) {
+	listener, err := net.Listen("tcp", bindAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", bindAddr, err)
+	}
+
+	stream := &tcpStreamLayer{Listener: listener, advertise: advertise}
+	if addr, ok := stream.Addr().(*net.TCPAddr); !ok || addr.IP == nil || addr.IP.IsUnspecified() {
+		_ = listener.Close()
+		return nil, fmt.Errorf("local bind address is not advertisable")
+	}
+	return stream, nil
+}
+
+// Dial implements raft.StreamLayer.Dial
+func (t *tcpStreamLayer) Dial(addr raft.ServerAddress, timeout time.Duration) (net.Conn, error) {
+	return net.DialTimeout("tcp", string(addr), timeout)
+}
+
+// Addr implements raft.StreamLayer.Addr
+func (t *tcpStreamLayer) Addr() net.Addr {
+	if t.advertise != nil {
+		return t.advertise
+	}
+	return t.Listener.Addr()
+}
+
+func newTLSStreamLayer(cfg *Config, advertiseAddr net.Addr, logger hclog.Logger) (raft.StreamLayer, error) {
 	// Load certificates
 	cert, err := tls.LoadX509KeyPair(cfg.TLS.CertFile, cfg.TLS.KeyFile)
 	if err != nil {
@@ -73,21 +125,11 @@ func newTLSTransport(cfg *Config, advertiseAddr net.Addr, logger hclog.Logger) (
 	tlsListener := tls.NewListener(listener, tlsConfig)
 
 	// Create a StreamLayer that uses TLS
-	streamLayer := &tlsStreamLayer{
+	return &tlsStreamLayer{
 		Listener:  tlsListener,
 		tlsConfig: tlsConfig,
 		advertise: advertiseAddr,
-	}
-
-	// Create NetworkTransport
-	transConfig := &raft.NetworkTransportConfig{
-		Stream:  streamLayer,
-		MaxPool: 3,
-		Timeout: 10 * time.Second,
-		Logger:  logger,
-	}
-
-	return raft.NewNetworkTransportWithConfig(transConfig), nil
+	}, nil
 }
 
 // tlsStreamLayer implements raft.StreamLayer interface
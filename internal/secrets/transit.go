@@ -0,0 +1,167 @@
+// Package secrets implements envelope encryption of secret KV values.
+//
+// Every value is encrypted with its own random data key, and the data key is
+// encrypted ("wrapped") with the transit key. Only the sealed form is stored,
+// replicated and persisted; the transit key itself never leaves the node
+// configuration.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// sealedPrefix starts every sealed value, followed by the transit key ID, the
+// wrapped data key and the encrypted value, separated by colons.
+const sealedPrefix = "konsul:v1:"
+
+const dataKeySize = 32
+
+var (
+	// ErrMalformed is returned for values that are not sealed secrets.
+	ErrMalformed = errors.New("malformed sealed secret")
+	// ErrUnknownKey is returned for secrets sealed with a transit key that is
+	// not configured.
+	ErrUnknownKey = errors.New("secret is sealed with an unknown transit key")
+	// ErrDecrypt is returned when a secret fails to decrypt, for example
+	// because it was sealed for another key name or was modified.
+	ErrDecrypt = errors.New("failed to decrypt secret")
+)
+
+var encoding = base64.RawURLEncoding
+
+// Transit seals and opens secret values. It seals with its primary key and
+// opens values sealed with the primary or any previous key, so that the
+// transit key can be rotated without re-encrypting stored secrets first.
+type Transit struct {
+	primaryID string
+	keys      map[string]cipher.AEAD // key ID -> cipher
+}
+
+// NewTransit creates a transit from an AES key of 16, 24 or 32 bytes and
+// optional previous keys.
+func NewTransit(key []byte, previous ...[]byte) (*Transit, error) {
+	t := &Transit{keys: make(map[string]cipher.AEAD, 1+len(previous))}
+	for i, k := range append([][]byte{key}, previous...) {
+		aead, err := newAEAD(k)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transit key: %w", err)
+		}
+		id := KeyID(k)
+		if i == 0 {
+			t.primaryID = id
+		}
+		t.keys[id] = aead
+	}
+	return t, nil
+}
+
+// KeyID returns the ID recorded in secrets sealed with key: a prefix of its
+// SHA-256 hash, which tells keys apart without revealing them.
+func KeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:4])
+}
+
+// IsSealed reports whether value looks like a sealed secret.
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix)
+}
+
+// Seal encrypts plaintext for the key name name. The name is authenticated,
+// so a sealed value copied to another key fails to open.
+func (t *Transit) Seal(name, plaintext string) (string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	wrapped, err := seal(t.keys[t.primaryID], dataKey, []byte(t.primaryID))
+	if err != nil {
+		return "", err
+	}
+	valueAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(valueAEAD, []byte(plaintext), []byte(name))
+	if err != nil {
+		return "", err
+	}
+
+	return sealedPrefix + t.primaryID + ":" + encoding.EncodeToString(wrapped) + ":" + encoding.EncodeToString(ciphertext), nil
+}
+
+// Open decrypts a value sealed for the key name name.
+func (t *Transit) Open(name, sealed string) (string, error) {
+	if !IsSealed(sealed) {
+		return "", ErrMalformed
+	}
+	parts := strings.Split(strings.TrimPrefix(sealed, sealedPrefix), ":")
+	if len(parts) != 3 {
+		return "", ErrMalformed
+	}
+	keyAEAD, ok := t.keys[parts[0]]
+	if !ok {
+		return "", ErrUnknownKey
+	}
+	wrapped, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	ciphertext, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	dataKey, err := open(keyAEAD, wrapped, []byte(parts[0]))
+	if err != nil {
+		return "", err
+	}
+	valueAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	plaintext, err := open(valueAEAD, ciphertext, []byte(name))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext under a random nonce, which it prepends.
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open decrypts data produced by seal.
+func open(aead cipher.AEAD, data, additionalData []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
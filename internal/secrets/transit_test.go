@@ -0,0 +1,95 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func TestTransit_SealOpen(t *testing.T) {
+	transit, err := NewTransit(testKey(1))
+	if err != nil {
+		t.Fatalf("NewTransit() error = %v", err)
+	}
+
+	sealed, err := transit.Seal("db/password", "hunter2")
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if !IsSealed(sealed) {
+		t.Errorf("Expected sealed value, got %q", sealed)
+	}
+	if strings.Contains(sealed, "hunter2") {
+		t.Errorf("Sealed value contains the plaintext: %q", sealed)
+	}
+
+	again, _ := transit.Seal("db/password", "hunter2")
+	if again == sealed {
+		t.Error("Expected a fresh data key and nonce for every seal")
+	}
+
+	plaintext, err := transit.Open("db/password", sealed)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if plaintext != "hunter2" {
+		t.Errorf("Expected hunter2, got %q", plaintext)
+	}
+}
+
+func TestTransit_OpenRejects(t *testing.T) {
+	transit, _ := NewTransit(testKey(1))
+	sealed, _ := transit.Seal("db/password", "hunter2")
+
+	// Sealed values are bound to their key name
+	if _, err := transit.Open("other/key", sealed); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Expected ErrDecrypt for another key name, got %v", err)
+	}
+
+	other, _ := NewTransit(testKey(2))
+	if _, err := other.Open("db/password", sealed); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected ErrUnknownKey, got %v", err)
+	}
+
+	tampered := sealed[:len(sealed)-2] + "AA"
+	if tampered == sealed {
+		tampered = sealed[:len(sealed)-2] + "BB"
+	}
+	if _, err := transit.Open("db/password", tampered); err == nil {
+		t.Error("Expected error for tampered value")
+	}
+
+	if _, err := transit.Open("db/password", "hunter2"); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Expected ErrMalformed, got %v", err)
+	}
+}
+
+func TestTransit_Rotation(t *testing.T) {
+	oldTransit, _ := NewTransit(testKey(1))
+	sealed, _ := oldTransit.Seal("db/password", "hunter2")
+
+	rotated, err := NewTransit(testKey(2), testKey(1))
+	if err != nil {
+		t.Fatalf("NewTransit() error = %v", err)
+	}
+	plaintext, err := rotated.Open("db/password", sealed)
+	if err != nil || plaintext != "hunter2" {
+		t.Fatalf("Expected old secret to open after rotation, got %q (%v)", plaintext, err)
+	}
+
+	resealed, _ := rotated.Seal("db/password", plaintext)
+	if !strings.HasPrefix(resealed, sealedPrefix+KeyID(testKey(2))+":") {
+		t.Errorf("Expected new secrets to be sealed with the primary key, got %q", resealed)
+	}
+}
+
+func TestNewTransit_InvalidKey(t *testing.T) {
+	if _, err := NewTransit([]byte("short")); err == nil {
+		t.Error("Expected error for invalid key length")
+	}
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/neogan74/konsul/internal/healthcheck"
+	"github.com/neogan74/konsul/internal/watch"
+)
+
+// Index returns the current KV index. It advances on every write, including
+// deletes, so blocking queries wake up for any change to the store.
+func (kv *KVStore) Index() uint64 {
+	return atomic.LoadUint64(&kv.globalIndex)
+}
+
+// WaitForIndex blocks until the KV index differs from index, wait elapses or
+// ctx is done, and returns the current index.
+func (kv *KVStore) WaitForIndex(ctx context.Context, index uint64, wait time.Duration) uint64 {
+	return kv.changes.Wait(ctx, index, wait, kv.Index)
+}
+
+// deleteEntry removes key, advancing the index if it existed, and returns
+// the index of the deletion or 0. Callers must hold kv.Mutex.
+func (kv *KVStore) deleteEntry(key string) uint64 {
+	if _, ok := kv.Data[key]; !ok {
+		return 0
+	}
+	for _, view := range kv.snapshots {
+		view.save(kv.Data, key)
+	}
+	delete(kv.Data, key)
+	return kv.nextIndex(key)
+}
+
+// resetIndex sets the index after a load or restore and wakes blocking queries.
+func (kv *KVStore) resetIndex(index uint64) {
+	atomic.StoreUint64(&kv.globalIndex, index)
+	kv.changeLog.Reset(index)
+	kv.changes.Notify()
+	if kv.watchManager != nil {
+		kv.watchManager.ResetJournal(watch.KindKV, index)
+	}
+}
+
+// Index returns the current service index. It advances when an instance is
+// registered, updated, deregistered or expires, and when one of its checks
+// changes status; heartbeats leave it alone.
+func (s *ServiceStore) Index() uint64 {
+	return atomic.LoadUint64(&s.globalIndex)
+}
+
+// WaitForIndex blocks until the service index differs from index, wait
+// elapses or ctx is done, and returns the current index.
+func (s *ServiceStore) WaitForIndex(ctx context.Context, index uint64, wait time.Duration) uint64 {
+	return s.changes.Wait(ctx, index, wait, s.Index)
+}
+
+// HealthIndex returns the current health check index. It advances when a check
+// is added or removed or its status changes.
+func (s *ServiceStore) HealthIndex() uint64 {
+	return s.health().Index()
+}
+
+// WaitForHealthIndex blocks until the health check index differs from index,
+// wait elapses or ctx is done, and returns the current index.
+func (s *ServiceStore) WaitForHealthIndex(ctx context.Context, index uint64, wait time.Duration) uint64 {
+	return s.health().WaitForIndex(ctx, index, wait)
+}
+
+// health returns the current health check manager, which is replaced when a
+// snapshot is restored.
+func (s *ServiceStore) health() *healthcheck.Manager {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+	return s.healthManager
+}
+
+// deleteEntry removes an instance, advancing the index if it existed, and
+// returns the index of the deletion or 0. Callers must hold s.Mutex and
+// remove the instance from the indexes.
+func (s *ServiceStore) deleteEntry(id string) uint64 {
+	if _, ok := s.Data[id]; !ok {
+		return 0
+	}
+	for _, view := range s.snapshots {
+		view.save(s.Data, id)
+	}
+	delete(s.Data, id)
+	return s.nextIndex(id)
+}
+
+// resetIndex sets the index after a load or restore and wakes blocking queries.
+func (s *ServiceStore) resetIndex(index uint64) {
+	atomic.StoreUint64(&s.globalIndex, index)
+	s.changeLog.Reset(index)
+	s.changes.Notify()
+	if s.watchManager != nil {
+		s.watchManager.ResetJournal(watch.KindService, index)
+	}
+}
+
+// KVChange is a key changed since a given index. Entry is nil when the key
+// was deleted or is past its expiry.
+type KVChange struct {
+	Key   string
+	Entry *KVEntry
+}
+
+// ChangesSince returns the keys changed after index with their current
+// entries, and the index they bring a reader up to. ok is false when the
+// change log no longer covers index; the reader then has to load all keys.
+func (kv *KVStore) ChangesSince(index uint64) (changes []KVChange, last uint64, ok bool) {
+	keys, last, ok := kv.changeLog.Since(index)
+	if !ok {
+		return nil, last, false
+	}
+
+	kv.Mutex.RLock()
+	defer kv.Mutex.RUnlock()
+
+	now := time.Now()
+	changes = make([]KVChange, 0, len(keys))
+	for _, key := range keys {
+		change := KVChange{Key: key}
+		if entry, exists := kv.Data[key]; exists && !entry.Expired(now) {
+			change.Entry = &entry
+		}
+		changes = append(changes, change)
+	}
+	return changes, last, true
+}
+
+// ServiceChange is a service instance changed since a given index. Entry is
+// nil when the instance was deregistered or expired.
+type ServiceChange struct {
+	ID    string
+	Entry *ServiceEntry
+}
+
+// ChangesSince returns the instances changed after index with their current
+// entries, and the index they bring a reader up to. ok is false when the
+// change log no longer covers index; the reader then has to load all
+// instances.
+func (s *ServiceStore) ChangesSince(index uint64) (changes []ServiceChange, last uint64, ok bool) {
+	ids, last, ok := s.changeLog.Since(index)
+	if !ok {
+		return nil, last, false
+	}
+
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	changes = make([]ServiceChange, 0, len(ids))
+	for _, id := range ids {
+		change := ServiceChange{ID: id}
+		if entry, exists := s.Data[id]; exists {
+			change.Entry = &entry
+		}
+		changes = append(changes, change)
+	}
+	return changes, last, true
+}
+
+// HealthChangesSince returns the health checks changed after index, the IDs
+// of the checks removed since, and the index they bring a reader up to; see
+// healthcheck.Manager.ChangesSince.
+func (s *ServiceStore) HealthChangesSince(index uint64) (changed []healthcheck.Check, removed []string, last uint64, ok bool) {
+	return s.health().ChangesSince(index)
+}
@@ -49,3 +49,20 @@ type IndexMismatchError struct {
 func (e *IndexMismatchError) Error() string {
 	return e.Message
 }
+
+// NamespaceConflictError is returned when a namespace cannot be created
+// because it already exists, or cannot be deleted because it is in use
+type NamespaceConflictError struct {
+	Name   string
+	Reason string
+}
+
+func (e *NamespaceConflictError) Error() string {
+	return fmt.Sprintf("namespace '%s' %s", e.Name, e.Reason)
+}
+
+// IsNamespaceConflict checks if an error is a namespace conflict error
+func IsNamespaceConflict(err error) bool {
+	_, ok := err.(*NamespaceConflictError)
+	return ok
+}
@@ -3,21 +3,30 @@ package store
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/neogan74/konsul/internal/blocking"
+	"github.com/neogan74/konsul/internal/changelog"
 	"github.com/neogan74/konsul/internal/logger"
 	"github.com/neogan74/konsul/internal/persistence"
+	"github.com/neogan74/konsul/internal/secrets"
 	"github.com/neogan74/konsul/internal/watch"
 )
 
 // KVEntry represents a key-value entry with version tracking for CAS operations
 type KVEntry struct {
-	Value       string `json:"value"`
-	ModifyIndex uint64 `json:"modify_index"`
-	CreateIndex uint64 `json:"create_index"`
-	Flags       uint64 `json:"flags,omitempty"`
+	Value       string    `json:"value"`
+	ModifyIndex uint64    `json:"modify_index"`
+	CreateIndex uint64    `json:"create_index"`
+	Flags       uint64    `json:"flags,omitempty"`
+	Session     string    `json:"session,omitempty"`    // ID of the session holding the lock, if any
+	LockIndex   uint64    `json:"lock_index,omitempty"` // Number of times the lock has been acquired
+	ExpiresAt   time.Time `json:"expires_at,omitzero"`  // When the key is deleted; zero if it never expires
+	Secret      bool      `json:"secret,omitempty"`     // Value is sealed with the transit key
 }
 
 // KVStore represents the key-value store
@@ -28,6 +37,11 @@ type KVStore struct {
 	engine       persistence.Engine
 	log          logger.Logger
 	watchManager *watch.Manager
+	changes      blocking.Notifier // Wakes blocking queries when the index changes
+	changeLog    *changelog.Log    // Keys changed at recent indexes, for delta syncs
+	transit      *secrets.Transit  // Seals secret values; nil disables secrets
+
+	snapshots []*snapshotView[KVEntry, KVRecord] // Open Raft snapshots
 }
 
 // NewKVStore creates a KV store with optional persistence
@@ -36,6 +50,7 @@ func NewKVStore() *KVStore {
 		Data:        make(map[string]KVEntry),
 		globalIndex: 0,
 		log:         logger.GetDefault(),
+		changeLog:   changelog.New(changelog.DefaultCapacity),
 	}
 }
 
@@ -46,6 +61,7 @@ func NewKVStoreWithPersistence(engine persistence.Engine, log logger.Logger) (*K
 		globalIndex: 0,
 		engine:      engine,
 		log:         log,
+		changeLog:   changelog.New(changelog.DefaultCapacity),
 	}
 
 	// Load existing data from persistence if available
@@ -75,6 +91,7 @@ func (kv *KVStore) loadFromPersistence() error {
 	}
 
 	var maxIndex uint64
+	now := time.Now()
 	for _, key := range keys {
 		value, err := kv.engine.Get(key)
 		if err != nil {
@@ -93,14 +110,17 @@ func (kv *KVStore) loadFromPersistence() error {
 				CreateIndex: 1,
 			}
 		}
-		kv.Data[key] = entry
+		if entry.Expired(now) {
+			continue
+		}
+		kv.putEntry(key, entry)
 		if entry.ModifyIndex > maxIndex {
 			maxIndex = entry.ModifyIndex
 		}
 	}
 
 	// Set global index to max found index
-	kv.globalIndex = maxIndex
+	kv.resetIndex(maxIndex)
 
 	kv.log.Info("Loaded KV data from persistence",
 		logger.Int("keys", len(keys)),
@@ -108,28 +128,38 @@ func (kv *KVStore) loadFromPersistence() error {
 	return nil
 }
 
-// Get retrieves a value by key
+// Get retrieves a value by key. The value of a secret is redacted, see Reveal.
+// Keys past their expiry are not returned, whether or not they have been
+// deleted yet.
 func (kv *KVStore) Get(key string) (string, bool) {
 	kv.Mutex.RLock()
 	defer kv.Mutex.RUnlock()
 	entry, ok := kv.Data[key]
-	if !ok {
+	if !ok || entry.Expired(time.Now()) {
 		return "", false
 	}
-	return entry.Value, true
+	return entry.Redacted().Value, true
 }
 
-// GetEntry returns the full KVEntry with version information
+// GetEntry returns the full KVEntry with version information, unless it is
+// past its expiry.
 func (kv *KVStore) GetEntry(key string) (KVEntry, bool) {
 	kv.Mutex.RLock()
 	defer kv.Mutex.RUnlock()
 	entry, ok := kv.Data[key]
-	return entry, ok
+	if !ok || entry.Expired(time.Now()) {
+		return KVEntry{}, false
+	}
+	return entry, true
 }
 
-// nextIndex atomically increments and returns the next global index
-func (kv *KVStore) nextIndex() uint64 {
-	return atomic.AddUint64(&kv.globalIndex, 1)
+// nextIndex atomically increments and returns the next global index, recording
+// it as a change of key. Callers must hold kv.Mutex.
+func (kv *KVStore) nextIndex(key string) uint64 {
+	index := atomic.AddUint64(&kv.globalIndex, 1)
+	kv.changeLog.Record(index, key)
+	kv.changes.Notify()
+	return index
 }
 
 // Set sets a value with a new index
@@ -137,7 +167,7 @@ func (kv *KVStore) Set(key, value string) {
 	kv.Mutex.Lock()
 	oldEntry, existed := kv.Data[key]
 
-	newIndex := kv.nextIndex()
+	newIndex := kv.nextIndex(key)
 	entry := KVEntry{
 		Value:       value,
 		ModifyIndex: newIndex,
@@ -145,10 +175,12 @@ func (kv *KVStore) Set(key, value string) {
 	if existed {
 		entry.CreateIndex = oldEntry.CreateIndex
 		entry.Flags = oldEntry.Flags
+		entry.Session = oldEntry.Session
+		entry.LockIndex = oldEntry.LockIndex
 	} else {
 		entry.CreateIndex = newIndex
 	}
-	kv.Data[key] = entry
+	kv.putEntry(key, entry)
 	kv.Mutex.Unlock()
 
 	// Persist to storage if engine is available
@@ -170,13 +202,14 @@ func (kv *KVStore) Set(key, value string) {
 		event := watch.Event{
 			Type:      watch.EventTypeSet,
 			Key:       key,
+			Index:     newIndex,
 			Value:     value,
 			Timestamp: time.Now().Unix(),
 		}
 		if existed {
 			event.OldValue = oldEntry.Value
 		}
-		kv.watchManager.Notify(event)
+		kv.publish(event)
 	}
 }
 
@@ -216,7 +249,7 @@ func (kv *KVStore) SetCAS(key, value string, expectedIndex uint64) (uint64, erro
 		}
 	}
 
-	newIndex := kv.nextIndex()
+	newIndex := kv.nextIndex(key)
 	entry := KVEntry{
 		Value:       value,
 		ModifyIndex: newIndex,
@@ -224,10 +257,12 @@ func (kv *KVStore) SetCAS(key, value string, expectedIndex uint64) (uint64, erro
 	if existed {
 		entry.CreateIndex = oldEntry.CreateIndex
 		entry.Flags = oldEntry.Flags
+		entry.Session = oldEntry.Session
+		entry.LockIndex = oldEntry.LockIndex
 	} else {
 		entry.CreateIndex = newIndex
 	}
-	kv.Data[key] = entry
+	kv.putEntry(key, entry)
 
 	// Persist to storage if engine is available
 	if kv.engine != nil {
@@ -251,13 +286,14 @@ func (kv *KVStore) SetCAS(key, value string, expectedIndex uint64) (uint64, erro
 		event := watch.Event{
 			Type:      watch.EventTypeSet,
 			Key:       key,
+			Index:     newIndex,
 			Value:     value,
 			Timestamp: time.Now().Unix(),
 		}
 		if existed {
 			event.OldValue = oldEntry.Value
 		}
-		kv.watchManager.Notify(event)
+		kv.publish(event)
 	}
 
 	return newIndex, nil
@@ -268,7 +304,7 @@ func (kv *KVStore) SetWithFlags(key, value string, flags uint64) {
 	kv.Mutex.Lock()
 	oldEntry, existed := kv.Data[key]
 
-	newIndex := kv.nextIndex()
+	newIndex := kv.nextIndex(key)
 	entry := KVEntry{
 		Value:       value,
 		ModifyIndex: newIndex,
@@ -276,10 +312,12 @@ func (kv *KVStore) SetWithFlags(key, value string, flags uint64) {
 	}
 	if existed {
 		entry.CreateIndex = oldEntry.CreateIndex
+		entry.Session = oldEntry.Session
+		entry.LockIndex = oldEntry.LockIndex
 	} else {
 		entry.CreateIndex = newIndex
 	}
-	kv.Data[key] = entry
+	kv.putEntry(key, entry)
 	kv.Mutex.Unlock()
 
 	// Persist to storage if engine is available
@@ -301,13 +339,14 @@ func (kv *KVStore) SetWithFlags(key, value string, flags uint64) {
 		event := watch.Event{
 			Type:      watch.EventTypeSet,
 			Key:       key,
+			Index:     newIndex,
 			Value:     value,
 			Timestamp: time.Now().Unix(),
 		}
 		if existed {
 			event.OldValue = oldEntry.Value
 		}
-		kv.watchManager.Notify(event)
+		kv.publish(event)
 	}
 }
 
@@ -315,7 +354,7 @@ func (kv *KVStore) SetWithFlags(key, value string, flags uint64) {
 func (kv *KVStore) Delete(key string) {
 	kv.Mutex.Lock()
 	oldEntry, existed := kv.Data[key]
-	delete(kv.Data, key)
+	index := kv.deleteEntry(key)
 	kv.Mutex.Unlock()
 
 	// Delete from persistence if engine is available
@@ -332,10 +371,11 @@ func (kv *KVStore) Delete(key string) {
 		event := watch.Event{
 			Type:      watch.EventTypeDelete,
 			Key:       key,
+			Index:     index,
 			OldValue:  oldEntry.Value,
 			Timestamp: time.Now().Unix(),
 		}
-		kv.watchManager.Notify(event)
+		kv.publish(event)
 	}
 }
 
@@ -360,7 +400,7 @@ func (kv *KVStore) DeleteCAS(key string, expectedIndex uint64) error {
 		}
 	}
 
-	delete(kv.Data, key)
+	index := kv.deleteEntry(key)
 
 	// Delete from persistence if engine is available
 	if kv.engine != nil {
@@ -377,33 +417,60 @@ func (kv *KVStore) DeleteCAS(key string, expectedIndex uint64) error {
 		event := watch.Event{
 			Type:      watch.EventTypeDelete,
 			Key:       key,
+			Index:     index,
 			OldValue:  oldEntry.Value,
 			Timestamp: time.Now().Unix(),
 		}
-		kv.watchManager.Notify(event)
+		kv.publish(event)
 	}
 
 	return nil
 }
 
-// List returns all keys in the store
+// List returns all keys in the store that are not past their expiry
 func (kv *KVStore) List() []string {
 	kv.Mutex.RLock()
 	defer kv.Mutex.RUnlock()
+	now := time.Now()
 	keys := make([]string, 0, len(kv.Data))
-	for key := range kv.Data {
-		keys = append(keys, key)
+	for key, entry := range kv.Data {
+		if !entry.Expired(now) {
+			keys = append(keys, key)
+		}
 	}
 	return keys
 }
 
-// ListEntries returns all key-value entries with their metadata
+// ListNamespace returns the keys of namespace ns, without the namespace prefix
+func (kv *KVStore) ListNamespace(ns string) []string {
+	kv.Mutex.RLock()
+	defer kv.Mutex.RUnlock()
+
+	ns = NormalizeNamespace(ns)
+	now := time.Now()
+	keys := make([]string, 0)
+	for storageKey, entry := range kv.Data {
+		if entry.Expired(now) {
+			continue
+		}
+		if keyNS, key := SplitNamespacedKey(storageKey); keyNS == ns {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// ListEntries returns all key-value entries with their metadata, leaving out
+// those past their expiry
 func (kv *KVStore) ListEntries() map[string]KVEntry {
 	kv.Mutex.RLock()
 	defer kv.Mutex.RUnlock()
+	now := time.Now()
 	result := make(map[string]KVEntry, len(kv.Data))
 	for key, entry := range kv.Data {
-		result[key] = entry
+		if !entry.Expired(now) {
+			result[key] = entry
+		}
 	}
 	return result
 }
@@ -417,9 +484,10 @@ func (kv *KVStore) BatchGet(keys []string) (found map[string]string, notFound []
 	found = make(map[string]string)
 	notFound = make([]string, 0)
 
+	now := time.Now()
 	for _, key := range keys {
-		if entry, ok := kv.Data[key]; ok {
-			found[key] = entry.Value
+		if entry, ok := kv.Data[key]; ok && !entry.Expired(now) {
+			found[key] = entry.Redacted().Value
 		} else {
 			notFound = append(notFound, key)
 		}
@@ -436,8 +504,9 @@ func (kv *KVStore) BatchGetEntries(keys []string) (found map[string]KVEntry, not
 	found = make(map[string]KVEntry)
 	notFound = make([]string, 0)
 
+	now := time.Now()
 	for _, key := range keys {
-		if entry, ok := kv.Data[key]; ok {
+		if entry, ok := kv.Data[key]; ok && !entry.Expired(now) {
 			found[key] = entry
 		} else {
 			notFound = append(notFound, key)
@@ -453,6 +522,7 @@ func (kv *KVStore) BatchSet(items map[string]string) error {
 
 	// Track old values for watch events
 	oldEntries := make(map[string]KVEntry)
+	indexes := make(map[string]uint64)
 	newEntries := make(map[string]KVEntry)
 
 	for key, value := range items {
@@ -461,7 +531,7 @@ func (kv *KVStore) BatchSet(items map[string]string) error {
 			oldEntries[key] = oldEntry
 		}
 
-		newIndex := kv.nextIndex()
+		newIndex := kv.nextIndex(key)
 		entry := KVEntry{
 			Value:       value,
 			ModifyIndex: newIndex,
@@ -469,10 +539,13 @@ func (kv *KVStore) BatchSet(items map[string]string) error {
 		if existed {
 			entry.CreateIndex = oldEntry.CreateIndex
 			entry.Flags = oldEntry.Flags
+			entry.Session = oldEntry.Session
+			entry.LockIndex = oldEntry.LockIndex
 		} else {
 			entry.CreateIndex = newIndex
 		}
-		kv.Data[key] = entry
+		kv.putEntry(key, entry)
+		indexes[key] = newIndex
 		newEntries[key] = entry
 	}
 	kv.Mutex.Unlock()
@@ -499,13 +572,14 @@ func (kv *KVStore) BatchSet(items map[string]string) error {
 			event := watch.Event{
 				Type:      watch.EventTypeSet,
 				Key:       key,
+				Index:     indexes[key],
 				Value:     value,
 				Timestamp: timestamp,
 			}
 			if oldEntry, existed := oldEntries[key]; existed {
 				event.OldValue = oldEntry.Value
 			}
-			kv.watchManager.Notify(event)
+			kv.publish(event)
 		}
 	}
 
@@ -559,7 +633,7 @@ func (kv *KVStore) BatchSetCAS(items map[string]string, expectedIndices map[stri
 			oldEntries[key] = oldEntry
 		}
 
-		newIndex := kv.nextIndex()
+		newIndex := kv.nextIndex(key)
 		entry := KVEntry{
 			Value:       value,
 			ModifyIndex: newIndex,
@@ -567,10 +641,12 @@ func (kv *KVStore) BatchSetCAS(items map[string]string, expectedIndices map[stri
 		if existed {
 			entry.CreateIndex = oldEntry.CreateIndex
 			entry.Flags = oldEntry.Flags
+			entry.Session = oldEntry.Session
+			entry.LockIndex = oldEntry.LockIndex
 		} else {
 			entry.CreateIndex = newIndex
 		}
-		kv.Data[key] = entry
+		kv.putEntry(key, entry)
 		newEntries[key] = entry
 		newIndices[key] = newIndex
 	}
@@ -597,13 +673,14 @@ func (kv *KVStore) BatchSetCAS(items map[string]string, expectedIndices map[stri
 			event := watch.Event{
 				Type:      watch.EventTypeSet,
 				Key:       key,
+				Index:     newIndices[key],
 				Value:     value,
 				Timestamp: timestamp,
 			}
 			if oldEntry, existed := oldEntries[key]; existed {
 				event.OldValue = oldEntry.Value
 			}
-			kv.watchManager.Notify(event)
+			kv.publish(event)
 		}
 	}
 
@@ -616,11 +693,12 @@ func (kv *KVStore) BatchDelete(keys []string) error {
 
 	// Track old values for watch events
 	oldEntries := make(map[string]KVEntry)
+	indexes := make(map[string]uint64)
 	for _, key := range keys {
 		if oldEntry, existed := kv.Data[key]; existed {
 			oldEntries[key] = oldEntry
 		}
-		delete(kv.Data, key)
+		indexes[key] = kv.deleteEntry(key)
 	}
 	kv.Mutex.Unlock()
 
@@ -638,10 +716,11 @@ func (kv *KVStore) BatchDelete(keys []string) error {
 			event := watch.Event{
 				Type:      watch.EventTypeDelete,
 				Key:       key,
+				Index:     indexes[key],
 				OldValue:  oldEntry.Value,
 				Timestamp: timestamp,
 			}
-			kv.watchManager.Notify(event)
+			kv.publish(event)
 		}
 	}
 
@@ -675,9 +754,10 @@ func (kv *KVStore) BatchDeleteCAS(keys []string, expectedIndices map[string]uint
 
 	// Second pass: delete all keys
 	oldEntries := make(map[string]KVEntry)
+	indexes := make(map[string]uint64)
 	for _, key := range keys {
 		oldEntries[key] = kv.Data[key]
-		delete(kv.Data, key)
+		indexes[key] = kv.deleteEntry(key)
 	}
 
 	// Delete from persistence if engine is available
@@ -694,10 +774,11 @@ func (kv *KVStore) BatchDeleteCAS(keys []string, expectedIndices map[string]uint
 			event := watch.Event{
 				Type:      watch.EventTypeDelete,
 				Key:       key,
+				Index:     indexes[key],
 				OldValue:  oldEntry.Value,
 				Timestamp: timestamp,
 			}
-			kv.watchManager.Notify(event)
+			kv.publish(event)
 		}
 	}
 
@@ -724,7 +805,7 @@ func (kv *KVStore) SetLocal(key, value string) {
 	kv.Mutex.Lock()
 	oldEntry, existed := kv.Data[key]
 
-	newIndex := kv.nextIndex()
+	newIndex := kv.nextIndex(key)
 	entry := KVEntry{
 		Value:       value,
 		ModifyIndex: newIndex,
@@ -732,10 +813,12 @@ func (kv *KVStore) SetLocal(key, value string) {
 	if existed {
 		entry.CreateIndex = oldEntry.CreateIndex
 		entry.Flags = oldEntry.Flags
+		entry.Session = oldEntry.Session
+		entry.LockIndex = oldEntry.LockIndex
 	} else {
 		entry.CreateIndex = newIndex
 	}
-	kv.Data[key] = entry
+	kv.putEntry(key, entry)
 	kv.Mutex.Unlock()
 
 	// Notify watchers (watchers still work in Raft mode)
@@ -743,13 +826,14 @@ func (kv *KVStore) SetLocal(key, value string) {
 		event := watch.Event{
 			Type:      watch.EventTypeSet,
 			Key:       key,
+			Index:     newIndex,
 			Value:     value,
 			Timestamp: time.Now().Unix(),
 		}
 		if existed {
 			event.OldValue = oldEntry.Value
 		}
-		kv.watchManager.Notify(event)
+		kv.publish(event)
 	}
 }
 
@@ -759,7 +843,7 @@ func (kv *KVStore) SetWithFlagsLocal(key, value string, flags uint64) {
 	kv.Mutex.Lock()
 	oldEntry, existed := kv.Data[key]
 
-	newIndex := kv.nextIndex()
+	newIndex := kv.nextIndex(key)
 	entry := KVEntry{
 		Value:       value,
 		ModifyIndex: newIndex,
@@ -767,10 +851,12 @@ func (kv *KVStore) SetWithFlagsLocal(key, value string, flags uint64) {
 	}
 	if existed {
 		entry.CreateIndex = oldEntry.CreateIndex
+		entry.Session = oldEntry.Session
+		entry.LockIndex = oldEntry.LockIndex
 	} else {
 		entry.CreateIndex = newIndex
 	}
-	kv.Data[key] = entry
+	kv.putEntry(key, entry)
 	kv.Mutex.Unlock()
 
 	// Notify watchers
@@ -778,13 +864,14 @@ func (kv *KVStore) SetWithFlagsLocal(key, value string, flags uint64) {
 		event := watch.Event{
 			Type:      watch.EventTypeSet,
 			Key:       key,
+			Index:     newIndex,
 			Value:     value,
 			Timestamp: time.Now().Unix(),
 		}
 		if existed {
 			event.OldValue = oldEntry.Value
 		}
-		kv.watchManager.Notify(event)
+		kv.publish(event)
 	}
 }
 
@@ -819,7 +906,7 @@ func (kv *KVStore) SetCASLocal(key, value string, expectedIndex uint64) (uint64,
 		}
 	}
 
-	newIndex := kv.nextIndex()
+	newIndex := kv.nextIndex(key)
 	entry := KVEntry{
 		Value:       value,
 		ModifyIndex: newIndex,
@@ -827,23 +914,26 @@ func (kv *KVStore) SetCASLocal(key, value string, expectedIndex uint64) (uint64,
 	if existed {
 		entry.CreateIndex = oldEntry.CreateIndex
 		entry.Flags = oldEntry.Flags
+		entry.Session = oldEntry.Session
+		entry.LockIndex = oldEntry.LockIndex
 	} else {
 		entry.CreateIndex = newIndex
 	}
-	kv.Data[key] = entry
+	kv.putEntry(key, entry)
 
 	// Notify watchers
 	if kv.watchManager != nil {
 		event := watch.Event{
 			Type:      watch.EventTypeSet,
 			Key:       key,
+			Index:     newIndex,
 			Value:     value,
 			Timestamp: time.Now().Unix(),
 		}
 		if existed {
 			event.OldValue = oldEntry.Value
 		}
-		kv.watchManager.Notify(event)
+		kv.publish(event)
 	}
 
 	return newIndex, nil
@@ -868,17 +958,18 @@ func (kv *KVStore) DeleteCASLocal(key string, expectedIndex uint64) error {
 		}
 	}
 
-	delete(kv.Data, key)
+	index := kv.deleteEntry(key)
 
 	// Notify watchers
 	if kv.watchManager != nil {
 		event := watch.Event{
 			Type:      watch.EventTypeDelete,
 			Key:       key,
+			Index:     index,
 			OldValue:  oldEntry.Value,
 			Timestamp: time.Now().Unix(),
 		}
-		kv.watchManager.Notify(event)
+		kv.publish(event)
 	}
 
 	return nil
@@ -928,7 +1019,7 @@ func (kv *KVStore) BatchSetCASLocal(items map[string]string, expectedIndices map
 			oldEntries[key] = oldEntry
 		}
 
-		newIndex := kv.nextIndex()
+		newIndex := kv.nextIndex(key)
 		entry := KVEntry{
 			Value:       value,
 			ModifyIndex: newIndex,
@@ -936,10 +1027,12 @@ func (kv *KVStore) BatchSetCASLocal(items map[string]string, expectedIndices map
 		if existed {
 			entry.CreateIndex = oldEntry.CreateIndex
 			entry.Flags = oldEntry.Flags
+			entry.Session = oldEntry.Session
+			entry.LockIndex = oldEntry.LockIndex
 		} else {
 			entry.CreateIndex = newIndex
 		}
-		kv.Data[key] = entry
+		kv.putEntry(key, entry)
 		newIndices[key] = newIndex
 	}
 
@@ -950,13 +1043,14 @@ func (kv *KVStore) BatchSetCASLocal(items map[string]string, expectedIndices map
 			event := watch.Event{
 				Type:      watch.EventTypeSet,
 				Key:       key,
+				Index:     newIndices[key],
 				Value:     value,
 				Timestamp: timestamp,
 			}
 			if oldEntry, existed := oldEntries[key]; existed {
 				event.OldValue = oldEntry.Value
 			}
-			kv.watchManager.Notify(event)
+			kv.publish(event)
 		}
 	}
 
@@ -988,9 +1082,10 @@ func (kv *KVStore) BatchDeleteCASLocal(keys []string, expectedIndices map[string
 
 	// Second pass: delete all keys
 	oldEntries := make(map[string]KVEntry)
+	indexes := make(map[string]uint64)
 	for _, key := range keys {
 		oldEntries[key] = kv.Data[key]
-		delete(kv.Data, key)
+		indexes[key] = kv.deleteEntry(key)
 	}
 
 	// Notify watchers
@@ -1000,10 +1095,11 @@ func (kv *KVStore) BatchDeleteCASLocal(keys []string, expectedIndices map[string
 			event := watch.Event{
 				Type:      watch.EventTypeDelete,
 				Key:       key,
+				Index:     indexes[key],
 				OldValue:  oldEntry.Value,
 				Timestamp: timestamp,
 			}
-			kv.watchManager.Notify(event)
+			kv.publish(event)
 		}
 	}
 
@@ -1030,6 +1126,7 @@ func (kv *KVStore) BatchSetLocal(items map[string]string) error {
 
 	// Track old values for watch events
 	oldEntries := make(map[string]KVEntry)
+	indexes := make(map[string]uint64)
 
 	for key, value := range items {
 		oldEntry, existed := kv.Data[key]
@@ -1037,7 +1134,7 @@ func (kv *KVStore) BatchSetLocal(items map[string]string) error {
 			oldEntries[key] = oldEntry
 		}
 
-		newIndex := kv.nextIndex()
+		newIndex := kv.nextIndex(key)
 		entry := KVEntry{
 			Value:       value,
 			ModifyIndex: newIndex,
@@ -1045,10 +1142,13 @@ func (kv *KVStore) BatchSetLocal(items map[string]string) error {
 		if existed {
 			entry.CreateIndex = oldEntry.CreateIndex
 			entry.Flags = oldEntry.Flags
+			entry.Session = oldEntry.Session
+			entry.LockIndex = oldEntry.LockIndex
 		} else {
 			entry.CreateIndex = newIndex
 		}
-		kv.Data[key] = entry
+		kv.putEntry(key, entry)
+		indexes[key] = newIndex
 	}
 	kv.Mutex.Unlock()
 
@@ -1059,13 +1159,14 @@ func (kv *KVStore) BatchSetLocal(items map[string]string) error {
 			event := watch.Event{
 				Type:      watch.EventTypeSet,
 				Key:       key,
+				Index:     indexes[key],
 				Value:     value,
 				Timestamp: timestamp,
 			}
 			if oldEntry, existed := oldEntries[key]; existed {
 				event.OldValue = oldEntry.Value
 			}
-			kv.watchManager.Notify(event)
+			kv.publish(event)
 		}
 	}
 
@@ -1077,7 +1178,7 @@ func (kv *KVStore) BatchSetLocal(items map[string]string) error {
 func (kv *KVStore) DeleteLocal(key string) {
 	kv.Mutex.Lock()
 	oldEntry, existed := kv.Data[key]
-	delete(kv.Data, key)
+	index := kv.deleteEntry(key)
 	kv.Mutex.Unlock()
 
 	// Notify watchers if key existed
@@ -1085,10 +1186,11 @@ func (kv *KVStore) DeleteLocal(key string) {
 		event := watch.Event{
 			Type:      watch.EventTypeDelete,
 			Key:       key,
+			Index:     index,
 			OldValue:  oldEntry.Value,
 			Timestamp: time.Now().Unix(),
 		}
-		kv.watchManager.Notify(event)
+		kv.publish(event)
 	}
 }
 
@@ -1099,11 +1201,12 @@ func (kv *KVStore) BatchDeleteLocal(keys []string) error {
 
 	// Track old values for watch events
 	oldEntries := make(map[string]KVEntry)
+	indexes := make(map[string]uint64)
 	for _, key := range keys {
 		if oldEntry, existed := kv.Data[key]; existed {
 			oldEntries[key] = oldEntry
 		}
-		delete(kv.Data, key)
+		indexes[key] = kv.deleteEntry(key)
 	}
 	kv.Mutex.Unlock()
 
@@ -1114,10 +1217,11 @@ func (kv *KVStore) BatchDeleteLocal(keys []string) error {
 			event := watch.Event{
 				Type:      watch.EventTypeDelete,
 				Key:       key,
+				Index:     indexes[key],
 				OldValue:  oldEntry.Value,
 				Timestamp: timestamp,
 			}
-			kv.watchManager.Notify(event)
+			kv.publish(event)
 		}
 	}
 
@@ -1126,10 +1230,14 @@ func (kv *KVStore) BatchDeleteLocal(keys []string) error {
 
 // KVEntrySnapshot represents KV entry data for Raft snapshots.
 type KVEntrySnapshot struct {
-	Value       string `json:"value"`
-	ModifyIndex uint64 `json:"modify_index"`
-	CreateIndex uint64 `json:"create_index"`
-	Flags       uint64 `json:"flags,omitempty"`
+	Value       string    `json:"value"`
+	ModifyIndex uint64    `json:"modify_index"`
+	CreateIndex uint64    `json:"create_index"`
+	Flags       uint64    `json:"flags,omitempty"`
+	Session     string    `json:"session,omitempty"`    // ID of the session holding the lock, if any
+	LockIndex   uint64    `json:"lock_index,omitempty"` // Number of times the lock has been acquired
+	ExpiresAt   time.Time `json:"expires_at,omitzero"`  // When the key is deleted; zero if it never expires
+	Secret      bool      `json:"secret,omitempty"`     // Value is sealed with the transit key
 }
 
 // GetAllData returns all KV data for Raft snapshotting.
@@ -1148,23 +1256,94 @@ func (kv *KVStore) GetAllData() map[string]KVEntrySnapshot {
 // RestoreFromSnapshot restores KV data from a Raft snapshot.
 // This replaces all existing data with the snapshot data.
 func (kv *KVStore) RestoreFromSnapshot(data map[string]KVEntrySnapshot) error {
+	records := make([]KVRecord, 0, len(data))
+	for key, entry := range data {
+		records = append(records, KVRecord{Key: key, Entry: entry})
+	}
+	commit, err := kv.PrepareRestore(singleChunk(records))
+	if err != nil {
+		return err
+	}
+	commit()
+	return nil
+}
+
+// KVRecord is a KV entry of a Raft snapshot.
+type KVRecord struct {
+	Key   string
+	Entry KVEntrySnapshot
+}
+
+// OpenSnapshot returns the KV records as they are now, for Raft
+// snapshotting. They are read from the store chunk by chunk while it keeps
+// changing; only the keys changed before they are read are copied.
+func (kv *KVStore) OpenSnapshot() RecordReader[KVRecord] {
 	kv.Mutex.Lock()
 	defer kv.Mutex.Unlock()
 
-	// Clear existing data
-	kv.Data = make(map[string]KVEntry, len(data))
+	var view *snapshotView[KVEntry, KVRecord]
+	view = newSnapshotView(kv.Data, &kv.Mutex, kvRecord, func() {
+		kv.snapshots = slices.DeleteFunc(kv.snapshots, func(v *snapshotView[KVEntry, KVRecord]) bool {
+			return v == view
+		})
+	})
+	kv.snapshots = append(kv.snapshots, view)
+	return view
+}
+
+func kvRecord(key string, entry KVEntry) KVRecord {
+	return KVRecord{Key: key, Entry: KVEntrySnapshot(entry)}
+}
+
+// putEntry stores entry under key. Callers must hold kv.Mutex.
+func (kv *KVStore) putEntry(key string, entry KVEntry) {
+	for _, view := range kv.snapshots {
+		view.save(kv.Data, key)
+	}
+	kv.Data[key] = entry
+}
 
-	// Restore from snapshot
+// PrepareRestore builds the KV data of the records returned by next, which
+// returns io.EOF after the last chunk, while the store keeps serving the
+// current data. The returned commit function replaces all KV data with it;
+// the store is left as is if next fails or commit is never called.
+func (kv *KVStore) PrepareRestore(next func() ([]KVRecord, error)) (func(), error) {
+	data := make(map[string]KVEntry)
 	var maxIndex uint64
-	for key, snapshot := range data {
-		kv.Data[key] = KVEntry(snapshot)
-		if snapshot.ModifyIndex > maxIndex {
-			maxIndex = snapshot.ModifyIndex
+	for {
+		records, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			data[record.Key] = KVEntry(record.Entry)
+			maxIndex = max(maxIndex, record.Entry.ModifyIndex)
 		}
 	}
 
-	// Update global index to max found index
-	kv.globalIndex = maxIndex
+	return func() {
+		kv.Mutex.Lock()
+		defer kv.Mutex.Unlock()
 
-	return nil
+		kv.Data = data
+		kv.snapshots = nil // They read the replaced data, which no longer changes
+
+		// Update global index to max found index
+		kv.resetIndex(maxIndex)
+	}, nil
+}
+
+// singleChunk returns a PrepareRestore callback that returns records once.
+func singleChunk[T any](records []T) func() ([]T, error) {
+	done := false
+	return func() ([]T, error) {
+		if done {
+			return nil, io.EOF
+		}
+		done = true
+		return records, nil
+	}
 }
@@ -0,0 +1,191 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/watch"
+)
+
+// acquireLock takes the lock on key for the given session and stores value.
+// The lock is refused while it is held by another session that isLive still
+// reports as existing; locks left behind by unknown sessions are taken over.
+// Re-acquiring a lock already held by the session only updates the value.
+func (kv *KVStore) acquireLock(key, value, session string, isLive func(string) bool, persist bool) bool {
+	kv.Mutex.Lock()
+	oldEntry, existed := kv.Data[key]
+	if existed && oldEntry.Session != "" && oldEntry.Session != session && isLive(oldEntry.Session) {
+		kv.Mutex.Unlock()
+		return false
+	}
+
+	newIndex := kv.nextIndex(key)
+	entry := KVEntry{
+		Value:       value,
+		ModifyIndex: newIndex,
+		Session:     session,
+	}
+	if existed {
+		entry.CreateIndex = oldEntry.CreateIndex
+		entry.Flags = oldEntry.Flags
+		entry.LockIndex = oldEntry.LockIndex
+	} else {
+		entry.CreateIndex = newIndex
+	}
+	if oldEntry.Session != session {
+		entry.LockIndex++
+	}
+	kv.putEntry(key, entry)
+	kv.Mutex.Unlock()
+
+	if persist {
+		kv.persistEntry(key, entry)
+	}
+	kv.notifySet(key, entry, oldEntry, existed)
+	return true
+}
+
+// releaseLock clears the lock on key if it is held by session. The value is
+// left untouched. Returns false if the key is missing or held by someone else.
+func (kv *KVStore) releaseLock(key, session string, persist bool) bool {
+	kv.Mutex.Lock()
+	oldEntry, existed := kv.Data[key]
+	if !existed || oldEntry.Session != session {
+		kv.Mutex.Unlock()
+		return false
+	}
+
+	entry := oldEntry
+	entry.Session = ""
+	entry.ModifyIndex = kv.nextIndex(key)
+	kv.putEntry(key, entry)
+	kv.Mutex.Unlock()
+
+	if persist {
+		kv.persistEntry(key, entry)
+	}
+	kv.notifySet(key, entry, oldEntry, true)
+	return true
+}
+
+// releaseSession drops every lock held by session. With deleteKeys the locked
+// keys are removed, otherwise only the lock is cleared. Keys are processed in
+// sorted order so that replicas assign identical indices.
+func (kv *KVStore) releaseSession(session string, deleteKeys, persist bool) []string {
+	kv.Mutex.Lock()
+	var keys []string
+	for key, entry := range kv.Data {
+		if entry.Session == session {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	oldEntries := make(map[string]KVEntry, len(keys))
+	newEntries := make(map[string]KVEntry, len(keys))
+	deleteIndexes := make(map[string]uint64, len(keys))
+	for _, key := range keys {
+		oldEntry := kv.Data[key]
+		oldEntries[key] = oldEntry
+		if deleteKeys {
+			deleteIndexes[key] = kv.deleteEntry(key)
+			continue
+		}
+		entry := oldEntry
+		entry.Session = ""
+		entry.ModifyIndex = kv.nextIndex(key)
+		kv.putEntry(key, entry)
+		newEntries[key] = entry
+	}
+	kv.Mutex.Unlock()
+
+	for _, key := range keys {
+		if deleteKeys {
+			if persist {
+				kv.persistDelete(key)
+			}
+			kv.notifyDelete(key, deleteIndexes[key], oldEntries[key])
+			continue
+		}
+		if persist {
+			kv.persistEntry(key, newEntries[key])
+		}
+		kv.notifySet(key, newEntries[key], oldEntries[key], true)
+	}
+
+	return keys
+}
+
+// persistEntry writes an entry to the persistence engine, if configured.
+func (kv *KVStore) persistEntry(key string, entry KVEntry) {
+	if kv.engine == nil {
+		return
+	}
+	if err := kv.writeEntry(key, entry); err != nil {
+		kv.log.Error("Failed to persist key",
+			logger.String("key", key),
+			logger.Error(err))
+	}
+}
+
+// writeEntry encodes an entry and writes it to the persistence engine.
+// Entries with an expiry are written with a matching engine TTL.
+func (kv *KVStore) writeEntry(key string, entry KVEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal KV entry: %w", err)
+	}
+	if entry.ExpiresAt.IsZero() {
+		return kv.engine.Set(key, data)
+	}
+	ttl := time.Until(entry.ExpiresAt)
+	if ttl <= 0 {
+		return kv.engine.Delete(key)
+	}
+	return kv.engine.SetWithTTL(key, data, ttl)
+}
+
+// persistDelete removes a key from the persistence engine, if configured.
+func (kv *KVStore) persistDelete(key string) {
+	if kv.engine == nil {
+		return
+	}
+	if err := kv.engine.Delete(key); err != nil {
+		kv.log.Error("Failed to delete key from persistence",
+			logger.String("key", key),
+			logger.Error(err))
+	}
+}
+
+func (kv *KVStore) notifySet(key string, entry, oldEntry KVEntry, existed bool) {
+	if kv.watchManager == nil {
+		return
+	}
+	event := watch.Event{
+		Type:      watch.EventTypeSet,
+		Key:       key,
+		Index:     entry.ModifyIndex,
+		Value:     entry.Value,
+		Timestamp: time.Now().Unix(),
+	}
+	if existed {
+		event.OldValue = oldEntry.Value
+	}
+	kv.publish(event)
+}
+
+func (kv *KVStore) notifyDelete(key string, index uint64, oldEntry KVEntry) {
+	if kv.watchManager == nil {
+		return
+	}
+	kv.publish(watch.Event{
+		Type:      watch.EventTypeDelete,
+		Key:       key,
+		Index:     index,
+		OldValue:  oldEntry.Value,
+		Timestamp: time.Now().Unix(),
+	})
+}
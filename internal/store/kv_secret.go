@@ -0,0 +1,100 @@
+package store
+
+import (
+	"errors"
+	"time"
+
+	"github.com/neogan74/konsul/internal/secrets"
+	"github.com/neogan74/konsul/internal/watch"
+)
+
+// ErrSecretsDisabled is returned for secret operations on a KV store without
+// a transit key.
+var ErrSecretsDisabled = errors.New("secrets are not enabled (no transit key configured)")
+
+// SetTransit sets the transit key that secret values are sealed with.
+func (kv *KVStore) SetTransit(t *secrets.Transit) {
+	kv.transit = t
+}
+
+// SecretsEnabled reports whether secret values can be stored.
+func (kv *KVStore) SecretsEnabled() bool {
+	return kv.transit != nil
+}
+
+// SealSecret encrypts value for key. The result is what SetSealedSecretLocal
+// stores, so that a Raft leader can seal a secret once and replicate only
+// the sealed value.
+func (kv *KVStore) SealSecret(key, value string) (string, error) {
+	if kv.transit == nil {
+		return "", ErrSecretsDisabled
+	}
+	return kv.transit.Seal(key, value)
+}
+
+// SetSecret seals value and stores it as a secret. A ttl of 0 means the key
+// never expires; flags of 0 keep the existing flags.
+func (kv *KVStore) SetSecret(key, value string, flags uint64, ttl time.Duration) error {
+	sealed, err := kv.SealSecret(key, value)
+	if err != nil {
+		return err
+	}
+	kv.setExpiring(key, sealed, flags, KVExpiry(ttl), true, true)
+	return nil
+}
+
+// SetSecretCAS is SetCAS for a secret.
+func (kv *KVStore) SetSecretCAS(key, value string, expectedIndex uint64, ttl time.Duration) (uint64, error) {
+	sealed, err := kv.SealSecret(key, value)
+	if err != nil {
+		return 0, err
+	}
+	return kv.setCASExpiring(key, sealed, expectedIndex, KVExpiry(ttl), true, true)
+}
+
+// SetSealedSecretLocal stores a value sealed by SealSecret, without
+// persisting. This is used by Raft FSM when applying committed log entries.
+func (kv *KVStore) SetSealedSecretLocal(key, sealed string, flags uint64, expiresAt time.Time) {
+	kv.setExpiring(key, sealed, flags, expiresAt, true, false)
+}
+
+// SetSealedSecretCASLocal is SetSealedSecretLocal with a CAS check.
+func (kv *KVStore) SetSealedSecretCASLocal(key, sealed string, expectedIndex uint64, expiresAt time.Time) (uint64, error) {
+	return kv.setCASExpiring(key, sealed, expectedIndex, expiresAt, true, false)
+}
+
+// Reveal returns the plaintext value of an entry of key: the decrypted value
+// of a secret, or the value itself otherwise.
+func (kv *KVStore) Reveal(key string, entry KVEntry) (string, error) {
+	if !entry.Secret {
+		return entry.Value, nil
+	}
+	if kv.transit == nil {
+		return "", ErrSecretsDisabled
+	}
+	return kv.transit.Open(key, entry.Value)
+}
+
+// Redacted returns the entry with the value of a secret removed.
+func (e KVEntry) Redacted() KVEntry {
+	if e.Secret {
+		e.Value = ""
+	}
+	return e
+}
+
+// publish sends a KV event to the watch manager. Sealed secret values are
+// never published: they are removed and the event is marked as secret.
+func (kv *KVStore) publish(event watch.Event) {
+	if kv.watchManager == nil {
+		return
+	}
+	if secrets.IsSealed(event.Value) {
+		event.Value = ""
+		event.Secret = true
+	}
+	if secrets.IsSealed(event.OldValue) {
+		event.OldValue = ""
+	}
+	kv.watchManager.Notify(event)
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/persistence"
+	"github.com/neogan74/konsul/internal/secrets"
+	"github.com/neogan74/konsul/internal/watch"
+)
+
+func newTestTransit(t *testing.T) *secrets.Transit {
+	t.Helper()
+	transit, err := secrets.NewTransit(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("failed to create transit: %v", err)
+	}
+	return transit
+}
+
+func TestKVStore_SetSecret(t *testing.T) {
+	kv := NewKVStore()
+	if err := kv.SetSecret("db/password", "hunter2", 0, 0); !errors.Is(err, ErrSecretsDisabled) {
+		t.Fatalf("expected ErrSecretsDisabled without a transit key, got %v", err)
+	}
+
+	kv.SetTransit(newTestTransit(t))
+	if err := kv.SetSecret("db/password", "hunter2", 3, time.Hour); err != nil {
+		t.Fatalf("failed to set secret: %v", err)
+	}
+
+	entry, ok := kv.GetEntry("db/password")
+	if !ok || !entry.Secret {
+		t.Fatalf("expected a secret entry, got %+v", entry)
+	}
+	if strings.Contains(entry.Value, "hunter2") || !secrets.IsSealed(entry.Value) {
+		t.Errorf("expected a sealed value, got %q", entry.Value)
+	}
+	if entry.Flags != 3 || entry.ExpiresAt.IsZero() {
+		t.Errorf("expected flags and expiry to be set, got %+v", entry)
+	}
+
+	// Plain reads never see the secret
+	if value, ok := kv.Get("db/password"); !ok || value != "" {
+		t.Errorf("expected redacted value from Get, got %q", value)
+	}
+	found, _ := kv.BatchGet([]string{"db/password"})
+	if found["db/password"] != "" {
+		t.Errorf("expected redacted value from BatchGet, got %q", found["db/password"])
+	}
+
+	value, err := kv.Reveal("db/password", entry)
+	if err != nil || value != "hunter2" {
+		t.Fatalf("expected hunter2, got %q (%v)", value, err)
+	}
+
+	// A plain write replaces the secret
+	kv.Set("db/password", "visible")
+	entry, _ = kv.GetEntry("db/password")
+	if entry.Secret {
+		t.Error("expected a plain write to clear the secret flag")
+	}
+	if value, _ := kv.Reveal("db/password", entry); value != "visible" {
+		t.Errorf("expected plain value, got %q", value)
+	}
+}
+
+func TestKVStore_SetSecretCAS(t *testing.T) {
+	kv := NewKVStore()
+	kv.SetTransit(newTestTransit(t))
+	kv.Set("api/token", "old")
+	entry, _ := kv.GetEntry("api/token")
+
+	if _, err := kv.SetSecretCAS("api/token", "s3cr3t", entry.ModifyIndex+1, 0); !IsCASConflict(err) {
+		t.Fatalf("expected CAS conflict, got %v", err)
+	}
+	newIndex, err := kv.SetSecretCAS("api/token", "s3cr3t", entry.ModifyIndex, 0)
+	if err != nil {
+		t.Fatalf("failed to set secret with CAS: %v", err)
+	}
+	entry, _ = kv.GetEntry("api/token")
+	if !entry.Secret || entry.ModifyIndex != newIndex {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+}
+
+func TestKVStore_SecretWatchEvents(t *testing.T) {
+	kv := NewKVStore()
+	kv.SetTransit(newTestTransit(t))
+	wm := watch.NewManager(nil, logger.GetDefault(), 10, 0)
+	kv.SetWatchManager(wm)
+	watcher, err := wm.AddWatcher("db/*", nil, watch.TransportWebSocket, "")
+	if err != nil {
+		t.Fatalf("failed to add watcher: %v", err)
+	}
+
+	if err := kv.SetSecret("db/password", "hunter2", 0, 0); err != nil {
+		t.Fatalf("failed to set secret: %v", err)
+	}
+	if err := kv.SetSecret("db/password", "hunter3", 0, 0); err != nil {
+		t.Fatalf("failed to set secret: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case event := <-watcher.Events:
+			if !event.Secret || event.Value != "" || event.OldValue != "" {
+				t.Errorf("expected redacted secret event, got %+v", event)
+			}
+		default:
+			t.Fatal("expected a set event")
+		}
+	}
+}
+
+func TestKVStore_SecretPersistence(t *testing.T) {
+	engine := persistence.NewMemoryEngine()
+	log := logger.GetDefault()
+
+	kv, err := NewKVStoreWithPersistence(engine, log)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	kv.SetTransit(newTestTransit(t))
+	if err := kv.SetSecret("db/password", "hunter2", 0, 0); err != nil {
+		t.Fatalf("failed to set secret: %v", err)
+	}
+
+	raw, err := engine.Get("db/password")
+	if err != nil {
+		t.Fatalf("failed to read persisted entry: %v", err)
+	}
+	if bytes.Contains(raw, []byte("hunter2")) {
+		t.Error("found plaintext secret in persisted entry")
+	}
+
+	reloaded, _ := NewKVStoreWithPersistence(engine, log)
+	reloaded.SetTransit(newTestTransit(t))
+	entry, ok := reloaded.GetEntry("db/password")
+	if !ok || !entry.Secret {
+		t.Fatalf("expected secret to be reloaded, got %+v", entry)
+	}
+	if value, err := reloaded.Reveal("db/password", entry); err != nil || value != "hunter2" {
+		t.Errorf("expected hunter2 after reload, got %q (%v)", value, err)
+	}
+}
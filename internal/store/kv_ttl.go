@@ -0,0 +1,252 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MinKVTTL is the shortest TTL accepted for a key. The persistence engine
+// tracks expiry with one second granularity.
+const MinKVTTL = time.Second
+
+// ParseKVTTL parses a key TTL such as "30s". An empty string means no TTL.
+func ParseKVTTL(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid TTL: %w", err)
+	}
+	if ttl < MinKVTTL {
+		return 0, fmt.Errorf("TTL must be at least %s", MinKVTTL)
+	}
+	return ttl, nil
+}
+
+// KVExpiry returns the absolute expiry for a TTL starting now, or the zero
+// time if ttl is not positive.
+func KVExpiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+// Expired reports whether the entry has an expiry at or before now.
+func (e KVEntry) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt)
+}
+
+// SetWithTTL stores a key that is deleted once ttl elapses. Flags of 0 keep
+// the existing flags, as Set does.
+func (kv *KVStore) SetWithTTL(key, value string, flags uint64, ttl time.Duration) {
+	kv.setExpiring(key, value, flags, KVExpiry(ttl), false, true)
+}
+
+// SetWithExpiryLocal stores a key that is deleted at expiresAt, without
+// persisting. This is used by Raft FSM when applying committed log entries;
+// the expiry is absolute so that every node agrees on it.
+func (kv *KVStore) SetWithExpiryLocal(key, value string, flags uint64, expiresAt time.Time) {
+	kv.setExpiring(key, value, flags, expiresAt, false, false)
+}
+
+func (kv *KVStore) setExpiring(key, value string, flags uint64, expiresAt time.Time, secret, persist bool) {
+	kv.Mutex.Lock()
+	oldEntry, existed := kv.Data[key]
+	entry := kv.nextEntry(key, value, oldEntry, existed)
+	if flags > 0 {
+		entry.Flags = flags
+	}
+	entry.ExpiresAt = expiresAt
+	entry.Secret = secret
+	kv.putEntry(key, entry)
+	kv.Mutex.Unlock()
+
+	if persist {
+		kv.persistEntry(key, entry)
+	}
+	kv.notifySet(key, entry, oldEntry, existed)
+}
+
+// SetCASWithTTL is SetCAS for a key that is deleted once ttl elapses.
+func (kv *KVStore) SetCASWithTTL(key, value string, expectedIndex uint64, ttl time.Duration) (uint64, error) {
+	return kv.setCASExpiring(key, value, expectedIndex, KVExpiry(ttl), false, true)
+}
+
+// SetCASWithExpiryLocal is SetCAS for a key that is deleted at expiresAt,
+// without persisting. This is used by Raft FSM when applying committed log entries.
+func (kv *KVStore) SetCASWithExpiryLocal(key, value string, expectedIndex uint64, expiresAt time.Time) (uint64, error) {
+	return kv.setCASExpiring(key, value, expectedIndex, expiresAt, false, false)
+}
+
+func (kv *KVStore) setCASExpiring(key, value string, expectedIndex uint64, expiresAt time.Time, secret, persist bool) (uint64, error) {
+	kv.Mutex.Lock()
+	oldEntry, existed := kv.Data[key]
+	if err := checkKeyCAS(key, oldEntry, existed, expectedIndex); err != nil {
+		kv.Mutex.Unlock()
+		return 0, err
+	}
+	entry := kv.nextEntry(key, value, oldEntry, existed)
+	entry.ExpiresAt = expiresAt
+	entry.Secret = secret
+	kv.putEntry(key, entry)
+	kv.Mutex.Unlock()
+
+	if persist {
+		kv.persistEntry(key, entry)
+	}
+	kv.notifySet(key, entry, oldEntry, existed)
+	return entry.ModifyIndex, nil
+}
+
+// BatchSetWithTTL sets multiple keys that are all deleted once ttl elapses.
+func (kv *KVStore) BatchSetWithTTL(items map[string]string, ttl time.Duration) error {
+	return kv.batchSetExpiring(items, KVExpiry(ttl), true)
+}
+
+// BatchSetWithExpiryLocal sets multiple keys that are all deleted at
+// expiresAt, without persisting. This is used by Raft FSM when applying
+// committed log entries.
+func (kv *KVStore) BatchSetWithExpiryLocal(items map[string]string, expiresAt time.Time) error {
+	return kv.batchSetExpiring(items, expiresAt, false)
+}
+
+func (kv *KVStore) batchSetExpiring(items map[string]string, expiresAt time.Time, persist bool) error {
+	// Sorted so that replicas assign identical indices
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	kv.Mutex.Lock()
+	oldEntries := make(map[string]KVEntry)
+	newEntries := make(map[string]KVEntry, len(keys))
+	for _, key := range keys {
+		oldEntry, existed := kv.Data[key]
+		if existed {
+			oldEntries[key] = oldEntry
+		}
+		entry := kv.nextEntry(key, items[key], oldEntry, existed)
+		entry.ExpiresAt = expiresAt
+		kv.putEntry(key, entry)
+		newEntries[key] = entry
+	}
+	kv.Mutex.Unlock()
+
+	if persist && kv.engine != nil {
+		for _, key := range keys {
+			if err := kv.writeEntry(key, newEntries[key]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, key := range keys {
+		oldEntry, existed := oldEntries[key]
+		kv.notifySet(key, newEntries[key], oldEntry, existed)
+	}
+	return nil
+}
+
+// ExpiredKeys returns the keys whose TTL has run out at now, in sorted order.
+func (kv *KVStore) ExpiredKeys(now time.Time) []string {
+	kv.Mutex.RLock()
+	defer kv.Mutex.RUnlock()
+
+	var keys []string
+	for key, entry := range kv.Data {
+		if entry.Expired(now) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// DeleteExpired deletes every key whose TTL has run out at now and returns
+// the deleted keys. Watchers receive a delete event for each of them.
+func (kv *KVStore) DeleteExpired(now time.Time) []string {
+	return kv.deleteExpired(kv.ExpiredKeys(now), now, true)
+}
+
+// DeleteExpiredLocal deletes the given keys that are still expired at now,
+// without persisting. Keys rewritten since they were found expired are kept.
+// This is used by Raft FSM when applying committed log entries.
+func (kv *KVStore) DeleteExpiredLocal(keys []string, now time.Time) []string {
+	return kv.deleteExpired(keys, now, false)
+}
+
+func (kv *KVStore) deleteExpired(keys []string, now time.Time, persist bool) []string {
+	kv.Mutex.Lock()
+	var deleted []string
+	oldEntries := make(map[string]KVEntry, len(keys))
+	indexes := make(map[string]uint64, len(keys))
+	for _, key := range keys {
+		entry, ok := kv.Data[key]
+		if !ok || !entry.Expired(now) {
+			continue
+		}
+		indexes[key] = kv.deleteEntry(key)
+		oldEntries[key] = entry
+		deleted = append(deleted, key)
+	}
+	kv.Mutex.Unlock()
+
+	for _, key := range deleted {
+		if persist {
+			kv.persistDelete(key)
+		}
+		kv.notifyDelete(key, indexes[key], oldEntries[key])
+	}
+	return deleted
+}
+
+// nextEntry builds the entry that replaces oldEntry of key with value, keeping its
+// creation index, flags and lock. Callers must hold kv.Mutex.
+func (kv *KVStore) nextEntry(key, value string, oldEntry KVEntry, existed bool) KVEntry {
+	newIndex := kv.nextIndex(key)
+	entry := KVEntry{
+		Value:       value,
+		ModifyIndex: newIndex,
+	}
+	if existed {
+		entry.CreateIndex = oldEntry.CreateIndex
+		entry.Flags = oldEntry.Flags
+		entry.Session = oldEntry.Session
+		entry.LockIndex = oldEntry.LockIndex
+	} else {
+		entry.CreateIndex = newIndex
+	}
+	return entry
+}
+
+// checkKeyCAS verifies the CAS condition for a key write. An expectedIndex
+// of 0 means the key must not exist yet.
+func checkKeyCAS(key string, oldEntry KVEntry, existed bool, expectedIndex uint64) error {
+	if expectedIndex == 0 {
+		if existed {
+			return &CASConflictError{
+				Key:           key,
+				ExpectedIndex: 0,
+				CurrentIndex:  oldEntry.ModifyIndex,
+				OperationType: "key",
+			}
+		}
+		return nil
+	}
+	if !existed {
+		return &NotFoundError{Type: "key", Key: key}
+	}
+	if oldEntry.ModifyIndex != expectedIndex {
+		return &CASConflictError{
+			Key:           key,
+			ExpectedIndex: expectedIndex,
+			CurrentIndex:  oldEntry.ModifyIndex,
+			OperationType: "key",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/persistence"
+	"github.com/neogan74/konsul/internal/watch"
+)
+
+func TestParseKVTTL(t *testing.T) {
+	if ttl, err := ParseKVTTL(""); err != nil || ttl != 0 {
+		t.Errorf("expected empty TTL to mean no TTL, got %v, %v", ttl, err)
+	}
+	if ttl, err := ParseKVTTL("30s"); err != nil || ttl != 30*time.Second {
+		t.Errorf("expected 30s, got %v, %v", ttl, err)
+	}
+	for _, s := range []string{"soon", "500ms", "-5s"} {
+		if _, err := ParseKVTTL(s); err == nil {
+			t.Errorf("expected error for TTL %q", s)
+		}
+	}
+}
+
+func TestKVStore_SetWithTTL(t *testing.T) {
+	kv := NewKVStore()
+	kv.SetWithFlags("flag/override", "old", 7)
+
+	kv.SetWithTTL("flag/override", "on", 0, time.Minute)
+	entry, ok := kv.GetEntry("flag/override")
+	if !ok || entry.Value != "on" {
+		t.Fatalf("expected value on, got %+v", entry)
+	}
+	if entry.ExpiresAt.IsZero() || time.Until(entry.ExpiresAt) > time.Minute {
+		t.Errorf("unexpected expiry %v", entry.ExpiresAt)
+	}
+	if entry.Flags != 7 {
+		t.Errorf("expected flags to be kept, got %d", entry.Flags)
+	}
+
+	// A plain write makes the key permanent again
+	kv.Set("flag/override", "off")
+	entry, _ = kv.GetEntry("flag/override")
+	if !entry.ExpiresAt.IsZero() {
+		t.Errorf("expected Set to clear the expiry, got %v", entry.ExpiresAt)
+	}
+}
+
+func TestKVStore_SetCASWithTTL(t *testing.T) {
+	kv := NewKVStore()
+
+	index, err := kv.SetCASWithTTL("presence/node-1", "up", 0, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := kv.SetCASWithTTL("presence/node-1", "up", 0, time.Minute); !IsCASConflict(err) {
+		t.Errorf("expected CAS conflict creating existing key, got %v", err)
+	}
+	if _, err := kv.SetCASWithTTL("presence/node-1", "up", index, time.Minute); err != nil {
+		t.Errorf("expected CAS update to succeed, got %v", err)
+	}
+	if _, err := kv.SetCASWithTTL("presence/missing", "up", 5, time.Minute); !IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestKVStore_DeleteExpired(t *testing.T) {
+	kv := NewKVStore()
+	wm := watch.NewManager(nil, logger.GetDefault(), 10, 0)
+	kv.SetWatchManager(wm)
+	watcher, err := wm.AddWatcher("presence/*", nil, watch.TransportWebSocket, "")
+	if err != nil {
+		t.Fatalf("failed to add watcher: %v", err)
+	}
+
+	now := time.Now()
+	kv.SetWithExpiryLocal("presence/a", "up", 0, now.Add(-time.Second))
+	if err := kv.BatchSetWithExpiryLocal(map[string]string{"presence/b": "up"}, now.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kv.Set("presence/c", "up")
+	for len(watcher.Events) > 0 {
+		<-watcher.Events
+	}
+
+	if keys := kv.ExpiredKeys(now); len(keys) != 1 || keys[0] != "presence/a" {
+		t.Fatalf("expected only presence/a to be expired, got %v", keys)
+	}
+	deleted := kv.DeleteExpired(now)
+	if len(deleted) != 1 || deleted[0] != "presence/a" {
+		t.Fatalf("expected presence/a to be deleted, got %v", deleted)
+	}
+	if _, ok := kv.Get("presence/a"); ok {
+		t.Error("expected presence/a to be gone")
+	}
+	if _, ok := kv.Get("presence/b"); !ok {
+		t.Error("expected presence/b to be kept")
+	}
+
+	select {
+	case event := <-watcher.Events:
+		if event.Type != watch.EventTypeDelete || event.Key != "presence/a" {
+			t.Errorf("unexpected event %+v", event)
+		}
+	default:
+		t.Error("expected a delete event for the expired key")
+	}
+
+	// A key rewritten after it was found expired survives the delete
+	kv.SetWithExpiryLocal("presence/d", "up", 0, now.Add(-time.Second))
+	kv.Set("presence/d", "back")
+	if deleted := kv.DeleteExpiredLocal([]string{"presence/d"}, now); len(deleted) != 0 {
+		t.Errorf("expected rewritten key to be kept, deleted %v", deleted)
+	}
+}
+
+func TestKVStore_ExpiredKeysNotRead(t *testing.T) {
+	kv := NewKVStore()
+	kv.SetWithTTL("presence/a", "up", 0, MinKVTTL)
+	kv.Set("presence/b", "up")
+	time.Sleep(MinKVTTL + 50*time.Millisecond)
+
+	// Without a sweep the key is still stored, but no read returns it
+	if keys := kv.ExpiredKeys(time.Now()); len(keys) != 1 {
+		t.Fatalf("expected presence/a to await its deletion, got %v", keys)
+	}
+	if _, ok := kv.Get("presence/a"); ok {
+		t.Error("expected Get to skip the expired key")
+	}
+	if _, ok := kv.GetEntry("presence/a"); ok {
+		t.Error("expected GetEntry to skip the expired key")
+	}
+	if keys := kv.List(); len(keys) != 1 || keys[0] != "presence/b" {
+		t.Errorf("expected List to return only presence/b, got %v", keys)
+	}
+	if keys := kv.ListNamespace(DefaultNamespace); len(keys) != 1 {
+		t.Errorf("expected ListNamespace to return only presence/b, got %v", keys)
+	}
+	if entries := kv.ListEntries(); len(entries) != 1 {
+		t.Errorf("expected ListEntries to return only presence/b, got %v", entries)
+	}
+	if found, notFound := kv.BatchGet([]string{"presence/a", "presence/b"}); len(found) != 1 || len(notFound) != 1 {
+		t.Errorf("expected BatchGet to find only presence/b, got %v, %v", found, notFound)
+	}
+	if found, notFound := kv.BatchGetEntries([]string{"presence/a"}); len(found) != 0 || len(notFound) != 1 {
+		t.Errorf("expected BatchGetEntries not to find presence/a, got %v, %v", found, notFound)
+	}
+}
+
+func TestKVStore_TTLPersistence(t *testing.T) {
+	engine := persistence.NewMemoryEngine()
+	log := logger.GetDefault()
+
+	kv, err := NewKVStoreWithPersistence(engine, log)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	kv.SetWithTTL("ephemeral", "v", 0, time.Hour)
+	kv.Set("permanent", "v")
+
+	reloaded, _ := NewKVStoreWithPersistence(engine, log)
+	entry, ok := reloaded.GetEntry("ephemeral")
+	if !ok || entry.ExpiresAt.IsZero() {
+		t.Fatalf("expected expiring key to be reloaded with its expiry, got %+v", entry)
+	}
+
+	kv.DeleteExpired(time.Now().Add(2 * time.Hour))
+	reloaded, _ = NewKVStoreWithPersistence(engine, log)
+	if _, ok := reloaded.Get("ephemeral"); ok {
+		t.Error("expected expired key to be removed from persistence")
+	}
+	if _, ok := reloaded.Get("permanent"); !ok {
+		t.Error("expected permanent key to be kept")
+	}
+}
+
+func TestKVStore_WatchEventIndexes(t *testing.T) {
+	kv := NewKVStore()
+	wm := watch.NewManager(nil, logger.GetDefault(), 10, 0)
+	kv.SetWatchManager(wm)
+	watcher, err := wm.AddWatcher("app/*", nil, watch.TransportWebSocket, "")
+	if err != nil {
+		t.Fatalf("failed to add watcher: %v", err)
+	}
+
+	kv.Set("app/a", "1")
+	if err := kv.BatchSet(map[string]string{"app/b": "2", "app/c": "3"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		event := <-watcher.Events
+		if entry, _ := kv.GetEntry(event.Key); event.Index != entry.ModifyIndex {
+			t.Errorf("expected %s at index %d, got %d", event.Key, entry.ModifyIndex, event.Index)
+		}
+	}
+
+	kv.Delete("app/a")
+	if event := <-watcher.Events; event.Type != watch.EventTypeDelete || event.Index != kv.Index() {
+		t.Errorf("expected a delete event at index %d, got %+v", kv.Index(), event)
+	}
+
+	// Restoring the store resets the journal
+	kv.RestoreSnapshot(map[string]KVEntry{"app/a": {Value: "1", ModifyIndex: 10, CreateIndex: 10}}, 10)
+	_, replay, err := wm.ResumeWatcher("app/*", nil, watch.TransportWebSocket, "", 1)
+	if err != nil {
+		t.Fatalf("failed to resume watcher: %v", err)
+	}
+	if len(replay) != 1 || replay[0].Type != watch.EventTypeResync || replay[0].Index != 10 {
+		t.Errorf("expected a resync event at index 10, got %v", replay)
+	}
+}
@@ -0,0 +1,253 @@
+package store
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultNamespace holds everything written without a namespace. It always
+// exists and cannot be deleted.
+const DefaultNamespace = "default"
+
+// NamespaceKeyPrefix starts the storage key of every KV key and service
+// instance outside the default namespace: "_ns/<namespace>/<key>". Keys in the
+// default namespace are stored as-is, so they may not start with it.
+const NamespaceKeyPrefix = "_ns/"
+
+// Namespace names follow DNS label rules: lowercase alphanumeric and -, at
+// most 63 characters
+var namespaceNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Namespace isolates KV keys and services of one tenant from the others.
+type Namespace struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Meta        map[string]string `json:"meta,omitempty"`
+	CreateIndex uint64            `json:"create_index"`
+	ModifyIndex uint64            `json:"modify_index"`
+	CreatedAt   time.Time         `json:"created_at,omitzero"`
+}
+
+// ValidateNamespaceName checks that name is a valid namespace name.
+func ValidateNamespaceName(name string) error {
+	if !namespaceNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid namespace name: %q (allowed: lowercase alphanumeric and -, max 63 chars)", name)
+	}
+	return nil
+}
+
+// NormalizeNamespace maps the empty namespace to DefaultNamespace.
+func NormalizeNamespace(ns string) string {
+	if ns == "" {
+		return DefaultNamespace
+	}
+	return ns
+}
+
+// NamespacedKey returns the storage key of key in namespace ns. Keys in the
+// default namespace are returned unchanged.
+func NamespacedKey(ns, key string) string {
+	if ns == "" || ns == DefaultNamespace {
+		return key
+	}
+	return NamespaceKeyPrefix + ns + "/" + key
+}
+
+// SplitNamespacedKey is the inverse of NamespacedKey.
+func SplitNamespacedKey(storageKey string) (ns, key string) {
+	rest, ok := strings.CutPrefix(storageKey, NamespaceKeyPrefix)
+	if !ok {
+		return DefaultNamespace, storageKey
+	}
+	ns, key, ok = strings.Cut(rest, "/")
+	if !ok {
+		return DefaultNamespace, storageKey
+	}
+	return ns, key
+}
+
+// ValidateKeyName rejects keys that would reach into another namespace.
+func ValidateKeyName(key string) error {
+	if strings.HasPrefix(key, NamespaceKeyPrefix) {
+		return fmt.Errorf("key %q uses the reserved prefix %s", key, NamespaceKeyPrefix)
+	}
+	return nil
+}
+
+// NamespaceStore is the registry of namespaces. Like sessions, namespaces are
+// not written to the persistence engine; in clustered mode they are
+// replicated through Raft and included in its snapshots, and in standalone
+// mode Discover re-registers the namespaces found in persisted data.
+type NamespaceStore struct {
+	Data        map[string]Namespace
+	Mutex       sync.RWMutex
+	globalIndex uint64
+	kv          *KVStore
+	services    *ServiceStore
+}
+
+// NewNamespaceStore creates a namespace registry for the given stores.
+func NewNamespaceStore(kv *KVStore, services *ServiceStore) *NamespaceStore {
+	return &NamespaceStore{
+		Data:     make(map[string]Namespace),
+		kv:       kv,
+		services: services,
+	}
+}
+
+// Create registers a new namespace.
+func (s *NamespaceStore) Create(ns Namespace) (Namespace, error) {
+	if err := ValidateNamespaceName(ns.Name); err != nil {
+		return Namespace{}, err
+	}
+	if ns.Name == DefaultNamespace {
+		return Namespace{}, &NamespaceConflictError{Name: ns.Name, Reason: "already exists"}
+	}
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	if _, exists := s.Data[ns.Name]; exists {
+		return Namespace{}, &NamespaceConflictError{Name: ns.Name, Reason: "already exists"}
+	}
+
+	s.globalIndex++
+	ns.CreateIndex = s.globalIndex
+	ns.ModifyIndex = s.globalIndex
+	if ns.CreatedAt.IsZero() {
+		ns.CreatedAt = time.Now()
+	}
+	s.Data[ns.Name] = ns
+
+	return ns, nil
+}
+
+// Get returns a namespace by name. The default namespace is always found.
+func (s *NamespaceStore) Get(name string) (Namespace, bool) {
+	if name == DefaultNamespace {
+		return Namespace{Name: DefaultNamespace, Description: "Default namespace"}, true
+	}
+
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+	ns, ok := s.Data[name]
+	return ns, ok
+}
+
+// Exists reports whether a namespace exists.
+func (s *NamespaceStore) Exists(name string) bool {
+	_, ok := s.Get(name)
+	return ok
+}
+
+// List returns the default namespace followed by all others ordered by name.
+func (s *NamespaceStore) List() []Namespace {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	names := make([]string, 0, len(s.Data))
+	for name := range s.Data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	defaultNS, _ := s.Get(DefaultNamespace)
+	namespaces := make([]Namespace, 0, len(names)+1)
+	namespaces = append(namespaces, defaultNS)
+	for _, name := range names {
+		namespaces = append(namespaces, s.Data[name])
+	}
+	return namespaces
+}
+
+// Delete removes an empty namespace. Namespaces that still hold KV keys or
+// services cannot be deleted.
+func (s *NamespaceStore) Delete(name string) error {
+	if name == DefaultNamespace {
+		return &NamespaceConflictError{Name: name, Reason: "cannot be deleted"}
+	}
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	if _, ok := s.Data[name]; !ok {
+		return &NotFoundError{Type: "namespace", Key: name}
+	}
+	if s.kv != nil && len(s.kv.ListNamespace(name)) > 0 {
+		return &NamespaceConflictError{Name: name, Reason: "still holds KV keys"}
+	}
+	if s.services != nil && len(s.services.ListNamespace(name)) > 0 {
+		return &NamespaceConflictError{Name: name, Reason: "still holds services"}
+	}
+
+	delete(s.Data, name)
+	return nil
+}
+
+// Discover registers every namespace referenced by stored KV keys or
+// services that is not registered yet, and returns their names.
+func (s *NamespaceStore) Discover() []string {
+	found := make(map[string]bool)
+	if s.kv != nil {
+		for _, key := range s.kv.List() {
+			found[namespaceOf(key)] = true
+		}
+	}
+	if s.services != nil {
+		for _, entry := range s.services.ListAll() {
+			found[NormalizeNamespace(entry.Service.Namespace)] = true
+		}
+	}
+
+	var created []string
+	for name := range found {
+		if s.Exists(name) || ValidateNamespaceName(name) != nil {
+			continue
+		}
+		if _, err := s.Create(Namespace{Name: name}); err == nil {
+			created = append(created, name)
+		}
+	}
+	sort.Strings(created)
+	return created
+}
+
+// GetAllData returns a copy of all namespaces for Raft snapshotting.
+func (s *NamespaceStore) GetAllData() map[string]Namespace {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	result := make(map[string]Namespace, len(s.Data))
+	for name, ns := range s.Data {
+		result[name] = ns
+	}
+	return result
+}
+
+// RestoreFromSnapshot replaces all namespaces with the snapshot data.
+func (s *NamespaceStore) RestoreFromSnapshot(data map[string]Namespace) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	s.Data = make(map[string]Namespace, len(data))
+	var maxIndex uint64
+	for name, ns := range data {
+		s.Data[name] = ns
+		if ns.ModifyIndex > maxIndex {
+			maxIndex = ns.ModifyIndex
+		}
+	}
+	s.globalIndex = maxIndex
+
+	return nil
+}
+
+// namespaceOf returns the namespace of a storage key.
+func namespaceOf(storageKey string) string {
+	ns, _ := SplitNamespacedKey(storageKey)
+	return ns
+}
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNamespacedKey(t *testing.T) {
+	tests := []struct {
+		ns, key, storageKey string
+	}{
+		{"", "app/config", "app/config"},
+		{DefaultNamespace, "app/config", "app/config"},
+		{"team-a", "app/config", "_ns/team-a/app/config"},
+	}
+
+	for _, tt := range tests {
+		if got := NamespacedKey(tt.ns, tt.key); got != tt.storageKey {
+			t.Errorf("NamespacedKey(%q, %q) = %q, want %q", tt.ns, tt.key, got, tt.storageKey)
+		}
+		ns, key := SplitNamespacedKey(tt.storageKey)
+		if ns != NormalizeNamespace(tt.ns) || key != tt.key {
+			t.Errorf("SplitNamespacedKey(%q) = %q, %q", tt.storageKey, ns, key)
+		}
+	}
+
+	if err := ValidateKeyName("_ns/team-a/secret"); err == nil {
+		t.Error("Expected reserved prefix to be rejected")
+	}
+	for _, name := range []string{"", "Team", "-team", "team_a", "a/b"} {
+		if err := ValidateNamespaceName(name); err == nil {
+			t.Errorf("Expected namespace name %q to be rejected", name)
+		}
+	}
+}
+
+func TestNamespaceStore_CreateListDelete(t *testing.T) {
+	kv := NewKVStore()
+	services := NewServiceStore()
+	namespaces := NewNamespaceStore(kv, services)
+
+	ns, err := namespaces.Create(Namespace{Name: "team-b", Description: "Team B"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if ns.CreateIndex == 0 || ns.CreatedAt.IsZero() {
+		t.Errorf("Expected index and creation time to be set, got %+v", ns)
+	}
+	if _, err := namespaces.Create(Namespace{Name: "team-a"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := namespaces.Create(Namespace{Name: "team-a"}); err == nil {
+		t.Error("Expected duplicate namespace to be rejected")
+	}
+	if _, err := namespaces.Create(Namespace{Name: DefaultNamespace}); err == nil {
+		t.Error("Expected default namespace to be rejected")
+	}
+
+	list := namespaces.List()
+	if len(list) != 3 || list[0].Name != DefaultNamespace || list[1].Name != "team-a" || list[2].Name != "team-b" {
+		t.Errorf("Unexpected namespace list: %+v", list)
+	}
+
+	// Namespaces holding data cannot be deleted
+	kv.Set(NamespacedKey("team-a", "config"), "v")
+	if err := namespaces.Delete("team-a"); err == nil {
+		t.Error("Expected delete of namespace with keys to fail")
+	}
+	kv.Delete(NamespacedKey("team-a", "config"))
+	if err := namespaces.Delete("team-a"); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+
+	if err := services.Register(Service{Name: "web", Address: "10.0.0.1", Port: 80, Namespace: "team-b"}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := namespaces.Delete("team-b"); err == nil {
+		t.Error("Expected delete of namespace with services to fail")
+	}
+
+	if err := namespaces.Delete(DefaultNamespace); err == nil {
+		t.Error("Expected delete of default namespace to fail")
+	}
+	if _, ok := namespaces.Delete("missing").(*NotFoundError); !ok {
+		t.Error("Expected NotFoundError for a missing namespace")
+	}
+}
+
+func TestNamespaceStore_Isolation(t *testing.T) {
+	kv := NewKVStore()
+	services := NewServiceStoreWithTTL(time.Minute)
+
+	kv.Set("config", "default")
+	kv.Set(NamespacedKey("team-a", "config"), "team-a")
+
+	if keys := kv.ListNamespace(DefaultNamespace); len(keys) != 1 || keys[0] != "config" {
+		t.Errorf("Unexpected default namespace keys: %v", keys)
+	}
+	if keys := kv.ListNamespace("team-a"); len(keys) != 1 || keys[0] != "config" {
+		t.Errorf("Unexpected team-a keys: %v", keys)
+	}
+
+	for _, ns := range []string{"", "team-a"} {
+		if err := services.Register(Service{Name: "web", Address: "10.0.0.1", Port: 80, Namespace: ns}); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+	}
+
+	if instances := services.ListInstances("web"); len(instances) != 1 || instances[0].Namespace != "" {
+		t.Errorf("Expected one default instance of web, got %+v", instances)
+	}
+	instances := services.ListInstances(NamespacedKey("team-a", "web"))
+	if len(instances) != 1 || instances[0].Namespace != "team-a" {
+		t.Errorf("Expected one team-a instance of web, got %+v", instances)
+	}
+	if _, ok := services.Get(NamespacedKey("team-a", "web")); !ok {
+		t.Error("Expected team-a instance to be found by its storage key")
+	}
+
+	namespaces := NewNamespaceStore(kv, services)
+	if created := namespaces.Discover(); len(created) != 1 || created[0] != "team-a" {
+		t.Errorf("Expected team-a to be discovered, got %v", created)
+	}
+}
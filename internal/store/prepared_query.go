@@ -0,0 +1,191 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/neogan74/konsul/internal/healthcheck"
+)
+
+// PreparedQuery is a named service query stored in the catalog. It bundles
+// the filters of a lookup so that clients, DNS included, can run it by name.
+type PreparedQuery struct {
+	Name        string            `json:"name"`                // DNS label: <name>.query.<domain>
+	Namespace   string            `json:"namespace,omitempty"` // Namespace of the services; empty for the default one
+	Service     string            `json:"service"`
+	Tags        []string          `json:"tags,omitempty"` // Instances must have all tags
+	Meta        map[string]string `json:"meta,omitempty"` // Instances must match all metadata
+	OnlyPassing bool              `json:"only_passing,omitempty"`
+	// Failover lists services tried in order when Service has no matching
+	// healthy instance
+	Failover    []string  `json:"failover,omitempty"`
+	CreateIndex uint64    `json:"create_index"`
+	ModifyIndex uint64    `json:"modify_index"`
+	CreatedAt   time.Time `json:"created_at,omitzero"`
+}
+
+// PreparedQueryResult is the outcome of executing a prepared query.
+type PreparedQueryResult struct {
+	Query     string    `json:"query"`
+	Service   string    `json:"service"`   // Service the instances belong to
+	Failovers int       `json:"failovers"` // Number of failover services tried
+	Instances []Service `json:"instances"`
+}
+
+// ValidatePreparedQuery checks the fields of a prepared query.
+func ValidatePreparedQuery(q PreparedQuery) error {
+	if !namespaceNameRegex.MatchString(q.Name) {
+		return fmt.Errorf("invalid prepared query name: %q (allowed: lowercase alphanumeric and -, max 63 chars)", q.Name)
+	}
+	if q.Namespace != "" {
+		if err := ValidateNamespaceName(q.Namespace); err != nil {
+			return err
+		}
+	}
+	if q.Service == "" {
+		return fmt.Errorf("prepared query %q has no service", q.Name)
+	}
+	for _, service := range q.Failover {
+		if service == "" {
+			return fmt.Errorf("prepared query %q has an empty failover service", q.Name)
+		}
+	}
+	return nil
+}
+
+// PreparedQueryStore holds the prepared queries of the catalog. Like
+// namespaces, prepared queries are not written to the persistence engine; in
+// clustered mode they are replicated through Raft and its snapshots.
+type PreparedQueryStore struct {
+	Data        map[string]PreparedQuery
+	Mutex       sync.RWMutex
+	globalIndex uint64
+	services    *ServiceStore
+}
+
+// NewPreparedQueryStore creates a prepared query store that runs its queries
+// against services.
+func NewPreparedQueryStore(services *ServiceStore) *PreparedQueryStore {
+	return &PreparedQueryStore{
+		Data:     make(map[string]PreparedQuery),
+		services: services,
+	}
+}
+
+// Set creates or replaces a prepared query and returns it as stored.
+func (s *PreparedQueryStore) Set(q PreparedQuery) (PreparedQuery, error) {
+	if err := ValidatePreparedQuery(q); err != nil {
+		return PreparedQuery{}, err
+	}
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	s.globalIndex++
+	q.CreateIndex = s.globalIndex
+	if existing, ok := s.Data[q.Name]; ok {
+		q.CreateIndex = existing.CreateIndex
+		q.CreatedAt = existing.CreatedAt
+	}
+	q.ModifyIndex = s.globalIndex
+	if q.CreatedAt.IsZero() {
+		q.CreatedAt = time.Now()
+	}
+	s.Data[q.Name] = q
+
+	return q, nil
+}
+
+// Get returns a prepared query by name.
+func (s *PreparedQueryStore) Get(name string) (PreparedQuery, bool) {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+	q, ok := s.Data[name]
+	return q, ok
+}
+
+// List returns all prepared queries ordered by name.
+func (s *PreparedQueryStore) List() []PreparedQuery {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	queries := make([]PreparedQuery, 0, len(s.Data))
+	for _, q := range s.Data {
+		queries = append(queries, q)
+	}
+	sort.Slice(queries, func(i, j int) bool { return queries[i].Name < queries[j].Name })
+	return queries
+}
+
+// Delete removes a prepared query.
+func (s *PreparedQueryStore) Delete(name string) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	if _, ok := s.Data[name]; !ok {
+		return &NotFoundError{Type: "prepared query", Key: name}
+	}
+	delete(s.Data, name)
+	return nil
+}
+
+// Execute runs a prepared query. It returns the healthy instances of the
+// query's service that match its tags and metadata or, when there are none,
+// those of the first failover service that has some.
+func (s *PreparedQueryStore) Execute(name string) (PreparedQueryResult, error) {
+	q, ok := s.Get(name)
+	if !ok {
+		return PreparedQueryResult{}, &NotFoundError{Type: "prepared query", Key: name}
+	}
+
+	statuses := DiscoveryStatuses
+	if q.OnlyPassing {
+		statuses = []healthcheck.Status{healthcheck.StatusPassing}
+	}
+
+	result := PreparedQueryResult{Query: q.Name, Service: q.Service, Instances: []Service{}}
+	for i, service := range append([]string{q.Service}, q.Failover...) {
+		instances := s.services.QueryInstances(NamespacedKey(q.Namespace, service), q.Tags, q.Meta)
+		instances = s.services.FilterByStatus(instances, statuses...)
+		if len(instances) > 0 {
+			result.Service = service
+			result.Failovers = i
+			result.Instances = instances
+			return result, nil
+		}
+	}
+	result.Failovers = len(q.Failover)
+	return result, nil
+}
+
+// GetAllData returns a copy of all prepared queries for Raft snapshotting.
+func (s *PreparedQueryStore) GetAllData() map[string]PreparedQuery {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	result := make(map[string]PreparedQuery, len(s.Data))
+	for name, q := range s.Data {
+		result[name] = q
+	}
+	return result
+}
+
+// RestoreFromSnapshot replaces all prepared queries with the snapshot data.
+func (s *PreparedQueryStore) RestoreFromSnapshot(data map[string]PreparedQuery) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	s.Data = make(map[string]PreparedQuery, len(data))
+	var maxIndex uint64
+	for name, q := range data {
+		s.Data[name] = q
+		if q.ModifyIndex > maxIndex {
+			maxIndex = q.ModifyIndex
+		}
+	}
+	s.globalIndex = maxIndex
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/neogan74/konsul/internal/healthcheck"
+)
+
+func TestPreparedQueryStore_SetGetDelete(t *testing.T) {
+	s := NewPreparedQueryStore(NewServiceStore())
+
+	if _, err := s.Set(PreparedQuery{Name: "Bad_Name", Service: "web"}); err == nil {
+		t.Error("expected an invalid name to be rejected")
+	}
+	if _, err := s.Set(PreparedQuery{Name: "web-prod"}); err == nil {
+		t.Error("expected a query without service to be rejected")
+	}
+
+	created, err := s.Set(PreparedQuery{Name: "web-prod", Service: "web", Tags: []string{"prod"}})
+	if err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if created.CreateIndex == 0 || created.CreatedAt.IsZero() {
+		t.Errorf("expected index and creation time to be set, got %+v", created)
+	}
+
+	updated, err := s.Set(PreparedQuery{Name: "web-prod", Service: "web", OnlyPassing: true})
+	if err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if updated.CreateIndex != created.CreateIndex || updated.ModifyIndex <= created.ModifyIndex {
+		t.Errorf("expected an update to keep the create index, got %+v", updated)
+	}
+	if !updated.CreatedAt.Equal(created.CreatedAt) {
+		t.Error("expected an update to keep the creation time")
+	}
+
+	if q, ok := s.Get("web-prod"); !ok || !q.OnlyPassing || len(q.Tags) != 0 {
+		t.Errorf("expected the replaced query, got %+v", q)
+	}
+	if queries := s.List(); len(queries) != 1 {
+		t.Errorf("expected 1 query, got %d", len(queries))
+	}
+
+	if err := s.Delete("web-prod"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := s.Delete("web-prod"); !IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestPreparedQueryStore_Execute(t *testing.T) {
+	services := NewServiceStore()
+	defer func() { _ = services.Close() }()
+	s := NewPreparedQueryStore(services)
+
+	register := func(svc Service) {
+		t.Helper()
+		if err := services.Register(svc); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+	}
+	register(Service{ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80, Tags: []string{"prod"}, Meta: map[string]string{"zone": "a"}})
+	register(Service{ID: "web-2", Name: "web", Address: "10.0.0.2", Port: 80, Tags: []string{"prod"}, Meta: map[string]string{"zone": "b"}})
+	register(Service{ID: "web-3", Name: "web", Address: "10.0.0.3", Port: 80, Tags: []string{"canary"}})
+	register(Service{ID: "web-dr-1", Name: "web-dr", Address: "10.1.0.1", Port: 80, Tags: []string{"prod"}, Meta: map[string]string{"zone": "a"},
+		Checks: []*healthcheck.CheckDefinition{{ID: "web-dr-1-ttl", TTL: "60s"}}})
+
+	if _, err := s.Execute("missing"); !IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+
+	if _, err := s.Set(PreparedQuery{Name: "web-prod", Service: "web", Tags: []string{"prod"}, Meta: map[string]string{"zone": "a"}, Failover: []string{"web-dr"}}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	result, err := s.Execute("web-prod")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.Service != "web" || result.Failovers != 0 || len(result.Instances) != 1 || result.Instances[0].ID != "web-1" {
+		t.Errorf("expected web-1, got %+v", result)
+	}
+
+	// Without a matching instance the failover service answers, once healthy
+	services.Deregister("web-1")
+	result, _ = s.Execute("web-prod")
+	if len(result.Instances) != 0 || result.Failovers != 1 {
+		t.Errorf("expected no instance while web-dr is critical, got %+v", result)
+	}
+	if err := services.UpdateTTLCheck("web-dr-1-ttl"); err != nil {
+		t.Fatalf("UpdateTTLCheck failed: %v", err)
+	}
+	result, _ = s.Execute("web-prod")
+	if result.Service != "web-dr" || result.Failovers != 1 || len(result.Instances) != 1 {
+		t.Errorf("expected the failover service, got %+v", result)
+	}
+}
+
+func TestServiceStore_QueryInstances(t *testing.T) {
+	s := NewServiceStore()
+	defer func() { _ = s.Close() }()
+
+	for _, svc := range []Service{
+		{Name: "api", Address: "10.0.0.1", Port: 80, Tags: []string{"prod"}},
+		{Name: "web", Address: "10.0.0.2", Port: 80, Tags: []string{"prod"}, Meta: map[string]string{"zone": "a"}},
+		{Name: "web", Namespace: "team-a", Address: "10.0.0.3", Port: 80, Tags: []string{"prod"}, Meta: map[string]string{"zone": "a"}},
+	} {
+		if err := s.Register(svc); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+	}
+
+	if found := s.QueryInstances("web", []string{"prod"}, nil); len(found) != 1 || found[0].Address != "10.0.0.2" {
+		t.Errorf("expected the default namespace web instance, got %+v", found)
+	}
+	if found := s.QueryInstances(NamespacedKey("team-a", "web"), []string{"prod"}, map[string]string{"zone": "a"}); len(found) != 1 || found[0].Address != "10.0.0.3" {
+		t.Errorf("expected the team-a web instance, got %+v", found)
+	}
+	if found := s.QueryInstances("web", []string{"canary"}, nil); len(found) != 0 {
+		t.Errorf("expected no instance, got %+v", found)
+	}
+}
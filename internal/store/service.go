@@ -3,23 +3,49 @@ package store
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"slices"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/neogan74/konsul/internal/blocking"
+	"github.com/neogan74/konsul/internal/changelog"
 	"github.com/neogan74/konsul/internal/healthcheck"
 	"github.com/neogan74/konsul/internal/logger"
 	"github.com/neogan74/konsul/internal/persistence"
+	"github.com/neogan74/konsul/internal/watch"
 )
 
-// Service represents a service registered in the service store
+// Service represents a single service instance registered in the service store.
+// Several instances may share a Name; each one is identified by its ID.
 type Service struct {
-	Name    string                         `json:"name"`
-	Address string                         `json:"address"`
-	Port    int                            `json:"port"`
-	Tags    []string                       `json:"tags,omitempty"` // Service tags for filtering and categorization
-	Meta    map[string]string              `json:"meta,omitempty"` // Service metadata (key-value pairs)
-	Checks  []*healthcheck.CheckDefinition `json:"checks,omitempty"`
+	ID        string                         `json:"id,omitempty"` // Instance ID (defaults to Name when empty)
+	Name      string                         `json:"name"`
+	Namespace string                         `json:"namespace,omitempty"` // Empty for the default namespace
+	Node      string                         `json:"node,omitempty"`      // Node the instance runs on
+	Address   string                         `json:"address"`
+	Port      int                            `json:"port"`
+	Tags      []string                       `json:"tags,omitempty"` // Service tags for filtering and categorization
+	Meta      map[string]string              `json:"meta,omitempty"` // Service metadata (key-value pairs)
+	Checks    []*healthcheck.CheckDefinition `json:"checks,omitempty"`
+}
+
+// InstanceID returns the ID the instance is stored under.
+// Services registered without an explicit ID use their name, so
+// single-instance deployments keep addressing services by name.
+func (s Service) InstanceID() string {
+	if s.ID != "" {
+		return s.ID
+	}
+	return s.Name
+}
+
+// StorageKey returns the key the instance is stored under: its instance ID,
+// qualified with the namespace outside the default one.
+func (s Service) StorageKey() string {
+	return NamespacedKey(s.Namespace, s.InstanceID())
 }
 
 // ServiceEntry represents a service entry in the service store
@@ -32,27 +58,37 @@ type ServiceEntry struct {
 
 // ServiceStore represents the service store
 type ServiceStore struct {
-	Data          map[string]ServiceEntry
-	TagIndex      map[string]map[string]bool     // Tag → {ServiceName: true} - for fast tag queries
-	MetaIndex     map[string]map[string][]string // MetaKey → {MetaValue: [ServiceNames]} - for fast metadata queries
+	Data          map[string]ServiceEntry        // InstanceID → entry
+	NameIndex     map[string]map[string]bool     // ServiceName → {InstanceID: true} - for instance lookups by name
+	TagIndex      map[string]map[string]bool     // Tag → {InstanceID: true} - for fast tag queries
+	MetaIndex     map[string]map[string][]string // MetaKey → {MetaValue: [InstanceIDs]} - for fast metadata queries
 	Mutex         sync.RWMutex
 	globalIndex   uint64 // Monotonically increasing global index
 	TTL           time.Duration
 	engine        persistence.Engine
 	log           logger.Logger
 	healthManager *healthcheck.Manager
+	watchManager  *watch.Manager    // Notified of check status changes
+	changes       blocking.Notifier // Wakes blocking queries when the index changes
+	changeLog     *changelog.Log    // Instances changed at recent indexes, for delta syncs
+
+	replicatedExpiry bool // Instances are expired through the Raft log, see EnableReplicatedExpiry
+
+	snapshots []*snapshotView[ServiceEntry, ServiceRecord] // Open Raft snapshots
 }
 
 // NewServiceStore creates a new service store
 func NewServiceStore() *ServiceStore {
 	return &ServiceStore{
 		Data:          make(map[string]ServiceEntry),
+		NameIndex:     make(map[string]map[string]bool),
 		TagIndex:      make(map[string]map[string]bool),
 		MetaIndex:     make(map[string]map[string][]string),
 		globalIndex:   0,
 		TTL:           30 * time.Second, // default TTL
 		log:           logger.GetDefault(),
 		healthManager: healthcheck.NewManager(logger.GetDefault()),
+		changeLog:     changelog.New(changelog.DefaultCapacity),
 	}
 }
 
@@ -60,12 +96,14 @@ func NewServiceStore() *ServiceStore {
 func NewServiceStoreWithTTL(ttl time.Duration) *ServiceStore {
 	return &ServiceStore{
 		Data:          make(map[string]ServiceEntry),
+		NameIndex:     make(map[string]map[string]bool),
 		TagIndex:      make(map[string]map[string]bool),
 		MetaIndex:     make(map[string]map[string][]string),
 		globalIndex:   0,
 		TTL:           ttl,
 		log:           logger.GetDefault(),
 		healthManager: healthcheck.NewManager(logger.GetDefault()),
+		changeLog:     changelog.New(changelog.DefaultCapacity),
 	}
 }
 
@@ -73,6 +111,7 @@ func NewServiceStoreWithTTL(ttl time.Duration) *ServiceStore {
 func NewServiceStoreWithPersistence(ttl time.Duration, engine persistence.Engine, log logger.Logger) (*ServiceStore, error) {
 	store := &ServiceStore{
 		Data:          make(map[string]ServiceEntry),
+		NameIndex:     make(map[string]map[string]bool),
 		TagIndex:      make(map[string]map[string]bool),
 		MetaIndex:     make(map[string]map[string][]string),
 		globalIndex:   0,
@@ -80,6 +119,7 @@ func NewServiceStoreWithPersistence(ttl time.Duration, engine persistence.Engine
 		engine:        engine,
 		log:           log,
 		healthManager: healthcheck.NewManager(log),
+		changeLog:     changelog.New(changelog.DefaultCapacity),
 	}
 
 	// Load existing data from persistence if available
@@ -92,9 +132,13 @@ func NewServiceStoreWithPersistence(ttl time.Duration, engine persistence.Engine
 	return store, nil
 }
 
-// nextIndex atomically increments and returns the next global index
-func (s *ServiceStore) nextIndex() uint64 {
-	return atomic.AddUint64(&s.globalIndex, 1)
+// nextIndex atomically increments and returns the next global index, recording
+// it as a change of the instance id. Callers must hold s.Mutex.
+func (s *ServiceStore) nextIndex(id string) uint64 {
+	index := atomic.AddUint64(&s.globalIndex, 1)
+	s.changeLog.Record(index, id)
+	s.changes.Notify()
+	return index
 }
 
 // loadFromPersistence loads service data from persistence
@@ -110,11 +154,11 @@ func (s *ServiceStore) loadFromPersistence() error {
 
 	loaded := 0
 	var maxIndex uint64
-	for _, name := range services {
-		data, err := s.engine.GetService(name)
+	for _, id := range services {
+		data, err := s.engine.GetService(id)
 		if err != nil {
 			s.log.Warn("Failed to load service from persistence",
-				logger.String("service", name),
+				logger.String("service_id", id),
 				logger.Error(err))
 			continue
 		}
@@ -122,11 +166,16 @@ func (s *ServiceStore) loadFromPersistence() error {
 		var entry ServiceEntry
 		if err := json.Unmarshal(data, &entry); err != nil {
 			s.log.Warn("Failed to unmarshal service data",
-				logger.String("service", name),
+				logger.String("service_id", id),
 				logger.Error(err))
 			continue
 		}
 
+		// Entries persisted before instance IDs existed are keyed by name
+		if entry.Service.ID == "" {
+			entry.Service.ID = id
+		}
+
 		// Migrate old entries without indices
 		if entry.ModifyIndex == 0 {
 			entry.ModifyIndex = 1
@@ -135,10 +184,9 @@ func (s *ServiceStore) loadFromPersistence() error {
 
 		// Only load non-expired services
 		if entry.ExpiresAt.After(time.Now()) {
-			s.Data[name] = entry
+			s.putEntry(id, entry)
 			// Rebuild indexes for loaded services
-			s.addToTagIndex(name, entry.Service.Tags)
-			s.addToMetaIndex(name, entry.Service.Meta)
+			s.addToIndexes(id, entry.Service)
 			if entry.ModifyIndex > maxIndex {
 				maxIndex = entry.ModifyIndex
 			}
@@ -147,7 +195,7 @@ func (s *ServiceStore) loadFromPersistence() error {
 	}
 
 	// Set global index to max found index
-	s.globalIndex = maxIndex
+	s.resetIndex(maxIndex)
 
 	s.log.Info("Loaded service data from persistence",
 		logger.Int("services", loaded),
@@ -164,18 +212,19 @@ func (s *ServiceStore) Register(service Service) error {
 			logger.Error(err))
 		return err
 	}
+	service.ID = service.InstanceID()
+	key := service.StorageKey()
 
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	// Remove old indexes if service exists (for re-registration)
-	oldEntry, existed := s.Data[service.Name]
+	// Remove old indexes if the instance exists (for re-registration)
+	oldEntry, existed := s.Data[key]
 	if existed {
-		s.removeFromTagIndex(service.Name, oldEntry.Service.Tags)
-		s.removeFromMetaIndex(service.Name, oldEntry.Service.Meta)
+		s.removeFromIndexes(key, oldEntry.Service)
 	}
 
-	newIndex := s.nextIndex()
+	newIndex := s.nextIndex(key)
 	entry := ServiceEntry{
 		Service:     service,
 		ExpiresAt:   time.Now().Add(s.TTL),
@@ -186,22 +235,22 @@ func (s *ServiceStore) Register(service Service) error {
 	} else {
 		entry.CreateIndex = newIndex
 	}
-	s.Data[service.Name] = entry
+	s.putEntry(key, entry)
 
-	// Add to tag and metadata indexes
-	s.addToTagIndex(service.Name, service.Tags)
-	s.addToMetaIndex(service.Name, service.Meta)
+	// Add to name, tag and metadata indexes
+	s.addToIndexes(key, service)
+	s.notifyService(watch.EventTypeRegister, service, newIndex)
 
 	// Register health checks
 	for _, checkDef := range service.Checks {
 		// Set service ID for the check
 		if checkDef.ServiceID == "" {
-			checkDef.ServiceID = service.Name
+			checkDef.ServiceID = key
 		}
 
 		// Set check name if not provided
 		if checkDef.Name == "" {
-			checkDef.Name = fmt.Sprintf("%s-health", service.Name)
+			checkDef.Name = fmt.Sprintf("%s-health", service.ID)
 		}
 
 		_, err := s.healthManager.AddCheck(checkDef)
@@ -223,7 +272,7 @@ func (s *ServiceStore) Register(service Service) error {
 			return err
 		}
 
-		if err := s.engine.SetService(service.Name, data, s.TTL); err != nil {
+		if err := s.engine.SetService(key, data, s.TTL); err != nil {
 			s.log.Error("Failed to persist service",
 				logger.String("service", service.Name),
 				logger.Error(err))
@@ -233,6 +282,7 @@ func (s *ServiceStore) Register(service Service) error {
 
 	s.log.Info("Service registered with tags/metadata",
 		logger.String("service", service.Name),
+		logger.String("service_id", service.ID),
 		logger.Int("tags", len(service.Tags)),
 		logger.Int("metadata_keys", len(service.Meta)))
 
@@ -251,18 +301,20 @@ func (s *ServiceStore) RegisterCAS(service Service, expectedIndex uint64) (uint6
 			logger.Error(err))
 		return 0, err
 	}
+	service.ID = service.InstanceID()
+	key := service.StorageKey()
 
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	oldEntry, existed := s.Data[service.Name]
+	oldEntry, existed := s.Data[key]
 
 	// Check CAS condition
 	if expectedIndex == 0 {
 		// Create only if not exists
 		if existed {
 			return 0, &CASConflictError{
-				Key:           service.Name,
+				Key:           service.ID,
 				ExpectedIndex: 0,
 				CurrentIndex:  oldEntry.ModifyIndex,
 				OperationType: "service",
@@ -271,11 +323,11 @@ func (s *ServiceStore) RegisterCAS(service Service, expectedIndex uint64) (uint6
 	} else {
 		// Update only if index matches
 		if !existed {
-			return 0, &NotFoundError{Type: "service", Key: service.Name}
+			return 0, &NotFoundError{Type: "service", Key: service.ID}
 		}
 		if oldEntry.ModifyIndex != expectedIndex {
 			return 0, &CASConflictError{
-				Key:           service.Name,
+				Key:           service.ID,
 				ExpectedIndex: expectedIndex,
 				CurrentIndex:  oldEntry.ModifyIndex,
 				OperationType: "service",
@@ -283,13 +335,12 @@ func (s *ServiceStore) RegisterCAS(service Service, expectedIndex uint64) (uint6
 		}
 	}
 
-	// Remove old indexes if service exists
+	// Remove old indexes if the instance exists
 	if existed {
-		s.removeFromTagIndex(service.Name, oldEntry.Service.Tags)
-		s.removeFromMetaIndex(service.Name, oldEntry.Service.Meta)
+		s.removeFromIndexes(key, oldEntry.Service)
 	}
 
-	newIndex := s.nextIndex()
+	newIndex := s.nextIndex(key)
 	entry := ServiceEntry{
 		Service:     service,
 		ExpiresAt:   time.Now().Add(s.TTL),
@@ -300,22 +351,22 @@ func (s *ServiceStore) RegisterCAS(service Service, expectedIndex uint64) (uint6
 	} else {
 		entry.CreateIndex = newIndex
 	}
-	s.Data[service.Name] = entry
+	s.putEntry(key, entry)
 
-	// Add to tag and metadata indexes
-	s.addToTagIndex(service.Name, service.Tags)
-	s.addToMetaIndex(service.Name, service.Meta)
+	// Add to name, tag and metadata indexes
+	s.addToIndexes(key, service)
+	s.notifyService(watch.EventTypeRegister, service, newIndex)
 
 	// Register health checks
 	for _, checkDef := range service.Checks {
 		// Set service ID for the check
 		if checkDef.ServiceID == "" {
-			checkDef.ServiceID = service.Name
+			checkDef.ServiceID = key
 		}
 
 		// Set check name if not provided
 		if checkDef.Name == "" {
-			checkDef.Name = fmt.Sprintf("%s-health", service.Name)
+			checkDef.Name = fmt.Sprintf("%s-health", service.ID)
 		}
 
 		_, err := s.healthManager.AddCheck(checkDef)
@@ -337,7 +388,7 @@ func (s *ServiceStore) RegisterCAS(service Service, expectedIndex uint64) (uint6
 			return newIndex, err
 		}
 
-		if err := s.engine.SetService(service.Name, data, s.TTL); err != nil {
+		if err := s.engine.SetService(key, data, s.TTL); err != nil {
 			s.log.Error("Failed to persist service",
 				logger.String("service", service.Name),
 				logger.Error(err))
@@ -347,12 +398,13 @@ func (s *ServiceStore) RegisterCAS(service Service, expectedIndex uint64) (uint6
 
 	s.log.Info("Service registered with CAS",
 		logger.String("service", service.Name),
+		logger.String("service_id", service.ID),
 		logger.String("new_index", fmt.Sprintf("%d", newIndex)))
 
 	return newIndex, nil
 }
 
-// List returns a list of all non-expired services
+// List returns a list of all non-expired service instances
 func (s *ServiceStore) List() []Service {
 	s.Mutex.RLock()
 	defer s.Mutex.RUnlock()
@@ -360,14 +412,26 @@ func (s *ServiceStore) List() []Service {
 	services := make([]Service, 0, len(s.Data))
 	now := time.Now()
 	for _, entry := range s.Data {
-		if entry.ExpiresAt.After(now) {
+		if s.live(entry, now) {
 			services = append(services, entry.Service)
 		}
 	}
 	return services
 }
 
-// ListAll returns a list of all services, including expired ones
+// ListNamespace returns the non-expired service instances of namespace ns
+func (s *ServiceStore) ListNamespace(ns string) []Service {
+	ns = NormalizeNamespace(ns)
+	services := make([]Service, 0)
+	for _, service := range s.List() {
+		if NormalizeNamespace(service.Namespace) == ns {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+// ListAll returns a list of all service instances, including expired ones
 func (s *ServiceStore) ListAll() []ServiceEntry {
 	s.Mutex.RLock()
 	defer s.Mutex.RUnlock()
@@ -379,36 +443,68 @@ func (s *ServiceStore) ListAll() []ServiceEntry {
 	return entries
 }
 
-// Get returns a service by name
-func (s *ServiceStore) Get(name string) (Service, bool) {
+// ListInstances returns all non-expired instances of a service, ordered by instance ID
+func (s *ServiceStore) ListInstances(name string) []Service {
+	entries := s.ListInstanceEntries(name)
+	services := make([]Service, 0, len(entries))
+	for _, entry := range entries {
+		services = append(services, entry.Service)
+	}
+	return services
+}
+
+// ListInstanceEntries returns the entries of all non-expired instances of a service,
+// ordered by instance ID
+func (s *ServiceStore) ListInstanceEntries(name string) []ServiceEntry {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	ids := make([]string, 0, len(s.NameIndex[name]))
+	for id := range s.NameIndex[name] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	now := time.Now()
+	entries := make([]ServiceEntry, 0, len(ids))
+	for _, id := range ids {
+		if entry, ok := s.Data[id]; ok && s.live(entry, now) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Get returns a service instance by ID
+func (s *ServiceStore) Get(id string) (Service, bool) {
 	s.Mutex.RLock()
 	defer s.Mutex.RUnlock()
 
-	entry, ok := s.Data[name]
-	if !ok || entry.ExpiresAt.Before(time.Now()) {
+	entry, ok := s.Data[id]
+	if !ok || !s.live(entry, time.Now()) {
 		return Service{}, false
 	}
 	return entry.Service, true
 }
 
-// GetEntry returns the full ServiceEntry with version information
-func (s *ServiceStore) GetEntry(name string) (ServiceEntry, bool) {
+// GetEntry returns the full ServiceEntry of an instance with version information
+func (s *ServiceStore) GetEntry(id string) (ServiceEntry, bool) {
 	s.Mutex.RLock()
 	defer s.Mutex.RUnlock()
 
-	entry, ok := s.Data[name]
-	if !ok || entry.ExpiresAt.Before(time.Now()) {
+	entry, ok := s.Data[id]
+	if !ok || !s.live(entry, time.Now()) {
 		return ServiceEntry{}, false
 	}
 	return entry, true
 }
 
-// Heartbeat extends the TTL of a service
-func (s *ServiceStore) Heartbeat(name string) bool {
+// Heartbeat extends the TTL of a service instance
+func (s *ServiceStore) Heartbeat(id string) bool {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	entry, ok := s.Data[name]
+	entry, ok := s.Data[id]
 	if !ok {
 		return false
 	}
@@ -416,21 +512,21 @@ func (s *ServiceStore) Heartbeat(name string) bool {
 	// Update TTL but preserve indices
 	entry.ExpiresAt = time.Now().Add(s.TTL)
 	// Heartbeat is not a modification, so don't update ModifyIndex
-	s.Data[name] = entry
+	s.putEntry(id, entry)
 
 	// Update in persistence if engine is available
 	if s.engine != nil {
 		data, err := json.Marshal(entry)
 		if err != nil {
 			s.log.Error("Failed to marshal service entry",
-				logger.String("service", name),
+				logger.String("service_id", id),
 				logger.Error(err))
 			return true
 		}
 
-		if err := s.engine.SetService(name, data, s.TTL); err != nil {
+		if err := s.engine.SetService(id, data, s.TTL); err != nil {
 			s.log.Error("Failed to update service heartbeat in persistence",
-				logger.String("service", name),
+				logger.String("service_id", id),
 				logger.Error(err))
 		}
 	}
@@ -438,44 +534,42 @@ func (s *ServiceStore) Heartbeat(name string) bool {
 	return true
 }
 
-// Deregister removes a service by name
-func (s *ServiceStore) Deregister(name string) {
+// Deregister removes a service instance by ID
+func (s *ServiceStore) Deregister(id string) {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
 	// Remove from indexes before deleting
-	if entry, exists := s.Data[name]; exists {
-		s.removeFromTagIndex(name, entry.Service.Tags)
-		s.removeFromMetaIndex(name, entry.Service.Meta)
+	if entry, exists := s.Data[id]; exists {
+		s.removeFromIndexes(id, entry.Service)
+		s.notifyService(watch.EventTypeDeregister, entry.Service, s.deleteEntry(id))
 	}
 
-	delete(s.Data, name)
-
 	// Delete from persistence if engine is available
 	if s.engine != nil {
-		if err := s.engine.DeleteService(name); err != nil {
+		if err := s.engine.DeleteService(id); err != nil {
 			s.log.Error("Failed to delete service from persistence",
-				logger.String("service", name),
+				logger.String("service_id", id),
 				logger.Error(err))
 		}
 	}
 }
 
 // DeregisterCAS performs a Compare-And-Swap deregistration operation
-// It will only deregister the service if the current ModifyIndex matches the expected index
+// It will only deregister the instance if the current ModifyIndex matches the expected index
 // Returns error on conflict
-func (s *ServiceStore) DeregisterCAS(name string, expectedIndex uint64) error {
+func (s *ServiceStore) DeregisterCAS(id string, expectedIndex uint64) error {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	entry, existed := s.Data[name]
+	entry, existed := s.Data[id]
 	if !existed {
-		return &NotFoundError{Type: "service", Key: name}
+		return &NotFoundError{Type: "service", Key: id}
 	}
 
 	if entry.ModifyIndex != expectedIndex {
 		return &CASConflictError{
-			Key:           name,
+			Key:           id,
 			ExpectedIndex: expectedIndex,
 			CurrentIndex:  entry.ModifyIndex,
 			OperationType: "service",
@@ -483,29 +577,27 @@ func (s *ServiceStore) DeregisterCAS(name string, expectedIndex uint64) error {
 	}
 
 	// Remove from indexes before deleting
-	s.removeFromTagIndex(name, entry.Service.Tags)
-	s.removeFromMetaIndex(name, entry.Service.Meta)
-
-	delete(s.Data, name)
+	s.removeFromIndexes(id, entry.Service)
+	s.notifyService(watch.EventTypeDeregister, entry.Service, s.deleteEntry(id))
 
 	// Delete from persistence if engine is available
 	if s.engine != nil {
-		if err := s.engine.DeleteService(name); err != nil {
+		if err := s.engine.DeleteService(id); err != nil {
 			s.log.Error("Failed to delete service from persistence",
-				logger.String("service", name),
+				logger.String("service_id", id),
 				logger.Error(err))
 			return err
 		}
 	}
 
 	s.log.Info("Service deregistered with CAS",
-		logger.String("service", name),
+		logger.String("service_id", id),
 		logger.String("index", fmt.Sprintf("%d", expectedIndex)))
 
 	return nil
 }
 
-// CleanupExpired removes expired services
+// CleanupExpired removes expired service instances
 func (s *ServiceStore) CleanupExpired() int {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
@@ -514,25 +606,23 @@ func (s *ServiceStore) CleanupExpired() int {
 	count := 0
 	expiredServices := make([]string, 0)
 
-	for name, entry := range s.Data {
+	for id, entry := range s.Data {
 		if !entry.ExpiresAt.Before(now) {
 			continue
 		}
 		// Remove from indexes before deleting
-		s.removeFromTagIndex(name, entry.Service.Tags)
-		s.removeFromMetaIndex(name, entry.Service.Meta)
-
-		delete(s.Data, name)
-		expiredServices = append(expiredServices, name)
+		s.removeFromIndexes(id, entry.Service)
+		s.notifyService(watch.EventTypeDeregister, entry.Service, s.deleteEntry(id))
+		expiredServices = append(expiredServices, id)
 		count++
 	}
 
 	// Delete expired services from persistence
 	if s.engine != nil && len(expiredServices) > 0 {
-		for _, name := range expiredServices {
-			if err := s.engine.DeleteService(name); err != nil {
+		for _, id := range expiredServices {
+			if err := s.engine.DeleteService(id); err != nil {
 				s.log.Error("Failed to delete expired service from persistence",
-					logger.String("service", name),
+					logger.String("service_id", id),
 					logger.Error(err))
 			}
 		}
@@ -541,23 +631,61 @@ func (s *ServiceStore) CleanupExpired() int {
 	return count
 }
 
-// GetHealthChecks returns all health checks for a service
+// GetHealthChecks returns all health checks for a service, across all of its instances
 func (s *ServiceStore) GetHealthChecks(serviceName string) []*healthcheck.Check {
+	s.Mutex.RLock()
+	ids := make(map[string]bool, len(s.NameIndex[serviceName])+1)
+	ids[serviceName] = true
+	for id := range s.NameIndex[serviceName] {
+		ids[id] = true
+	}
+	s.Mutex.RUnlock()
+
 	checks := s.healthManager.ListChecks()
 	var serviceChecks []*healthcheck.Check
 	for _, check := range checks {
-		if check.ServiceID == serviceName {
+		if ids[check.ServiceID] {
 			serviceChecks = append(serviceChecks, check)
 		}
 	}
 	return serviceChecks
 }
 
+// EnableScriptChecks allows or forbids script checks in service
+// registrations. Script checks run commands on the server and are forbidden
+// by default.
+func (s *ServiceStore) EnableScriptChecks(enabled bool) {
+	s.healthManager.EnableScriptChecks(enabled)
+}
+
+// ValidateChecks checks the health check definitions of a registration,
+// including whether script checks are allowed.
+func (s *ServiceStore) ValidateChecks(checks []*healthcheck.CheckDefinition) error {
+	for _, def := range checks {
+		if err := def.Validate(); err != nil {
+			return fmt.Errorf("check %q: %w", def.Name, err)
+		}
+		if def.Type() == healthcheck.CheckTypeScript && !s.healthManager.ScriptChecksEnabled() {
+			return fmt.Errorf("check %q: %w", def.Name, healthcheck.ErrScriptChecksDisabled)
+		}
+	}
+	return nil
+}
+
 // GetAllHealthChecks returns all health checks
 func (s *ServiceStore) GetAllHealthChecks() []*healthcheck.Check {
 	return s.healthManager.ListChecks()
 }
 
+// CheckStatus returns the current status of a health check by ID
+func (s *ServiceStore) CheckStatus(checkID string) (healthcheck.Status, bool) {
+	check, ok := s.healthManager.GetCheck(checkID)
+	if !ok {
+		return "", false
+	}
+	return check.Status, true
+}
+
 // UpdateTTLCheck updates a TTL-based health check
 func (s *ServiceStore) UpdateTTLCheck(checkID string) error {
 	return s.healthManager.UpdateTTLCheck(checkID)
@@ -581,11 +709,14 @@ func (s *ServiceStore) Close() error {
 // ServiceDataSnapshot represents service data for Raft commands and snapshots.
 // This is decoupled from internal Service struct to avoid circular dependencies.
 type ServiceDataSnapshot struct {
-	Name    string            `json:"name"`
-	Address string            `json:"address"`
-	Port    int               `json:"port"`
-	Tags    []string          `json:"tags,omitempty"`
-	Meta    map[string]string `json:"meta,omitempty"`
+	ID        string            `json:"id,omitempty"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace,omitempty"`
+	Node      string            `json:"node,omitempty"`
+	Address   string            `json:"address"`
+	Port      int               `json:"port"`
+	Tags      []string          `json:"tags,omitempty"`
+	Meta      map[string]string `json:"meta,omitempty"`
 }
 
 // ServiceEntrySnapshot represents service entry data for Raft snapshots.
@@ -602,11 +733,14 @@ type ServiceEntrySnapshot struct {
 func (s *ServiceStore) RegisterLocal(serviceData ServiceDataSnapshot) error {
 	// Convert to internal Service type
 	service := Service{
-		Name:    serviceData.Name,
-		Address: serviceData.Address,
-		Port:    serviceData.Port,
-		Tags:    serviceData.Tags,
-		Meta:    serviceData.Meta,
+		ID:        serviceData.ID,
+		Name:      serviceData.Name,
+		Namespace: serviceData.Namespace,
+		Node:      serviceData.Node,
+		Address:   serviceData.Address,
+		Port:      serviceData.Port,
+		Tags:      serviceData.Tags,
+		Meta:      serviceData.Meta,
 		// Note: Checks are not included - they are local to each node
 	}
 
@@ -617,18 +751,19 @@ func (s *ServiceStore) RegisterLocal(serviceData ServiceDataSnapshot) error {
 			logger.Error(err))
 		return err
 	}
+	service.ID = service.InstanceID()
+	key := service.StorageKey()
 
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	// Remove old indexes if service exists (for re-registration)
-	oldEntry, existed := s.Data[service.Name]
+	// Remove old indexes if the instance exists (for re-registration)
+	oldEntry, existed := s.Data[key]
 	if existed {
-		s.removeFromTagIndex(service.Name, oldEntry.Service.Tags)
-		s.removeFromMetaIndex(service.Name, oldEntry.Service.Meta)
+		s.removeFromIndexes(key, oldEntry.Service)
 	}
 
-	newIndex := s.nextIndex()
+	newIndex := s.nextIndex(key)
 	entry := ServiceEntry{
 		Service:     service,
 		ExpiresAt:   time.Now().Add(s.TTL),
@@ -639,11 +774,11 @@ func (s *ServiceStore) RegisterLocal(serviceData ServiceDataSnapshot) error {
 	} else {
 		entry.CreateIndex = newIndex
 	}
-	s.Data[service.Name] = entry
+	s.putEntry(key, entry)
 
-	// Add to tag and metadata indexes
-	s.addToTagIndex(service.Name, service.Tags)
-	s.addToMetaIndex(service.Name, service.Meta)
+	// Add to name, tag and metadata indexes
+	s.addToIndexes(key, service)
+	s.notifyService(watch.EventTypeRegister, service, newIndex)
 
 	s.log.Debug("Service registered via Raft",
 		logger.String("service", service.Name),
@@ -652,33 +787,34 @@ func (s *ServiceStore) RegisterLocal(serviceData ServiceDataSnapshot) error {
 	return nil
 }
 
-// DeregisterLocal removes a service without persisting the deletion.
+// DeregisterLocal removes a service instance without persisting the deletion.
 // This is used by Raft FSM when applying committed log entries.
-func (s *ServiceStore) DeregisterLocal(name string) {
+func (s *ServiceStore) DeregisterLocal(id string) {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
 	// Remove from indexes before deleting
-	if entry, exists := s.Data[name]; exists {
-		s.removeFromTagIndex(name, entry.Service.Tags)
-		s.removeFromMetaIndex(name, entry.Service.Meta)
+	if entry, exists := s.Data[id]; exists {
+		s.removeFromIndexes(id, entry.Service)
+		s.notifyService(watch.EventTypeDeregister, entry.Service, s.deleteEntry(id))
 	}
 
-	delete(s.Data, name)
-
 	s.log.Debug("Service deregistered via Raft",
-		logger.String("service", name))
+		logger.String("service_id", id))
 }
 
 // RegisterCASLocal performs Compare-And-Swap registration without persistence.
 func (s *ServiceStore) RegisterCASLocal(serviceData ServiceDataSnapshot, expectedIndex uint64) (uint64, error) {
 	// Convert to internal Service type
 	service := Service{
-		Name:    serviceData.Name,
-		Address: serviceData.Address,
-		Port:    serviceData.Port,
-		Tags:    serviceData.Tags,
-		Meta:    serviceData.Meta,
+		ID:        serviceData.ID,
+		Name:      serviceData.Name,
+		Namespace: serviceData.Namespace,
+		Node:      serviceData.Node,
+		Address:   serviceData.Address,
+		Port:      serviceData.Port,
+		Tags:      serviceData.Tags,
+		Meta:      serviceData.Meta,
 	}
 
 	// Validate service
@@ -688,17 +824,19 @@ func (s *ServiceStore) RegisterCASLocal(serviceData ServiceDataSnapshot, expecte
 			logger.Error(err))
 		return 0, err
 	}
+	service.ID = service.InstanceID()
+	key := service.StorageKey()
 
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	oldEntry, existed := s.Data[service.Name]
+	oldEntry, existed := s.Data[key]
 
 	// Check CAS condition
 	if expectedIndex == 0 {
 		if existed {
 			return 0, &CASConflictError{
-				Key:           service.Name,
+				Key:           service.ID,
 				ExpectedIndex: 0,
 				CurrentIndex:  oldEntry.ModifyIndex,
 				OperationType: "service",
@@ -706,11 +844,11 @@ func (s *ServiceStore) RegisterCASLocal(serviceData ServiceDataSnapshot, expecte
 		}
 	} else {
 		if !existed {
-			return 0, &NotFoundError{Type: "service", Key: service.Name}
+			return 0, &NotFoundError{Type: "service", Key: service.ID}
 		}
 		if oldEntry.ModifyIndex != expectedIndex {
 			return 0, &CASConflictError{
-				Key:           service.Name,
+				Key:           service.ID,
 				ExpectedIndex: expectedIndex,
 				CurrentIndex:  oldEntry.ModifyIndex,
 				OperationType: "service",
@@ -718,13 +856,12 @@ func (s *ServiceStore) RegisterCASLocal(serviceData ServiceDataSnapshot, expecte
 		}
 	}
 
-	// Remove old indexes if service exists
+	// Remove old indexes if the instance exists
 	if existed {
-		s.removeFromTagIndex(service.Name, oldEntry.Service.Tags)
-		s.removeFromMetaIndex(service.Name, oldEntry.Service.Meta)
+		s.removeFromIndexes(key, oldEntry.Service)
 	}
 
-	newIndex := s.nextIndex()
+	newIndex := s.nextIndex(key)
 	entry := ServiceEntry{
 		Service:     service,
 		ExpiresAt:   time.Now().Add(s.TTL),
@@ -735,11 +872,11 @@ func (s *ServiceStore) RegisterCASLocal(serviceData ServiceDataSnapshot, expecte
 	} else {
 		entry.CreateIndex = newIndex
 	}
-	s.Data[service.Name] = entry
+	s.putEntry(key, entry)
 
-	// Add to tag and metadata indexes
-	s.addToTagIndex(service.Name, service.Tags)
-	s.addToMetaIndex(service.Name, service.Meta)
+	// Add to name, tag and metadata indexes
+	s.addToIndexes(key, service)
+	s.notifyService(watch.EventTypeRegister, service, newIndex)
 
 	s.log.Debug("Service registered via Raft CAS",
 		logger.String("service", service.Name),
@@ -749,18 +886,18 @@ func (s *ServiceStore) RegisterCASLocal(serviceData ServiceDataSnapshot, expecte
 }
 
 // DeregisterCASLocal performs Compare-And-Swap deregistration without persistence.
-func (s *ServiceStore) DeregisterCASLocal(name string, expectedIndex uint64) error {
+func (s *ServiceStore) DeregisterCASLocal(id string, expectedIndex uint64) error {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	entry, existed := s.Data[name]
+	entry, existed := s.Data[id]
 	if !existed {
-		return &NotFoundError{Type: "service", Key: name}
+		return &NotFoundError{Type: "service", Key: id}
 	}
 
 	if entry.ModifyIndex != expectedIndex {
 		return &CASConflictError{
-			Key:           name,
+			Key:           id,
 			ExpectedIndex: expectedIndex,
 			CurrentIndex:  entry.ModifyIndex,
 			OperationType: "service",
@@ -768,25 +905,23 @@ func (s *ServiceStore) DeregisterCASLocal(name string, expectedIndex uint64) err
 	}
 
 	// Remove from indexes before deleting
-	s.removeFromTagIndex(name, entry.Service.Tags)
-	s.removeFromMetaIndex(name, entry.Service.Meta)
-
-	delete(s.Data, name)
+	s.removeFromIndexes(id, entry.Service)
+	s.notifyService(watch.EventTypeDeregister, entry.Service, s.deleteEntry(id))
 
 	s.log.Debug("Service deregistered via Raft CAS",
-		logger.String("service", name),
+		logger.String("service_id", id),
 		logger.String("index", fmt.Sprintf("%d", expectedIndex)))
 
 	return nil
 }
 
-// GetEntrySnapshot returns a snapshot of a service entry.
-func (s *ServiceStore) GetEntrySnapshot(name string) (ServiceEntrySnapshot, bool) {
+// GetEntrySnapshot returns a snapshot of a service instance entry.
+func (s *ServiceStore) GetEntrySnapshot(id string) (ServiceEntrySnapshot, bool) {
 	s.Mutex.RLock()
 	defer s.Mutex.RUnlock()
 
-	entry, ok := s.Data[name]
-	if !ok || entry.ExpiresAt.Before(time.Now()) {
+	entry, ok := s.Data[id]
+	if !ok || !s.live(entry, time.Now()) {
 		return ServiceEntrySnapshot{}, false
 	}
 
@@ -808,11 +943,14 @@ func (s *ServiceStore) GetEntrySnapshot(name string) (ServiceEntrySnapshot, bool
 
 	return ServiceEntrySnapshot{
 		Service: ServiceDataSnapshot{
-			Name:    entry.Service.Name,
-			Address: entry.Service.Address,
-			Port:    entry.Service.Port,
-			Tags:    tags,
-			Meta:    meta,
+			ID:        entry.Service.ID,
+			Name:      entry.Service.Name,
+			Namespace: entry.Service.Namespace,
+			Node:      entry.Service.Node,
+			Address:   entry.Service.Address,
+			Port:      entry.Service.Port,
+			Tags:      tags,
+			Meta:      meta,
 		},
 		ExpiresAt:   entry.ExpiresAt,
 		ModifyIndex: entry.ModifyIndex,
@@ -820,20 +958,20 @@ func (s *ServiceStore) GetEntrySnapshot(name string) (ServiceEntrySnapshot, bool
 	}, true
 }
 
-// HeartbeatLocal updates service TTL without persisting.
+// HeartbeatLocal updates a service instance TTL without persisting.
 // This is used by Raft FSM when applying committed log entries.
-func (s *ServiceStore) HeartbeatLocal(name string) bool {
+func (s *ServiceStore) HeartbeatLocal(id string) bool {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	entry, ok := s.Data[name]
+	entry, ok := s.Data[id]
 	if !ok {
 		return false
 	}
 
 	// Update TTL but preserve indices
 	entry.ExpiresAt = time.Now().Add(s.TTL)
-	s.Data[name] = entry
+	s.putEntry(id, entry)
 
 	return true
 }
@@ -845,87 +983,163 @@ func (s *ServiceStore) GetAllData() map[string]ServiceEntrySnapshot {
 	defer s.Mutex.RUnlock()
 
 	result := make(map[string]ServiceEntrySnapshot, len(s.Data))
-	for name, entry := range s.Data {
-		// Deep copy tags
-		var tags []string
-		if len(entry.Service.Tags) > 0 {
-			tags = make([]string, len(entry.Service.Tags))
-			copy(tags, entry.Service.Tags)
-		}
-
-		// Deep copy meta
-		var meta map[string]string
-		if len(entry.Service.Meta) > 0 {
-			meta = make(map[string]string, len(entry.Service.Meta))
-			for k, v := range entry.Service.Meta {
-				meta[k] = v
-			}
-		}
+	for id, entry := range s.Data {
+		result[id] = snapshotServiceEntry(entry)
+	}
+	return result
+}
 
-		result[name] = ServiceEntrySnapshot{
-			Service: ServiceDataSnapshot{
-				Name:    entry.Service.Name,
-				Address: entry.Service.Address,
-				Port:    entry.Service.Port,
-				Tags:    tags,
-				Meta:    meta,
-			},
-			ExpiresAt:   entry.ExpiresAt,
-			ModifyIndex: entry.ModifyIndex,
-			CreateIndex: entry.CreateIndex,
+// ServiceRecord is a service instance of a Raft snapshot.
+type ServiceRecord struct {
+	ID    string               `json:"id"`
+	Entry ServiceEntrySnapshot `json:"entry"`
+}
+
+// OpenSnapshot returns deep copies of the service records as they are now,
+// for Raft snapshotting. They are read from the store chunk by chunk while
+// it keeps changing, like the records of KVStore.OpenSnapshot.
+func (s *ServiceStore) OpenSnapshot() RecordReader[ServiceRecord] {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	var view *snapshotView[ServiceEntry, ServiceRecord]
+	view = newSnapshotView(s.Data, &s.Mutex, serviceRecord, func() {
+		s.snapshots = slices.DeleteFunc(s.snapshots, func(v *snapshotView[ServiceEntry, ServiceRecord]) bool {
+			return v == view
+		})
+	})
+	s.snapshots = append(s.snapshots, view)
+	return view
+}
+
+func serviceRecord(id string, entry ServiceEntry) ServiceRecord {
+	return ServiceRecord{ID: id, Entry: snapshotServiceEntry(entry)}
+}
+
+// putEntry stores entry under id. Callers must hold s.Mutex and update the
+// indexes.
+func (s *ServiceStore) putEntry(id string, entry ServiceEntry) {
+	for _, view := range s.snapshots {
+		view.save(s.Data, id)
+	}
+	s.Data[id] = entry
+}
+
+// snapshotServiceEntry deep copies entry for a Raft snapshot.
+func snapshotServiceEntry(entry ServiceEntry) ServiceEntrySnapshot {
+	// Deep copy tags
+	var tags []string
+	if len(entry.Service.Tags) > 0 {
+		tags = make([]string, len(entry.Service.Tags))
+		copy(tags, entry.Service.Tags)
+	}
+
+	// Deep copy meta
+	var meta map[string]string
+	if len(entry.Service.Meta) > 0 {
+		meta = make(map[string]string, len(entry.Service.Meta))
+		for k, v := range entry.Service.Meta {
+			meta[k] = v
 		}
 	}
-	return result
+
+	return ServiceEntrySnapshot{
+		Service: ServiceDataSnapshot{
+			ID:        entry.Service.ID,
+			Name:      entry.Service.Name,
+			Namespace: entry.Service.Namespace,
+			Node:      entry.Service.Node,
+			Address:   entry.Service.Address,
+			Port:      entry.Service.Port,
+			Tags:      tags,
+			Meta:      meta,
+		},
+		ExpiresAt:   entry.ExpiresAt,
+		ModifyIndex: entry.ModifyIndex,
+		CreateIndex: entry.CreateIndex,
+	}
 }
 
 // RestoreFromSnapshot restores service data from a Raft snapshot.
 // This replaces all existing data with the snapshot data.
 // Note: Health checks are NOT restored - they should be re-registered locally.
 func (s *ServiceStore) RestoreFromSnapshot(data map[string]ServiceEntrySnapshot) error {
-	s.Mutex.Lock()
-	defer s.Mutex.Unlock()
-
-	// Clear existing data and indexes
-	s.Data = make(map[string]ServiceEntry, len(data))
-	s.TagIndex = make(map[string]map[string]bool)
-	s.MetaIndex = make(map[string]map[string][]string)
+	records := make([]ServiceRecord, 0, len(data))
+	for id, entry := range data {
+		records = append(records, ServiceRecord{ID: id, Entry: entry})
+	}
+	commit, err := s.PrepareRestore(singleChunk(records))
+	if err != nil {
+		return err
+	}
+	commit()
+	return nil
+}
 
-	// Restore from snapshot
+// PrepareRestore builds the service data of the records returned by next,
+// which returns io.EOF after the last chunk. The returned commit function
+// replaces all service data with it; the store keeps its data if next fails
+// or commit is never called.
+// Note: Health checks are NOT restored - they should be re-registered locally.
+func (s *ServiceStore) PrepareRestore(next func() ([]ServiceRecord, error)) (func(), error) {
+	data := make(map[string]ServiceEntry)
 	var maxIndex uint64
-	for name, snapshot := range data {
-		// Convert snapshot to internal types
-		service := Service{
-			Name:    snapshot.Service.Name,
-			Address: snapshot.Service.Address,
-			Port:    snapshot.Service.Port,
-			Tags:    snapshot.Service.Tags,
-			Meta:    snapshot.Service.Meta,
+	for {
+		records, err := next()
+		if err == io.EOF {
+			break
 		}
-
-		entry := ServiceEntry{
-			Service:     service,
-			ExpiresAt:   snapshot.ExpiresAt,
-			ModifyIndex: snapshot.ModifyIndex,
-			CreateIndex: snapshot.CreateIndex,
+		if err != nil {
+			return nil, err
 		}
+		for _, record := range records {
+			// Convert snapshot to internal types
+			snapshot := record.Entry
+			service := Service{
+				ID:        snapshot.Service.ID,
+				Name:      snapshot.Service.Name,
+				Namespace: snapshot.Service.Namespace,
+				Node:      snapshot.Service.Node,
+				Address:   snapshot.Service.Address,
+				Port:      snapshot.Service.Port,
+				Tags:      snapshot.Service.Tags,
+				Meta:      snapshot.Service.Meta,
+			}
 
-		s.Data[name] = entry
-
-		// Rebuild indexes
-		s.addToTagIndex(name, service.Tags)
-		s.addToMetaIndex(name, service.Meta)
+			// Snapshots taken before instance IDs existed are keyed by name
+			if service.ID == "" {
+				service.ID = record.ID
+			}
 
-		if snapshot.ModifyIndex > maxIndex {
-			maxIndex = snapshot.ModifyIndex
+			data[record.ID] = ServiceEntry{
+				Service:     service,
+				ExpiresAt:   snapshot.ExpiresAt,
+				ModifyIndex: snapshot.ModifyIndex,
+				CreateIndex: snapshot.CreateIndex,
+			}
+			maxIndex = max(maxIndex, snapshot.ModifyIndex)
 		}
 	}
 
-	// Update global index to max found index
-	s.globalIndex = maxIndex
+	return func() {
+		s.Mutex.Lock()
+		defer s.Mutex.Unlock()
+
+		// Replace the data and rebuild the indexes
+		s.Data = data
+		s.snapshots = nil // They read the replaced data, which no longer changes
+		s.NameIndex = make(map[string]map[string]bool)
+		s.TagIndex = make(map[string]map[string]bool)
+		s.MetaIndex = make(map[string]map[string][]string)
+		for id, entry := range data {
+			s.addToIndexes(id, entry.Service)
+		}
 
-	s.log.Info("Service store restored from Raft snapshot",
-		logger.Int("services", len(data)),
-		logger.String("max_index", fmt.Sprintf("%d", maxIndex)))
+		// Update global index to max found index
+		s.resetIndex(maxIndex)
 
-	return nil
+		s.log.Info("Service store restored from Raft snapshot",
+			logger.Int("services", len(data)),
+			logger.String("max_index", fmt.Sprintf("%d", maxIndex)))
+	}, nil
 }
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"time"
+
+	"github.com/neogan74/konsul/internal/healthcheck"
+)
+
+// DiscoveryStatuses are the aggregated check statuses of the instances that
+// discovery returns by default: critical instances are left out.
+var DiscoveryStatuses = []healthcheck.Status{healthcheck.StatusPassing, healthcheck.StatusWarning}
+
+// instanceStatus returns the aggregated status of an instance from the
+// statuses returned by healthcheck.Manager.ServiceStatuses. The checks of an
+// instance are its own checks and the checks registered for its service name.
+func instanceStatus(statuses map[string]healthcheck.Status, service Service) healthcheck.Status {
+	var found []healthcheck.Status
+	if status, ok := statuses[service.StorageKey()]; ok {
+		found = append(found, status)
+	}
+	if status, ok := statuses[NamespacedKey(service.Namespace, service.Name)]; ok {
+		found = append(found, status)
+	}
+	return healthcheck.AggregateStatus(found...)
+}
+
+// InstanceStatus returns the aggregated status of the health checks of a
+// service instance. Instances without checks are passing.
+func (s *ServiceStore) InstanceStatus(service Service) healthcheck.Status {
+	return instanceStatus(s.health().ServiceStatuses(), service)
+}
+
+// statusFilter returns a function that reports whether an instance's
+// aggregated check status is one of statuses.
+func (s *ServiceStore) statusFilter(statuses []healthcheck.Status) func(Service) bool {
+	checkStatuses := s.health().ServiceStatuses()
+	return func(service Service) bool {
+		status := instanceStatus(checkStatuses, service)
+		for _, allowed := range statuses {
+			if status == allowed {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FilterByStatus returns the instances among services whose aggregated
+// check status is one of statuses.
+func (s *ServiceStore) FilterByStatus(services []Service, statuses ...healthcheck.Status) []Service {
+	matches := s.statusFilter(statuses)
+	filtered := make([]Service, 0, len(services))
+	for _, service := range services {
+		if matches(service) {
+			filtered = append(filtered, service)
+		}
+	}
+	return filtered
+}
+
+// FilterEntriesByStatus is FilterByStatus for service entries.
+func (s *ServiceStore) FilterEntriesByStatus(entries []ServiceEntry, statuses ...healthcheck.Status) []ServiceEntry {
+	matches := s.statusFilter(statuses)
+	filtered := make([]ServiceEntry, 0, len(entries))
+	for _, entry := range entries {
+		if matches(entry.Service) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// CheckHistory returns the recent results of a health check, oldest first.
+func (s *ServiceStore) CheckHistory(checkID string) ([]healthcheck.HistoryEntry, bool) {
+	return s.health().History(checkID)
+}
+
+// CriticalInstances returns the IDs of the registered instances with a check
+// critical for longer than its deregister_critical_service_after at now.
+func (s *ServiceStore) CriticalInstances(now time.Time) []string {
+	ids := s.health().CriticalServices(now)
+
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	instances := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := s.Data[id]; ok {
+			instances = append(instances, id)
+		}
+	}
+	return instances
+}
+
+// RemoveHealthChecks removes the health checks of the instance id, so that
+// they do not outlive its deregistration.
+func (s *ServiceStore) RemoveHealthChecks(id string) int {
+	return s.health().RemoveServiceChecks(id)
+}
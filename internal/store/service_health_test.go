@@ -0,0 +1,121 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/neogan74/konsul/internal/healthcheck"
+)
+
+func TestServiceStore_FilterByStatus(t *testing.T) {
+	s := NewServiceStore()
+	defer func() { _ = s.Close() }()
+
+	register := func(id string, checks ...*healthcheck.CheckDefinition) {
+		t.Helper()
+		if err := s.Register(Service{ID: id, Name: "web", Address: "10.0.0.1", Port: 80, Checks: checks}); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+	}
+	register("web-unchecked")
+	register("web-passing", &healthcheck.CheckDefinition{ID: "web-passing-ttl", TTL: "60s"})
+	register("web-critical", &healthcheck.CheckDefinition{ID: "web-critical-ttl", TTL: "60s"})
+	if err := s.UpdateTTLCheck("web-passing-ttl"); err != nil {
+		t.Fatalf("UpdateTTLCheck failed: %v", err)
+	}
+
+	instances := s.ListInstances("web")
+	ids := func(services []Service) map[string]bool {
+		found := make(map[string]bool)
+		for _, svc := range services {
+			found[svc.ID] = true
+		}
+		return found
+	}
+
+	healthy := ids(s.FilterByStatus(instances, DiscoveryStatuses...))
+	if len(healthy) != 2 || !healthy["web-unchecked"] || !healthy["web-passing"] {
+		t.Errorf("expected unchecked and passing instances, got %v", healthy)
+	}
+	critical := ids(s.FilterByStatus(instances, healthcheck.StatusCritical))
+	if len(critical) != 1 || !critical["web-critical"] {
+		t.Errorf("expected only the critical instance, got %v", critical)
+	}
+
+	if status := s.InstanceStatus(Service{ID: "web-critical", Name: "web"}); status != healthcheck.StatusCritical {
+		t.Errorf("expected critical, got %s", status)
+	}
+
+	// A check registered for the service name applies to every instance
+	if _, err := s.health().AddCheck(&healthcheck.CheckDefinition{ID: "web-shared", ServiceID: "web", TTL: "60s"}); err != nil {
+		t.Fatalf("AddCheck failed: %v", err)
+	}
+	if healthy := s.FilterByStatus(instances, DiscoveryStatuses...); len(healthy) != 0 {
+		t.Errorf("expected the critical service check to fail all instances, got %v", ids(healthy))
+	}
+
+	entries := s.FilterEntriesByStatus(s.ListInstanceEntries("web"), healthcheck.StatusCritical)
+	if len(entries) != 3 {
+		t.Errorf("expected 3 critical entries, got %d", len(entries))
+	}
+}
+
+func TestServiceStore_ValidateChecks(t *testing.T) {
+	s := NewServiceStore()
+	defer func() { _ = s.Close() }()
+
+	script := []*healthcheck.CheckDefinition{{Name: "script", Args: []string{"true"}}}
+	if err := s.ValidateChecks(script); !errors.Is(err, healthcheck.ErrScriptChecksDisabled) {
+		t.Errorf("expected script checks to be rejected by default, got %v", err)
+	}
+	if err := s.ValidateChecks([]*healthcheck.CheckDefinition{{Name: "http", HTTP: "http://x", BodyMatch: "("}}); err == nil {
+		t.Error("expected an invalid body regex to be rejected")
+	}
+
+	s.EnableScriptChecks(true)
+	if err := s.ValidateChecks(script); err != nil {
+		t.Errorf("expected script checks to be accepted once enabled, got %v", err)
+	}
+
+	// Restoring a snapshot keeps the setting
+	s.RestoreSnapshot(map[string]ServiceEntry{}, 1)
+	if err := s.ValidateChecks(script); err != nil {
+		t.Errorf("expected script checks to stay enabled after a restore, got %v", err)
+	}
+}
+
+func TestServiceStore_CriticalInstances(t *testing.T) {
+	s := NewServiceStore()
+	defer func() { _ = s.Close() }()
+
+	for _, id := range []string{"web-1", "web-2"} {
+		check := &healthcheck.CheckDefinition{ID: id + "-ttl", TTL: "1h", DeregisterCriticalServiceAfter: "1m"}
+		if err := s.Register(Service{ID: id, Name: "web", Address: "10.0.0.1", Port: 80, Checks: []*healthcheck.CheckDefinition{check}}); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+	}
+	if err := s.UpdateTTLCheck("web-2-ttl"); err != nil {
+		t.Fatalf("UpdateTTLCheck failed: %v", err)
+	}
+
+	later := time.Now().Add(2 * time.Minute)
+	if ids := s.CriticalInstances(time.Now()); len(ids) != 0 {
+		t.Errorf("expected no instance critical for long enough, got %v", ids)
+	}
+	ids := s.CriticalInstances(later)
+	if len(ids) != 1 || ids[0] != "web-1" {
+		t.Fatalf("expected [web-1], got %v", ids)
+	}
+
+	s.Deregister(ids[0])
+	if removed := s.RemoveHealthChecks(ids[0]); removed != 1 {
+		t.Errorf("expected 1 check removed, got %d", removed)
+	}
+	if ids := s.CriticalInstances(later); len(ids) != 0 {
+		t.Errorf("expected the deregistered instance to be gone, got %v", ids)
+	}
+	if history, ok := s.CheckHistory("web-2-ttl"); !ok || len(history) != 1 {
+		t.Errorf("expected one result for web-2-ttl, got %v", history)
+	}
+}
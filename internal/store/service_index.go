@@ -1,5 +1,39 @@
 package store
 
+// addToIndexes adds a service instance to the name, tag and metadata indexes
+func (s *ServiceStore) addToIndexes(id string, service Service) {
+	s.addToNameIndex(NamespacedKey(service.Namespace, service.Name), id)
+	s.addToTagIndex(id, service.Tags)
+	s.addToMetaIndex(id, service.Meta)
+}
+
+// removeFromIndexes removes a service instance from the name, tag and metadata indexes
+func (s *ServiceStore) removeFromIndexes(id string, service Service) {
+	s.removeFromNameIndex(NamespacedKey(service.Namespace, service.Name), id)
+	s.removeFromTagIndex(id, service.Tags)
+	s.removeFromMetaIndex(id, service.Meta)
+}
+
+// addToNameIndex records an instance ID under its service name, qualified
+// with the namespace like storage keys
+func (s *ServiceStore) addToNameIndex(serviceName, id string) {
+	if s.NameIndex[serviceName] == nil {
+		s.NameIndex[serviceName] = make(map[string]bool)
+	}
+	s.NameIndex[serviceName][id] = true
+}
+
+// removeFromNameIndex removes an instance ID from its service name entry
+func (s *ServiceStore) removeFromNameIndex(serviceName, id string) {
+	if instances, ok := s.NameIndex[serviceName]; ok {
+		delete(instances, id)
+		// Clean up empty name entries
+		if len(instances) == 0 {
+			delete(s.NameIndex, serviceName)
+		}
+	}
+}
+
 // addToTagIndex adds a service to the tag index for all its tags
 func (s *ServiceStore) addToTagIndex(serviceName string, tags []string) {
 	for _, tag := range tags {
@@ -49,7 +49,7 @@ func (s *ServiceStore) QueryByTags(tags []string) []Service {
 	now := time.Now()
 	result := make([]Service, 0, len(names))
 	for _, name := range names {
-		if entry, ok := s.Data[name]; ok && entry.ExpiresAt.After(now) {
+		if entry, ok := s.Data[name]; ok && s.live(entry, now) {
 			result = append(result, entry.Service)
 		}
 	}
@@ -126,7 +126,7 @@ func (s *ServiceStore) QueryByMetadata(filters map[string]string) []Service {
 	now := time.Now()
 	result := make([]Service, 0, len(names))
 	for _, name := range names {
-		if entry, ok := s.Data[name]; ok && entry.ExpiresAt.After(now) {
+		if entry, ok := s.Data[name]; ok && s.live(entry, now) {
 			result = append(result, entry.Service)
 		}
 	}
@@ -162,23 +162,49 @@ func (s *ServiceStore) QueryByTagsAndMetadata(tags []string, meta map[string]str
 		return []Service{}
 	}
 
-	// Create a set of service names from tag results
+	// Create a set of storage keys from tag results
 	tagServiceSet := make(map[string]bool)
 	for _, svc := range tagServices {
-		tagServiceSet[svc.Name] = true
+		tagServiceSet[svc.StorageKey()] = true
 	}
 
 	// Filter metadata results to only include those also in tag results
 	result := make([]Service, 0)
 	for _, svc := range metaServices {
-		if tagServiceSet[svc.Name] {
+		if tagServiceSet[svc.StorageKey()] {
 			result = append(result, svc)
 		}
 	}
 
 	sort.Slice(result, func(i, j int) bool {
-		return result[i].Name < result[j].Name
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ID < result[j].ID
 	})
 
 	return result
 }
+
+// QueryInstances returns the non-expired instances of a service (a namespaced
+// name, see NamespacedKey) that have all tags and match all metadata filters,
+// ordered by instance ID
+func (s *ServiceStore) QueryInstances(name string, tags []string, meta map[string]string) []Service {
+	instances := s.ListInstances(name)
+	if len(tags) == 0 && len(meta) == 0 {
+		return instances
+	}
+
+	matches := make(map[string]bool)
+	for _, svc := range s.QueryByTagsAndMetadata(tags, meta) {
+		matches[svc.StorageKey()] = true
+	}
+
+	result := make([]Service, 0, len(instances))
+	for _, svc := range instances {
+		if matches[svc.StorageKey()] {
+			result = append(result, svc)
+		}
+	}
+	return result
+}
@@ -521,6 +521,87 @@ func TestServiceStore_RegisterOverwrite(t *testing.T) {
 	}
 }
 
+func TestServiceStore_MultipleInstances(t *testing.T) {
+	s := NewServiceStore()
+
+	instances := []Service{
+		{ID: "api-1", Name: "api", Node: "node-a", Address: "10.0.0.1", Port: 8080, Tags: []string{"http"}},
+		{ID: "api-2", Name: "api", Node: "node-b", Address: "10.0.0.2", Port: 8080, Tags: []string{"http"}},
+		{Name: "db", Address: "10.0.0.3", Port: 5432},
+	}
+	for _, svc := range instances {
+		if err := s.Register(svc); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+	}
+
+	if got := len(s.List()); got != 3 {
+		t.Errorf("expected 3 instances, got %d", got)
+	}
+
+	api := s.ListInstances("api")
+	if len(api) != 2 {
+		t.Fatalf("expected 2 api instances, got %d", len(api))
+	}
+	if api[0].ID != "api-1" || api[1].ID != "api-2" {
+		t.Errorf("expected instances ordered by ID, got %q and %q", api[0].ID, api[1].ID)
+	}
+
+	// Services without an explicit ID are stored under their name
+	db, ok := s.Get("db")
+	if !ok || db.ID != "db" {
+		t.Errorf("expected db instance with default ID, got %+v (found=%v)", db, ok)
+	}
+
+	if got := len(s.QueryByTags([]string{"http"})); got != 2 {
+		t.Errorf("expected 2 instances tagged http, got %d", got)
+	}
+
+	// Heartbeat and deregister address a single instance
+	if !s.Heartbeat("api-2") {
+		t.Error("expected heartbeat for api-2 to succeed")
+	}
+	if s.Heartbeat("api") {
+		t.Error("expected heartbeat by service name to fail when instances have IDs")
+	}
+
+	s.Deregister("api-1")
+	api = s.ListInstances("api")
+	if len(api) != 1 || api[0].ID != "api-2" {
+		t.Errorf("expected only api-2 to remain, got %+v", api)
+	}
+	if got := len(s.QueryByTags([]string{"http"})); got != 1 {
+		t.Errorf("expected 1 instance tagged http after deregister, got %d", got)
+	}
+
+	s.Deregister("api-2")
+	if len(s.ListInstances("api")) != 0 {
+		t.Error("expected no api instances left")
+	}
+	if _, ok := s.NameIndex["api"]; ok {
+		t.Error("expected empty name index entry to be cleaned up")
+	}
+}
+
+func TestServiceStore_SnapshotRoundTripInstances(t *testing.T) {
+	s := NewServiceStore()
+	_ = s.Register(Service{ID: "api-1", Name: "api", Node: "node-a", Address: "10.0.0.1", Port: 8080})
+	_ = s.Register(Service{ID: "api-2", Name: "api", Node: "node-b", Address: "10.0.0.2", Port: 8080})
+
+	restored := NewServiceStore()
+	if err := restored.RestoreFromSnapshot(s.GetAllData()); err != nil {
+		t.Fatalf("RestoreFromSnapshot failed: %v", err)
+	}
+
+	api := restored.ListInstances("api")
+	if len(api) != 2 {
+		t.Fatalf("expected 2 restored instances, got %d", len(api))
+	}
+	if api[0].Node != "node-a" || api[1].Node != "node-b" {
+		t.Errorf("expected nodes to survive the snapshot, got %q and %q", api[0].Node, api[1].Node)
+	}
+}
+
 func TestServiceStore_ListEmptyStore(t *testing.T) {
 	s := NewServiceStore()
 
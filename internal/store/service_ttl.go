@@ -0,0 +1,82 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"github.com/neogan74/konsul/internal/watch"
+)
+
+// EnableReplicatedExpiry makes instances stay listed once their TTL has run
+// out, until they are removed through the Raft log. In a cluster heartbeats
+// only reach the leader, so the deadlines tracked by the other nodes say
+// nothing about whether an instance is alive; the leader expires instances
+// with ExpiredInstances and ExpireLocal instead.
+func (s *ServiceStore) EnableReplicatedExpiry(enabled bool) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	s.replicatedExpiry = enabled
+}
+
+// live returns true if entry is listed at now. Callers must hold s.Mutex.
+func (s *ServiceStore) live(entry ServiceEntry, now time.Time) bool {
+	return s.replicatedExpiry || entry.ExpiresAt.After(now)
+}
+
+// ExpiredInstances returns the instances whose TTL has run out at now, with
+// the ModifyIndex they were found expired at, by storage key.
+func (s *ServiceStore) ExpiredInstances(now time.Time) map[string]uint64 {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	expired := make(map[string]uint64)
+	for id, entry := range s.Data {
+		if entry.ExpiresAt.Before(now) {
+			expired[id] = entry.ModifyIndex
+		}
+	}
+	return expired
+}
+
+// ExpireLocal removes the given instances that are still at the ModifyIndex
+// they were found expired at, without persisting. Instances registered again
+// since are kept. Returns the removed instances in sorted order.
+// This is used by Raft FSM when applying committed log entries.
+func (s *ServiceStore) ExpireLocal(instances map[string]uint64) []string {
+	ids := make([]string, 0, len(instances))
+	for id := range instances {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	var expired []string
+	for _, id := range ids {
+		entry, ok := s.Data[id]
+		if !ok || entry.ModifyIndex != instances[id] {
+			continue
+		}
+		s.removeFromIndexes(id, entry.Service)
+		s.notifyService(watch.EventTypeDeregister, entry.Service, s.deleteEntry(id))
+		expired = append(expired, id)
+	}
+	return expired
+}
+
+// ExtendTTLLocal pushes the deadline of every instance to at least the TTL
+// plus grace from now. A node that becomes the Raft leader calls it before
+// expiring anything, as the heartbeats were sent to the previous leader.
+func (s *ServiceStore) ExtendTTLLocal(grace time.Duration) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	deadline := time.Now().Add(s.TTL + grace)
+	for id, entry := range s.Data {
+		if entry.ExpiresAt.Before(deadline) {
+			entry.ExpiresAt = deadline
+			s.putEntry(id, entry)
+		}
+	}
+}
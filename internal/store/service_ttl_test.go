@@ -0,0 +1,72 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestServiceStore_ReplicatedExpiry(t *testing.T) {
+	s := NewServiceStoreWithTTL(50 * time.Millisecond)
+	s.EnableReplicatedExpiry(true)
+	if err := s.RegisterLocal(ServiceDataSnapshot{ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80}); err != nil {
+		t.Fatalf("RegisterLocal failed: %v", err)
+	}
+	if err := s.RegisterLocal(ServiceDataSnapshot{ID: "web-2", Name: "web", Address: "10.0.0.2", Port: 80}); err != nil {
+		t.Fatalf("RegisterLocal failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	// Past their TTL, instances stay listed until expired through the log
+	if _, ok := s.Get("web-1"); !ok {
+		t.Fatalf("expected web-1 to stay listed")
+	}
+	if got := len(s.ListInstances("web")); got != 2 {
+		t.Fatalf("expected 2 instances, got %d", got)
+	}
+
+	expired := s.ExpiredInstances(time.Now())
+	if len(expired) != 2 {
+		t.Fatalf("expected 2 expired instances, got %v", expired)
+	}
+
+	// web-2 registers again before the expiry is applied and is kept
+	if err := s.RegisterLocal(ServiceDataSnapshot{ID: "web-2", Name: "web", Address: "10.0.0.2", Port: 81}); err != nil {
+		t.Fatalf("RegisterLocal failed: %v", err)
+	}
+	removed := s.ExpireLocal(expired)
+	if len(removed) != 1 || removed[0] != "web-1" {
+		t.Fatalf("expected only web-1 to be removed, got %v", removed)
+	}
+	if _, ok := s.Get("web-1"); ok {
+		t.Errorf("expected web-1 to be removed")
+	}
+	if svc, ok := s.Get("web-2"); !ok || svc.Port != 81 {
+		t.Errorf("expected web-2 to be kept, got %+v", svc)
+	}
+}
+
+func TestServiceStore_ExtendTTLLocal(t *testing.T) {
+	s := NewServiceStoreWithTTL(50 * time.Millisecond)
+	if err := s.RegisterLocal(ServiceDataSnapshot{ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80}); err != nil {
+		t.Fatalf("RegisterLocal failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if len(s.ExpiredInstances(time.Now())) != 1 {
+		t.Fatalf("expected web-1 to be expired")
+	}
+
+	s.ExtendTTLLocal(time.Minute)
+	if expired := s.ExpiredInstances(time.Now()); len(expired) != 0 {
+		t.Errorf("expected no expired instance after the grace period was granted, got %v", expired)
+	}
+	entry, ok := s.GetEntry("web-1")
+	if !ok || time.Until(entry.ExpiresAt) < 59*time.Second {
+		t.Errorf("unexpected deadline %v", entry.ExpiresAt)
+	}
+
+	// Deadlines already further away are kept
+	s.ExtendTTLLocal(0)
+	if later, _ := s.GetEntry("web-1"); !later.ExpiresAt.Equal(entry.ExpiresAt) {
+		t.Errorf("expected deadline %v to be kept, got %v", entry.ExpiresAt, later.ExpiresAt)
+	}
+}
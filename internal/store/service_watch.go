@@ -0,0 +1,116 @@
+package store
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/neogan74/konsul/internal/healthcheck"
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/watch"
+)
+
+// SetWatchManager sets the watch manager notified of instances registered
+// and deregistered and of check status changes.
+func (s *ServiceStore) SetWatchManager(wm *watch.Manager) {
+	s.watchManager = wm
+	s.health().OnStatusChange(s.notifyHealthChange)
+}
+
+// serviceEvent returns the watch event of type t about an instance at the
+// service index index, or 0 for an event replaying the current state. Health
+// check definitions are left out, as they may carry credentials.
+func (s *ServiceStore) serviceEvent(t watch.EventType, service Service, index uint64) watch.Event {
+	ns := NormalizeNamespace(service.Namespace)
+	if ns == DefaultNamespace {
+		ns = ""
+	}
+	service.Checks = nil
+
+	event := watch.Event{
+		Type:      t,
+		Kind:      watch.KindService,
+		Key:       service.Name,
+		Index:     index,
+		Namespace: ns,
+		Instance:  service.InstanceID(),
+		Timestamp: time.Now().Unix(),
+	}
+	data, err := json.Marshal(service)
+	if err != nil {
+		s.log.Warn("Failed to encode service for watchers",
+			logger.String("service", service.Name),
+			logger.Error(err))
+		return event
+	}
+	event.Service = data
+	return event
+}
+
+// notifyService tells watchers that an instance was registered or
+// deregistered at index. Callers must hold s.Mutex, so that events are
+// published in index order.
+func (s *ServiceStore) notifyService(t watch.EventType, service Service, index uint64) {
+	if s.watchManager == nil {
+		return
+	}
+	s.watchManager.Notify(s.serviceEvent(t, service, index))
+}
+
+// notifyHealthChange tells watchers that a check changed status. Checks
+// registered for a service name rather than an instance concern every
+// instance of the service, and their events have no instance.
+func (s *ServiceStore) notifyHealthChange(check healthcheck.Check, previous healthcheck.Status) {
+	if s.watchManager == nil {
+		return
+	}
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	index := s.healthChangeIndex(check.ServiceID)
+	var event watch.Event
+	if entry, ok := s.Data[check.ServiceID]; ok {
+		event = s.serviceEvent(watch.EventTypeHealth, entry.Service, index)
+	} else {
+		ns, name := SplitNamespacedKey(check.ServiceID)
+		event = s.serviceEvent(watch.EventTypeHealth, Service{Name: name, Namespace: ns}, index)
+		event.Instance = ""
+		event.Service = nil
+	}
+	event.Check = check.ID
+	event.Value = string(check.Status)
+	event.OldValue = string(previous)
+	s.watchManager.Notify(event)
+}
+
+// healthChangeIndex advances the service index for a status change of a
+// check of id, an instance or a service name, and records it as a change of
+// each instance concerned. Entries keep their ModifyIndex. Callers must hold
+// s.Mutex.
+func (s *ServiceStore) healthChangeIndex(id string) uint64 {
+	if _, ok := s.Data[id]; ok {
+		return s.nextIndex(id)
+	}
+	var index uint64
+	for instance := range s.NameIndex[id] {
+		index = s.nextIndex(instance)
+	}
+	if index == 0 {
+		// No instance to record the change for
+		index = atomic.AddUint64(&s.globalIndex, 1)
+		s.changes.Notify()
+	}
+	return index
+}
+
+// RegisterEvents returns a register event for each instance of the service
+// name in namespace ns, for watchers to start from the current instances.
+func (s *ServiceStore) RegisterEvents(ns, name string) []watch.Event {
+	instances := s.ListInstances(NamespacedKey(ns, name))
+	events := make([]watch.Event, 0, len(instances))
+	for _, service := range instances {
+		events = append(events, s.serviceEvent(watch.EventTypeRegister, service, 0))
+	}
+	return events
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/neogan74/konsul/internal/healthcheck"
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/watch"
+)
+
+func nextServiceEvent(t *testing.T, watcher *watch.Watcher) watch.Event {
+	t.Helper()
+	select {
+	case event := <-watcher.Events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("expected a service event")
+		return watch.Event{}
+	}
+}
+
+func TestServiceStore_WatchEvents(t *testing.T) {
+	s := NewServiceStore()
+	defer func() { _ = s.Close() }()
+	wm := watch.NewManager(nil, logger.GetDefault(), 10, 0)
+	s.SetWatchManager(wm)
+	watcher, err := wm.AddServiceWatcher("", "web", nil, watch.TransportWebSocket, "")
+	if err != nil {
+		t.Fatalf("failed to add watcher: %v", err)
+	}
+
+	check := &healthcheck.CheckDefinition{ID: "web-1-ttl", TTL: "60s", Headers: map[string]string{"Authorization": "secret"}}
+	if err := s.Register(Service{ID: "web-1", Name: "web", Address: "10.0.0.1", Port: 80, Checks: []*healthcheck.CheckDefinition{check}}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	event := nextServiceEvent(t, watcher)
+	if event.Type != watch.EventTypeRegister || event.Kind != watch.KindService || event.Key != "web" || event.Instance != "web-1" {
+		t.Errorf("unexpected register event %+v", event)
+	}
+	var service Service
+	if err := json.Unmarshal(event.Service, &service); err != nil {
+		t.Fatalf("failed to decode the service: %v", err)
+	}
+	if service.Address != "10.0.0.1" || service.Checks != nil {
+		t.Errorf("expected the instance without its checks, got %+v", service)
+	}
+	if entry, _ := s.GetEntry("web-1"); event.Index != entry.ModifyIndex {
+		t.Errorf("expected the register event at index %d, got %d", entry.ModifyIndex, event.Index)
+	}
+
+	if err := s.UpdateTTLCheck("web-1-ttl"); err != nil {
+		t.Fatalf("UpdateTTLCheck failed: %v", err)
+	}
+	event = nextServiceEvent(t, watcher)
+	if event.Type != watch.EventTypeHealth || event.Instance != "web-1" || event.Check != "web-1-ttl" ||
+		event.Value != string(healthcheck.StatusPassing) || event.OldValue != string(healthcheck.StatusCritical) {
+		t.Errorf("unexpected health event %+v", event)
+	}
+	if event.Index != s.Index() {
+		t.Errorf("expected the health event to advance the index to %d, got %d", event.Index, s.Index())
+	}
+
+	s.Deregister("web-1")
+	if event = nextServiceEvent(t, watcher); event.Type != watch.EventTypeDeregister || event.Instance != "web-1" || event.Index != s.Index() {
+		t.Errorf("unexpected deregister event %+v", event)
+	}
+
+	// Raft apply paths
+	if err := s.RegisterLocal(ServiceDataSnapshot{ID: "web-2", Name: "web", Address: "10.0.0.2", Port: 80}); err != nil {
+		t.Fatalf("RegisterLocal failed: %v", err)
+	}
+	if event = nextServiceEvent(t, watcher); event.Type != watch.EventTypeRegister || event.Instance != "web-2" {
+		t.Errorf("unexpected register event %+v", event)
+	}
+	s.DeregisterLocal("web-2")
+	if event = nextServiceEvent(t, watcher); event.Type != watch.EventTypeDeregister || event.Instance != "web-2" {
+		t.Errorf("unexpected deregister event %+v", event)
+	}
+
+	// Other services are not watched
+	if err := s.Register(Service{Name: "db", Address: "10.0.0.3", Port: 5432}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	select {
+	case event := <-watcher.Events:
+		t.Errorf("unexpected event %+v", event)
+	default:
+	}
+}
+
+func TestServiceStore_WatchEventsAfterRestore(t *testing.T) {
+	s := NewServiceStore()
+	defer func() { _ = s.Close() }()
+	wm := watch.NewManager(nil, logger.GetDefault(), 10, 0)
+	s.SetWatchManager(wm)
+	watcher, err := wm.AddServiceWatcher("", "*", nil, watch.TransportSSE, "")
+	if err != nil {
+		t.Fatalf("failed to add watcher: %v", err)
+	}
+
+	check := &healthcheck.CheckDefinition{ID: "web-ttl", TTL: "60s"}
+	s.RestoreSnapshot(map[string]ServiceEntry{"web": {Service: Service{Name: "web", Address: "10.0.0.1", Port: 80, Checks: []*healthcheck.CheckDefinition{check}}}}, 1)
+	if err := s.UpdateTTLCheck("web-ttl"); err != nil {
+		t.Fatalf("UpdateTTLCheck failed: %v", err)
+	}
+	if event := nextServiceEvent(t, watcher); event.Type != watch.EventTypeHealth || event.Check != "web-ttl" {
+		t.Errorf("unexpected event %+v", event)
+	}
+}
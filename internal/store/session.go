@@ -0,0 +1,347 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neogan74/konsul/internal/healthcheck"
+)
+
+// SessionBehavior controls what happens to locks held by a session when it is
+// destroyed or invalidated.
+type SessionBehavior string
+
+const (
+	// SessionBehaviorRelease releases held locks and keeps the keys
+	SessionBehaviorRelease SessionBehavior = "release"
+	// SessionBehaviorDelete deletes keys whose lock the session holds
+	SessionBehaviorDelete SessionBehavior = "delete"
+)
+
+// Session TTL limits
+const (
+	MinSessionTTL = 10 * time.Second
+	MaxSessionTTL = 24 * time.Hour
+)
+
+// Session is a lease that can hold locks on KV keys. It is invalidated when
+// its TTL runs out without a renewal or when one of its linked health checks
+// turns critical.
+type Session struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name,omitempty"`
+	Node        string          `json:"node,omitempty"`
+	TTL         string          `json:"ttl,omitempty"`
+	Behavior    SessionBehavior `json:"behavior,omitempty"`
+	Checks      []string        `json:"checks,omitempty"`
+	CreateIndex uint64          `json:"create_index"`
+	ModifyIndex uint64          `json:"modify_index"`
+	ExpiresAt   time.Time       `json:"expires_at,omitempty"`
+}
+
+// CheckStatusFunc reports the current status of a health check by ID.
+type CheckStatusFunc func(checkID string) (healthcheck.Status, bool)
+
+// SessionStore keeps sessions and coordinates the KV locks they hold.
+// Sessions are not written to the persistence engine; in clustered mode they
+// are replicated through Raft and included in its snapshots.
+type SessionStore struct {
+	Data        map[string]Session
+	Mutex       sync.RWMutex
+	globalIndex uint64
+	kv          *KVStore
+}
+
+// NewSessionStore creates a session store whose locks live in kv.
+func NewSessionStore(kv *KVStore) *SessionStore {
+	return &SessionStore{
+		Data: make(map[string]Session),
+		kv:   kv,
+	}
+}
+
+// PrepareSession validates a session and fills in defaults: a generated ID
+// and the release behavior. It is called before a session is replicated so
+// that every node applies the same ID.
+func PrepareSession(session *Session) error {
+	switch session.Behavior {
+	case "":
+		session.Behavior = SessionBehaviorRelease
+	case SessionBehaviorRelease, SessionBehaviorDelete:
+	default:
+		return fmt.Errorf("invalid session behavior: %s (allowed: release, delete)", session.Behavior)
+	}
+
+	if session.TTL != "" {
+		ttl, err := time.ParseDuration(session.TTL)
+		if err != nil {
+			return fmt.Errorf("invalid session TTL: %w", err)
+		}
+		if ttl < MinSessionTTL || ttl > MaxSessionTTL {
+			return fmt.Errorf("session TTL must be between %s and %s", MinSessionTTL, MaxSessionTTL)
+		}
+	}
+
+	if session.ID == "" {
+		session.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// ValidateSessionChecks verifies that every linked health check exists and is
+// not critical, so that a new session is not invalidated right away.
+func ValidateSessionChecks(checks []string, checkStatus CheckStatusFunc) error {
+	for _, checkID := range checks {
+		status, ok := checkStatus(checkID)
+		if !ok {
+			return fmt.Errorf("health check %q not found", checkID)
+		}
+		if status == healthcheck.StatusCritical {
+			return fmt.Errorf("health check %q is critical", checkID)
+		}
+	}
+	return nil
+}
+
+// sessionTTL returns the parsed TTL of a prepared session, or 0 if it has none.
+func sessionTTL(session Session) time.Duration {
+	if session.TTL == "" {
+		return 0
+	}
+	ttl, _ := time.ParseDuration(session.TTL)
+	return ttl
+}
+
+// SessionExpiry returns when a session created or renewed now expires, or
+// the zero time if it has no TTL.
+func SessionExpiry(session Session) time.Time {
+	return KVExpiry(sessionTTL(session))
+}
+
+// Create stores a new session. The session is prepared first, so callers
+// may pass it without an ID.
+func (s *SessionStore) Create(session Session) (Session, error) {
+	if err := PrepareSession(&session); err != nil {
+		return Session{}, err
+	}
+	session.ExpiresAt = SessionExpiry(session)
+	return s.CreateLocal(session)
+}
+
+// CreateLocal stores a new session with the expiry it carries, which the
+// Raft leader computes so that every node expires the session at the same
+// moment. Sessions with a TTL but no expiry, from log entries written by
+// older versions, expire a TTL from now.
+// This is used by Raft FSM when applying committed log entries.
+func (s *SessionStore) CreateLocal(session Session) (Session, error) {
+	if err := PrepareSession(&session); err != nil {
+		return Session{}, err
+	}
+	if sessionTTL(session) == 0 {
+		session.ExpiresAt = time.Time{}
+	} else if session.ExpiresAt.IsZero() {
+		session.ExpiresAt = SessionExpiry(session)
+	}
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	if _, exists := s.Data[session.ID]; exists {
+		return Session{}, fmt.Errorf("session '%s' already exists", session.ID)
+	}
+
+	s.globalIndex++
+	session.CreateIndex = s.globalIndex
+	session.ModifyIndex = s.globalIndex
+	s.Data[session.ID] = session
+
+	return session, nil
+}
+
+// Get returns a session by ID.
+func (s *SessionStore) Get(id string) (Session, bool) {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+	session, ok := s.Data[id]
+	return session, ok
+}
+
+// List returns all sessions ordered by creation.
+func (s *SessionStore) List() []Session {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	sessions := make([]Session, 0, len(s.Data))
+	for _, session := range s.Data {
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreateIndex < sessions[j].CreateIndex
+	})
+	return sessions
+}
+
+// Renew extends the TTL of a session.
+func (s *SessionStore) Renew(id string) (Session, error) {
+	return s.RenewLocal(id, time.Time{})
+}
+
+// RenewLocal extends the TTL of a session to expiresAt, which the Raft
+// leader computes so that every node expires the session at the same moment.
+// The zero time, also found in log entries written by older versions,
+// extends it a TTL from now.
+// This is used by Raft FSM when applying committed log entries.
+func (s *SessionStore) RenewLocal(id string, expiresAt time.Time) (Session, error) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	session, ok := s.Data[id]
+	if !ok {
+		return Session{}, &NotFoundError{Type: "session", Key: id}
+	}
+
+	s.globalIndex++
+	session.ModifyIndex = s.globalIndex
+	if sessionTTL(session) > 0 {
+		if expiresAt.IsZero() {
+			expiresAt = SessionExpiry(session)
+		}
+		session.ExpiresAt = expiresAt
+	}
+	s.Data[session.ID] = session
+
+	return session, nil
+}
+
+// Destroy removes a session and releases or deletes the keys it holds,
+// depending on its behavior.
+func (s *SessionStore) Destroy(id string) error {
+	return s.destroy(id, true)
+}
+
+// DestroyLocal is Destroy without persisting the affected KV keys.
+// This is used by Raft FSM when applying committed log entries.
+func (s *SessionStore) DestroyLocal(id string) error {
+	return s.destroy(id, false)
+}
+
+func (s *SessionStore) destroy(id string, persist bool) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	session, ok := s.Data[id]
+	if !ok {
+		return &NotFoundError{Type: "session", Key: id}
+	}
+	delete(s.Data, id)
+
+	s.kv.releaseSession(id, session.Behavior == SessionBehaviorDelete, persist)
+	return nil
+}
+
+// Acquire takes the lock on key for the session and stores value.
+// Returns false if the lock is held by another session.
+func (s *SessionStore) Acquire(key, value, id string) (bool, error) {
+	return s.acquire(key, value, id, true)
+}
+
+// AcquireLocal is Acquire without persisting the KV entry.
+// This is used by Raft FSM when applying committed log entries.
+func (s *SessionStore) AcquireLocal(key, value, id string) (bool, error) {
+	return s.acquire(key, value, id, false)
+}
+
+func (s *SessionStore) acquire(key, value, id string, persist bool) (bool, error) {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	session, ok := s.Data[id]
+	if !ok {
+		return false, &NotFoundError{Type: "session", Key: id}
+	}
+
+	isLive := func(holder string) bool {
+		_, ok := s.Data[holder]
+		return ok
+	}
+	return s.kv.acquireLock(key, value, session.ID, isLive, persist), nil
+}
+
+// Release drops the session's lock on key, keeping the current value.
+// Returns false if the key is not locked by the session.
+func (s *SessionStore) Release(key, id string) (bool, error) {
+	return s.release(key, id, true)
+}
+
+// ReleaseLocal is Release without persisting the KV entry.
+// This is used by Raft FSM when applying committed log entries.
+func (s *SessionStore) ReleaseLocal(key, id string) (bool, error) {
+	return s.release(key, id, false)
+}
+
+func (s *SessionStore) release(key, id string, persist bool) (bool, error) {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	if _, ok := s.Data[id]; !ok {
+		return false, &NotFoundError{Type: "session", Key: id}
+	}
+	return s.kv.releaseLock(key, id, persist), nil
+}
+
+// Invalidated returns the IDs of sessions whose TTL has run out at now or
+// that link a health check which is critical or no longer exists.
+func (s *SessionStore) Invalidated(now time.Time, checkStatus CheckStatusFunc) []string {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	var ids []string
+	for id, session := range s.Data {
+		if !session.ExpiresAt.IsZero() && now.After(session.ExpiresAt) {
+			ids = append(ids, id)
+			continue
+		}
+		for _, checkID := range session.Checks {
+			status, ok := checkStatus(checkID)
+			if !ok || status == healthcheck.StatusCritical {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// GetAllData returns a copy of all sessions for Raft snapshotting.
+func (s *SessionStore) GetAllData() map[string]Session {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	result := make(map[string]Session, len(s.Data))
+	for id, session := range s.Data {
+		result[id] = session
+	}
+	return result
+}
+
+// RestoreFromSnapshot replaces all sessions with the snapshot data.
+func (s *SessionStore) RestoreFromSnapshot(data map[string]Session) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	s.Data = make(map[string]Session, len(data))
+	var maxIndex uint64
+	for id, session := range data {
+		s.Data[id] = session
+		if session.ModifyIndex > maxIndex {
+			maxIndex = session.ModifyIndex
+		}
+	}
+	s.globalIndex = maxIndex
+
+	return nil
+}
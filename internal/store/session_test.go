@@ -0,0 +1,216 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neogan74/konsul/internal/healthcheck"
+)
+
+func noChecks(string) (healthcheck.Status, bool) { return "", false }
+
+func TestSessionStore_CreateDefaults(t *testing.T) {
+	sessions := NewSessionStore(NewKVStore())
+
+	session, err := sessions.Create(Session{Name: "leader", TTL: "15s"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if session.ID == "" {
+		t.Fatal("Expected generated session ID")
+	}
+	if session.Behavior != SessionBehaviorRelease {
+		t.Errorf("Expected default behavior release, got %s", session.Behavior)
+	}
+	if session.ExpiresAt.IsZero() {
+		t.Error("Expected ExpiresAt to be set for a TTL session")
+	}
+
+	if _, err := sessions.Create(Session{TTL: "1s"}); err == nil {
+		t.Error("Expected TTL below minimum to be rejected")
+	}
+	if _, err := sessions.Create(Session{Behavior: "explode"}); err == nil {
+		t.Error("Expected unknown behavior to be rejected")
+	}
+	if _, err := sessions.Create(Session{ID: session.ID}); err == nil {
+		t.Error("Expected duplicate session ID to be rejected")
+	}
+}
+
+func TestSessionStore_AcquireRelease(t *testing.T) {
+	kv := NewKVStore()
+	sessions := NewSessionStore(kv)
+
+	a, _ := sessions.Create(Session{Name: "a"})
+	b, _ := sessions.Create(Session{Name: "b"})
+
+	ok, err := sessions.Acquire("locks/db", "a", a.ID)
+	if err != nil || !ok {
+		t.Fatalf("Expected a to acquire lock, got %v, %v", ok, err)
+	}
+	entry, _ := kv.GetEntry("locks/db")
+	if entry.Session != a.ID || entry.LockIndex != 1 {
+		t.Fatalf("Unexpected lock state: %+v", entry)
+	}
+
+	ok, err = sessions.Acquire("locks/db", "b", b.ID)
+	if err != nil || ok {
+		t.Fatalf("Expected b to be refused, got %v, %v", ok, err)
+	}
+	if value, _ := kv.Get("locks/db"); value != "a" {
+		t.Errorf("Expected value to stay a, got %s", value)
+	}
+
+	// Re-acquiring by the holder updates the value but not the lock index
+	if ok, _ := sessions.Acquire("locks/db", "a2", a.ID); !ok {
+		t.Fatal("Expected holder to re-acquire")
+	}
+	entry, _ = kv.GetEntry("locks/db")
+	if entry.Value != "a2" || entry.LockIndex != 1 {
+		t.Errorf("Unexpected entry after re-acquire: %+v", entry)
+	}
+
+	// Plain writes keep the lock
+	kv.Set("locks/db", "plain")
+	entry, _ = kv.GetEntry("locks/db")
+	if entry.Session != a.ID {
+		t.Errorf("Expected plain Set to keep the lock holder, got %q", entry.Session)
+	}
+
+	if ok, _ := sessions.Release("locks/db", b.ID); ok {
+		t.Error("Expected release by non-holder to fail")
+	}
+	if ok, _ := sessions.Release("locks/db", a.ID); !ok {
+		t.Fatal("Expected holder to release")
+	}
+
+	ok, _ = sessions.Acquire("locks/db", "b", b.ID)
+	if !ok {
+		t.Fatal("Expected b to acquire released lock")
+	}
+	entry, _ = kv.GetEntry("locks/db")
+	if entry.Session != b.ID || entry.LockIndex != 2 {
+		t.Errorf("Unexpected lock state after handover: %+v", entry)
+	}
+
+	if _, err := sessions.Acquire("locks/db", "x", "missing"); !IsNotFound(err) {
+		t.Errorf("Expected NotFoundError for unknown session, got %v", err)
+	}
+}
+
+func TestSessionStore_DestroyBehavior(t *testing.T) {
+	kv := NewKVStore()
+	sessions := NewSessionStore(kv)
+
+	release, _ := sessions.Create(Session{Behavior: SessionBehaviorRelease})
+	del, _ := sessions.Create(Session{Behavior: SessionBehaviorDelete})
+
+	_, _ = sessions.Acquire("keep", "v", release.ID)
+	_, _ = sessions.Acquire("drop", "v", del.ID)
+
+	if err := sessions.Destroy(release.ID); err != nil {
+		t.Fatalf("Destroy failed: %v", err)
+	}
+	if err := sessions.Destroy(del.ID); err != nil {
+		t.Fatalf("Destroy failed: %v", err)
+	}
+
+	entry, ok := kv.GetEntry("keep")
+	if !ok || entry.Session != "" {
+		t.Errorf("Expected key to remain unlocked, got %+v (exists=%v)", entry, ok)
+	}
+	if _, ok := kv.Get("drop"); ok {
+		t.Error("Expected key held by delete-behavior session to be removed")
+	}
+
+	if err := sessions.Destroy(release.ID); !IsNotFound(err) {
+		t.Errorf("Expected NotFoundError for destroyed session, got %v", err)
+	}
+}
+
+func TestSessionStore_Invalidated(t *testing.T) {
+	sessions := NewSessionStore(NewKVStore())
+
+	ttl, _ := sessions.Create(Session{TTL: "10s"})
+	linked, _ := sessions.Create(Session{Checks: []string{"web-health"}})
+	plain, _ := sessions.Create(Session{})
+
+	status := healthcheck.StatusPassing
+	checkStatus := func(id string) (healthcheck.Status, bool) {
+		if id == "web-health" {
+			return status, true
+		}
+		return "", false
+	}
+
+	if ids := sessions.Invalidated(time.Now(), checkStatus); len(ids) != 0 {
+		t.Fatalf("Expected no invalid sessions, got %v", ids)
+	}
+
+	ids := sessions.Invalidated(time.Now().Add(11*time.Second), checkStatus)
+	if len(ids) != 1 || ids[0] != ttl.ID {
+		t.Errorf("Expected expired TTL session, got %v", ids)
+	}
+
+	if _, err := sessions.Renew(ttl.ID); err != nil {
+		t.Fatalf("Renew failed: %v", err)
+	}
+
+	status = healthcheck.StatusCritical
+	ids = sessions.Invalidated(time.Now(), checkStatus)
+	if len(ids) != 1 || ids[0] != linked.ID {
+		t.Errorf("Expected session with critical check, got %v", ids)
+	}
+
+	ids = sessions.Invalidated(time.Now(), noChecks)
+	if len(ids) != 1 || ids[0] != linked.ID {
+		t.Errorf("Expected session with missing check, got %v", ids)
+	}
+	for _, id := range ids {
+		if id == plain.ID {
+			t.Error("Session without TTL or checks should never be invalidated")
+		}
+	}
+}
+
+func TestValidateSessionChecks(t *testing.T) {
+	checkStatus := func(id string) (healthcheck.Status, bool) {
+		switch id {
+		case "ok":
+			return healthcheck.StatusPassing, true
+		case "bad":
+			return healthcheck.StatusCritical, true
+		}
+		return "", false
+	}
+
+	if err := ValidateSessionChecks([]string{"ok"}, checkStatus); err != nil {
+		t.Errorf("Expected passing check to validate, got %v", err)
+	}
+	if err := ValidateSessionChecks([]string{"ok", "bad"}, checkStatus); err == nil {
+		t.Error("Expected critical check to be rejected")
+	}
+	if err := ValidateSessionChecks([]string{"missing"}, checkStatus); err == nil {
+		t.Error("Expected missing check to be rejected")
+	}
+}
+
+func TestSessionStore_SnapshotRoundTrip(t *testing.T) {
+	sessions := NewSessionStore(NewKVStore())
+	created, _ := sessions.Create(Session{Name: "snap", TTL: "30s"})
+
+	restored := NewSessionStore(NewKVStore())
+	if err := restored.RestoreFromSnapshot(sessions.GetAllData()); err != nil {
+		t.Fatalf("RestoreFromSnapshot failed: %v", err)
+	}
+
+	got, ok := restored.Get(created.ID)
+	if !ok || got.Name != "snap" {
+		t.Fatalf("Expected restored session, got %+v (exists=%v)", got, ok)
+	}
+
+	next, _ := restored.Create(Session{})
+	if next.CreateIndex <= created.ModifyIndex {
+		t.Errorf("Expected index to continue after restore, got %d", next.CreateIndex)
+	}
+}
@@ -17,7 +17,7 @@ func (kv *KVStore) SnapshotState() (entries map[string]KVEntry, index uint64) {
 	for key, entry := range kv.Data {
 		entries[key] = entry
 	}
-	return entries, kv.globalIndex
+	return entries, kv.Index()
 }
 
 // RestoreSnapshot replaces KV data with snapshot entries and resets the global index.
@@ -26,10 +26,11 @@ func (kv *KVStore) RestoreSnapshot(entries map[string]KVEntry, index uint64) {
 	defer kv.Mutex.Unlock()
 
 	kv.Data = make(map[string]KVEntry, len(entries))
+	kv.snapshots = nil
 	for key, entry := range entries {
 		kv.Data[key] = entry
 	}
-	kv.globalIndex = index
+	kv.resetIndex(index)
 }
 
 // SnapshotState returns a copy of service entries and current global index.
@@ -41,7 +42,7 @@ func (s *ServiceStore) SnapshotState() (entries map[string]ServiceEntry, index u
 	for name, entry := range s.Data {
 		entries[name] = entry
 	}
-	return entries, s.globalIndex
+	return entries, s.Index()
 }
 
 // RestoreSnapshot replaces service data with snapshot entries and resets indices.
@@ -50,18 +51,29 @@ func (s *ServiceStore) RestoreSnapshot(entries map[string]ServiceEntry, index ui
 	defer s.Mutex.Unlock()
 
 	s.Data = make(map[string]ServiceEntry, len(entries))
+	s.snapshots = nil
+	s.NameIndex = make(map[string]map[string]bool)
 	s.TagIndex = make(map[string]map[string]bool)
 	s.MetaIndex = make(map[string]map[string][]string)
 
-	for name, entry := range entries {
-		s.Data[name] = entry
-		s.addToTagIndex(name, entry.Service.Tags)
-		s.addToMetaIndex(name, entry.Service.Meta)
+	for id, entry := range entries {
+		if entry.Service.ID == "" {
+			entry.Service.ID = id
+		}
+		s.Data[id] = entry
+		s.addToIndexes(id, entry.Service)
 	}
-	s.globalIndex = index
+	s.resetIndex(index)
 
-	s.healthManager.Stop()
+	// Carry the check index over so that delta syncs notice the replacement
+	prev := s.healthManager
+	prev.Stop()
 	s.healthManager = healthcheck.NewManager(s.log)
+	s.healthManager.ResetIndex(prev.Index() + 1)
+	s.healthManager.EnableScriptChecks(prev.ScriptChecksEnabled())
+	if s.watchManager != nil {
+		s.healthManager.OnStatusChange(s.notifyHealthChange)
+	}
 
 	for name, entry := range entries {
 		for _, checkDef := range entry.Service.Checks {
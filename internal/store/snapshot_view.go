@@ -0,0 +1,144 @@
+package store
+
+import (
+	"io"
+	"iter"
+	"maps"
+	"sync"
+)
+
+// RecordReader returns the records of a Raft snapshot a chunk at a time.
+type RecordReader[R any] interface {
+	// Next returns up to n records, and io.EOF once all were returned. The
+	// records are only valid until the next call.
+	Next(n int) ([]R, error)
+
+	// Close releases the reader; it may be called before the last chunk.
+	Close()
+}
+
+// snapshotView reads the records of a store map as they were when the view
+// was opened, while the store keeps changing the map. It walks the live map
+// a chunk at a time under the store's read lock, so nothing is copied up
+// front. Before a key first changes, the store saves its record in the
+// view: the walk skips the keys saved and the records they had are returned
+// after it. A key changed after the walk returned it is thus returned twice,
+// with the same record both times.
+//
+// Go lets a map change between the steps of a range over it, as long as the
+// changes do not race with the steps: the walk may then miss the keys
+// deleted and return some of the keys added, all of which are saved.
+type snapshotView[V, R any] struct {
+	mu      *sync.RWMutex
+	record  func(key string, value V) R
+	release func() // unregisters the view from the store, under mu
+
+	next  func() (string, V, bool)
+	stop  func()
+	saved map[string]savedRecord[R] // guarded by mu until the walk is over
+
+	walked   bool
+	released bool
+	chunk    []R // reused between walk chunks
+	rest     []R // saved records left to return after the walk
+}
+
+// savedRecord is the record a key had when a view was opened.
+type savedRecord[R any] struct {
+	record  R
+	existed bool
+}
+
+// newSnapshotView opens a view of data. Callers must hold mu and register
+// the view, so that the store saves records in it before changing data.
+func newSnapshotView[V, R any](data map[string]V, mu *sync.RWMutex, record func(string, V) R, release func()) *snapshotView[V, R] {
+	next, stop := iter.Pull2(maps.All(data))
+	return &snapshotView[V, R]{
+		mu:      mu,
+		record:  record,
+		release: release,
+		next:    next,
+		stop:    stop,
+		saved:   make(map[string]savedRecord[R]),
+	}
+}
+
+// save keeps the record key has in data, unless the view saved one already.
+// Callers must hold mu and call it before changing key.
+func (v *snapshotView[V, R]) save(data map[string]V, key string) {
+	if _, ok := v.saved[key]; ok {
+		return
+	}
+	value, existed := data[key]
+	saved := savedRecord[R]{existed: existed}
+	if existed {
+		saved.record = v.record(key, value)
+	}
+	v.saved[key] = saved
+}
+
+// Next implements RecordReader.
+func (v *snapshotView[V, R]) Next(n int) ([]R, error) {
+	if !v.walked {
+		if records := v.walk(n); len(records) > 0 {
+			return records, nil
+		}
+	}
+
+	if len(v.rest) == 0 {
+		return nil, io.EOF
+	}
+	records := v.rest[:min(n, len(v.rest))]
+	v.rest = v.rest[len(records):]
+	return records, nil
+}
+
+// walk returns up to n records of the keys not changed since the view was
+// opened. Once the walk is over, the view is released and the records saved
+// are queued.
+func (v *snapshotView[V, R]) walk(n int) []R {
+	records := v.chunk[:0]
+	v.mu.RLock()
+	for len(records) < n {
+		key, value, ok := v.next()
+		if !ok {
+			v.walked = true
+			break
+		}
+		if _, changed := v.saved[key]; !changed {
+			records = append(records, v.record(key, value))
+		}
+	}
+	v.mu.RUnlock()
+	v.chunk = records
+
+	if v.walked {
+		// Once released, no more records are saved
+		v.unregister()
+		for _, saved := range v.saved {
+			if saved.existed {
+				v.rest = append(v.rest, saved.record)
+			}
+		}
+	}
+	return records
+}
+
+// Close implements RecordReader.
+func (v *snapshotView[V, R]) Close() {
+	v.unregister()
+	v.walked = true
+	v.rest = nil
+}
+
+// unregister stops the walk and releases the view from the store.
+func (v *snapshotView[V, R]) unregister() {
+	if v.released {
+		return
+	}
+	v.released = true
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.stop()
+	v.release()
+}
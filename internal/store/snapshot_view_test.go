@@ -0,0 +1,177 @@
+package store
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+)
+
+// readKVSnapshot reads all records of r in chunks of n, calling between
+// after the first chunk, and returns them by key.
+func readKVSnapshot(t *testing.T, r RecordReader[KVRecord], n int, between func()) map[string]KVEntrySnapshot {
+	t.Helper()
+	records := make(map[string]KVEntrySnapshot)
+	for first := true; ; first = false {
+		chunk, err := r.Next(n)
+		if err == io.EOF {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		for _, record := range chunk {
+			if seen, ok := records[record.Key]; ok && seen != record.Entry {
+				t.Fatalf("key %s returned twice with different records: %+v, %+v", record.Key, seen, record.Entry)
+			}
+			records[record.Key] = record.Entry
+		}
+		if first && between != nil {
+			between()
+		}
+	}
+}
+
+func TestKVStore_OpenSnapshot_PointInTime(t *testing.T) {
+	kv := NewKVStore()
+	for i := 0; i < 3000; i++ {
+		kv.SetLocal(fmt.Sprintf("key-%d", i), "v1")
+	}
+	want := kv.GetAllData()
+
+	snapshot := kv.OpenSnapshot()
+	got := readKVSnapshot(t, snapshot, 100, func() {
+		// Change keys both already read and not read yet
+		for i := 0; i < 3000; i += 3 {
+			kv.SetLocal(fmt.Sprintf("key-%d", i), "v2")
+		}
+		for i := 1; i < 3000; i += 3 {
+			kv.DeleteLocal(fmt.Sprintf("key-%d", i))
+		}
+		for i := 0; i < 100; i++ {
+			kv.SetLocal(fmt.Sprintf("new-%d", i), "v1")
+		}
+	})
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d records, got %d", len(want), len(got))
+	}
+	for key, entry := range want {
+		if got[key] != entry {
+			t.Errorf("key %s: expected %+v, got %+v", key, entry, got[key])
+		}
+	}
+	if len(kv.snapshots) != 0 {
+		t.Errorf("expected the snapshot to be released once read, %d open", len(kv.snapshots))
+	}
+}
+
+func TestKVStore_OpenSnapshot_ConcurrentWrites(t *testing.T) {
+	kv := NewKVStore()
+	for i := 0; i < 2000; i++ {
+		kv.SetLocal(fmt.Sprintf("key-%d", i), "v1")
+	}
+	want := kv.GetAllData()
+
+	snapshot := kv.OpenSnapshot()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 2000; i++ {
+			kv.SetLocal(fmt.Sprintf("key-%d", (i*7)%2000), "v2")
+			kv.DeleteLocal(fmt.Sprintf("key-%d", (i*13)%2000))
+			kv.SetLocal(fmt.Sprintf("new-%d", i), "v1")
+		}
+	}()
+	got := readKVSnapshot(t, snapshot, 10, nil)
+	wg.Wait()
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d records, got %d", len(want), len(got))
+	}
+	for key, entry := range want {
+		if got[key] != entry {
+			t.Errorf("key %s: expected %+v, got %+v", key, entry, got[key])
+		}
+	}
+}
+
+func TestKVStore_OpenSnapshot_Close(t *testing.T) {
+	kv := NewKVStore()
+	for i := 0; i < 10; i++ {
+		kv.SetLocal(fmt.Sprintf("key-%d", i), "v1")
+	}
+
+	snapshot := kv.OpenSnapshot()
+	if _, err := snapshot.Next(5); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	snapshot.Close()
+	snapshot.Close()
+
+	if len(kv.snapshots) != 0 {
+		t.Errorf("expected the snapshot to be released, %d open", len(kv.snapshots))
+	}
+	if _, err := snapshot.Next(5); err != io.EOF {
+		t.Errorf("expected io.EOF after Close, got %v", err)
+	}
+	// Writes no longer keep records for it
+	kv.SetLocal("key-0", "v2")
+}
+
+func TestKVStore_OpenSnapshot_Restore(t *testing.T) {
+	kv := NewKVStore()
+	kv.SetLocal("old", "v1")
+
+	snapshot := kv.OpenSnapshot()
+	kv.RestoreSnapshot(map[string]KVEntry{"restored": {Value: "v1", ModifyIndex: 5, CreateIndex: 5}}, 5)
+	kv.SetLocal("restored", "v2")
+
+	got := readKVSnapshot(t, snapshot, 10, nil)
+	if _, ok := got["old"]; !ok || len(got) != 1 {
+		t.Errorf("expected the snapshot to hold the data replaced, got %v", got)
+	}
+}
+
+func TestServiceStore_OpenSnapshot_PointInTime(t *testing.T) {
+	s := NewServiceStore()
+	for i := 0; i < 3; i++ {
+		if err := s.RegisterLocal(ServiceDataSnapshot{ID: fmt.Sprintf("web-%d", i), Name: "web", Address: "10.0.0.1", Port: 80 + i}); err != nil {
+			t.Fatalf("RegisterLocal failed: %v", err)
+		}
+	}
+
+	snapshot := s.OpenSnapshot()
+	if err := s.RegisterLocal(ServiceDataSnapshot{ID: "web-0", Name: "web", Address: "10.0.0.2", Port: 80}); err != nil {
+		t.Fatalf("RegisterLocal failed: %v", err)
+	}
+	if err := s.RegisterLocal(ServiceDataSnapshot{ID: "web-3", Name: "web", Address: "10.0.0.1", Port: 83}); err != nil {
+		t.Fatalf("RegisterLocal failed: %v", err)
+	}
+	s.DeregisterLocal("web-1")
+
+	got := make(map[string]ServiceEntrySnapshot)
+	for {
+		chunk, err := snapshot.Next(1)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		for _, record := range chunk {
+			got[record.ID] = record.Entry
+		}
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("expected web-0, web-1 and web-2, got %v", got)
+	}
+	if got["web-0"].Service.Address != "10.0.0.1" {
+		t.Errorf("expected web-0 as registered first, got %+v", got["web-0"].Service)
+	}
+	if _, ok := got["web-1"]; !ok {
+		t.Error("expected web-1, deregistered after the snapshot")
+	}
+}
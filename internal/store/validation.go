@@ -103,7 +103,8 @@ func isReservedMetadataKey(key string) bool {
 	return false
 }
 
-// ValidateService validates a complete service including tags and metadata
+// ValidateService validates a complete service including tags and metadata.
+// It normalizes the default namespace to empty.
 func ValidateService(service *Service) error {
 	// Basic field validation
 	if service.Name == "" {
@@ -122,6 +123,20 @@ func ValidateService(service *Service) error {
 		return fmt.Errorf("service port must be between 1 and 65535")
 	}
 
+	if err := ValidateKeyName(service.InstanceID()); err != nil {
+		return err
+	}
+
+	// The default namespace is stored as empty
+	if service.Namespace == DefaultNamespace {
+		service.Namespace = ""
+	}
+	if service.Namespace != "" {
+		if err := ValidateNamespaceName(service.Namespace); err != nil {
+			return err
+		}
+	}
+
 	// Tag validation
 	if err := ValidateTags(service.Tags); err != nil {
 		return fmt.Errorf("tag validation failed: %w", err)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 )
@@ -15,6 +16,7 @@ func (ctx *RenderContext) FuncMap() template.FuncMap {
 		"kv":     ctx.kv,
 		"kvTree": ctx.kvTree,
 		"kvList": ctx.kvList,
+		"secret": ctx.secret,
 
 		// Service discovery functions
 		"service":  ctx.service,
@@ -51,6 +53,20 @@ func (ctx *RenderContext) kv(key string) (string, error) {
 	return value, nil
 }
 
+// secret retrieves the plaintext of a secret value from the KV store
+// Usage: {{ secret "config/database/password" }}
+func (ctx *RenderContext) secret(key string) (string, error) {
+	if ctx.KVStore == nil {
+		return "", fmt.Errorf("KV store not available")
+	}
+
+	reader, ok := ctx.KVStore.(SecretReader)
+	if !ok {
+		return "", fmt.Errorf("KV store cannot read secrets")
+	}
+	return reader.Secret(key)
+}
+
 // kvTree retrieves all key-value pairs under a prefix
 // Usage: {{ range kvTree "config/" }}{{ .Key }}: {{ .Value }}{{ end }}
 func (ctx *RenderContext) kvTree(prefix string) ([]KVPair, error) {
@@ -101,13 +117,18 @@ func (ctx *RenderContext) service(name string) ([]Service, error) {
 		return nil, fmt.Errorf("service store not available")
 	}
 
-	// Get specific service
-	svc, ok := ctx.ServiceStore.Get(name)
-	if !ok {
-		return []Service{}, nil // Return empty slice if service not found
+	// Collect every instance registered under the name
+	instances := make([]Service, 0)
+	for _, svc := range ctx.ServiceStore.List() {
+		if svc.Name == name {
+			instances = append(instances, svc)
+		}
 	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].ID < instances[j].ID
+	})
 
-	return []Service{svc}, nil
+	return instances, nil
 }
 
 // services retrieves all registered services
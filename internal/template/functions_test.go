@@ -1,6 +1,7 @@
 package template
 
 import (
+	"fmt"
 	"os"
 	"testing"
 )
@@ -104,6 +105,40 @@ func TestKVFunction(t *testing.T) {
 	}
 }
 
+// mockSecretStore is a KV store that also reads secrets
+type mockSecretStore struct {
+	*MockKVStore
+	secrets map[string]string
+}
+
+func (m *mockSecretStore) Secret(key string) (string, error) {
+	val, ok := m.secrets[key]
+	if !ok {
+		return "", fmt.Errorf("key not found: %s", key)
+	}
+	return val, nil
+}
+
+func TestSecretFunction(t *testing.T) {
+	// Stores without secret support fail instead of rendering redacted values
+	ctx := &RenderContext{KVStore: NewMockKVStore()}
+	if _, err := ctx.secret("db/password"); err == nil {
+		t.Error("secret() expected error for a store without secret support")
+	}
+
+	ctx = &RenderContext{KVStore: &mockSecretStore{
+		MockKVStore: NewMockKVStore(),
+		secrets:     map[string]string{"db/password": "hunter2"},
+	}}
+	got, err := ctx.secret("db/password")
+	if err != nil || got != "hunter2" {
+		t.Errorf("secret() = %q, %v, want hunter2", got, err)
+	}
+	if _, err := ctx.secret("db/missing"); err == nil {
+		t.Error("secret() expected error for a missing key")
+	}
+}
+
 func TestKVTreeFunction(t *testing.T) {
 	kvStore := NewMockKVStore()
 	kvStore.Set("config/database/host", "localhost")
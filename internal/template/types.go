@@ -76,6 +76,12 @@ type KVStoreReader interface {
 	List() []string
 }
 
+// SecretReader is implemented by KV stores that can read the plaintext of
+// secret values, which KVStoreReader.Get returns redacted
+type SecretReader interface {
+	Secret(key string) (string, error)
+}
+
 // ServiceStoreReader interface for reading service data
 type ServiceStoreReader interface {
 	List() []Service
@@ -84,7 +90,9 @@ type ServiceStoreReader interface {
 
 // Service represents a registered service (matching store.Service)
 type Service struct {
+	ID      string `json:"id,omitempty"`
 	Name    string `json:"name"`
+	Node    string `json:"node,omitempty"`
 	Address string `json:"address"`
 	Port    int    `json:"port"`
 }
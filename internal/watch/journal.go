@@ -0,0 +1,81 @@
+package watch
+
+// DefaultJournalSize is the number of recent events of each kind a manager
+// keeps for watchers resuming from an index.
+const DefaultJournalSize = 4096
+
+// journal is a bounded, index-ordered record of the recent events of one
+// kind, replayed to watchers that resume after an index they saw. The
+// zero value is not usable; create journals with newJournal.
+type journal struct {
+	events []Event // ring buffer
+	start  int     // position of the oldest event
+	count  int
+	floor  uint64 // events at or below floor are not in the journal
+	last   uint64 // index of the newest event, or floor if there is none
+}
+
+func newJournal(size int) *journal {
+	if size <= 0 {
+		size = DefaultJournalSize
+	}
+	return &journal{events: make([]Event, size)}
+}
+
+// at returns the i-th oldest event.
+func (j *journal) at(i int) *Event {
+	return &j.events[(j.start+i)%len(j.events)]
+}
+
+// add records event, dropping the oldest event when the journal is full.
+// Stores publish events after releasing their locks, so an event may come
+// after one with a higher index; it is put in index order.
+func (j *journal) add(event Event) {
+	if event.Index <= j.floor {
+		return
+	}
+	if j.count == len(j.events) {
+		if event.Index < j.at(0).Index {
+			// Older than every event kept: it is lost like a dropped one
+			j.floor = event.Index
+			return
+		}
+		j.floor = j.at(0).Index
+		j.start = (j.start + 1) % len(j.events)
+		j.count--
+	}
+
+	i := j.count
+	for ; i > 0 && j.at(i-1).Index > event.Index; i-- {
+		*j.at(i) = *j.at(i - 1)
+	}
+	*j.at(i) = event
+	j.count++
+	j.last = max(j.last, event.Index)
+}
+
+// since returns the events after index, oldest first. ok is false when the
+// journal cannot tell what happened since: index is older than the oldest
+// event kept, or ahead of the journal because the store was reset.
+func (j *journal) since(index uint64) (events []Event, ok bool) {
+	if index < j.floor || index > j.last {
+		return nil, false
+	}
+	for i := j.count - 1; i >= 0 && j.at(i).Index > index; i-- {
+		events = append(events, *j.at(i))
+	}
+	for a, b := 0, len(events)-1; a < b; a, b = a+1, b-1 {
+		events[a], events[b] = events[b], events[a]
+	}
+	return events, true
+}
+
+// reset empties the journal after the store was replaced wholesale, for
+// example by a snapshot restore. Events up to index are unknown from then on.
+func (j *journal) reset(index uint64) {
+	clear(j.events)
+	j.start = 0
+	j.count = 0
+	j.floor = index
+	j.last = index
+}
@@ -0,0 +1,96 @@
+package watch
+
+import "testing"
+
+func journalIndexes(events []Event) []uint64 {
+	indexes := make([]uint64, 0, len(events))
+	for _, event := range events {
+		indexes = append(indexes, event.Index)
+	}
+	return indexes
+}
+
+func equalIndexes(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestJournal_Since(t *testing.T) {
+	j := newJournal(3)
+
+	if events, ok := j.since(0); !ok || len(events) != 0 {
+		t.Fatalf("expected an empty journal to cover index 0, got %v, %v", events, ok)
+	}
+
+	for _, index := range []uint64{1, 2, 3, 4} {
+		j.add(Event{Index: index})
+	}
+
+	tests := []struct {
+		since uint64
+		want  []uint64
+		ok    bool
+	}{
+		{0, nil, false}, // Event 1 was dropped
+		{1, []uint64{2, 3, 4}, true},
+		{3, []uint64{4}, true},
+		{4, []uint64{}, true},
+		{5, nil, false}, // Ahead of the journal
+	}
+	for _, tt := range tests {
+		events, ok := j.since(tt.since)
+		if ok != tt.ok {
+			t.Errorf("since(%d): expected ok %v, got %v", tt.since, tt.ok, ok)
+			continue
+		}
+		if ok && !equalIndexes(journalIndexes(events), tt.want) {
+			t.Errorf("since(%d): expected %v, got %v", tt.since, tt.want, journalIndexes(events))
+		}
+	}
+}
+
+func TestJournal_OutOfOrder(t *testing.T) {
+	j := newJournal(3)
+	for _, index := range []uint64{1, 4, 3} {
+		j.add(Event{Index: index})
+	}
+	if events, _ := j.since(0); !equalIndexes(journalIndexes(events), []uint64{1, 3, 4}) {
+		t.Errorf("expected events in index order, got %v", journalIndexes(events))
+	}
+
+	// An event older than every event kept is lost
+	j.add(Event{Index: 6})
+	j.add(Event{Index: 2})
+	if _, ok := j.since(1); ok {
+		t.Error("expected the journal not to cover index 1")
+	}
+	if events, ok := j.since(2); !ok || !equalIndexes(journalIndexes(events), []uint64{3, 4, 6}) {
+		t.Errorf("expected [3 4 6], got %v, %v", journalIndexes(events), ok)
+	}
+}
+
+func TestJournal_Reset(t *testing.T) {
+	j := newJournal(3)
+	j.add(Event{Index: 1})
+	j.add(Event{Index: 2})
+
+	j.reset(10)
+	if _, ok := j.since(2); ok {
+		t.Error("expected the journal not to cover indexes before the reset")
+	}
+	if events, ok := j.since(10); !ok || len(events) != 0 {
+		t.Errorf("expected no event since the reset, got %v, %v", events, ok)
+	}
+
+	j.add(Event{Index: 11})
+	if events, ok := j.since(10); !ok || !equalIndexes(journalIndexes(events), []uint64{11}) {
+		t.Errorf("expected [11], got %v, %v", journalIndexes(events), ok)
+	}
+}
@@ -2,8 +2,10 @@ package watch
 
 import (
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -22,6 +24,8 @@ type Manager struct {
 	bufferSize   int
 	maxPerClient int
 	clientCounts map[string]int // UserID -> count
+	journals     map[Kind]*journal
+	journalSize  int
 }
 
 // NewManager creates a new watch manager
@@ -34,14 +38,100 @@ func NewManager(aclEval *acl.Evaluator, log logger.Logger, bufferSize, maxPerCli
 		bufferSize:   bufferSize,
 		maxPerClient: maxPerClient,
 		clientCounts: make(map[string]int),
+		journals:     make(map[Kind]*journal),
+		journalSize:  DefaultJournalSize,
 	}
 }
 
-// AddWatcher adds a new watcher for the given pattern
+// WithJournalSize sets the number of recent events of each kind kept for
+// resuming watchers. It must be called before the first event is notified.
+func (wm *Manager) WithJournalSize(size int) *Manager {
+	wm.journalSize = size
+	return wm
+}
+
+// journal returns the journal of kind, creating it on first use. Callers
+// must hold wm.mu for writing.
+func (wm *Manager) journal(kind Kind) *journal {
+	j, ok := wm.journals[kind]
+	if !ok {
+		j = newJournal(wm.journalSize)
+		wm.journals[kind] = j
+	}
+	return j
+}
+
+// AddWatcher adds a new watcher for the keys matching pattern
 func (wm *Manager) AddWatcher(pattern string, policies []string, transport TransportType, userID string) (*Watcher, error) {
+	return wm.addWatcher(KindKV, "", pattern, policies, transport, userID)
+}
+
+// AddServiceWatcher adds a new watcher for the services of namespace ns
+// whose name matches pattern
+func (wm *Manager) AddServiceWatcher(ns, pattern string, policies []string, transport TransportType, userID string) (*Watcher, error) {
+	return wm.addWatcher(KindService, ns, pattern, policies, transport, userID)
+}
+
+// ResumeWatcher is AddWatcher for a client that already saw the events up
+// to index since. It returns the journaled events after since that the
+// watcher would have received, oldest first, or a single resync event when
+// the journal no longer covers since.
+func (wm *Manager) ResumeWatcher(pattern string, policies []string, transport TransportType, userID string, since uint64) (*Watcher, []Event, error) {
+	return wm.resumeWatcher(KindKV, "", pattern, policies, transport, userID, since)
+}
+
+// ResumeServiceWatcher is ResumeWatcher for AddServiceWatcher.
+func (wm *Manager) ResumeServiceWatcher(ns, pattern string, policies []string, transport TransportType, userID string, since uint64) (*Watcher, []Event, error) {
+	return wm.resumeWatcher(KindService, ns, pattern, policies, transport, userID, since)
+}
+
+func (wm *Manager) addWatcher(kind Kind, ns, pattern string, policies []string, transport TransportType, userID string) (*Watcher, error) {
 	wm.mu.Lock()
 	defer wm.mu.Unlock()
+	return wm.registerWatcher(kind, ns, pattern, policies, transport, userID)
+}
+
+func (wm *Manager) resumeWatcher(kind Kind, ns, pattern string, policies []string, transport TransportType, userID string, since uint64) (*Watcher, []Event, error) {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+
+	// Registering under the lock that journals events means every event
+	// is either replayed or sent to the watcher, never both nor neither
+	watcher, err := wm.registerWatcher(kind, ns, pattern, policies, transport, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	j := wm.journal(kind)
+	events, ok := j.since(since)
+	if !ok {
+		wm.log.Info("Watcher cannot resume, resync required",
+			logger.String("id", watcher.ID),
+			logger.String("kind", string(kind)),
+			logger.String("since", strconv.FormatUint(since, 10)))
+		metrics.WatchResyncsTotal.WithLabelValues("journal_truncated").Inc()
+		resync := Event{
+			Type:      EventTypeResync,
+			Kind:      kind,
+			Namespace: ns,
+			Index:     j.last,
+			Timestamp: time.Now().Unix(),
+		}
+		return watcher, []Event{resync}, nil
+	}
+
+	replay := make([]Event, 0, len(events))
+	for _, event := range events {
+		if wm.matchesPattern(event.Key, pattern) && wm.accepts(watcher, event) {
+			replay = append(replay, event)
+		}
+	}
+	watcher.lastIndex = max(since, j.last)
+	return watcher, replay, nil
+}
 
+// registerWatcher adds a watcher. Callers must hold wm.mu for writing.
+func (wm *Manager) registerWatcher(kind Kind, ns, pattern string, policies []string, transport TransportType, userID string) (*Watcher, error) {
 	// Check per-client limit
 	if wm.maxPerClient > 0 && userID != "" {
 		if wm.clientCounts[userID] >= wm.maxPerClient {
@@ -62,6 +152,8 @@ func (wm *Manager) AddWatcher(pattern string, policies []string, transport Trans
 		wm.bufferSize,
 	)
 	watcher.UserID = userID
+	watcher.Kind = kind
+	watcher.Namespace = ns
 
 	// Add to maps
 	wm.watchers[watcher.ID] = watcher
@@ -74,6 +166,7 @@ func (wm *Manager) AddWatcher(pattern string, policies []string, transport Trans
 
 	wm.log.Info("Watcher added",
 		logger.String("id", watcher.ID),
+		logger.String("kind", string(kind)),
 		logger.String("pattern", pattern),
 		logger.String("transport", string(transport)),
 		logger.String("user_id", userID))
@@ -130,10 +223,23 @@ func (wm *Manager) RemoveWatcher(id string) {
 		logger.String("pattern", watcher.Pattern))
 }
 
-// Notify sends an event to all matching watchers
+// ResetJournal forgets the journaled events of kind after its store was
+// replaced wholesale at index, as by a snapshot restore. Watchers resuming
+// from an earlier index get a resync event.
+func (wm *Manager) ResetJournal(kind Kind, index uint64) {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	wm.journal(kind).reset(index)
+}
+
+// Notify records an event in the journal of its kind and sends it to all
+// matching watchers. A watcher whose buffer is full gets a resync event
+// instead, and no more events until it is removed.
 func (wm *Manager) Notify(event Event) {
-	wm.mu.RLock()
-	defer wm.mu.RUnlock()
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+
+	wm.journal(event.kind()).add(event)
 
 	notified := 0
 	dropped := 0
@@ -143,33 +249,44 @@ func (wm *Manager) Notify(event Event) {
 		if wm.matchesPattern(event.Key, pattern) {
 			for _, id := range watcherIDs {
 				watcher, exists := wm.watchers[id]
-				if !exists {
+				if !exists || !wm.accepts(watcher, event) {
 					continue
 				}
-
-				// Check ACL permissions
-				if !wm.canWatch(watcher, event.Key) {
-					wm.log.Debug("Watcher ACL check failed",
-						logger.String("watcher_id", id),
-						logger.String("key", event.Key))
+				if watcher.overflowed {
+					dropped++
+					metrics.WatchEventsDropped.WithLabelValues("resync_pending").Inc()
 					continue
 				}
 
-				// Send event (non-blocking)
-				select {
-				case watcher.Events <- event:
+				// Send event; the channel has a slot beyond bufferSize
+				// for the resync event, and senders hold wm.mu, so
+				// neither send blocks
+				if len(watcher.Events) < wm.bufferSize {
+					watcher.Events <- event
+					watcher.lastIndex = max(watcher.lastIndex, event.Index)
 					notified++
 					metrics.WatchEventsTotal.WithLabelValues(string(event.Type)).Inc()
-				default:
-					// Channel full, log warning and drop event
-					dropped++
-					metrics.WatchEventsDropped.WithLabelValues("channel_full").Inc()
-					wm.log.Warn("Watcher channel full, dropping event",
-						logger.String("watcher_id", id),
-						logger.String("pattern", pattern),
-						logger.String("key", event.Key),
-						logger.String("event_type", string(event.Type)))
+					continue
+				}
+
+				// Channel full: drop the event and tell the watcher to
+				// resync from the last event it got
+				watcher.overflowed = true
+				watcher.Events <- Event{
+					Type:      EventTypeResync,
+					Kind:      watcher.Kind,
+					Namespace: watcher.Namespace,
+					Index:     watcher.lastIndex,
+					Timestamp: event.Timestamp,
 				}
+				dropped++
+				metrics.WatchEventsDropped.WithLabelValues("channel_full").Inc()
+				metrics.WatchResyncsTotal.WithLabelValues("channel_full").Inc()
+				wm.log.Warn("Watcher channel full, resync required",
+					logger.String("watcher_id", id),
+					logger.String("pattern", pattern),
+					logger.String("key", event.Key),
+					logger.String("event_type", string(event.Type)))
 			}
 		}
 	}
@@ -213,15 +330,38 @@ func (wm *Manager) matchesPattern(key, pattern string) bool {
 	return matched
 }
 
-// canWatch checks if a watcher has permission to watch a key
-func (wm *Manager) canWatch(watcher *Watcher, key string) bool {
+// accepts reports whether watcher gets event, the key aside: the event is
+// of the watched kind and namespace and the watcher may read it.
+func (wm *Manager) accepts(watcher *Watcher, event Event) bool {
+	if watcher.Kind != event.kind() {
+		return false
+	}
+	if watcher.Kind == KindService && watcher.Namespace != event.Namespace {
+		return false
+	}
+
+	// Check ACL permissions
+	if !wm.canWatch(watcher, event) {
+		wm.log.Debug("Watcher ACL check failed",
+			logger.String("watcher_id", watcher.ID),
+			logger.String("key", event.Key))
+		return false
+	}
+	return true
+}
+
+// canWatch checks if a watcher has permission to read the key or service of
+// an event
+func (wm *Manager) canWatch(watcher *Watcher, event Event) bool {
 	if wm.aclEval == nil {
 		// ACL not enabled, allow all
 		return true
 	}
 
-	// Check if watcher's policies allow reading this key
-	resource := acl.NewKVResource(key)
+	resource := acl.NewKVResource(event.Key)
+	if watcher.Kind == KindService {
+		resource = acl.NewServiceResource(event.Key).InNamespace(event.Namespace)
+	}
 	return wm.aclEval.Evaluate(watcher.ACLPolicies, resource, acl.CapabilityRead)
 }
 
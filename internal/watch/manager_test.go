@@ -360,31 +360,102 @@ func TestManager_Notify_BufferFull(t *testing.T) {
 		event := Event{
 			Type:      EventTypeSet,
 			Key:       "app/config",
+			Index:     uint64(i + 1),
 			Value:     "test",
 			Timestamp: time.Now().Unix(),
 		}
 		manager.Notify(event)
 	}
 
-	// Send one more (should be dropped)
-	event := Event{
-		Type:      EventTypeSet,
-		Key:       "app/config",
-		Value:     "dropped",
-		Timestamp: time.Now().Unix(),
+	// Send two more (should be dropped)
+	for i := 2; i < 4; i++ {
+		manager.Notify(Event{
+			Type:      EventTypeSet,
+			Key:       "app/config",
+			Index:     uint64(i + 1),
+			Value:     "dropped",
+			Timestamp: time.Now().Unix(),
+		})
 	}
-	manager.Notify(event)
 
 	// Drain first two events
 	<-watcher.Events
 	<-watcher.Events
 
-	// Third event should not be in channel
+	// The dropped events are replaced by a resync from the last event sent
+	resync := <-watcher.Events
+	if resync.Type != EventTypeResync || resync.Index != 2 {
+		t.Errorf("Expected a resync event at index 2, got %s at %d", resync.Type, resync.Index)
+	}
 	select {
 	case <-watcher.Events:
-		t.Error("Expected channel to be empty (event dropped)")
+		t.Error("Expected channel to be empty (events dropped)")
 	case <-time.After(50 * time.Millisecond):
-		// Expected - event was dropped
+		// Expected - events were dropped
+	}
+
+	// Resuming after the resync index replays the dropped events
+	resumed, replay, err := manager.ResumeWatcher("app/config", []string{}, TransportWebSocket, "user1", resync.Index)
+	if err != nil {
+		t.Fatalf("Failed to resume watcher: %v", err)
+	}
+	defer manager.RemoveWatcher(resumed.ID)
+	if len(replay) != 2 || replay[0].Index != 3 || replay[1].Index != 4 {
+		t.Errorf("Expected events 3 and 4 replayed, got %v", replay)
+	}
+}
+
+func TestManager_ResumeWatcher(t *testing.T) {
+	log := logger.GetDefault()
+	manager := NewManager(nil, log, 10, 0).WithJournalSize(3)
+
+	for i, key := range []string{"app/a", "other/b", "app/c", "app/d"} {
+		manager.Notify(Event{Type: EventTypeSet, Key: key, Index: uint64(i + 1)})
+	}
+
+	// Only the matching events after the index are replayed
+	watcher, replay, err := manager.ResumeWatcher("app/*", nil, TransportSSE, "user1", 2)
+	if err != nil {
+		t.Fatalf("Failed to resume watcher: %v", err)
+	}
+	if len(replay) != 2 || replay[0].Key != "app/c" || replay[1].Key != "app/d" {
+		t.Errorf("Expected app/c and app/d replayed, got %v", replay)
+	}
+
+	// Later events are sent to the watcher
+	manager.Notify(Event{Type: EventTypeDelete, Key: "app/a", Index: 5})
+	select {
+	case event := <-watcher.Events:
+		if event.Index != 5 {
+			t.Errorf("Expected event 5, got %d", event.Index)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Timeout waiting for event")
+	}
+
+	// Event 2 fell out of the journal
+	_, replay, err = manager.ResumeWatcher("app/*", nil, TransportSSE, "user1", 1)
+	if err != nil {
+		t.Fatalf("Failed to resume watcher: %v", err)
+	}
+	if len(replay) != 1 || replay[0].Type != EventTypeResync || replay[0].Index != 5 {
+		t.Errorf("Expected a resync event at index 5, got %v", replay)
+	}
+
+	// Service events have their own journal
+	_, replay, err = manager.ResumeServiceWatcher("", "*", nil, TransportSSE, "user1", 0)
+	if err != nil {
+		t.Fatalf("Failed to resume service watcher: %v", err)
+	}
+	if len(replay) != 0 {
+		t.Errorf("Expected no service event replayed, got %v", replay)
+	}
+
+	// After a reset, earlier indexes cannot be resumed from
+	manager.ResetJournal(KindKV, 10)
+	_, replay, _ = manager.ResumeWatcher("app/*", nil, TransportSSE, "user1", 5)
+	if len(replay) != 1 || replay[0].Type != EventTypeResync || replay[0].Index != 10 {
+		t.Errorf("Expected a resync event at index 10 after a reset, got %v", replay)
 	}
 }
 
@@ -504,3 +575,52 @@ func TestManager_matchesPattern(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_Notify_ServiceEvents(t *testing.T) {
+	log := logger.GetDefault()
+	evaluator := acl.NewEvaluator(log)
+	policy := &acl.Policy{
+		Name: "web-reader",
+		Service: []acl.ServiceRule{
+			{Name: "web*", Capabilities: []acl.Capability{acl.CapabilityRead}},
+			{Namespace: "staging", Name: "web*", Capabilities: []acl.Capability{acl.CapabilityRead}},
+		},
+	}
+	if err := evaluator.AddPolicy(policy); err != nil {
+		t.Fatalf("Failed to add policy: %v", err)
+	}
+
+	manager := NewManager(evaluator, log, 10, 0)
+	services, _ := manager.AddServiceWatcher("", "*", []string{"web-reader"}, TransportWebSocket, "user1")
+	staging, _ := manager.AddServiceWatcher("staging", "*", []string{"web-reader"}, TransportSSE, "user1")
+	keys, _ := manager.AddWatcher("**", []string{"web-reader"}, TransportWebSocket, "user1")
+
+	tests := []struct {
+		name    string
+		event   Event
+		watcher *Watcher
+	}{
+		{"service event", Event{Type: EventTypeRegister, Kind: KindService, Key: "web"}, services},
+		{"other namespace", Event{Type: EventTypeRegister, Kind: KindService, Key: "web", Namespace: "staging"}, staging},
+		{"denied service", Event{Type: EventTypeRegister, Kind: KindService, Key: "db"}, nil},
+		{"KV event", Event{Type: EventTypeSet, Key: "web"}, nil}, // No KV rule allows it
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager.Notify(tt.event)
+			for _, watcher := range []*Watcher{services, staging, keys} {
+				select {
+				case received := <-watcher.Events:
+					if watcher != tt.watcher {
+						t.Errorf("unexpected event %+v for watcher %s", received, watcher.Pattern)
+					}
+				default:
+					if watcher == tt.watcher {
+						t.Errorf("expected an event for watcher %s", watcher.Pattern)
+					}
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package watch
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -11,15 +12,53 @@ type EventType string
 const (
 	EventTypeSet    EventType = "set"
 	EventTypeDelete EventType = "delete"
+
+	// Service events
+	EventTypeRegister   EventType = "register"
+	EventTypeDeregister EventType = "deregister"
+	EventTypeHealth     EventType = "health" // A health check of the service changed status
+
+	// EventTypeResync tells a watcher that it missed events, because it
+	// resumed from an index the journal no longer covers or fell behind
+	// and overflowed its buffer. It must reload the current state, or
+	// resume after the index of the event, which is the last one it got.
+	EventTypeResync EventType = "resync"
+)
+
+// Kind is the kind of resource a watcher observes
+type Kind string
+
+// Kinds of watched resources
+const (
+	KindKV      Kind = "kv"
+	KindService Kind = "service"
 )
 
-// Event represents a change to a key
+// Event represents a change to a key, or to a service for service events.
+// The key of a service event is the service name.
 type Event struct {
 	Type      EventType `json:"type"`
+	Kind      Kind      `json:"kind,omitempty"` // KV when empty
 	Key       string    `json:"key"`
+	Index     uint64    `json:"index,omitempty"` // Store index of the change
 	Value     string    `json:"value,omitempty"`
 	OldValue  string    `json:"old_value,omitempty"`
+	Secret    bool      `json:"secret,omitempty"` // Value is a redacted secret
 	Timestamp int64     `json:"timestamp"`
+
+	// Service events
+	Namespace string          `json:"namespace,omitempty"` // Empty for the default namespace
+	Instance  string          `json:"instance,omitempty"`  // Instance ID, unless the event concerns the whole service
+	Check     string          `json:"check,omitempty"`     // Check that changed status; Value and OldValue are its statuses
+	Service   json.RawMessage `json:"service,omitempty"`   // The instance
+}
+
+// kind returns the kind of resource the event is about
+func (e Event) kind() Kind {
+	if e.Kind == "" {
+		return KindKV
+	}
+	return e.Kind
 }
 
 // TransportType represents the transport protocol for watch connections
@@ -29,25 +68,34 @@ type TransportType string
 const (
 	TransportWebSocket TransportType = "websocket"
 	TransportSSE       TransportType = "sse"
+	TransportWebhook   TransportType = "webhook" // Watchers forwarding events to webhooks
 )
 
 // Watcher represents a single watch subscription
 type Watcher struct {
 	ID          string
+	Kind        Kind   // Kind of resource watched
+	Namespace   string // Namespace of the services watched; empty for the default one
 	Pattern     string // Key or prefix to watch (supports * and **)
 	Events      chan Event
 	ACLPolicies []string // Policies for ACL checks
 	CreatedAt   time.Time
 	Transport   TransportType
 	UserID      string // Optional user identifier
+
+	lastIndex  uint64 // Index of the last event queued
+	overflowed bool   // A resync event was queued; later events are dropped
 }
 
-// NewWatcher creates a new watcher with a buffered event channel
+// NewWatcher creates a new watcher with a buffered event channel. The
+// channel has one more slot than bufferSize, kept for the resync event
+// queued when the buffer overflows.
 func NewWatcher(id, pattern string, policies []string, transport TransportType, bufferSize int) *Watcher {
 	return &Watcher{
 		ID:          id,
+		Kind:        KindKV,
 		Pattern:     pattern,
-		Events:      make(chan Event, bufferSize),
+		Events:      make(chan Event, bufferSize+1),
 		ACLPolicies: policies,
 		CreatedAt:   time.Now(),
 		Transport:   transport,
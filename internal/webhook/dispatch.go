@@ -0,0 +1,243 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/metrics"
+)
+
+// Headers of delivery requests
+const (
+	HeaderWebhook   = "X-Konsul-Webhook"
+	HeaderDelivery  = "X-Konsul-Delivery"
+	HeaderEvent     = "X-Konsul-Event"
+	HeaderAttempt   = "X-Konsul-Attempt"
+	HeaderSignature = "X-Konsul-Signature"
+)
+
+// idleWait is how long the dispatcher sleeps when no delivery is pending.
+const idleWait = time.Hour
+
+// Sign returns the signature header value of a request body: the hex
+// HMAC-SHA256 of body keyed with secret, prefixed with "sha256=".
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff returns the wait after the failed attempt of a delivery: the
+// retry base doubled for each earlier attempt, up to the retry maximum.
+func (m *Manager) backoff(attempt int) time.Duration {
+	wait := m.cfg.RetryBase
+	for i := 1; i < attempt && wait < m.cfg.RetryMax; i++ {
+		wait *= 2
+	}
+	return min(wait, m.cfg.RetryMax)
+}
+
+// runDispatcher hands due deliveries to the workers until the manager
+// closes.
+func (m *Manager) runDispatcher() {
+	defer m.wg.Done()
+	timer := time.NewTimer(idleWait)
+	defer timer.Stop()
+
+	for {
+		due, wait := m.dueDeliveries(time.Now())
+		for _, delivery := range due {
+			select {
+			case m.jobs <- delivery:
+			case <-m.ctx.Done():
+				return
+			}
+		}
+		if len(due) > 0 {
+			// Handing deliveries over may have blocked on busy workers
+			continue
+		}
+
+		timer.Reset(wait)
+		select {
+		case <-timer.C:
+		case <-m.wake:
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// dueDeliveries marks the deliveries due at now as in flight and returns
+// them oldest first, along with the time until the next one is due.
+func (m *Manager) dueDeliveries(now time.Time) ([]*Delivery, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var due []*Delivery
+	wait := idleWait
+	for _, delivery := range m.pending {
+		if delivery.inFlight {
+			continue
+		}
+		if next := delivery.NextAttempt.Sub(now); next > 0 {
+			wait = min(wait, next)
+			continue
+		}
+		delivery.inFlight = true
+		due = append(due, delivery)
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].CreatedAt.Before(due[j].CreatedAt) })
+	return due, wait
+}
+
+// runWorker POSTs the deliveries handed over by the dispatcher until the
+// manager closes.
+func (m *Manager) runWorker() {
+	defer m.wg.Done()
+	for {
+		select {
+		case delivery := <-m.jobs:
+			m.attempt(delivery)
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// attempt POSTs a delivery without holding the manager's lock, then records
+// the outcome unless the delivery was dropped meanwhile: the delivery
+// completes on a 2xx response or its last attempt, and is retried after a
+// backoff otherwise.
+func (m *Manager) attempt(delivery *Delivery) {
+	m.mu.Lock()
+	sub, ok := m.hooks[delivery.Webhook]
+	if !ok || m.pending[delivery.ID] != delivery {
+		m.mu.Unlock()
+		return
+	}
+	hook := sub.hook
+	payload := Payload{
+		Webhook:  hook.Name,
+		Delivery: delivery.ID,
+		Attempt:  delivery.Attempts + 1,
+		Event:    delivery.Event,
+	}
+	m.mu.Unlock()
+
+	start := time.Now()
+	status, err := m.post(hook, payload)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delivery.inFlight = false
+	if m.pending[delivery.ID] != delivery {
+		return
+	}
+	delivery.Attempts++
+	delivery.StatusCode = status
+	delivery.Error = ""
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	switch {
+	case err == nil:
+		m.complete(delivery, DeliveryDelivered)
+		m.log.Debug("Webhook delivered",
+			logger.String("webhook", hook.Name),
+			logger.String("delivery", delivery.ID),
+			logger.Int("attempt", delivery.Attempts),
+			logger.Duration("duration", time.Since(start)))
+	case delivery.Attempts >= m.cfg.MaxAttempts:
+		m.complete(delivery, DeliveryFailed)
+		m.log.Warn("Webhook delivery failed",
+			logger.String("webhook", hook.Name),
+			logger.String("delivery", delivery.ID),
+			logger.Int("attempts", delivery.Attempts),
+			logger.Error(err))
+	default:
+		delivery.NextAttempt = time.Now().UTC().Add(m.backoff(delivery.Attempts))
+		m.persist(delivery)
+		metrics.WebhookDeliveriesTotal.WithLabelValues("retried").Inc()
+		m.log.Debug("Webhook delivery will be retried",
+			logger.String("webhook", hook.Name),
+			logger.String("delivery", delivery.ID),
+			logger.Int("attempt", delivery.Attempts),
+			logger.Error(err))
+
+		// Wake the dispatcher in case the retry is due before its next
+		// wakeup
+		select {
+		case m.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// complete moves a delivery from the pending ones to the history of its
+// webhook. Callers must hold m.mu.
+func (m *Manager) complete(delivery *Delivery, status DeliveryStatus) {
+	delivery.Status = status
+	delivery.NextAttempt = time.Time{}
+	delivery.CompletedAt = time.Now().UTC()
+	delete(m.pending, delivery.ID)
+	m.deleteRecord(deliveryRecordKind, delivery.ID)
+
+	history := append(m.history[delivery.Webhook], *delivery)
+	if len(history) > m.cfg.HistorySize {
+		history = history[len(history)-m.cfg.HistorySize:]
+	}
+	m.history[delivery.Webhook] = history
+
+	metrics.WebhookDeliveriesPending.Dec()
+	metrics.WebhookDeliveriesTotal.WithLabelValues(string(status)).Inc()
+}
+
+// post sends payload to hook and returns the response status. Statuses
+// other than 2xx are errors.
+func (m *Manager) post(hook Webhook, payload Payload) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(m.ctx, m.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "konsul-webhook")
+	req.Header.Set(HeaderWebhook, hook.Name)
+	req.Header.Set(HeaderDelivery, payload.Delivery)
+	req.Header.Set(HeaderEvent, string(payload.Event.Type))
+	req.Header.Set(HeaderAttempt, strconv.Itoa(payload.Attempt))
+	if hook.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(hook.Secret, body))
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
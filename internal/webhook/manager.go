@@ -0,0 +1,464 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/metrics"
+	"github.com/neogan74/konsul/internal/persistence"
+	"github.com/neogan74/konsul/internal/watch"
+)
+
+// Persistence record kinds of webhooks and of their pending deliveries
+const (
+	webhookRecordKind  = "webhook"
+	deliveryRecordKind = "webhook-delivery"
+)
+
+// Defaults of Config
+const (
+	DefaultMaxAttempts = 8
+	DefaultRetryBase   = time.Second
+	DefaultRetryMax    = 5 * time.Minute
+	DefaultTimeout     = 10 * time.Second
+	DefaultWorkers     = 4
+	DefaultHistorySize = 50
+)
+
+// Config mirrors the public webhook configuration.
+type Config struct {
+	MaxAttempts int           // Attempts of a delivery before it fails
+	RetryBase   time.Duration // Wait before the first retry, doubled for each next one
+	RetryMax    time.Duration // Longest wait between attempts
+	Timeout     time.Duration // Timeout of a delivery request
+	Workers     int           // Deliveries POSTed at once
+	HistorySize int           // Completed deliveries kept per webhook
+}
+
+// subscription is a webhook and the watcher feeding it events. It is
+// guarded by the manager's mutex.
+type subscription struct {
+	hook    Webhook
+	watcher *watch.Watcher
+}
+
+// Manager subscribes webhooks to the watch manager and POSTs their events.
+// Events are queued as deliveries, persisted until they are delivered or
+// fail for good, so they survive restarts. Webhooks are local to the node
+// they are created on.
+type Manager struct {
+	cfg    Config
+	watch  *watch.Manager
+	engine persistence.Engine // nil when persistence is disabled
+	log    logger.Logger
+	client *http.Client
+
+	mu      sync.Mutex
+	hooks   map[string]*subscription // Name -> subscription
+	pending map[string]*Delivery     // ID -> pending delivery
+	history map[string][]Delivery    // Name -> completed deliveries, oldest first
+	closed  bool
+
+	jobs   chan *Delivery
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewManager loads the persisted webhooks and pending deliveries from engine,
+// which may be nil, subscribes the webhooks to wm and starts delivering.
+func NewManager(wm *watch.Manager, engine persistence.Engine, cfg Config, log logger.Logger) (*Manager, error) {
+	if log == nil {
+		log = logger.GetDefault()
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.RetryBase <= 0 {
+		cfg.RetryBase = DefaultRetryBase
+	}
+	if cfg.RetryMax <= 0 {
+		cfg.RetryMax = DefaultRetryMax
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = DefaultWorkers
+	}
+	if cfg.HistorySize <= 0 {
+		cfg.HistorySize = DefaultHistorySize
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		cfg:     cfg,
+		watch:   wm,
+		engine:  engine,
+		log:     log,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		hooks:   make(map[string]*subscription),
+		pending: make(map[string]*Delivery),
+		history: make(map[string][]Delivery),
+		jobs:    make(chan *Delivery),
+		wake:    make(chan struct{}, 1),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	if err := m.load(); err != nil {
+		cancel()
+		m.Close()
+		return nil, err
+	}
+
+	m.wg.Add(1 + cfg.Workers)
+	go m.runDispatcher()
+	for i := 0; i < cfg.Workers; i++ {
+		go m.runWorker()
+	}
+	return m, nil
+}
+
+// load subscribes the persisted webhooks and queues their pending
+// deliveries. Deliveries of unknown webhooks are dropped.
+func (m *Manager) load() error {
+	if m.engine == nil {
+		return nil
+	}
+
+	records, err := m.engine.ListACL(webhookRecordKind)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for name, data := range records {
+		var hook Webhook
+		if err := json.Unmarshal(data, &hook); err != nil {
+			m.log.Warn("Skipping invalid webhook record",
+				logger.String("name", name),
+				logger.Error(err))
+			continue
+		}
+		if err := m.subscribe(hook); err != nil {
+			return err
+		}
+	}
+
+	records, err = m.engine.ListACL(deliveryRecordKind)
+	if err != nil {
+		return err
+	}
+	for id, data := range records {
+		var delivery Delivery
+		if err := json.Unmarshal(data, &delivery); err != nil || m.hooks[delivery.Webhook] == nil {
+			m.deleteRecord(deliveryRecordKind, id)
+			continue
+		}
+		m.pending[id] = &delivery
+	}
+	metrics.WebhookDeliveriesPending.Set(float64(len(m.pending)))
+
+	if len(m.hooks) > 0 {
+		m.log.Info("Webhooks loaded",
+			logger.Int("webhooks", len(m.hooks)),
+			logger.Int("pending_deliveries", len(m.pending)))
+	}
+	return nil
+}
+
+// Set creates or replaces a webhook. The events it missed while being
+// replaced are not delivered; its pending deliveries are kept. A webhook
+// replaced without a secret keeps its secret unless ClearSecret is set.
+func (m *Manager) Set(hook Webhook) (Webhook, error) {
+	hook.Normalize()
+	hook.Signed = false
+	clearSecret := hook.ClearSecret
+	hook.ClearSecret = false
+	if err := hook.Validate(); err != nil {
+		return Webhook{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return Webhook{}, ErrManagerClosed
+	}
+
+	now := time.Now().UTC()
+	hook.CreatedAt, hook.UpdatedAt = now, now
+	old, exists := m.hooks[hook.Name]
+	if exists {
+		hook.CreatedAt = old.hook.CreatedAt
+		if hook.Secret == "" && !clearSecret {
+			hook.Secret = old.hook.Secret
+		}
+	}
+
+	data, err := json.Marshal(hook)
+	if err != nil {
+		return Webhook{}, err
+	}
+	if m.engine != nil {
+		if err := m.engine.SetACL(webhookRecordKind, hook.Name, data); err != nil {
+			return Webhook{}, err
+		}
+	}
+
+	if exists {
+		m.watch.RemoveWatcher(old.watcher.ID)
+	}
+	if err := m.subscribe(hook); err != nil {
+		return Webhook{}, err
+	}
+
+	m.log.Info("Webhook set",
+		logger.String("name", hook.Name),
+		logger.String("kind", string(hook.Kind)),
+		logger.String("pattern", hook.Pattern))
+	return hook.Redacted(), nil
+}
+
+// subscribe adds a watcher for hook and starts forwarding its events.
+// Callers must hold m.mu.
+func (m *Manager) subscribe(hook Webhook) error {
+	var watcher *watch.Watcher
+	var err error
+	if hook.Kind == watch.KindService {
+		watcher, err = m.watch.AddServiceWatcher(hook.Namespace, hook.Pattern, hook.Policies, watch.TransportWebhook, watcherUser(hook.Name))
+	} else {
+		watcher, err = m.watch.AddWatcher(hook.Pattern, hook.Policies, watch.TransportWebhook, watcherUser(hook.Name))
+	}
+	if err != nil {
+		return err
+	}
+
+	sub := &subscription{hook: hook, watcher: watcher}
+	m.hooks[hook.Name] = sub
+	m.wg.Add(1)
+	go m.forward(sub, watcher.Events)
+	return nil
+}
+
+// watcherUser is the user ID of the watchers of webhook name.
+func watcherUser(name string) string {
+	return "webhook:" + name
+}
+
+// forward queues the events of the watcher of sub until it is removed.
+func (m *Manager) forward(sub *subscription, events <-chan watch.Event) {
+	defer m.wg.Done()
+	for {
+		event, ok := <-events
+		if !ok {
+			return
+		}
+		if event.Type == watch.EventTypeResync {
+			if events, ok = m.resubscribe(sub, event); !ok {
+				return
+			}
+			continue
+		}
+
+		m.mu.Lock()
+		if m.hooks[sub.hook.Name] == sub && sub.hook.wants(event.Type) {
+			m.enqueue(sub.hook.Name, event)
+		}
+		m.mu.Unlock()
+	}
+}
+
+// resubscribe replaces the watcher of sub after it overflowed, queuing the
+// events it dropped from the watch journal, and returns the events of the
+// new watcher. When the journal no longer has them, the resync event is
+// delivered instead so the receiver reloads the state it mirrors.
+func (m *Manager) resubscribe(sub *subscription, resync watch.Event) (<-chan watch.Event, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed || m.hooks[sub.hook.Name] != sub {
+		return nil, false
+	}
+
+	hook := sub.hook
+	m.watch.RemoveWatcher(sub.watcher.ID)
+	var watcher *watch.Watcher
+	var replay []watch.Event
+	var err error
+	if hook.Kind == watch.KindService {
+		watcher, replay, err = m.watch.ResumeServiceWatcher(hook.Namespace, hook.Pattern, hook.Policies, watch.TransportWebhook, watcherUser(hook.Name), resync.Index)
+	} else {
+		watcher, replay, err = m.watch.ResumeWatcher(hook.Pattern, hook.Policies, watch.TransportWebhook, watcherUser(hook.Name), resync.Index)
+	}
+	if err != nil {
+		m.log.Error("Failed to resubscribe webhook",
+			logger.String("name", hook.Name),
+			logger.Error(err))
+		delete(m.hooks, hook.Name)
+		return nil, false
+	}
+
+	sub.watcher = watcher
+	for _, event := range replay {
+		if hook.wants(event.Type) {
+			m.enqueue(hook.Name, event)
+		}
+	}
+	return watcher.Events, true
+}
+
+// enqueue queues the delivery of event to webhook name. Callers must hold
+// m.mu.
+func (m *Manager) enqueue(name string, event watch.Event) {
+	now := time.Now().UTC()
+	delivery := &Delivery{
+		ID:          uuid.New().String(),
+		Webhook:     name,
+		Event:       event,
+		Status:      DeliveryPending,
+		CreatedAt:   now,
+		NextAttempt: now,
+	}
+	m.pending[delivery.ID] = delivery
+	m.persist(delivery)
+	metrics.WebhookDeliveriesPending.Inc()
+
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// persist saves a pending delivery. Callers must hold m.mu.
+func (m *Manager) persist(delivery *Delivery) {
+	if m.engine == nil {
+		return
+	}
+	data, err := json.Marshal(delivery)
+	if err == nil {
+		err = m.engine.SetACL(deliveryRecordKind, delivery.ID, data)
+	}
+	if err != nil {
+		m.log.Error("Failed to persist webhook delivery",
+			logger.String("webhook", delivery.Webhook),
+			logger.String("delivery", delivery.ID),
+			logger.Error(err))
+	}
+}
+
+// deleteRecord deletes a persisted record, logging failures.
+func (m *Manager) deleteRecord(kind, id string) {
+	if m.engine == nil {
+		return
+	}
+	if err := m.engine.DeleteACL(kind, id); err != nil {
+		m.log.Error("Failed to delete webhook record",
+			logger.String("kind", kind),
+			logger.String("id", id),
+			logger.Error(err))
+	}
+}
+
+// Get returns the webhook name, without its secret.
+func (m *Manager) Get(name string) (Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub, ok := m.hooks[name]
+	if !ok {
+		return Webhook{}, ErrNotFound
+	}
+	return sub.hook.Redacted(), nil
+}
+
+// List returns the webhooks sorted by name, without their secrets.
+func (m *Manager) List() []Webhook {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	hooks := make([]Webhook, 0, len(m.hooks))
+	for _, sub := range m.hooks {
+		hooks = append(hooks, sub.hook.Redacted())
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].Name < hooks[j].Name })
+	return hooks
+}
+
+// Delete removes the webhook name along with its pending deliveries and
+// delivery history.
+func (m *Manager) Delete(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub, ok := m.hooks[name]
+	if !ok {
+		return ErrNotFound
+	}
+
+	if m.engine != nil {
+		if err := m.engine.DeleteACL(webhookRecordKind, name); err != nil {
+			return err
+		}
+	}
+	m.watch.RemoveWatcher(sub.watcher.ID)
+	delete(m.hooks, name)
+	delete(m.history, name)
+	for id, delivery := range m.pending {
+		if delivery.Webhook == name {
+			delete(m.pending, id)
+			m.deleteRecord(deliveryRecordKind, id)
+			metrics.WebhookDeliveriesPending.Dec()
+		}
+	}
+
+	m.log.Info("Webhook deleted", logger.String("name", name))
+	return nil
+}
+
+// Deliveries returns the pending and recent deliveries of webhook name,
+// newest first.
+func (m *Manager) Deliveries(name string) ([]Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.hooks[name]; !ok {
+		return nil, ErrNotFound
+	}
+
+	deliveries := slices.Clone(m.history[name])
+	for _, delivery := range m.pending {
+		if delivery.Webhook == name {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		if !deliveries[i].CreatedAt.Equal(deliveries[j].CreatedAt) {
+			return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+		}
+		return strings.Compare(deliveries[i].ID, deliveries[j].ID) > 0
+	})
+	return deliveries, nil
+}
+
+// Close stops the webhooks, waiting for running deliveries to complete.
+// Pending deliveries stay persisted for the next start.
+func (m *Manager) Close() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	for _, sub := range m.hooks {
+		m.watch.RemoveWatcher(sub.watcher.ID)
+	}
+	m.mu.Unlock()
+
+	m.cancel()
+	m.wg.Wait()
+}
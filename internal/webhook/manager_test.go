@@ -0,0 +1,383 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neogan74/konsul/internal/logger"
+	"github.com/neogan74/konsul/internal/persistence"
+	"github.com/neogan74/konsul/internal/watch"
+)
+
+// receiver is a webhook endpoint recording the requests it gets.
+type receiver struct {
+	*httptest.Server
+	failures atomic.Int32 // Requests still to fail with a 500
+
+	mu       sync.Mutex
+	requests []*http.Request
+	payloads []Payload
+	bodies   [][]byte
+}
+
+func newReceiver(t *testing.T) *receiver {
+	r := &receiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if r.failures.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		var payload Payload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.mu.Lock()
+		r.requests = append(r.requests, req)
+		r.payloads = append(r.payloads, payload)
+		r.bodies = append(r.bodies, body)
+		r.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+// wait returns the payloads received once there are n of them.
+func (r *receiver) wait(t *testing.T, n int) []Payload {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		r.mu.Lock()
+		got := append([]Payload(nil), r.payloads...)
+		r.mu.Unlock()
+		if len(got) >= n {
+			return got
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d deliveries, got %d", n, len(got))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func newTestManager(t *testing.T, wm *watch.Manager, engine persistence.Engine, cfg Config) *Manager {
+	t.Helper()
+	manager, err := NewManager(wm, engine, cfg, logger.GetDefault())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	t.Cleanup(manager.Close)
+	return manager
+}
+
+// waitForStatus waits for the only delivery of webhook name to complete.
+func waitForStatus(t *testing.T, manager *Manager, name string, want DeliveryStatus) Delivery {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		deliveries, err := manager.Deliveries(name)
+		if err != nil {
+			t.Fatalf("Deliveries failed: %v", err)
+		}
+		if len(deliveries) == 1 && deliveries[0].Status == want {
+			return deliveries[0]
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a %s delivery, got %+v", want, deliveries)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestManager_DeliversSignedEvents(t *testing.T) {
+	recv := newReceiver(t)
+	wm := watch.NewManager(nil, logger.GetDefault(), 10, 0)
+	manager := newTestManager(t, wm, nil, Config{})
+
+	hook, err := manager.Set(Webhook{
+		Name:    "deploy",
+		URL:     recv.URL,
+		Secret:  "s3cret",
+		Pattern: "app/**",
+		Events:  []watch.EventType{watch.EventTypeSet},
+	})
+	if err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if hook.Secret != "" || !hook.Signed || hook.Kind != watch.KindKV {
+		t.Errorf("expected a redacted, signed KV webhook, got %+v", hook)
+	}
+
+	wm.Notify(watch.Event{Type: watch.EventTypeDelete, Key: "app/old", Index: 1})
+	wm.Notify(watch.Event{Type: watch.EventTypeSet, Key: "other/key", Index: 2})
+	wm.Notify(watch.Event{Type: watch.EventTypeSet, Key: "app/config", Value: "v1", Index: 3})
+
+	payloads := recv.wait(t, 1)
+	payload := payloads[0]
+	if payload.Webhook != "deploy" || payload.Attempt != 1 || payload.Event.Key != "app/config" || payload.Event.Value != "v1" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+
+	recv.mu.Lock()
+	req, body := recv.requests[0], recv.bodies[0]
+	recv.mu.Unlock()
+	if got := req.Header.Get(HeaderSignature); got != Sign("s3cret", body) {
+		t.Errorf("expected signature %s, got %s", Sign("s3cret", body), got)
+	}
+	if req.Header.Get(HeaderEvent) != "set" || req.Header.Get(HeaderDelivery) != payload.Delivery {
+		t.Errorf("unexpected headers: %v", req.Header)
+	}
+
+	delivery := waitForStatus(t, manager, "deploy", DeliveryDelivered)
+	if delivery.Attempts != 1 || delivery.StatusCode != http.StatusNoContent {
+		t.Errorf("unexpected delivery: %+v", delivery)
+	}
+	if len(recv.wait(t, 1)) != 1 {
+		t.Error("expected the filtered events not to be delivered")
+	}
+}
+
+func TestManager_RetriesWithBackoff(t *testing.T) {
+	recv := newReceiver(t)
+	recv.failures.Store(2)
+	wm := watch.NewManager(nil, logger.GetDefault(), 10, 0)
+	manager := newTestManager(t, wm, nil, Config{MaxAttempts: 3, RetryBase: 10 * time.Millisecond})
+
+	if _, err := manager.Set(Webhook{Name: "ci", URL: recv.URL}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	wm.Notify(watch.Event{Type: watch.EventTypeSet, Key: "app/config", Index: 1})
+
+	payloads := recv.wait(t, 1)
+	if payloads[0].Attempt != 3 {
+		t.Errorf("expected delivery on attempt 3, got %d", payloads[0].Attempt)
+	}
+	delivery := waitForStatus(t, manager, "ci", DeliveryDelivered)
+	if delivery.Attempts != 3 || delivery.Error != "" {
+		t.Errorf("unexpected delivery: %+v", delivery)
+	}
+
+	// The last attempt fails the delivery for good
+	recv.failures.Store(3)
+	if _, err := manager.Set(Webhook{Name: "ci", URL: recv.URL, Pattern: "db/**"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	wm.Notify(watch.Event{Type: watch.EventTypeSet, Key: "db/config", Index: 2})
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		deliveries, _ := manager.Deliveries("ci")
+		if len(deliveries) == 2 && deliveries[0].Status == DeliveryFailed {
+			if deliveries[0].Attempts != 3 || deliveries[0].StatusCode != http.StatusInternalServerError {
+				t.Errorf("unexpected delivery: %+v", deliveries[0])
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a failed delivery, got %+v", deliveries)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestManager_Backoff(t *testing.T) {
+	manager := &Manager{cfg: Config{RetryBase: time.Second, RetryMax: 5 * time.Second}}
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 40: 5 * time.Second} {
+		if got := manager.backoff(attempt); got != want {
+			t.Errorf("attempt %d: expected %s, got %s", attempt, want, got)
+		}
+	}
+}
+
+func TestManager_PersistsPendingDeliveries(t *testing.T) {
+	recv := newReceiver(t)
+	recv.failures.Store(1)
+	engine := persistence.NewMemoryEngine()
+	wm := watch.NewManager(nil, logger.GetDefault(), 10, 0)
+
+	manager, err := NewManager(wm, engine, Config{RetryBase: time.Hour}, logger.GetDefault())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	if _, err := manager.Set(Webhook{Name: "ci", URL: recv.URL}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	wm.Notify(watch.Event{Type: watch.EventTypeSet, Key: "app/config", Index: 1})
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		deliveries, _ := manager.Deliveries("ci")
+		if len(deliveries) == 1 && deliveries[0].Attempts == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a failed attempt, got %+v", deliveries)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	manager.Close()
+
+	// The pending delivery is retried by the next manager, at once since
+	// its retry is due before the restart completes
+	records, _ := engine.ListACL(deliveryRecordKind)
+	for id, data := range records {
+		var delivery Delivery
+		_ = json.Unmarshal(data, &delivery)
+		delivery.NextAttempt = time.Now()
+		data, _ = json.Marshal(delivery)
+		_ = engine.SetACL(deliveryRecordKind, id, data)
+	}
+	manager = newTestManager(t, wm, engine, Config{})
+	if hooks := manager.List(); len(hooks) != 1 || hooks[0].Name != "ci" {
+		t.Fatalf("expected the persisted webhook, got %+v", hooks)
+	}
+	payloads := recv.wait(t, 1)
+	if payloads[0].Attempt != 2 || payloads[0].Event.Key != "app/config" {
+		t.Errorf("unexpected payload: %+v", payloads[0])
+	}
+	waitForStatus(t, manager, "ci", DeliveryDelivered)
+	if records, _ := engine.ListACL(deliveryRecordKind); len(records) != 0 {
+		t.Errorf("expected no persisted delivery, got %d", len(records))
+	}
+}
+
+func TestManager_ResubscribesAfterOverflow(t *testing.T) {
+	recv := newReceiver(t)
+	wm := watch.NewManager(nil, logger.GetDefault(), 1, 0)
+	manager := newTestManager(t, wm, nil, Config{})
+	if _, err := manager.Set(Webhook{Name: "ci", URL: recv.URL}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	// Block the forwarding of events so the watcher overflows
+	manager.mu.Lock()
+	for i := 1; i <= 5; i++ {
+		wm.Notify(watch.Event{Type: watch.EventTypeSet, Key: fmt.Sprintf("app/%d", i), Index: uint64(i)})
+	}
+	manager.mu.Unlock()
+
+	payloads := recv.wait(t, 5)
+	keys := make([]string, 0, len(payloads))
+	for _, payload := range payloads {
+		keys = append(keys, payload.Event.Key)
+	}
+	sort.Strings(keys)
+	if fmt.Sprint(keys) != "[app/1 app/2 app/3 app/4 app/5]" {
+		t.Errorf("expected every event delivered once, got %v", keys)
+	}
+}
+
+func TestManager_SetKeepsSecret(t *testing.T) {
+	engine := persistence.NewMemoryEngine()
+	wm := watch.NewManager(nil, logger.GetDefault(), 10, 0)
+	manager := newTestManager(t, wm, engine, Config{})
+
+	storedSecret := func() string {
+		t.Helper()
+		records, err := engine.ListACL(webhookRecordKind)
+		if err != nil {
+			t.Fatalf("ListACL failed: %v", err)
+		}
+		var hook Webhook
+		if err := json.Unmarshal(records["ci"], &hook); err != nil {
+			t.Fatalf("failed to decode the webhook record: %v", err)
+		}
+		if hook.ClearSecret {
+			t.Error("expected ClearSecret not to be stored")
+		}
+		return hook.Secret
+	}
+
+	if _, err := manager.Set(Webhook{Name: "ci", URL: "http://ci.example.com/hook", Secret: "s3cret"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	// Replaced without a secret, as the API never returns it
+	hook, err := manager.Set(Webhook{Name: "ci", URL: "http://ci.example.com/other"})
+	if err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if !hook.Signed || hook.URL != "http://ci.example.com/other" {
+		t.Errorf("expected the updated webhook to stay signed, got %+v", hook)
+	}
+	if got := storedSecret(); got != "s3cret" {
+		t.Errorf("expected secret s3cret to be kept, got %q", got)
+	}
+
+	hook, err = manager.Set(Webhook{Name: "ci", URL: "http://ci.example.com/other", ClearSecret: true})
+	if err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if hook.Signed || hook.ClearSecret {
+		t.Errorf("expected an unsigned webhook, got %+v", hook)
+	}
+	if got := storedSecret(); got != "" {
+		t.Errorf("expected the secret to be cleared, got %q", got)
+	}
+}
+
+func TestManager_Delete(t *testing.T) {
+	recv := newReceiver(t)
+	recv.failures.Store(100)
+	engine := persistence.NewMemoryEngine()
+	wm := watch.NewManager(nil, logger.GetDefault(), 10, 0)
+	manager := newTestManager(t, wm, engine, Config{RetryBase: time.Hour})
+
+	if _, err := manager.Set(Webhook{Name: "ci", URL: recv.URL}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	wm.Notify(watch.Event{Type: watch.EventTypeSet, Key: "app/config", Index: 1})
+	waitForStatus(t, manager, "ci", DeliveryPending)
+
+	if err := manager.Delete("ci"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := manager.Get("ci"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if err := manager.Delete("ci"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	for _, kind := range []string{webhookRecordKind, deliveryRecordKind} {
+		if records, _ := engine.ListACL(kind); len(records) != 0 {
+			t.Errorf("expected no %s record, got %d", kind, len(records))
+		}
+	}
+	if count := wm.GetActiveWatcherCount(); count != 0 {
+		t.Errorf("expected no watcher, got %d", count)
+	}
+}
+
+func TestWebhook_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    Webhook
+		wantErr bool
+	}{
+		{"kv", Webhook{Name: "ci", URL: "https://ci.example.com/hook"}, false},
+		{"service", Webhook{Name: "ci", URL: "http://ci", Kind: watch.KindService, Namespace: "team-a", Events: []watch.EventType{watch.EventTypeHealth}}, false},
+		{"invalid name", Webhook{Name: "CI", URL: "http://ci"}, true},
+		{"invalid URL", Webhook{Name: "ci", URL: "ftp://ci"}, true},
+		{"relative URL", Webhook{Name: "ci", URL: "/hook"}, true},
+		{"invalid kind", Webhook{Name: "ci", URL: "http://ci", Kind: "node"}, true},
+		{"kv namespace", Webhook{Name: "ci", URL: "http://ci", Namespace: "team-a"}, true},
+		{"invalid event", Webhook{Name: "ci", URL: "http://ci", Events: []watch.EventType{watch.EventTypeRegister}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.hook.Normalize()
+			if err := tt.hook.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/neogan74/konsul/internal/acl"
+	"github.com/neogan74/konsul/internal/watch"
+)
+
+var (
+	// ErrNotFound is returned for operations on an unknown webhook.
+	ErrNotFound = errors.New("webhook not found")
+	// ErrManagerClosed is returned when webhooks are changed after Close.
+	ErrManagerClosed = errors.New("webhook manager closed")
+)
+
+var nameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Webhook POSTs the watch events of the KV keys or services matching a
+// pattern to a URL. Events are filtered with the ACL policies of the token
+// that created the webhook, like the events of a watch opened with it.
+type Webhook struct {
+	Name        string            `json:"name"`
+	URL         string            `json:"url"`
+	Secret      string            `json:"secret,omitempty"`       // HMAC-SHA256 key of the signature header
+	Signed      bool              `json:"signed,omitempty"`       // Set instead of Secret in API responses
+	ClearSecret bool              `json:"clear_secret,omitempty"` // Drops the secret of a replaced webhook, kept when Secret is empty
+	Kind        watch.Kind        `json:"kind"`                   // kv or service
+	Namespace   string            `json:"namespace,omitempty"`    // Namespace of the services watched
+	Pattern     string            `json:"pattern"`                // Key or service name pattern, as for watches
+	Events      []watch.EventType `json:"events,omitempty"`       // Event types delivered; all when empty
+	Policies    []string          `json:"policies,omitempty"`
+	CreatedAt   time.Time         `json:"created_at,omitzero"`
+	UpdatedAt   time.Time         `json:"updated_at,omitzero"`
+}
+
+// eventTypes are the event types of each kind of webhook.
+var eventTypes = map[watch.Kind][]watch.EventType{
+	watch.KindKV:      {watch.EventTypeSet, watch.EventTypeDelete},
+	watch.KindService: {watch.EventTypeRegister, watch.EventTypeDeregister, watch.EventTypeHealth},
+}
+
+// Normalize fills in the defaults of a webhook: KV webhooks on every key.
+func (w *Webhook) Normalize() {
+	if w.Kind == "" {
+		w.Kind = watch.KindKV
+	}
+	if w.Pattern == "" {
+		w.Pattern = "**"
+		if w.Kind == watch.KindService {
+			w.Pattern = "*"
+		}
+	}
+}
+
+// Validate checks the fields of a normalized webhook.
+func (w Webhook) Validate() error {
+	if !nameRegex.MatchString(w.Name) {
+		return fmt.Errorf("invalid webhook name: %q (allowed: lowercase alphanumeric and -, max 63 chars)", w.Name)
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL: %q (must be an http or https URL)", w.URL)
+	}
+	types, ok := eventTypes[w.Kind]
+	if !ok {
+		return fmt.Errorf("invalid webhook kind: %q (must be kv or service)", w.Kind)
+	}
+	if w.Kind == watch.KindKV && w.Namespace != "" {
+		return fmt.Errorf("a namespace only applies to service webhooks")
+	}
+	for _, t := range w.Events {
+		if !slices.Contains(types, t) {
+			return fmt.Errorf("invalid event type for a %s webhook: %q", w.Kind, t)
+		}
+	}
+	return nil
+}
+
+// Redacted returns the webhook without its secret, as shown by the API.
+func (w Webhook) Redacted() Webhook {
+	w.Signed = w.Secret != ""
+	w.Secret = ""
+	return w
+}
+
+// Resource returns the ACL resource of the keys or services the webhook
+// watches. Creating a webhook requires read access to it.
+func (w Webhook) Resource() acl.Resource {
+	if w.Kind == watch.KindService {
+		return acl.NewServiceResource(w.Pattern).InNamespace(w.Namespace)
+	}
+	return acl.NewKVResource(w.Pattern)
+}
+
+// wants reports whether the webhook delivers events of type t. Resync
+// events are always delivered.
+func (w Webhook) wants(t watch.EventType) bool {
+	return len(w.Events) == 0 || t == watch.EventTypeResync || slices.Contains(w.Events, t)
+}
+
+// DeliveryStatus is the state of a delivery.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"   // Queued, or waiting for a retry
+	DeliveryDelivered DeliveryStatus = "delivered" // The receiver answered with a 2xx status
+	DeliveryFailed    DeliveryStatus = "failed"    // Every attempt failed
+)
+
+// Delivery is an event to POST to a webhook and the outcome of its attempts.
+type Delivery struct {
+	ID          string         `json:"id"`
+	Webhook     string         `json:"webhook"`
+	Event       watch.Event    `json:"event"`
+	Status      DeliveryStatus `json:"status"`
+	Attempts    int            `json:"attempts"`
+	StatusCode  int            `json:"status_code,omitempty"` // Status of the last response
+	Error       string         `json:"error,omitempty"`       // Why the last attempt failed
+	CreatedAt   time.Time      `json:"created_at"`
+	NextAttempt time.Time      `json:"next_attempt,omitzero"`
+	CompletedAt time.Time      `json:"completed_at,omitzero"`
+
+	inFlight bool // A worker is posting the delivery
+}
+
+// Payload is the JSON body POSTed to webhooks.
+type Payload struct {
+	Webhook  string      `json:"webhook"`
+	Delivery string      `json:"delivery"`
+	Attempt  int         `json:"attempt"`
+	Event    watch.Event `json:"event"`
+}
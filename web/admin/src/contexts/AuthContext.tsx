@@ -15,13 +15,25 @@ interface AuthContextType {
   refreshToken: string | null;
   isAuthenticated: boolean;
   isLoading: boolean;
-  login: (username: string, userId: string, roles: string[], policies?: string[]) => Promise<void>;
+  login: (username: string, password: string) => Promise<void>;
   logout: () => void;
   refreshAccessToken: () => Promise<void>;
 }
 
 const AuthContext = createContext<AuthContextType | undefined>(undefined);
 
+// userFromToken reads the user of an access token from its claims
+function userFromToken(token: string): User {
+  const payload = token.split('.')[1].replace(/-/g, '+').replace(/_/g, '/');
+  const claims = JSON.parse(atob(payload));
+  return {
+    user_id: claims.user_id,
+    username: claims.username,
+    roles: claims.roles ?? [],
+    policies: claims.policies ?? [],
+  };
+}
+
 export function AuthProvider({ children }: { children: ReactNode }) {
   const [user, setUser] = useState<User | null>(null);
   const [token, setToken] = useState<string | null>(null);
@@ -42,17 +54,15 @@ export function AuthProvider({ children }: { children: ReactNode }) {
     setIsLoading(false);
   }, []);
 
-  const login = async (username: string, userId: string, roles: string[], policies: string[] = []) => {
+  const login = async (username: string, password: string) => {
     try {
       const response = await authApi.post('/auth/login', {
-        user_id: userId,
         username,
-        roles,
-        policies,
+        password,
       });
 
       const { token: accessToken, refresh_token: newRefreshToken } = response.data;
-      const userData = { user_id: userId, username, roles, policies };
+      const userData = userFromToken(accessToken);
 
       setToken(accessToken);
       setRefreshToken(newRefreshToken);
@@ -85,15 +95,15 @@ export function AuthProvider({ children }: { children: ReactNode }) {
     try {
       const response = await authApi.post('/auth/refresh', {
         refresh_token: refreshToken,
-        username: user.username,
-        roles: user.roles,
-        policies: user.policies,
       });
 
       const { token: newAccessToken, refresh_token: newRefreshToken } = response.data;
+      const userData = userFromToken(newAccessToken);
 
       setToken(newAccessToken);
       setRefreshToken(newRefreshToken);
+      setUser(userData);
+      localStorage.setItem('konsul_user', JSON.stringify(userData));
 
       localStorage.setItem('konsul_token', newAccessToken);
       localStorage.setItem('konsul_refresh_token', newRefreshToken);
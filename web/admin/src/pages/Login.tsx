@@ -1,13 +1,11 @@
 import { useState } from 'react';
 import { useNavigate } from 'react-router-dom';
-import { Lock, User, Shield, Key } from 'lucide-react';
+import { Lock, User, Key } from 'lucide-react';
 import { useAuth } from '../contexts/AuthContext';
 
 export default function Login() {
   const [username, setUsername] = useState('');
-  const [userId, setUserId] = useState('');
-  const [roles, setRoles] = useState('admin');
-  const [policies, setPolicies] = useState('');
+  const [password, setPassword] = useState('');
   const [error, setError] = useState('');
   const [isLoading, setIsLoading] = useState(false);
 
@@ -20,10 +18,7 @@ export default function Login() {
     setIsLoading(true);
 
     try {
-      const roleList = roles.split(',').map(r => r.trim()).filter(Boolean);
-      const policyList = policies.split(',').map(p => p.trim()).filter(Boolean);
-
-      await login(username, userId || username, roleList, policyList);
+      await login(username, password);
       navigate('/');
     } catch (err) {
       setError('Login failed. Please check your credentials and try again.');
@@ -68,59 +63,26 @@ export default function Login() {
               </div>
             </div>
 
-            {/* User ID (optional) */}
+            {/* Password */}
             <div>
               <label className="block text-sm font-medium text-slate-300 mb-2">
-                User ID (optional)
+                Password
               </label>
               <div className="relative">
                 <div className="absolute left-3 top-1/2 -translate-y-1/2 pointer-events-none z-10">
                   <Key className="text-slate-400" size={20} />
                 </div>
                 <input
-                  type="text"
-                  value={userId}
-                  onChange={(e) => setUserId(e.target.value)}
-                  className="w-full pl-10 pr-4 py-3 bg-slate-900 border border-slate-700 rounded-lg text-white placeholder-slate-500 focus:outline-none focus:ring-2 focus:ring-blue-500"
-                  placeholder="Defaults to username"
-                />
-              </div>
-            </div>
-
-            {/* Roles */}
-            <div>
-              <label className="block text-sm font-medium text-slate-300 mb-2">
-                Roles (comma-separated)
-              </label>
-              <div className="relative">
-                <div className="absolute left-3 top-1/2 -translate-y-1/2 pointer-events-none z-10">
-                  <Shield className="text-slate-400" size={20} />
-                </div>
-                <input
-                  type="text"
+                  type="password"
                   required
-                  value={roles}
-                  onChange={(e) => setRoles(e.target.value)}
+                  value={password}
+                  onChange={(e) => setPassword(e.target.value)}
                   className="w-full pl-10 pr-4 py-3 bg-slate-900 border border-slate-700 rounded-lg text-white placeholder-slate-500 focus:outline-none focus:ring-2 focus:ring-blue-500"
-                  placeholder="admin, developer"
+                  placeholder="Enter password"
                 />
               </div>
             </div>
 
-            {/* Policies (optional) */}
-            <div>
-              <label className="block text-sm font-medium text-slate-300 mb-2">
-                Policies (comma-separated, optional)
-              </label>
-              <input
-                type="text"
-                value={policies}
-                onChange={(e) => setPolicies(e.target.value)}
-                className="w-full px-4 py-3 bg-slate-900 border border-slate-700 rounded-lg text-white placeholder-slate-500 focus:outline-none focus:ring-2 focus:ring-blue-500"
-                placeholder="developer, readonly"
-              />
-            </div>
-
             {/* Error Message */}
             {error && (
               <div className="px-4 py-3 bg-red-900/20 border border-red-700 rounded-lg text-red-400 text-sm">